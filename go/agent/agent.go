@@ -5,6 +5,7 @@ import (
 
 	"github.com/leftbin/stigmer-sdk/go/environment"
 	"github.com/leftbin/stigmer-sdk/go/mcpserver"
+	"github.com/leftbin/stigmer-sdk/go/resources"
 	"github.com/leftbin/stigmer-sdk/go/skill"
 	"github.com/leftbin/stigmer-sdk/go/subagent"
 )
@@ -48,6 +49,15 @@ type Agent struct {
 	// Org is the organization that owns this agent (optional).
 	Org string
 
+	// Version is the semantic version of this agent definition (optional, e.g., "1.3.0").
+	Version string
+
+	// Changelog describes what changed in this version (optional, max 1000 chars).
+	Changelog string
+
+	// Resources is the CPU and memory requested for the agent runtime (optional).
+	Resources resources.Requirements
+
 	// Skills are references to Skill resources providing agent knowledge.
 	Skills []skill.Skill
 
@@ -86,6 +96,32 @@ type Option func(*Agent) error
 //	    return err
 //	})
 func New(ctx Context, opts ...Option) (*Agent, error) {
+	a, err := build(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Register with context
+	ctx.RegisterAgent(a)
+
+	return a, nil
+}
+
+// Build creates and validates an Agent like New, without registering it with
+// a context. Register it later with stigmer.Context.Adopt.
+//
+// Example:
+//
+//	ag, err := agent.Build(
+//	    agent.WithName("code-reviewer"),
+//	    agent.WithInstructions("Review code and suggest improvements"),
+//	)
+func Build(opts ...Option) (*Agent, error) {
+	return build(nil, opts)
+}
+
+// build applies options and validates an agent without registering it.
+func build(ctx Context, opts []Option) (*Agent, error) {
 	a := &Agent{
 		ctx: ctx,
 	}
@@ -102,9 +138,6 @@ func New(ctx Context, opts ...Option) (*Agent, error) {
 		return nil, err
 	}
 
-	// Register with context
-	ctx.RegisterAgent(a)
-
 	return a, nil
 }
 
@@ -216,6 +249,63 @@ func WithOrg(org interface{}) Option {
 	}
 }
 
+// WithVersion sets the semantic version of the agent definition.
+//
+// Versions let agent rollouts and rollbacks be tracked the same way as
+// workflow versions. Must be valid semver (e.g., "1.3.0", "2.0.0-beta.1").
+// This is an optional field.
+//
+// Accepts either a string or a StringRef from context.
+//
+// Examples:
+//
+//	agent.WithVersion("1.3.0")                                 // Legacy string
+//	agent.WithVersion(ctx.SetString("version", "1.3.0"))       // Typed context
+func WithVersion(version interface{}) Option {
+	return func(a *Agent) error {
+		a.Version = toExpression(version)
+		return nil
+	}
+}
+
+// WithChangelog describes what changed in this version of the agent.
+//
+// The changelog is optional and must be max 1000 characters.
+//
+// Accepts either a string or a StringRef from context.
+//
+// Example:
+//
+//	agent.New(ctx,
+//	    agent.WithName("code-reviewer"),
+//	    agent.WithVersion("1.3.0"),
+//	    agent.WithChangelog("added security skill"),
+//	)
+func WithChangelog(changelog interface{}) Option {
+	return func(a *Agent) error {
+		a.Changelog = toExpression(changelog)
+		return nil
+	}
+}
+
+// WithResources declares the CPU and memory requested for the agent runtime.
+//
+// Quantities use the Kubernetes notation (e.g., "500m" CPU, "1Gi" memory);
+// pass an empty string to keep the platform default for either value.
+//
+// Example:
+//
+//	agent.New(ctx,
+//	    agent.WithName("code-reviewer"),
+//	    agent.WithResources("500m", "1Gi"),
+//	)
+func WithResources(cpu, memory string) Option {
+	return func(a *Agent) error {
+		a.Resources = resources.Requirements{CPU: cpu, Memory: memory}
+		return nil
+	}
+}
+
 // WithSkill adds a skill reference to the agent.
 //
 // Skills provide knowledge and capabilities to agents.
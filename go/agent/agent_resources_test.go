@@ -0,0 +1,33 @@
+package agent
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/resources"
+)
+
+func TestWithResources(t *testing.T) {
+	a, err := New(&versionTestContext{},
+		WithName("code-reviewer"),
+		WithInstructions("Review code and suggest improvements"),
+		WithResources("500m", "1Gi"),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if want := (resources.Requirements{CPU: "500m", Memory: "1Gi"}); a.Resources != want {
+		t.Errorf("Resources = %+v, want %+v", a.Resources, want)
+	}
+}
+
+func TestWithResources_Invalid(t *testing.T) {
+	_, err := New(&versionTestContext{},
+		WithName("code-reviewer"),
+		WithInstructions("Review code and suggest improvements"),
+		WithResources("", "1GB"),
+	)
+	if !errors.Is(err, ErrInvalidResources) {
+		t.Errorf("New() error = %v, want ErrInvalidResources", err)
+	}
+}
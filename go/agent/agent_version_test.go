@@ -0,0 +1,67 @@
+package agent
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// versionTestContext is a minimal Context that records registered agents.
+type versionTestContext struct {
+	agents []*Agent
+}
+
+func (c *versionTestContext) RegisterAgent(a *Agent) {
+	c.agents = append(c.agents, a)
+}
+
+func TestWithVersionAndChangelog(t *testing.T) {
+	ctx := &versionTestContext{}
+	a, err := New(ctx,
+		WithName("code-reviewer"),
+		WithInstructions("Review code and suggest improvements"),
+		WithVersion("1.3.0"),
+		WithChangelog("added security skill"),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if a.Version != "1.3.0" {
+		t.Errorf("Version = %q, want %q", a.Version, "1.3.0")
+	}
+	if a.Changelog != "added security skill" {
+		t.Errorf("Changelog = %q, want %q", a.Changelog, "added security skill")
+	}
+}
+
+func TestWithVersion_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		opt     Option
+		wantErr error
+	}{
+		{
+			name:    "not semver",
+			opt:     WithVersion("v1"),
+			wantErr: ErrInvalidVersion,
+		},
+		{
+			name:    "changelog too long",
+			opt:     WithChangelog(strings.Repeat("a", changelogMaxLength+1)),
+			wantErr: ErrInvalidChangelog,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(&versionTestContext{},
+				WithName("code-reviewer"),
+				WithInstructions("Review code and suggest improvements"),
+				tt.opt,
+			)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("New() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
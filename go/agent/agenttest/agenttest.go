@@ -0,0 +1,136 @@
+// Package agenttest provides helpers for unit-testing agent blueprints without
+// synthesizing them to disk.
+//
+// The assertions report failures through testing.TB, so they read like any
+// other test check:
+//
+//	func TestReviewer(t *testing.T) {
+//	    reviewer, err := agent.Build(
+//	        agent.WithName("code-reviewer"),
+//	        agent.WithInstructionsFromFile("instructions/reviewer.md"),
+//	        agent.WithSkill(skill.Platform("coding-best-practices")),
+//	        agent.WithMCPServer(github),
+//	    )
+//	    if err != nil {
+//	        t.Fatal(err)
+//	    }
+//	    agenttest.AssertHasSkill(t, reviewer, "coding-best-practices")
+//	    agenttest.AssertToolEnabled(t, reviewer, "github", "create_review")
+//	    agenttest.AssertEnvVar(t, reviewer, "GITHUB_TOKEN")
+//
+//	    prompt, err := agenttest.RenderPrompt(reviewer, map[string]any{"team": "payments"})
+//	    if err != nil {
+//	        t.Fatal(err)
+//	    }
+//	    if !strings.Contains(prompt, "payments") {
+//	        t.Errorf("prompt does not mention the team:\n%s", prompt)
+//	    }
+//	}
+//
+// GoldenInstructions guards against accidental prompt edits by comparing the
+// assembled instructions with a golden file checked in next to the test.
+package agenttest
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/agent"
+)
+
+// ErrMissingVariables is returned (wrapped) when the instructions reference
+// context variables that were not provided to RenderPrompt.
+var ErrMissingVariables = errors.New("missing prompt variables")
+
+// AssertHasSkill fails the test if the agent does not have the named skill.
+// Inline skills are matched by name, referenced skills by slug.
+func AssertHasSkill(t testing.TB, a *agent.Agent, name string) {
+	t.Helper()
+	var names []string
+	for _, s := range a.Skills {
+		if s.NameOrSlug() == name {
+			return
+		}
+		names = append(names, s.NameOrSlug())
+	}
+	t.Errorf("agent %q has no skill %q; skills: [%s]", a.Name, name, strings.Join(names, ", "))
+}
+
+// AssertToolEnabled fails the test if the agent's MCP server does not expose
+// the tool. A server without an enabled-tools list exposes all of its tools.
+func AssertToolEnabled(t testing.TB, a *agent.Agent, server, tool string) {
+	t.Helper()
+	var servers []string
+	for _, s := range a.MCPServers {
+		if s.Name() != server {
+			servers = append(servers, s.Name())
+			continue
+		}
+		tools := s.EnabledTools()
+		if len(tools) == 0 {
+			return
+		}
+		for _, enabled := range tools {
+			if enabled == tool {
+				return
+			}
+		}
+		t.Errorf("agent %q MCP server %q does not enable tool %q; enabled tools: [%s]",
+			a.Name, server, tool, strings.Join(tools, ", "))
+		return
+	}
+	t.Errorf("agent %q has no MCP server %q; servers: [%s]", a.Name, server, strings.Join(servers, ", "))
+}
+
+// AssertEnvVar fails the test if the agent does not declare the environment variable.
+func AssertEnvVar(t testing.TB, a *agent.Agent, name string) {
+	t.Helper()
+	var names []string
+	for _, v := range a.EnvironmentVariables {
+		if v.Name == name {
+			return
+		}
+		names = append(names, v.Name)
+	}
+	t.Errorf("agent %q does not declare environment variable %q; declared: [%s]",
+		a.Name, name, strings.Join(names, ", "))
+}
+
+// placeholderRegex matches ${name} and ${ $context.name } references in instructions.
+var placeholderRegex = regexp.MustCompile(`\$\{\s*(?:\$context\.)?([a-zA-Z_][a-zA-Z0-9_]*)\s*\}`)
+
+// RenderPrompt returns the agent's instructions with context variable
+// references (${name} and ${ $context.name }) replaced by values from ctxVars.
+//
+// Values may be plain Go values or context refs (as returned by
+// Context.Variables()). Every referenced variable must be provided; missing
+// ones are reported together as ErrMissingVariables.
+func RenderPrompt(a *agent.Agent, ctxVars map[string]any) (string, error) {
+	missing := map[string]bool{}
+	prompt := placeholderRegex.ReplaceAllStringFunc(a.Instructions, func(match string) string {
+		name := placeholderRegex.FindStringSubmatch(match)[1]
+		value, ok := ctxVars[name]
+		if !ok {
+			missing[name] = true
+			return match
+		}
+		if ref, ok := value.(interface{ ToValue() interface{} }); ok {
+			value = ref.ToValue()
+		}
+		return fmt.Sprint(value)
+	})
+
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return "", fmt.Errorf("%w: %s", ErrMissingVariables, strings.Join(names, ", "))
+	}
+	return prompt, nil
+}
@@ -0,0 +1,103 @@
+package agenttest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/agent"
+	"github.com/leftbin/stigmer-sdk/go/environment"
+	"github.com/leftbin/stigmer-sdk/go/mcpserver"
+	"github.com/leftbin/stigmer-sdk/go/skill"
+)
+
+// recorder captures assertion failures instead of failing the test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func newReviewer(t *testing.T) *agent.Agent {
+	t.Helper()
+	github, err := mcpserver.Stdio(
+		mcpserver.WithName("github"),
+		mcpserver.WithCommand("npx"),
+		mcpserver.WithEnabledTools("create_review", "list_prs"),
+	)
+	if err != nil {
+		t.Fatalf("Stdio() error = %v", err)
+	}
+	token, err := environment.New(environment.WithName("GITHUB_TOKEN"), environment.WithSecret(true))
+	if err != nil {
+		t.Fatalf("environment.New() error = %v", err)
+	}
+
+	a, err := agent.Build(
+		agent.WithName("code-reviewer"),
+		agent.WithInstructions("Review pull requests for the ${team} team in ${ $context.language }."),
+		agent.WithSkill(skill.Platform("coding-best-practices")),
+		agent.WithMCPServer(github),
+		agent.WithEnvironmentVariable(token),
+	)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	return a
+}
+
+// TestAssertions verifies the assertions pass for a matching blueprint and report what is present otherwise.
+func TestAssertions(t *testing.T) {
+	a := newReviewer(t)
+
+	r := &recorder{}
+	AssertHasSkill(r, a, "coding-best-practices")
+	AssertToolEnabled(r, a, "github", "create_review")
+	AssertEnvVar(r, a, "GITHUB_TOKEN")
+	if len(r.errors) != 0 {
+		t.Errorf("assertions failed for a matching agent: %v", r.errors)
+	}
+
+	AssertHasSkill(r, a, "security")
+	AssertToolEnabled(r, a, "github", "delete_repo")
+	AssertToolEnabled(r, a, "slack", "post_message")
+	AssertEnvVar(r, a, "SLACK_TOKEN")
+
+	want := []string{
+		`agent "code-reviewer" has no skill "security"; skills: [coding-best-practices]`,
+		`agent "code-reviewer" MCP server "github" does not enable tool "delete_repo"; enabled tools: [create_review, list_prs]`,
+		`agent "code-reviewer" has no MCP server "slack"; servers: [github]`,
+		`agent "code-reviewer" does not declare environment variable "SLACK_TOKEN"; declared: [GITHUB_TOKEN]`,
+	}
+	if strings.Join(r.errors, "\n") != strings.Join(want, "\n") {
+		t.Errorf("failures =\n%s\nwant:\n%s", strings.Join(r.errors, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+// TestRenderPrompt verifies both placeholder forms are resolved and missing variables are reported.
+func TestRenderPrompt(t *testing.T) {
+	a := newReviewer(t)
+
+	got, err := RenderPrompt(a, map[string]any{"team": "payments", "language": "Go"})
+	if err != nil {
+		t.Fatalf("RenderPrompt() error = %v", err)
+	}
+	if want := "Review pull requests for the payments team in Go."; got != want {
+		t.Errorf("RenderPrompt() = %q, want %q", got, want)
+	}
+
+	_, err = RenderPrompt(a, map[string]any{})
+	if !errors.Is(err, ErrMissingVariables) || !strings.HasSuffix(err.Error(), ": language, team") {
+		t.Errorf("RenderPrompt() error = %v, want ErrMissingVariables for language, team", err)
+	}
+}
@@ -0,0 +1,100 @@
+package agenttest
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/agent"
+)
+
+// updateGolden rewrites golden files instead of comparing against them:
+//
+//	go test ./... -agenttest.update
+var updateGolden = flag.Bool("agenttest.update", false, "rewrite agenttest golden files with the current output")
+
+// updateGoldenEnv rewrites golden files when set to true, for runners that
+// cannot pass test flags.
+const updateGoldenEnv = "STIGMER_UPDATE_GOLDEN"
+
+// GoldenInstructions compares the agent's assembled instructions (after file
+// loading and templating) with a golden file, so accidental prompt edits fail
+// a test and intended ones show up in review as a golden file diff.
+//
+// Run the tests with -agenttest.update (or STIGMER_UPDATE_GOLDEN=true) to
+// create or rewrite the golden file from the current instructions.
+//
+// Example:
+//
+//	func TestReviewerPrompt(t *testing.T) {
+//	    reviewer, err := agent.Build(
+//	        agent.WithName("code-reviewer"),
+//	        agent.WithInstructionsFromFile("instructions/reviewer.md"),
+//	    )
+//	    if err != nil {
+//	        t.Fatal(err)
+//	    }
+//	    agenttest.GoldenInstructions(t, reviewer, "testdata/reviewer.md")
+//	}
+func GoldenInstructions(t testing.TB, a *agent.Agent, path string) {
+	t.Helper()
+	got := a.Instructions
+
+	if shouldUpdateGolden() {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("creating golden file directory: %v", err)
+			return
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatalf("writing golden file: %v", err)
+		}
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		t.Fatalf("golden file %s does not exist; run the tests with -agenttest.update to create it", path)
+		return
+	}
+	if err != nil {
+		t.Fatalf("reading golden file: %v", err)
+		return
+	}
+
+	if want := string(data); got != want {
+		line, wantLine, gotLine := firstDifference(want, got)
+		t.Errorf("agent %q instructions differ from %s at line %d:\n  want: %q\n  got:  %q\nrun the tests with -agenttest.update if the change is intended",
+			a.Name, path, line, wantLine, gotLine)
+	}
+}
+
+// shouldUpdateGolden reports whether golden files should be rewritten.
+func shouldUpdateGolden() bool {
+	if *updateGolden {
+		return true
+	}
+	update, _ := strconv.ParseBool(os.Getenv(updateGoldenEnv))
+	return update
+}
+
+// firstDifference returns the 1-based number of the first line that differs
+// between want and got, and that line from each ("" past the end).
+func firstDifference(want, got string) (int, string, string) {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+	for i := 0; ; i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g || i >= len(wantLines) || i >= len(gotLines) {
+			return i + 1, w, g
+		}
+	}
+}
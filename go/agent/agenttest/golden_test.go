@@ -0,0 +1,55 @@
+package agenttest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/agent"
+)
+
+func newPromptAgent(t *testing.T, instructions string) *agent.Agent {
+	t.Helper()
+	a, err := agent.Build(agent.WithName("reviewer"), agent.WithInstructions(instructions))
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	return a
+}
+
+// TestGoldenInstructions verifies matching instructions pass and edits are reported with the first changed line.
+func TestGoldenInstructions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reviewer.md")
+	if err := os.WriteFile(path, []byte("Review code.\nBe concise."), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &recorder{}
+	GoldenInstructions(r, newPromptAgent(t, "Review code.\nBe concise."), path)
+	if len(r.errors) != 0 {
+		t.Errorf("matching instructions reported: %v", r.errors)
+	}
+
+	GoldenInstructions(r, newPromptAgent(t, "Review code.\nBe thorough."), path)
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "at line 2:\n  want: \"Be concise.\"\n  got:  \"Be thorough.\"") {
+		t.Errorf("failures = %v, want a line 2 difference", r.errors)
+	}
+}
+
+// TestGoldenInstructions_Update verifies update mode writes the golden file.
+func TestGoldenInstructions_Update(t *testing.T) {
+	t.Setenv(updateGoldenEnv, "true")
+	path := filepath.Join(t.TempDir(), "testdata", "reviewer.md")
+
+	r := &recorder{}
+	GoldenInstructions(r, newPromptAgent(t, "Review code carefully."), path)
+	if len(r.errors) != 0 {
+		t.Fatalf("update reported failures: %v", r.errors)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "Review code carefully." {
+		t.Errorf("golden file = %q, %v", data, err)
+	}
+}
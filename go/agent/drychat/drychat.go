@@ -0,0 +1,456 @@
+// Package drychat runs an agent blueprint on the local machine so its
+// instructions can be tried out before the agent is deployed.
+//
+// A Harness starts the agent's MCP servers, sends prompts to a model with the
+// agent's instructions as the system prompt, and runs the tool calls the model
+// makes against the local servers:
+//
+//	h, err := drychat.New(reviewer, drychat.WithAPIKey(os.Getenv("ANTHROPIC_API_KEY")))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer h.Close()
+//
+//	reply, err := h.Send("Review https://github.com/acme/api/pull/42")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, call := range reply.ToolCalls {
+//	    fmt.Printf("-> %s.%s %v\n", call.Server, call.Tool, call.Input)
+//	}
+//	fmt.Println(reply.Text)
+//
+// The conversation is kept between Send calls. Inline skills are appended to
+// the system prompt; platform and organization skills and sub-agents live on
+// the platform and are not available locally. MCP env placeholders are
+// expanded from the local environment, so export the agent's environment
+// variables first.
+//
+// The harness talks to the Anthropic Messages API. It is a development aid:
+// prompts and tool results are sent to the model provider, and MCP tools run
+// with your local credentials.
+package drychat
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/leftbin/stigmer-sdk/go/agent"
+	"github.com/leftbin/stigmer-sdk/go/mcpserver"
+)
+
+// Defaults used when no option overrides them.
+const (
+	DefaultModel     = "claude-sonnet-4-5"
+	DefaultBaseURL   = "https://api.anthropic.com"
+	DefaultMaxTurns  = 10
+	DefaultMaxTokens = 4096
+)
+
+// apiKeyEnv is read when WithAPIKey is not given.
+const apiKeyEnv = "ANTHROPIC_API_KEY"
+
+// Option is a functional option for New.
+type Option func(*config)
+
+type config struct {
+	apiKey    string
+	model     string
+	baseURL   string
+	maxTurns  int
+	maxTokens int
+	mcpOpts   []mcpserver.ProbeOption
+}
+
+// WithAPIKey sets the model API key (default: $ANTHROPIC_API_KEY).
+func WithAPIKey(key string) Option {
+	return func(c *config) {
+		c.apiKey = key
+	}
+}
+
+// WithModel sets the model to chat with (default: DefaultModel).
+func WithModel(model string) Option {
+	return func(c *config) {
+		c.model = model
+	}
+}
+
+// WithBaseURL sets the API endpoint, for proxies and gateways.
+func WithBaseURL(url string) Option {
+	return func(c *config) {
+		c.baseURL = strings.TrimSuffix(url, "/")
+	}
+}
+
+// WithMaxTurns bounds the model calls made for one prompt (default: 10).
+// Each round of tool calls takes one turn.
+func WithMaxTurns(turns int) Option {
+	return func(c *config) {
+		c.maxTurns = turns
+	}
+}
+
+// WithMaxTokens sets the maximum length of each model response (default: 4096).
+func WithMaxTokens(tokens int) Option {
+	return func(c *config) {
+		c.maxTokens = tokens
+	}
+}
+
+// WithMCPOptions passes options (e.g., mcpserver.WithProbeTimeout) to
+// mcpserver.Connect for each of the agent's MCP servers.
+func WithMCPOptions(opts ...mcpserver.ProbeOption) Option {
+	return func(c *config) {
+		c.mcpOpts = append(c.mcpOpts, opts...)
+	}
+}
+
+// Harness is a local chat session with an agent.
+type Harness struct {
+	agent    *agent.Agent
+	cfg      *config
+	system   string
+	sessions []*mcpserver.Session
+	tools    []modelTool
+	messages []message
+}
+
+// Reply is the agent's answer to one prompt.
+type Reply struct {
+	// Text is the final answer
+	Text string
+
+	// ToolCalls lists the tools called while answering, in order
+	ToolCalls []ToolCall
+}
+
+// ToolCall records one tool call made by the model.
+type ToolCall struct {
+	Server  string
+	Tool    string
+	Input   map[string]any
+	Output  string
+	IsError bool
+}
+
+// modelTool is an MCP tool as offered to the model.
+type modelTool struct {
+	name    string // unique name sent to the model
+	server  string
+	tool    string
+	desc    string
+	schema  map[string]any
+	session *mcpserver.Session
+}
+
+// New starts a local chat session with an agent.
+//
+// Required environment variables of the agent without a default must be set
+// locally. Each MCP server is started and its enabled tools (all tools when
+// WithEnabledTools was not used) are offered to the model.
+func New(a *agent.Agent, opts ...Option) (*Harness, error) {
+	cfg := &config{
+		apiKey:    os.Getenv(apiKeyEnv),
+		model:     DefaultModel,
+		baseURL:   DefaultBaseURL,
+		maxTurns:  DefaultMaxTurns,
+		maxTokens: DefaultMaxTokens,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.apiKey == "" {
+		return nil, fmt.Errorf("model API key is required (use WithAPIKey or set %s)", apiKeyEnv)
+	}
+
+	var unset []string
+	for _, v := range a.EnvironmentVariables {
+		if v.Required && v.DefaultValue == "" && os.Getenv(v.Name) == "" {
+			unset = append(unset, v.Name)
+		}
+	}
+	if len(unset) > 0 {
+		return nil, fmt.Errorf("agent %s: set environment variables to run locally: %s", a.Name, strings.Join(unset, ", "))
+	}
+
+	h := &Harness{agent: a, cfg: cfg, system: systemPrompt(a)}
+	for _, server := range a.MCPServers {
+		if err := h.connect(server); err != nil {
+			h.Close()
+			return nil, fmt.Errorf("agent %s: %w", a.Name, err)
+		}
+	}
+	return h, nil
+}
+
+// connect starts an MCP server and registers its enabled tools.
+func (h *Harness) connect(server mcpserver.MCPServer) error {
+	session, err := mcpserver.Connect(server, h.cfg.mcpOpts...)
+	if err != nil {
+		return err
+	}
+	h.sessions = append(h.sessions, session)
+
+	tools, err := session.ListTools()
+	if err != nil {
+		return fmt.Errorf("list tools of %s: %w", server.Name(), err)
+	}
+	enabled := map[string]bool{}
+	for _, name := range server.EnabledTools() {
+		enabled[name] = true
+	}
+	for _, t := range tools {
+		if len(enabled) > 0 && !enabled[t.Name] {
+			continue
+		}
+		schema := t.InputSchema
+		if schema == nil {
+			schema = map[string]any{"type": "object"}
+		}
+		h.tools = append(h.tools, modelTool{
+			name:    h.toolName(server.Name(), t.Name),
+			server:  server.Name(),
+			tool:    t.Name,
+			desc:    t.Description,
+			schema:  schema,
+			session: session,
+		})
+	}
+	return nil
+}
+
+// toolNameChars matches characters not allowed in model tool names.
+var toolNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// toolName returns a unique model tool name of the form server__tool.
+func (h *Harness) toolName(server, tool string) string {
+	name := toolNameChars.ReplaceAllString(server+"__"+tool, "_")
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	base := name
+	for i := 2; h.findTool(name) != nil; i++ {
+		suffix := fmt.Sprintf("_%d", i)
+		name = base[:min(len(base), 64-len(suffix))] + suffix
+	}
+	return name
+}
+
+func (h *Harness) findTool(name string) *modelTool {
+	for i := range h.tools {
+		if h.tools[i].name == name {
+			return &h.tools[i]
+		}
+	}
+	return nil
+}
+
+// systemPrompt joins the agent's instructions and inline skills.
+func systemPrompt(a *agent.Agent) string {
+	var b strings.Builder
+	b.WriteString(a.Instructions)
+	for _, s := range a.Skills {
+		if !s.IsInline {
+			continue
+		}
+		fmt.Fprintf(&b, "\n\n# Skill: %s\n\n%s", s.Name, s.Markdown())
+	}
+	return b.String()
+}
+
+// Close stops the agent's MCP servers.
+func (h *Harness) Close() error {
+	var errs []string
+	for _, s := range h.sessions {
+		if err := s.Close(); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	h.sessions = nil
+	if len(errs) > 0 {
+		return fmt.Errorf("closing MCP servers: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Send sends a prompt and runs the model's tool calls until it answers.
+// If it fails, the conversation is left as it was before the call.
+func (h *Harness) Send(prompt string) (*Reply, error) {
+	n := len(h.messages)
+	reply, err := h.send(prompt)
+	if err != nil {
+		// Drop the prompt and any tool turns so the next Send starts from a valid conversation
+		h.messages = h.messages[:n]
+		return nil, err
+	}
+	return reply, nil
+}
+
+// send implements Send.
+func (h *Harness) send(prompt string) (*Reply, error) {
+	h.messages = append(h.messages, message{
+		Role:    "user",
+		Content: []contentBlock{{Type: "text", Text: prompt}},
+	})
+
+	reply := &Reply{}
+	for turn := 0; turn < h.cfg.maxTurns; turn++ {
+		resp, err := h.complete()
+		if err != nil {
+			return nil, err
+		}
+		h.messages = append(h.messages, message{Role: "assistant", Content: resp.Content})
+
+		var results []contentBlock
+		var texts []string
+		for _, block := range resp.Content {
+			switch block.Type {
+			case "text":
+				texts = append(texts, block.Text)
+			case "tool_use":
+				call, result := h.callTool(block)
+				reply.ToolCalls = append(reply.ToolCalls, call)
+				results = append(results, result)
+			}
+		}
+		if resp.StopReason != "tool_use" || len(results) == 0 {
+			reply.Text = strings.Join(texts, "\n")
+			return reply, nil
+		}
+		h.messages = append(h.messages, message{Role: "user", Content: results})
+	}
+	return nil, fmt.Errorf("no answer after %d model turns (use WithMaxTurns to allow more)", h.cfg.maxTurns)
+}
+
+// callTool runs a tool_use block and returns its tool_result block.
+func (h *Harness) callTool(block contentBlock) (ToolCall, contentBlock) {
+	result := contentBlock{Type: "tool_result", ToolUseID: block.ID}
+	tool := h.findTool(block.Name)
+	if tool == nil {
+		result.Content = fmt.Sprintf("unknown tool %q", block.Name)
+		result.IsError = true
+		return ToolCall{Tool: block.Name, Input: block.Input, Output: result.Content, IsError: true}, result
+	}
+
+	call := ToolCall{Server: tool.server, Tool: tool.tool, Input: block.Input}
+	out, err := tool.session.CallTool(tool.tool, block.Input)
+	if err != nil {
+		call.Output, call.IsError = err.Error(), true
+	} else {
+		call.Output, call.IsError = out.Text, out.IsError
+	}
+	result.Content, result.IsError = call.Output, call.IsError
+	return call, result
+}
+
+// message is a Messages API conversation turn.
+type message struct {
+	Role    string         `json:"role"`
+	Content []contentBlock `json:"content"`
+}
+
+// contentBlock is a text, tool_use or tool_result block.
+type contentBlock struct {
+	Type      string         `json:"type"`
+	Text      string         `json:"text,omitempty"`
+	ID        string         `json:"id,omitempty"`
+	Name      string         `json:"name,omitempty"`
+	Input     map[string]any `json:"input,omitempty"`
+	ToolUseID string         `json:"tool_use_id,omitempty"`
+	Content   string         `json:"content,omitempty"`
+	IsError   bool           `json:"is_error,omitempty"`
+}
+
+// MarshalJSON always includes the input of tool_use blocks, which the API
+// requires even when the tool takes no arguments.
+func (b contentBlock) MarshalJSON() ([]byte, error) {
+	type plain contentBlock
+	if b.Type != "tool_use" {
+		return json.Marshal(plain(b))
+	}
+	input := b.Input
+	if input == nil {
+		input = map[string]any{}
+	}
+	return json.Marshal(struct {
+		plain
+		Input map[string]any `json:"input"`
+	}{plain(b), input})
+}
+
+type toolParam struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	InputSchema map[string]any `json:"input_schema"`
+}
+
+type completionRequest struct {
+	Model     string      `json:"model"`
+	MaxTokens int         `json:"max_tokens"`
+	System    string      `json:"system,omitempty"`
+	Messages  []message   `json:"messages"`
+	Tools     []toolParam `json:"tools,omitempty"`
+}
+
+type completionResponse struct {
+	Content    []contentBlock `json:"content"`
+	StopReason string         `json:"stop_reason"`
+	Error      *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// complete sends the conversation to the model.
+func (h *Harness) complete() (*completionResponse, error) {
+	req := completionRequest{
+		Model:     h.cfg.model,
+		MaxTokens: h.cfg.maxTokens,
+		System:    h.system,
+		Messages:  h.messages,
+	}
+	for _, t := range h.tools {
+		req.Tools = append(req.Tools, toolParam{Name: t.name, Description: t.desc, InputSchema: t.schema})
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, h.cfg.baseURL+"/v1/messages", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-Api-Key", h.cfg.apiKey)
+	httpReq.Header.Set("Anthropic-Version", "2023-06-01")
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("model request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("model request failed: %w", err)
+	}
+
+	var out completionResponse
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("model returned %s: %s", resp.Status, bytes.TrimSpace(data))
+	}
+	if out.Error != nil {
+		return nil, fmt.Errorf("model returned %s: %s: %s", resp.Status, out.Error.Type, out.Error.Message)
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("model returned %s", resp.Status)
+	}
+	return &out, nil
+}
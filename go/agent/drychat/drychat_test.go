@@ -0,0 +1,230 @@
+package drychat
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/agent"
+	"github.com/leftbin/stigmer-sdk/go/environment"
+	"github.com/leftbin/stigmer-sdk/go/mcpserver"
+	"github.com/leftbin/stigmer-sdk/go/skill"
+)
+
+// fakeMCPServer serves an HTTP MCP server with create_issue and list_repos.
+func fakeMCPServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			return
+		}
+		var req struct {
+			ID     *int64         `json:"id"`
+			Method string         `json:"method"`
+			Params map[string]any `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.ID == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		var result any
+		switch req.Method {
+		case "initialize":
+			result = map[string]any{"serverInfo": map[string]any{"name": "fake", "version": "0.1.0"}}
+		case "tools/list":
+			result = map[string]any{"tools": []any{
+				map[string]any{"name": "create_issue", "description": "Create an issue"},
+				map[string]any{"name": "list_repos"},
+			}}
+		case "tools/call":
+			args, _ := json.Marshal(req.Params["arguments"])
+			result = map[string]any{"content": []any{
+				map[string]any{"type": "text", "text": "created " + string(args)},
+			}}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": *req.ID, "result": result})
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func testAgent(t *testing.T, mcpURL string) *agent.Agent {
+	t.Helper()
+	github, err := mcpserver.HTTP(
+		mcpserver.WithName("github"),
+		mcpserver.WithURL(mcpURL),
+		mcpserver.WithEnabledTools("create_issue"),
+	)
+	if err != nil {
+		t.Fatalf("HTTP() error = %v", err)
+	}
+	guide, err := skill.New(
+		skill.WithName("triage"),
+		skill.WithMarkdown("Label every issue."),
+	)
+	if err != nil {
+		t.Fatalf("skill.New() error = %v", err)
+	}
+	a, err := agent.Build(
+		agent.WithName("triager"),
+		agent.WithInstructions("File issues for reported bugs."),
+		agent.WithSkill(*guide),
+		agent.WithSkill(skill.Platform("coding-best-practices")),
+		agent.WithMCPServer(github),
+	)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	return a
+}
+
+func TestSend_ToolUse(t *testing.T) {
+	var requests []completionRequest
+	model := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" || r.Header.Get("X-Api-Key") != "test-key" {
+			http.Error(w, `{"type":"error","error":{"type":"authentication_error","message":"bad key"}}`, http.StatusUnauthorized)
+			return
+		}
+		var req completionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		requests = append(requests, req)
+
+		resp := completionResponse{StopReason: "end_turn", Content: []contentBlock{{Type: "text", Text: "Filed the issue."}}}
+		if len(requests) == 1 {
+			resp = completionResponse{StopReason: "tool_use", Content: []contentBlock{
+				{Type: "text", Text: "Filing it."},
+				{Type: "tool_use", ID: "call-1", Name: "github__create_issue", Input: map[string]any{"title": "crash"}},
+			}}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer model.Close()
+
+	h, err := New(testAgent(t, fakeMCPServer(t).URL), WithAPIKey("test-key"), WithBaseURL(model.URL))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer h.Close()
+
+	reply, err := h.Send("The app crashes on start.")
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if reply.Text != "Filed the issue." {
+		t.Errorf("Text = %q", reply.Text)
+	}
+	if len(reply.ToolCalls) != 1 {
+		t.Fatalf("ToolCalls = %+v, want 1 call", reply.ToolCalls)
+	}
+	call := reply.ToolCalls[0]
+	if call.Server != "github" || call.Tool != "create_issue" || call.Output != `created {"title":"crash"}` || call.IsError {
+		t.Errorf("ToolCall = %+v", call)
+	}
+
+	first := requests[0]
+	if !strings.Contains(first.System, "File issues for reported bugs.") || !strings.Contains(first.System, "# Skill: triage") {
+		t.Errorf("System = %q, want instructions and inline skill", first.System)
+	}
+	if len(first.Tools) != 1 || first.Tools[0].Name != "github__create_issue" {
+		t.Errorf("Tools = %+v, want only the enabled tool", first.Tools)
+	}
+	last := requests[1].Messages[len(requests[1].Messages)-1]
+	if last.Role != "user" || last.Content[0].Type != "tool_result" || last.Content[0].ToolUseID != "call-1" {
+		t.Errorf("last message = %+v, want tool_result for call-1", last)
+	}
+}
+
+func TestSend_ModelError(t *testing.T) {
+	model := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"type":"error","error":{"type":"authentication_error","message":"invalid x-api-key"}}`))
+	}))
+	defer model.Close()
+
+	h, err := New(testAgent(t, fakeMCPServer(t).URL), WithAPIKey("wrong"), WithBaseURL(model.URL))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer h.Close()
+
+	_, err = h.Send("hello")
+	if err == nil || !strings.Contains(err.Error(), "invalid x-api-key") {
+		t.Errorf("Send() error = %v, want authentication error", err)
+	}
+}
+
+func TestContentBlock_ToolUseInput(t *testing.T) {
+	data, err := json.Marshal(contentBlock{Type: "tool_use", ID: "x", Name: "n", Input: map[string]any{}})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if want := `{"type":"tool_use","id":"x","name":"n","input":{}}`; string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+
+	data, err = json.Marshal(contentBlock{Type: "text", Text: "hi"})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if want := `{"type":"text","text":"hi"}`; string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+}
+
+func TestSend_ErrorKeepsConversation(t *testing.T) {
+	fail := false
+	model := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"type":"error","error":{"type":"overloaded_error","message":"overloaded"}}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(completionResponse{StopReason: "end_turn", Content: []contentBlock{{Type: "text", Text: "Hi."}}})
+	}))
+	defer model.Close()
+
+	h, err := New(testAgent(t, fakeMCPServer(t).URL), WithAPIKey("test-key"), WithBaseURL(model.URL))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer h.Close()
+
+	if _, err := h.Send("hello"); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	fail = true
+	if _, err := h.Send("are you there?"); err == nil {
+		t.Fatal("Send() error = nil, want overloaded error")
+	}
+	if len(h.messages) != 2 || h.messages[1].Role != "assistant" {
+		t.Errorf("messages after failed Send() = %+v, want the first exchange only", h.messages)
+	}
+}
+
+func TestNew_Validation(t *testing.T) {
+	t.Setenv(apiKeyEnv, "")
+	a := testAgent(t, fakeMCPServer(t).URL)
+	if _, err := New(a); err == nil || !strings.Contains(err.Error(), apiKeyEnv) {
+		t.Errorf("New() error = %v, want missing API key", err)
+	}
+
+	t.Setenv("DRYCHAT_TEST_TOKEN", "")
+	token, err := environment.New(environment.WithName("DRYCHAT_TEST_TOKEN"))
+	if err != nil {
+		t.Fatalf("environment.New() error = %v", err)
+	}
+	a.EnvironmentVariables = append(a.EnvironmentVariables, token)
+	if _, err := New(a, WithAPIKey("k")); err == nil || !strings.Contains(err.Error(), "DRYCHAT_TEST_TOKEN") {
+		t.Errorf("New() error = %v, want unset environment variable", err)
+	}
+}
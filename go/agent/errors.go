@@ -19,11 +19,27 @@ var (
 	// ErrInvalidIconURL is returned when the icon URL is invalid.
 	ErrInvalidIconURL = errors.New("invalid icon URL")
 
+	// ErrInvalidVersion is returned when the agent version is not valid semver.
+	ErrInvalidVersion = errors.New("invalid agent version")
+
+	// ErrInvalidChangelog is returned when the agent changelog is invalid.
+	ErrInvalidChangelog = errors.New("invalid agent changelog")
+
+	// ErrInvalidResources is returned when the requested CPU or memory is malformed.
+	ErrInvalidResources = errors.New("invalid agent resources")
+
 	// ErrMissingRequiredField is returned when a required field is missing.
 	ErrMissingRequiredField = errors.New("missing required field")
 
 	// ErrConversion is returned when proto conversion fails.
 	ErrConversion = errors.New("proto conversion failed")
+
+	// ErrSubAgentCycle is returned when agents delegate to each other in a cycle.
+	ErrSubAgentCycle = errors.New("sub-agent cycle")
+
+	// ErrSubAgentDepth is returned when sub-agents are nested deeper than
+	// MaxSubAgentDepth.
+	ErrSubAgentDepth = errors.New("sub-agents nested too deep")
 )
 
 // ValidationError represents a validation error with context.
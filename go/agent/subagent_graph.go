@@ -0,0 +1,105 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/leftbin/stigmer-sdk/go/subagent"
+)
+
+// MaxSubAgentDepth is the deepest delegation the platform runs: an agent's
+// sub-agents are at depth 1, their sub-agents at depth 2, and so on.
+const MaxSubAgentDepth = 3
+
+// ValidateSubAgents checks the sub-agents reachable from this agent, following
+// referenced sub-agents into the other agents of the program.
+//
+// A referenced sub-agent refers to one of agents when its instance reference
+// is that agent's name and it is not pinned to another organization. Inline
+// sub-agents and references to agents defined elsewhere end the delegation.
+//
+// Delegation that leads back to this agent is reported as ErrSubAgentCycle,
+// and delegation deeper than MaxSubAgentDepth as ErrSubAgentDepth. Both
+// errors name the path that causes them, e.g. "triage -> reviewer -> triage".
+//
+// This is used during synthesis, with every agent defined in the program.
+func (a *Agent) ValidateSubAgents(agents []*Agent) error {
+	byName := make(map[string]*Agent, len(agents))
+	for _, ag := range agents {
+		if _, ok := byName[ag.Name]; !ok {
+			byName[ag.Name] = ag
+		}
+	}
+
+	// resolve returns the agent of the program a sub-agent refers to, or nil
+	resolve := func(sub subagent.SubAgent) *Agent {
+		if !sub.IsReference() {
+			return nil
+		}
+		target := byName[sub.AgentInstanceID()]
+		if target == nil || (sub.Organization() != "" && sub.Organization() != target.Org) {
+			return nil
+		}
+		return target
+	}
+
+	var errs []error
+	onPath := map[*Agent]bool{a: true}
+	var visit func(current *Agent, path []string)
+	visit = func(current *Agent, path []string) {
+		for _, sub := range current.SubAgents {
+			target := resolve(sub)
+
+			var next string
+			switch {
+			case target == a:
+				errs = append(errs, subAgentPathError(extendPath(path, a.Name), "acyclic",
+					"sub-agent cycle", ErrSubAgentCycle))
+				continue
+			case target != nil && onPath[target]:
+				// A cycle not through this agent, reported by the agents in it
+				continue
+			case target != nil:
+				next = target.Name
+			case sub.IsReference():
+				next = sub.QualifiedRef()
+			default:
+				next = sub.Name()
+			}
+
+			// path holds the agents above sub, so sub is at depth len(path)
+			if len(path) > MaxSubAgentDepth {
+				errs = append(errs, subAgentPathError(extendPath(path, next), "max_depth",
+					fmt.Sprintf("sub-agents nested %d levels deep, the platform allows %d", len(path), MaxSubAgentDepth),
+					ErrSubAgentDepth))
+				continue
+			}
+			if target != nil {
+				onPath[target] = true
+				visit(target, extendPath(path, next))
+				onPath[target] = false
+			}
+		}
+	}
+	visit(a, []string{a.Name})
+
+	return errors.Join(errs...)
+}
+
+// extendPath returns a copy of path with name appended, so sibling branches
+// of the walk don't share a backing array.
+func extendPath(path []string, name string) []string {
+	return append(path[:len(path):len(path)], name)
+}
+
+// subAgentPathError reports a problem with the delegation along path.
+func subAgentPathError(path []string, rule, message string, cause error) error {
+	return NewValidationErrorWithCause(
+		"sub_agents",
+		strings.Join(path, ","),
+		rule,
+		fmt.Sprintf("%s: %s", message, strings.Join(path, " -> ")),
+		cause,
+	)
+}
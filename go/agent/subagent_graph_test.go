@@ -0,0 +1,103 @@
+package agent
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/subagent"
+)
+
+func TestAgent_ValidateSubAgents(t *testing.T) {
+	helper, err := subagent.Inline(
+		subagent.WithName("helper"),
+		subagent.WithInstructions("Help with small tasks"),
+	)
+	if err != nil {
+		t.Fatalf("Inline() error = %v", err)
+	}
+
+	// delegating returns an agent whose referenced sub-agents point to the named agents
+	delegating := func(name string, to ...string) *Agent {
+		a := &Agent{Name: name}
+		for _, target := range to {
+			a.SubAgents = append(a.SubAgents, subagent.Reference(target, target))
+		}
+		return a
+	}
+
+	tests := []struct {
+		name    string
+		agents  []*Agent
+		wantErr error
+		wantMsg string
+	}{
+		{
+			name: "references to external agents",
+			agents: []*Agent{
+				delegating("triage", "sec-prod"),
+			},
+		},
+		{
+			name: "chain within the limit",
+			agents: []*Agent{
+				delegating("a", "b"),
+				delegating("b", "c"),
+				{Name: "c", SubAgents: []subagent.SubAgent{helper}},
+			},
+		},
+		{
+			name: "self reference",
+			agents: []*Agent{
+				delegating("a", "a"),
+			},
+			wantErr: ErrSubAgentCycle,
+			wantMsg: "sub-agent cycle: a -> a",
+		},
+		{
+			name: "cycle through other agents",
+			agents: []*Agent{
+				delegating("a", "b"),
+				delegating("b", "c"),
+				delegating("c", "a"),
+			},
+			wantErr: ErrSubAgentCycle,
+			wantMsg: "sub-agent cycle: a -> b -> c -> a",
+		},
+		{
+			name: "inline sub-agent too deep",
+			agents: []*Agent{
+				delegating("a", "b"),
+				delegating("b", "c"),
+				delegating("c", "d"),
+				{Name: "d", SubAgents: []subagent.SubAgent{helper}},
+			},
+			wantErr: ErrSubAgentDepth,
+			wantMsg: "sub-agents nested 4 levels deep, the platform allows 3: a -> b -> c -> d -> helper",
+		},
+		{
+			name: "reference pinned to another org is external",
+			agents: []*Agent{
+				{Name: "a", SubAgents: []subagent.SubAgent{subagent.Reference("a", "a", subagent.InOrg("other"))}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.agents[0].ValidateSubAgents(tt.agents)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("ValidateSubAgents() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ValidateSubAgents() error = %v, want %v", err, tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("ValidateSubAgents() error = %q, want it to contain %q", err, tt.wantMsg)
+			}
+		})
+	}
+}
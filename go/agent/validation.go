@@ -19,11 +19,17 @@ const (
 
 	// Description validation
 	descriptionMaxLength = 500
+
+	// Changelog validation
+	changelogMaxLength = 1000
 )
 
 // nameRegex matches valid agent names (lowercase alphanumeric with hyphens).
 var nameRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
 
+// versionRegex matches semantic versions (same rule as workflow versions).
+var versionRegex = regexp.MustCompile(`^\d+\.\d+\.\d+(-[a-zA-Z0-9.-]+)?(\+[a-zA-Z0-9.-]+)?$`)
+
 // validate validates an Agent according to the validation rules.
 //
 // Validation rules:
@@ -31,6 +37,9 @@ var nameRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
 //   - Instructions: required, min 10 chars, max 10,000 chars
 //   - Description: optional, max 500 chars
 //   - IconURL: optional, must be valid URL if provided
+//   - Version: optional, must be valid semver if provided
+//   - Changelog: optional, max 1,000 chars
+//   - Resources: optional, CPU and memory must be valid quantities
 func validate(a *Agent) error {
 	// Validate name (required)
 	if err := validateName(a.Name); err != nil {
@@ -56,6 +65,23 @@ func validate(a *Agent) error {
 		}
 	}
 
+	// Validate version (optional)
+	if a.Version != "" {
+		if err := validateVersion(a.Version); err != nil {
+			return err
+		}
+	}
+
+	// Validate changelog (optional)
+	if err := validateChangelog(a.Changelog); err != nil {
+		return err
+	}
+
+	// Validate resources (optional)
+	if err := a.Resources.Validate(); err != nil {
+		return NewValidationErrorWithCause("resources", a.Resources.String(), "quantity", err.Error(), ErrInvalidResources)
+	}
+
 	return nil
 }
 
@@ -197,3 +223,41 @@ func validateIconURL(iconURL string) error {
 
 	return nil
 }
+
+// validateVersion validates the agent version.
+//
+// Rules:
+//   - Optional (empty is valid)
+//   - Must be semantic version (e.g., "1.3.0", "2.0.0-beta.1")
+func validateVersion(version string) error {
+	if !versionRegex.MatchString(version) {
+		return NewValidationErrorWithCause(
+			"version",
+			version,
+			"semver",
+			"version must be semantic version (e.g., 1.3.0)",
+			ErrInvalidVersion,
+		)
+	}
+
+	return nil
+}
+
+// validateChangelog validates the agent changelog.
+//
+// Rules:
+//   - Optional
+//   - Max 1,000 characters
+func validateChangelog(changelog string) error {
+	if len(changelog) > changelogMaxLength {
+		return NewValidationErrorWithCause(
+			"changelog",
+			changelog,
+			"max_length",
+			fmt.Sprintf("changelog must be at most %d characters (got %d)", changelogMaxLength, len(changelog)),
+			ErrInvalidChangelog,
+		)
+	}
+
+	return nil
+}
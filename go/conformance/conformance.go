@@ -0,0 +1,165 @@
+// Package conformance verifies that resources still synthesize identically
+// after an SDK upgrade.
+//
+// Check compares the manifests synthesized from a program's resources with
+// manifests recorded in the repository, so an upgrade that changes what would
+// be deployed fails CI instead of surprising the platform:
+//
+//	func TestManifests(t *testing.T) {
+//	    conformance.Check(t, "testdata/manifests", defineResources)
+//	}
+//
+// Run the tests with -conformance.update (or STIGMER_UPDATE_GOLDEN=true) to
+// record the manifests, and again when a change is intended.
+//
+// The package also ships canonical fixtures, versioned with the platform API,
+// that cover every task kind and agent feature. RunFixtures checks that the
+// SDK synthesizes each fixture to the manifest the platform expects.
+package conformance
+
+import (
+	"errors"
+	"flag"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	agentv1 "buf.build/gen/go/leftbin/stigmer/protocolbuffers/go/ai/stigmer/agentic/agent/v1"
+	workflowv1 "buf.build/gen/go/leftbin/stigmer/protocolbuffers/go/ai/stigmer/agentic/workflow/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/leftbin/stigmer-sdk/go/stigmer"
+	"github.com/leftbin/stigmer-sdk/go/synth"
+)
+
+// updateGolden rewrites recorded manifests instead of comparing against them:
+//
+//	go test ./... -conformance.update
+var updateGolden = flag.Bool("conformance.update", false, "rewrite conformance manifests with the current output")
+
+// updateGoldenEnv rewrites recorded manifests when set to true, for runners
+// that cannot pass test flags.
+const updateGoldenEnv = "STIGMER_UPDATE_GOLDEN"
+
+// Recorded manifest file names, in the directory passed to Check.
+const (
+	AgentManifestFile    = "agents.json"
+	WorkflowManifestFile = "workflows.json"
+)
+
+// Check synthesizes the resources defined by build and compares the manifests
+// with those recorded in dir. Differences are reported as a synth.Diff plan.
+//
+// Manifests are synthesized with a fixed clock and seeded IDs, and SDK
+// metadata (such as the SDK version) is not recorded, so only changes to the
+// resources themselves are reported.
+func Check(t testing.TB, dir string, build func(*stigmer.Context) error) {
+	t.Helper()
+
+	ctx := stigmer.NewContext(
+		stigmer.WithClock(stigmer.FixedClock(time.Unix(0, 0).UTC())),
+		stigmer.WithIDGenerator(stigmer.SeededIDs(1)),
+	)
+	if err := build(ctx); err != nil {
+		t.Fatalf("defining resources: %v", err)
+		return
+	}
+	m, err := ctx.Manifests()
+	if err != nil {
+		t.Fatalf("synthesizing manifests: %v", err)
+		return
+	}
+
+	checkManifest(t, filepath.Join(dir, AgentManifestFile), m.AgentManifest, &agentv1.AgentManifest{}, os.ReadFile)
+	checkManifest(t, filepath.Join(dir, WorkflowManifestFile), m.WorkflowManifest, &workflowv1.WorkflowManifest{}, os.ReadFile)
+}
+
+// checkManifest compares got (a nil manifest if no resources of its kind were
+// defined) with the manifest recorded at path, read with read and decoded
+// into want. In update mode got is written to path instead.
+func checkManifest(t testing.TB, path string, got, want proto.Message, read func(string) ([]byte, error)) {
+	t.Helper()
+
+	if got.ProtoReflect().IsValid() {
+		got = withoutMetadata(got)
+	} else {
+		got = nil
+	}
+	if shouldUpdateGolden() {
+		if err := writeManifest(path, got); err != nil {
+			t.Fatalf("writing %s: %v", path, err)
+		}
+		return
+	}
+
+	data, err := read(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist) && got == nil:
+		return
+	case errors.Is(err, fs.ErrNotExist):
+		t.Fatalf("%s does not exist; run the tests with -conformance.update to record it", path)
+		return
+	case err != nil:
+		t.Fatalf("reading %s: %v", path, err)
+		return
+	}
+	if err := protojson.Unmarshal(data, want); err != nil {
+		t.Fatalf("decoding %s: %v", path, err)
+		return
+	}
+
+	if got == nil {
+		got = want.ProtoReflect().New().Interface()
+	}
+	plan, err := synth.Diff(want, got)
+	if err != nil {
+		t.Fatalf("comparing with %s: %v", path, err)
+		return
+	}
+	if !plan.Empty() {
+		t.Errorf("synthesized manifest differs from %s:\n%s\nrun the tests with -conformance.update if the change is intended", path, plan)
+	}
+}
+
+// withoutMetadata returns a copy of the manifest without its SDK metadata.
+func withoutMetadata(m proto.Message) proto.Message {
+	m = proto.Clone(m)
+	switch manifest := m.(type) {
+	case *agentv1.AgentManifest:
+		manifest.SdkMetadata = nil
+	case *workflowv1.WorkflowManifest:
+		manifest.SdkMetadata = nil
+	}
+	return m
+}
+
+// writeManifest records m at path, or removes the file when m is nil.
+func writeManifest(path string, m proto.Message) error {
+	if m == nil {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	data, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(m)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// shouldUpdateGolden reports whether recorded manifests should be rewritten.
+func shouldUpdateGolden() bool {
+	if *updateGolden {
+		return true
+	}
+	update, _ := strconv.ParseBool(os.Getenv(updateGoldenEnv))
+	return update
+}
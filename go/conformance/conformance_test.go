@@ -0,0 +1,68 @@
+package conformance
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/agent"
+	"github.com/leftbin/stigmer-sdk/go/stigmer"
+)
+
+func TestFixtures(t *testing.T) {
+	RunFixtures(t)
+}
+
+func TestFixtures_Platforms(t *testing.T) {
+	seen := map[string]bool{}
+	for _, f := range Fixtures() {
+		if seen[f.Name] {
+			t.Errorf("duplicate fixture %q", f.Name)
+		}
+		seen[f.Name] = true
+		if f.Platform == "" || f.Define == nil {
+			t.Errorf("fixture %q is incomplete", f.Name)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	define := func(name string) func(*stigmer.Context) error {
+		return func(ctx *stigmer.Context) error {
+			_, err := agent.New(ctx, agent.WithName(name), agent.WithInstructions("Review code changes."))
+			return err
+		}
+	}
+
+	t.Setenv(updateGoldenEnv, "true")
+	Check(t, dir, define("reviewer"))
+	if _, err := os.Stat(filepath.Join(dir, AgentManifestFile)); err != nil {
+		t.Fatalf("agent manifest not recorded: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, WorkflowManifestFile)); !os.IsNotExist(err) {
+		t.Errorf("workflow manifest recorded without workflows, Stat() error = %v", err)
+	}
+
+	t.Setenv(updateGoldenEnv, "false")
+	Check(t, dir, define("reviewer"))
+
+	// A changed resource must be reported
+	ft := &fakeT{TB: t}
+	Check(ft, dir, define("other-reviewer"))
+	if !ft.failed {
+		t.Error("Check() did not report a changed agent")
+	}
+}
+
+// fakeT records failures instead of failing the test.
+type fakeT struct {
+	testing.TB
+	failed bool
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(format string, args ...any) { f.failed = true }
+
+func (f *fakeT) Fatalf(format string, args ...any) { f.failed = true }
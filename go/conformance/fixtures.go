@@ -0,0 +1,245 @@
+package conformance
+
+import (
+	"embed"
+	"io/fs"
+	"path"
+	"testing"
+	"time"
+
+	agentv1 "buf.build/gen/go/leftbin/stigmer/protocolbuffers/go/ai/stigmer/agentic/agent/v1"
+	workflowv1 "buf.build/gen/go/leftbin/stigmer/protocolbuffers/go/ai/stigmer/agentic/workflow/v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/leftbin/stigmer-sdk/go/agent"
+	"github.com/leftbin/stigmer-sdk/go/environment"
+	"github.com/leftbin/stigmer-sdk/go/mcpserver"
+	"github.com/leftbin/stigmer-sdk/go/skill"
+	"github.com/leftbin/stigmer-sdk/go/stigmer"
+	"github.com/leftbin/stigmer-sdk/go/subagent"
+	"github.com/leftbin/stigmer-sdk/go/synth"
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+// fixtureFiles holds the expected manifests of the canonical fixtures, in
+// fixtures/<platform>/<name>/.
+//
+//go:embed fixtures
+var fixtureFiles embed.FS
+
+// Fixture is a canonical set of resources and the manifests the platform
+// expects for them.
+type Fixture struct {
+	// Name identifies the fixture, e.g. "agent-features"
+	Name string
+
+	// Platform is the first platform release that can run the fixture
+	// (see synth.PlatformVersions); its manifests are stored under it
+	Platform string
+
+	// Define registers the fixture's resources
+	Define func(*stigmer.Context) error
+}
+
+// dir returns the directory of the fixture's manifests.
+func (f Fixture) dir() string {
+	return path.Join("fixtures", f.Platform, f.Name)
+}
+
+// Fixtures returns the canonical fixtures, oldest platform release first.
+func Fixtures() []Fixture {
+	return []Fixture{
+		{Name: "agent-features", Platform: "2024.4", Define: defineAgentFeatures},
+		{Name: "workflow-control-flow", Platform: "2024.4", Define: defineControlFlow},
+		{Name: "agent-orchestration", Platform: "2025.1", Define: defineOrchestration},
+	}
+}
+
+// RunFixtures checks that the SDK synthesizes every canonical fixture to the
+// manifests the platform expects, and that the manifests run on the fixture's
+// platform release. Add it to a test to verify an SDK upgrade:
+//
+//	func TestSDKConformance(t *testing.T) {
+//	    conformance.RunFixtures(t)
+//	}
+func RunFixtures(t *testing.T) {
+	for _, f := range Fixtures() {
+		t.Run(f.Name, func(t *testing.T) {
+			m := synthesizeFixture(t, f)
+			if m == nil {
+				return
+			}
+			checkFixtureManifest(t, f, AgentManifestFile, m.AgentManifest, &agentv1.AgentManifest{})
+			checkFixtureManifest(t, f, WorkflowManifestFile, m.WorkflowManifest, &workflowv1.WorkflowManifest{})
+
+			caps, err := synth.PlatformCapabilities(f.Platform)
+			if err != nil {
+				t.Fatalf("PlatformCapabilities(%q) error = %v", f.Platform, err)
+			}
+			if m.WorkflowManifest != nil {
+				if err := synth.CheckCompatibility(m.WorkflowManifest, caps); err != nil {
+					t.Errorf("fixture does not run on platform %s: %v", f.Platform, err)
+				}
+			}
+		})
+	}
+}
+
+// synthesizeFixture synthesizes the fixture's manifests deterministically.
+func synthesizeFixture(t *testing.T, f Fixture) *stigmer.Manifests {
+	t.Helper()
+	ctx := stigmer.NewContext(
+		stigmer.WithClock(stigmer.FixedClock(time.Unix(0, 0).UTC())),
+		stigmer.WithIDGenerator(stigmer.SeededIDs(1)),
+	)
+	if err := f.Define(ctx); err != nil {
+		t.Fatalf("defining fixture %s: %v", f.Name, err)
+		return nil
+	}
+	m, err := ctx.Manifests()
+	if err != nil {
+		t.Fatalf("synthesizing fixture %s: %v", f.Name, err)
+		return nil
+	}
+	return m
+}
+
+// checkFixtureManifest compares a fixture manifest with the embedded one. In
+// update mode the manifest is written to the package source directory.
+func checkFixtureManifest(t *testing.T, f Fixture, name string, got, want proto.Message) {
+	t.Helper()
+	readEmbedded := func(p string) ([]byte, error) { return fs.ReadFile(fixtureFiles, p) }
+	checkManifest(t, path.Join(f.dir(), name), got, want, readEmbedded)
+}
+
+func defineAgentFeatures(ctx *stigmer.Context) error {
+	token, err := environment.New(
+		environment.WithName("GITHUB_TOKEN"),
+		environment.WithSecret(true),
+		environment.WithDescription("GitHub token with repo scope"),
+	)
+	if err != nil {
+		return err
+	}
+	apiToken, err := environment.New(
+		environment.WithName("API_TOKEN"),
+		environment.WithSecret(true),
+	)
+	if err != nil {
+		return err
+	}
+	region, err := environment.New(
+		environment.WithName("AWS_REGION"),
+		environment.WithDefaultValue("us-east-1"),
+	)
+	if err != nil {
+		return err
+	}
+	github, err := mcpserver.Stdio(
+		mcpserver.WithName("github"),
+		mcpserver.WithCommand("npx"),
+		mcpserver.WithArgs("-y", "@modelcontextprotocol/server-github"),
+		mcpserver.WithEnvPlaceholder("GITHUB_TOKEN", "${GITHUB_TOKEN}"),
+		mcpserver.WithEnabledTools("create_issue", "create_pr"),
+	)
+	if err != nil {
+		return err
+	}
+	api, err := mcpserver.HTTP(
+		mcpserver.WithName("api-service"),
+		mcpserver.WithURL("https://mcp.example.com/api"),
+		mcpserver.WithHeader("Authorization", "Bearer ${API_TOKEN}"),
+		mcpserver.WithTimeout(60),
+	)
+	if err != nil {
+		return err
+	}
+	guide, err := skill.New(skill.WithName("style-guide"), skill.WithMarkdown("# Style\n\nPrefer small functions."))
+	if err != nil {
+		return err
+	}
+	scanner, err := subagent.Inline(
+		subagent.WithName("security-scanner"),
+		subagent.WithInstructions("Scan code for security vulnerabilities"),
+		subagent.WithDescription("Security-focused code analyzer"),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = agent.New(ctx,
+		agent.WithName("code-reviewer"),
+		agent.WithInstructions("Review pull requests for correctness and style."),
+		agent.WithDescription("Reviews pull requests"),
+		agent.WithIconURL("https://example.com/reviewer.png"),
+		agent.WithSkills(skill.Platform("coding-best-practices"), *guide),
+		agent.WithMCPServers(github, api),
+		agent.WithSubAgent(scanner),
+		agent.WithEnvironmentVariables(token, apiToken, region),
+	)
+	return err
+}
+
+func defineControlFlow(ctx *stigmer.Context) error {
+	wf, err := workflow.New(ctx,
+		workflow.WithNamespace("conformance"),
+		workflow.WithName("control-flow"),
+		workflow.WithVersion("1.0.0"),
+	)
+	if err != nil {
+		return err
+	}
+
+	fetch := wf.HttpGet("fetchItems", "https://api.example.com/items",
+		workflow.Header("Authorization", workflow.RuntimeSecret("API_TOKEN")),
+		workflow.Timeout(30),
+	)
+	wf.AddTask(workflow.SwitchTask("route",
+		workflow.WithCase(workflow.Equals(workflow.Var("fetchItems.status"), workflow.Literal("ready")), "processItems"),
+		workflow.WithDefault("giveUp"),
+	))
+	wf.AddTask(workflow.ForTask("processItems",
+		workflow.WithIn(fetch.FieldAsList("items").Filter(".active")),
+		workflow.WithDo(workflow.SetTask("mark", workflow.SetVar("processed", "${ .id }"))),
+	))
+	wf.AddTask(workflow.ForkTask("notify",
+		workflow.WithBranch("email", workflow.HttpCallTask("sendEmail",
+			workflow.WithHTTPPost(),
+			workflow.WithURI("https://mail.example.com/send"),
+			workflow.WithBody(map[string]any{"count": fetch.FieldAsList("items").Len().Expression()}),
+		)),
+		workflow.WithBranch("audit", workflow.SetTask("audit", workflow.SetVar("event", "processed"))),
+	))
+	wf.SetVars("giveUp", "status", "failed")
+	return nil
+}
+
+func defineOrchestration(ctx *stigmer.Context) error {
+	reviewer, err := agent.New(ctx,
+		agent.WithName("pr-reviewer"),
+		agent.WithInstructions("Review the pull request you are given."),
+	)
+	if err != nil {
+		return err
+	}
+	wf, err := workflow.New(ctx,
+		workflow.WithNamespace("conformance"),
+		workflow.WithName("review-queue"),
+	)
+	if err != nil {
+		return err
+	}
+
+	prs := wf.HttpGet("listPullRequests", "https://api.github.com/repos/org/repo/pulls")
+	reviews := wf.FanOutAgentCalls("reviewAll", workflow.Agent(reviewer),
+		workflow.WithItems(prs.FieldAsList("items")),
+		workflow.WithConcurrency(2),
+		workflow.WithCollect("reviews"),
+	)
+	wf.CallAgent("summarize",
+		workflow.AgentOption(workflow.AgentBySlug("release-notes-writer")),
+		workflow.WithPrompt("${ $context.reviews | tojson }"),
+		workflow.AgentTimeout(10*time.Minute),
+	).DependsOn(reviews)
+	return nil
+}
@@ -0,0 +1,40 @@
+package environment
+
+// Context is a minimal interface that represents a stigmer context.
+// This allows the environment package to declare variables on a context
+// without importing the stigmer package (avoiding import cycles).
+//
+// The stigmer.Context type implements this interface.
+type Context interface {
+	DeclareEnvironmentVariable(Variable) (Variable, error)
+}
+
+// Declare creates an environment variable once at context level so agents and
+// workflows built in the same program can attach the same declaration instead
+// of defining it twice.
+//
+// Declaring a name that is already declared returns the existing variable if
+// the settings match, and an error if they differ.
+//
+// Example:
+//
+//	stigmer.Run(func(ctx *stigmer.Context) error {
+//	    apiToken, err := environment.Declare(ctx,
+//	        environment.WithName("API_TOKEN"),
+//	        environment.WithSecret(true),
+//	    )
+//	    if err != nil {
+//	        return err
+//	    }
+//
+//	    workflow.New(ctx, ..., workflow.WithEnvironmentVariable(apiToken))
+//	    agent.New(ctx, ..., agent.WithEnvironmentVariable(apiToken))
+//	    return nil
+//	})
+func Declare(ctx Context, opts ...Option) (Variable, error) {
+	v, err := New(opts...)
+	if err != nil {
+		return Variable{}, err
+	}
+	return ctx.DeclareEnvironmentVariable(v)
+}
@@ -30,8 +30,9 @@ func main() {
 		orgName := ctx.SetString("orgName", "data-processing-team")
 		retryCount := ctx.SetInt("retryCount", 3)
 		
-		// Create shared environment variable
-		apiToken, err := environment.New(
+		// Declare the shared environment variable once on the context;
+		// both the workflow and the agent attach the same declaration
+		apiToken, err := environment.Declare(ctx,
 			environment.WithName("API_TOKEN"),
 			environment.WithSecret(true),
 			environment.WithDescription("API authentication token"),
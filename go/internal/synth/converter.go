@@ -1,7 +1,10 @@
 package synth
 
 import (
+	"errors"
 	"fmt"
+	"regexp"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -42,7 +45,8 @@ const (
 //   - subagent.SubAgent → agentv1.ManifestSubAgent
 //   - environment.Variable → agentv1.ManifestEnvironmentVariable
 //
-// Returns an error if any nested conversion fails.
+// Returns an error if any nested conversion fails. Every agent is converted
+// before returning, and the failures are joined as *ResourceError values.
 func ToManifest(agentInterfaces ...interface{}) (*agentv1.AgentManifest, error) {
 	if len(agentInterfaces) == 0 {
 		return nil, fmt.Errorf("at least one agent is required")
@@ -62,63 +66,107 @@ func ToManifest(agentInterfaces ...interface{}) (*agentv1.AgentManifest, error)
 		Agents:      []*agentv1.AgentBlueprint{},
 	}
 
-	// Convert each agent
+	// Type assert to *agent.Agent
+	agents := make([]*agent.Agent, 0, len(agentInterfaces))
 	for agentIdx, agentInterface := range agentInterfaces {
-		// Type assert to *agent.Agent
 		a, ok := agentInterface.(*agent.Agent)
 		if !ok {
 			return nil, fmt.Errorf("agent[%d]: invalid type %T, expected *agent.Agent", agentIdx, agentInterface)
 		}
+		agents = append(agents, a)
+	}
 
-		// Create agent blueprint
-		blueprint := &agentv1.AgentBlueprint{
-			Name:         a.Name,
-			Instructions: a.Instructions,
-			Description:  a.Description,
-			IconUrl:      a.IconURL,
+	// Convert each agent, collecting the errors of all agents
+	var errs []error
+	for agentIdx, a := range agents {
+		blueprint, err := agentToBlueprint(a)
+		if err == nil {
+			// Referenced sub-agents can delegate to the other agents
+			err = a.ValidateSubAgents(agents)
 		}
-
-		// Convert skills
-		for i, s := range a.Skills {
-			manifestSkill, err := skillToManifest(s)
-			if err != nil {
-				return nil, fmt.Errorf("agent[%d] %s: converting skill[%d]: %w", agentIdx, a.Name, i, err)
-			}
-			blueprint.Skills = append(blueprint.Skills, manifestSkill)
+		if err != nil {
+			errs = append(errs, &ResourceError{Kind: "agent", Index: agentIdx, Name: a.Name, Err: err})
+			continue
 		}
 
-		// Convert MCP servers
-		for i, mcp := range a.MCPServers {
-			manifestMCP, err := mcpServerToManifest(mcp)
-			if err != nil {
-				return nil, fmt.Errorf("agent[%d] %s: converting mcp_server[%d]: %w", agentIdx, a.Name, i, err)
-			}
-			blueprint.McpServers = append(blueprint.McpServers, manifestMCP)
+		// Add blueprint to manifest
+		manifest.Agents = append(manifest.Agents, blueprint)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return manifest, nil
+}
+
+// agentToBlueprint converts a single agent. Failures of its skills, MCP
+// servers, sub-agents and environment variables are all reported, joined.
+func agentToBlueprint(a *agent.Agent) (*agentv1.AgentBlueprint, error) {
+	// Note: a.Version, a.Changelog and a.Resources (and MCP server resources)
+	// are validated by the SDK but the AgentBlueprint proto has no fields for
+	// them yet, so they are not part of the manifest until the proto is extended.
+	blueprint := &agentv1.AgentBlueprint{
+		Name:         a.Name,
+		Instructions: a.Instructions,
+		Description:  a.Description,
+		IconUrl:      a.IconURL,
+	}
+	var errs []error
+
+	// Convert skills
+	for i, s := range a.Skills {
+		manifestSkill, err := skillToManifest(s)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("converting skill[%d]: %w", i, err))
+			continue
 		}
+		blueprint.Skills = append(blueprint.Skills, manifestSkill)
+	}
 
-		// Convert sub-agents
-		for i, sub := range a.SubAgents {
-			manifestSub, err := subAgentToManifest(sub)
-			if err != nil {
-				return nil, fmt.Errorf("agent[%d] %s: converting sub_agent[%d]: %w", agentIdx, a.Name, i, err)
-			}
-			blueprint.SubAgents = append(blueprint.SubAgents, manifestSub)
+	// Convert MCP servers
+	for i, mcp := range a.MCPServers {
+		manifestMCP, err := mcpServerToManifest(mcp)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("converting mcp_server[%d]: %w", i, err))
+			continue
 		}
+		blueprint.McpServers = append(blueprint.McpServers, manifestMCP)
+	}
 
-		// Convert environment variables
-		for i, env := range a.EnvironmentVariables {
-			manifestEnv, err := environmentVariableToManifest(env)
-			if err != nil {
-				return nil, fmt.Errorf("agent[%d] %s: converting environment_variable[%d]: %w", agentIdx, a.Name, i, err)
-			}
-			blueprint.EnvironmentVariables = append(blueprint.EnvironmentVariables, manifestEnv)
+	// Convert sub-agents
+	for i, sub := range a.SubAgents {
+		manifestSub, err := subAgentToManifest(sub)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("converting sub_agent[%d]: %w", i, err))
+			continue
 		}
+		blueprint.SubAgents = append(blueprint.SubAgents, manifestSub)
+	}
 
-		// Add blueprint to manifest
-		manifest.Agents = append(manifest.Agents, blueprint)
+	// Convert environment variables (a variable attached more than once,
+	// e.g. a context-level declaration, appears in the manifest once)
+	envVars, err := dedupeEnvVars(a.EnvironmentVariables)
+	if err != nil {
+		return nil, errors.Join(append(errs, err)...)
+	}
+	// Declared placeholders are reported by AgentWarnings
+	envVars, _, err = declarePlaceholders(a.MCPServers, envVars)
+	if err != nil {
+		return nil, errors.Join(append(errs, err)...)
+	}
+	for i, env := range envVars {
+		manifestEnv, err := environmentVariableToManifest(env)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("converting environment_variable[%d]: %w", i, err))
+			continue
+		}
+		blueprint.EnvironmentVariables = append(blueprint.EnvironmentVariables, manifestEnv)
 	}
 
-	return manifest, nil
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return blueprint, nil
 }
 
 // skillToManifest converts a skill.Skill to a ManifestSkill proto.
@@ -274,9 +322,13 @@ func subAgentToManifest(sub subagent.SubAgent) (*agentv1.ManifestSubAgent, error
 			},
 		}
 	} else if sub.IsReference() {
+		// Reject malformed references before they reach the manifest
+		if err := sub.Validate(); err != nil {
+			return nil, err
+		}
 		manifestSub.Source = &agentv1.ManifestSubAgent_Reference{
 			Reference: &agentv1.ReferencedSubAgent{
-				AgentInstanceId: sub.AgentInstanceID(),
+				AgentInstanceId: sub.QualifiedRef(),
 			},
 		}
 	} else {
@@ -286,6 +338,91 @@ func subAgentToManifest(sub subagent.SubAgent) (*agentv1.ManifestSubAgent, error
 	return manifestSub, nil
 }
 
+// dedupeEnvVars drops repeated declarations of the same variable, keeping the
+// first. Two variables with the same name but different settings are an error.
+func dedupeEnvVars(vars []environment.Variable) ([]environment.Variable, error) {
+	seen := make(map[string]environment.Variable, len(vars))
+	result := make([]environment.Variable, 0, len(vars))
+	for _, v := range vars {
+		if existing, ok := seen[v.Name]; ok {
+			if existing != v {
+				return nil, fmt.Errorf("environment variable %q is declared twice with different settings", v.Name)
+			}
+			continue
+		}
+		seen[v.Name] = v
+		result = append(result, v)
+	}
+	return result, nil
+}
+
+// placeholderRef matches ${VAR} references in MCP server settings.
+var placeholderRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// declarePlaceholders makes sure every ${VAR} referenced by an MCP server's env
+// placeholders, headers or query parameters is a declared environment
+// variable. Undeclared variables would leave the server without a value at
+// runtime, so they are declared as required secrets and reported in warnings.
+func declarePlaceholders(servers []mcpserver.MCPServer, vars []environment.Variable) ([]environment.Variable, []string, error) {
+	declared := make(map[string]bool, len(vars))
+	for _, v := range vars {
+		declared[v.Name] = true
+	}
+
+	var warnings []string
+	for _, server := range servers {
+		for _, value := range placeholderValues(server) {
+			for _, match := range placeholderRef.FindAllStringSubmatch(value, -1) {
+				name := match[1]
+				if declared[name] {
+					continue
+				}
+				v, err := environment.New(
+					environment.WithName(name),
+					environment.WithSecret(true),
+					environment.WithDescription(fmt.Sprintf("Referenced by MCP server %s", server.Name())),
+				)
+				if err != nil {
+					return nil, nil, fmt.Errorf("mcp_server %s references ${%s}: %w", server.Name(), name, err)
+				}
+				declared[name] = true
+				vars = append(vars, v)
+				warnings = append(warnings, fmt.Sprintf(
+					"MCP server %s references ${%s}, which is not declared; declaring it as a required secret (use WithEnvironmentVariable to declare it explicitly)",
+					server.Name(), name))
+			}
+		}
+	}
+	return vars, warnings, nil
+}
+
+// placeholderValues returns the settings of a server that may hold
+// placeholders, in a stable order.
+func placeholderValues(server mcpserver.MCPServer) []string {
+	var maps []map[string]string
+	switch s := server.(type) {
+	case *mcpserver.StdioServer:
+		maps = append(maps, s.EnvPlaceholders())
+	case *mcpserver.DockerServer:
+		maps = append(maps, s.EnvPlaceholders())
+	case *mcpserver.HTTPServer:
+		maps = append(maps, s.Headers(), s.QueryParams())
+	}
+
+	var values []string
+	for _, m := range maps {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			values = append(values, m[k])
+		}
+	}
+	return values
+}
+
 // environmentVariableToManifest converts an environment.Variable to a ManifestEnvironmentVariable proto.
 func environmentVariableToManifest(env environment.Variable) (*agentv1.ManifestEnvironmentVariable, error) {
 	// environment.Variable fields are exported, so access them directly
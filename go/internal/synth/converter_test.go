@@ -0,0 +1,67 @@
+package synth
+
+import (
+	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/environment"
+	"github.com/leftbin/stigmer-sdk/go/mcpserver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDedupeEnvVars verifies repeated declarations collapse and conflicts are reported.
+func TestDedupeEnvVars(t *testing.T) {
+	token, err := environment.New(environment.WithName("API_TOKEN"), environment.WithSecret(true))
+	require.NoError(t, err)
+	region, err := environment.New(environment.WithName("AWS_REGION"), environment.WithDefaultValue("us-east-1"))
+	require.NoError(t, err)
+
+	vars, err := dedupeEnvVars([]environment.Variable{token, region, token})
+	require.NoError(t, err)
+	assert.Equal(t, []environment.Variable{token, region}, vars)
+
+	conflict := token
+	conflict.IsSecret = false
+	_, err = dedupeEnvVars([]environment.Variable{token, conflict})
+	assert.ErrorContains(t, err, "API_TOKEN")
+}
+
+// TestDeclarePlaceholders verifies undeclared MCP placeholders are declared with a warning.
+func TestDeclarePlaceholders(t *testing.T) {
+	github, err := mcpserver.Stdio(
+		mcpserver.WithName("github"),
+		mcpserver.WithCommand("npx"),
+		mcpserver.WithEnvPlaceholder("GITHUB_TOKEN", "${GITHUB_TOKEN}"),
+		mcpserver.WithEnvPlaceholder("GITHUB_ORG", "${GITHUB_ORG}"),
+	)
+	require.NoError(t, err)
+	api, err := mcpserver.HTTP(
+		mcpserver.WithName("api"),
+		mcpserver.WithURL("https://mcp.example.com"),
+		mcpserver.WithHeader("Authorization", "Bearer ${API_TOKEN}"),
+		mcpserver.WithQueryParam("org", "${GITHUB_ORG}"),
+	)
+	require.NoError(t, err)
+	token, err := environment.New(environment.WithName("GITHUB_TOKEN"), environment.WithSecret(true))
+	require.NoError(t, err)
+
+	vars, warnings, err := declarePlaceholders([]mcpserver.MCPServer{github, api}, []environment.Variable{token})
+	require.NoError(t, err)
+	require.Len(t, vars, 3)
+	assert.Equal(t, token, vars[0])
+	assert.Equal(t, "GITHUB_ORG", vars[1].Name)
+	assert.Equal(t, "API_TOKEN", vars[2].Name)
+	assert.True(t, vars[2].IsSecret)
+	assert.True(t, vars[2].Required)
+	require.Len(t, warnings, 2)
+	assert.Contains(t, warnings[1], "MCP server api references ${API_TOKEN}")
+
+	lower, err := mcpserver.Stdio(
+		mcpserver.WithName("lower"),
+		mcpserver.WithCommand("npx"),
+		mcpserver.WithEnvPlaceholder("TOKEN", "${token}"),
+	)
+	require.NoError(t, err)
+	_, _, err = declarePlaceholders([]mcpserver.MCPServer{lower}, nil)
+	assert.ErrorContains(t, err, "${token}")
+}
@@ -0,0 +1,22 @@
+package synth
+
+import "fmt"
+
+// ResourceError is the conversion failure of a single agent or workflow.
+//
+// ToManifest and ToWorkflowManifest convert every resource before returning,
+// so their error may join several ResourceErrors (see errors.Join).
+type ResourceError struct {
+	Kind  string // "agent" or "workflow"
+	Index int    // position in the converted list
+	Name  string
+	Err   error
+}
+
+func (e *ResourceError) Error() string {
+	return fmt.Sprintf("%s[%d] %s: %v", e.Kind, e.Index, e.Name, e.Err)
+}
+
+func (e *ResourceError) Unwrap() error {
+	return e.Err
+}
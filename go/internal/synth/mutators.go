@@ -0,0 +1,53 @@
+package synth
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+// TaskMutator modifies a task's converted configuration during synthesis.
+//
+// The config map is the task_config that will be written to the manifest, so
+// keys follow the manifest format (e.g., "endpoint", "headers" for HTTP_CALL).
+type TaskMutator func(task *workflow.Task, config map[string]interface{}) error
+
+var (
+	taskMutatorsMu sync.RWMutex
+	taskMutators   = make(map[workflow.TaskKind][]TaskMutator)
+)
+
+// RegisterTaskMutator registers a mutator that runs on every task of the given
+// kind after its configuration has been converted. Mutators for the same kind
+// run in registration order.
+//
+// Custom task kinds are lowered before conversion, so mutators should be
+// registered for the built-in kind they lower to.
+func RegisterTaskMutator(kind workflow.TaskKind, fn TaskMutator) {
+	taskMutatorsMu.Lock()
+	defer taskMutatorsMu.Unlock()
+
+	taskMutators[kind] = append(taskMutators[kind], fn)
+}
+
+// applyTaskMutators runs the registered mutators for the task's kind on config.
+func applyTaskMutators(task *workflow.Task, config map[string]interface{}) error {
+	taskMutatorsMu.RLock()
+	mutators := taskMutators[task.Kind]
+	taskMutatorsMu.RUnlock()
+
+	for i, mutate := range mutators {
+		if err := mutate(task, config); err != nil {
+			return fmt.Errorf("task mutator[%d] for %s task %s: %w", i, task.Kind, task.Name, err)
+		}
+	}
+	return nil
+}
+
+// hasTaskMutators reports whether any mutators are registered for kind.
+func hasTaskMutators(kind workflow.TaskKind) bool {
+	taskMutatorsMu.RLock()
+	defer taskMutatorsMu.RUnlock()
+	return len(taskMutators[kind]) > 0
+}
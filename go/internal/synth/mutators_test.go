@@ -0,0 +1,74 @@
+package synth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withTaskMutators registers mutators for the duration of a test.
+func withTaskMutators(t *testing.T, kind workflow.TaskKind, fns ...TaskMutator) {
+	t.Helper()
+	for _, fn := range fns {
+		RegisterTaskMutator(kind, fn)
+	}
+	t.Cleanup(func() {
+		taskMutatorsMu.Lock()
+		defer taskMutatorsMu.Unlock()
+		delete(taskMutators, kind)
+	})
+}
+
+// TestTaskMutator_AppliesToAllTasksOfKind verifies mutators run on top-level and nested tasks.
+func TestTaskMutator_AppliesToAllTasksOfKind(t *testing.T) {
+	withTaskMutators(t, workflow.TaskKindHttpCall, func(task *workflow.Task, config map[string]interface{}) error {
+		config["proxy"] = "http://proxy.corp.example.com:3128"
+		return nil
+	})
+
+	wf := newTestWorkflow(t, "policy")
+	wf.AddTask(workflow.HttpCallTask("fetch",
+		workflow.WithHTTPGet(),
+		workflow.WithURI("https://api.example.com"),
+	))
+	wf.AddTask(workflow.ForTask("each",
+		workflow.WithIn("${.items}"),
+		workflow.WithDo(workflow.HttpCallTask("post",
+			workflow.WithHTTPPost(),
+			workflow.WithURI("https://api.example.com"),
+		)),
+	))
+	wf.AddTask(workflow.SetTask("done", workflow.SetVar("ok", "true")))
+
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err, "should convert workflow")
+
+	tasks := manifest.Workflows[0].Spec.Tasks
+	assert.Equal(t, "http://proxy.corp.example.com:3128", tasks[0].TaskConfig.Fields["proxy"].GetStringValue())
+
+	nested := tasks[1].TaskConfig.Fields["do"].GetListValue().Values[0].GetStructValue()
+	nestedConfig := nested.Fields["task_config"].GetStructValue()
+	assert.Equal(t, "http://proxy.corp.example.com:3128", nestedConfig.Fields["proxy"].GetStringValue())
+
+	assert.NotContains(t, tasks[2].TaskConfig.Fields, "proxy", "SET tasks should not be mutated")
+}
+
+// TestTaskMutator_Error verifies mutator errors fail synthesis.
+func TestTaskMutator_Error(t *testing.T) {
+	errPolicy := errors.New("insecure endpoints are not allowed")
+	withTaskMutators(t, workflow.TaskKindHttpCall, func(task *workflow.Task, config map[string]interface{}) error {
+		return errPolicy
+	})
+
+	wf := newTestWorkflow(t, "policy")
+	wf.AddTask(workflow.HttpCallTask("fetch",
+		workflow.WithHTTPGet(),
+		workflow.WithURI("http://api.example.com"),
+	))
+
+	_, err := ToWorkflowManifest(wf)
+	assert.ErrorIs(t, err, errPolicy)
+}
@@ -0,0 +1,255 @@
+package synth
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"unicode/utf8"
+
+	validate "buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	agentv1 "buf.build/gen/go/leftbin/stigmer/protocolbuffers/go/ai/stigmer/agentic/agent/v1"
+	workflowv1 "buf.build/gen/go/leftbin/stigmer/protocolbuffers/go/ai/stigmer/agentic/workflow/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ErrConstraintViolation is matched (with errors.Is) by every FieldViolation.
+var ErrConstraintViolation = errors.New("manifest constraint violation")
+
+// cliPopulatedFields are filled in by the CLI when it deploys a manifest, so
+// their rules do not apply to synthesized manifests. The workflow metadata is
+// only synthesized when the workflow has annotations; the CLI sets its name,
+// organization and owner scope.
+var cliPopulatedFields = map[protoreflect.FullName]bool{
+	"ai.stigmer.agentic.workflow.v1.Workflow.metadata": true,
+}
+
+// FieldViolation is a manifest field that breaks a buf.validate constraint
+// declared in the Stigmer protos.
+type FieldViolation struct {
+	Path    string // Field path within the resource, e.g. "spec.tasks[0].name"
+	Rule    string // Violated rule, e.g. "string.min_len"
+	Message string
+}
+
+func (v *FieldViolation) Error() string {
+	return fmt.Sprintf("%s: %s (%s)", v.Path, v.Message, v.Rule)
+}
+
+func (v *FieldViolation) Is(target error) bool {
+	return target == ErrConstraintViolation
+}
+
+// ValidateAgentManifest checks the manifest against the buf.validate
+// constraints of its proto definition, so problems surface during synthesis
+// with the offending field instead of as a rejection by the CLI or server.
+// Violations inside an agent are grouped in a ResourceError.
+//
+// The standard rules used by the Stigmer protos are checked (required, string,
+// integer, enum and repeated rules); CEL expressions are not evaluated, and
+// fields the CLI fills in on deploy are skipped.
+func ValidateAgentManifest(m *agentv1.AgentManifest) error {
+	errs := validateManifestFields(m, "agents")
+	for i, ag := range m.GetAgents() {
+		if violations := validateMessage(ag.ProtoReflect(), ""); len(violations) > 0 {
+			errs = append(errs, &ResourceError{Kind: "agent", Index: i, Name: ag.GetName(), Err: errors.Join(violations...)})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ValidateWorkflowManifest checks the manifest like ValidateAgentManifest.
+// Violations inside a workflow are grouped in a ResourceError.
+func ValidateWorkflowManifest(m *workflowv1.WorkflowManifest) error {
+	errs := validateManifestFields(m, "workflows")
+	for i, wf := range m.GetWorkflows() {
+		if violations := validateMessage(wf.ProtoReflect(), ""); len(violations) > 0 {
+			name := wf.GetSpec().GetDocument().GetName()
+			errs = append(errs, &ResourceError{Kind: "workflow", Index: i, Name: name, Err: errors.Join(violations...)})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateManifestFields checks a manifest's own fields, leaving the resources
+// in resourceField to be checked one by one.
+func validateManifestFields(m proto.Message, resourceField protoreflect.Name) []error {
+	msg := m.ProtoReflect()
+	var errs []error
+	fields := msg.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if fd.Name() == resourceField {
+			errs = append(errs, validateField(msg, fd, string(fd.Name()), false)...)
+			continue
+		}
+		errs = append(errs, validateField(msg, fd, string(fd.Name()), true)...)
+	}
+	return errs
+}
+
+// validateMessage checks every field of msg, recursively.
+func validateMessage(msg protoreflect.Message, prefix string) []error {
+	var errs []error
+	fields := msg.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		errs = append(errs, validateField(msg, fd, joinPath(prefix, string(fd.Name())), true)...)
+	}
+	return errs
+}
+
+// validateField checks the rules declared on fd and, when recurse is set, the
+// messages it holds.
+func validateField(msg protoreflect.Message, fd protoreflect.FieldDescriptor, path string, recurse bool) []error {
+	rules, _ := proto.GetExtension(fd.Options(), validate.E_Field).(*validate.FieldRules)
+	if cliPopulatedFields[fd.FullName()] {
+		rules = nil
+	}
+	set := msg.Has(fd)
+
+	var errs []error
+	if rules != nil && rules.GetIgnore() != validate.Ignore_IGNORE_ALWAYS {
+		if rules.GetRequired() && !set {
+			return []error{&FieldViolation{Path: path, Rule: "required", Message: "value is required"}}
+		}
+		// Rules only apply to fields that are set, or that have no presence
+		checkValue := set || (!fd.HasPresence() && rules.GetIgnore() != validate.Ignore_IGNORE_IF_ZERO_VALUE)
+		if checkValue {
+			errs = append(errs, checkRules(rules, fd, msg.Get(fd), path)...)
+		}
+	}
+
+	if !recurse || !set || fd.Message() == nil {
+		return errs
+	}
+	switch {
+	case fd.IsList():
+		list := msg.Get(fd).List()
+		for i := 0; i < list.Len(); i++ {
+			errs = append(errs, validateMessage(list.Get(i).Message(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case fd.IsMap():
+		if fd.MapValue().Message() != nil {
+			msg.Get(fd).Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+				errs = append(errs, validateMessage(v.Message(), fmt.Sprintf("%s[%q]", path, k.String()))...)
+				return true
+			})
+		}
+	default:
+		errs = append(errs, validateMessage(msg.Get(fd).Message(), path)...)
+	}
+	return errs
+}
+
+// checkRules applies the type-specific rules to a field value.
+func checkRules(rules *validate.FieldRules, fd protoreflect.FieldDescriptor, value protoreflect.Value, path string) []error {
+	violation := func(rule, format string, args ...any) []error {
+		return []error{&FieldViolation{Path: path, Rule: rule, Message: fmt.Sprintf(format, args...)}}
+	}
+
+	switch {
+	case rules.HasRepeated() && fd.IsList():
+		r, n := rules.GetRepeated(), uint64(value.List().Len())
+		if r.HasMinItems() && n < r.GetMinItems() {
+			return violation("repeated.min_items", "must contain at least %d item(s)", r.GetMinItems())
+		}
+		if r.HasMaxItems() && n > r.GetMaxItems() {
+			return violation("repeated.max_items", "must contain at most %d item(s)", r.GetMaxItems())
+		}
+
+	case rules.HasString() && fd.Kind() == protoreflect.StringKind && !fd.IsList():
+		r, s := rules.GetString(), value.String()
+		n := uint64(utf8.RuneCountInString(s))
+		switch {
+		case r.HasConst() && s != r.GetConst():
+			return violation("string.const", "must equal %q", r.GetConst())
+		case r.HasMinLen() && n < r.GetMinLen():
+			return violation("string.min_len", "must be at least %d characters", r.GetMinLen())
+		case r.HasMaxLen() && n > r.GetMaxLen():
+			return violation("string.max_len", "must be at most %d characters", r.GetMaxLen())
+		case r.HasPattern() && !compilePattern(r.GetPattern()).MatchString(s):
+			return violation("string.pattern", "does not match regex pattern %q", r.GetPattern())
+		case len(r.GetIn()) > 0 && !contains(r.GetIn(), s):
+			return violation("string.in", "must be one of %q", r.GetIn())
+		}
+
+	case rules.HasInt32() && fd.Kind() == protoreflect.Int32Kind && !fd.IsList():
+		return checkInt(rules.GetInt32(), "int32", value.Int(), violation)
+
+	case rules.HasInt64() && fd.Kind() == protoreflect.Int64Kind && !fd.IsList():
+		return checkInt(rules.GetInt64(), "int64", value.Int(), violation)
+
+	case rules.HasEnum() && fd.Kind() == protoreflect.EnumKind && !fd.IsList():
+		if rules.GetEnum().GetDefinedOnly() && fd.Enum().Values().ByNumber(value.Enum()) == nil {
+			return violation("enum.defined_only", "value must be one of the defined enum values")
+		}
+	}
+	return nil
+}
+
+// intRules is implemented by Int32Rules and Int64Rules.
+type intRules[T int32 | int64] interface {
+	HasConst() bool
+	GetConst() T
+	HasGt() bool
+	GetGt() T
+	HasGte() bool
+	GetGte() T
+	HasLt() bool
+	GetLt() T
+	HasLte() bool
+	GetLte() T
+}
+
+// checkInt applies integer comparison rules.
+func checkInt[T int32 | int64](r intRules[T], kind string, v int64, violation func(string, string, ...any) []error) []error {
+	switch {
+	case r.HasConst() && v != int64(r.GetConst()):
+		return violation(kind+".const", "must equal %d", r.GetConst())
+	case r.HasGt() && v <= int64(r.GetGt()):
+		return violation(kind+".gt", "must be greater than %d", r.GetGt())
+	case r.HasGte() && v < int64(r.GetGte()):
+		return violation(kind+".gte", "must be greater than or equal to %d", r.GetGte())
+	case r.HasLt() && v >= int64(r.GetLt()):
+		return violation(kind+".lt", "must be less than %d", r.GetLt())
+	case r.HasLte() && v > int64(r.GetLte()):
+		return violation(kind+".lte", "must be less than or equal to %d", r.GetLte())
+	}
+	return nil
+}
+
+var (
+	patternsMu sync.Mutex
+	patterns   = make(map[string]*regexp.Regexp)
+)
+
+// compilePattern returns the compiled string.pattern rule. The patterns come
+// from the generated descriptors, which buf has already checked are valid RE2.
+func compilePattern(pattern string) *regexp.Regexp {
+	patternsMu.Lock()
+	defer patternsMu.Unlock()
+	re, ok := patterns[pattern]
+	if !ok {
+		re = regexp.MustCompile(pattern)
+		patterns[pattern] = re
+	}
+	return re
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func joinPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
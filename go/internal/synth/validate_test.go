@@ -0,0 +1,54 @@
+package synth
+
+import (
+	"errors"
+	"testing"
+
+	agentv1 "buf.build/gen/go/leftbin/stigmer/protocolbuffers/go/ai/stigmer/agentic/agent/v1"
+	sdk "buf.build/gen/go/leftbin/stigmer/protocolbuffers/go/ai/stigmer/commons/sdk"
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestValidateWorkflowManifest verifies synthesized manifests pass and broken fields are reported by path.
+func TestValidateWorkflowManifest(t *testing.T) {
+	wf := newTestWorkflow(t, "valid")
+	wf.AddTask(workflow.SetTask("init", workflow.SetVar("x", "1")))
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err)
+	require.NoError(t, ValidateWorkflowManifest(manifest))
+
+	manifest.Workflows[0].Spec.Document.Dsl = "2.0.0"
+	manifest.Workflows[0].Spec.Tasks[0].Name = ""
+	manifest.SdkMetadata.Language = "Go"
+
+	err = ValidateWorkflowManifest(manifest)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrConstraintViolation)
+	assert.Contains(t, err.Error(), "sdk_metadata.language: does not match regex pattern")
+
+	var resourceErr *ResourceError
+	require.True(t, errors.As(err, &resourceErr))
+	assert.Equal(t, "workflow", resourceErr.Kind)
+	assert.Equal(t, "valid", resourceErr.Name)
+	assert.Contains(t, resourceErr.Error(), "spec.document.dsl: does not match regex pattern")
+	assert.Contains(t, resourceErr.Error(), "spec.tasks[0].name: value is required (required)")
+}
+
+// TestValidateAgentManifest verifies manifest-level and per-agent rules.
+func TestValidateAgentManifest(t *testing.T) {
+	err := ValidateAgentManifest(&agentv1.AgentManifest{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "sdk_metadata: value is required (required)")
+	assert.Contains(t, err.Error(), "agents: must contain at least 1 item(s) (repeated.min_items)")
+
+	manifest := &agentv1.AgentManifest{
+		SdkMetadata: &sdk.SdkMetadata{Language: "go", Version: "0.1.0", GeneratedAt: -1},
+		Agents:      []*agentv1.AgentBlueprint{{Name: "ab", Instructions: "Review code and suggest improvements"}},
+	}
+	err = ValidateAgentManifest(manifest)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "sdk_metadata.generated_at: must be greater than or equal to 0 (int64.gte)")
+	assert.Contains(t, err.Error(), "agent[0] ab: name: must be at least 3 characters (string.min_len)")
+}
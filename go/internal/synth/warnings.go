@@ -0,0 +1,98 @@
+package synth
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/leftbin/stigmer-sdk/go/agent"
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+// ConversionWarning reports data the manifest cannot represent, which
+// conversion dropped or replaced with a default.
+type ConversionWarning struct {
+	Kind    string `json:"kind"`           // "agent" or "workflow"
+	Name    string `json:"name"`           // Agent or workflow name
+	Task    string `json:"task,omitempty"` // Task the warning applies to, if any
+	Field   string `json:"field"`          // Dropped or defaulted field, e.g. "catch[1]"
+	Message string `json:"message"`
+}
+
+func (w ConversionWarning) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s: ", w.Kind, w.Name)
+	if w.Task != "" {
+		fmt.Fprintf(&b, "task %s: ", w.Task)
+	}
+	fmt.Fprintf(&b, "%s: %s", w.Field, w.Message)
+	return b.String()
+}
+
+// AgentWarnings returns the warnings of converting a, e.g. undeclared MCP
+// server placeholders that are declared as required secrets.
+func AgentWarnings(a *agent.Agent) []ConversionWarning {
+	envVars, err := dedupeEnvVars(a.EnvironmentVariables)
+	if err != nil {
+		return nil // Reported by ToManifest
+	}
+	_, messages, err := declarePlaceholders(a.MCPServers, envVars)
+	if err != nil {
+		return nil
+	}
+	var warnings []ConversionWarning
+	for _, msg := range messages {
+		warnings = append(warnings, ConversionWarning{Kind: "agent", Name: a.Name, Field: "environment_variables", Message: msg})
+	}
+	return warnings
+}
+
+// WorkflowWarnings returns the warnings of converting wf: catch blocks after
+// the first, catch error filters, SWITCH defaults shadowed by a case without a
+// condition, and ExportFields selections, none of which the manifest can hold.
+func WorkflowWarnings(wf *workflow.Workflow) []ConversionWarning {
+	var warnings []ConversionWarning
+	warn := func(task, field, format string, args ...any) {
+		warnings = append(warnings, ConversionWarning{
+			Kind:    "workflow",
+			Name:    wf.Document.Name,
+			Task:    task,
+			Field:   field,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	for task := range wf.AllTasks() {
+		switch cfg := task.Config.(type) {
+		case *workflow.TryTaskConfig:
+			for i, c := range cfg.Catch {
+				if i > 0 {
+					warn(task.Name, fmt.Sprintf("catch[%d]", i),
+						"only the first catch block is kept; this block and its %d task(s) are dropped", len(c.Tasks))
+					continue
+				}
+				if len(c.Errors) > 0 && !(len(c.Errors) == 1 && c.Errors[0] == workflow.ErrorTypeAny) {
+					warn(task.Name, "catch[0].errors",
+						"error filter %q is dropped; the catch block handles every error", c.Errors)
+				}
+			}
+
+		case *workflow.SwitchTaskConfig:
+			if cfg.DefaultTask == "" {
+				break
+			}
+			for _, c := range cfg.Cases {
+				if c.Condition == "" {
+					warn(task.Name, "default",
+						"default task %q is dropped; the case without a condition is the default", cfg.DefaultTask)
+					break
+				}
+			}
+		}
+
+		if len(task.ExportedFields) > 0 && task.ExportAs == "${.}" {
+			warn(task.Name, "export",
+				"field selection %q is dropped; the whole output is exported", task.ExportedFields)
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,54 @@
+package synth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+func TestWorkflowWarnings(t *testing.T) {
+	wf := newTestWorkflow(t, "lossy")
+	wf.AddTask(workflow.TryTask("guarded",
+		workflow.WithTry(workflow.SetTask("work", workflow.SetVar("x", "1"))),
+		workflow.WithCatchTyped(workflow.CatchCustom("Timeout"), "err", workflow.SetTask("onTimeout", workflow.SetVar("y", "1"))),
+		workflow.WithCatchTyped(workflow.CatchAny(), "err", workflow.SetTask("onError", workflow.SetVar("z", "1"))),
+	))
+	wf.AddTask(workflow.SwitchTask("route",
+		workflow.WithCase("${ .x == 1 }", "guarded"),
+		workflow.WithCase("", "guarded"),
+		workflow.WithDefault("fetch"),
+	))
+	wf.AddTask(workflow.SetTask("fetch", workflow.SetVar("a", "1")).ExportFields("a", "b"))
+
+	warnings := WorkflowWarnings(wf)
+	require.Len(t, warnings, 4)
+
+	var fields []string
+	for _, w := range warnings {
+		assert.Equal(t, "workflow", w.Kind)
+		assert.Equal(t, "lossy", w.Name)
+		fields = append(fields, w.Task+" "+w.Field)
+	}
+	assert.Equal(t, []string{
+		"guarded catch[0].errors",
+		"guarded catch[1]",
+		"route default",
+		"fetch export",
+	}, fields)
+	assert.Equal(t, `workflow lossy: task guarded: catch[1]: only the first catch block is kept; this block and its 1 task(s) are dropped`,
+		warnings[1].String())
+}
+
+func TestWorkflowWarnings_None(t *testing.T) {
+	wf := newTestWorkflow(t, "lossless")
+	wf.AddTask(workflow.TryTask("guarded",
+		workflow.WithTry(workflow.SetTask("work", workflow.SetVar("x", "1"))),
+		workflow.WithCatchTyped(workflow.CatchAny(), "err", workflow.SetTask("onError", workflow.SetVar("z", "1"))),
+	))
+	wf.AddTask(workflow.SetTask("fetch", workflow.SetVar("a", "1")).ExportAll())
+
+	assert.Empty(t, WorkflowWarnings(wf))
+}
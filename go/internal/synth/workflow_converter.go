@@ -1,6 +1,8 @@
 package synth
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -70,27 +72,132 @@ func ToWorkflowManifestWithContext(contextVars map[string]interface{}, workflowI
 		Workflows:   []*workflowv1.Workflow{},
 	}
 
-	// Convert each workflow
+	// Type assert all workflows up front so cross-workflow references can be resolved
+	workflows := make([]*workflow.Workflow, 0, len(workflowInterfaces))
 	for wfIdx, workflowInterface := range workflowInterfaces {
-		// Type assert to *workflow.Workflow
 		wf, ok := workflowInterface.(*workflow.Workflow)
 		if !ok {
 			return nil, fmt.Errorf("workflow[%d]: invalid type %T, expected *workflow.Workflow", wfIdx, workflowInterface)
 		}
+		workflows = append(workflows, wf)
+	}
+
+	// Validate that typed sub-workflow references point at registered workflows
+	var errs []error
+	if err := validateWorkflowRefs(workflows); err != nil {
+		errs = append(errs, err)
+	}
 
+	// Convert each workflow, collecting the errors of all workflows
+	for wfIdx, wf := range workflows {
 		// Convert to proto with context variable injection
 		protoWorkflow, err := workflowToProtoWithContext(wf, contextVars)
 		if err != nil {
-			return nil, fmt.Errorf("workflow[%d] %s: %w", wfIdx, wf.Document.Name, err)
+			errs = append(errs, &ResourceError{Kind: "workflow", Index: wfIdx, Name: wf.Document.Name, Err: err})
+			continue
 		}
 
 		// Add to manifest
 		manifest.Workflows = append(manifest.Workflows, protoWorkflow)
 	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
 
 	return manifest, nil
 }
 
+// validateWorkflowRefs verifies that every RUN task created with WithWorkflowRef
+// references a workflow that is part of the synthesized set.
+//
+// RUN tasks that only specify a workflow name (WithWorkflow) are not checked,
+// since they may reference workflows deployed outside this program.
+func validateWorkflowRefs(workflows []*workflow.Workflow) error {
+	registered := make(map[string]bool, len(workflows))
+	for _, wf := range workflows {
+		registered[workflowRefKey(wf.Document.Namespace, wf.Document.Name, wf.Document.Version)] = true
+	}
+
+	for wfIdx, wf := range workflows {
+		for task := range wf.AllTasks() {
+			cfg, ok := task.Config.(*workflow.RunTaskConfig)
+			if !ok || cfg.WorkflowNamespace == "" {
+				continue
+			}
+			key := workflowRefKey(cfg.WorkflowNamespace, cfg.WorkflowName, cfg.WorkflowVersion)
+			if !registered[key] {
+				return fmt.Errorf("workflow[%d] %s: RUN task %s references workflow %s which is not registered in the context",
+					wfIdx, wf.Document.Name, task.Name, key)
+			}
+		}
+	}
+	return nil
+}
+
+// oauth2ToMap converts OAuth2 client credentials settings to the endpoint authentication block.
+func oauth2ToMap(auth *workflow.OAuth2Config) map[string]interface{} {
+	oauth2 := map[string]interface{}{
+		"grant":         "client_credentials",
+		"client_id":     auth.ClientID,
+		"client_secret": auth.ClientSecret,
+		"token_url":     auth.TokenURL,
+	}
+	if len(auth.Scopes) > 0 {
+		scopes := make([]interface{}, len(auth.Scopes))
+		for i, scope := range auth.Scopes {
+			scopes[i] = scope
+		}
+		oauth2["scopes"] = scopes
+	}
+	return map[string]interface{}{
+		"oauth2": oauth2,
+	}
+}
+
+// tlsToMap converts TLS settings to a task config map.
+func tlsToMap(tls *workflow.TLSConfig) map[string]interface{} {
+	result := map[string]interface{}{
+		"insecure_skip_verify": tls.InsecureSkipVerify,
+	}
+	if tls.CACert != "" {
+		result["ca_cert"] = tls.CACert
+	}
+	if tls.ServerName != "" {
+		result["server_name"] = tls.ServerName
+	}
+	return result
+}
+
+// retryPolicyToMap converts an activity retry policy into its config map form.
+func retryPolicyToMap(policy *workflow.RetryPolicy) map[string]interface{} {
+	result := map[string]interface{}{}
+	if policy.InitialInterval != "" {
+		result["initial_interval"] = policy.InitialInterval
+	}
+	if policy.BackoffCoefficient != 0 {
+		result["backoff_coefficient"] = policy.BackoffCoefficient
+	}
+	if policy.MaximumInterval != "" {
+		result["maximum_interval"] = policy.MaximumInterval
+	}
+	if policy.MaximumAttempts != 0 {
+		result["maximum_attempts"] = policy.MaximumAttempts
+	}
+	if len(policy.NonRetryableErrorTypes) > 0 {
+		errorTypes := make([]interface{}, len(policy.NonRetryableErrorTypes))
+		for i, t := range policy.NonRetryableErrorTypes {
+			errorTypes[i] = t
+		}
+		result["non_retryable_error_types"] = errorTypes
+	}
+	return result
+}
+
+// workflowRefKey builds the lookup key for a workflow reference (namespace/name@version).
+func workflowRefKey(namespace, name, version string) string {
+	return fmt.Sprintf("%s/%s@%s", namespace, name, version)
+}
+
 // workflowToProto converts a workflow.Workflow to a workflowv1.Workflow proto.
 // This version does not inject context variables.
 func workflowToProto(wf *workflow.Workflow) (*workflowv1.Workflow, error) {
@@ -106,8 +213,12 @@ func workflowToProtoWithContext(wf *workflow.Workflow, contextVars map[string]in
 		Kind:       "Workflow",
 	}
 
-	// Convert metadata (placeholder - would need actual metadata proto structure)
-	// For now, we'll focus on the spec
+	// Convert metadata (only workflow-level settings without a dedicated spec field for now)
+	metadata, err := workflowMetadataToProto(wf)
+	if err != nil {
+		return nil, fmt.Errorf("converting metadata: %w", err)
+	}
+	protoWorkflow.Metadata = metadata
 
 	// Convert spec with context variable injection
 	spec, err := workflowSpecToProtoWithContext(wf, contextVars)
@@ -119,6 +230,125 @@ func workflowToProtoWithContext(wf *workflow.Workflow, contextVars map[string]in
 	return protoWorkflow, nil
 }
 
+// workflowMetadataToProto builds the resource metadata for a workflow.
+// Returns nil when the workflow has nothing to annotate.
+func workflowMetadataToProto(wf *workflow.Workflow) (*apiresource.ApiResourceMetadata, error) {
+	annotations := make(map[string]string)
+
+	if len(wf.Triggers) > 0 {
+		triggers, err := triggersToJSON(wf.Triggers)
+		if err != nil {
+			return nil, fmt.Errorf("converting triggers: %w", err)
+		}
+		annotations[workflow.AnnotationTriggers] = triggers
+	}
+
+	if wf.Timeout != "" {
+		annotations[workflow.AnnotationTimeout] = wf.Timeout
+	}
+
+	if wf.Disabled {
+		annotations[workflow.AnnotationDisabled] = "true"
+		if wf.DisabledReason != "" {
+			annotations[workflow.AnnotationDisabledReason] = wf.DisabledReason
+		}
+	}
+
+	if wf.ErrorPolicy != nil {
+		data, err := json.Marshal(wf.ErrorPolicy)
+		if err != nil {
+			return nil, fmt.Errorf("converting error policy: %w", err)
+		}
+		annotations[workflow.AnnotationErrorPolicy] = string(data)
+	}
+
+	if wf.Rollout != nil {
+		data, err := json.Marshal(map[string]interface{}{
+			"version": wf.Document.Version,
+			"percent": wf.Rollout.Percent,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("converting rollout: %w", err)
+		}
+		annotations[workflow.AnnotationRollout] = string(data)
+	}
+
+	if !wf.Resources.IsZero() {
+		data, err := json.Marshal(wf.Resources)
+		if err != nil {
+			return nil, fmt.Errorf("converting resources: %w", err)
+		}
+		annotations[workflow.AnnotationResources] = string(data)
+	}
+
+	deadlines := make(map[string]string)
+	idempotencyKeys := make(map[string]string)
+	for task := range wf.AllTasks() {
+		if task.Deadline != "" {
+			deadlines[task.Name] = task.Deadline
+		}
+		if task.IdempotencyKey != "" {
+			idempotencyKeys[task.Name] = task.IdempotencyKey
+		}
+	}
+	if len(deadlines) > 0 {
+		data, err := json.Marshal(deadlines)
+		if err != nil {
+			return nil, fmt.Errorf("converting task deadlines: %w", err)
+		}
+		annotations[workflow.AnnotationTaskDeadlines] = string(data)
+	}
+	if len(idempotencyKeys) > 0 {
+		data, err := json.Marshal(idempotencyKeys)
+		if err != nil {
+			return nil, fmt.Errorf("converting idempotency keys: %w", err)
+		}
+		annotations[workflow.AnnotationIdempotencyKeys] = string(data)
+	}
+
+	if len(annotations) == 0 {
+		return nil, nil
+	}
+
+	return &apiresource.ApiResourceMetadata{
+		Name:        wf.Document.Name,
+		Org:         wf.Org,
+		Annotations: annotations,
+	}, nil
+}
+
+// triggersToJSON encodes workflow triggers as a JSON array.
+//
+// Each trigger becomes an object with a "kind" and the kind-specific field:
+//   - {"kind": "CRON", "cron": "0 2 * * *"}
+//   - {"kind": "INTERVAL", "interval": "15m"}
+//   - {"kind": "EVENT", "event": "order.created"}
+func triggersToJSON(triggers []workflow.Trigger) (string, error) {
+	result := make([]map[string]interface{}, 0, len(triggers))
+	for i, t := range triggers {
+		triggerMap := map[string]interface{}{
+			"kind": string(t.Kind),
+		}
+		switch t.Kind {
+		case workflow.TriggerKindCron:
+			triggerMap["cron"] = t.Cron
+		case workflow.TriggerKindInterval:
+			triggerMap["interval"] = t.Interval
+		case workflow.TriggerKindEvent:
+			triggerMap["event"] = t.Event
+		default:
+			return "", fmt.Errorf("trigger[%d]: unknown trigger kind: %s", i, t.Kind)
+		}
+		result = append(result, triggerMap)
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
 // workflowSpecToProto converts workflow spec to proto.
 // This version does not inject context variables.
 func workflowSpecToProto(wf *workflow.Workflow) (*workflowv1.WorkflowSpec, error) {
@@ -155,8 +385,49 @@ func workflowSpecToProtoWithContext(wf *workflow.Workflow, contextVars map[strin
 	// REMOVED: No longer inject __stigmer_init_context SET task
 	// Variables are now resolved at compile-time via interpolation
 
+	// Fill in workflow-level defaults (timeouts, retries, headers) on copies of
+	// the HTTP and gRPC tasks, leaving the caller's workflow untouched
+	wf, err := workflow.ApplyDefaults(wf)
+	if err != nil {
+		return nil, err
+	}
+
+	// Run every task after the tasks it depends on
+	tasks, err := workflow.OrderTasks(wf.Tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reject dangling Then/switch targets, unreachable tasks and endless loops
+	if err := workflow.ValidateFlow(tasks); err != nil {
+		return nil, err
+	}
+
+	// Reject features the workflow's DSL version does not support
+	if err := workflow.ValidateDSLVersion(wf); err != nil {
+		return nil, err
+	}
+
+	// Wrap tasks that follow a compensated task in TRY blocks
+	tasks, err = workflow.LowerCompensations(tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	// Wrap the tasks in a top-level TRY so workflow finalizers always run
+	tasks, err = workflow.LowerFinalizers(tasks, wf.Finalizers)
+	if err != nil {
+		return nil, err
+	}
+
+	// Substitute shared expressions (UseExpression) with their definitions
+	tasks, err = workflow.ResolveExpressions(tasks)
+	if err != nil {
+		return nil, err
+	}
+
 	// Convert user-defined tasks with variable interpolation
-	for i, task := range wf.Tasks {
+	for i, task := range tasks {
 		protoTask, err := taskToProtoWithInterpolation(task, contextVars)
 		if err != nil {
 			return nil, fmt.Errorf("converting task[%d] %s: %w", i, task.Name, err)
@@ -205,6 +476,18 @@ func taskToProto(task *workflow.Task) (*workflowv1.WorkflowTask, error) {
 //
 // This eliminates the need for runtime variable resolution via a SET task.
 func taskToProtoWithInterpolation(task *workflow.Task, contextVars map[string]interface{}) (*workflowv1.WorkflowTask, error) {
+	// Lower custom task kinds to their built-in equivalent
+	task, err := workflow.LowerTask(task)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reject kinds the manifest cannot represent instead of emitting UNSPECIFIED
+	kind := taskKindToProtoKind(task.Kind)
+	if kind == apiresource.WorkflowTaskKind_WORKFLOW_TASK_KIND_UNSPECIFIED {
+		return nil, fmt.Errorf("task %s: task kind %s is not supported by the workflow manifest", task.Name, task.Kind)
+	}
+
 	// Convert task config to google.protobuf.Struct
 	taskConfig, err := taskConfigToStruct(task)
 	if err != nil {
@@ -229,9 +512,21 @@ func taskToProtoWithInterpolation(task *workflow.Task, contextVars map[string]in
 		}
 	}
 
+	// Apply registered task mutators (e.g., organization policies)
+	if hasTaskMutators(task.Kind) {
+		configMap := taskConfig.AsMap()
+		if err := applyTaskMutators(task, configMap); err != nil {
+			return nil, err
+		}
+		taskConfig, err = structpb.NewStruct(configMap)
+		if err != nil {
+			return nil, fmt.Errorf("converting mutated config to struct: %w", err)
+		}
+	}
+
 	protoTask := &workflowv1.WorkflowTask{
 		Name:       task.Name,
-		Kind:       taskKindToProtoKind(task.Kind),
+		Kind:       kind,
 		TaskConfig: taskConfig,
 	}
 
@@ -328,9 +623,15 @@ func convertNestedTasksToMaps(tasks []workflow.Task) ([]interface{}, error) {
 	}
 	
 	result := make([]interface{}, len(tasks))
-	for i, task := range tasks {
+	for i := range tasks {
+		// Lower custom task kinds to their built-in equivalent
+		task, err := workflow.LowerTask(&tasks[i])
+		if err != nil {
+			return nil, fmt.Errorf("converting nested task[%d] %s: %w", i, tasks[i].Name, err)
+		}
+
 		// Convert task config to Struct
-		taskConfig, err := taskConfigToStruct(&task)
+		taskConfig, err := taskConfigToStruct(task)
 		if err != nil {
 			return nil, fmt.Errorf("converting nested task[%d] %s config: %w", i, task.Name, err)
 		}
@@ -338,6 +639,11 @@ func convertNestedTasksToMaps(tasks []workflow.Task) ([]interface{}, error) {
 		// Convert the Struct back to a map to avoid nested Struct issues
 		// structpb.NewStruct() cannot handle *structpb.Struct as a value
 		taskConfigMap := taskConfig.AsMap()
+
+		// Apply registered task mutators (e.g., organization policies)
+		if err := applyTaskMutators(task, taskConfigMap); err != nil {
+			return nil, err
+		}
 		
 		// Build task map with all required proto fields
 		taskMap := map[string]interface{}{
@@ -434,22 +740,67 @@ func taskConfigToStruct(task *workflow.Task) (*structpb.Struct, error) {
 
 	case workflow.TaskKindHttpCall:
 		cfg := task.Config.(*workflow.HttpCallTaskConfig)
+		body, err := cfg.ResolvedBody()
+		if err != nil {
+			return nil, err
+		}
 		configMap = map[string]interface{}{
 			"method": cfg.Method,
 			"endpoint": map[string]interface{}{
-				"uri": cfg.URI,
+				"uri": cfg.RequestURI(),
 			},
 			"headers":         stringMapToInterface(cfg.Headers),
-			"body":            convertToProtobufCompatible(cfg.Body), // FIX: Handle TaskFieldRef and nested structures
+			"body":            convertToProtobufCompatible(body), // FIX: Handle TaskFieldRef and nested structures
 			"timeout_seconds": cfg.TimeoutSeconds,
 		}
+		if cfg.BodyEncoding != "" && cfg.BodyEncoding != workflow.BodyEncodingJSON {
+			configMap["body_encoding"] = string(cfg.BodyEncoding)
+		}
+		if cfg.OAuth2 != nil {
+			endpoint := configMap["endpoint"].(map[string]interface{})
+			endpoint["authentication"] = oauth2ToMap(cfg.OAuth2)
+		}
+		if cfg.TLS != nil {
+			configMap["tls"] = tlsToMap(cfg.TLS)
+		}
+		if cfg.Proxy != "" {
+			configMap["proxy"] = cfg.Proxy
+		}
+		if cfg.FollowRedirects != nil {
+			configMap["follow_redirects"] = *cfg.FollowRedirects
+		}
+		if cfg.RetryPolicy != nil {
+			configMap["retry_policy"] = retryPolicyToMap(cfg.RetryPolicy)
+		}
 
 	case workflow.TaskKindGrpcCall:
 		cfg := task.Config.(*workflow.GrpcCallTaskConfig)
+		if err := cfg.ValidateBody(); err != nil {
+			return nil, err
+		}
+		body, err := cfg.ResolvedBody()
+		if err != nil {
+			return nil, err
+		}
 		configMap = map[string]interface{}{
 			"service": cfg.Service,
 			"method":  cfg.Method,
-			"body":    convertToProtobufCompatible(cfg.Body), // FIX: Handle TaskFieldRef and nested structures
+			"body":    convertToProtobufCompatible(body), // FIX: Handle TaskFieldRef and nested structures
+		}
+		if cfg.Endpoint != "" {
+			configMap["endpoint"] = cfg.Endpoint
+		}
+		if len(cfg.Metadata) > 0 {
+			configMap["metadata"] = stringMapToInterface(cfg.Metadata)
+		}
+		if cfg.TLS != nil {
+			configMap["tls"] = tlsToMap(cfg.TLS)
+		}
+		if cfg.Deadline != "" {
+			configMap["deadline"] = cfg.Deadline
+		}
+		if cfg.RetryPolicy != nil {
+			configMap["retry_policy"] = retryPolicyToMap(cfg.RetryPolicy)
 		}
 
 	case workflow.TaskKindSwitch:
@@ -527,9 +878,8 @@ func taskConfigToStruct(task *workflow.Task) (*structpb.Struct, error) {
 		
 		configMap = map[string]interface{}{
 			"branches": mapSliceToInterfaceSlice(branches),
-			// Default "compete" to false (all branches must complete)
-			// TODO: Add "Compete" field to ForkTaskConfig Go struct for race mode support
-			"compete": false,
+			// When false (default), all branches must complete
+			"compete": cfg.Compete,
 		}
 
 	case workflow.TaskKindTry:
@@ -573,11 +923,25 @@ func taskConfigToStruct(task *workflow.Task) (*structpb.Struct, error) {
 		configMap = map[string]interface{}{
 			"event": cfg.Event,
 		}
+		if cfg.Filter != "" {
+			configMap["filter"] = cfg.Filter
+		}
+		if len(cfg.Correlation) > 0 {
+			configMap["correlation"] = stringMapToInterface(cfg.Correlation)
+		}
+		if cfg.Timeout != "" {
+			configMap["timeout"] = cfg.Timeout
+			configMap["timeout_then"] = cfg.TimeoutThen
+		}
 
 	case workflow.TaskKindWait:
 		cfg := task.Config.(*workflow.WaitTaskConfig)
-		configMap = map[string]interface{}{
-			"duration": cfg.Duration,
+		configMap = map[string]interface{}{}
+		if cfg.Duration != "" {
+			configMap["duration"] = cfg.Duration
+		}
+		if cfg.Until != "" {
+			configMap["until"] = cfg.Until
 		}
 
 	case workflow.TaskKindCallActivity:
@@ -586,6 +950,21 @@ func taskConfigToStruct(task *workflow.Task) (*structpb.Struct, error) {
 			"activity": cfg.Activity,
 			"input":    convertToProtobufCompatible(cfg.Input), // FIX: Handle TaskFieldRef
 		}
+		if cfg.TaskQueue != "" {
+			configMap["task_queue"] = cfg.TaskQueue
+		}
+		if cfg.StartToCloseTimeout != "" {
+			configMap["start_to_close_timeout"] = cfg.StartToCloseTimeout
+		}
+		if cfg.ScheduleToCloseTimeout != "" {
+			configMap["schedule_to_close_timeout"] = cfg.ScheduleToCloseTimeout
+		}
+		if cfg.HeartbeatTimeout != "" {
+			configMap["heartbeat_timeout"] = cfg.HeartbeatTimeout
+		}
+		if cfg.RetryPolicy != nil {
+			configMap["retry_policy"] = retryPolicyToMap(cfg.RetryPolicy)
+		}
 
 	case workflow.TaskKindRaise:
 		cfg := task.Config.(*workflow.RaiseTaskConfig)
@@ -602,6 +981,14 @@ func taskConfigToStruct(task *workflow.Task) (*structpb.Struct, error) {
 			"input":    convertToProtobufCompatible(cfg.Input), // FIX: Handle TaskFieldRef
 		}
 
+		// Add namespace/version when the sub-workflow was referenced by object
+		if cfg.WorkflowNamespace != "" {
+			configMap["namespace"] = cfg.WorkflowNamespace
+		}
+		if cfg.WorkflowVersion != "" {
+			configMap["version"] = cfg.WorkflowVersion
+		}
+
 	case workflow.TaskKindAgentCall:
 		cfg := task.Config.(*workflow.AgentCallTaskConfig)
 		configMap = map[string]interface{}{
@@ -609,6 +996,11 @@ func taskConfigToStruct(task *workflow.Task) (*structpb.Struct, error) {
 			"message": cfg.Message,
 			"env":     stringMapToInterface(cfg.Env),
 		}
+
+		// Add structured input if specified
+		if len(cfg.Input) > 0 {
+			configMap["input"] = convertToProtobufCompatible(cfg.Input) // FIX: Handle TaskFieldRef
+		}
 		
 		// Add scope if specified (not empty)
 		if scope := cfg.Agent.Scope(); scope != "" {
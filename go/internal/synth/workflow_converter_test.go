@@ -0,0 +1,761 @@
+package synth
+
+import (
+	"testing"
+
+	apiresource "buf.build/gen/go/leftbin/stigmer/protocolbuffers/go/ai/stigmer/commons/apiresource"
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// mockContext is a minimal workflow.Context used to construct workflows in tests.
+type mockContext struct {
+	workflows []*workflow.Workflow
+}
+
+func (m *mockContext) RegisterWorkflow(wf *workflow.Workflow) {
+	m.workflows = append(m.workflows, wf)
+}
+
+// newTestWorkflow creates a workflow registered with a mock context.
+func newTestWorkflow(t *testing.T, name string, opts ...workflow.Option) *workflow.Workflow {
+	t.Helper()
+	allOpts := append([]workflow.Option{
+		workflow.WithNamespace("test"),
+		workflow.WithName(name),
+	}, opts...)
+	wf, err := workflow.New(&mockContext{}, allOpts...)
+	require.NoError(t, err, "should create workflow")
+	return wf
+}
+
+// TestRunTaskWithWorkflowRef verifies typed sub-workflow references are synthesized.
+func TestRunTaskWithWorkflowRef(t *testing.T) {
+	sub := newTestWorkflow(t, "sub-workflow", workflow.WithVersion("1.0.0"))
+	sub.AddTask(workflow.SetTask("init", workflow.SetVar("x", "1")))
+
+	parent := newTestWorkflow(t, "parent")
+	parent.AddTask(workflow.RunTask("runSub", workflow.WithWorkflowRef(sub)))
+
+	manifest, err := ToWorkflowManifest(parent, sub)
+	require.NoError(t, err, "should convert workflows")
+
+	taskConfig := manifest.Workflows[0].Spec.Tasks[0].TaskConfig
+	assert.Equal(t, "sub-workflow", taskConfig.Fields["workflow"].GetStringValue())
+	assert.Equal(t, "test", taskConfig.Fields["namespace"].GetStringValue())
+	assert.Equal(t, "1.0.0", taskConfig.Fields["version"].GetStringValue())
+}
+
+// TestRunTaskWithWorkflowRef_NotRegistered verifies synthesis fails for unknown references.
+func TestRunTaskWithWorkflowRef_NotRegistered(t *testing.T) {
+	sub := newTestWorkflow(t, "sub-workflow")
+
+	parent := newTestWorkflow(t, "parent")
+	parent.AddTask(workflow.ForTask("loop",
+		workflow.WithIn("${.items}"),
+		workflow.WithDo(workflow.RunTask("runSub", workflow.WithWorkflowRef(sub))),
+	))
+
+	_, err := ToWorkflowManifest(parent)
+	require.Error(t, err, "should reject unregistered workflow reference")
+	assert.Contains(t, err.Error(), "test/sub-workflow@0.1.0")
+}
+
+// TestRunTaskWithWorkflowName_NotChecked verifies plain name references are not validated.
+func TestRunTaskWithWorkflowName_NotChecked(t *testing.T) {
+	parent := newTestWorkflow(t, "parent")
+	parent.AddTask(workflow.RunTask("runSub", workflow.WithWorkflow("external-workflow")))
+
+	manifest, err := ToWorkflowManifest(parent)
+	require.NoError(t, err, "should convert workflow")
+
+	taskConfig := manifest.Workflows[0].Spec.Tasks[0].TaskConfig
+	assert.NotContains(t, taskConfig.Fields, "namespace")
+	assert.NotContains(t, taskConfig.Fields, "version")
+}
+
+// TestWorkflowTriggersAnnotation verifies triggers are carried in the manifest metadata.
+func TestWorkflowTriggersAnnotation(t *testing.T) {
+	wf := newTestWorkflow(t, "nightly-report",
+		workflow.WithSchedule(workflow.Cron("0 2 * * *")),
+		workflow.WithEventTrigger("order.created"),
+	)
+	wf.AddTask(workflow.SetTask("init", workflow.SetVar("x", "1")))
+
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err, "should convert workflow")
+
+	metadata := manifest.Workflows[0].Metadata
+	require.NotNil(t, metadata, "should have metadata")
+	assert.JSONEq(t,
+		`[{"kind":"CRON","cron":"0 2 * * *"},{"kind":"EVENT","event":"order.created"}]`,
+		metadata.Annotations[workflow.AnnotationTriggers],
+	)
+}
+
+// TestWorkflowDisabledAnnotation verifies disabled workflows are flagged in the metadata.
+func TestWorkflowDisabledAnnotation(t *testing.T) {
+	wf := newTestWorkflow(t, "nightly-report",
+		workflow.WithSchedule(workflow.Cron("0 2 * * *")),
+		workflow.WithDisabled("staged rollout"),
+	)
+	wf.AddTask(workflow.SetTask("init", workflow.SetVar("x", "1")))
+
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err, "should convert workflow")
+
+	annotations := manifest.Workflows[0].Metadata.Annotations
+	assert.Equal(t, "true", annotations[workflow.AnnotationDisabled])
+	assert.Equal(t, "staged rollout", annotations[workflow.AnnotationDisabledReason])
+	assert.Contains(t, annotations, workflow.AnnotationTriggers, "triggers should still be declared")
+}
+
+// TestWorkflowRolloutAnnotation verifies canary rollouts are annotated with the version they apply to.
+func TestWorkflowRolloutAnnotation(t *testing.T) {
+	wf := newTestWorkflow(t, "fulfil-order",
+		workflow.WithVersion("1.4.0"),
+		workflow.WithRollout(workflow.Percent(10)),
+	)
+	wf.AddTask(workflow.SetTask("init", workflow.SetVar("x", "1")))
+
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err, "should convert workflow")
+
+	assert.JSONEq(t,
+		`{"version":"1.4.0","percent":10}`,
+		manifest.Workflows[0].Metadata.Annotations[workflow.AnnotationRollout],
+	)
+}
+
+// TestWorkflowResourcesAnnotation verifies resource requests are annotated.
+func TestWorkflowResourcesAnnotation(t *testing.T) {
+	wf := newTestWorkflow(t, "nightly-export", workflow.WithResources("2", "4Gi"))
+	wf.AddTask(workflow.SetTask("init", workflow.SetVar("x", "1")))
+
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err, "should convert workflow")
+
+	assert.JSONEq(t,
+		`{"cpu":"2","memory":"4Gi"}`,
+		manifest.Workflows[0].Metadata.Annotations[workflow.AnnotationResources],
+	)
+}
+
+// TestWorkflowWithoutTriggers_NoMetadata verifies metadata is omitted when there is nothing to annotate.
+func TestWorkflowWithoutTriggers_NoMetadata(t *testing.T) {
+	wf := newTestWorkflow(t, "plain")
+	wf.AddTask(workflow.SetTask("init", workflow.SetVar("x", "1")))
+
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err, "should convert workflow")
+	assert.Nil(t, manifest.Workflows[0].Metadata)
+}
+
+// TestHttpCallTaskOAuth2 verifies OAuth2 settings are synthesized as an endpoint authentication block.
+func TestHttpCallTaskOAuth2(t *testing.T) {
+	wf := newTestWorkflow(t, "orders")
+	wf.AddTask(workflow.HttpCallTask("fetchOrders",
+		workflow.WithHTTPGet(),
+		workflow.WithURI("https://api.example.com/orders"),
+		workflow.WithOAuth2(
+			"my-client",
+			workflow.RuntimeSecret("ORDERS_CLIENT_SECRET"),
+			"https://auth.example.com/oauth/token",
+			"orders:read",
+		),
+	))
+
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err, "should convert workflow")
+
+	endpoint := manifest.Workflows[0].Spec.Tasks[0].TaskConfig.Fields["endpoint"].GetStructValue()
+	oauth2 := endpoint.Fields["authentication"].GetStructValue().Fields["oauth2"].GetStructValue()
+	require.NotNil(t, oauth2, "should have oauth2 block")
+	assert.Equal(t, "client_credentials", oauth2.Fields["grant"].GetStringValue())
+	assert.Equal(t, "my-client", oauth2.Fields["client_id"].GetStringValue())
+	assert.Equal(t, "${.secrets.ORDERS_CLIENT_SECRET}", oauth2.Fields["client_secret"].GetStringValue())
+	assert.Equal(t, "https://auth.example.com/oauth/token", oauth2.Fields["token_url"].GetStringValue())
+	assert.Equal(t, "orders:read", oauth2.Fields["scopes"].GetListValue().Values[0].GetStringValue())
+}
+
+// TestHttpCallTaskFormBody verifies non-JSON body encodings are synthesized.
+func TestHttpCallTaskFormBody(t *testing.T) {
+	wf := newTestWorkflow(t, "legacy")
+	wf.AddTask(workflow.HttpCallTask("login",
+		workflow.WithHTTPPost(),
+		workflow.WithURI("https://legacy.example.com/login"),
+		workflow.WithFormBody(map[string]string{"username": "admin"}),
+	))
+	wf.AddTask(workflow.HttpCallTask("fetch",
+		workflow.WithHTTPGet(),
+		workflow.WithURI("https://legacy.example.com/data"),
+	))
+
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err, "should convert workflow")
+
+	tasks := manifest.Workflows[0].Spec.Tasks
+	assert.Equal(t, "form", tasks[0].TaskConfig.Fields["body_encoding"].GetStringValue())
+	assert.Equal(t, "admin", tasks[0].TaskConfig.Fields["body"].GetStructValue().Fields["username"].GetStringValue())
+	assert.NotContains(t, tasks[1].TaskConfig.Fields, "body_encoding", "JSON bodies should not set body_encoding")
+}
+
+// TestHttpCallTaskBodyFromStruct verifies struct bodies are converted at synthesis.
+func TestHttpCallTaskBodyFromStruct(t *testing.T) {
+	type payload struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+
+	wf := newTestWorkflow(t, "structs")
+	wf.AddTask(workflow.HttpCallTask("create",
+		workflow.WithHTTPPost(),
+		workflow.WithURI("https://api.example.com/items"),
+		workflow.WithBodyFromStruct(payload{Name: "widget", Count: 3}),
+	))
+
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err, "should convert workflow")
+
+	body := manifest.Workflows[0].Spec.Tasks[0].TaskConfig.Fields["body"].GetStructValue()
+	require.NotNil(t, body, "should have body")
+	assert.Equal(t, "widget", body.Fields["name"].GetStringValue())
+	assert.Equal(t, float64(3), body.Fields["count"].GetNumberValue())
+}
+
+// TestHttpCallTaskNetworkOptions verifies TLS, proxy and redirect settings are synthesized.
+func TestHttpCallTaskNetworkOptions(t *testing.T) {
+	wf := newTestWorkflow(t, "internal")
+	wf.AddTask(workflow.HttpCallTask("call",
+		workflow.WithHTTPGet(),
+		workflow.WithURI("https://internal.example.com"),
+		workflow.WithInsecureSkipVerify(),
+		workflow.WithProxy("http://proxy:3128"),
+		workflow.WithFollowRedirects(false),
+	))
+
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err, "should convert workflow")
+
+	fields := manifest.Workflows[0].Spec.Tasks[0].TaskConfig.Fields
+	assert.True(t, fields["tls"].GetStructValue().Fields["insecure_skip_verify"].GetBoolValue())
+	assert.Equal(t, "http://proxy:3128", fields["proxy"].GetStringValue())
+	assert.Contains(t, fields, "follow_redirects")
+	assert.False(t, fields["follow_redirects"].GetBoolValue())
+}
+
+type synthTicketConfig struct {
+	workflow.CustomConfig
+	Project string
+}
+
+// TestCustomTaskKindLowering verifies custom task kinds are synthesized as their built-in equivalent.
+func TestCustomTaskKindLowering(t *testing.T) {
+	const kind workflow.TaskKind = "SYNTH_TEST_TICKET"
+	require.NoError(t, workflow.RegisterTaskKind(kind, func(task *workflow.Task) (*workflow.Task, error) {
+		cfg := task.Config.(*synthTicketConfig)
+		return workflow.HttpCallTask(task.Name,
+			workflow.WithHTTPPost(),
+			workflow.WithURI("https://tickets.example.com/"+cfg.Project),
+		), nil
+	}))
+
+	wf := newTestWorkflow(t, "tickets")
+	wf.AddTask(workflow.ForTask("each",
+		workflow.WithIn("${.projects}"),
+		workflow.WithDo(workflow.CustomTask("nested", kind, &synthTicketConfig{Project: "DEV"})),
+	))
+	wf.AddTask(workflow.CustomTask("open", kind, &synthTicketConfig{Project: "OPS"}))
+
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err, "should convert workflow")
+
+	tasks := manifest.Workflows[0].Spec.Tasks
+	assert.Equal(t, apiresource.WorkflowTaskKind_WORKFLOW_TASK_KIND_HTTP_CALL, tasks[1].Kind)
+	assert.Equal(t, "open", tasks[1].Name)
+	assert.Equal(t, "https://tickets.example.com/OPS",
+		tasks[1].TaskConfig.Fields["endpoint"].GetStructValue().Fields["uri"].GetStringValue())
+
+	nested := tasks[0].TaskConfig.Fields["do"].GetListValue().Values[0].GetStructValue()
+	assert.Equal(t, "WORKFLOW_TASK_KIND_HTTP_CALL", nested.Fields["kind"].GetStringValue())
+}
+
+// TestGrpcCallTaskConnection verifies gRPC connection settings are synthesized.
+func TestGrpcCallTaskConnection(t *testing.T) {
+	wf := newTestWorkflow(t, "grpc")
+	wf.AddTask(workflow.GrpcCallTask("getUser",
+		workflow.WithGrpcEndpoint("users.internal:443"),
+		workflow.WithService("UserService"),
+		workflow.WithGrpcMethod("GetUser"),
+		workflow.WithGrpcMetadata(map[string]string{"x-tenant": "acme"}),
+		workflow.WithGrpcTLS(),
+		workflow.WithGrpcDeadline("30s"),
+	))
+
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err, "should convert workflow")
+
+	fields := manifest.Workflows[0].Spec.Tasks[0].TaskConfig.Fields
+	assert.Equal(t, "users.internal:443", fields["endpoint"].GetStringValue())
+	assert.Equal(t, "acme", fields["metadata"].GetStructValue().Fields["x-tenant"].GetStringValue())
+	assert.NotNil(t, fields["tls"].GetStructValue())
+	assert.Equal(t, "30s", fields["deadline"].GetStringValue())
+}
+
+// TestCallActivityTaskOptions verifies Temporal activity settings are synthesized.
+func TestCallActivityTaskOptions(t *testing.T) {
+	wf := newTestWorkflow(t, "activity")
+	wf.AddTask(workflow.CallActivityTask("processData",
+		workflow.WithActivity("DataProcessor"),
+		workflow.WithTaskQueue("data-processing"),
+		workflow.WithStartToCloseTimeout("5m"),
+		workflow.WithHeartbeatTimeout("30s"),
+		workflow.WithActivityRetry(workflow.RetryPolicy{
+			MaximumAttempts:        3,
+			NonRetryableErrorTypes: []string{"ValidationError"},
+		}),
+	))
+
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err, "should convert workflow")
+
+	fields := manifest.Workflows[0].Spec.Tasks[0].TaskConfig.Fields
+	assert.Equal(t, "data-processing", fields["task_queue"].GetStringValue())
+	assert.Equal(t, "5m", fields["start_to_close_timeout"].GetStringValue())
+	assert.Equal(t, "30s", fields["heartbeat_timeout"].GetStringValue())
+	assert.NotContains(t, fields, "schedule_to_close_timeout")
+
+	retry := fields["retry_policy"].GetStructValue().Fields
+	assert.Equal(t, float64(3), retry["maximum_attempts"].GetNumberValue())
+	assert.Equal(t, "ValidationError", retry["non_retryable_error_types"].GetListValue().Values[0].GetStringValue())
+}
+
+// TestListenTaskFilterAndCorrelation verifies LISTEN filters and correlations are synthesized.
+func TestListenTaskFilterAndCorrelation(t *testing.T) {
+	wf := newTestWorkflow(t, "listen")
+	wf.AddTask(workflow.ListenTask("waitForPayment",
+		workflow.WithEvent("payment.completed"),
+		workflow.WithEventFilter(".data.amount > 100"),
+		workflow.WithCorrelation("orderId", "${ $context.orderId }"),
+	))
+
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err, "should convert workflow")
+
+	fields := manifest.Workflows[0].Spec.Tasks[0].TaskConfig.Fields
+	assert.Equal(t, "${ .data.amount > 100 }", fields["filter"].GetStringValue())
+	assert.Equal(t, "${ $context.orderId }",
+		fields["correlation"].GetStructValue().Fields["orderId"].GetStringValue())
+}
+
+// TestListenTaskTimeout verifies LISTEN timeouts and their fallback task are synthesized.
+func TestListenTaskTimeout(t *testing.T) {
+	wf := newTestWorkflow(t, "listen-timeout")
+	escalate := workflow.SetTask("escalate", workflow.SetVar("escalated", "true"))
+	wf.AddTask(workflow.ListenTask("waitForApproval",
+		workflow.WithEvent("approval.granted"),
+		workflow.WithListenTimeout(workflow.Hours(24), escalate),
+	))
+	wf.AddTask(escalate)
+
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err, "should convert workflow")
+
+	fields := manifest.Workflows[0].Spec.Tasks[0].TaskConfig.Fields
+	assert.Equal(t, "24h", fields["timeout"].GetStringValue())
+	assert.Equal(t, "escalate", fields["timeout_then"].GetStringValue())
+}
+
+// TestWaitTaskUntil verifies WAIT until-timestamps replace the duration in the task config.
+func TestWaitTaskUntil(t *testing.T) {
+	wf := newTestWorkflow(t, "wait-until")
+	wf.AddTask(workflow.WaitTask("waitForSlot",
+		workflow.WithUntilExpression("${ $context.slot.startsAt }"),
+	))
+
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err, "should convert workflow")
+
+	fields := manifest.Workflows[0].Spec.Tasks[0].TaskConfig.Fields
+	assert.Equal(t, "${ $context.slot.startsAt }", fields["until"].GetStringValue())
+	assert.NotContains(t, fields, "duration")
+}
+
+// TestEmitTaskLowered verifies EMIT tasks are synthesized as CALL_ACTIVITY
+// tasks running the platform event activity.
+func TestEmitTaskLowered(t *testing.T) {
+	wf := newTestWorkflow(t, "emit")
+	createOrder := wf.HttpPost("createOrder", "https://api.example.com/orders")
+	wf.AddTask(workflow.EmitTask("orderCreated",
+		workflow.WithEventName("order.created"),
+		workflow.WithEventData(map[string]any{"orderId": createOrder.Field("id")}),
+	))
+
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err, "should convert workflow")
+
+	task := manifest.Workflows[0].Spec.Tasks[1]
+	assert.Equal(t, "orderCreated", task.Name)
+	assert.Equal(t, apiresource.WorkflowTaskKind_WORKFLOW_TASK_KIND_CALL_ACTIVITY, task.Kind)
+	assert.Equal(t, "stigmer.events.Emit", task.TaskConfig.Fields["activity"].GetStringValue())
+	input := task.TaskConfig.Fields["input"].GetStructValue()
+	assert.Equal(t, "order.created", input.Fields["event"].GetStringValue())
+	assert.Equal(t, "${ $context.createOrder.id }",
+		input.Fields["data"].GetStructValue().Fields["orderId"].GetStringValue())
+}
+
+// TestScriptTaskLowered verifies SCRIPT tasks are synthesized as SET tasks exporting the result.
+func TestScriptTaskLowered(t *testing.T) {
+	wf := newTestWorkflow(t, "script")
+	wf.AddTask(workflow.ScriptTask("toPayload",
+		workflow.WithExpression("$context.fetchUser | {id, email}"),
+	))
+
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err, "should convert workflow")
+
+	task := manifest.Workflows[0].Spec.Tasks[0]
+	assert.Equal(t, apiresource.WorkflowTaskKind_WORKFLOW_TASK_KIND_SET, task.Kind)
+	assert.Equal(t, "${ $context.fetchUser | {id, email} }",
+		task.TaskConfig.Fields["variables"].GetStructValue().Fields["result"].GetStringValue())
+	assert.Equal(t, "${ .result }", task.Export.As)
+}
+
+// TestAgentCallTaskInput verifies AGENT_CALL structured input is synthesized.
+func TestAgentCallTaskInput(t *testing.T) {
+	wf := newTestWorkflow(t, "agent-call")
+	wf.AddTask(workflow.AgentCallTask("review",
+		workflow.AgentOption(workflow.AgentBySlug("code-reviewer")),
+		workflow.WithPrompt("Review this pull request"),
+		workflow.WithAgentInput(map[string]any{"prUrl": "${ $context.prUrl }"}),
+	))
+
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err, "should convert workflow")
+
+	fields := manifest.Workflows[0].Spec.Tasks[0].TaskConfig.Fields
+	assert.Equal(t, "Review this pull request", fields["message"].GetStringValue())
+	assert.Equal(t, "${ $context.prUrl }",
+		fields["input"].GetStructValue().Fields["prUrl"].GetStringValue())
+}
+
+// TestTaskCompensationLowered verifies compensated tasks are synthesized as TRY blocks.
+func TestTaskCompensationLowered(t *testing.T) {
+	wf := newTestWorkflow(t, "saga")
+	wf.AddTask(workflow.SetTask("charge", workflow.SetVar("charged", "true")).
+		Compensate(workflow.SetTask("refund", workflow.SetVar("charged", "false"))))
+	wf.AddTask(workflow.SetTask("ship", workflow.SetVar("shipped", "true")))
+
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err, "should convert workflow")
+
+	tasks := manifest.Workflows[0].Spec.Tasks
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "charge", tasks[0].Name)
+	assert.Equal(t, "charge-saga", tasks[1].Name)
+	assert.Equal(t, apiresource.WorkflowTaskKind_WORKFLOW_TASK_KIND_TRY, tasks[1].Kind)
+
+	tryTasks := tasks[1].TaskConfig.Fields["try"].GetListValue().Values
+	require.Len(t, tryTasks, 1)
+	assert.Equal(t, "ship", tryTasks[0].GetStructValue().Fields["name"].GetStringValue())
+
+	catch := tasks[1].TaskConfig.Fields["catch"].GetStructValue()
+	assert.Equal(t, "sagaError", catch.Fields["as"].GetStringValue())
+	catchTasks := catch.Fields["do"].GetListValue().Values
+	require.Len(t, catchTasks, 2)
+	assert.Equal(t, "refund", catchTasks[0].GetStructValue().Fields["name"].GetStringValue())
+	assert.Equal(t, "charge-rethrow", catchTasks[1].GetStructValue().Fields["name"].GetStringValue())
+}
+
+// TestWorkflowFinalizersLowered verifies finalizers are synthesized around a top-level TRY block.
+func TestWorkflowFinalizersLowered(t *testing.T) {
+	wf := newTestWorkflow(t, "finalizers",
+		workflow.WithFinalizer(workflow.SetTask("releaseLock", workflow.SetVar("locked", "false"))))
+	wf.AddTask(workflow.SetTask("work", workflow.SetVar("done", "true")))
+
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err, "should convert workflow")
+
+	tasks := manifest.Workflows[0].Spec.Tasks
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "finally", tasks[0].Name)
+	assert.Equal(t, apiresource.WorkflowTaskKind_WORKFLOW_TASK_KIND_TRY, tasks[0].Kind)
+	assert.Equal(t, "releaseLock", tasks[1].Name)
+
+	catch := tasks[0].TaskConfig.Fields["catch"].GetStructValue()
+	assert.Equal(t, "finallyError", catch.Fields["as"].GetStringValue())
+	catchTasks := catch.Fields["do"].GetListValue().Values
+	require.Len(t, catchTasks, 2)
+	assert.Equal(t, "releaseLock-on-error", catchTasks[0].GetStructValue().Fields["name"].GetStringValue())
+	assert.Equal(t, "finally-rethrow", catchTasks[1].GetStructValue().Fields["name"].GetStringValue())
+}
+
+// TestWorkflowLockLowered verifies locks are synthesized as activity calls released by a finalizer.
+func TestWorkflowLockLowered(t *testing.T) {
+	wf := newTestWorkflow(t, "locks")
+	wf.AcquireLock("ledger", 600)
+	wf.AddTask(workflow.SetTask("work", workflow.SetVar("done", "true")))
+
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err, "should convert workflow")
+
+	tasks := manifest.Workflows[0].Spec.Tasks
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "finally", tasks[0].Name)
+	assert.Equal(t, "finally-release-lock-ledger", tasks[1].Name)
+	assert.Equal(t, apiresource.WorkflowTaskKind_WORKFLOW_TASK_KIND_CALL_ACTIVITY, tasks[1].Kind)
+
+	tryTasks := tasks[0].TaskConfig.Fields["try"].GetListValue().Values
+	require.Len(t, tryTasks, 2)
+	acquire := tryTasks[0].GetStructValue().Fields
+	assert.Equal(t, "acquire-lock-ledger", acquire["name"].GetStringValue())
+	assert.Equal(t, "WORKFLOW_TASK_KIND_CALL_ACTIVITY", acquire["kind"].GetStringValue())
+}
+
+// TestWorkflowTimeoutAndTaskDeadlines verifies execution deadlines are carried as annotations.
+func TestWorkflowTimeoutAndTaskDeadlines(t *testing.T) {
+	wf := newTestWorkflow(t, "deadlines", workflow.WithWorkflowTimeout(workflow.Hours(1)))
+	wf.AddTask(workflow.SetTask("init", workflow.SetVar("x", "1")))
+	wf.AddTask(workflow.WaitTask("pause", workflow.WithDuration("5s")).WithDeadline(workflow.Seconds(10)))
+
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err, "should convert workflow")
+
+	metadata := manifest.Workflows[0].Metadata
+	require.NotNil(t, metadata, "should have metadata")
+	assert.Equal(t, "1h", metadata.Annotations[workflow.AnnotationTimeout])
+	assert.JSONEq(t, `{"pause":"10s"}`, metadata.Annotations[workflow.AnnotationTaskDeadlines])
+}
+
+// TestCompensationDeadline verifies a deadline set on a compensation task is carried as an annotation.
+func TestCompensationDeadline(t *testing.T) {
+	wf := newTestWorkflow(t, "saga-deadlines")
+	wf.AddTask(workflow.SetTask("charge", workflow.SetVar("charged", "true")).
+		Compensate(workflow.SetTask("refund", workflow.SetVar("charged", "false")).WithDeadline(workflow.Seconds(30))))
+	wf.AddTask(workflow.SetTask("ship", workflow.SetVar("shipped", "true")))
+
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err, "should convert workflow")
+
+	metadata := manifest.Workflows[0].Metadata
+	require.NotNil(t, metadata, "should have metadata")
+	assert.JSONEq(t, `{"refund":"30s"}`, metadata.Annotations[workflow.AnnotationTaskDeadlines])
+}
+
+// TestWorkflowErrorPolicy verifies the retry budget is carried as an annotation.
+func TestWorkflowErrorPolicy(t *testing.T) {
+	wf := newTestWorkflow(t, "budget", workflow.WithErrorPolicy(
+		workflow.MaxTotalRetries(10),
+		workflow.OnExhausted("RetryBudgetExceeded", "Budget exceeded"),
+	))
+	wf.AddTask(workflow.SetTask("init", workflow.SetVar("x", "1")))
+
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err, "should convert workflow")
+
+	metadata := manifest.Workflows[0].Metadata
+	require.NotNil(t, metadata, "should have metadata")
+	assert.JSONEq(t,
+		`{"max_total_retries":10,"exhausted_error":"RetryBudgetExceeded","exhausted_message":"Budget exceeded"}`,
+		metadata.Annotations[workflow.AnnotationErrorPolicy],
+	)
+}
+
+// TestTaskIdempotencyKeys verifies idempotency keys are carried as an annotation.
+func TestTaskIdempotencyKeys(t *testing.T) {
+	wf := newTestWorkflow(t, "idempotent")
+	wf.AddTask(workflow.SetTask("charge", workflow.SetVar("charged", "true")).
+		WithIdempotencyKey("${ .input.orderId }"))
+
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err, "should convert workflow")
+
+	metadata := manifest.Workflows[0].Metadata
+	require.NotNil(t, metadata, "should have metadata")
+	assert.JSONEq(t, `{"charge":"${ .input.orderId }"}`, metadata.Annotations[workflow.AnnotationIdempotencyKeys])
+}
+
+// TestRaceTaskCompetes verifies race tasks synthesize to a competing FORK.
+func TestRaceTaskCompetes(t *testing.T) {
+	wf := newTestWorkflow(t, "race")
+	wf.AddTask(workflow.RaceTask("awaitApproval",
+		workflow.WithListen("approval.granted"),
+		workflow.WithRaceTimeout(workflow.Minutes(30)),
+	))
+
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err, "should convert workflow")
+
+	task := manifest.Workflows[0].Spec.Tasks[0]
+	assert.Equal(t, apiresource.WorkflowTaskKind_WORKFLOW_TASK_KIND_FORK, task.Kind)
+	assert.True(t, task.TaskConfig.Fields["compete"].GetBoolValue())
+	assert.Len(t, task.TaskConfig.Fields["branches"].GetListValue().Values, 2)
+}
+
+// TestWorkflowDefaultsApplied verifies HTTP tasks inherit workflow defaults in the manifest.
+func TestWorkflowDefaultsApplied(t *testing.T) {
+	wf := newTestWorkflow(t, "defaults", workflow.WithDefaults(
+		workflow.DefaultTimeout(10),
+		workflow.DefaultRetry(workflow.RetryPolicy{MaximumAttempts: 3}),
+		workflow.DefaultHeaders(map[string]string{"X-Team": "billing"}),
+	))
+	wf.HttpGet("fetch", "https://api.example.com/invoices")
+
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err, "should convert workflow")
+
+	fields := manifest.Workflows[0].Spec.Tasks[0].TaskConfig.Fields
+	assert.Equal(t, float64(10), fields["timeout_seconds"].GetNumberValue())
+	assert.Equal(t, "billing", fields["headers"].GetStructValue().Fields["X-Team"].GetStringValue())
+	assert.Equal(t, float64(3),
+		fields["retry_policy"].GetStructValue().Fields["maximum_attempts"].GetNumberValue())
+}
+
+// TestTasksOrderedByDependencies verifies tasks are synthesized after their dependencies.
+func TestTasksOrderedByDependencies(t *testing.T) {
+	wf := newTestWorkflow(t, "ordered")
+	notify := workflow.SetTask("notify", workflow.SetVar("sent", "true"))
+	fetch := workflow.HttpCallTask("fetch", workflow.WithURI("https://api.example.com"))
+	wf.AddTasks(notify.DependsOn(fetch), fetch)
+
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err, "should convert workflow")
+
+	tasks := manifest.Workflows[0].Spec.Tasks
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "fetch", tasks[0].Name)
+	assert.Equal(t, "notify", tasks[1].Name)
+
+	fetch.DependsOn(notify)
+	_, err = ToWorkflowManifest(wf)
+	assert.ErrorIs(t, err, workflow.ErrDependencyCycle)
+}
+
+// TestSwitchTargetValidated verifies switch cases pointing at missing tasks fail synthesis.
+func TestSwitchTargetValidated(t *testing.T) {
+	wf := newTestWorkflow(t, "switch-target")
+	wf.AddTasks(
+		workflow.SwitchTask("route", workflow.WithCase("${ .ok }", "handleX")),
+		workflow.SetTask("handleY", workflow.SetVar("x", "1")),
+	)
+
+	_, err := ToWorkflowManifest(wf)
+	assert.ErrorIs(t, err, workflow.ErrInvalidFlow)
+	assert.ErrorContains(t, err, "valid targets: route, handleY")
+}
+
+// TestSharedExpressionsResolved verifies UseExpression placeholders are replaced
+// by their definitions and undefined names fail synthesis.
+func TestSharedExpressionsResolved(t *testing.T) {
+	require.NoError(t, workflow.DefineExpression("synthIsOpen", "${ .status == \"open\" }"))
+
+	wf := newTestWorkflow(t, "shared-expressions")
+	wf.AddTasks(
+		workflow.SwitchTask("route", workflow.WithCase(workflow.UseExpression("synthIsOpen"), "handle")),
+		workflow.SetTask("handle", workflow.SetVar("x", "1")),
+	)
+
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err, "should convert workflow")
+
+	cases := manifest.Workflows[0].Spec.Tasks[0].TaskConfig.Fields["cases"].GetListValue().Values
+	require.Len(t, cases, 1)
+	assert.Equal(t, `${ (.status == "open") }`, cases[0].GetStructValue().Fields["when"].GetStringValue())
+
+	wf.AddTask(workflow.SetTask("flag", workflow.SetVar("closed", workflow.UseExpression("synthIsClosed"))))
+	_, err = ToWorkflowManifest(wf)
+	assert.ErrorIs(t, err, workflow.ErrInvalidExpression)
+}
+
+// TestFunctionCallsLowered verifies tasks calling a shared function are synthesized as its HTTP call.
+func TestFunctionCallsLowered(t *testing.T) {
+	billing := workflow.HttpCallTask("billing", workflow.WithHTTPPost(), workflow.WithURI("https://billing.example.com/charges"))
+	require.NoError(t, workflow.DefineFunction("synthCallBilling", billing.Config))
+
+	wf := newTestWorkflow(t, "functions")
+	wf.CallFunction("charge", "synthCallBilling")
+	wf.CallFunction("refund", "synthCallBilling")
+
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err, "should convert workflow")
+
+	tasks := manifest.Workflows[0].Spec.Tasks
+	require.Len(t, tasks, 2)
+	for _, task := range tasks {
+		assert.Equal(t, apiresource.WorkflowTaskKind_WORKFLOW_TASK_KIND_HTTP_CALL, task.Kind)
+		assert.Equal(t, "https://billing.example.com/charges", task.TaskConfig.Fields["endpoint"].GetStructValue().Fields["uri"].GetStringValue())
+	}
+
+	wf.CallFunction("notify", "synthUndefined")
+	_, err = ToWorkflowManifest(wf)
+	assert.ErrorIs(t, err, workflow.ErrInvalidTaskConfig)
+}
+
+// TestDSLVersionValidated verifies features newer than the workflow's DSL version fail synthesis.
+func TestDSLVersionValidated(t *testing.T) {
+	wf := newTestWorkflow(t, "legacy", workflow.WithDSLVersion("1.0.0-alpha5"))
+	wf.AddTask(workflow.ListenTask("approval", workflow.WithEvent("approval.granted")))
+
+	_, err := ToWorkflowManifest(wf)
+	assert.ErrorIs(t, err, workflow.ErrDSLIncompatible)
+
+	wf.Document.DSL = workflow.DefaultDSLVersion
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err, "should convert workflow")
+	assert.Equal(t, "1.0.0", manifest.Workflows[0].Spec.Document.Dsl)
+}
+
+// TestWorkflowFromProto_RoundTrip verifies a synthesized workflow can be read back
+// into typed tasks and synthesizes to the same proto again.
+func TestWorkflowFromProto_RoundTrip(t *testing.T) {
+	wf := newTestWorkflow(t, "orders",
+		workflow.WithVersion("1.2.0"),
+		workflow.WithDescription("Process orders"),
+		workflow.WithSchedule(workflow.Cron("0 2 * * *")),
+		workflow.WithWorkflowTimeout(workflow.Hours(1)),
+		workflow.WithErrorPolicy(workflow.MaxTotalRetries(5)),
+		workflow.WithDisabled("staged rollout"),
+		workflow.WithResources("500m", "1Gi"),
+	)
+	fetch := wf.HttpGet("fetch", "https://api.example.com/orders",
+		workflow.WithHeader("Accept", "application/json"),
+	).WithDeadline(workflow.Minutes(5))
+	wf.AddTask(workflow.SwitchTask("route",
+		workflow.WithCase("${ .ok }", "process"),
+		workflow.WithDefault("fail"),
+	))
+	wf.AddTask(workflow.ForTask("process",
+		workflow.WithIn(fetch.Field("items")),
+		workflow.WithDo(workflow.SetTask("mark", workflow.SetVar("seen", "true"))),
+	).End())
+	wf.AddTask(workflow.TryTask("fail",
+		workflow.WithTry(workflow.RaiseTask("raise", workflow.WithError("OrderFailed"))),
+		workflow.WithCatch(nil, "err", workflow.WaitTask("backoff", workflow.WithDuration("5s"))),
+	))
+
+	manifest, err := ToWorkflowManifest(wf)
+	require.NoError(t, err, "should convert workflow")
+	original := manifest.Workflows[0]
+
+	restored, err := workflow.FromProto(original)
+	require.NoError(t, err, "should read workflow back")
+
+	assert.Equal(t, "orders", restored.Document.Name)
+	assert.Equal(t, "1.2.0", restored.Document.Version)
+	assert.Equal(t, []workflow.Trigger{workflow.Cron("0 2 * * *")}, restored.Triggers)
+	assert.True(t, restored.Disabled)
+	assert.Equal(t, wf.Resources, restored.Resources)
+	require.Len(t, restored.Tasks, 4)
+	http, ok := restored.Tasks[0].Config.(*workflow.HttpCallTaskConfig)
+	require.True(t, ok, "fetch should be an HTTP task")
+	assert.Equal(t, "https://api.example.com/orders", http.URI)
+	assert.Equal(t, "5m", restored.Tasks[0].Deadline)
+	assert.Equal(t, "fail", restored.Tasks[1].Config.(*workflow.SwitchTaskConfig).DefaultTask)
+	assert.Equal(t, "mark", restored.Tasks[2].Config.(*workflow.ForTaskConfig).Do[0].Name)
+
+	again, err := ToWorkflowManifest(restored)
+	require.NoError(t, err, "should convert restored workflow")
+	assert.True(t, proto.Equal(original, again.Workflows[0]), "round trip should be lossless:\n%v\n%v", original, again.Workflows[0])
+}
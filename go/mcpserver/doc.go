@@ -31,4 +31,14 @@
 //		mcpserver.WithImage("ghcr.io/org/mcp:latest"),
 //		mcpserver.WithEnvPlaceholder("API_KEY", "${API_KEY}"),
 //	)
+//
+// Smoke Testing:
+//
+// Probe launches a server (or calls an HTTP endpoint) on the local machine and
+// runs the MCP handshake, catching a wrong command, URL or tool name before the
+// configuration is deployed:
+//
+//	if _, err := mcpserver.Probe(github); err != nil {
+//		log.Fatal(err)
+//	}
 package mcpserver
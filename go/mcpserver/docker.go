@@ -2,6 +2,8 @@ package mcpserver
 
 import (
 	"fmt"
+
+	"github.com/leftbin/stigmer-sdk/go/resources"
 )
 
 // DockerServer represents a Docker-based MCP server that runs in a container.
@@ -24,6 +26,7 @@ type DockerServer struct {
 	network         string
 	ports           []PortMapping
 	containerName   string
+	resources       resources.Requirements
 }
 
 // Docker creates a new Docker-based MCP server with the given options.
@@ -96,6 +99,11 @@ func (d *DockerServer) ContainerName() string {
 	return d.containerName
 }
 
+// Resources returns the requested CPU and memory.
+func (d *DockerServer) Resources() resources.Requirements {
+	return d.resources
+}
+
 // Type returns the server type (docker).
 func (d *DockerServer) Type() ServerType {
 	return TypeDocker
@@ -133,5 +141,9 @@ func (d *DockerServer) Validate() error {
 		}
 	}
 
+	if err := d.resources.Validate(); err != nil {
+		return fmt.Errorf("docker server %q: %w", d.name, err)
+	}
+
 	return nil
 }
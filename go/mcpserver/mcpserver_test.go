@@ -1,7 +1,10 @@
 package mcpserver
 
 import (
+	"errors"
 	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/resources"
 )
 
 // Test Stdio Server
@@ -260,6 +263,38 @@ func TestDockerServer_InvalidProtocol(t *testing.T) {
 	}
 }
 
+func TestWithResources(t *testing.T) {
+	server, err := Docker(
+		WithName("custom-mcp"),
+		WithImage("ghcr.io/org/mcp:latest"),
+		WithResources("250m", "256Mi"),
+	)
+	if err != nil {
+		t.Fatalf("Docker() error = %v", err)
+	}
+	if got := server.Resources(); got.CPU != "250m" || got.Memory != "256Mi" {
+		t.Errorf("Resources() = %+v, want 250m/256Mi", got)
+	}
+
+	_, err = Stdio(
+		WithName("github"),
+		WithCommand("npx"),
+		WithResources("", "lots"),
+	)
+	if !errors.Is(err, resources.ErrInvalidMemory) {
+		t.Errorf("Stdio() error = %v, want ErrInvalidMemory", err)
+	}
+
+	_, err = HTTP(
+		WithName("api"),
+		WithURL("https://mcp.example.com"),
+		WithResources("1", ""),
+	)
+	if err == nil {
+		t.Error("HTTP() expected error for WithResources")
+	}
+}
+
 
 
 // Test VolumeMount and PortMapping
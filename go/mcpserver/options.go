@@ -1,6 +1,10 @@
 package mcpserver
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/leftbin/stigmer-sdk/go/resources"
+)
 
 // Option is a function that configures an MCP server.
 type Option func(interface{}) error
@@ -104,6 +108,10 @@ func WithArgs(args ...string) Option {
 // The placeholder value (e.g., "${GITHUB_TOKEN}") will be resolved at agent instance runtime.
 // Only applicable to StdioServer and DockerServer.
 //
+// Referenced variables should be declared on the agent with
+// agent.WithEnvironmentVariable; synthesis declares missing ones as required
+// secrets and prints a warning.
+//
 // Example:
 //
 //	mcpserver.Stdio(
@@ -144,6 +152,32 @@ func WithWorkingDir(dir string) Option {
 	}
 }
 
+// WithResources sets the CPU and memory requested for a stdio or docker server.
+// Quantities use the Kubernetes notation (e.g., "250m" CPU, "256Mi" memory);
+// pass an empty string to keep the platform default for either value.
+// Only applicable to StdioServer and DockerServer.
+//
+// Example:
+//
+//	mcpserver.Docker(
+//		mcpserver.WithImage("ghcr.io/org/mcp:latest"),
+//		mcpserver.WithResources("250m", "256Mi"),
+//	)
+func WithResources(cpu, memory string) Option {
+	return func(s interface{}) error {
+		req := resources.Requirements{CPU: cpu, Memory: memory}
+		switch server := s.(type) {
+		case *StdioServer:
+			server.resources = req
+		case *DockerServer:
+			server.resources = req
+		default:
+			return fmt.Errorf("WithResources only applies to StdioServer or DockerServer, got %T", s)
+		}
+		return nil
+	}
+}
+
 // HTTP-specific options
 
 // WithURL sets the base URL for an HTTP server.
@@ -0,0 +1,204 @@
+package mcpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultProbeTimeout bounds a probe, including process start-up. It leaves
+// room for launchers such as npx that download the server on first use.
+const DefaultProbeTimeout = 30 * time.Second
+
+// ProbeResult describes a server that answered the MCP handshake.
+type ProbeResult struct {
+	// ServerName and ServerVersion are reported by the server itself
+	ServerName    string
+	ServerVersion string
+
+	// Tools lists the tools the server offers. It is empty for legacy SSE
+	// endpoints, which are only checked for reachability.
+	Tools []string
+}
+
+// ProbeOption is a functional option for Probe and Connect.
+type ProbeOption func(*probeConfig)
+
+type probeConfig struct {
+	timeout time.Duration
+}
+
+func newProbeConfig(opts []ProbeOption) *probeConfig {
+	cfg := &probeConfig{timeout: DefaultProbeTimeout}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithProbeTimeout sets how long Probe waits for the server (default 30s).
+func WithProbeTimeout(timeout time.Duration) ProbeOption {
+	return func(c *probeConfig) {
+		c.timeout = timeout
+	}
+}
+
+// Probe smoke-tests an MCP server configuration on the local machine.
+//
+// Stdio servers are launched with their command, arguments and working
+// directory, and Docker servers with "docker run -i". HTTP servers are sent
+// the handshake over streamable HTTP; endpoints that only accept GET are
+// treated as legacy SSE servers and checked for reachability.
+//
+// Env placeholders and header values are expanded from the local environment,
+// so export the variables (e.g., GITHUB_TOKEN) before probing. Probe lists the
+// server's tools and fails if a tool passed to WithEnabledTools is missing.
+//
+// Probe is meant for development and CI, not for synthesis: it runs real
+// processes and opens network connections.
+//
+// Example:
+//
+//	result, err := mcpserver.Probe(github)
+//	if err != nil {
+//	    log.Fatalf("github MCP server is broken: %v", err)
+//	}
+//	fmt.Println(result.ServerName, result.Tools)
+func Probe(server MCPServer, opts ...ProbeOption) (*ProbeResult, error) {
+	cfg := newProbeConfig(opts)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.timeout)
+	defer cancel()
+
+	result, err := probe(ctx, server, cfg.timeout)
+	if err != nil {
+		if ctx.Err() != nil {
+			err = fmt.Errorf("no response within %s: %w", cfg.timeout, err)
+		}
+		return nil, fmt.Errorf("probe %s: %w", server.Name(), err)
+	}
+
+	if result.Tools != nil {
+		if missing := missingTools(server.EnabledTools(), result.Tools); len(missing) > 0 {
+			return result, fmt.Errorf("probe %s: enabled tools not offered by the server: %s",
+				server.Name(), strings.Join(missing, ", "))
+		}
+	}
+	return result, nil
+}
+
+// probe connects to a server and lists its tools.
+func probe(ctx context.Context, server MCPServer, timeout time.Duration) (*ProbeResult, error) {
+	session, err := connect(ctx, server, timeout)
+	if errors.Is(err, errLegacySSE) {
+		return probeSSE(ctx, server.(*HTTPServer))
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	tools, err := session.listTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := &ProbeResult{
+		ServerName:    session.ServerName,
+		ServerVersion: session.ServerVersion,
+		Tools:         make([]string, 0, len(tools)),
+	}
+	for _, tool := range tools {
+		result.Tools = append(result.Tools, tool.Name)
+	}
+	return result, nil
+}
+
+// probeSSE checks that a legacy SSE endpoint accepts an event stream.
+func probeSSE(ctx context.Context, s *HTTPServer) (*ProbeResult, error) {
+	t, err := newHTTPTransport(s)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	t.setHeaders(req)
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("SSE endpoint returned %s", resp.Status)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+		return nil, fmt.Errorf("SSE endpoint returned content type %q", ct)
+	}
+	return &ProbeResult{}, nil
+}
+
+// dockerRunArgs builds the "docker run" arguments for an interactive session.
+// Ports are not published; the client talks to the container over stdio.
+//
+// Env placeholders are passed by name only ("-e KEY"), so docker reads the
+// values from its own environment (see expandedEnv) and they never appear in
+// the process list.
+func dockerRunArgs(d *DockerServer) []string {
+	args := []string{"run", "-i", "--rm"}
+	for _, k := range sortedKeys(d.EnvPlaceholders()) {
+		args = append(args, "-e", k)
+	}
+	for _, v := range d.Volumes() {
+		mount := v.HostPath + ":" + v.ContainerPath
+		if v.ReadOnly {
+			mount += ":ro"
+		}
+		args = append(args, "-v", mount)
+	}
+	if d.Network() != "" {
+		args = append(args, "--network", d.Network())
+	}
+	args = append(args, d.Image())
+	return append(args, d.Args()...)
+}
+
+// expandedEnv returns the local environment with the placeholders added,
+// their values expanded from the local environment.
+func expandedEnv(placeholders map[string]string) []string {
+	env := os.Environ()
+	for _, k := range sortedKeys(placeholders) {
+		env = append(env, k+"="+os.ExpandEnv(placeholders[k]))
+	}
+	return env
+}
+
+// missingTools returns the enabled tools the server does not offer.
+func missingTools(enabled, offered []string) []string {
+	available := make(map[string]bool, len(offered))
+	for _, name := range offered {
+		available[name] = true
+	}
+	var missing []string
+	for _, name := range enabled {
+		if !available[name] {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// sortedKeys returns the keys of m in lexical order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,346 @@
+package mcpserver
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// probeHelperEnv makes the test binary act as a stdio MCP server.
+const probeHelperEnv = "MCPSERVER_PROBE_HELPER"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(probeHelperEnv) == "1" {
+		runFakeStdioServer()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runFakeStdioServer answers initialize and tools/list on stdin/stdout.
+func runFakeStdioServer() {
+	fmt.Println("fake server starting") // servers may log to stdout
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var req rpcMessage
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil || req.ID == nil || req.Method == "test/unanswered" {
+			continue
+		}
+		_ = json.NewEncoder(os.Stdout).Encode(fakeResponse(req))
+	}
+}
+
+// fakeResponse builds the response of the fake servers to a request.
+func fakeResponse(req rpcMessage) map[string]any {
+	resp := map[string]any{"jsonrpc": "2.0", "id": *req.ID}
+	switch req.Method {
+	case "initialize":
+		resp["result"] = map[string]any{
+			"protocolVersion": mcpProtocolVersion,
+			"serverInfo":      map[string]any{"name": "fake", "version": "0.1.0"},
+		}
+	case "tools/list":
+		resp["result"] = map[string]any{
+			"tools": []any{
+				map[string]any{"name": "create_issue"},
+				map[string]any{"name": "list_repos"},
+			},
+		}
+	case "tools/call":
+		params, _ := req.Params.(map[string]any)
+		args, _ := json.Marshal(params["arguments"])
+		resp["result"] = map[string]any{
+			"content": []any{map[string]any{"type": "text", "text": fmt.Sprintf("%s %s", params["name"], args)}},
+			"isError": params["name"] != "create_issue",
+		}
+	default:
+		resp["error"] = map[string]any{"code": -32601, "message": "method not found"}
+	}
+	return resp
+}
+
+func fakeStdioServer(t *testing.T, tools ...string) *StdioServer {
+	t.Helper()
+	t.Setenv("PROBE_HELPER_VALUE", "1")
+	server, err := Stdio(
+		WithName("fake"),
+		WithCommand(os.Args[0]),
+		WithEnvPlaceholder(probeHelperEnv, "${PROBE_HELPER_VALUE}"),
+		WithEnabledTools(tools...),
+	)
+	if err != nil {
+		t.Fatalf("Stdio() error = %v", err)
+	}
+	return server
+}
+
+func TestProbe_Stdio(t *testing.T) {
+	result, err := Probe(fakeStdioServer(t, "create_issue"), WithProbeTimeout(10*time.Second))
+	if err != nil {
+		t.Fatalf("Probe() error = %v", err)
+	}
+	if result.ServerName != "fake" || result.ServerVersion != "0.1.0" {
+		t.Errorf("server info = %q %q, want fake 0.1.0", result.ServerName, result.ServerVersion)
+	}
+	if want := []string{"create_issue", "list_repos"}; !reflect.DeepEqual(result.Tools, want) {
+		t.Errorf("Tools = %v, want %v", result.Tools, want)
+	}
+}
+
+func TestProbe_StdioMissingTool(t *testing.T) {
+	_, err := Probe(fakeStdioServer(t, "create_issue", "delete_repo"), WithProbeTimeout(10*time.Second))
+	if err == nil || !strings.Contains(err.Error(), "delete_repo") {
+		t.Errorf("Probe() error = %v, want missing tool delete_repo", err)
+	}
+}
+
+func TestProbe_StdioCommandNotFound(t *testing.T) {
+	server, err := Stdio(WithName("broken"), WithCommand("stigmer-no-such-mcp-server"))
+	if err != nil {
+		t.Fatalf("Stdio() error = %v", err)
+	}
+	_, err = Probe(server)
+	if err == nil || !strings.Contains(err.Error(), "probe broken: failed to start") {
+		t.Errorf("Probe() error = %v, want start failure", err)
+	}
+}
+
+func TestProbe_StdioNotMCP(t *testing.T) {
+	server, err := Stdio(WithName("echo"), WithCommand("true"))
+	if err != nil {
+		t.Fatalf("Stdio() error = %v", err)
+	}
+	_, err = Probe(server, WithProbeTimeout(5*time.Second))
+	if err == nil {
+		t.Error("Probe() expected error for a command that is not an MCP server")
+	}
+}
+
+func TestProbe_HTTP(t *testing.T) {
+	t.Setenv("PROBE_API_TOKEN", "secret")
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		var req rpcMessage
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.ID == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		if req.Method == "initialize" {
+			w.Header().Set("Mcp-Session-Id", "session-1")
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(fakeResponse(req))
+			return
+		}
+		if r.Header.Get("Mcp-Session-Id") != "session-1" {
+			http.Error(w, "missing session", http.StatusBadRequest)
+			return
+		}
+		// Answer tools/list as an event stream
+		data, _ := json.Marshal(fakeResponse(req))
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+	}))
+	defer ts.Close()
+
+	server, err := HTTP(
+		WithName("api"),
+		WithURL(ts.URL),
+		WithHeader("Authorization", "Bearer ${PROBE_API_TOKEN}"),
+	)
+	if err != nil {
+		t.Fatalf("HTTP() error = %v", err)
+	}
+	result, err := Probe(server)
+	if err != nil {
+		t.Fatalf("Probe() error = %v", err)
+	}
+	if len(result.Tools) != 2 {
+		t.Errorf("Tools = %v, want 2 tools", result.Tools)
+	}
+}
+
+func TestProbe_HTTPLegacySSE(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: endpoint\ndata: /messages\n\n")
+	}))
+	defer ts.Close()
+
+	server, err := HTTP(WithName("legacy"), WithURL(ts.URL))
+	if err != nil {
+		t.Fatalf("HTTP() error = %v", err)
+	}
+	result, err := Probe(server)
+	if err != nil {
+		t.Fatalf("Probe() error = %v", err)
+	}
+	if result.Tools != nil {
+		t.Errorf("Tools = %v, want nil for legacy SSE", result.Tools)
+	}
+}
+
+func TestProbe_HTTPError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	server, err := HTTP(WithName("api"), WithURL(ts.URL))
+	if err != nil {
+		t.Fatalf("HTTP() error = %v", err)
+	}
+	_, err = Probe(server)
+	if err == nil || !strings.Contains(err.Error(), "500") {
+		t.Errorf("Probe() error = %v, want HTTP 500", err)
+	}
+}
+
+func TestDockerRunArgs(t *testing.T) {
+	t.Setenv("PROBE_API_KEY", "k")
+	server, err := Docker(
+		WithName("custom"),
+		WithImage("ghcr.io/org/mcp:latest"),
+		WithArgs("--verbose"),
+		WithEnvPlaceholder("API_KEY", "${PROBE_API_KEY}"),
+		WithVolumeMount("/data", "/mnt/data", true),
+		WithNetwork("mcp"),
+	)
+	if err != nil {
+		t.Fatalf("Docker() error = %v", err)
+	}
+	want := []string{
+		"run", "-i", "--rm",
+		"-e", "API_KEY",
+		"-v", "/data:/mnt/data:ro",
+		"--network", "mcp",
+		"ghcr.io/org/mcp:latest", "--verbose",
+	}
+	if got := dockerRunArgs(server); !reflect.DeepEqual(got, want) {
+		t.Errorf("dockerRunArgs() = %v, want %v", got, want)
+	}
+
+	// The value reaches docker through its environment, not argv
+	env := expandedEnv(server.EnvPlaceholders())
+	if got := env[len(env)-1]; got != "API_KEY=k" {
+		t.Errorf("expandedEnv() last entry = %q, want API_KEY=k", got)
+	}
+}
+
+func TestConnect_CallTool(t *testing.T) {
+	session, err := Connect(fakeStdioServer(t), WithProbeTimeout(10*time.Second))
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer session.Close()
+
+	tools, err := session.ListTools()
+	if err != nil {
+		t.Fatalf("ListTools() error = %v", err)
+	}
+	if len(tools) != 2 || tools[0].Name != "create_issue" {
+		t.Errorf("ListTools() = %+v, want create_issue and list_repos", tools)
+	}
+
+	result, err := session.CallTool("create_issue", map[string]any{"title": "bug"})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if result.Text != `create_issue {"title":"bug"}` || result.IsError {
+		t.Errorf("CallTool() = %+v", result)
+	}
+
+	result, err = session.CallTool("list_repos", nil)
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if !result.IsError {
+		t.Errorf("CallTool() = %+v, want IsError", result)
+	}
+}
+
+// TestCommandTransport_CanceledCall verifies a request abandoned on context
+// expiry is no longer tracked.
+func TestCommandTransport_CanceledCall(t *testing.T) {
+	session, err := Connect(fakeStdioServer(t), WithProbeTimeout(10*time.Second))
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer session.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := session.request(ctx, "test/unanswered", nil, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("request() error = %v, want context.DeadlineExceeded", err)
+	}
+
+	transport := session.transport.(*commandTransport)
+	transport.mu.Lock()
+	pending := len(transport.pending)
+	transport.mu.Unlock()
+	if pending != 0 {
+		t.Errorf("pending requests = %d, want 0", pending)
+	}
+}
+
+// TestConnect_HTTPConcurrentCalls verifies an HTTP session can be used from
+// several goroutines (run with -race).
+func TestConnect_HTTPConcurrentCalls(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpcMessage
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.ID == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.Header().Set("Mcp-Session-Id", "session-1")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(fakeResponse(req))
+	}))
+	defer ts.Close()
+
+	server, err := HTTP(WithName("api"), WithURL(ts.URL))
+	if err != nil {
+		t.Fatalf("HTTP() error = %v", err)
+	}
+	session, err := Connect(server, WithProbeTimeout(10*time.Second))
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer session.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := session.CallTool("create_issue", map[string]any{"title": "bug"}); err != nil {
+				t.Errorf("CallTool() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
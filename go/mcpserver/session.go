@@ -0,0 +1,511 @@
+package mcpserver
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// mcpProtocolVersion is the MCP protocol version sent in the handshake.
+const mcpProtocolVersion = "2025-03-26"
+
+// Session is a live connection to an MCP server running on the local machine.
+//
+// Sessions are for development tooling such as Probe and local dry runs; the
+// platform starts its own servers from the synthesized manifest.
+type Session struct {
+	// ServerName and ServerVersion are reported by the server itself
+	ServerName    string
+	ServerVersion string
+
+	server    MCPServer
+	timeout   time.Duration
+	transport transport
+
+	mu     sync.Mutex
+	nextID int
+}
+
+// Tool describes a tool offered by an MCP server.
+type Tool struct {
+	Name        string
+	Description string
+
+	// InputSchema is the JSON Schema of the tool's arguments
+	InputSchema map[string]any
+}
+
+// ToolResult is the outcome of a tool call.
+type ToolResult struct {
+	// Text joins the text content returned by the tool
+	Text string
+
+	// IsError reports a failure inside the tool (as opposed to a protocol error)
+	IsError bool
+}
+
+// transport carries JSON-RPC messages to a server.
+type transport interface {
+	// call sends a request and waits for its response; notifications
+	// (requests without an id) return an empty message
+	call(ctx context.Context, req rpcMessage) (rpcMessage, error)
+	close() error
+}
+
+// Connect starts a server (Stdio or Docker) or opens an HTTP session, and runs
+// the MCP handshake. The probe timeout applies to the handshake and to each
+// later request. Close the session to stop the server.
+//
+// Env placeholders, header values and query parameters are expanded from the
+// local environment.
+//
+// Example:
+//
+//	session, err := mcpserver.Connect(github)
+//	if err != nil {
+//	    return err
+//	}
+//	defer session.Close()
+//	result, err := session.CallTool("list_repos", map[string]any{"org": "acme"})
+func Connect(server MCPServer, opts ...ProbeOption) (*Session, error) {
+	cfg := newProbeConfig(opts)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.timeout)
+	defer cancel()
+
+	session, err := connect(ctx, server, cfg.timeout)
+	if err != nil {
+		return nil, fmt.Errorf("connect %s: %w", server.Name(), err)
+	}
+	return session, nil
+}
+
+// connect starts the transport for a server and runs the handshake.
+func connect(ctx context.Context, server MCPServer, timeout time.Duration) (*Session, error) {
+	if err := server.Validate(); err != nil {
+		return nil, err
+	}
+
+	var t transport
+	var err error
+	switch s := server.(type) {
+	case *StdioServer:
+		cmd := exec.Command(s.Command(), s.Args()...)
+		cmd.Dir = s.WorkingDir()
+		cmd.Env = expandedEnv(s.EnvPlaceholders())
+		t, err = startCommand(cmd)
+
+	case *DockerServer:
+		// Values are passed through the environment so secrets stay out of argv
+		cmd := exec.Command("docker", dockerRunArgs(s)...)
+		cmd.Env = expandedEnv(s.EnvPlaceholders())
+		t, err = startCommand(cmd)
+
+	case *HTTPServer:
+		t, err = newHTTPTransport(s)
+
+	default:
+		return nil, fmt.Errorf("unsupported server type %T", server)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	session := &Session{server: server, timeout: timeout, transport: t}
+	if err := session.initialize(ctx); err != nil {
+		t.close()
+		return nil, err
+	}
+	return session, nil
+}
+
+// initialize runs the MCP handshake.
+func (s *Session) initialize(ctx context.Context) error {
+	var result struct {
+		ServerInfo struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"serverInfo"`
+	}
+	params := map[string]any{
+		"protocolVersion": mcpProtocolVersion,
+		"capabilities":    map[string]any{},
+		"clientInfo":      map[string]any{"name": "stigmer-sdk", "version": "1.0.0"},
+	}
+	if err := s.request(ctx, "initialize", params, &result); err != nil {
+		return err
+	}
+	s.ServerName = result.ServerInfo.Name
+	s.ServerVersion = result.ServerInfo.Version
+
+	_, err := s.transport.call(ctx, rpcMessage{JSONRPC: "2.0", Method: "notifications/initialized"})
+	return err
+}
+
+// ListTools returns the tools the server offers, including tools not listed
+// in WithEnabledTools.
+func (s *Session) ListTools() ([]Tool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	return s.listTools(ctx)
+}
+
+func (s *Session) listTools(ctx context.Context) ([]Tool, error) {
+	var result struct {
+		Tools []struct {
+			Name        string         `json:"name"`
+			Description string         `json:"description"`
+			InputSchema map[string]any `json:"inputSchema"`
+		} `json:"tools"`
+	}
+	if err := s.request(ctx, "tools/list", map[string]any{}, &result); err != nil {
+		return nil, err
+	}
+	tools := make([]Tool, 0, len(result.Tools))
+	for _, t := range result.Tools {
+		tools = append(tools, Tool{Name: t.Name, Description: t.Description, InputSchema: t.InputSchema})
+	}
+	return tools, nil
+}
+
+// CallTool calls a tool with the given arguments.
+func (s *Session) CallTool(name string, args map[string]any) (*ToolResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	var result struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+		IsError bool `json:"isError"`
+	}
+	params := map[string]any{"name": name, "arguments": args}
+	if err := s.request(ctx, "tools/call", params, &result); err != nil {
+		return nil, fmt.Errorf("call %s/%s: %w", s.server.Name(), name, err)
+	}
+
+	var texts []string
+	for _, c := range result.Content {
+		if c.Type == "text" {
+			texts = append(texts, c.Text)
+		}
+	}
+	return &ToolResult{Text: strings.Join(texts, "\n"), IsError: result.IsError}, nil
+}
+
+// Close ends the session and stops the server process, if any.
+func (s *Session) Close() error {
+	return s.transport.close()
+}
+
+// request sends a request and decodes its result into target.
+func (s *Session) request(ctx context.Context, method string, params, target any) error {
+	s.mu.Lock()
+	s.nextID++
+	id := s.nextID
+	s.mu.Unlock()
+
+	msg, err := s.transport.call(ctx, rpcMessage{JSONRPC: "2.0", ID: &id, Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	if msg.Error != nil {
+		return fmt.Errorf("%s: server error %d: %s", method, msg.Error.Code, msg.Error.Message)
+	}
+	if err := json.Unmarshal(msg.Result, target); err != nil {
+		return fmt.Errorf("invalid %s result: %w", method, err)
+	}
+	return nil
+}
+
+// rpcMessage is a JSON-RPC 2.0 request, notification or response.
+type rpcMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *int            `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  any             `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// commandTransport talks to a server process over stdin/stdout.
+type commandTransport struct {
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	stderr    *syncBuffer
+	responses chan rpcMessage
+	done      chan struct{}
+
+	mu      sync.Mutex
+	writeMu sync.Mutex
+	pending map[int]chan rpcMessage
+}
+
+// startCommand starts a server process and routes its responses by id.
+func startCommand(cmd *exec.Cmd) (*commandTransport, error) {
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	t := &commandTransport{
+		cmd:     cmd,
+		stdin:   stdin,
+		stderr:  &syncBuffer{},
+		done:    make(chan struct{}),
+		pending: map[int]chan rpcMessage{},
+	}
+	cmd.Stderr = t.stderr
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", cmd.Path, err)
+	}
+
+	// Servers may log to stdout; only responses with a pending id are kept
+	go func() {
+		defer close(t.done)
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+		for scanner.Scan() {
+			var msg rpcMessage
+			if json.Unmarshal(scanner.Bytes(), &msg) != nil || msg.ID == nil || msg.Method != "" {
+				continue
+			}
+			t.mu.Lock()
+			ch := t.pending[*msg.ID]
+			delete(t.pending, *msg.ID)
+			t.mu.Unlock()
+			if ch != nil {
+				ch <- msg
+			}
+		}
+	}()
+	return t, nil
+}
+
+func (t *commandTransport) call(ctx context.Context, req rpcMessage) (rpcMessage, error) {
+	var ch chan rpcMessage
+	if req.ID != nil {
+		ch = make(chan rpcMessage, 1)
+		t.mu.Lock()
+		t.pending[*req.ID] = ch
+		t.mu.Unlock()
+	}
+
+	t.writeMu.Lock()
+	err := json.NewEncoder(t.stdin).Encode(req)
+	t.writeMu.Unlock()
+	if err != nil {
+		t.forget(req.ID)
+		return rpcMessage{}, fmt.Errorf("failed to send %s: %w%s", req.Method, err, stderrTail(t.stderr))
+	}
+	if ch == nil {
+		return rpcMessage{}, nil
+	}
+
+	select {
+	case msg := <-ch:
+		return msg, nil
+	case <-t.done:
+		return rpcMessage{}, fmt.Errorf("server exited during %s%s", req.Method, stderrTail(t.stderr))
+	case <-ctx.Done():
+		// A late response is dropped by the reader like any unknown id
+		t.forget(req.ID)
+		return rpcMessage{}, ctx.Err()
+	}
+}
+
+// forget stops waiting for the response to a request.
+func (t *commandTransport) forget(id *int) {
+	if id == nil {
+		return
+	}
+	t.mu.Lock()
+	delete(t.pending, *id)
+	t.mu.Unlock()
+}
+
+func (t *commandTransport) close() error {
+	t.stdin.Close()
+	_ = t.cmd.Process.Kill()
+	_ = t.cmd.Wait()
+	return nil
+}
+
+// httpTransport talks to a server over streamable HTTP.
+type httpTransport struct {
+	server *HTTPServer
+	url    string
+
+	mu        sync.Mutex
+	sessionID string // Assigned by the server in its response to initialize
+}
+
+// errLegacySSE signals an endpoint that rejects POST, as servers using the
+// legacy HTTP+SSE transport do. Probe falls back to probeSSE for them.
+var errLegacySSE = errors.New("endpoint does not accept POST (legacy HTTP+SSE transport)")
+
+func newHTTPTransport(s *HTTPServer) (*httpTransport, error) {
+	url := os.ExpandEnv(s.URL())
+	if len(s.QueryParams()) > 0 {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		query := req.URL.Query()
+		for k, v := range s.QueryParams() {
+			query.Set(k, os.ExpandEnv(v))
+		}
+		req.URL.RawQuery = query.Encode()
+		url = req.URL.String()
+	}
+	return &httpTransport{server: s, url: url}, nil
+}
+
+// setHeaders sets the server's configured headers with placeholders expanded.
+func (t *httpTransport) setHeaders(req *http.Request) {
+	for k, v := range t.server.Headers() {
+		req.Header.Set(k, os.ExpandEnv(v))
+	}
+}
+
+func (t *httpTransport) call(ctx context.Context, req rpcMessage) (rpcMessage, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return rpcMessage{}, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(body))
+	if err != nil {
+		return rpcMessage{}, err
+	}
+	t.setHeaders(httpReq)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json, text/event-stream")
+	if id := t.session(); id != "" {
+		httpReq.Header.Set("Mcp-Session-Id", id)
+	}
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return rpcMessage{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusMethodNotAllowed && req.Method == "initialize" {
+		return rpcMessage{}, errLegacySSE
+	}
+	if resp.StatusCode >= 300 {
+		return rpcMessage{}, fmt.Errorf("%s returned %s", req.Method, resp.Status)
+	}
+	if id := resp.Header.Get("Mcp-Session-Id"); id != "" {
+		t.mu.Lock()
+		t.sessionID = id
+		t.mu.Unlock()
+	}
+	if req.ID == nil {
+		return rpcMessage{}, nil
+	}
+	return readHTTPResponse(resp, *req.ID)
+}
+
+// session returns the session id assigned by the server, if any.
+func (t *httpTransport) session() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.sessionID
+}
+
+func (t *httpTransport) close() error {
+	id := t.session()
+	if id == "" {
+		return nil
+	}
+	req, err := http.NewRequest(http.MethodDelete, t.url, nil)
+	if err != nil {
+		return err
+	}
+	t.setHeaders(req)
+	req.Header.Set("Mcp-Session-Id", id)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// readHTTPResponse reads a JSON or event-stream response to request id.
+func readHTTPResponse(resp *http.Response, id int) (rpcMessage, error) {
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		var msg rpcMessage
+		if err := json.NewDecoder(resp.Body).Decode(&msg); err != nil {
+			return rpcMessage{}, fmt.Errorf("invalid response: %w", err)
+		}
+		return msg, nil
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+		var msg rpcMessage
+		if json.Unmarshal([]byte(strings.TrimSpace(data)), &msg) != nil || msg.ID == nil || *msg.ID != id {
+			continue
+		}
+		return msg, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return rpcMessage{}, err
+	}
+	return rpcMessage{}, io.ErrUnexpectedEOF
+}
+
+// syncBuffer collects a server's stderr while it is being read.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// stderrTail formats the end of a server's stderr for an error message.
+func stderrTail(stderr *syncBuffer) string {
+	out := strings.TrimSpace(stderr.String())
+	if out == "" {
+		return ""
+	}
+	const limit = 500
+	if len(out) > limit {
+		out = "..." + out[len(out)-limit:]
+	}
+	return "\nstderr: " + out
+}
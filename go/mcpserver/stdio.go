@@ -2,6 +2,8 @@ package mcpserver
 
 import (
 	"fmt"
+
+	"github.com/leftbin/stigmer-sdk/go/resources"
 )
 
 // StdioServer represents a stdio-based MCP server that runs as a subprocess.
@@ -21,6 +23,7 @@ type StdioServer struct {
 	args            []string
 	envPlaceholders map[string]string
 	workingDir      string
+	resources       resources.Requirements
 }
 
 // Stdio creates a new stdio-based MCP server with the given options.
@@ -73,6 +76,11 @@ func (s *StdioServer) WorkingDir() string {
 	return s.workingDir
 }
 
+// Resources returns the requested CPU and memory.
+func (s *StdioServer) Resources() resources.Requirements {
+	return s.resources
+}
+
 // Type returns the server type (stdio).
 func (s *StdioServer) Type() ServerType {
 	return TypeStdio
@@ -86,5 +94,8 @@ func (s *StdioServer) Validate() error {
 	if s.command == "" {
 		return fmt.Errorf("stdio server %q: command is required", s.name)
 	}
+	if err := s.resources.Validate(); err != nil {
+		return fmt.Errorf("stdio server %q: %w", s.name, err)
+	}
 	return nil
 }
@@ -0,0 +1,21 @@
+// Package resources declares the CPU and memory that agents, MCP servers and
+// workflows need at runtime.
+//
+// Sizing is declared next to the blueprint so every instance starts with the
+// right resources instead of being configured per instance after deployment:
+//
+//	agent.New(ctx,
+//	    agent.WithName("code-reviewer"),
+//	    agent.WithResources("500m", "1Gi"),
+//	)
+//
+//	mcpserver.Docker(
+//	    mcpserver.WithName("custom"),
+//	    mcpserver.WithImage("ghcr.io/org/mcp:latest"),
+//	    mcpserver.WithResources("250m", "256Mi"),
+//	)
+//
+// Quantities use the Kubernetes notation: CPU in cores ("2", "0.5") or
+// millicores ("500m"), memory in bytes with an optional suffix ("512Mi",
+// "2Gi", "1G"). Either value may be left empty to keep the platform default.
+package resources
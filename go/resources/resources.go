@@ -0,0 +1,72 @@
+package resources
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+var (
+	// ErrInvalidCPU is returned when a CPU quantity is malformed.
+	ErrInvalidCPU = errors.New("invalid CPU quantity")
+
+	// ErrInvalidMemory is returned when a memory quantity is malformed.
+	ErrInvalidMemory = errors.New("invalid memory quantity")
+)
+
+// cpuRegex matches CPU quantities in cores or millicores (e.g., "2", "0.5", "500m").
+var cpuRegex = regexp.MustCompile(`^(\d+m|\d+(\.\d+)?)$`)
+
+// memoryRegex matches memory quantities with binary or decimal suffixes (e.g., "512Mi", "1G").
+var memoryRegex = regexp.MustCompile(`^\d+(\.\d+)?(Ki|Mi|Gi|Ti|k|K|M|G|T)?$`)
+
+// Requirements is the CPU and memory requested for a runtime.
+// Empty fields keep the platform default.
+type Requirements struct {
+	// CPU in cores or millicores (e.g., "500m", "2")
+	CPU string `json:"cpu,omitempty"`
+
+	// Memory in bytes with an optional suffix (e.g., "512Mi", "2Gi")
+	Memory string `json:"memory,omitempty"`
+}
+
+// IsZero reports whether no resources were requested.
+func (r Requirements) IsZero() bool {
+	return r.CPU == "" && r.Memory == ""
+}
+
+// Validate checks that the quantities are well formed and not zero.
+func (r Requirements) Validate() error {
+	if r.CPU != "" && (!cpuRegex.MatchString(r.CPU) || isZero(r.CPU)) {
+		return fmt.Errorf("%w %q: use cores (e.g., \"2\", \"0.5\") or millicores (e.g., \"500m\")", ErrInvalidCPU, r.CPU)
+	}
+	if r.Memory != "" && (!memoryRegex.MatchString(r.Memory) || isZero(r.Memory)) {
+		return fmt.Errorf("%w %q: use bytes with a suffix (e.g., \"512Mi\", \"2Gi\")", ErrInvalidMemory, r.Memory)
+	}
+	return nil
+}
+
+// String returns a readable form, e.g. "cpu=500m memory=1Gi".
+func (r Requirements) String() string {
+	s := ""
+	if r.CPU != "" {
+		s = "cpu=" + r.CPU
+	}
+	if r.Memory != "" {
+		if s != "" {
+			s += " "
+		}
+		s += "memory=" + r.Memory
+	}
+	return s
+}
+
+// isZero reports whether a well-formed quantity has no non-zero digit.
+func isZero(quantity string) bool {
+	for _, c := range quantity {
+		if c >= '1' && c <= '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,43 @@
+package resources
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRequirements_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     Requirements
+		wantErr error
+	}{
+		{"empty", Requirements{}, nil},
+		{"millicores", Requirements{CPU: "500m", Memory: "1Gi"}, nil},
+		{"fractional cores", Requirements{CPU: "0.5"}, nil},
+		{"decimal memory", Requirements{Memory: "1G"}, nil},
+		{"unit typo", Requirements{CPU: "500mc"}, ErrInvalidCPU},
+		{"zero cpu", Requirements{CPU: "0m"}, ErrInvalidCPU},
+		{"lowercase mebibytes", Requirements{Memory: "512mi"}, ErrInvalidMemory},
+		{"zero memory", Requirements{Memory: "0Gi"}, ErrInvalidMemory},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("Validate() error = %v, want nil", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRequirements_String(t *testing.T) {
+	if got := (Requirements{CPU: "500m", Memory: "1Gi"}).String(); got != "cpu=500m memory=1Gi" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := (Requirements{Memory: "1Gi"}).String(); got != "memory=1Gi" {
+		t.Errorf("String() = %q", got)
+	}
+}
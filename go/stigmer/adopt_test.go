@@ -0,0 +1,57 @@
+package stigmer
+
+import (
+	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/agent"
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+func TestContext_Adopt(t *testing.T) {
+	ctx := NewContext()
+
+	wf, err := workflow.Build(
+		workflow.WithNamespace("sync"),
+		workflow.WithName("nightly"),
+	)
+	if err != nil {
+		t.Fatalf("workflow.Build() error = %v", err)
+	}
+	ag, err := agent.Build(
+		agent.WithName("reviewer"),
+		agent.WithInstructions("Review code and suggest improvements"),
+	)
+	if err != nil {
+		t.Fatalf("agent.Build() error = %v", err)
+	}
+
+	if len(ctx.Workflows()) != 0 || len(ctx.Agents()) != 0 {
+		t.Fatal("Build() should not register resources")
+	}
+
+	if err := ctx.Adopt(wf, ag); err != nil {
+		t.Fatalf("Adopt() error = %v", err)
+	}
+	if got := ctx.Workflows(); len(got) != 1 || got[0] != wf {
+		t.Errorf("Workflows() = %v, want adopted workflow", got)
+	}
+	if got := ctx.Agents(); len(got) != 1 || got[0] != ag {
+		t.Errorf("Agents() = %v, want adopted agent", got)
+	}
+
+	if err := ctx.Adopt(wf); err == nil {
+		t.Error("Adopt() of an already registered workflow should fail")
+	}
+	if err := ctx.Adopt("not a resource"); err == nil {
+		t.Error("Adopt() of an unsupported type should fail")
+	}
+}
+
+func TestBuild_Validates(t *testing.T) {
+	if _, err := workflow.Build(workflow.WithName("missing-namespace")); err == nil {
+		t.Error("workflow.Build() without namespace should fail")
+	}
+	if _, err := agent.Build(agent.WithName("no-instructions")); err == nil {
+		t.Error("agent.Build() without instructions should fail")
+	}
+}
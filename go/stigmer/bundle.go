@@ -0,0 +1,253 @@
+package stigmer
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	agentv1 "buf.build/gen/go/leftbin/stigmer/protocolbuffers/go/ai/stigmer/agentic/agent/v1"
+	workflowv1 "buf.build/gen/go/leftbin/stigmer/protocolbuffers/go/ai/stigmer/agentic/workflow/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// Manifest file names written to STIGMER_OUT_DIR.
+const (
+	// AgentManifestFileName is the agent manifest written by the split layout.
+	AgentManifestFileName = "agent-manifest.pb"
+
+	// WorkflowManifestFileName is the workflow manifest written by the split layout.
+	WorkflowManifestFileName = "workflow-manifest.pb"
+
+	// BundleFileName is the single file written by the bundle layout.
+	BundleFileName = "manifest-bundle.tar"
+
+	// bundleIndexFileName is the index entry inside a bundle.
+	bundleIndexFileName = "index.json"
+
+	// bundleFormatVersion is bumped when the bundle layout changes incompatibly.
+	bundleFormatVersion = 1
+)
+
+// ManifestLayout selects how synthesized manifests are written to disk.
+type ManifestLayout string
+
+const (
+	// ManifestLayoutSplit writes agent-manifest.pb and workflow-manifest.pb
+	// as separate files. This is the default.
+	ManifestLayoutSplit ManifestLayout = "split"
+
+	// ManifestLayoutBundle writes a single manifest-bundle.tar containing an
+	// index.json and both manifests.
+	ManifestLayoutBundle ManifestLayout = "bundle"
+
+	// ManifestLayoutPerResource writes one manifest per agent and workflow,
+	// e.g. agents/code-reviewer.pb and workflows/demo.basic-data-fetch.pb, so
+	// large repositories get smaller diffs and can upload only what changed.
+	ManifestLayoutPerResource ManifestLayout = "per-resource"
+)
+
+// manifestLayoutEnv overrides the layout when no WithManifestLayout option is given,
+// so the CLI can request a layout without changing the program.
+const manifestLayoutEnv = "STIGMER_MANIFEST_LAYOUT"
+
+// ErrInvalidBundle is returned when a manifest bundle cannot be read.
+var ErrInvalidBundle = errors.New("invalid manifest bundle")
+
+// WithManifestLayout selects how manifests are written during synthesis.
+//
+// Example:
+//
+//	stigmer.Run(func(ctx *stigmer.Context) error {
+//	    // ... define agents and workflows
+//	    return nil
+//	}, stigmer.WithManifestLayout(stigmer.ManifestLayoutBundle))
+func WithManifestLayout(layout ManifestLayout) ContextOption {
+	return func(c *Context) {
+		c.manifestLayout = layout
+	}
+}
+
+// resolveManifestLayout returns the configured layout, falling back to the
+// STIGMER_MANIFEST_LAYOUT environment variable and then the split layout.
+func (c *Context) resolveManifestLayout() (ManifestLayout, error) {
+	layout := c.manifestLayout
+	if layout == "" {
+		layout = ManifestLayout(os.Getenv(manifestLayoutEnv))
+	}
+	switch layout {
+	case "":
+		return ManifestLayoutSplit, nil
+	case ManifestLayoutSplit, ManifestLayoutBundle, ManifestLayoutPerResource:
+		return layout, nil
+	default:
+		return "", fmt.Errorf("unknown manifest layout %q (want %q, %q or %q)",
+			layout, ManifestLayoutSplit, ManifestLayoutBundle, ManifestLayoutPerResource)
+	}
+}
+
+// BundleEntryKind identifies the manifest stored in a bundle entry.
+type BundleEntryKind string
+
+const (
+	BundleEntryAgent    BundleEntryKind = "agent"
+	BundleEntryWorkflow BundleEntryKind = "workflow"
+)
+
+// BundleIndex describes the contents of a manifest bundle.
+type BundleIndex struct {
+	// Version is the bundle format version
+	Version int `json:"version"`
+
+	// Entries lists the manifests in the bundle
+	Entries []BundleEntry `json:"entries"`
+}
+
+// BundleEntry describes one manifest stored in a bundle.
+type BundleEntry struct {
+	// Kind is the manifest type (agent or workflow)
+	Kind BundleEntryKind `json:"kind"`
+
+	// Path is the entry name inside the bundle
+	Path string `json:"path"`
+
+	// Resources names the agents or workflows (namespace/name) in the manifest
+	Resources []string `json:"resources,omitempty"`
+
+	// SHA256 is the hex-encoded digest of the manifest bytes
+	SHA256 string `json:"sha256"`
+}
+
+// Bundle is a decoded manifest bundle.
+type Bundle struct {
+	Index            BundleIndex
+	AgentManifest    *agentv1.AgentManifest       // nil if the bundle has no agents
+	WorkflowManifest *workflowv1.WorkflowManifest // nil if the bundle has no workflows
+}
+
+// manifestFile is a serialized manifest waiting to be written.
+type manifestFile struct {
+	kind      BundleEntryKind
+	path      string
+	resources []string
+	data      []byte
+}
+
+// encodeBundle encodes the manifests and their index as a single tar file.
+func encodeBundle(files []manifestFile) ([]byte, error) {
+	index := BundleIndex{Version: bundleFormatVersion}
+	for _, f := range files {
+		sum := sha256.Sum256(f.data)
+		index.Entries = append(index.Entries, BundleEntry{
+			Kind:      f.kind,
+			Path:      f.path,
+			Resources: f.resources,
+			SHA256:    hex.EncodeToString(sum[:]),
+		})
+	}
+	indexData, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode bundle index: %w", err)
+	}
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	// The index comes first so readers can inspect it without scanning the archive
+	entries := append([]manifestFile{{path: bundleIndexFileName, data: indexData}}, files...)
+	for _, f := range entries {
+		if err := writeTarEntry(tw, f.path, f.data); err != nil {
+			return nil, fmt.Errorf("failed to write bundle entry %s: %w", f.path, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// writeTarEntry adds a file to a tar archive with a fixed timestamp, so
+// identical programs produce identical archives.
+func writeTarEntry(tw *tar.Writer, path string, data []byte) error {
+	hdr := &tar.Header{
+		Name:    path,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: time.Unix(0, 0),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// ReadBundle reads a manifest bundle written with ManifestLayoutBundle and
+// verifies each manifest against the digest recorded in the index.
+func ReadBundle(path string) (*Bundle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	contents := make(map[string][]byte)
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("%w: reading %s: %v", ErrInvalidBundle, hdr.Name, err)
+		}
+		contents[hdr.Name] = data
+	}
+
+	indexData, ok := contents[bundleIndexFileName]
+	if !ok {
+		return nil, fmt.Errorf("%w: missing %s", ErrInvalidBundle, bundleIndexFileName)
+	}
+	bundle := &Bundle{}
+	if err := json.Unmarshal(indexData, &bundle.Index); err != nil {
+		return nil, fmt.Errorf("%w: decoding index: %v", ErrInvalidBundle, err)
+	}
+	if bundle.Index.Version != bundleFormatVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidBundle, bundle.Index.Version)
+	}
+
+	for _, entry := range bundle.Index.Entries {
+		data, ok := contents[entry.Path]
+		if !ok {
+			return nil, fmt.Errorf("%w: missing entry %s", ErrInvalidBundle, entry.Path)
+		}
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != entry.SHA256 {
+			return nil, fmt.Errorf("%w: digest mismatch for %s", ErrInvalidBundle, entry.Path)
+		}
+
+		switch entry.Kind {
+		case BundleEntryAgent:
+			bundle.AgentManifest = &agentv1.AgentManifest{}
+			err = proto.Unmarshal(data, bundle.AgentManifest)
+		case BundleEntryWorkflow:
+			bundle.WorkflowManifest = &workflowv1.WorkflowManifest{}
+			err = proto.Unmarshal(data, bundle.WorkflowManifest)
+		default:
+			err = fmt.Errorf("unknown entry kind %q", entry.Kind)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidBundle, entry.Path, err)
+		}
+	}
+	return bundle, nil
+}
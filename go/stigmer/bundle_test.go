@@ -0,0 +1,140 @@
+package stigmer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	workflowv1 "buf.build/gen/go/leftbin/stigmer/protocolbuffers/go/ai/stigmer/agentic/workflow/v1"
+	"github.com/leftbin/stigmer-sdk/go/agent"
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+	"google.golang.org/protobuf/proto"
+)
+
+// defineBundleResources registers one agent and one workflow on the context.
+func defineBundleResources(ctx *Context) error {
+	if _, err := agent.New(ctx,
+		agent.WithName("bundle-agent"),
+		agent.WithInstructions("Test instructions for agent"),
+	); err != nil {
+		return err
+	}
+	wf, err := workflow.New(ctx,
+		workflow.WithNamespace("test"),
+		workflow.WithName("bundle-workflow"),
+	)
+	if err != nil {
+		return err
+	}
+	wf.SetVars("init", "status", "ready")
+	return nil
+}
+
+func TestSynthesize_SplitLayoutDefault(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("STIGMER_OUT_DIR", dir)
+	t.Setenv(manifestLayoutEnv, "")
+
+	if err := Run(defineBundleResources); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	for _, name := range []string{AgentManifestFileName, WorkflowManifestFileName} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("expected %s: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, BundleFileName)); !os.IsNotExist(err) {
+		t.Errorf("split layout should not write %s", BundleFileName)
+	}
+}
+
+func TestSynthesize_BundleLayout(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("STIGMER_OUT_DIR", dir)
+
+	if err := Run(defineBundleResources, WithManifestLayout(ManifestLayoutBundle)); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, AgentManifestFileName)); !os.IsNotExist(err) {
+		t.Errorf("bundle layout should not write %s", AgentManifestFileName)
+	}
+
+	bundle, err := ReadBundle(filepath.Join(dir, BundleFileName))
+	if err != nil {
+		t.Fatalf("ReadBundle() error = %v", err)
+	}
+	if len(bundle.Index.Entries) != 2 {
+		t.Fatalf("index has %d entries, want 2", len(bundle.Index.Entries))
+	}
+	if got := bundle.Index.Entries[1].Resources; len(got) != 1 || got[0] != "test/bundle-workflow" {
+		t.Errorf("workflow entry resources = %v, want [test/bundle-workflow]", got)
+	}
+	if bundle.AgentManifest == nil || len(bundle.AgentManifest.Agents) != 1 {
+		t.Errorf("AgentManifest = %v, want one agent", bundle.AgentManifest)
+	}
+	if bundle.WorkflowManifest == nil || len(bundle.WorkflowManifest.Workflows) != 1 {
+		t.Errorf("WorkflowManifest = %v, want one workflow", bundle.WorkflowManifest)
+	}
+}
+
+func TestSynthesize_UnknownLayout(t *testing.T) {
+	t.Setenv("STIGMER_OUT_DIR", t.TempDir())
+	t.Setenv(manifestLayoutEnv, "zip")
+
+	if err := Run(defineBundleResources); err == nil {
+		t.Error("Run() should fail for an unknown manifest layout")
+	}
+}
+
+func TestReadBundle_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), BundleFileName)
+	if err := os.WriteFile(path, []byte("not a bundle"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadBundle(path); !errors.Is(err, ErrInvalidBundle) {
+		t.Errorf("ReadBundle() error = %v, want ErrInvalidBundle", err)
+	}
+}
+
+func TestSynthesize_PerResourceLayout(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("STIGMER_OUT_DIR", dir)
+
+	// A manifest left by a previous run for a resource that no longer exists
+	stale := filepath.Join(dir, WorkflowManifestDir, "test.removed-workflow.pb")
+	if err := os.MkdirAll(filepath.Dir(stale), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(stale, []byte("stale"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Run(defineBundleResources, WithManifestLayout(ManifestLayoutPerResource)); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, AgentManifestFileName)); !os.IsNotExist(err) {
+		t.Errorf("per-resource layout should not write %s", AgentManifestFileName)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("stale manifest %s was not removed", stale)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, WorkflowManifestDir, "test.bundle-workflow.pb"))
+	if err != nil {
+		t.Fatalf("expected workflow manifest: %v", err)
+	}
+	manifest := &workflowv1.WorkflowManifest{}
+	if err := proto.Unmarshal(data, manifest); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if len(manifest.Workflows) != 1 || manifest.SdkMetadata == nil {
+		t.Errorf("workflow manifest = %v, want one workflow with SDK metadata", manifest)
+	}
+	if _, err := os.Stat(filepath.Join(dir, AgentManifestDir, "bundle-agent.pb")); err != nil {
+		t.Errorf("expected agent manifest: %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package stigmer
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// ChecksumFileName lists the SHA-256 digest of every manifest written, in
+	// the format read by sha256sum -c.
+	ChecksumFileName = "manifest.sha256"
+
+	// SignatureFileName holds the base64-encoded Ed25519 signature of
+	// manifest.sha256, written when a signing key is configured.
+	SignatureFileName = "manifest.sha256.sig"
+)
+
+// signingKeyEnv provides the signing key when no WithSigningKey option is
+// given: a PEM-encoded PKCS#8 Ed25519 private key, or the path to one.
+const signingKeyEnv = "STIGMER_SIGNING_KEY"
+
+// ErrChecksumMismatch is returned by VerifyManifests when a manifest or the
+// checksum file does not match.
+var ErrChecksumMismatch = errors.New("manifest checksum mismatch")
+
+// WithSigningKey signs manifest.sha256 with key during synthesis, so the
+// platform can verify the manifests came from a trusted build. The signature
+// is written to manifest.sha256.sig.
+//
+// The key can also be given with STIGMER_SIGNING_KEY, either as a PEM-encoded
+// PKCS#8 private key or as the path to one.
+//
+// Example:
+//
+//	stigmer.Run(func(ctx *stigmer.Context) error {
+//	    // ... define agents and workflows
+//	    return nil
+//	}, stigmer.WithSigningKey(key))
+func WithSigningKey(key ed25519.PrivateKey) ContextOption {
+	return func(c *Context) {
+		c.signingKey = key
+	}
+}
+
+// resolveSigningKey returns the configured key, falling back to the
+// STIGMER_SIGNING_KEY environment variable. It returns nil when manifests
+// should not be signed.
+func (c *Context) resolveSigningKey() (ed25519.PrivateKey, error) {
+	if c.signingKey != nil {
+		return c.signingKey, nil
+	}
+	value := os.Getenv(signingKeyEnv)
+	if value == "" {
+		return nil, nil
+	}
+
+	data := []byte(value)
+	if !strings.HasPrefix(strings.TrimSpace(value), "-----BEGIN") {
+		var err error
+		if data, err = os.ReadFile(value); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", signingKeyEnv, err)
+		}
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not a PEM-encoded key", signingKeyEnv)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", signingKeyEnv, err)
+	}
+	key, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s must be an Ed25519 key, got %T", signingKeyEnv, parsed)
+	}
+	return key, nil
+}
+
+// checksumFiles returns manifest.sha256 for the written manifests and, when
+// key is not nil, its signature.
+func checksumFiles(files []manifestFile, key ed25519.PrivateKey) []manifestFile {
+	sums := make([]manifestFile, len(files))
+	copy(sums, files)
+	sort.Slice(sums, func(i, j int) bool { return sums[i].path < sums[j].path })
+
+	var buf bytes.Buffer
+	for _, f := range sums {
+		sum := sha256.Sum256(f.data)
+		fmt.Fprintf(&buf, "%s  %s\n", hex.EncodeToString(sum[:]), filepath.ToSlash(f.path))
+	}
+	result := []manifestFile{{path: ChecksumFileName, data: buf.Bytes()}}
+	if key != nil {
+		sig := base64.StdEncoding.EncodeToString(ed25519.Sign(key, buf.Bytes()))
+		result = append(result, manifestFile{path: SignatureFileName, data: []byte(sig + "\n")})
+	}
+	return result
+}
+
+// removeStaleSignature removes the signature a previous signed run left in
+// outputDir; it no longer matches the checksums.
+func removeStaleSignature(outputDir string) error {
+	err := os.Remove(filepath.Join(outputDir, SignatureFileName))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove stale manifest signature: %w", err)
+	}
+	return nil
+}
+
+// VerifyManifests checks the manifests in dir against manifest.sha256 and,
+// when publicKey is not nil, checks manifest.sha256.sig as well.
+func VerifyManifests(dir string, publicKey ed25519.PublicKey) error {
+	data, err := os.ReadFile(filepath.Join(dir, ChecksumFileName))
+	if err != nil {
+		return err
+	}
+
+	if publicKey != nil {
+		encoded, err := os.ReadFile(filepath.Join(dir, SignatureFileName))
+		if err != nil {
+			return err
+		}
+		sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+		if err != nil || !ed25519.Verify(publicKey, data, sig) {
+			return fmt.Errorf("%w: invalid signature for %s", ErrChecksumMismatch, ChecksumFileName)
+		}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		want, name, ok := strings.Cut(scanner.Text(), "  ")
+		if !ok {
+			return fmt.Errorf("%w: malformed line %q", ErrChecksumMismatch, scanner.Text())
+		}
+		manifest, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(manifest)
+		if hex.EncodeToString(sum[:]) != want {
+			return fmt.Errorf("%w: %s", ErrChecksumMismatch, name)
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,69 @@
+package stigmer
+
+import "strings"
+
+// Child returns a context scoped to name, for grouping the variables and
+// resources of one module in a larger program.
+//
+// A child context shares its root context's state, so everything created
+// through it is synthesized with the root. Within the child:
+//   - Variables are stored as "<scope>_<name>" (e.g. "billing_apiURL"), so
+//     modules can use the same short names without colliding. Get looks in
+//     the child first and then in enclosing contexts.
+//   - Workflows and agents registered through the child are renamed to
+//     "<scope>-<name>" (e.g. "billing-process-invoice").
+//
+// Children can be nested: ctx.Child("billing").Child("invoices") has scope
+// "billing-invoices". Use lowercase letters, digits and hyphens in names.
+// Inspection methods (Variables, Workflows, Agents, Snapshot) report the
+// root context's state, and only the root context can be synthesized.
+//
+// Use references returned by the Set* methods rather than ${name}
+// placeholders inside a child, since placeholders use the unscoped name.
+//
+// Example:
+//
+//	stigmer.Run(func(ctx *stigmer.Context) error {
+//	    if err := billing.Define(ctx.Child("billing")); err != nil {
+//	        return err
+//	    }
+//	    return shipping.Define(ctx.Child("shipping"))
+//	})
+//
+//	// In package billing
+//	func Define(ctx *stigmer.Context) error {
+//	    apiURL := ctx.SetString("apiURL", "https://billing.example.com")  // Stored as "billing_apiURL"
+//	    wf, err := workflow.New(ctx, workflow.WithName("process-invoice"), ...)  // Registered as "billing-process-invoice"
+//	    ...
+//	}
+func (c *Context) Child(name string) *Context {
+	scope := name
+	if c.parent != nil {
+		scope = c.scope + "-" + name
+	}
+	return &Context{parent: c, scope: scope}
+}
+
+// Scope returns the full name of a child context, or "" for a root context.
+func (c *Context) Scope() string {
+	return c.scope
+}
+
+// root returns the context that owns the state shared by c and its children.
+func (c *Context) root() *Context {
+	for c.parent != nil {
+		c = c.parent
+	}
+	return c
+}
+
+// scopedVariable returns the name a variable created in c is stored under.
+// Hyphens become underscores so the name stays a valid JQ identifier.
+func (c *Context) scopedVariable(name string) string {
+	return strings.ReplaceAll(c.scope, "-", "_") + "_" + name
+}
+
+// scopedResource returns the name a workflow or agent registered through c gets.
+func (c *Context) scopedResource(name string) string {
+	return c.scope + "-" + name
+}
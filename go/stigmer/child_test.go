@@ -0,0 +1,68 @@
+package stigmer
+
+import (
+	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/agent"
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+func TestContext_ChildVariables(t *testing.T) {
+	ctx := NewContext()
+	ctx.SetString("region", "eu-west-1")
+	ctx.SetString("apiURL", "https://api.example.com")
+
+	billing := ctx.Child("billing")
+	apiURL := billing.SetString("apiURL", "https://billing.example.com")
+	invoices := billing.Child("invoices")
+	invoices.SetInt("retries", 3)
+
+	if apiURL.Name() != "billing_apiURL" {
+		t.Errorf("child variable name = %q, want billing_apiURL", apiURL.Name())
+	}
+	if got := ctx.GetString("apiURL").Value(); got != "https://api.example.com" {
+		t.Errorf("root apiURL = %q, want root value", got)
+	}
+	if got := billing.GetString("apiURL").Value(); got != "https://billing.example.com" {
+		t.Errorf("child apiURL = %q, want child value", got)
+	}
+	if got := invoices.GetString("region"); got == nil || got.Value() != "eu-west-1" {
+		t.Errorf("nested child should see root region, got %v", got)
+	}
+	if invoices.Scope() != "billing-invoices" {
+		t.Errorf("Scope() = %q, want billing-invoices", invoices.Scope())
+	}
+	if _, ok := ctx.Variables()["billing_invoices_retries"]; !ok {
+		t.Errorf("root Variables() = %v, want billing_invoices_retries", ctx.Variables())
+	}
+}
+
+func TestContext_ChildResources(t *testing.T) {
+	ctx := NewContext()
+	billing := ctx.Child("billing")
+
+	if _, err := workflow.New(billing,
+		workflow.WithNamespace("finance"),
+		workflow.WithName("process-invoice"),
+	); err != nil {
+		t.Fatalf("workflow.New() error = %v", err)
+	}
+	if _, err := agent.New(billing,
+		agent.WithName("auditor"),
+		agent.WithInstructions("Audit every invoice for policy violations"),
+	); err != nil {
+		t.Fatalf("agent.New() error = %v", err)
+	}
+
+	workflows := ctx.Workflows()
+	if len(workflows) != 1 || workflows[0].Document.Name != "billing-process-invoice" {
+		t.Errorf("root workflows = %v, want billing-process-invoice", workflows)
+	}
+	agents := billing.Agents()
+	if len(agents) != 1 || agents[0].Name != "billing-auditor" {
+		t.Errorf("agents = %v, want billing-auditor", agents)
+	}
+	if err := billing.Synthesize(); err == nil {
+		t.Error("Synthesize() on a child context should fail")
+	}
+}
@@ -0,0 +1,82 @@
+package stigmer
+
+import (
+	"math/rand"
+	"time"
+
+	agentv1 "buf.build/gen/go/leftbin/stigmer/protocolbuffers/go/ai/stigmer/agentic/agent/v1"
+	"github.com/google/uuid"
+)
+
+// WithClock sets the clock that stamps SdkMetadata.GeneratedAt, so golden
+// tests can compare manifests byte for byte. Use FixedClock for a constant time.
+//
+// Example:
+//
+//	ctx := stigmer.NewContext(stigmer.WithClock(stigmer.FixedClock(time.Unix(0, 0))))
+func WithClock(now func() time.Time) ContextOption {
+	return func(c *Context) {
+		c.clock = now
+	}
+}
+
+// WithIDGenerator sets the generator of the IDs synthesis assigns (the skill
+// IDs of agent manifests), so golden tests produce stable manifests. Use
+// SeededIDs for a reproducible sequence.
+//
+// Example:
+//
+//	ctx := stigmer.NewContext(stigmer.WithIDGenerator(stigmer.SeededIDs(1)))
+func WithIDGenerator(next func() string) ContextOption {
+	return func(c *Context) {
+		c.idGenerator = next
+	}
+}
+
+// FixedClock returns a clock that always reports t.
+func FixedClock(t time.Time) func() time.Time {
+	return func() time.Time { return t }
+}
+
+// SeededIDs returns a generator of random UUIDs that yields the same sequence
+// for the same seed. It is meant for tests; the IDs are not unpredictable.
+func SeededIDs(seed int64) func() string {
+	rng := rand.New(rand.NewSource(seed))
+	return func() string {
+		id, err := uuid.NewRandomFromReader(rng)
+		if err != nil {
+			// rand.Rand reads never fail
+			panic(err)
+		}
+		return id.String()
+	}
+}
+
+// stampManifests replaces the generation time and IDs assigned during
+// conversion with those of the configured clock and ID generator.
+func (c *Context) stampManifests(m *Manifests) {
+	if c.clock != nil {
+		generatedAt := c.clock().Unix()
+		if meta := m.AgentManifest.GetSdkMetadata(); meta != nil {
+			meta.GeneratedAt = generatedAt
+		}
+		if meta := m.WorkflowManifest.GetSdkMetadata(); meta != nil {
+			meta.GeneratedAt = generatedAt
+		}
+	}
+
+	if c.idGenerator == nil {
+		return
+	}
+	assign := func(skills []*agentv1.ManifestSkill) {
+		for _, s := range skills {
+			s.Id = c.idGenerator()
+		}
+	}
+	for _, ag := range m.AgentManifest.GetAgents() {
+		assign(ag.GetSkills())
+		for _, sub := range ag.GetSubAgents() {
+			assign(sub.GetInline().GetSkills())
+		}
+	}
+}
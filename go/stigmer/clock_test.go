@@ -0,0 +1,55 @@
+package stigmer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/leftbin/stigmer-sdk/go/agent"
+	"github.com/leftbin/stigmer-sdk/go/skill"
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestManifests_DeterministicClockAndIDs(t *testing.T) {
+	fixed := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	synthesize := func() *Manifests {
+		ctx := NewContext(WithClock(FixedClock(fixed)), WithIDGenerator(SeededIDs(42)))
+		if _, err := agent.New(ctx,
+			agent.WithName("golden-agent"),
+			agent.WithInstructions("Test instructions for agent"),
+			agent.WithSkills(skill.Platform("coding"), skill.Platform("review")),
+		); err != nil {
+			t.Fatalf("agent.New() error = %v", err)
+		}
+		wf, err := workflow.New(ctx, workflow.WithNamespace("test"), workflow.WithName("golden-workflow"))
+		if err != nil {
+			t.Fatalf("workflow.New() error = %v", err)
+		}
+		wf.SetVars("init", "status", "ready")
+
+		m, err := ctx.Manifests()
+		if err != nil {
+			t.Fatalf("Manifests() error = %v", err)
+		}
+		return m
+	}
+
+	first, second := synthesize(), synthesize()
+	if !proto.Equal(first.AgentManifest, second.AgentManifest) {
+		t.Error("agent manifests differ between runs")
+	}
+	if !proto.Equal(first.WorkflowManifest, second.WorkflowManifest) {
+		t.Error("workflow manifests differ between runs")
+	}
+
+	if got := first.AgentManifest.GetSdkMetadata().GetGeneratedAt(); got != fixed.Unix() {
+		t.Errorf("agent GeneratedAt = %d, want %d", got, fixed.Unix())
+	}
+	if got := first.WorkflowManifest.GetSdkMetadata().GetGeneratedAt(); got != fixed.Unix() {
+		t.Errorf("workflow GeneratedAt = %d, want %d", got, fixed.Unix())
+	}
+	skills := first.AgentManifest.GetAgents()[0].GetSkills()
+	if len(skills) != 2 || skills[0].GetId() == skills[1].GetId() {
+		t.Errorf("skill IDs = %v, want two distinct IDs", skills)
+	}
+}
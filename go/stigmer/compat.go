@@ -0,0 +1,91 @@
+package stigmer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// LegacyManifestFileName is the agent manifest name read by CLIs that predate
+// the split agent-manifest.pb / workflow-manifest.pb layout.
+const LegacyManifestFileName = "manifest.pb"
+
+// LegacyManifestMode controls whether the legacy manifest.pb is written
+// alongside agent-manifest.pb.
+type LegacyManifestMode string
+
+const (
+	// LegacyManifestCopy writes manifest.pb as a copy of agent-manifest.pb.
+	// This is the default so older CLIs keep working during migration.
+	LegacyManifestCopy LegacyManifestMode = "copy"
+
+	// LegacyManifestSymlink links manifest.pb to agent-manifest.pb, falling
+	// back to a copy where symlinks are not supported.
+	LegacyManifestSymlink LegacyManifestMode = "symlink"
+
+	// LegacyManifestOff does not write manifest.pb.
+	LegacyManifestOff LegacyManifestMode = "off"
+)
+
+// legacyManifestEnv overrides the legacy manifest mode when no WithLegacyManifest
+// option is given.
+const legacyManifestEnv = "STIGMER_LEGACY_MANIFEST"
+
+// WithLegacyManifest controls whether manifest.pb is written for older CLIs.
+// It only applies to the split layout; bundles and per-resource manifests are
+// never read by older CLIs.
+//
+// Example:
+//
+//	stigmer.Run(func(ctx *stigmer.Context) error {
+//	    // ... define agents
+//	    return nil
+//	}, stigmer.WithLegacyManifest(stigmer.LegacyManifestOff))
+func WithLegacyManifest(mode LegacyManifestMode) ContextOption {
+	return func(c *Context) {
+		c.legacyManifest = mode
+	}
+}
+
+// resolveLegacyManifestMode returns the configured mode, falling back to the
+// STIGMER_LEGACY_MANIFEST environment variable and then LegacyManifestCopy.
+func (c *Context) resolveLegacyManifestMode() (LegacyManifestMode, error) {
+	mode := c.legacyManifest
+	if mode == "" {
+		mode = LegacyManifestMode(os.Getenv(legacyManifestEnv))
+	}
+	switch mode {
+	case "":
+		return LegacyManifestCopy, nil
+	case LegacyManifestCopy, LegacyManifestSymlink, LegacyManifestOff:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown legacy manifest mode %q (want %q, %q or %q)",
+			mode, LegacyManifestCopy, LegacyManifestSymlink, LegacyManifestOff)
+	}
+}
+
+// writeLegacyManifest writes manifest.pb next to agent-manifest.pb according to mode.
+func writeLegacyManifest(outputDir string, mode LegacyManifestMode, data []byte) error {
+	if mode == LegacyManifestOff {
+		return nil
+	}
+
+	path := filepath.Join(outputDir, LegacyManifestFileName)
+	// Remove a stale file or link from a previous run so the new one always wins
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to replace legacy manifest: %w", err)
+	}
+
+	if mode == LegacyManifestSymlink {
+		// Relative target keeps the link valid if the output directory is moved
+		if err := os.Symlink(AgentManifestFileName, path); err == nil {
+			return nil
+		}
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write legacy manifest: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package stigmer
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSynthesize_LegacyManifest(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []ContextOption
+		wantLegacy  bool
+		wantSymlink bool
+	}{
+		{name: "default copies", wantLegacy: true},
+		{name: "copy", opts: []ContextOption{WithLegacyManifest(LegacyManifestCopy)}, wantLegacy: true},
+		{name: "symlink", opts: []ContextOption{WithLegacyManifest(LegacyManifestSymlink)}, wantLegacy: true, wantSymlink: true},
+		{name: "off", opts: []ContextOption{WithLegacyManifest(LegacyManifestOff)}},
+		{name: "bundle layout skips legacy", opts: []ContextOption{WithManifestLayout(ManifestLayoutBundle)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Setenv("STIGMER_OUT_DIR", dir)
+			t.Setenv(manifestLayoutEnv, "")
+			t.Setenv(legacyManifestEnv, "")
+
+			if err := Run(defineBundleResources, tt.opts...); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			legacyPath := filepath.Join(dir, LegacyManifestFileName)
+			info, err := os.Lstat(legacyPath)
+			if !tt.wantLegacy {
+				if !os.IsNotExist(err) {
+					t.Errorf("expected no %s, got err = %v", LegacyManifestFileName, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected %s: %v", LegacyManifestFileName, err)
+			}
+			if isLink := info.Mode()&os.ModeSymlink != 0; isLink != tt.wantSymlink {
+				t.Errorf("symlink = %v, want %v", isLink, tt.wantSymlink)
+			}
+
+			legacy, _ := os.ReadFile(legacyPath)
+			current, _ := os.ReadFile(filepath.Join(dir, AgentManifestFileName))
+			if !bytes.Equal(legacy, current) {
+				t.Errorf("%s does not match %s", LegacyManifestFileName, AgentManifestFileName)
+			}
+		})
+	}
+}
+
+func TestSynthesize_UnknownLegacyManifestMode(t *testing.T) {
+	t.Setenv("STIGMER_OUT_DIR", t.TempDir())
+	t.Setenv(manifestLayoutEnv, "")
+	t.Setenv(legacyManifestEnv, "hardlink")
+
+	if err := Run(defineBundleResources); err == nil {
+		t.Error("Run() should fail for an unknown legacy manifest mode")
+	}
+}
@@ -1,14 +1,20 @@
 package stigmer
 
 import (
+	"crypto/ed25519"
 	"fmt"
+	"io"
+	"iter"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
+	"time"
 
 	"google.golang.org/protobuf/proto"
 
 	"github.com/leftbin/stigmer-sdk/go/agent"
+	"github.com/leftbin/stigmer-sdk/go/environment"
 	"github.com/leftbin/stigmer-sdk/go/internal/synth"
 	"github.com/leftbin/stigmer-sdk/go/workflow"
 )
@@ -40,20 +46,74 @@ type Context struct {
 	// agents tracks all agents created in this context
 	agents []*agent.Agent
 
+	// sharedEnvVars are attached to every workflow and agent registered with this context
+	sharedEnvVars []environment.Variable
+
+	// declaredEnvVars are declared once via environment.Declare() and attached explicitly
+	declaredEnvVars map[string]environment.Variable
+
 	// mu protects concurrent access to context state
 	mu sync.RWMutex
 
+	// manifestLayout selects how manifests are written (set by WithManifestLayout)
+	manifestLayout ManifestLayout
+
+	// legacyManifest controls the manifest.pb compatibility file (set by WithLegacyManifest)
+	legacyManifest LegacyManifestMode
+
+	// strictVariables fails synthesis on unused variables (set by WithStrictVariables)
+	strictVariables bool
+
+	// yamlExport writes workflows as Serverless Workflow YAML (set by WithYAMLExport)
+	yamlExport bool
+
+	// platform is the platform manifests must be compatible with (set by
+	// WithTargetPlatformVersion or WithPlatformCapabilities)
+	platform platformTarget
+
+	// filter selects the resources to synthesize (set by WithInclude and WithExclude)
+	filter resourceFilter
+
+	// signingKey signs manifest.sha256 (set by WithSigningKey)
+	signingKey ed25519.PrivateKey
+
+	// sink and writer receive the synthesized files instead of STIGMER_OUT_DIR
+	// (set by WithSink and WithWriter)
+	sink   ManifestSink
+	writer io.Writer
+
+	// clock and idGenerator replace the time and IDs assigned during
+	// synthesis (set by WithClock and WithIDGenerator)
+	clock       func() time.Time
+	idGenerator func() string
+
+	// dryRunReport receives the JSON report of a dry run (set by WithDryRunReport)
+	dryRunReport io.Writer
+
+	// diagnostics is the format of errors and warnings on stderr (set by WithDiagnostics)
+	diagnostics DiagnosticsFormat
+
+	// locations maps "<kind>/<name>" to the file:line that registered the resource
+	locations map[string]string
+
 	// synthesized tracks whether synthesis has been performed
 	synthesized bool
+
+	// parent is the enclosing context of a child created with Child(); nil for a root context
+	parent *Context
+
+	// scope is the full name of a child context (e.g. "billing-invoices")
+	scope string
 }
 
 // newContext creates a new Context instance.
 // This is internal - users should use Run() instead.
 func newContext() *Context {
 	return &Context{
-		variables: make(map[string]Ref),
-		workflows: make([]*workflow.Workflow, 0),
-		agents:    make([]*agent.Agent, 0),
+		variables:       make(map[string]Ref),
+		workflows:       make([]*workflow.Workflow, 0),
+		agents:          make([]*agent.Agent, 0),
+		declaredEnvVars: make(map[string]environment.Variable),
 	}
 }
 
@@ -64,8 +124,38 @@ func newContext() *Context {
 //
 //	ctx := stigmer.NewContext()
 //	apiURL := ctx.SetString("apiURL", "https://api.example.com")
-func NewContext() *Context {
-	return newContext()
+func NewContext(opts ...ContextOption) *Context {
+	ctx := newContext()
+	for _, opt := range opts {
+		opt(ctx)
+	}
+	return ctx
+}
+
+// ContextOption configures a Context created by NewContext() or Run().
+type ContextOption func(*Context)
+
+// WithSharedEnvVars attaches a standard set of environment variables to every
+// workflow and agent registered with the context.
+//
+// Variables already declared by a workflow or agent take precedence over shared
+// variables with the same name.
+//
+// Example:
+//
+//	otelEndpoint, _ := environment.New(
+//	    environment.WithName("OTEL_EXPORTER_OTLP_ENDPOINT"),
+//	    environment.WithDefaultValue("http://otel-collector:4317"),
+//	)
+//
+//	stigmer.Run(func(ctx *stigmer.Context) error {
+//	    // Every workflow and agent created here declares OTEL_EXPORTER_OTLP_ENDPOINT
+//	    return nil
+//	}, stigmer.WithSharedEnvVars(otelEndpoint))
+func WithSharedEnvVars(vars ...environment.Variable) ContextOption {
+	return func(c *Context) {
+		c.sharedEnvVars = append(c.sharedEnvVars, vars...)
+	}
 }
 
 // =============================================================================
@@ -80,17 +170,16 @@ func NewContext() *Context {
 //
 //	apiURL := ctx.SetString("apiURL", "https://api.example.com")
 //	// In task config: "${apiURL}/users" → synthesizes to: "https://api.example.com/users"
-func (c *Context) SetString(name, value string) *StringRef {
+func (c *Context) SetString(name, value string, opts ...VariableOption) *StringRef {
+	if c.parent != nil {
+		return c.root().SetString(c.scopedVariable(name), value, opts...)
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	ref := &StringRef{
-		baseRef: baseRef{
-			name:     name,
-			isSecret: false,
-		},
-		value: value,
-	}
+	ref := &StringRef{variable(name, false, value)}
+	applyVariableOptions(&ref.baseRef, opts)
 	c.variables[name] = ref
 	return ref
 }
@@ -103,17 +192,16 @@ func (c *Context) SetString(name, value string) *StringRef {
 //
 //	apiKey := ctx.SetSecret("apiKey", "secret-key-123")
 //	// In headers: "Bearer ${apiKey}" → synthesizes to: "Bearer secret-key-123"
-func (c *Context) SetSecret(name, value string) *StringRef {
+func (c *Context) SetSecret(name, value string, opts ...VariableOption) *StringRef {
+	if c.parent != nil {
+		return c.root().SetSecret(c.scopedVariable(name), value, opts...)
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	ref := &StringRef{
-		baseRef: baseRef{
-			name:     name,
-			isSecret: true,
-		},
-		value: value,
-	}
+	ref := &StringRef{variable(name, true, value)}
+	applyVariableOptions(&ref.baseRef, opts)
 	c.variables[name] = ref
 	return ref
 }
@@ -125,17 +213,37 @@ func (c *Context) SetSecret(name, value string) *StringRef {
 //
 //	retries := ctx.SetInt("retries", 3)
 //	// In config: {"max_retries": "${retries}"} → synthesizes to: {"max_retries": 3}
-func (c *Context) SetInt(name string, value int) *IntRef {
+func (c *Context) SetInt(name string, value int, opts ...VariableOption) *IntRef {
+	if c.parent != nil {
+		return c.root().SetInt(c.scopedVariable(name), value, opts...)
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	ref := &IntRef{
-		baseRef: baseRef{
-			name:     name,
-			isSecret: false,
-		},
-		value: value,
+	ref := &IntRef{variable(name, false, value)}
+	applyVariableOptions(&ref.baseRef, opts)
+	c.variables[name] = ref
+	return ref
+}
+
+// SetFloat creates a floating-point variable in the context and returns a typed reference.
+// The variable is resolved at synthesis time (compile-time).
+//
+// Example:
+//
+//	threshold := ctx.SetFloat("threshold", 0.75)
+//	// In config: {"min_score": "${threshold}"} → synthesizes to: {"min_score": 0.75}
+func (c *Context) SetFloat(name string, value float64, opts ...VariableOption) *FloatRef {
+	if c.parent != nil {
+		return c.root().SetFloat(c.scopedVariable(name), value, opts...)
 	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ref := &FloatRef{variable(name, false, value)}
+	applyVariableOptions(&ref.baseRef, opts)
 	c.variables[name] = ref
 	return ref
 }
@@ -147,17 +255,58 @@ func (c *Context) SetInt(name string, value int) *IntRef {
 //
 //	isProd := ctx.SetBool("isProd", true)
 //	// In config: {"production": "${isProd}"} → synthesizes to: {"production": true}
-func (c *Context) SetBool(name string, value bool) *BoolRef {
+func (c *Context) SetBool(name string, value bool, opts ...VariableOption) *BoolRef {
+	if c.parent != nil {
+		return c.root().SetBool(c.scopedVariable(name), value, opts...)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ref := &BoolRef{variable(name, false, value)}
+	applyVariableOptions(&ref.baseRef, opts)
+	c.variables[name] = ref
+	return ref
+}
+
+// SetDuration creates a duration variable in the context and returns a typed reference.
+// The variable is resolved at synthesis time (compile-time).
+//
+// Example:
+//
+//	timeout := ctx.SetDuration("timeout", 30*time.Second)
+//	// workflow.Timeout(timeout) → synthesizes to a 30 second timeout
+func (c *Context) SetDuration(name string, value time.Duration, opts ...VariableOption) *DurationRef {
+	if c.parent != nil {
+		return c.root().SetDuration(c.scopedVariable(name), value, opts...)
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	ref := &BoolRef{
-		baseRef: baseRef{
-			name:     name,
-			isSecret: false,
-		},
-		value: value,
+	ref := &DurationRef{variable(name, false, value)}
+	applyVariableOptions(&ref.baseRef, opts)
+	c.variables[name] = ref
+	return ref
+}
+
+// SetTime creates a point-in-time variable in the context and returns a typed reference.
+// The time is stored in UTC as an RFC 3339 timestamp with second precision.
+//
+// Example:
+//
+//	launch := ctx.SetTime("launchAt", time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC))
+//	workflow.WaitTask("waitForLaunch", workflow.WithUntilExpression(launch))
+func (c *Context) SetTime(name string, value time.Time, opts ...VariableOption) *TimeRef {
+	if c.parent != nil {
+		return c.root().SetTime(c.scopedVariable(name), value, opts...)
 	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ref := &TimeRef{variable(name, false, value.UTC().Truncate(time.Second))}
+	applyVariableOptions(&ref.baseRef, opts)
 	c.variables[name] = ref
 	return ref
 }
@@ -174,17 +323,37 @@ func (c *Context) SetBool(name string, value bool) *BoolRef {
 //	    },
 //	})
 //	// In config: "${config}" → synthesizes to: {"database": {"host": "localhost", "port": 5432}}
-func (c *Context) SetObject(name string, value map[string]interface{}) *ObjectRef {
+func (c *Context) SetObject(name string, value map[string]interface{}, opts ...VariableOption) *ObjectRef {
+	if c.parent != nil {
+		return c.root().SetObject(c.scopedVariable(name), value, opts...)
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	ref := &ObjectRef{
-		baseRef: baseRef{
-			name:     name,
-			isSecret: false,
-		},
-		value: value,
+	ref := &ObjectRef{variable(name, false, value)}
+	applyVariableOptions(&ref.baseRef, opts)
+	c.variables[name] = ref
+	return ref
+}
+
+// SetList creates a list variable in the context and returns a typed reference.
+// The variable is resolved at synthesis time (compile-time).
+//
+// Example:
+//
+//	regions := ctx.SetList("regions", []interface{}{"us-east-1", "eu-west-1"})
+//	// In a FOR task: workflow.WithIn(regions) → iterates over both regions
+func (c *Context) SetList(name string, value []interface{}, opts ...VariableOption) *ListRef {
+	if c.parent != nil {
+		return c.root().SetList(c.scopedVariable(name), value, opts...)
 	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ref := &ListRef{variable(name, false, value)}
+	applyVariableOptions(&ref.baseRef, opts)
 	c.variables[name] = ref
 	return ref
 }
@@ -203,6 +372,14 @@ func (c *Context) SetObject(name string, value map[string]interface{}) *ObjectRe
 //	    // Use the reference
 //	}
 func (c *Context) Get(name string) Ref {
+	if c.parent != nil {
+		// Variables in this scope shadow those of enclosing scopes
+		if ref := c.root().Get(c.scopedVariable(name)); ref != nil {
+			return ref
+		}
+		return c.parent.Get(name)
+	}
+
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -229,6 +406,16 @@ func (c *Context) GetInt(name string) *IntRef {
 	return nil
 }
 
+// GetFloat retrieves a floating-point variable by name.
+// Returns nil if the variable doesn't exist or is not a FloatRef.
+func (c *Context) GetFloat(name string) *FloatRef {
+	ref := c.Get(name)
+	if floatRef, ok := ref.(*FloatRef); ok {
+		return floatRef
+	}
+	return nil
+}
+
 // GetBool retrieves a boolean variable by name.
 // Returns nil if the variable doesn't exist or is not a BoolRef.
 func (c *Context) GetBool(name string) *BoolRef {
@@ -239,6 +426,26 @@ func (c *Context) GetBool(name string) *BoolRef {
 	return nil
 }
 
+// GetDuration retrieves a duration variable by name.
+// Returns nil if the variable doesn't exist or is not a DurationRef.
+func (c *Context) GetDuration(name string) *DurationRef {
+	ref := c.Get(name)
+	if durationRef, ok := ref.(*DurationRef); ok {
+		return durationRef
+	}
+	return nil
+}
+
+// GetTime retrieves a point-in-time variable by name.
+// Returns nil if the variable doesn't exist or is not a TimeRef.
+func (c *Context) GetTime(name string) *TimeRef {
+	ref := c.Get(name)
+	if timeRef, ok := ref.(*TimeRef); ok {
+		return timeRef
+	}
+	return nil
+}
+
 // GetObject retrieves an object variable by name.
 // Returns nil if the variable doesn't exist or is not an ObjectRef.
 func (c *Context) GetObject(name string) *ObjectRef {
@@ -249,6 +456,16 @@ func (c *Context) GetObject(name string) *ObjectRef {
 	return nil
 }
 
+// GetList retrieves a list variable by name.
+// Returns nil if the variable doesn't exist or is not a ListRef.
+func (c *Context) GetList(name string) *ListRef {
+	ref := c.Get(name)
+	if listRef, ok := ref.(*ListRef); ok {
+		return listRef
+	}
+	return nil
+}
+
 // ExportVariables exports all context variables as a map for synthesis.
 // This is used internally during workflow synthesis to pass compile-time
 // variables to the interpolation layer.
@@ -260,6 +477,10 @@ func (c *Context) GetObject(name string) *ObjectRef {
 //
 //	manifest, err := synth.ToWorkflowManifestWithContext(ctx.ExportVariables(), wf)
 func (c *Context) ExportVariables() map[string]interface{} {
+	if c.parent != nil {
+		return c.root().ExportVariables()
+	}
+
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -278,19 +499,140 @@ func (c *Context) ExportVariables() map[string]interface{} {
 // RegisterWorkflow registers a workflow with this context.
 // This is typically called automatically by workflow.New() when passed a context.
 func (c *Context) RegisterWorkflow(wf *workflow.Workflow) {
+	if c.parent != nil {
+		wf.Document.Name = c.scopedResource(wf.Document.Name)
+		c.root().RegisterWorkflow(wf)
+		return
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	wf.EnvironmentVariables = mergeEnvVars(wf.EnvironmentVariables, c.sharedEnvVars)
 	c.workflows = append(c.workflows, wf)
+	c.recordLocation("workflow/" + wf.Document.Name)
 }
 
 // RegisterAgent registers an agent with this context.
 // This is typically called automatically by agent.New() when passed a context.
 func (c *Context) RegisterAgent(ag *agent.Agent) {
+	if c.parent != nil {
+		ag.Name = c.scopedResource(ag.Name)
+		c.root().RegisterAgent(ag)
+		return
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	ag.EnvironmentVariables = mergeEnvVars(ag.EnvironmentVariables, c.sharedEnvVars)
 	c.agents = append(c.agents, ag)
+	c.recordLocation("agent/" + ag.Name)
+}
+
+// recordLocation remembers the code that registered resource, for diagnostics.
+func (c *Context) recordLocation(resource string) {
+	if c.locations == nil {
+		c.locations = make(map[string]string)
+	}
+	if _, ok := c.locations[resource]; !ok {
+		c.locations[resource] = callerLocation()
+	}
+}
+
+// Adopt registers workflows and agents built without a context (see
+// workflow.Build and agent.Build), so they are synthesized with this context.
+//
+// Resources must be *workflow.Workflow or *agent.Agent values, and each can
+// only be registered once.
+//
+// Example:
+//
+//	wf, err := workflow.Build(workflow.WithNamespace("sync"), workflow.WithName("nightly"))
+//	if err != nil {
+//	    return err
+//	}
+//	return ctx.Adopt(wf)
+func (c *Context) Adopt(resources ...any) error {
+	registered := make(map[any]bool)
+	for res := range c.AllResources() {
+		registered[res] = true
+	}
+
+	for _, res := range resources {
+		if registered[res] {
+			return fmt.Errorf("cannot adopt %T: already registered with this context", res)
+		}
+		switch r := res.(type) {
+		case *workflow.Workflow:
+			c.RegisterWorkflow(r)
+		case *agent.Agent:
+			c.RegisterAgent(r)
+		default:
+			return fmt.Errorf("cannot adopt %T: want *workflow.Workflow or *agent.Agent", res)
+		}
+		registered[res] = true
+	}
+	return nil
+}
+
+// DeclareEnvironmentVariable records a context-level environment variable.
+// This is typically called by environment.Declare().
+//
+// Declaring the same name again returns the existing declaration when the
+// settings are identical, and an error when they conflict.
+func (c *Context) DeclareEnvironmentVariable(v environment.Variable) (environment.Variable, error) {
+	if c.parent != nil {
+		return c.root().DeclareEnvironmentVariable(v)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if existing, ok := c.declaredEnvVars[v.Name]; ok {
+		if existing != v {
+			return environment.Variable{}, fmt.Errorf("environment variable %q is already declared with different settings", v.Name)
+		}
+		return existing, nil
+	}
+	c.declaredEnvVars[v.Name] = v
+	return v, nil
+}
+
+// DeclaredEnvironmentVariables returns the variables declared with environment.Declare(), sorted by name.
+func (c *Context) DeclaredEnvironmentVariables() []environment.Variable {
+	if c.parent != nil {
+		return c.root().DeclaredEnvironmentVariables()
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	vars := make([]environment.Variable, 0, len(c.declaredEnvVars))
+	for _, v := range c.declaredEnvVars {
+		vars = append(vars, v)
+	}
+	sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+	return vars
+}
+
+// mergeEnvVars appends shared variables that are not already declared by name.
+func mergeEnvVars(declared, shared []environment.Variable) []environment.Variable {
+	if len(shared) == 0 {
+		return declared
+	}
+
+	names := make(map[string]bool, len(declared))
+	for _, v := range declared {
+		names[v.Name] = true
+	}
+	for _, v := range shared {
+		if !names[v.Name] {
+			declared = append(declared, v)
+			names[v.Name] = true
+		}
+	}
+	return declared
 }
 
 // =============================================================================
@@ -299,26 +641,56 @@ func (c *Context) RegisterAgent(ag *agent.Agent) {
 
 // Synthesize converts all registered workflows and agents to their proto representations
 // and writes them to disk. This is called automatically by Run() when the function completes.
-func (c *Context) Synthesize() error {
+//
+// Only the resources and variables of this context are synthesized. Without
+// STIGMER_OUT_DIR (or WithSink or WithWriter) nothing is written, but the
+// manifests are still built, so conversion errors, hooks and platform checks
+// fail synthesis as usual.
+func (c *Context) Synthesize() (err error) {
+	if c.parent != nil {
+		return fmt.Errorf("child context %q cannot be synthesized; synthesize the root context", c.scope)
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	defer func() {
+		if err != nil {
+			c.reportError(err)
+		}
+	}()
 
 	if c.synthesized {
 		return fmt.Errorf("context already synthesized")
 	}
 
-	// Get output directory from environment variable
-	// If not set, we're in dry-run mode (just validate, don't write files)
-	outputDir := os.Getenv("STIGMER_OUT_DIR")
-	if outputDir == "" {
-		// Dry-run mode: just mark as synthesized
+	if err := c.checkUnusedVariables(); err != nil {
+		return err
+	}
+
+	// Get the output from the options or the environment
+	// If there is none, we're in dry-run mode (just validate, don't write files)
+	sink, outputDir, finish := c.resolveSink()
+	if sink == nil {
+		// Dry-run mode: convert everything so errors surface, but write nothing
+		manifests, err := c.prepareManifests("")
+		if err != nil {
+			return fmt.Errorf("synthesis failed: %w", err)
+		}
+		if err := c.writeDryRunReport(manifests); err != nil {
+			return err
+		}
 		c.synthesized = true
 		return nil
 	}
 
-	// Import synthesis package for converters
-	// We'll call the converters to generate manifests
-	if err := c.synthesizeManifests(outputDir); err != nil {
+	// Convert the context's own agents, workflows and variables and write them
+	if err := c.synthesizeManifests(sink, outputDir); err != nil {
+		return fmt.Errorf("synthesis failed: %w", err)
+	}
+	if err := c.writeWorkflowYAML(sink); err != nil {
+		return fmt.Errorf("synthesis failed: %w", err)
+	}
+	if err := finish(); err != nil {
 		return fmt.Errorf("synthesis failed: %w", err)
 	}
 
@@ -326,92 +698,245 @@ func (c *Context) Synthesize() error {
 	return nil
 }
 
-// synthesizeManifests writes agent and workflow manifests to disk
-func (c *Context) synthesizeManifests(outputDir string) error {
+// synthesizeManifests writes agent and workflow manifests to sink. outputDir is
+// the directory sink writes to, or empty when the output is not a directory.
+func (c *Context) synthesizeManifests(sink ManifestSink, outputDir string) error {
+	layout, err := c.resolveManifestLayout()
+	if err != nil {
+		return err
+	}
+	signingKey, err := c.resolveSigningKey()
+	if err != nil {
+		return err
+	}
+
 	// Ensure output directory exists
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
+	if outputDir != "" {
+		if err := os.MkdirAll(outputDir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
 	}
 
-	// Convert agents to interfaces for the converter
-	var agentInterfaces []interface{}
-	for _, ag := range c.agents {
-		agentInterfaces = append(agentInterfaces, ag)
+	manifests, err := c.prepareManifests(outputDir)
+	if err != nil {
+		return err
+	}
+	if manifests.AgentManifest == nil && manifests.WorkflowManifest == nil {
+		return nil
 	}
 
-	// Convert workflows to interfaces for the converter
-	var workflowInterfaces []interface{}
-	for _, wf := range c.workflows {
-		workflowInterfaces = append(workflowInterfaces, wf)
+	var files []manifestFile
+	if layout == ManifestLayoutPerResource {
+		files, err = perResourceManifestFiles(manifests)
+	} else {
+		files, err = manifestFiles(manifests)
+	}
+	if err != nil {
+		return err
 	}
 
-	// Synthesize agents if any exist
-	if len(agentInterfaces) > 0 {
-		if err := c.synthesizeAgents(outputDir, agentInterfaces); err != nil {
+	legacyMode := LegacyManifestOff
+	switch layout {
+	case ManifestLayoutBundle:
+		// The checksum covers the bundle as written, not the manifests inside it
+		data, err := encodeBundle(files)
+		if err != nil {
+			return err
+		}
+		files = []manifestFile{{path: BundleFileName, data: data}}
+	case ManifestLayoutPerResource:
+		if outputDir != "" {
+			if err := removeStaleManifests(outputDir); err != nil {
+				return err
+			}
+		}
+	default:
+		if legacyMode, err = c.resolveLegacyManifestMode(); err != nil {
 			return err
 		}
 	}
 
-	// Synthesize workflows if any exist
-	if len(workflowInterfaces) > 0 {
-		if err := c.synthesizeWorkflows(outputDir, workflowInterfaces); err != nil {
+	for _, f := range files {
+		if err := sink(filepath.ToSlash(f.path), f.data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.path, err)
+		}
+		if f.kind != BundleEntryAgent || layout == ManifestLayoutPerResource {
+			continue
+		}
+		if outputDir != "" {
+			// Written directly so the symlink mode can link to the agent manifest
+			err = writeLegacyManifest(outputDir, legacyMode, f.data)
+		} else if legacyMode != LegacyManifestOff {
+			err = sink(LegacyManifestFileName, f.data)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write legacy manifest: %w", err)
+		}
+	}
+
+	for _, f := range checksumFiles(files, signingKey) {
+		if err := sink(f.path, f.data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.path, err)
+		}
+	}
+	if signingKey == nil && outputDir != "" {
+		if err := removeStaleSignature(outputDir); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	return runHooks("after-synth", &afterSynthHooks, manifests)
 }
 
-// synthesizeAgents converts agents to protobuf and writes to disk
-func (c *Context) synthesizeAgents(outputDir string, agentInterfaces []interface{}) error {
-	// Convert agents to manifest proto
-	manifest, err := synth.ToManifest(agentInterfaces...)
+// prepareManifests builds the manifests, runs the before-synth hooks and checks
+// them against the target platform and the proto constraints.
+func (c *Context) prepareManifests(outputDir string) (*Manifests, error) {
+	manifests, err := c.buildManifests()
 	if err != nil {
-		return fmt.Errorf("failed to convert agents to manifest: %w", err)
+		return nil, err
 	}
+	if manifests.AgentManifest == nil && manifests.WorkflowManifest == nil {
+		return manifests, nil
+	}
+	manifests.OutputDir = outputDir
 
-	// Serialize to binary protobuf
-	data, err := proto.Marshal(manifest)
-	if err != nil {
-		return fmt.Errorf("failed to serialize agent manifest: %w", err)
+	// Let registered hooks enforce policies or amend the manifests
+	if err := runHooks("before-synth", &beforeSynthHooks, manifests); err != nil {
+		return nil, err
 	}
 
-	// Write to agent-manifest.pb
-	manifestPath := filepath.Join(outputDir, "agent-manifest.pb")
-	if err := os.WriteFile(manifestPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write agent manifest: %w", err)
+	// Refuse features the target platform cannot run
+	if err := c.checkPlatformCompatibility(manifests); err != nil {
+		return nil, err
 	}
 
-	return nil
+	// Check the proto constraints the CLI and server enforce, reporting the offending fields
+	if err := validateManifests(manifests); err != nil {
+		return nil, err
+	}
+	return manifests, nil
 }
 
-// synthesizeWorkflows converts workflows to protobuf and writes to disk
-func (c *Context) synthesizeWorkflows(outputDir string, workflowInterfaces []interface{}) error {
-	// Convert context variables (map[string]Ref) to map[string]interface{} for synthesis
-	contextVars := make(map[string]interface{}, len(c.variables))
-	for name, ref := range c.variables {
-		contextVars[name] = ref
+// Manifests synthesizes the registered agents and workflows in memory and
+// returns the manifests without writing anything, regardless of
+// STIGMER_OUT_DIR. Unused variables, before-synth hooks and the target
+// platform are checked as in Synthesize; after-synth hooks do not run.
+//
+// Unlike Synthesize it can be called any number of times, which suits tests
+// and programs that serve or post-process manifests themselves.
+func (c *Context) Manifests() (*Manifests, error) {
+	if c.parent != nil {
+		return nil, fmt.Errorf("child context %q cannot be synthesized; synthesize the root context", c.scope)
 	}
 
-	// Convert workflows to manifest proto, passing context variables for injection
-	manifest, err := synth.ToWorkflowManifestWithContext(contextVars, workflowInterfaces...)
-	if err != nil {
-		return fmt.Errorf("failed to convert workflows to manifest: %w", err)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.checkUnusedVariables(); err != nil {
+		return nil, err
 	}
+	return c.prepareManifests("")
+}
+
+// buildManifests converts the registered agents and workflows selected by
+// WithInclude and WithExclude (or STIGMER_ONLY) to manifest protos.
+// A manifest is nil when there is nothing of its kind to synthesize.
+//
+// All agents and workflows are converted even if some fail; the failures are
+// returned together as a *SynthesisError.
+func (c *Context) buildManifests() (*Manifests, error) {
+	manifests := &Manifests{}
+	var errs []error
 
-	// Serialize to binary protobuf
-	data, err := proto.Marshal(manifest)
+	agents, workflows, err := c.selectResources()
 	if err != nil {
-		return fmt.Errorf("failed to serialize workflow manifest: %w", err)
+		return nil, err
+	}
+	for _, ag := range agents {
+		manifests.Warnings = append(manifests.Warnings, synth.AgentWarnings(ag)...)
+	}
+	for _, wf := range workflows {
+		manifests.Warnings = append(manifests.Warnings, synth.WorkflowWarnings(wf)...)
+	}
+	for _, w := range manifests.Warnings {
+		field := w.Field
+		if w.Task != "" {
+			field = "tasks." + w.Task + "." + field
+		}
+		c.warn(w.String(), Diagnostic{Code: "lossy_conversion", Resource: w.Kind + "/" + w.Name, Field: field, Message: w.Message})
 	}
 
-	// Write to workflow-manifest.pb
-	manifestPath := filepath.Join(outputDir, "workflow-manifest.pb")
-	if err := os.WriteFile(manifestPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write workflow manifest: %w", err)
+	// Synthesize agents if any exist
+	if len(agents) > 0 {
+		var agentInterfaces []interface{}
+		for _, ag := range agents {
+			agentInterfaces = append(agentInterfaces, ag)
+		}
+		manifest, err := synth.ToManifest(agentInterfaces...)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		manifests.AgentManifest = manifest
 	}
 
-	return nil
+	// Synthesize workflows if any exist
+	if len(workflows) > 0 {
+		var workflowInterfaces []interface{}
+		for _, wf := range workflows {
+			workflowInterfaces = append(workflowInterfaces, wf)
+		}
+
+		// Convert context variables (map[string]Ref) to map[string]interface{} for synthesis
+		contextVars := make(map[string]interface{}, len(c.variables))
+		for name, ref := range c.variables {
+			contextVars[name] = ref
+		}
+
+		// Convert workflows to manifest proto, passing context variables for injection
+		manifest, err := synth.ToWorkflowManifestWithContext(contextVars, workflowInterfaces...)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		manifests.WorkflowManifest = manifest
+	}
+
+	if len(errs) > 0 {
+		return nil, newSynthesisError(errs...)
+	}
+	c.stampManifests(manifests)
+	return manifests, nil
+}
+
+// manifestFiles serializes manifests to binary protobuf, agents first.
+func manifestFiles(m *Manifests) ([]manifestFile, error) {
+	var files []manifestFile
+
+	if m.AgentManifest != nil {
+		data, err := proto.Marshal(m.AgentManifest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize agent manifest: %w", err)
+		}
+		file := manifestFile{kind: BundleEntryAgent, path: AgentManifestFileName, data: data}
+		for _, ag := range m.AgentManifest.GetAgents() {
+			file.resources = append(file.resources, ag.GetName())
+		}
+		files = append(files, file)
+	}
+
+	if m.WorkflowManifest != nil {
+		data, err := proto.Marshal(m.WorkflowManifest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize workflow manifest: %w", err)
+		}
+		file := manifestFile{kind: BundleEntryWorkflow, path: WorkflowManifestFileName, data: data}
+		for _, wf := range m.WorkflowManifest.GetWorkflows() {
+			doc := wf.GetSpec().GetDocument()
+			file.resources = append(file.resources, doc.GetNamespace()+"/"+doc.GetName())
+		}
+		files = append(files, file)
+	}
+
+	return files, nil
 }
 
 // =============================================================================
@@ -449,8 +974,8 @@ func (c *Context) synthesizeWorkflows(outputDir string, workflowInterfaces []int
 //	        log.Fatal(err)
 //	    }
 //	}
-func Run(fn func(*Context) error) error {
-	ctx := newContext()
+func Run(fn func(*Context) error, opts ...ContextOption) error {
+	ctx := NewContext(opts...)
 
 	// Execute the user function
 	if err := fn(ctx); err != nil {
@@ -465,6 +990,134 @@ func Run(fn func(*Context) error) error {
 	return nil
 }
 
+// SynthAll runs fn with a new Context like Run, but returns the synthesized
+// manifests instead of writing them (see Context.Manifests). It never exits
+// the process, so synthesis can be driven from tests, other programs and HTTP
+// handlers, including concurrently.
+//
+// Example:
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//	    m, err := stigmer.SynthAll(definePipelines)
+//	    if err != nil {
+//	        http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+//	        return
+//	    }
+//	    data, _ := protojson.Marshal(m.WorkflowManifest)
+//	    w.Write(data)
+//	}
+func SynthAll(fn func(*Context) error, opts ...ContextOption) (*Manifests, error) {
+	ctx := NewContext(opts...)
+
+	if err := fn(ctx); err != nil {
+		return nil, fmt.Errorf("context function failed: %w", err)
+	}
+
+	manifests, err := ctx.Manifests()
+	if err != nil {
+		return nil, fmt.Errorf("synthesis failed: %w", err)
+	}
+	return manifests, nil
+}
+
+// =============================================================================
+// Snapshots
+// =============================================================================
+
+// Snapshot is a point-in-time copy of a Context's variables and registered resources.
+//
+// Snapshots are immutable and safe to share between goroutines, which makes them
+// useful for table-driven tests that branch a base configuration into variants.
+//
+// Note: workflows and agents are captured by pointer. Mutating a workflow after
+// taking a snapshot is visible through every context restored from it.
+type Snapshot struct {
+	variables       map[string]Ref
+	workflows       []*workflow.Workflow
+	agents          []*agent.Agent
+	sharedEnvVars   []environment.Variable
+	declaredEnvVars map[string]environment.Variable
+}
+
+// Snapshot captures the current variables, workflows, and agents of the context.
+//
+// Example:
+//
+//	base := stigmer.NewContext()
+//	base.SetString("apiURL", "https://api.example.com")
+//	snap := base.Snapshot()
+//
+//	for _, tt := range tests {
+//	    t.Run(tt.name, func(t *testing.T) {
+//	        t.Parallel()
+//	        ctx := snap.NewContext()
+//	        ctx.SetInt("retries", tt.retries)
+//	        // ...
+//	    })
+//	}
+func (c *Context) Snapshot() *Snapshot {
+	if c.parent != nil {
+		return c.root().Snapshot()
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	snap := &Snapshot{
+		variables: make(map[string]Ref, len(c.variables)),
+		workflows: make([]*workflow.Workflow, len(c.workflows)),
+		agents:    make([]*agent.Agent, len(c.agents)),
+	}
+	for k, v := range c.variables {
+		snap.variables[k] = v
+	}
+	copy(snap.workflows, c.workflows)
+	copy(snap.agents, c.agents)
+	snap.sharedEnvVars = append([]environment.Variable(nil), c.sharedEnvVars...)
+	snap.declaredEnvVars = make(map[string]environment.Variable, len(c.declaredEnvVars))
+	for k, v := range c.declaredEnvVars {
+		snap.declaredEnvVars[k] = v
+	}
+	return snap
+}
+
+// Restore resets the context's variables, workflows, and agents to the state
+// captured in the snapshot. Anything added after the snapshot was taken is discarded.
+//
+// Restore does not reset the synthesized flag.
+func (c *Context) Restore(snap *Snapshot) {
+	if c.parent != nil {
+		c.root().Restore(snap)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Each restored context gets its own copies, so variable usage is tracked per context
+	c.variables = make(map[string]Ref, len(snap.variables))
+	for k, v := range snap.variables {
+		c.variables[k] = cloneRef(v)
+	}
+	c.workflows = make([]*workflow.Workflow, len(snap.workflows))
+	copy(c.workflows, snap.workflows)
+	c.agents = make([]*agent.Agent, len(snap.agents))
+	copy(c.agents, snap.agents)
+	c.sharedEnvVars = append([]environment.Variable(nil), snap.sharedEnvVars...)
+	c.declaredEnvVars = make(map[string]environment.Variable, len(snap.declaredEnvVars))
+	for k, v := range snap.declaredEnvVars {
+		c.declaredEnvVars[k] = v
+	}
+}
+
+// NewContext creates a new, independent Context initialized from the snapshot.
+// Contexts created this way do not share maps or slices with each other.
+func (s *Snapshot) NewContext() *Context {
+	ctx := newContext()
+	ctx.Restore(s)
+	return ctx
+}
+
 // =============================================================================
 // Inspection Methods (for debugging and testing)
 // =============================================================================
@@ -472,6 +1125,10 @@ func Run(fn func(*Context) error) error {
 // Variables returns a copy of all variables in the context.
 // This is primarily useful for testing and debugging.
 func (c *Context) Variables() map[string]Ref {
+	if c.parent != nil {
+		return c.root().Variables()
+	}
+
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -486,6 +1143,10 @@ func (c *Context) Variables() map[string]Ref {
 // Workflows returns a copy of all workflows registered in the context.
 // This is primarily useful for testing and debugging.
 func (c *Context) Workflows() []*workflow.Workflow {
+	if c.parent != nil {
+		return c.root().Workflows()
+	}
+
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -498,6 +1159,10 @@ func (c *Context) Workflows() []*workflow.Workflow {
 // Agents returns a copy of all agents registered in the context.
 // This is primarily useful for testing and debugging.
 func (c *Context) Agents() []*agent.Agent {
+	if c.parent != nil {
+		return c.root().Agents()
+	}
+
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -506,3 +1171,38 @@ func (c *Context) Agents() []*agent.Agent {
 	copy(result, c.agents)
 	return result
 }
+
+// AllResources returns an iterator over every agent and workflow registered in
+// the context. Agents are yielded first, then workflows, each in registration order.
+//
+// Resources are yielded as *agent.Agent or *workflow.Workflow values. The set of
+// resources is captured when iteration starts, so registering new resources while
+// iterating is safe but they will not be visited.
+//
+// Example:
+//
+//	for res := range ctx.AllResources() {
+//	    switch r := res.(type) {
+//	    case *agent.Agent:
+//	        fmt.Println("agent:", r.Name)
+//	    case *workflow.Workflow:
+//	        fmt.Println("workflow:", r.Document.Name)
+//	    }
+//	}
+func (c *Context) AllResources() iter.Seq[any] {
+	return func(yield func(any) bool) {
+		agents := c.Agents()
+		workflows := c.Workflows()
+
+		for _, ag := range agents {
+			if !yield(ag) {
+				return
+			}
+		}
+		for _, wf := range workflows {
+			if !yield(wf) {
+				return
+			}
+		}
+	}
+}
@@ -1,8 +1,18 @@
 package stigmer
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/leftbin/stigmer-sdk/go/agent"
+	"github.com/leftbin/stigmer-sdk/go/environment"
+	"github.com/leftbin/stigmer-sdk/go/workflow"
 )
 
 // =============================================================================
@@ -141,6 +151,32 @@ func TestContext_SetBool(t *testing.T) {
 	}
 }
 
+func TestContext_SetDuration(t *testing.T) {
+	ctx := newContext()
+
+	ref := ctx.SetDuration("timeout", 90*time.Second)
+
+	if ref.Value() != 90*time.Second {
+		t.Errorf("Value() = %v, want %v", ref.Value(), 90*time.Second)
+	}
+	if ref.ToValue() != "1m30s" {
+		t.Errorf("ToValue() = %v, want %q", ref.ToValue(), "1m30s")
+	}
+	if ctx.GetDuration("timeout") != ref || ctx.Reader().GetDuration("timeout") != ref {
+		t.Error("GetDuration did not return the variable")
+	}
+
+	// Accepted wherever a duration or timeout is
+	wait := workflow.WaitTask("cooldown", workflow.WithDuration(ref)).Config.(*workflow.WaitTaskConfig)
+	if wait.Duration != "1m30s" {
+		t.Errorf("WithDuration(ref) = %q, want %q", wait.Duration, "1m30s")
+	}
+	http := workflow.HttpCallTask("fetch", workflow.WithTimeout(ref)).Config.(*workflow.HttpCallTaskConfig)
+	if http.TimeoutSeconds != 90 {
+		t.Errorf("WithTimeout(ref) = %d seconds, want 90", http.TimeoutSeconds)
+	}
+}
+
 func TestContext_SetObject(t *testing.T) {
 	ctx := newContext()
 
@@ -486,6 +522,158 @@ func TestContext_Agents(t *testing.T) {
 	// TODO: Add agent registration test when agent.New() accepts context
 }
 
+func TestContext_AllResources(t *testing.T) {
+	ctx := newContext()
+
+	wf, err := workflow.New(ctx,
+		workflow.WithNamespace("test"),
+		workflow.WithName("test-workflow"),
+	)
+	if err != nil {
+		t.Fatalf("workflow.New() error = %v", err)
+	}
+	ag, err := agent.New(ctx,
+		agent.WithName("test-agent"),
+		agent.WithInstructions("Test instructions for agent"),
+	)
+	if err != nil {
+		t.Fatalf("agent.New() error = %v", err)
+	}
+
+	var got []any
+	for res := range ctx.AllResources() {
+		got = append(got, res)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("AllResources() yielded %d resources, want 2", len(got))
+	}
+	if got[0] != any(ag) {
+		t.Errorf("AllResources()[0] = %v, want agent", got[0])
+	}
+	if got[1] != any(wf) {
+		t.Errorf("AllResources()[1] = %v, want workflow", got[1])
+	}
+}
+
+func TestWithSharedEnvVars(t *testing.T) {
+	otel, _ := environment.New(
+		environment.WithName("OTEL_EXPORTER_OTLP_ENDPOINT"),
+		environment.WithDefaultValue("http://otel-collector:4317"),
+	)
+	apiBase, _ := environment.New(
+		environment.WithName("ORG_API_BASE"),
+		environment.WithDefaultValue("https://api.example.com"),
+	)
+	override, _ := environment.New(
+		environment.WithName("ORG_API_BASE"),
+		environment.WithDefaultValue("https://staging.example.com"),
+	)
+
+	ctx := NewContext(WithSharedEnvVars(otel, apiBase))
+
+	wf, err := workflow.New(ctx,
+		workflow.WithNamespace("test"),
+		workflow.WithName("test-workflow"),
+		workflow.WithEnvironmentVariable(override),
+	)
+	if err != nil {
+		t.Fatalf("workflow.New() error = %v", err)
+	}
+	ag, err := agent.New(ctx,
+		agent.WithName("test-agent"),
+		agent.WithInstructions("Test instructions for agent"),
+	)
+	if err != nil {
+		t.Fatalf("agent.New() error = %v", err)
+	}
+
+	// Workflow keeps its own ORG_API_BASE and gains OTEL_EXPORTER_OTLP_ENDPOINT
+	if len(wf.EnvironmentVariables) != 2 {
+		t.Fatalf("workflow env vars = %d, want 2", len(wf.EnvironmentVariables))
+	}
+	if wf.EnvironmentVariables[0].DefaultValue != "https://staging.example.com" {
+		t.Errorf("workflow ORG_API_BASE = %q, want declared value", wf.EnvironmentVariables[0].DefaultValue)
+	}
+	if wf.EnvironmentVariables[1].Name != "OTEL_EXPORTER_OTLP_ENDPOINT" {
+		t.Errorf("workflow env var[1] = %q, want OTEL_EXPORTER_OTLP_ENDPOINT", wf.EnvironmentVariables[1].Name)
+	}
+
+	// Agent gains both shared variables
+	if len(ag.EnvironmentVariables) != 2 {
+		t.Errorf("agent env vars = %d, want 2", len(ag.EnvironmentVariables))
+	}
+
+	// Shared variables carry over to contexts created from a snapshot
+	wf2, err := workflow.New(ctx.Snapshot().NewContext(),
+		workflow.WithNamespace("test"),
+		workflow.WithName("other-workflow"),
+	)
+	if err != nil {
+		t.Fatalf("workflow.New() error = %v", err)
+	}
+	if len(wf2.EnvironmentVariables) != 2 {
+		t.Errorf("snapshot workflow env vars = %d, want 2", len(wf2.EnvironmentVariables))
+	}
+}
+
+func TestDeclareEnvironmentVariable(t *testing.T) {
+	ctx := NewContext()
+
+	apiToken, err := environment.Declare(ctx,
+		environment.WithName("API_TOKEN"),
+		environment.WithSecret(true),
+		environment.WithDescription("API authentication token"),
+	)
+	if err != nil {
+		t.Fatalf("environment.Declare() error = %v", err)
+	}
+
+	// Declaring the same variable again returns the existing declaration
+	again, err := environment.Declare(ctx,
+		environment.WithName("API_TOKEN"),
+		environment.WithSecret(true),
+		environment.WithDescription("API authentication token"),
+	)
+	if err != nil {
+		t.Fatalf("environment.Declare() identical error = %v", err)
+	}
+	if again != apiToken {
+		t.Errorf("re-declared variable = %v, want %v", again, apiToken)
+	}
+
+	// Conflicting settings are rejected
+	if _, err := environment.Declare(ctx,
+		environment.WithName("API_TOKEN"),
+		environment.WithDefaultValue("plain"),
+	); err == nil {
+		t.Error("environment.Declare() expected error for conflicting declaration")
+	}
+
+	if got := ctx.DeclaredEnvironmentVariables(); len(got) != 1 || got[0].Name != "API_TOKEN" {
+		t.Errorf("DeclaredEnvironmentVariables() = %v, want [API_TOKEN]", got)
+	}
+
+	// Attaching the declaration twice lists it once in the manifest view
+	wf, err := workflow.New(ctx,
+		workflow.WithNamespace("test"),
+		workflow.WithName("test-workflow"),
+		workflow.WithEnvironmentVariable(apiToken),
+	)
+	if err != nil {
+		t.Fatalf("workflow.New() error = %v", err)
+	}
+	wf.AddEnvironmentVariable(apiToken)
+
+	data, err := json.Marshal(wf)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if n := strings.Count(string(data), `"API_TOKEN"`); n != 1 {
+		t.Errorf("API_TOKEN appears %d times in workflow JSON, want 1", n)
+	}
+}
+
 // =============================================================================
 // Concurrency Tests
 // =============================================================================
@@ -514,6 +702,130 @@ func TestContext_ConcurrentAccess(t *testing.T) {
 	}
 }
 
+// =============================================================================
+// Snapshot Tests
+// =============================================================================
+
+func TestContext_SnapshotRestore(t *testing.T) {
+	ctx := newContext()
+	ctx.SetString("apiURL", "https://api.example.com")
+	snap := ctx.Snapshot()
+
+	// Changes after the snapshot
+	ctx.SetString("apiURL", "https://staging.example.com")
+	ctx.SetInt("retries", 5)
+
+	ctx.Restore(snap)
+
+	if got := ctx.GetString("apiURL").Value(); got != "https://api.example.com" {
+		t.Errorf("apiURL after Restore() = %q, want %q", got, "https://api.example.com")
+	}
+	if ctx.Get("retries") != nil {
+		t.Error("variable added after Snapshot() should be discarded by Restore()")
+	}
+}
+
+func TestSnapshot_NewContext_Independent(t *testing.T) {
+	base := newContext()
+	base.SetString("apiURL", "https://api.example.com")
+	snap := base.Snapshot()
+
+	done := make(chan bool, 10)
+	for i := 0; i < 10; i++ {
+		go func(n int) {
+			ctx := snap.NewContext()
+			ctx.SetInt("variant", n)
+			if ctx.GetString("apiURL") == nil {
+				t.Error("snapshot context missing base variable")
+			}
+			if got := ctx.GetInt("variant").Value(); got != n {
+				t.Errorf("variant = %d, want %d", got, n)
+			}
+			done <- true
+		}(i)
+	}
+	for i := 0; i < 10; i++ {
+		<-done
+	}
+
+	// Base context is unaffected by variants
+	if base.Get("variant") != nil {
+		t.Error("variant contexts should not modify the base context")
+	}
+	if len(base.Variables()) != 1 {
+		t.Errorf("base context variables = %d, want 1", len(base.Variables()))
+	}
+}
+
+// TestSnapshot_ConcurrentManifests verifies contexts restored from one snapshot
+// can be synthesized concurrently without workflow defaults leaking into the
+// shared workflow. Run with -race.
+func TestSnapshot_ConcurrentManifests(t *testing.T) {
+	base := newContext()
+	wf, err := workflow.New(base,
+		workflow.WithNamespace("test"),
+		workflow.WithName("defaults-workflow"),
+		workflow.WithDefaults(
+			workflow.DefaultTimeout(10),
+			workflow.DefaultHeaders(map[string]string{"X-Team": "billing"}),
+		),
+	)
+	if err != nil {
+		t.Fatalf("workflow.New() error = %v", err)
+	}
+	fetch := wf.HttpGet("fetch", "https://api.example.com/data")
+	cfg := fetch.Config.(*workflow.HttpCallTaskConfig)
+	timeout := cfg.TimeoutSeconds
+	snap := base.Snapshot()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := snap.NewContext().Manifests(); err != nil {
+				t.Errorf("Manifests() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if cfg.TimeoutSeconds != timeout || len(cfg.Headers) != 0 {
+		t.Errorf("synthesis modified the workflow: timeout = %d, headers = %v", cfg.TimeoutSeconds, cfg.Headers)
+	}
+}
+
+// TestSnapshot_ConcurrentVariableUse verifies contexts restored from one
+// snapshot track variable usage independently and without data races. Run with -race.
+func TestSnapshot_ConcurrentVariableUse(t *testing.T) {
+	base := newContext()
+	base.SetString("apiURL", "https://api.example.com")
+	snap := base.Snapshot()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx := snap.NewContext()
+			_ = ctx.GetString("apiURL").Value()
+			if _, err := ctx.Manifests(); err != nil {
+				t.Errorf("Manifests() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// A variant that never reads the variable still reports it as unused
+	idle := snap.NewContext()
+	if got := idle.UnusedVariables(); len(got) != 1 || got[0] != "apiURL" {
+		t.Errorf("UnusedVariables() = %v, want [apiURL]", got)
+	}
+	if got := base.UnusedVariables(); len(got) != 1 {
+		t.Errorf("base UnusedVariables() = %v, want [apiURL]", got)
+	}
+}
+
 // =============================================================================
 // Integration Tests
 // =============================================================================
@@ -564,3 +876,84 @@ func TestContext_CompleteWorkflow(t *testing.T) {
 		t.Errorf("Complete workflow failed: %v", err)
 	}
 }
+
+// TestSynthAll verifies manifests are returned in memory, nothing is written
+// and failures come back as errors.
+func TestSynthAll(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("STIGMER_OUT_DIR", dir)
+
+	m, err := SynthAll(defineBundleResources)
+	if err != nil {
+		t.Fatalf("SynthAll() error = %v", err)
+	}
+	if got := m.AgentManifest.GetAgents()[0].GetName(); got != "bundle-agent" {
+		t.Errorf("agent = %q, want bundle-agent", got)
+	}
+	if got := len(m.WorkflowManifest.GetWorkflows()); got != 1 {
+		t.Errorf("got %d workflows, want 1", got)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("SynthAll() wrote files: %v", entries)
+	}
+
+	_, err = SynthAll(func(ctx *Context) error {
+		wf, err := workflow.New(ctx, workflow.WithNamespace("test"), workflow.WithName("broken"))
+		if err != nil {
+			return err
+		}
+		wf.AddTask(workflow.SwitchTask("route", workflow.WithCase("${ .ok }", "missing")))
+		return nil
+	})
+	var synthErr *SynthesisError
+	if !errors.As(err, &synthErr) {
+		t.Errorf("SynthAll() error = %v, want *SynthesisError", err)
+	}
+}
+
+// TestContext_ManifestsRepeatable verifies Manifests can be called repeatedly
+// and from concurrent goroutines.
+func TestContext_ManifestsRepeatable(t *testing.T) {
+	ctx := NewContext()
+	if err := defineBundleResources(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := ctx.Manifests(); err != nil {
+				t.Errorf("Manifests() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := ctx.Synthesize(); err != nil {
+		t.Errorf("Synthesize() after Manifests() error = %v", err)
+	}
+}
+
+// TestSynthesize_DryRunValidates verifies synthesis without STIGMER_OUT_DIR
+// still converts the context's resources and reports errors.
+func TestSynthesize_DryRunValidates(t *testing.T) {
+	t.Setenv("STIGMER_OUT_DIR", "")
+
+	if err := Run(defineBundleResources); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	err := Run(func(ctx *Context) error {
+		wf, err := workflow.New(ctx, workflow.WithNamespace("test"), workflow.WithName("broken"))
+		if err != nil {
+			return err
+		}
+		wf.AddTask(workflow.SwitchTask("route", workflow.WithCase("${ .ok }", "missing")))
+		return nil
+	})
+	if !errors.Is(err, workflow.ErrInvalidFlow) {
+		t.Errorf("Run() error = %v, want ErrInvalidFlow", err)
+	}
+}
@@ -0,0 +1,183 @@
+package stigmer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/leftbin/stigmer-sdk/go/agent"
+	"github.com/leftbin/stigmer-sdk/go/synth"
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+// diagnosticsEnv selects the diagnostics format when no WithDiagnostics option
+// is given.
+const diagnosticsEnv = "STIGMER_DIAGNOSTICS"
+
+// DiagnosticsFormat controls how synthesis reports errors and warnings on stderr.
+type DiagnosticsFormat string
+
+const (
+	// DiagnosticsText prints warnings as "warning: ..." lines (the default).
+	DiagnosticsText DiagnosticsFormat = "text"
+
+	// DiagnosticsJSON prints every error and warning as a JSON Diagnostic, one
+	// per line.
+	DiagnosticsJSON DiagnosticsFormat = "json"
+)
+
+// Diagnostic is an error or warning reported by synthesis in the
+// DiagnosticsJSON format.
+type Diagnostic struct {
+	Severity string `json:"severity"`           // "error" or "warning"
+	Code     string `json:"code"`               // Stable identifier, e.g. "workflow.invalid_task_config"
+	Resource string `json:"resource,omitempty"` // "<kind>/<name>", e.g. "workflow/order-pipeline"
+	Field    string `json:"field,omitempty"`    // Path of the offending field, when known
+	Message  string `json:"message"`
+	Location string `json:"location,omitempty"` // file:line where the resource was defined
+}
+
+// WithDiagnostics sets how synthesis reports errors and warnings on stderr.
+// With DiagnosticsJSON every error and warning is written as a JSON
+// Diagnostic on its own line, so the Stigmer CLI and editors can render them
+// without parsing free text. Errors are still returned as usual.
+//
+// The format can also be set with STIGMER_DIAGNOSTICS=json.
+//
+// Example:
+//
+//	stigmer.Run(func(ctx *stigmer.Context) error {
+//	    // ... define agents and workflows
+//	    return nil
+//	}, stigmer.WithDiagnostics(stigmer.DiagnosticsJSON))
+func WithDiagnostics(format DiagnosticsFormat) ContextOption {
+	return func(c *Context) {
+		c.diagnostics = format
+	}
+}
+
+// jsonDiagnostics reports whether diagnostics are written as JSON. Unknown
+// formats fall back to text, so a typo never hides a warning.
+func (c *Context) jsonDiagnostics() bool {
+	format := c.diagnostics
+	if format == "" {
+		format = DiagnosticsFormat(os.Getenv(diagnosticsEnv))
+	}
+	return format == DiagnosticsJSON
+}
+
+// warn reports a warning on stderr, as text or as a JSON Diagnostic.
+func (c *Context) warn(text string, d Diagnostic) {
+	if !c.jsonDiagnostics() {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", text)
+		return
+	}
+	d.Severity = "warning"
+	d.Location = c.locationOf(d.Resource)
+	writeDiagnostic(d)
+}
+
+// reportError writes err as JSON Diagnostics on stderr, one per problem, when
+// JSON diagnostics are enabled. Text errors are left to the caller.
+func (c *Context) reportError(err error) {
+	if !c.jsonDiagnostics() {
+		return
+	}
+
+	var synthErr *SynthesisError
+	if !errors.As(err, &synthErr) {
+		writeDiagnostic(Diagnostic{Severity: "error", Code: diagnosticCode(err), Field: errorField(err), Message: err.Error()})
+		return
+	}
+	for _, r := range synthErr.Resources {
+		resource := ""
+		if r.Kind != "" {
+			resource = r.Kind + "/" + r.Name
+		}
+		for _, problem := range r.Errs {
+			writeDiagnostic(Diagnostic{
+				Severity: "error",
+				Code:     diagnosticCode(problem),
+				Resource: resource,
+				Field:    errorField(problem),
+				Message:  problem.Error(),
+				Location: c.locationOf(resource),
+			})
+		}
+	}
+}
+
+func writeDiagnostic(d Diagnostic) {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(os.Stderr, string(data))
+}
+
+// diagnosticCodes maps the errors synthesis can fail with to Diagnostic codes.
+// The first match wins, so more specific errors come first.
+var diagnosticCodes = []struct {
+	err  error
+	code string
+}{
+	{ErrConstraintViolation, "constraint_violation"},
+	{synth.ErrUnsupportedFeature, "unsupported_feature"},
+	{ErrUnusedVariables, "unused_variable"},
+	{workflow.ErrDuplicateTaskName, "workflow.duplicate_task_name"},
+	{workflow.ErrInvalidTaskName, "workflow.invalid_task_name"},
+	{workflow.ErrInvalidTaskKind, "workflow.invalid_task_kind"},
+	{workflow.ErrInvalidTaskConfig, "workflow.invalid_task_config"},
+	{workflow.ErrDependencyCycle, "workflow.dependency_cycle"},
+	{workflow.ErrInvalidFlow, "workflow.invalid_flow"},
+	{workflow.ErrInvalidExpression, "workflow.invalid_expression"},
+	{workflow.ErrDSLIncompatible, "workflow.dsl_incompatible"},
+	{workflow.ErrNoTasks, "workflow.no_tasks"},
+	{workflow.ErrMissingRequiredField, "workflow.missing_required_field"},
+	{workflow.ErrConversion, "workflow.conversion"},
+	{agent.ErrSubAgentCycle, "agent.subagent_cycle"},
+	{agent.ErrSubAgentDepth, "agent.subagent_depth"},
+	{agent.ErrMissingRequiredField, "agent.missing_required_field"},
+	{agent.ErrConversion, "agent.conversion"},
+}
+
+// diagnosticCode returns the code of err, or "synthesis_error" when it is not
+// one of the known errors.
+func diagnosticCode(err error) string {
+	for _, entry := range diagnosticCodes {
+		if errors.Is(err, entry.err) {
+			return entry.code
+		}
+	}
+	return "synthesis_error"
+}
+
+// sdkPackagePrefix identifies SDK frames when looking for the code that
+// defined a resource.
+const sdkPackagePrefix = "github.com/leftbin/stigmer-sdk/go/"
+
+// callerLocation returns the file:line of the first caller outside the SDK
+// (test files count as callers), or "" if there is none.
+func callerLocation() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		sdk := strings.HasPrefix(frame.Function, sdkPackagePrefix) &&
+			!strings.HasPrefix(frame.Function, sdkPackagePrefix+"examples")
+		if frame.Function != "" && (!sdk || strings.HasSuffix(frame.File, "_test.go")) {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// locationOf returns where the resource ("<kind>/<name>") was registered.
+func (c *Context) locationOf(resource string) string {
+	return c.locations[resource]
+}
@@ -0,0 +1,99 @@
+package stigmer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+// captureStderr returns what fn writes to os.Stderr.
+func captureStderr(t *testing.T, fn func()) []byte {
+	t.Helper()
+	f, err := os.CreateTemp(t.TempDir(), "stderr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	stderr := os.Stderr
+	os.Stderr = f
+	defer func() { os.Stderr = stderr }()
+	fn()
+
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestSynthesize_JSONDiagnostics(t *testing.T) {
+	t.Setenv("STIGMER_OUT_DIR", "")
+	t.Setenv(diagnosticsEnv, "")
+
+	var runErr error
+	out := captureStderr(t, func() {
+		runErr = Run(func(ctx *Context) error {
+			ctx.SetString("unusedURL", "https://example.com")
+			wf, err := workflow.New(ctx, workflow.WithNamespace("test"), workflow.WithName("broken"))
+			if err != nil {
+				return err
+			}
+			wf.AddTask(workflow.SwitchTask("route", workflow.WithCase("${ .ok }", "missing")))
+			wf.AddTask(workflow.SetTask("fetch", workflow.SetVar("a", "1")).ExportFields("a"))
+			return nil
+		}, WithDiagnostics(DiagnosticsJSON))
+	})
+	if runErr == nil {
+		t.Fatal("Run() error = nil, want a synthesis error")
+	}
+
+	var diags []Diagnostic
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		var d Diagnostic
+		if err := json.Unmarshal(scanner.Bytes(), &d); err != nil {
+			t.Fatalf("stderr line is not a JSON diagnostic: %q", scanner.Text())
+		}
+		diags = append(diags, d)
+	}
+	if len(diags) != 3 {
+		t.Fatalf("got %d diagnostics, want 3:\n%s", len(diags), out)
+	}
+
+	if d := diags[0]; d.Severity != "warning" || d.Code != "unused_variable" || d.Field != "unusedURL" {
+		t.Errorf("diags[0] = %+v, want unused_variable warning for unusedURL", d)
+	}
+	if d := diags[1]; d.Severity != "warning" || d.Code != "lossy_conversion" || d.Field != "tasks.fetch.export" {
+		t.Errorf("diags[1] = %+v, want lossy_conversion warning for tasks.fetch.export", d)
+	}
+	d := diags[2]
+	if d.Severity != "error" || d.Code != "workflow.invalid_flow" || d.Resource != "workflow/broken" {
+		t.Errorf("diags[2] = %+v, want workflow.invalid_flow error for workflow/broken", d)
+	}
+	if !strings.Contains(d.Location, "diagnostics_test.go:") {
+		t.Errorf("diags[2].Location = %q, want the line that created the workflow", d.Location)
+	}
+}
+
+func TestSynthesize_TextDiagnostics(t *testing.T) {
+	t.Setenv("STIGMER_OUT_DIR", "")
+	t.Setenv(diagnosticsEnv, "")
+
+	out := captureStderr(t, func() {
+		if err := Run(func(ctx *Context) error {
+			ctx.SetString("unusedURL", "https://example.com")
+			return nil
+		}); err != nil {
+			t.Errorf("Run() error = %v", err)
+		}
+	})
+	if want := "warning: context variable \"unusedURL\" is set but never used\n"; string(out) != want {
+		t.Errorf("stderr = %q, want %q", out, want)
+	}
+}
@@ -74,7 +74,7 @@
 //
 // ## Typed References
 //
-// Context variables are typed references (StringRef, IntRef, BoolRef, ObjectRef)
+// Context variables are typed references (StringRef, IntRef, FloatRef, BoolRef, DurationRef, TimeRef, ObjectRef, ListRef)
 // that provide compile-time safety and IDE autocomplete:
 //
 //	apiBase := ctx.SetString("apiBase", "https://api.example.com")
@@ -112,6 +112,60 @@
 //	    // Manifests synthesized automatically here!
 //	})
 //
+// By default agent-manifest.pb and workflow-manifest.pb are written as separate
+// files, and manifest.pb is kept as a copy of the agent manifest for older CLIs
+// (see WithLegacyManifest). Use WithManifestLayout(ManifestLayoutBundle) (or STIGMER_MANIFEST_LAYOUT=bundle)
+// to write a single manifest-bundle.tar with an index.json; read it back with ReadBundle.
+// ManifestLayoutPerResource writes one file per resource instead
+// (agents/<name>.pb, workflows/<namespace>.<name>.pb).
+//
+// WithSink sends the files to a custom sink instead of STIGMER_OUT_DIR (e.g.,
+// to upload them to an API), and WithWriter streams them as a tar archive to
+// an io.Writer such as os.Stdout.
+//
+// A manifest.sha256 listing the digest of every manifest written is kept
+// alongside them. WithSigningKey (or STIGMER_SIGNING_KEY) also signs it with an
+// Ed25519 key, and VerifyManifests checks both.
+//
+// Variables that were set but never referenced are reported as warnings during
+// synthesis; WithStrictVariables (or STIGMER_STRICT_VARIABLES=true) turns them into errors.
+//
+// OnBeforeSynth registers hooks that can check or amend the manifests before
+// they are written (e.g., organization-wide naming policies), and OnAfterSynth
+// hooks run once they are on disk (e.g., to upload them to custom storage).
+//
+// When synthesis fails, every failing agent and workflow is reported in a
+// single SynthesisError, grouped by resource. WithTargetPlatformVersion (or
+// STIGMER_TARGET_PLATFORM_VERSION) rejects task kinds and options the target
+// platform release does not support yet.
+//
+// Without an output, synthesis is a dry run: nothing is written, but
+// WithDryRunReport (or STIGMER_DRY_RUN_REPORT=-) prints a JSON Report of the
+// resources, task counts and referenced environment variables and secrets.
+//
+// Data a manifest cannot hold (a TRY task's extra catch blocks, catch error
+// filters, ExportFields selections) is reported as a ConversionWarning on
+// stderr, in Manifests.Warnings and in the dry-run Report.
+// WithDiagnostics(DiagnosticsJSON) (or STIGMER_DIAGNOSTICS=json) writes errors
+// and warnings to stderr as JSON lines, with a code, the resource, the field
+// path and the file:line that defined the resource.
+//
+// WithClock and WithIDGenerator (with FixedClock and SeededIDs) make the
+// generation time and skill IDs reproducible for golden tests.
+//
+// SynthAll (and Context.Manifests) synthesize in memory and return the
+// manifests instead of writing them, for tests and programs that embed
+// synthesis.
+//
+// WithInclude and WithExclude (or STIGMER_ONLY=workflows:demo/basic-data-fetch)
+// synthesize a subset of the resources during local iteration.
+//
+// Workflows and agents are tracked by the Context they are created with;
+// there is no process-wide resource registry, so separate contexts (for
+// example, one per test) never see each other's resources and need no
+// cleanup. workflow.Build and agent.Build create resources without
+// registering them anywhere; Context.Adopt registers them explicitly.
+//
 // # Architecture
 //
 // The SDK follows Pulumi-aligned infrastructure-as-code patterns:
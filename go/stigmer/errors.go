@@ -0,0 +1,148 @@
+package stigmer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/leftbin/stigmer-sdk/go/agent"
+	"github.com/leftbin/stigmer-sdk/go/internal/synth"
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+// SynthesisError reports every agent and workflow that failed to synthesize,
+// grouped by resource, so all problems can be fixed in one pass.
+//
+// Synthesize (and Run) return it wrapped; use errors.As to inspect it:
+//
+//	var synthErr *stigmer.SynthesisError
+//	if errors.As(err, &synthErr) {
+//	    for _, r := range synthErr.Resources {
+//	        fmt.Println(r.Kind, r.Name, len(r.Errs))
+//	    }
+//	}
+//
+// errors.Is matches any of the underlying errors, e.g. workflow.ErrInvalidFlow.
+type SynthesisError struct {
+	Resources []ResourceError
+}
+
+// ResourceError lists the problems of a single agent or workflow.
+type ResourceError struct {
+	// Kind is "agent" or "workflow", or empty for problems spanning resources
+	// (such as a reference to an unregistered workflow)
+	Kind string
+	Name string
+	Errs []error
+}
+
+// ErrConstraintViolation is matched (with errors.Is) by a synthesis error when
+// a manifest field breaks a constraint declared in the Stigmer protos.
+var ErrConstraintViolation = synth.ErrConstraintViolation
+
+// ConversionWarning reports data a manifest cannot represent, which synthesis
+// dropped or replaced with a default (e.g. a TRY task's second catch block).
+// Warnings are printed to stderr and listed in Manifests.Warnings and the
+// dry-run Report.
+type ConversionWarning = synth.ConversionWarning
+
+// errorField returns the path of the field err is about, or "" if unknown.
+func errorField(err error) string {
+	var violation *synth.FieldViolation
+	if errors.As(err, &violation) {
+		return violation.Path
+	}
+	var workflowErr *workflow.ValidationError
+	if errors.As(err, &workflowErr) {
+		return workflowErr.Field
+	}
+	var agentErr *agent.ValidationError
+	if errors.As(err, &agentErr) {
+		return agentErr.Field
+	}
+	return ""
+}
+
+// validateManifests checks the manifests against the buf.validate constraints
+// of their proto definitions, returning the violations as a *SynthesisError.
+func validateManifests(m *Manifests) error {
+	var errs []error
+	if m.AgentManifest != nil {
+		if err := synth.ValidateAgentManifest(m.AgentManifest); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if m.WorkflowManifest != nil {
+		if err := synth.ValidateWorkflowManifest(m.WorkflowManifest); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return newSynthesisError(errs...)
+	}
+	return nil
+}
+
+// newSynthesisError groups conversion errors by the resource they belong to.
+func newSynthesisError(errs ...error) *SynthesisError {
+	e := &SynthesisError{}
+	for _, err := range errs {
+		e.add(err)
+	}
+	return e
+}
+
+func (e *SynthesisError) add(err error) {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, inner := range joined.Unwrap() {
+			e.add(inner)
+		}
+		return
+	}
+	kind, name, problems := "", "", []error{err}
+	var resourceErr *synth.ResourceError
+	if errors.As(err, &resourceErr) {
+		kind, name = resourceErr.Kind, resourceErr.Name
+		if joined, ok := resourceErr.Err.(interface{ Unwrap() []error }); ok {
+			problems = joined.Unwrap()
+		} else {
+			problems = []error{resourceErr.Err}
+		}
+	}
+	for i := range e.Resources {
+		if r := &e.Resources[i]; r.Kind == kind && r.Name == name {
+			r.Errs = append(r.Errs, problems...)
+			return
+		}
+	}
+	e.Resources = append(e.Resources, ResourceError{Kind: kind, Name: name, Errs: problems})
+}
+
+func (e *SynthesisError) Error() string {
+	var b strings.Builder
+	if len(e.Resources) == 1 {
+		b.WriteString("1 resource failed to synthesize:")
+	} else {
+		fmt.Fprintf(&b, "%d resources failed to synthesize:", len(e.Resources))
+	}
+	for _, r := range e.Resources {
+		if r.Kind == "" {
+			b.WriteString("\n  general:")
+		} else {
+			fmt.Fprintf(&b, "\n  %s %s:", r.Kind, r.Name)
+		}
+		for _, err := range r.Errs {
+			b.WriteString("\n    - " + strings.ReplaceAll(err.Error(), "\n", "\n      "))
+		}
+	}
+	return b.String()
+}
+
+// Unwrap returns every underlying error, for errors.Is and errors.As.
+func (e *SynthesisError) Unwrap() []error {
+	var errs []error
+	for _, r := range e.Resources {
+		errs = append(errs, r.Errs...)
+	}
+	return errs
+}
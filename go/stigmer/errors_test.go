@@ -0,0 +1,68 @@
+package stigmer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/agent"
+	"github.com/leftbin/stigmer-sdk/go/environment"
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+// TestSynthesize_AggregatesErrors verifies every failing resource is reported,
+// grouped by resource, instead of stopping at the first failure.
+func TestSynthesize_AggregatesErrors(t *testing.T) {
+	t.Setenv("STIGMER_OUT_DIR", t.TempDir())
+
+	err := Run(func(ctx *Context) error {
+		ag, err := agent.New(ctx,
+			agent.WithName("broken-agent"),
+			agent.WithInstructions("Test instructions for agent"),
+		)
+		if err != nil {
+			return err
+		}
+		ag.EnvironmentVariables = append(ag.EnvironmentVariables,
+			environment.Variable{Name: "TOKEN", IsSecret: true},
+			environment.Variable{Name: "TOKEN"},
+		)
+
+		for _, name := range []string{"route-a", "route-b"} {
+			wf, err := workflow.New(ctx, workflow.WithNamespace("test"), workflow.WithName(name))
+			if err != nil {
+				return err
+			}
+			wf.AddTask(workflow.SwitchTask("route", workflow.WithCase("${ .ok }", "missing")))
+		}
+		return nil
+	})
+
+	var synthErr *SynthesisError
+	if !errors.As(err, &synthErr) {
+		t.Fatalf("Run() error = %v, want *SynthesisError", err)
+	}
+	if len(synthErr.Resources) != 3 {
+		t.Fatalf("got %d resources, want 3: %v", len(synthErr.Resources), synthErr)
+	}
+	if r := synthErr.Resources[0]; r.Kind != "agent" || r.Name != "broken-agent" || len(r.Errs) != 1 {
+		t.Errorf("Resources[0] = %s %s with %d errors, want agent broken-agent with 1", r.Kind, r.Name, len(r.Errs))
+	}
+	for i, name := range []string{"route-a", "route-b"} {
+		if r := synthErr.Resources[i+1]; r.Kind != "workflow" || r.Name != name {
+			t.Errorf("Resources[%d] = %s %s, want workflow %s", i+1, r.Kind, r.Name, name)
+		}
+	}
+	if !errors.Is(err, workflow.ErrInvalidFlow) {
+		t.Errorf("errors.Is(err, ErrInvalidFlow) = false for %v", err)
+	}
+	for _, want := range []string{
+		"3 resources failed to synthesize:",
+		"\n  agent broken-agent:\n    - environment variable \"TOKEN\" is declared twice",
+		"\n  workflow route-b:\n    - ",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error message missing %q:\n%v", want, err)
+		}
+	}
+}
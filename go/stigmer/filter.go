@@ -0,0 +1,202 @@
+package stigmer
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/leftbin/stigmer-sdk/go/agent"
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+// onlyEnv selects the resources to synthesize when no WithInclude option is
+// given, as a comma-separated list of selectors.
+const onlyEnv = "STIGMER_ONLY"
+
+// resourceFilter selects the agents and workflows to synthesize.
+type resourceFilter struct {
+	include []string
+	exclude []string
+}
+
+// WithInclude synthesizes only the agents and workflows matching one of the
+// selectors, so a large program can synthesize a subset during local iteration
+// without commenting out code. Workflows run by a selected workflow's RUN
+// tasks are included too.
+//
+// A selector is "workflows:<namespace>/<name>" or "agents:<name>"; the name
+// may use path.Match wildcards, and a selector without a kind matches both.
+// A selector that matches nothing fails synthesis.
+//
+// The selectors can also be set with STIGMER_ONLY, comma separated.
+//
+// Example:
+//
+//	stigmer.Run(func(ctx *stigmer.Context) error {
+//	    // ... define workflows and agents
+//	    return nil
+//	}, stigmer.WithInclude("workflows:demo/basic-data-fetch", "agents:code-*"))
+//
+// Or, without changing the program:
+//
+//	STIGMER_ONLY=workflows:demo/basic-data-fetch go run .
+func WithInclude(selectors ...string) ContextOption {
+	return func(c *Context) {
+		c.filter.include = append(c.filter.include, selectors...)
+	}
+}
+
+// WithExclude leaves out the agents and workflows matching one of the
+// selectors (see WithInclude for the syntax). Exclusions apply after
+// inclusions.
+func WithExclude(selectors ...string) ContextOption {
+	return func(c *Context) {
+		c.filter.exclude = append(c.filter.exclude, selectors...)
+	}
+}
+
+// selectResources returns the agents and workflows the filter selects.
+func (c *Context) selectResources() ([]*agent.Agent, []*workflow.Workflow, error) {
+	include := c.filter.include
+	if len(include) == 0 {
+		for _, s := range strings.Split(os.Getenv(onlyEnv), ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				include = append(include, s)
+			}
+		}
+	}
+	if len(include) == 0 && len(c.filter.exclude) == 0 {
+		return c.agents, c.workflows, nil
+	}
+
+	agents, workflows := c.agents, c.workflows
+	if len(include) > 0 {
+		var err error
+		if agents, workflows, err = c.matchResources(include); err != nil {
+			return nil, nil, err
+		}
+		workflows = withCalledWorkflows(workflows, c.workflows)
+	}
+	if len(c.filter.exclude) > 0 {
+		excludedAgents, excludedWorkflows, err := c.matchResources(c.filter.exclude)
+		if err != nil {
+			return nil, nil, err
+		}
+		agents = without(agents, excludedAgents)
+		workflows = without(workflows, excludedWorkflows)
+	}
+	return agents, workflows, nil
+}
+
+// matchResources returns the registered agents and workflows matching any of
+// the selectors, in registration order.
+func (c *Context) matchResources(selectors []string) ([]*agent.Agent, []*workflow.Workflow, error) {
+	agentMatched := make(map[*agent.Agent]bool)
+	workflowMatched := make(map[*workflow.Workflow]bool)
+
+	for _, selector := range selectors {
+		kind, pattern, hasKind := strings.Cut(selector, ":")
+		if !hasKind {
+			kind, pattern = "", selector
+		}
+		if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+			return nil, nil, fmt.Errorf("invalid resource selector %q: bad name pattern", selector)
+		}
+
+		matches := 0
+		switch kind {
+		case "", "agents", "agent":
+			for _, ag := range c.agents {
+				if ok, _ := path.Match(pattern, ag.Name); ok {
+					agentMatched[ag] = true
+					matches++
+				}
+			}
+			if kind != "" {
+				break
+			}
+			fallthrough
+		case "workflows", "workflow":
+			for _, wf := range c.workflows {
+				if ok, _ := path.Match(pattern, wf.Document.Namespace+"/"+wf.Document.Name); ok {
+					workflowMatched[wf] = true
+					matches++
+				}
+			}
+		default:
+			return nil, nil, fmt.Errorf("invalid resource selector %q: kind must be \"agents\" or \"workflows\"", selector)
+		}
+		if matches == 0 {
+			return nil, nil, fmt.Errorf("resource selector %q matches no agent or workflow", selector)
+		}
+	}
+
+	var agents []*agent.Agent
+	for _, ag := range c.agents {
+		if agentMatched[ag] {
+			agents = append(agents, ag)
+		}
+	}
+	var workflows []*workflow.Workflow
+	for _, wf := range c.workflows {
+		if workflowMatched[wf] {
+			workflows = append(workflows, wf)
+		}
+	}
+	return agents, workflows, nil
+}
+
+// withCalledWorkflows adds the registered workflows that the selected
+// workflows run (directly or indirectly) through RUN tasks, keeping
+// registration order.
+func withCalledWorkflows(selected, registered []*workflow.Workflow) []*workflow.Workflow {
+	keep := make(map[*workflow.Workflow]bool, len(selected))
+	queue := append([]*workflow.Workflow{}, selected...)
+	for _, wf := range selected {
+		keep[wf] = true
+	}
+	for len(queue) > 0 {
+		wf := queue[0]
+		queue = queue[1:]
+		for task := range wf.AllTasks() {
+			cfg, ok := task.Config.(*workflow.RunTaskConfig)
+			if !ok || cfg.WorkflowNamespace == "" {
+				continue
+			}
+			for _, callee := range registered {
+				doc := callee.Document
+				if !keep[callee] && doc.Namespace == cfg.WorkflowNamespace && doc.Name == cfg.WorkflowName && doc.Version == cfg.WorkflowVersion {
+					keep[callee] = true
+					queue = append(queue, callee)
+				}
+			}
+		}
+	}
+
+	var result []*workflow.Workflow
+	for _, wf := range registered {
+		if keep[wf] {
+			result = append(result, wf)
+		}
+	}
+	return result
+}
+
+// without returns the items not in remove, keeping order.
+func without[T comparable](items, remove []T) []T {
+	var result []T
+	for _, item := range items {
+		found := false
+		for _, r := range remove {
+			if item == r {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,126 @@
+package stigmer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/agent"
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+// defineFilterResources registers two agents and three workflows, the last
+// of which runs the first.
+func defineFilterResources(ctx *Context) error {
+	for _, name := range []string{"code-reviewer", "support-bot"} {
+		if _, err := agent.New(ctx, agent.WithName(name), agent.WithInstructions("Test instructions for agent")); err != nil {
+			return err
+		}
+	}
+	var processor *workflow.Workflow
+	for _, name := range []string{"processor", "basic-data-fetch", "pipeline"} {
+		wf, err := workflow.New(ctx, workflow.WithNamespace("demo"), workflow.WithName(name))
+		if err != nil {
+			return err
+		}
+		if processor == nil {
+			processor = wf
+			wf.SetVars("init", "status", "ready")
+			continue
+		}
+		if name == "pipeline" {
+			wf.AddTask(workflow.RunTask("process", workflow.WithWorkflowRef(processor)))
+			continue
+		}
+		wf.SetVars("init", "status", "ready")
+	}
+	return nil
+}
+
+func synthesizedNames(t *testing.T, ctx *Context) string {
+	t.Helper()
+	m, err := ctx.Manifests()
+	if err != nil {
+		t.Fatalf("Manifests() error = %v", err)
+	}
+	var names []string
+	for _, ag := range m.AgentManifest.GetAgents() {
+		names = append(names, "agents:"+ag.GetName())
+	}
+	for _, wf := range m.WorkflowManifest.GetWorkflows() {
+		doc := wf.GetSpec().GetDocument()
+		names = append(names, "workflows:"+doc.GetNamespace()+"/"+doc.GetName())
+	}
+	return strings.Join(names, " ")
+}
+
+func TestWithInclude(t *testing.T) {
+	t.Setenv(onlyEnv, "")
+
+	tests := []struct {
+		name string
+		opts []ContextOption
+		want string
+	}{
+		{
+			name: "no filter",
+			want: "agents:code-reviewer agents:support-bot workflows:demo/processor workflows:demo/basic-data-fetch workflows:demo/pipeline",
+		},
+		{
+			name: "single workflow",
+			opts: []ContextOption{WithInclude("workflows:demo/basic-data-fetch")},
+			want: "workflows:demo/basic-data-fetch",
+		},
+		{
+			name: "wildcard agents",
+			opts: []ContextOption{WithInclude("agents:code-*")},
+			want: "agents:code-reviewer",
+		},
+		{
+			name: "called workflows included",
+			opts: []ContextOption{WithInclude("workflows:demo/pipeline")},
+			want: "workflows:demo/processor workflows:demo/pipeline",
+		},
+		{
+			name: "exclude",
+			opts: []ContextOption{WithExclude("agents:*", "workflows:demo/pipeline")},
+			want: "workflows:demo/processor workflows:demo/basic-data-fetch",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := NewContext(tt.opts...)
+			if err := defineFilterResources(ctx); err != nil {
+				t.Fatal(err)
+			}
+			if got := synthesizedNames(t, ctx); got != tt.want {
+				t.Errorf("synthesized %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithInclude_Env(t *testing.T) {
+	t.Setenv(onlyEnv, "workflows:demo/basic-data-fetch, support-bot")
+
+	ctx := NewContext()
+	if err := defineFilterResources(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := synthesizedNames(t, ctx), "agents:support-bot workflows:demo/basic-data-fetch"; got != want {
+		t.Errorf("synthesized %q, want %q", got, want)
+	}
+}
+
+func TestWithInclude_InvalidSelectors(t *testing.T) {
+	t.Setenv(onlyEnv, "")
+
+	for _, selector := range []string{"workflows:demo/missing", "tasks:demo/processor", "workflows:["} {
+		ctx := NewContext(WithInclude(selector))
+		if err := defineFilterResources(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ctx.Manifests(); err == nil || !strings.Contains(err.Error(), selector) {
+			t.Errorf("selector %q: error = %v", selector, err)
+		}
+	}
+}
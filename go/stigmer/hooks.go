@@ -0,0 +1,113 @@
+package stigmer
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	agentv1 "buf.build/gen/go/leftbin/stigmer/protocolbuffers/go/ai/stigmer/agentic/agent/v1"
+	workflowv1 "buf.build/gen/go/leftbin/stigmer/protocolbuffers/go/ai/stigmer/agentic/workflow/v1"
+)
+
+// Manifests holds the manifests produced by one synthesis, as passed to
+// synthesis hooks.
+type Manifests struct {
+	AgentManifest    *agentv1.AgentManifest       // nil if no agents were registered
+	WorkflowManifest *workflowv1.WorkflowManifest // nil if no workflows were registered
+
+	// OutputDir is the directory the manifests are written to (STIGMER_OUT_DIR);
+	// empty when they are only synthesized in memory or written with WithSink
+	// or WithWriter
+	OutputDir string
+
+	// Warnings lists the data conversion dropped or replaced with a default
+	Warnings []ConversionWarning
+}
+
+// SynthHook is called with the manifests of a synthesis. Returning an error
+// fails the synthesis.
+type SynthHook func(*Manifests) error
+
+// synthHook is a registered hook; id identifies it for removal.
+type synthHook struct {
+	id int
+	fn SynthHook
+}
+
+var (
+	hooksMu          sync.Mutex
+	nextHookID       int
+	beforeSynthHooks []synthHook
+	afterSynthHooks  []synthHook
+)
+
+// OnBeforeSynth registers a hook that runs after manifests are built and
+// before they are written, for every context synthesized by this program.
+//
+// Hooks may inspect and modify the manifests, which makes them the place for
+// organization-wide policies shipped as a shared package (enforcing
+// namespaces, adding mandatory labels). Hooks run in registration order; the
+// first error stops synthesis and nothing is written.
+//
+// Hooks only run when manifests are produced: by Synthesize when
+// STIGMER_OUT_DIR is set, and by Context.Manifests and SynthAll. The returned
+// function unregisters the hook.
+//
+// Example:
+//
+//	func init() {
+//	    stigmer.OnBeforeSynth(func(m *stigmer.Manifests) error {
+//	        for _, wf := range m.WorkflowManifest.GetWorkflows() {
+//	            if ns := wf.GetSpec().GetDocument().GetNamespace(); !strings.HasPrefix(ns, "acme-") {
+//	                return fmt.Errorf("workflow namespace %q must start with acme-", ns)
+//	            }
+//	        }
+//	        return nil
+//	    })
+//	}
+func OnBeforeSynth(hook SynthHook) (remove func()) {
+	return registerHook(&beforeSynthHooks, hook)
+}
+
+// OnAfterSynth registers a hook that runs after the manifests have been
+// written, for example to upload them to custom storage. Hooks run in
+// registration order; an error fails synthesis but the files stay on disk.
+//
+// The returned function unregisters the hook.
+//
+// Example:
+//
+//	stigmer.OnAfterSynth(func(m *stigmer.Manifests) error {
+//	    return uploadDir(m.OutputDir)
+//	})
+func OnAfterSynth(hook SynthHook) (remove func()) {
+	return registerHook(&afterSynthHooks, hook)
+}
+
+func registerHook(hooks *[]synthHook, fn SynthHook) func() {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	nextHookID++
+	id := nextHookID
+	*hooks = append(*hooks, synthHook{id: id, fn: fn})
+	return func() {
+		hooksMu.Lock()
+		defer hooksMu.Unlock()
+		*hooks = slices.DeleteFunc(*hooks, func(h synthHook) bool { return h.id == id })
+	}
+}
+
+// runHooks calls the registered hooks in order. The list is copied first so
+// hooks may register or remove hooks themselves.
+func runHooks(stage string, hooks *[]synthHook, m *Manifests) error {
+	hooksMu.Lock()
+	pending := slices.Clone(*hooks)
+	hooksMu.Unlock()
+
+	for i, h := range pending {
+		if err := h.fn(m); err != nil {
+			return fmt.Errorf("%s hook[%d]: %w", stage, i, err)
+		}
+	}
+	return nil
+}
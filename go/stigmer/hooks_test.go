@@ -0,0 +1,123 @@
+package stigmer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	workflowv1 "buf.build/gen/go/leftbin/stigmer/protocolbuffers/go/ai/stigmer/agentic/workflow/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestOnBeforeSynth_ModifiesManifests(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("STIGMER_OUT_DIR", dir)
+	t.Setenv(manifestLayoutEnv, "")
+
+	var order []string
+	remove := OnBeforeSynth(func(m *Manifests) error {
+		order = append(order, "before")
+		for _, wf := range m.WorkflowManifest.GetWorkflows() {
+			wf.Spec.Description = "owned by platform team"
+		}
+		return nil
+	})
+	defer remove()
+	removeAfter := OnAfterSynth(func(m *Manifests) error {
+		order = append(order, "after")
+		if m.OutputDir != dir {
+			t.Errorf("OutputDir = %q, want %q", m.OutputDir, dir)
+		}
+		if _, err := os.Stat(filepath.Join(dir, WorkflowManifestFileName)); err != nil {
+			t.Errorf("manifest not written before after-synth hook: %v", err)
+		}
+		return nil
+	})
+	defer removeAfter()
+
+	if err := Run(defineBundleResources); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(order) != 2 || order[0] != "before" || order[1] != "after" {
+		t.Errorf("hook order = %v, want [before after]", order)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, WorkflowManifestFileName))
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	manifest := &workflowv1.WorkflowManifest{}
+	if err := proto.Unmarshal(data, manifest); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if got := manifest.Workflows[0].Spec.Description; got != "owned by platform team" {
+		t.Errorf("written description = %q, want hook change", got)
+	}
+}
+
+func TestOnBeforeSynth_ErrorStopsSynthesis(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("STIGMER_OUT_DIR", dir)
+
+	errPolicy := errors.New("namespace must start with acme-")
+	remove := OnBeforeSynth(func(*Manifests) error { return errPolicy })
+	defer remove()
+
+	err := Run(defineBundleResources)
+	if !errors.Is(err, errPolicy) {
+		t.Fatalf("Run() error = %v, want policy error", err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("files written despite hook error: %v", entries)
+	}
+}
+
+func TestOnAfterSynth_Remove(t *testing.T) {
+	t.Setenv("STIGMER_OUT_DIR", t.TempDir())
+
+	calls := 0
+	remove := OnAfterSynth(func(*Manifests) error {
+		calls++
+		return nil
+	})
+	remove()
+
+	if err := Run(defineBundleResources); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("removed hook called %d times", calls)
+	}
+}
+
+// TestManifests_ConstraintViolations verifies manifests are checked against the
+// proto constraints after the before-synth hooks, with the offending fields reported.
+func TestManifests_ConstraintViolations(t *testing.T) {
+	remove := OnBeforeSynth(func(m *Manifests) error {
+		m.AgentManifest.Agents[0].Instructions = "short"
+		m.WorkflowManifest.Workflows[0].Spec.Tasks[0].Name = ""
+		return nil
+	})
+	defer remove()
+
+	ctx := NewContext()
+	if err := defineBundleResources(ctx); err != nil {
+		t.Fatal(err)
+	}
+	_, err := ctx.Manifests()
+	if !errors.Is(err, ErrConstraintViolation) {
+		t.Fatalf("Manifests() error = %v, want ErrConstraintViolation", err)
+	}
+	var synthErr *SynthesisError
+	if !errors.As(err, &synthErr) || len(synthErr.Resources) != 2 {
+		t.Fatalf("Manifests() error = %v, want violations grouped by resource", err)
+	}
+	for _, want := range []string{"instructions: must be at least 10 characters", "spec.tasks[0].name: value is required"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package stigmer
+
+import (
+	"github.com/leftbin/stigmer-sdk/go/internal/synth"
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+// TaskMutator modifies a task's converted configuration during synthesis.
+//
+// The config map is the task_config written to the workflow manifest, so keys
+// follow the manifest format (e.g., "endpoint", "headers", "proxy" for HTTP_CALL).
+type TaskMutator func(task *workflow.Task, config map[string]interface{}) error
+
+// RegisterTaskMutator registers a mutator applied to every task of the given kind
+// during synthesis, including nested tasks. Use this to apply organization policies
+// centrally instead of in every workflow.
+//
+// Mutators are global and typically registered from an init() function.
+//
+// Example (force a corporate proxy on all HTTP tasks):
+//
+//	func init() {
+//	    stigmer.RegisterTaskMutator(workflow.TaskKindHttpCall,
+//	        func(task *workflow.Task, config map[string]interface{}) error {
+//	            config["proxy"] = "http://proxy.corp.example.com:3128"
+//	            return nil
+//	        },
+//	    )
+//	}
+func RegisterTaskMutator(kind workflow.TaskKind, fn TaskMutator) {
+	synth.RegisterTaskMutator(kind, synth.TaskMutator(fn))
+}
@@ -0,0 +1,85 @@
+package stigmer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	agentv1 "buf.build/gen/go/leftbin/stigmer/protocolbuffers/go/ai/stigmer/agentic/agent/v1"
+	workflowv1 "buf.build/gen/go/leftbin/stigmer/protocolbuffers/go/ai/stigmer/agentic/workflow/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// Directories written to STIGMER_OUT_DIR by the per-resource layout.
+const (
+	// AgentManifestDir holds one <name>.pb manifest per agent.
+	AgentManifestDir = "agents"
+
+	// WorkflowManifestDir holds one <namespace>.<name>.pb manifest per workflow.
+	WorkflowManifestDir = "workflows"
+)
+
+// perResourceManifestFiles serializes one manifest per agent and workflow,
+// agents first. Each manifest carries the SDK metadata of the combined one.
+func perResourceManifestFiles(m *Manifests) ([]manifestFile, error) {
+	var files []manifestFile
+	owners := make(map[string]string)
+	add := func(kind BundleEntryKind, path, resource string, msg proto.Message) error {
+		if owner, ok := owners[path]; ok {
+			return fmt.Errorf("%s and %s would both be written to %s", owner, resource, path)
+		}
+		owners[path] = resource
+		data, err := proto.Marshal(msg)
+		if err != nil {
+			return fmt.Errorf("failed to serialize %s manifest for %s: %w", kind, resource, err)
+		}
+		files = append(files, manifestFile{kind: kind, path: path, resources: []string{resource}, data: data})
+		return nil
+	}
+
+	if m.AgentManifest != nil {
+		for _, ag := range m.AgentManifest.GetAgents() {
+			path := filepath.Join(AgentManifestDir, ag.GetName()+".pb")
+			single := &agentv1.AgentManifest{
+				SdkMetadata: m.AgentManifest.GetSdkMetadata(),
+				Agents:      []*agentv1.AgentBlueprint{ag},
+			}
+			if err := add(BundleEntryAgent, path, ag.GetName(), single); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if m.WorkflowManifest != nil {
+		for _, wf := range m.WorkflowManifest.GetWorkflows() {
+			doc := wf.GetSpec().GetDocument()
+			path := filepath.Join(WorkflowManifestDir, doc.GetNamespace()+"."+doc.GetName()+".pb")
+			single := &workflowv1.WorkflowManifest{
+				SdkMetadata: m.WorkflowManifest.GetSdkMetadata(),
+				Workflows:   []*workflowv1.Workflow{wf},
+			}
+			if err := add(BundleEntryWorkflow, path, doc.GetNamespace()+"/"+doc.GetName(), single); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return files, nil
+}
+
+// removeStaleManifests removes the manifests a previous run left in the agents
+// and workflows directories of outputDir, so deleted resources do not linger.
+func removeStaleManifests(outputDir string) error {
+	for _, dir := range []string{AgentManifestDir, WorkflowManifestDir} {
+		stale, err := filepath.Glob(filepath.Join(outputDir, dir, "*.pb"))
+		if err != nil {
+			return err
+		}
+		for _, path := range stale {
+			if err := os.Remove(path); err != nil {
+				return fmt.Errorf("failed to remove stale manifest: %w", err)
+			}
+		}
+	}
+	return nil
+}
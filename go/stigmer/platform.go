@@ -0,0 +1,69 @@
+package stigmer
+
+import (
+	"os"
+
+	"github.com/leftbin/stigmer-sdk/go/synth"
+)
+
+// targetPlatformEnv sets the target platform version when no
+// WithTargetPlatformVersion or WithPlatformCapabilities option is given.
+const targetPlatformEnv = "STIGMER_TARGET_PLATFORM_VERSION"
+
+// platformTarget is the platform synthesized manifests are checked against.
+type platformTarget struct {
+	version      string
+	capabilities *synth.Capabilities // overrides version when set
+}
+
+// WithTargetPlatformVersion makes synthesis fail when a workflow uses a task
+// kind or option the given platform release does not support yet (see
+// synth.PlatformVersions), instead of the deployment failing later.
+//
+// The target can also be set with STIGMER_TARGET_PLATFORM_VERSION. Without a
+// target, no compatibility check is done.
+//
+// Example:
+//
+//	stigmer.Run(func(ctx *stigmer.Context) error {
+//	    // ... define workflows
+//	    return nil
+//	}, stigmer.WithTargetPlatformVersion("2025.1"))
+func WithTargetPlatformVersion(version string) ContextOption {
+	return func(c *Context) {
+		c.platform.version = version
+	}
+}
+
+// WithPlatformCapabilities checks synthesized workflows against capabilities
+// obtained elsewhere, such as the capability document of a running platform
+// (see synth.FetchCapabilities). It takes precedence over
+// WithTargetPlatformVersion.
+func WithPlatformCapabilities(caps *synth.Capabilities) ContextOption {
+	return func(c *Context) {
+		c.platform.capabilities = caps
+	}
+}
+
+// checkPlatformCompatibility verifies the manifests against the target
+// platform, if one is configured.
+func (c *Context) checkPlatformCompatibility(m *Manifests) error {
+	caps := c.platform.capabilities
+	if caps == nil {
+		version := c.platform.version
+		if version == "" {
+			version = os.Getenv(targetPlatformEnv)
+		}
+		if version == "" {
+			return nil
+		}
+		var err error
+		if caps, err = synth.PlatformCapabilities(version); err != nil {
+			return err
+		}
+	}
+	if m.WorkflowManifest == nil {
+		return nil
+	}
+	return synth.CheckCompatibility(m.WorkflowManifest, caps)
+}
@@ -0,0 +1,57 @@
+package stigmer
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/synth"
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+func defineAgentCallWorkflow(ctx *Context) error {
+	wf, err := workflow.New(ctx, workflow.WithNamespace("test"), workflow.WithName("triage"))
+	if err != nil {
+		return err
+	}
+	wf.AddTask(workflow.AgentCallTask("classify",
+		workflow.AgentOption(workflow.AgentBySlug("classifier")),
+		workflow.Message("Classify the ticket"),
+	))
+	return nil
+}
+
+// TestWithTargetPlatformVersion verifies synthesis refuses features the target
+// platform lacks and writes nothing.
+func TestWithTargetPlatformVersion(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("STIGMER_OUT_DIR", dir)
+
+	err := Run(defineAgentCallWorkflow, WithTargetPlatformVersion("2024.4"))
+	if !errors.Is(err, synth.ErrUnsupportedFeature) {
+		t.Fatalf("Run() error = %v, want ErrUnsupportedFeature", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("files written for incompatible manifests: %v", entries)
+	}
+
+	if err := Run(defineAgentCallWorkflow, WithTargetPlatformVersion("2025.1")); err != nil {
+		t.Errorf("Run(2025.1) error = %v", err)
+	}
+}
+
+// TestTargetPlatformEnv verifies the target can be set from the environment and
+// that explicit capabilities take precedence.
+func TestTargetPlatformEnv(t *testing.T) {
+	t.Setenv("STIGMER_OUT_DIR", t.TempDir())
+	t.Setenv(targetPlatformEnv, "2024.4")
+
+	if err := Run(defineAgentCallWorkflow); !errors.Is(err, synth.ErrUnsupportedFeature) {
+		t.Errorf("Run() error = %v, want ErrUnsupportedFeature", err)
+	}
+
+	caps := &synth.Capabilities{Version: "edge", TaskKinds: []string{"WORKFLOW_TASK_KIND_AGENT_CALL"}}
+	if err := Run(defineAgentCallWorkflow, WithPlatformCapabilities(caps)); err != nil {
+		t.Errorf("Run() with capabilities error = %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package stigmer
+
+// ContextReader is a read-only view of a Context's variables.
+//
+// Pass a ContextReader to shared helper packages that need configuration but
+// should not set variables or register workflows and agents. Obtain one with
+// Context.Reader.
+//
+// Example:
+//
+//	// In a shared package
+//	func SlackNotify(cfg stigmer.ContextReader, name string) *workflow.Task {
+//	    return workflow.HttpCallTask(name,
+//	        workflow.WithHTTPPost(),
+//	        workflow.WithURI(cfg.GetString("slackWebhook")),
+//	    )
+//	}
+//
+//	// In the program
+//	wf.AddTask(notify.SlackNotify(ctx.Reader(), "notify"))
+type ContextReader interface {
+	// Get retrieves a variable by name, or nil if it doesn't exist.
+	Get(name string) Ref
+
+	// GetString retrieves a string variable, or nil if it doesn't exist or is not a string.
+	GetString(name string) *StringRef
+
+	// GetInt retrieves an integer variable, or nil if it doesn't exist or is not an int.
+	GetInt(name string) *IntRef
+
+	// GetBool retrieves a boolean variable, or nil if it doesn't exist or is not a bool.
+	GetBool(name string) *BoolRef
+
+	// GetDuration retrieves a duration variable, or nil if it doesn't exist or is not a duration.
+	GetDuration(name string) *DurationRef
+
+	// GetObject retrieves an object variable, or nil if it doesn't exist or is not an object.
+	GetObject(name string) *ObjectRef
+
+	// Variables returns a copy of all variables, keyed by name.
+	Variables() map[string]Ref
+}
+
+// Context satisfies ContextReader, but Reader should be preferred so that
+// helpers cannot type-assert back to *Context.
+var _ ContextReader = (*Context)(nil)
+
+// Reader returns a read-only view of the context's variables for helper packages.
+func (c *Context) Reader() ContextReader {
+	return contextReader{ctx: c}
+}
+
+// contextReader wraps a Context so only the ContextReader methods are reachable.
+type contextReader struct {
+	ctx *Context
+}
+
+func (r contextReader) Get(name string) Ref                  { return r.ctx.Get(name) }
+func (r contextReader) GetString(name string) *StringRef     { return r.ctx.GetString(name) }
+func (r contextReader) GetInt(name string) *IntRef           { return r.ctx.GetInt(name) }
+func (r contextReader) GetBool(name string) *BoolRef         { return r.ctx.GetBool(name) }
+func (r contextReader) GetDuration(name string) *DurationRef { return r.ctx.GetDuration(name) }
+func (r contextReader) GetObject(name string) *ObjectRef     { return r.ctx.GetObject(name) }
+func (r contextReader) Variables() map[string]Ref            { return r.ctx.Variables() }
@@ -0,0 +1,38 @@
+package stigmer
+
+import "testing"
+
+func TestContext_Reader(t *testing.T) {
+	ctx := NewContext()
+	ctx.SetString("region", "eu-west-1")
+	ctx.SetInt("retries", 3)
+	ctx.SetBool("debug", true)
+	ctx.SetObject("limits", map[string]interface{}{"rps": 10})
+
+	reader := ctx.Reader()
+	if _, ok := reader.(*Context); ok {
+		t.Fatal("Reader() returned the *Context itself")
+	}
+
+	if got := reader.Variables(); len(got) != 4 || got["region"] != ctx.Get("region") {
+		t.Errorf("Variables() = %v, want the 4 context variables", got)
+	}
+	if ref := reader.GetString("region"); ref == nil || ref.Value() != "eu-west-1" {
+		t.Errorf("GetString(region) = %v", ref)
+	}
+	if ref := reader.GetInt("retries"); ref == nil || ref.Value() != 3 {
+		t.Errorf("GetInt(retries) = %v", ref)
+	}
+	if ref := reader.GetBool("debug"); ref == nil || !ref.Value() {
+		t.Errorf("GetBool(debug) = %v", ref)
+	}
+	if ref := reader.GetObject("limits"); ref == nil {
+		t.Error("GetObject(limits) = nil")
+	}
+	if reader.GetString("retries") != nil {
+		t.Error("GetString(retries) should be nil for an int variable")
+	}
+	if reader.Get("missing") != nil {
+		t.Error("Get(missing) should be nil")
+	}
+}
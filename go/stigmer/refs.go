@@ -2,7 +2,12 @@ package stigmer
 
 import (
 	"fmt"
+	"math"
 	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/leftbin/stigmer-sdk/go/workflow"
 )
 
 // Ref is the base interface for all typed references.
@@ -36,10 +41,14 @@ type Ref interface {
 
 // baseRef provides common functionality for all Ref implementations.
 type baseRef struct {
-	name         string
-	isSecret     bool
-	isComputed   bool   // If true, name contains full expression, not just variable name
-	rawExpression string // For computed expressions, the full expression without ${ }
+	name          string
+	isSecret      bool
+	isComputed    bool       // If true, name contains full expression, not just variable name
+	rawExpression string     // For computed expressions, the full expression without ${ }
+	doc           string     // Human-readable description (set by WithDoc)
+	used          uint32     // Set atomically once the value or expression is consumed (see markUsed)
+	sources       []*baseRef // Context variables a derived reference was built from
+	origin        *baseRef   // The snapshotted variable this one was copied from (see cloneRef)
 }
 
 func (r *baseRef) Name() string {
@@ -50,13 +59,123 @@ func (r *baseRef) IsSecret() bool {
 	return r.isSecret
 }
 
+// Doc returns the variable's description set with WithDoc, or "" if none was given.
+func (r *baseRef) Doc() string {
+	return r.doc
+}
+
+// wasUsed reports whether markUsed has been called on this reference, or on
+// the variable it was copied from before the snapshot was restored.
+func (r *baseRef) wasUsed() bool {
+	return atomic.LoadUint32(&r.used) == 1 || (r.origin != nil && r.origin.wasUsed())
+}
+
+// markUsed records that this reference, and every variable it was derived from,
+// has been consumed by a workflow, agent or expression.
+//
+// References are shared by the contexts restored from a Snapshot, which may
+// run concurrently, so the flag is set atomically.
+func (r *baseRef) markUsed() {
+	atomic.StoreUint32(&r.used, 1)
+	for _, src := range r.sources {
+		src.markUsed()
+	}
+}
+
 func (r *baseRef) Expression() string {
+	r.markUsed()
 	if r.isComputed {
 		return fmt.Sprintf("${ %s }", r.rawExpression)
 	}
 	return fmt.Sprintf("${ $context.%s }", r.name)
 }
 
+// base returns the reference itself, giving code that handles any typed
+// reference access to the embedded baseRef.
+func (r *baseRef) base() *baseRef {
+	return r
+}
+
+// operand returns the JQ operand for this reference inside a larger
+// expression: the computed expression, or the context variable.
+func (r *baseRef) operand() string {
+	if r.isComputed {
+		return r.rawExpression
+	}
+	return "$context." + r.name
+}
+
+// valueRef is the base of the typed references: a reference whose value has
+// type T. Each Ref type embeds it and adds the operations that make sense for
+// its type, so a new type only needs its own struct and methods.
+type valueRef[T any] struct {
+	baseRef
+	value T // Initial value (used during synthesis)
+}
+
+// Value returns the initial value of this reference (used during synthesis).
+func (r *valueRef[T]) Value() T {
+	r.markUsed()
+	return r.value
+}
+
+// ToValue implements Ref.ToValue() for synthesis/serialization.
+// Returns the value as interface{} for JSON serialization.
+func (r *valueRef[T]) ToValue() interface{} {
+	return r.value
+}
+
+// clone returns a copy of the reference that tracks its own usage, so contexts
+// restored from one Snapshot do not see each other's uses (see cloneRef).
+func (r *valueRef[T]) clone() valueRef[T] {
+	return valueRef[T]{
+		baseRef: baseRef{
+			name:          r.name,
+			isSecret:      r.isSecret,
+			isComputed:    r.isComputed,
+			rawExpression: r.rawExpression,
+			doc:           r.doc,
+			sources:       r.sources,
+			origin:        &r.baseRef,
+		},
+		value: r.value,
+	}
+}
+
+func (s *StringRef) clone() Ref   { return &StringRef{s.valueRef.clone()} }
+func (i *IntRef) clone() Ref      { return &IntRef{i.valueRef.clone()} }
+func (f *FloatRef) clone() Ref    { return &FloatRef{f.valueRef.clone()} }
+func (b *BoolRef) clone() Ref     { return &BoolRef{b.valueRef.clone()} }
+func (d *DurationRef) clone() Ref { return &DurationRef{d.valueRef.clone()} }
+func (t *TimeRef) clone() Ref     { return &TimeRef{t.valueRef.clone()} }
+func (o *ObjectRef) clone() Ref   { return &ObjectRef{o.valueRef.clone()} }
+func (l *ListRef) clone() Ref     { return &ListRef{l.valueRef.clone()} }
+
+// cloneRef returns a copy of a context variable for a restored context.
+// References of other types are returned as is.
+func cloneRef(ref Ref) Ref {
+	if c, ok := ref.(interface{ clone() Ref }); ok {
+		return c.clone()
+	}
+	return ref
+}
+
+// variable returns the base of a context variable holding value.
+func variable[T any](name string, secret bool, value T) valueRef[T] {
+	return valueRef[T]{baseRef: baseRef{name: name, isSecret: secret}, value: value}
+}
+
+// derive returns the base of a reference computed at runtime by expr from
+// sources. Its value is not known at synthesis time.
+func derive[T any](expr string, secret bool, sources ...*baseRef) valueRef[T] {
+	return valueRef[T]{baseRef: baseRef{
+		isSecret:      secret,
+		isComputed:    true,
+		rawExpression: expr,
+		sources:       sources,
+	}}
+}
+
 // =============================================================================
 // StringRef - Reference to a string value
 // =============================================================================
@@ -70,19 +189,7 @@ func (r *baseRef) Expression() string {
 //	apiURL := ctx.SetString("apiURL", "https://api.example.com")
 //	endpoint := apiURL.Concat("/users")  // "${ $context.apiURL + "/users" }"
 type StringRef struct {
-	baseRef
-	value string // Initial value (used during synthesis)
-}
-
-// Value returns the initial value of this string reference (used during synthesis).
-func (s *StringRef) Value() string {
-	return s.value
-}
-
-// ToValue implements Ref.ToValue() for synthesis/serialization.
-// Returns the string value as interface{} for JSON serialization.
-func (s *StringRef) ToValue() interface{} {
-	return s.value
+	valueRef[string]
 }
 
 // Concat creates a new StringRef that concatenates this string with other strings.
@@ -109,6 +216,7 @@ func (s *StringRef) Concat(parts ...interface{}) *StringRef {
 	// Build both the resolved value AND the expression (we'll use one or the other)
 	var resolvedParts []string
 	var expressions []string
+	sources := []*baseRef{&s.baseRef}
 	
 	// Add base value/expression
 	if !s.isComputed {
@@ -125,9 +233,10 @@ func (s *StringRef) Concat(parts ...interface{}) *StringRef {
 		case string:
 			// Literal string - always known
 			resolvedParts = append(resolvedParts, v)
-			expressions = append(expressions, fmt.Sprintf(`"%s"`, v))
+			expressions = append(expressions, workflow.Literal(v))
 			
 		case *StringRef:
+			sources = append(sources, &v.baseRef)
 			// Another StringRef - check if it's known
 			if !v.isComputed {
 				resolvedParts = append(resolvedParts, v.value)
@@ -138,6 +247,7 @@ func (s *StringRef) Concat(parts ...interface{}) *StringRef {
 			}
 		
 		case *IntRef:
+			sources = append(sources, &v.baseRef)
 			// IntRef - check if it's known
 			if !v.isComputed {
 				resolvedParts = append(resolvedParts, fmt.Sprintf("%d", v.value))
@@ -148,6 +258,7 @@ func (s *StringRef) Concat(parts ...interface{}) *StringRef {
 			}
 		
 		case *BoolRef:
+			sources = append(sources, &v.baseRef)
 			// BoolRef - check if it's known
 			if !v.isComputed {
 				resolvedParts = append(resolvedParts, fmt.Sprintf("%t", v.value))
@@ -165,36 +276,21 @@ func (s *StringRef) Concat(parts ...interface{}) *StringRef {
 		default:
 			// Fallback - literal value
 			resolvedParts = append(resolvedParts, fmt.Sprintf("%v", v))
-			expressions = append(expressions, fmt.Sprintf(`"%v"`, v))
+			expressions = append(expressions, workflow.Literal(fmt.Sprintf("%v", v)))
 		}
 	}
 
 	// SMART DECISION: Can we resolve this now, or defer to runtime?
 	if allKnown {
 		// All parts are known - compute the final value NOW
-		finalValue := strings.Join(resolvedParts, "")
-		return &StringRef{
-			baseRef: baseRef{
-				name:         "", // Not a context variable, it's a resolved literal
-				isSecret:     s.isSecret,
-				isComputed:   false, // ← KEY: This is a known value!
-				rawExpression: "",
-			},
-			value: finalValue, // ← The actual resolved string
-		}
+		// Not a context variable: the name is empty and the value is the resolved string
+		resolved := variable("", s.isSecret, strings.Join(resolvedParts, ""))
+		resolved.sources = sources
+		return &StringRef{resolved}
 	}
 
 	// At least one part is a runtime value - create expression
-	result := strings.Join(expressions, " + ")
-	return &StringRef{
-		baseRef: baseRef{
-			name:         "",
-			isSecret:     s.isSecret,
-			isComputed:   true,
-			rawExpression: result,
-		},
-		value: "", // Runtime value, not known at synthesis time
-	}
+	return &StringRef{derive[string](strings.Join(expressions, " + "), s.isSecret, sources...)}
 }
 
 // Upper creates a new StringRef that converts this string to uppercase.
@@ -205,21 +301,7 @@ func (s *StringRef) Concat(parts ...interface{}) *StringRef {
 //	name := ctx.SetString("name", "alice")
 //	upperName := name.Upper()  // "${ $context.name | ascii_upcase }"
 func (s *StringRef) Upper() *StringRef {
-	var expr string
-	if s.isComputed {
-		expr = fmt.Sprintf("(%s | ascii_upcase)", s.rawExpression)
-	} else {
-		expr = fmt.Sprintf("($context.%s | ascii_upcase)", s.name)
-	}
-	return &StringRef{
-		baseRef: baseRef{
-			name:         "",
-			isSecret:     s.isSecret,
-			isComputed:   true,
-			rawExpression: expr,
-		},
-		value: "",
-	}
+	return &StringRef{derive[string](fmt.Sprintf("(%s | ascii_upcase)", s.operand()), s.isSecret, &s.baseRef)}
 }
 
 // Lower creates a new StringRef that converts this string to lowercase.
@@ -230,21 +312,7 @@ func (s *StringRef) Upper() *StringRef {
 //	name := ctx.SetString("name", "ALICE")
 //	lowerName := name.Lower()  // "${ $context.name | ascii_downcase }"
 func (s *StringRef) Lower() *StringRef {
-	var expr string
-	if s.isComputed {
-		expr = fmt.Sprintf("(%s | ascii_downcase)", s.rawExpression)
-	} else {
-		expr = fmt.Sprintf("($context.%s | ascii_downcase)", s.name)
-	}
-	return &StringRef{
-		baseRef: baseRef{
-			name:         "",
-			isSecret:     s.isSecret,
-			isComputed:   true,
-			rawExpression: expr,
-		},
-		value: "",
-	}
+	return &StringRef{derive[string](fmt.Sprintf("(%s | ascii_downcase)", s.operand()), s.isSecret, &s.baseRef)}
 }
 
 // Prepend creates a new StringRef that prepends a prefix to this string.
@@ -255,21 +323,8 @@ func (s *StringRef) Lower() *StringRef {
 //	path := ctx.SetString("path", "users")
 //	fullPath := path.Prepend("/api/")  // "${ "/api/" + $context.path }"
 func (s *StringRef) Prepend(prefix string) *StringRef {
-	var expr string
-	if s.isComputed {
-		expr = fmt.Sprintf(`("%s" + %s)`, prefix, s.rawExpression)
-	} else {
-		expr = fmt.Sprintf(`("%s" + $context.%s)`, prefix, s.name)
-	}
-	return &StringRef{
-		baseRef: baseRef{
-			name:         "",
-			isSecret:     s.isSecret,
-			isComputed:   true,
-			rawExpression: expr,
-		},
-		value: "",
-	}
+	expr := fmt.Sprintf(`(%s + %s)`, workflow.Literal(prefix), s.operand())
+	return &StringRef{derive[string](expr, s.isSecret, &s.baseRef)}
 }
 
 // Append creates a new StringRef that appends a suffix to this string.
@@ -280,21 +335,123 @@ func (s *StringRef) Prepend(prefix string) *StringRef {
 //	base := ctx.SetString("base", "https://api.example.com")
 //	url := base.Append("/v1")  // "${ $context.base + "/v1" }"
 func (s *StringRef) Append(suffix string) *StringRef {
-	var expr string
-	if s.isComputed {
-		expr = fmt.Sprintf(`(%s + "%s")`, s.rawExpression, suffix)
-	} else {
-		expr = fmt.Sprintf(`($context.%s + "%s")`, s.name, suffix)
+	expr := fmt.Sprintf(`(%s + %s)`, s.operand(), workflow.Literal(suffix))
+	return &StringRef{derive[string](expr, s.isSecret, &s.baseRef)}
+}
+
+// Split creates a ListRef with the parts of this string separated by sep.
+//
+// Example:
+//
+//	tags := ctx.SetString("tags", "api,backend,go")
+//	list := tags.Split(",")  // "${ ($context.tags | split(",")) }"
+func (s *StringRef) Split(sep string) *ListRef {
+	expr := fmt.Sprintf("(%s | split(%s))", s.operand(), workflow.Literal(sep))
+	return &ListRef{derive[[]interface{}](expr, s.isSecret, &s.baseRef)}
+}
+
+// Replace creates a new StringRef with every occurrence of old replaced by
+// new. old is matched literally, not as a regular expression.
+//
+// Example:
+//
+//	branch := ctx.SetString("branch", "feature/login")
+//	slug := branch.Replace("/", "-")  // "${ ($context.branch | split("/") | join("-")) }"
+func (s *StringRef) Replace(old, new string) *StringRef {
+	expr := fmt.Sprintf("(%s | split(%s) | join(%s))", s.operand(), workflow.Literal(old), workflow.Literal(new))
+	return &StringRef{derive[string](expr, s.isSecret, &s.baseRef)}
+}
+
+// TrimSpace creates a new StringRef with leading and trailing whitespace removed.
+//
+// Example:
+//
+//	input := ctx.SetString("input", "  hello  ")
+//	trimmed := input.TrimSpace()  // "${ ($context.input | sub("^\\s+"; "") | sub("\\s+$"; "")) }"
+func (s *StringRef) TrimSpace() *StringRef {
+	expr := fmt.Sprintf(`(%s | sub("^\\s+"; "") | sub("\\s+$"; ""))`, s.operand())
+	return &StringRef{derive[string](expr, s.isSecret, &s.baseRef)}
+}
+
+// Slice creates a new StringRef with the characters from start up to (not
+// including) end. Negative positions count from the end of the string.
+//
+// Example:
+//
+//	sha := ctx.SetString("sha", "3f2a9c1e7b")
+//	short := sha.Slice(0, 7)  // "${ ($context.sha[0:7]) }"
+func (s *StringRef) Slice(start, end int) *StringRef {
+	expr := fmt.Sprintf("(%s[%d:%d])", s.operand(), start, end)
+	return &StringRef{derive[string](expr, s.isSecret, &s.baseRef)}
+}
+
+// Format creates a StringRef from a printf-style format, evaluated at runtime
+// so arguments can be context variables or task outputs.
+//
+// Each verb (%s, %d, %v, ...) is replaced by the next argument converted to a
+// string; flags, widths and precisions are not supported. %% is a literal
+// percent sign. Arguments can be typed references, workflow refs such as
+// task fields, or plain values. The result is secret if any argument is.
+//
+// Example:
+//
+//	userID := fetchTask.Field("id")
+//	url := stigmer.Format("%s/users/%v", apiBase, userID)
+//	// Result: "${ ($context.apiBase + "/users/" + ($context.fetchTask.id | tostring)) }"
+func Format(format string, args ...interface{}) *StringRef {
+	var parts []string
+	var sources []*baseRef
+	secret := false
+	var literal strings.Builder
+	flush := func() {
+		if literal.Len() > 0 {
+			parts = append(parts, workflow.Literal(literal.String()))
+			literal.Reset()
+		}
 	}
-	return &StringRef{
-		baseRef: baseRef{
-			name:         "",
-			isSecret:     s.isSecret,
-			isComputed:   true,
-			rawExpression: expr,
-		},
-		value: "",
+
+	next := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i == len(format)-1 {
+			literal.WriteByte(format[i])
+			continue
+		}
+		i++
+		if format[i] == '%' {
+			literal.WriteByte('%')
+			continue
+		}
+		if next >= len(args) {
+			literal.WriteString("%!" + string(format[i]) + "(MISSING)")
+			continue
+		}
+		flush()
+		arg := args[next]
+		next++
+
+		switch v := arg.(type) {
+		case *StringRef:
+			parts = append(parts, v.operand())
+			sources = append(sources, &v.baseRef)
+			secret = secret || v.isSecret
+		case interface{ base() *baseRef }:
+			b := v.base()
+			parts = append(parts, fmt.Sprintf("(%s | tostring)", b.operand()))
+			sources = append(sources, b)
+			secret = secret || b.isSecret
+		case workflow.Ref:
+			parts = append(parts, fmt.Sprintf("(%s | tostring)", jqBody(v.Expression())))
+		default:
+			parts = append(parts, workflow.Literal(fmt.Sprint(v)))
+		}
+	}
+	flush()
+
+	if len(parts) == 0 {
+		parts = append(parts, `""`)
 	}
+	expr := fmt.Sprintf("(%s)", strings.Join(parts, " + "))
+	return &StringRef{derive[string](expr, secret, sources...)}
 }
 
 // =============================================================================
@@ -311,19 +468,7 @@ func (s *StringRef) Append(suffix string) *StringRef {
 //	increased := retries.Add(ctx.SetInt("additional", 2))
 //	// Result: "${ $context.retries + $context.additional }"
 type IntRef struct {
-	baseRef
-	value int // Initial value (used during synthesis)
-}
-
-// Value returns the initial value of this integer reference (used during synthesis).
-func (i *IntRef) Value() int {
-	return i.value
-}
-
-// ToValue implements Ref.ToValue() for synthesis/serialization.
-// Returns the integer value as interface{} for JSON serialization.
-func (i *IntRef) ToValue() interface{} {
-	return i.value
+	valueRef[int]
 }
 
 // Add creates a new IntRef that adds another integer to this one.
@@ -335,101 +480,145 @@ func (i *IntRef) ToValue() interface{} {
 //	total := base.Add(ctx.SetInt("increment", 5))
 //	// Result: "${ $context.base + $context.increment }"
 func (i *IntRef) Add(other *IntRef) *IntRef {
-	var left, right string
-	if i.isComputed {
-		left = i.rawExpression
-	} else {
-		left = fmt.Sprintf("$context.%s", i.name)
-	}
-	if other.isComputed {
-		right = other.rawExpression
-	} else {
-		right = fmt.Sprintf("$context.%s", other.name)
-	}
-	return &IntRef{
-		baseRef: baseRef{
-			name:         "",
-			isSecret:     false,
-			isComputed:   true,
-			rawExpression: fmt.Sprintf("(%s + %s)", left, right),
-		},
-		value: 0,
-	}
+	return i.arithmetic("+", other)
 }
 
 // Subtract creates a new IntRef that subtracts another integer from this one.
 // It generates a JQ expression for runtime subtraction.
 func (i *IntRef) Subtract(other *IntRef) *IntRef {
-	var left, right string
-	if i.isComputed {
-		left = i.rawExpression
-	} else {
-		left = fmt.Sprintf("$context.%s", i.name)
-	}
-	if other.isComputed {
-		right = other.rawExpression
-	} else {
-		right = fmt.Sprintf("$context.%s", other.name)
-	}
-	return &IntRef{
-		baseRef: baseRef{
-			name:         "",
-			isSecret:     false,
-			isComputed:   true,
-			rawExpression: fmt.Sprintf("(%s - %s)", left, right),
-		},
-		value: 0,
-	}
+	return i.arithmetic("-", other)
 }
 
 // Multiply creates a new IntRef that multiplies this integer by another.
 // It generates a JQ expression for runtime multiplication.
 func (i *IntRef) Multiply(other *IntRef) *IntRef {
-	var left, right string
-	if i.isComputed {
-		left = i.rawExpression
-	} else {
-		left = fmt.Sprintf("$context.%s", i.name)
-	}
-	if other.isComputed {
-		right = other.rawExpression
-	} else {
-		right = fmt.Sprintf("$context.%s", other.name)
-	}
-	return &IntRef{
-		baseRef: baseRef{
-			name:         "",
-			isSecret:     false,
-			isComputed:   true,
-			rawExpression: fmt.Sprintf("(%s * %s)", left, right),
-		},
-		value: 0,
-	}
+	return i.arithmetic("*", other)
 }
 
 // Divide creates a new IntRef that divides this integer by another.
 // It generates a JQ expression for runtime division.
 func (i *IntRef) Divide(other *IntRef) *IntRef {
-	var left, right string
-	if i.isComputed {
-		left = i.rawExpression
-	} else {
-		left = fmt.Sprintf("$context.%s", i.name)
-	}
-	if other.isComputed {
-		right = other.rawExpression
-	} else {
-		right = fmt.Sprintf("$context.%s", other.name)
+	return i.arithmetic("/", other)
+}
+
+// arithmetic returns the IntRef computing (i op other) at runtime.
+func (i *IntRef) arithmetic(op string, other *IntRef) *IntRef {
+	expr := fmt.Sprintf("(%s %s %s)", i.operand(), op, other.operand())
+	return &IntRef{derive[int](expr, false, &i.baseRef, &other.baseRef)}
+}
+
+// GreaterThan creates a BoolRef that is true when this integer is greater
+// than x, an int or an IntRef. Use it as a SWITCH condition or combine it
+// with other BoolRefs.
+//
+// Example:
+//
+//	attempts := ctx.SetInt("attempts", 0)
+//	exhausted := attempts.GreaterThan(ctx.SetInt("maxAttempts", 3))
+//	workflow.WithCase(exhausted.Expression(), "giveUp")
+//	// Condition: "${ ($context.attempts > $context.maxAttempts) }"
+func (i *IntRef) GreaterThan(x interface{}) *BoolRef {
+	return i.compare(">", x)
+}
+
+// LessThan creates a BoolRef that is true when this integer is less than x,
+// an int or an IntRef.
+func (i *IntRef) LessThan(x interface{}) *BoolRef {
+	return i.compare("<", x)
+}
+
+// Equals creates a BoolRef that is true when this integer equals x, an int
+// or an IntRef.
+func (i *IntRef) Equals(x interface{}) *BoolRef {
+	return i.compare("==", x)
+}
+
+// Between creates a BoolRef that is true when this integer is between low and
+// high, inclusive. low and high are ints or IntRefs.
+//
+// Example:
+//
+//	status := ctx.SetInt("statusCode", 200)
+//	ok := status.Between(200, 299)
+//	// Result: "${ (($context.statusCode >= 200) and ($context.statusCode <= 299)) }"
+func (i *IntRef) Between(low, high interface{}) *BoolRef {
+	return i.compare(">=", low).And(i.compare("<=", high))
+}
+
+// compare returns the BoolRef computing (i op x) at runtime.
+func (i *IntRef) compare(op string, x interface{}) *BoolRef {
+	sources := []*baseRef{&i.baseRef}
+	var operand string
+	switch v := x.(type) {
+	case *IntRef:
+		operand = v.operand()
+		sources = append(sources, &v.baseRef)
+	default:
+		operand = fmt.Sprintf("%d", v)
 	}
-	return &IntRef{
-		baseRef: baseRef{
-			name:         "",
-			isSecret:     false,
-			isComputed:   true,
-			rawExpression: fmt.Sprintf("(%s / %s)", left, right),
-		},
-		value: 0,
+	expr := fmt.Sprintf("(%s %s %s)", i.operand(), op, operand)
+	return &BoolRef{derive[bool](expr, false, sources...)}
+}
+
+// =============================================================================
+// FloatRef - Reference to a floating-point value
+// =============================================================================
+
+// FloatRef represents a reference to a floating-point value in the workflow
+// context, for numeric configuration like prices and thresholds. It provides
+// methods for arithmetic operations that generate JQ expressions for runtime
+// evaluation.
+//
+// Example:
+//
+//	price := ctx.SetFloat("price", 19.99)
+//	withTax := price.Multiply(ctx.SetFloat("taxRate", 1.2)).Round(2)
+//	// Result: "${ ((($context.price * $context.taxRate) * 100 | round) / 100) }"
+type FloatRef struct {
+	valueRef[float64]
+}
+
+// Add creates a new FloatRef that adds another number to this one.
+func (f *FloatRef) Add(other *FloatRef) *FloatRef {
+	return f.arithmetic("+", other)
+}
+
+// Subtract creates a new FloatRef that subtracts another number from this one.
+func (f *FloatRef) Subtract(other *FloatRef) *FloatRef {
+	return f.arithmetic("-", other)
+}
+
+// Multiply creates a new FloatRef that multiplies this number by another.
+func (f *FloatRef) Multiply(other *FloatRef) *FloatRef {
+	return f.arithmetic("*", other)
+}
+
+// Divide creates a new FloatRef that divides this number by another.
+func (f *FloatRef) Divide(other *FloatRef) *FloatRef {
+	return f.arithmetic("/", other)
+}
+
+// Round creates a new FloatRef that rounds this number to the given number of
+// decimal places (half away from zero). Negative places are treated as 0.
+//
+// Example:
+//
+//	price := ctx.SetFloat("price", 19.987)
+//	rounded := price.Round(2)
+//	// Result: "${ (($context.price * 100 | round) / 100) }"
+func (f *FloatRef) Round(places int) *FloatRef {
+	expr := fmt.Sprintf("(%s | round)", f.operand())
+	if places > 0 {
+		scale := math.Pow10(places)
+		expr = fmt.Sprintf("((%s * %g | round) / %g)", f.operand(), scale, scale)
 	}
+	return &FloatRef{derive[float64](expr, false, &f.baseRef)}
+}
+
+// arithmetic returns the FloatRef computing (f op other) at runtime.
+func (f *FloatRef) arithmetic(op string, other *FloatRef) *FloatRef {
+	expr := fmt.Sprintf("(%s %s %s)", f.operand(), op, other.operand())
+	return &FloatRef{derive[float64](expr, false, &f.baseRef, &other.baseRef)}
 }
 
 // =============================================================================
@@ -447,20 +636,7 @@ func (i *IntRef) Divide(other *IntRef) *IntRef {
 //	shouldLog := isProd.And(isDebug.Not())
 //	// Result: "${ $context.isProd and ($context.isDebug | not) }"
 type BoolRef struct {
-	baseRef
-	value bool // Initial value (used during synthesis)
-}
-
-// Value returns the initial value of this boolean reference (used during synthesis).
-// Returns the boolean value as interface{} for JSON serialization.
-func (b *BoolRef) Value() bool {
-	return b.value
-}
-
-// ToValue implements Ref.ToValue() for synthesis/serialization.
-// Returns the boolean value as interface{} for JSON serialization.
-func (b *BoolRef) ToValue() interface{} {
-	return b.value
+	valueRef[bool]
 }
 
 // And creates a new BoolRef that performs logical AND with another boolean.
@@ -473,51 +649,19 @@ func (b *BoolRef) ToValue() interface{} {
 //	canProceed := hasAccess.And(isEnabled)
 //	// Result: "${ $context.hasAccess and $context.isEnabled }"
 func (b *BoolRef) And(other *BoolRef) *BoolRef {
-	var left, right string
-	if b.isComputed {
-		left = b.rawExpression
-	} else {
-		left = fmt.Sprintf("$context.%s", b.name)
-	}
-	if other.isComputed {
-		right = other.rawExpression
-	} else {
-		right = fmt.Sprintf("$context.%s", other.name)
-	}
-	return &BoolRef{
-		baseRef: baseRef{
-			name:         "",
-			isSecret:     false,
-			isComputed:   true,
-			rawExpression: fmt.Sprintf("(%s and %s)", left, right),
-		},
-		value: false,
-	}
+	return b.logic("and", other)
 }
 
 // Or creates a new BoolRef that performs logical OR with another boolean.
 // It generates a JQ expression for runtime evaluation.
 func (b *BoolRef) Or(other *BoolRef) *BoolRef {
-	var left, right string
-	if b.isComputed {
-		left = b.rawExpression
-	} else {
-		left = fmt.Sprintf("$context.%s", b.name)
-	}
-	if other.isComputed {
-		right = other.rawExpression
-	} else {
-		right = fmt.Sprintf("$context.%s", other.name)
-	}
-	return &BoolRef{
-		baseRef: baseRef{
-			name:         "",
-			isSecret:     false,
-			isComputed:   true,
-			rawExpression: fmt.Sprintf("(%s or %s)", left, right),
-		},
-		value: false,
-	}
+	return b.logic("or", other)
+}
+
+// logic returns the BoolRef computing (b op other) at runtime.
+func (b *BoolRef) logic(op string, other *BoolRef) *BoolRef {
+	expr := fmt.Sprintf("(%s %s %s)", b.operand(), op, other.operand())
+	return &BoolRef{derive[bool](expr, false, &b.baseRef, &other.baseRef)}
 }
 
 // Not creates a new BoolRef that negates this boolean.
@@ -529,21 +673,142 @@ func (b *BoolRef) Or(other *BoolRef) *BoolRef {
 //	isDisabled := isEnabled.Not()
 //	// Result: "${ ($context.isEnabled | not) }"
 func (b *BoolRef) Not() *BoolRef {
-	var expr string
-	if b.isComputed {
-		expr = fmt.Sprintf("(%s | not)", b.rawExpression)
-	} else {
-		expr = fmt.Sprintf("($context.%s | not)", b.name)
-	}
-	return &BoolRef{
-		baseRef: baseRef{
-			name:         "",
-			isSecret:     false,
-			isComputed:   true,
-			rawExpression: expr,
-		},
-		value: false,
-	}
+	return &BoolRef{derive[bool](fmt.Sprintf("(%s | not)", b.operand()), false, &b.baseRef)}
+}
+
+// =============================================================================
+// DurationRef - Reference to a duration value
+// =============================================================================
+
+// DurationRef represents a reference to a duration in the workflow context.
+// Every workflow option that takes a duration or timeout accepts it.
+//
+// Example:
+//
+//	timeout := ctx.SetDuration("requestTimeout", 45*time.Second)
+//	wf.HttpGet("fetch", endpoint, workflow.Timeout(timeout))
+//	workflow.WaitTask("cooldown", workflow.WithDuration(timeout))
+type DurationRef struct {
+	valueRef[time.Duration]
+}
+
+// ToValue implements Ref.ToValue() for synthesis/serialization.
+// Returns the duration as a string like "1h30m".
+func (d *DurationRef) ToValue() interface{} {
+	return workflow.Duration(d.value)
+}
+
+// Add creates a new DurationRef for the sum of two durations.
+//
+// Durations are resolved at synthesis time (workflow options read their
+// value), so the sum is computed immediately rather than in JQ.
+//
+// Example:
+//
+//	base := ctx.SetDuration("baseTimeout", 30*time.Second)
+//	grace := ctx.SetDuration("grace", 15*time.Second)
+//	wf.HttpGet("fetch", endpoint, workflow.Timeout(base.Add(grace)))  // 45 second timeout
+func (d *DurationRef) Add(other *DurationRef) *DurationRef {
+	sum := d.value + other.value
+	ref := derive[time.Duration](workflow.Literal(workflow.Duration(sum)), false, &d.baseRef, &other.baseRef)
+	ref.value = sum
+	return &DurationRef{ref}
+}
+
+// Seconds returns the duration in whole seconds, for arithmetic with other
+// numbers such as TimeRef timestamps.
+func (d *DurationRef) Seconds() int64 {
+	d.markUsed()
+	return int64(d.value / time.Second)
+}
+
+// =============================================================================
+// TimeRef - Reference to a point in time
+// =============================================================================
+
+// TimeRef represents a reference to a point in time in the workflow context,
+// stored in UTC as an RFC 3339 timestamp (e.g. "2026-03-01T09:00:00Z").
+// Its methods generate JQ date expressions for runtime evaluation, and derived
+// timestamps use the same format, so they can be passed to
+// workflow.WithUntilExpression or compared with each other.
+//
+// Example:
+//
+//	start := ctx.SetTime("start", time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC))
+//	deadline := start.Add(2 * time.Hour)
+//	workflow.WaitTask("waitForDeadline", workflow.WithUntilExpression(deadline))
+//	// Result: "${ ($context.start | fromdateiso8601 + 7200 | todateiso8601) }"
+type TimeRef struct {
+	valueRef[time.Time]
+}
+
+// ToValue implements Ref.ToValue() for synthesis/serialization.
+// Returns the time as an RFC 3339 timestamp in UTC.
+func (t *TimeRef) ToValue() interface{} {
+	return t.value.UTC().Format(time.RFC3339)
+}
+
+// Add creates a new TimeRef offset by d. Partial seconds are dropped, and a
+// negative duration moves the time back.
+//
+// Example:
+//
+//	createdAt := ctx.SetTime("createdAt", created)
+//	expiresAt := createdAt.Add(24 * time.Hour)
+//	// Result: "${ ($context.createdAt | fromdateiso8601 + 86400 | todateiso8601) }"
+func (t *TimeRef) Add(d time.Duration) *TimeRef {
+	expr := fmt.Sprintf("(%s | fromdateiso8601 + %d | todateiso8601)", t.operand(), int64(d/time.Second))
+	return &TimeRef{derive[time.Time](expr, t.isSecret, &t.baseRef)}
+}
+
+// AddDuration creates a new TimeRef offset by a duration variable, such as a
+// TTL set with ctx.SetDuration.
+//
+// Example:
+//
+//	ttl := ctx.SetDuration("ttl", 7*24*time.Hour)
+//	expiresAt := ctx.SetTime("createdAt", created).AddDuration(ttl)
+func (t *TimeRef) AddDuration(d *DurationRef) *TimeRef {
+	expr := fmt.Sprintf("(%s | fromdateiso8601 + %d | todateiso8601)", t.operand(), d.Seconds())
+	return &TimeRef{derive[time.Time](expr, t.isSecret, &t.baseRef, &d.baseRef)}
+}
+
+// Before creates a BoolRef that is true when this time is before other.
+//
+// Example:
+//
+//	expired := expiresAt.Before(now)
+//	// Result: "${ (($context.expiresAt | fromdateiso8601) < ($context.now | fromdateiso8601)) }"
+func (t *TimeRef) Before(other *TimeRef) *BoolRef {
+	return t.compare("<", other)
+}
+
+// After creates a BoolRef that is true when this time is after other.
+func (t *TimeRef) After(other *TimeRef) *BoolRef {
+	return t.compare(">", other)
+}
+
+// compare returns the BoolRef comparing the two times as Unix seconds.
+func (t *TimeRef) compare(op string, other *TimeRef) *BoolRef {
+	expr := fmt.Sprintf("((%s | fromdateiso8601) %s (%s | fromdateiso8601))", t.operand(), op, other.operand())
+	return &BoolRef{derive[bool](expr, false, &t.baseRef, &other.baseRef)}
+}
+
+// Unix creates an IntRef with the time as seconds since the Unix epoch.
+func (t *TimeRef) Unix() *IntRef {
+	return &IntRef{derive[int](fmt.Sprintf("(%s | fromdateiso8601)", t.operand()), t.isSecret, &t.baseRef)}
+}
+
+// Format creates a StringRef with the time formatted by layout, a strftime
+// format string (JQ's date formatting), not a Go layout.
+//
+// Example:
+//
+//	day := start.Format("%Y-%m-%d")
+//	// Result: "${ ($context.start | fromdateiso8601 | strftime("%Y-%m-%d")) }"
+func (t *TimeRef) Format(layout string) *StringRef {
+	expr := fmt.Sprintf("(%s | fromdateiso8601 | strftime(%s))", t.operand(), workflow.Literal(layout))
+	return &StringRef{derive[string](expr, t.isSecret, &t.baseRef)}
 }
 
 // =============================================================================
@@ -565,19 +830,7 @@ func (b *BoolRef) Not() *BoolRef {
 //	dbHost := config.Field("database").Field("host")
 //	// Result: "${ $context.config.database.host }"
 type ObjectRef struct {
-	baseRef
-	value map[string]interface{} // Initial value (used during synthesis)
-}
-
-// Value returns the initial value of this object reference (used during synthesis).
-func (o *ObjectRef) Value() map[string]interface{} {
-	return o.value
-}
-
-// ToValue implements Ref.ToValue() for synthesis/serialization.
-// Returns the object (map) value as interface{} for JSON serialization.
-func (o *ObjectRef) ToValue() interface{} {
-	return o.value
+	valueRef[map[string]interface{}]
 }
 
 // Field accesses a nested field in the object and returns a new ObjectRef.
@@ -589,21 +842,8 @@ func (o *ObjectRef) ToValue() interface{} {
 //	database := config.Field("database")
 //	// Result: "${ $context.config.database }"
 func (o *ObjectRef) Field(name string) *ObjectRef {
-	var expr string
-	if o.isComputed {
-		expr = fmt.Sprintf("(%s.%s)", o.rawExpression, name)
-	} else {
-		expr = fmt.Sprintf("($context.%s.%s)", o.name, name)
-	}
-	return &ObjectRef{
-		baseRef: baseRef{
-			name:         "",
-			isSecret:     o.isSecret,
-			isComputed:   true,
-			rawExpression: expr,
-		},
-		value: nil, // Nested value, not known at synthesis time
-	}
+	// Nested value, not known at synthesis time
+	return &ObjectRef{derive[map[string]interface{}](fmt.Sprintf("(%s.%s)", o.operand(), name), o.isSecret, &o.baseRef)}
 }
 
 // FieldAsString accesses a nested field and returns it as a StringRef.
@@ -615,68 +855,106 @@ func (o *ObjectRef) Field(name string) *ObjectRef {
 //	dbHost := config.FieldAsString("database", "host")
 //	// Result: "${ $context.config.database.host }"
 func (o *ObjectRef) FieldAsString(fields ...string) *StringRef {
-	var expr string
-	if o.isComputed {
-		expr = o.rawExpression
-	} else {
-		expr = fmt.Sprintf("$context.%s", o.name)
-	}
-	for _, field := range fields {
-		expr = fmt.Sprintf("(%s.%s)", expr, field)
-	}
-	return &StringRef{
-		baseRef: baseRef{
-			name:         "",
-			isSecret:     o.isSecret,
-			isComputed:   true,
-			rawExpression: expr,
-		},
-		value: "",
-	}
+	return &StringRef{derive[string](o.fieldPath(fields), o.isSecret, &o.baseRef)}
 }
 
 // FieldAsInt accesses a nested field and returns it as an IntRef.
 // This is useful when you know the field contains an integer value.
 func (o *ObjectRef) FieldAsInt(fields ...string) *IntRef {
-	var expr string
-	if o.isComputed {
-		expr = o.rawExpression
-	} else {
-		expr = fmt.Sprintf("$context.%s", o.name)
-	}
-	for _, field := range fields {
-		expr = fmt.Sprintf("(%s.%s)", expr, field)
-	}
-	return &IntRef{
-		baseRef: baseRef{
-			name:         "",
-			isSecret:     false,
-			isComputed:   true,
-			rawExpression: expr,
-		},
-		value: 0,
-	}
+	return &IntRef{derive[int](o.fieldPath(fields), false, &o.baseRef)}
 }
 
 // FieldAsBool accesses a nested field and returns it as a BoolRef.
 // This is useful when you know the field contains a boolean value.
 func (o *ObjectRef) FieldAsBool(fields ...string) *BoolRef {
-	var expr string
-	if o.isComputed {
-		expr = o.rawExpression
-	} else {
-		expr = fmt.Sprintf("$context.%s", o.name)
-	}
+	return &BoolRef{derive[bool](o.fieldPath(fields), false, &o.baseRef)}
+}
+
+// fieldPath returns the expression reading the nested fields of the object.
+func (o *ObjectRef) fieldPath(fields []string) string {
+	expr := o.operand()
 	for _, field := range fields {
 		expr = fmt.Sprintf("(%s.%s)", expr, field)
 	}
-	return &BoolRef{
-		baseRef: baseRef{
-			name:         "",
-			isSecret:     false,
-			isComputed:   true,
-			rawExpression: expr,
-		},
-		value: false,
+	return expr
+}
+
+// FieldAsList accesses a nested field and returns it as a ListRef.
+// This is useful when you know the field contains an array.
+func (o *ObjectRef) FieldAsList(fields ...string) *ListRef {
+	return &ListRef{derive[[]interface{}](o.fieldPath(fields), o.isSecret, &o.baseRef)}
+}
+
+// =============================================================================
+// ListRef - Reference to a list value
+// =============================================================================
+
+// ListRef represents a reference to a list (array) value in the workflow context.
+// It provides methods for reading and transforming the list that generate JQ
+// expressions for runtime evaluation, so FOR loops and array handling don't
+// need hand-written expressions.
+//
+// Example:
+//
+//	regions := ctx.SetList("regions", []interface{}{"us-east-1", "eu-west-1"})
+//	workflow.ForTask("deploy", workflow.WithIn(regions), ...)
+//	count := regions.Len()
+//	// Result: "${ ($context.regions | length) }"
+type ListRef struct {
+	valueRef[[]interface{}]
+}
+
+// Len returns an IntRef with the number of items in the list.
+func (l *ListRef) Len() *IntRef {
+	return &IntRef{derive[int](fmt.Sprintf("(%s | length)", l.operand()), false, &l.baseRef)}
+}
+
+// Index returns the item at position i. Negative positions count from the end.
+// The item is returned as an ObjectRef; use its FieldAs methods to read
+// typed fields.
+//
+// Example:
+//
+//	last := regions.Index(-1)
+//	// Result: "${ ($context.regions[-1]) }"
+func (l *ListRef) Index(i int) *ObjectRef {
+	return &ObjectRef{derive[map[string]interface{}](fmt.Sprintf("(%s[%d])", l.operand(), i), l.isSecret, &l.baseRef)}
+}
+
+// First returns the first item of the list, like Index(0).
+func (l *ListRef) First() *ObjectRef {
+	return l.Index(0)
+}
+
+// Filter returns a ListRef with the items for which cond is true. cond is a
+// JQ expression evaluated against each item, with or without "${ }".
+//
+// Example:
+//
+//	open := issues.Filter(`.state == "open"`)
+//	// Result: "${ ($context.issues | map(select(.state == "open"))) }"
+func (l *ListRef) Filter(cond string) *ListRef {
+	expr := fmt.Sprintf("(%s | map(select(%s)))", l.operand(), jqBody(cond))
+	return &ListRef{derive[[]interface{}](expr, l.isSecret, &l.baseRef)}
+}
+
+// Map returns a ListRef with expr applied to each item. expr is a JQ
+// expression evaluated against each item, with or without "${ }".
+//
+// Example:
+//
+//	titles := issues.Map(".title")
+//	// Result: "${ ($context.issues | map(.title)) }"
+func (l *ListRef) Map(expr string) *ListRef {
+	mapped := fmt.Sprintf("(%s | map(%s))", l.operand(), jqBody(expr))
+	return &ListRef{derive[[]interface{}](mapped, l.isSecret, &l.baseRef)}
+}
+
+// jqBody strips the "${ }" delimiters from expr, if present.
+func jqBody(expr string) string {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "${") && strings.HasSuffix(expr, "}") {
+		return strings.TrimSpace(expr[2 : len(expr)-1])
 	}
+	return expr
 }
@@ -0,0 +1,30 @@
+package stigmer
+
+import (
+	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+// FuzzStringRef_Concat verifies runtime string expressions stay well formed
+// for arbitrary literal parts (embedded quotes, backslashes, "${").
+func FuzzStringRef_Concat(f *testing.F) {
+	f.Add("/users/", `"`)
+	f.Add(`\`, "${ .a")
+	f.Add("}", "")
+	f.Fuzz(func(t *testing.T, a, b string) {
+		ctx := NewContext()
+		name := ctx.SetString("name", "alice").Upper()
+
+		for _, ref := range []*StringRef{
+			name.Concat(a, b),
+			name.Append(a),
+			name.Prepend(b),
+		} {
+			expr := ref.Expression()
+			if got := workflow.Normalize(expr); got != expr {
+				t.Fatalf("corrupt expression %q (normalized to %q)", expr, got)
+			}
+		}
+	})
+}
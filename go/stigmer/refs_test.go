@@ -2,6 +2,9 @@ package stigmer
 
 import (
 	"testing"
+	"time"
+
+	"github.com/leftbin/stigmer-sdk/go/workflow"
 )
 
 // =============================================================================
@@ -9,10 +12,10 @@ import (
 // =============================================================================
 
 func TestStringRef_Expression(t *testing.T) {
-	ref := &StringRef{
+	ref := &StringRef{valueRef: valueRef[string]{
 		baseRef: baseRef{name: "apiURL"},
 		value:   "https://api.example.com",
-	}
+	}}
 
 	expected := "${ $context.apiURL }"
 	if got := ref.Expression(); got != expected {
@@ -21,10 +24,10 @@ func TestStringRef_Expression(t *testing.T) {
 }
 
 func TestStringRef_Name(t *testing.T) {
-	ref := &StringRef{
+	ref := &StringRef{valueRef: valueRef[string]{
 		baseRef: baseRef{name: "apiURL"},
 		value:   "https://api.example.com",
-	}
+	}}
 
 	expected := "apiURL"
 	if got := ref.Name(); got != expected {
@@ -43,10 +46,10 @@ func TestStringRef_IsSecret(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ref := &StringRef{
+			ref := &StringRef{valueRef: valueRef[string]{
 				baseRef: baseRef{name: "test", isSecret: tt.isSecret},
 				value:   "value",
-			}
+			}}
 
 			if got := ref.IsSecret(); got != tt.isSecret {
 				t.Errorf("IsSecret() = %v, want %v", got, tt.isSecret)
@@ -57,10 +60,10 @@ func TestStringRef_IsSecret(t *testing.T) {
 
 func TestStringRef_Value(t *testing.T) {
 	expected := "https://api.example.com"
-	ref := &StringRef{
+	ref := &StringRef{valueRef: valueRef[string]{
 		baseRef: baseRef{name: "apiURL"},
 		value:   expected,
-	}
+	}}
 
 	if got := ref.Value(); got != expected {
 		t.Errorf("Value() = %q, want %q", got, expected)
@@ -76,39 +79,39 @@ func TestStringRef_Concat(t *testing.T) {
 	}{
 		{
 			name: "concat with literal string",
-			base: &StringRef{
+			base: &StringRef{valueRef: valueRef[string]{
 				baseRef: baseRef{name: "apiURL"},
 				value:   "https://api.example.com",
-			},
+			}},
 			parts:    []interface{}{"/users"},
 			expected: `${ $context.apiURL + "/users" }`,
 		},
 		{
 			name: "concat with another StringRef",
-			base: &StringRef{
+			base: &StringRef{valueRef: valueRef[string]{
 				baseRef: baseRef{name: "baseURL"},
 				value:   "https://api.example.com",
-			},
+			}},
 			parts: []interface{}{
-				&StringRef{
+				&StringRef{valueRef: valueRef[string]{
 					baseRef: baseRef{name: "path"},
 					value:   "/users",
-				},
+				}},
 			},
 			expected: `${ $context.baseURL + $context.path }`,
 		},
 		{
 			name: "concat multiple parts",
-			base: &StringRef{
+			base: &StringRef{valueRef: valueRef[string]{
 				baseRef: baseRef{name: "baseURL"},
 				value:   "https://api.example.com",
-			},
+			}},
 			parts: []interface{}{
 				"/users/",
-				&StringRef{
+				&StringRef{valueRef: valueRef[string]{
 					baseRef: baseRef{name: "userID"},
 					value:   "123",
-				},
+				}},
 			},
 			expected: `${ $context.baseURL + "/users/" + $context.userID }`,
 		},
@@ -125,10 +128,10 @@ func TestStringRef_Concat(t *testing.T) {
 }
 
 func TestStringRef_Upper(t *testing.T) {
-	ref := &StringRef{
+	ref := &StringRef{valueRef: valueRef[string]{
 		baseRef: baseRef{name: "name"},
 		value:   "alice",
-	}
+	}}
 
 	result := ref.Upper()
 	expected := "${ ($context.name | ascii_upcase) }"
@@ -139,10 +142,10 @@ func TestStringRef_Upper(t *testing.T) {
 }
 
 func TestStringRef_Lower(t *testing.T) {
-	ref := &StringRef{
+	ref := &StringRef{valueRef: valueRef[string]{
 		baseRef: baseRef{name: "name"},
 		value:   "ALICE",
-	}
+	}}
 
 	result := ref.Lower()
 	expected := "${ ($context.name | ascii_downcase) }"
@@ -153,10 +156,10 @@ func TestStringRef_Lower(t *testing.T) {
 }
 
 func TestStringRef_Prepend(t *testing.T) {
-	ref := &StringRef{
+	ref := &StringRef{valueRef: valueRef[string]{
 		baseRef: baseRef{name: "path"},
 		value:   "users",
-	}
+	}}
 
 	result := ref.Prepend("/api/")
 	expected := `${ ("/api/" + $context.path) }`
@@ -167,10 +170,10 @@ func TestStringRef_Prepend(t *testing.T) {
 }
 
 func TestStringRef_Append(t *testing.T) {
-	ref := &StringRef{
+	ref := &StringRef{valueRef: valueRef[string]{
 		baseRef: baseRef{name: "base"},
 		value:   "https://api.example.com",
-	}
+	}}
 
 	result := ref.Append("/v1")
 	expected := `${ ($context.base + "/v1") }`
@@ -185,10 +188,10 @@ func TestStringRef_Append(t *testing.T) {
 // =============================================================================
 
 func TestIntRef_Expression(t *testing.T) {
-	ref := &IntRef{
+	ref := &IntRef{valueRef: valueRef[int]{
 		baseRef: baseRef{name: "retries"},
 		value:   3,
-	}
+	}}
 
 	expected := "${ $context.retries }"
 	if got := ref.Expression(); got != expected {
@@ -198,10 +201,10 @@ func TestIntRef_Expression(t *testing.T) {
 
 func TestIntRef_Value(t *testing.T) {
 	expected := 42
-	ref := &IntRef{
+	ref := &IntRef{valueRef: valueRef[int]{
 		baseRef: baseRef{name: "answer"},
 		value:   expected,
-	}
+	}}
 
 	if got := ref.Value(); got != expected {
 		t.Errorf("Value() = %d, want %d", got, expected)
@@ -209,14 +212,14 @@ func TestIntRef_Value(t *testing.T) {
 }
 
 func TestIntRef_Add(t *testing.T) {
-	base := &IntRef{
+	base := &IntRef{valueRef: valueRef[int]{
 		baseRef: baseRef{name: "base"},
 		value:   10,
-	}
-	increment := &IntRef{
+	}}
+	increment := &IntRef{valueRef: valueRef[int]{
 		baseRef: baseRef{name: "increment"},
 		value:   5,
-	}
+	}}
 
 	result := base.Add(increment)
 	expected := "${ ($context.base + $context.increment) }"
@@ -227,14 +230,14 @@ func TestIntRef_Add(t *testing.T) {
 }
 
 func TestIntRef_Subtract(t *testing.T) {
-	base := &IntRef{
+	base := &IntRef{valueRef: valueRef[int]{
 		baseRef: baseRef{name: "total"},
 		value:   100,
-	}
-	decrement := &IntRef{
+	}}
+	decrement := &IntRef{valueRef: valueRef[int]{
 		baseRef: baseRef{name: "used"},
 		value:   30,
-	}
+	}}
 
 	result := base.Subtract(decrement)
 	expected := "${ ($context.total - $context.used) }"
@@ -245,14 +248,14 @@ func TestIntRef_Subtract(t *testing.T) {
 }
 
 func TestIntRef_Multiply(t *testing.T) {
-	base := &IntRef{
+	base := &IntRef{valueRef: valueRef[int]{
 		baseRef: baseRef{name: "quantity"},
 		value:   5,
-	}
-	multiplier := &IntRef{
+	}}
+	multiplier := &IntRef{valueRef: valueRef[int]{
 		baseRef: baseRef{name: "price"},
 		value:   10,
-	}
+	}}
 
 	result := base.Multiply(multiplier)
 	expected := "${ ($context.quantity * $context.price) }"
@@ -263,14 +266,14 @@ func TestIntRef_Multiply(t *testing.T) {
 }
 
 func TestIntRef_Divide(t *testing.T) {
-	base := &IntRef{
+	base := &IntRef{valueRef: valueRef[int]{
 		baseRef: baseRef{name: "total"},
 		value:   100,
-	}
-	divisor := &IntRef{
+	}}
+	divisor := &IntRef{valueRef: valueRef[int]{
 		baseRef: baseRef{name: "count"},
 		value:   5,
-	}
+	}}
 
 	result := base.Divide(divisor)
 	expected := "${ ($context.total / $context.count) }"
@@ -285,10 +288,10 @@ func TestIntRef_Divide(t *testing.T) {
 // =============================================================================
 
 func TestBoolRef_Expression(t *testing.T) {
-	ref := &BoolRef{
+	ref := &BoolRef{valueRef: valueRef[bool]{
 		baseRef: baseRef{name: "isEnabled"},
 		value:   true,
-	}
+	}}
 
 	expected := "${ $context.isEnabled }"
 	if got := ref.Expression(); got != expected {
@@ -307,10 +310,10 @@ func TestBoolRef_Value(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ref := &BoolRef{
+			ref := &BoolRef{valueRef: valueRef[bool]{
 				baseRef: baseRef{name: "test"},
 				value:   tt.expected,
-			}
+			}}
 
 			if got := ref.Value(); got != tt.expected {
 				t.Errorf("Value() = %v, want %v", got, tt.expected)
@@ -320,14 +323,14 @@ func TestBoolRef_Value(t *testing.T) {
 }
 
 func TestBoolRef_And(t *testing.T) {
-	hasAccess := &BoolRef{
+	hasAccess := &BoolRef{valueRef: valueRef[bool]{
 		baseRef: baseRef{name: "hasAccess"},
 		value:   true,
-	}
-	isEnabled := &BoolRef{
+	}}
+	isEnabled := &BoolRef{valueRef: valueRef[bool]{
 		baseRef: baseRef{name: "isEnabled"},
 		value:   true,
-	}
+	}}
 
 	result := hasAccess.And(isEnabled)
 	expected := "${ ($context.hasAccess and $context.isEnabled) }"
@@ -338,14 +341,14 @@ func TestBoolRef_And(t *testing.T) {
 }
 
 func TestBoolRef_Or(t *testing.T) {
-	isAdmin := &BoolRef{
+	isAdmin := &BoolRef{valueRef: valueRef[bool]{
 		baseRef: baseRef{name: "isAdmin"},
 		value:   false,
-	}
-	isOwner := &BoolRef{
+	}}
+	isOwner := &BoolRef{valueRef: valueRef[bool]{
 		baseRef: baseRef{name: "isOwner"},
 		value:   true,
-	}
+	}}
 
 	result := isAdmin.Or(isOwner)
 	expected := "${ ($context.isAdmin or $context.isOwner) }"
@@ -356,10 +359,10 @@ func TestBoolRef_Or(t *testing.T) {
 }
 
 func TestBoolRef_Not(t *testing.T) {
-	isEnabled := &BoolRef{
+	isEnabled := &BoolRef{valueRef: valueRef[bool]{
 		baseRef: baseRef{name: "isEnabled"},
 		value:   true,
-	}
+	}}
 
 	result := isEnabled.Not()
 	expected := "${ ($context.isEnabled | not) }"
@@ -374,13 +377,13 @@ func TestBoolRef_Not(t *testing.T) {
 // =============================================================================
 
 func TestObjectRef_Expression(t *testing.T) {
-	ref := &ObjectRef{
+	ref := &ObjectRef{valueRef: valueRef[map[string]interface{}]{
 		baseRef: baseRef{name: "config"},
 		value: map[string]interface{}{
 			"host": "localhost",
 			"port": 5432,
 		},
-	}
+	}}
 
 	expected := "${ $context.config }"
 	if got := ref.Expression(); got != expected {
@@ -393,10 +396,10 @@ func TestObjectRef_Value(t *testing.T) {
 		"host": "localhost",
 		"port": 5432,
 	}
-	ref := &ObjectRef{
+	ref := &ObjectRef{valueRef: valueRef[map[string]interface{}]{
 		baseRef: baseRef{name: "config"},
 		value:   expected,
-	}
+	}}
 
 	got := ref.Value()
 	if got["host"] != expected["host"] || got["port"] != expected["port"] {
@@ -405,7 +408,7 @@ func TestObjectRef_Value(t *testing.T) {
 }
 
 func TestObjectRef_Field(t *testing.T) {
-	config := &ObjectRef{
+	config := &ObjectRef{valueRef: valueRef[map[string]interface{}]{
 		baseRef: baseRef{name: "config"},
 		value: map[string]interface{}{
 			"database": map[string]interface{}{
@@ -413,7 +416,7 @@ func TestObjectRef_Field(t *testing.T) {
 				"port": 5432,
 			},
 		},
-	}
+	}}
 
 	database := config.Field("database")
 	expected := "${ ($context.config.database) }"
@@ -424,7 +427,7 @@ func TestObjectRef_Field(t *testing.T) {
 }
 
 func TestObjectRef_NestedFields(t *testing.T) {
-	config := &ObjectRef{
+	config := &ObjectRef{valueRef: valueRef[map[string]interface{}]{
 		baseRef: baseRef{name: "config"},
 		value: map[string]interface{}{
 			"database": map[string]interface{}{
@@ -433,7 +436,7 @@ func TestObjectRef_NestedFields(t *testing.T) {
 				},
 			},
 		},
-	}
+	}}
 
 	host := config.Field("database").Field("connection").Field("host")
 	expected := "${ ((($context.config.database).connection).host) }"
@@ -444,14 +447,14 @@ func TestObjectRef_NestedFields(t *testing.T) {
 }
 
 func TestObjectRef_FieldAsString(t *testing.T) {
-	config := &ObjectRef{
+	config := &ObjectRef{valueRef: valueRef[map[string]interface{}]{
 		baseRef: baseRef{name: "config"},
 		value: map[string]interface{}{
 			"database": map[string]interface{}{
 				"host": "localhost",
 			},
 		},
-	}
+	}}
 
 	host := config.FieldAsString("database", "host")
 	expected := "${ (($context.config.database).host) }"
@@ -462,14 +465,14 @@ func TestObjectRef_FieldAsString(t *testing.T) {
 }
 
 func TestObjectRef_FieldAsInt(t *testing.T) {
-	config := &ObjectRef{
+	config := &ObjectRef{valueRef: valueRef[map[string]interface{}]{
 		baseRef: baseRef{name: "config"},
 		value: map[string]interface{}{
 			"database": map[string]interface{}{
 				"port": 5432,
 			},
 		},
-	}
+	}}
 
 	port := config.FieldAsInt("database", "port")
 	expected := "${ (($context.config.database).port) }"
@@ -480,14 +483,14 @@ func TestObjectRef_FieldAsInt(t *testing.T) {
 }
 
 func TestObjectRef_FieldAsBool(t *testing.T) {
-	config := &ObjectRef{
+	config := &ObjectRef{valueRef: valueRef[map[string]interface{}]{
 		baseRef: baseRef{name: "config"},
 		value: map[string]interface{}{
 			"features": map[string]interface{}{
 				"enabled": true,
 			},
 		},
-	}
+	}}
 
 	enabled := config.FieldAsBool("features", "enabled")
 	expected := "${ (($context.config.features).enabled) }"
@@ -503,18 +506,18 @@ func TestObjectRef_FieldAsBool(t *testing.T) {
 
 func TestComplexExpressions_StringConcat(t *testing.T) {
 	// Test complex string concatenation with multiple operations
-	base := &StringRef{
+	base := &StringRef{valueRef: valueRef[string]{
 		baseRef: baseRef{name: "baseURL"},
 		value:   "https://api.example.com",
-	}
-	version := &StringRef{
+	}}
+	version := &StringRef{valueRef: valueRef[string]{
 		baseRef: baseRef{name: "version"},
 		value:   "v1",
-	}
-	endpoint := &StringRef{
+	}}
+	endpoint := &StringRef{valueRef: valueRef[string]{
 		baseRef: baseRef{name: "endpoint"},
 		value:   "users",
-	}
+	}}
 
 	// Build: baseURL + "/api/" + version + "/" + endpoint
 	fullURL := base.Concat("/api/", version, "/", endpoint)
@@ -531,18 +534,18 @@ func TestComplexExpressions_StringConcat(t *testing.T) {
 
 func TestComplexExpressions_IntArithmetic(t *testing.T) {
 	// Test complex integer arithmetic
-	base := &IntRef{
+	base := &IntRef{valueRef: valueRef[int]{
 		baseRef: baseRef{name: "base"},
 		value:   100,
-	}
-	multiplier := &IntRef{
+	}}
+	multiplier := &IntRef{valueRef: valueRef[int]{
 		baseRef: baseRef{name: "multiplier"},
 		value:   2,
-	}
-	offset := &IntRef{
+	}}
+	offset := &IntRef{valueRef: valueRef[int]{
 		baseRef: baseRef{name: "offset"},
 		value:   10,
-	}
+	}}
 
 	// Build: (base * multiplier) + offset
 	result := base.Multiply(multiplier).Add(offset)
@@ -557,18 +560,18 @@ func TestComplexExpressions_IntArithmetic(t *testing.T) {
 
 func TestComplexExpressions_BoolLogic(t *testing.T) {
 	// Test complex boolean logic
-	isProd := &BoolRef{
+	isProd := &BoolRef{valueRef: valueRef[bool]{
 		baseRef: baseRef{name: "isProd"},
 		value:   true,
-	}
-	isDebug := &BoolRef{
+	}}
+	isDebug := &BoolRef{valueRef: valueRef[bool]{
 		baseRef: baseRef{name: "isDebug"},
 		value:   false,
-	}
-	hasAccess := &BoolRef{
+	}}
+	hasAccess := &BoolRef{valueRef: valueRef[bool]{
 		baseRef: baseRef{name: "hasAccess"},
 		value:   true,
-	}
+	}}
 
 	// Build: (isProd and !isDebug) or hasAccess
 	result := isProd.And(isDebug.Not()).Or(hasAccess)
@@ -583,10 +586,10 @@ func TestComplexExpressions_BoolLogic(t *testing.T) {
 
 func TestSecretPropagation(t *testing.T) {
 	// Test that secret flag is preserved through operations
-	apiKey := &StringRef{
+	apiKey := &StringRef{valueRef: valueRef[string]{
 		baseRef: baseRef{name: "apiKey", isSecret: true},
 		value:   "secret-key-123",
-	}
+	}}
 
 	// Transform the secret - should remain secret
 	header := apiKey.Prepend("Bearer ")
@@ -612,26 +615,26 @@ func TestStringRef_ToValue(t *testing.T) {
 	}{
 		{
 			name: "simple string value",
-			ref: &StringRef{
+			ref: &StringRef{valueRef: valueRef[string]{
 				baseRef: baseRef{name: "apiURL"},
 				value:   "https://api.example.com",
-			},
+			}},
 			expected: "https://api.example.com",
 		},
 		{
 			name: "empty string value",
-			ref: &StringRef{
+			ref: &StringRef{valueRef: valueRef[string]{
 				baseRef: baseRef{name: "empty"},
 				value:   "",
-			},
+			}},
 			expected: "",
 		},
 		{
 			name: "secret string value",
-			ref: &StringRef{
+			ref: &StringRef{valueRef: valueRef[string]{
 				baseRef: baseRef{name: "apiKey", isSecret: true},
 				value:   "secret-123",
-			},
+			}},
 			expected: "secret-123",
 		},
 	}
@@ -659,26 +662,26 @@ func TestIntRef_ToValue(t *testing.T) {
 	}{
 		{
 			name: "positive integer",
-			ref: &IntRef{
+			ref: &IntRef{valueRef: valueRef[int]{
 				baseRef: baseRef{name: "retries"},
 				value:   3,
-			},
+			}},
 			expected: 3,
 		},
 		{
 			name: "zero value",
-			ref: &IntRef{
+			ref: &IntRef{valueRef: valueRef[int]{
 				baseRef: baseRef{name: "zero"},
 				value:   0,
-			},
+			}},
 			expected: 0,
 		},
 		{
 			name: "negative integer",
-			ref: &IntRef{
+			ref: &IntRef{valueRef: valueRef[int]{
 				baseRef: baseRef{name: "offset"},
 				value:   -10,
-			},
+			}},
 			expected: -10,
 		},
 	}
@@ -706,18 +709,18 @@ func TestBoolRef_ToValue(t *testing.T) {
 	}{
 		{
 			name: "true value",
-			ref: &BoolRef{
+			ref: &BoolRef{valueRef: valueRef[bool]{
 				baseRef: baseRef{name: "isProd"},
 				value:   true,
-			},
+			}},
 			expected: true,
 		},
 		{
 			name: "false value",
-			ref: &BoolRef{
+			ref: &BoolRef{valueRef: valueRef[bool]{
 				baseRef: baseRef{name: "isDebug"},
 				value:   false,
-			},
+			}},
 			expected: false,
 		},
 	}
@@ -745,13 +748,13 @@ func TestObjectRef_ToValue(t *testing.T) {
 	}{
 		{
 			name: "simple object",
-			ref: &ObjectRef{
+			ref: &ObjectRef{valueRef: valueRef[map[string]interface{}]{
 				baseRef: baseRef{name: "config"},
 				value: map[string]interface{}{
 					"host": "localhost",
 					"port": 5432,
 				},
-			},
+			}},
 			validate: func(t *testing.T, got interface{}) {
 				m, ok := got.(map[string]interface{})
 				if !ok {
@@ -767,7 +770,7 @@ func TestObjectRef_ToValue(t *testing.T) {
 		},
 		{
 			name: "nested object",
-			ref: &ObjectRef{
+			ref: &ObjectRef{valueRef: valueRef[map[string]interface{}]{
 				baseRef: baseRef{name: "config"},
 				value: map[string]interface{}{
 					"database": map[string]interface{}{
@@ -778,7 +781,7 @@ func TestObjectRef_ToValue(t *testing.T) {
 						"enabled": true,
 					},
 				},
-			},
+			}},
 			validate: func(t *testing.T, got interface{}) {
 				m, ok := got.(map[string]interface{})
 				if !ok {
@@ -804,10 +807,10 @@ func TestObjectRef_ToValue(t *testing.T) {
 		},
 		{
 			name: "empty object",
-			ref: &ObjectRef{
+			ref: &ObjectRef{valueRef: valueRef[map[string]interface{}]{
 				baseRef: baseRef{name: "empty"},
 				value:   map[string]interface{}{},
-			},
+			}},
 			validate: func(t *testing.T, got interface{}) {
 				m, ok := got.(map[string]interface{})
 				if !ok {
@@ -832,27 +835,27 @@ func TestToValue_RefInterface(t *testing.T) {
 	// Test that ToValue() works via the Ref interface
 	var refs []Ref
 	
-	refs = append(refs, &StringRef{
+	refs = append(refs, &StringRef{valueRef: valueRef[string]{
 		baseRef: baseRef{name: "str"},
 		value:   "hello",
-	})
+	}})
 	
-	refs = append(refs, &IntRef{
+	refs = append(refs, &IntRef{valueRef: valueRef[int]{
 		baseRef: baseRef{name: "num"},
 		value:   42,
-	})
+	}})
 	
-	refs = append(refs, &BoolRef{
+	refs = append(refs, &BoolRef{valueRef: valueRef[bool]{
 		baseRef: baseRef{name: "flag"},
 		value:   true,
-	})
+	}})
 	
-	refs = append(refs, &ObjectRef{
+	refs = append(refs, &ObjectRef{valueRef: valueRef[map[string]interface{}]{
 		baseRef: baseRef{name: "obj"},
 		value: map[string]interface{}{
 			"key": "value",
 		},
-	})
+	}})
 	
 	// Verify we can call ToValue() through the interface
 	for i, ref := range refs {
@@ -863,3 +866,257 @@ func TestToValue_RefInterface(t *testing.T) {
 		t.Logf("refs[%d] (%s): ToValue() = %v (type: %T)", i, ref.Name(), value, value)
 	}
 }
+
+// The typed references satisfy workflow.TypedRef for their value type.
+var (
+	_ workflow.TypedRef[string]                 = (*StringRef)(nil)
+	_ workflow.TypedRef[int]                    = (*IntRef)(nil)
+	_ workflow.TypedRef[float64]                = (*FloatRef)(nil)
+	_ workflow.TypedRef[bool]                   = (*BoolRef)(nil)
+	_ workflow.TypedRef[time.Duration]          = (*DurationRef)(nil)
+	_ workflow.TypedRef[time.Time]              = (*TimeRef)(nil)
+	_ workflow.TypedRef[map[string]interface{}] = (*ObjectRef)(nil)
+	_ workflow.TypedRef[[]interface{}]          = (*ListRef)(nil)
+)
+
+func TestIntRef_TimeoutRef(t *testing.T) {
+	ctx := NewContext()
+	timeout := ctx.SetInt("timeout", 30)
+
+	cfg := &workflow.HttpCallTaskConfig{}
+	workflow.TimeoutRef(timeout)(cfg)
+	if cfg.TimeoutSeconds != 30 {
+		t.Errorf("TimeoutSeconds = %d, want 30", cfg.TimeoutSeconds)
+	}
+}
+
+func TestListRef_Expressions(t *testing.T) {
+	ctx := NewContext()
+	issues := ctx.SetList("issues", []interface{}{
+		map[string]interface{}{"title": "a", "state": "open"},
+	})
+
+	tests := []struct {
+		name     string
+		ref      Ref
+		expected string
+	}{
+		{"list", issues, "${ $context.issues }"},
+		{"len", issues.Len(), "${ ($context.issues | length) }"},
+		{"index", issues.Index(2), "${ ($context.issues[2]) }"},
+		{"first field", issues.First().FieldAsString("title"), "${ (($context.issues[0]).title) }"},
+		{"filter", issues.Filter(`${ .state == "open" }`), `${ ($context.issues | map(select(.state == "open"))) }`},
+		{"map", issues.Map(".title").Len(), "${ (($context.issues | map(.title)) | length) }"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ref.Expression(); got != tt.expected {
+				t.Errorf("Expression() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+
+	if got := ctx.GetList("issues"); got != issues {
+		t.Errorf("GetList() = %v, want %v", got, issues)
+	}
+	if got := issues.ToValue().([]interface{}); len(got) != 1 {
+		t.Errorf("ToValue() = %v, want the list", got)
+	}
+}
+
+func TestFloatRef_Expressions(t *testing.T) {
+	ctx := NewContext()
+	price := ctx.SetFloat("price", 19.987)
+	rate := ctx.SetFloat("rate", 1.2)
+
+	tests := []struct {
+		name     string
+		ref      Ref
+		expected string
+	}{
+		{"add", price.Add(rate), "${ ($context.price + $context.rate) }"},
+		{"subtract", price.Subtract(rate), "${ ($context.price - $context.rate) }"},
+		{"divide", price.Divide(rate), "${ ($context.price / $context.rate) }"},
+		{"round", price.Round(0), "${ ($context.price | round) }"},
+		{"round places", price.Multiply(rate).Round(2), "${ ((($context.price * $context.rate) * 100 | round) / 100) }"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ref.Expression(); got != tt.expected {
+				t.Errorf("Expression() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+
+	if got := ctx.GetFloat("price"); got != price || got.Value() != 19.987 {
+		t.Errorf("GetFloat() = %v, want price", got)
+	}
+	if got := price.ToValue(); got != 19.987 {
+		t.Errorf("ToValue() = %v, want 19.987", got)
+	}
+}
+
+func TestTimeRef_Expressions(t *testing.T) {
+	ctx := NewContext()
+	start := ctx.SetTime("start", time.Date(2026, 3, 1, 10, 0, 0, 500, time.FixedZone("CET", 3600)))
+	end := ctx.SetTime("end", time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+	ttl := ctx.SetDuration("ttl", 90*time.Minute)
+
+	tests := []struct {
+		name     string
+		ref      Ref
+		expected string
+	}{
+		{"add", start.Add(2 * time.Hour), "${ ($context.start | fromdateiso8601 + 7200 | todateiso8601) }"},
+		{"subtract", start.Add(-time.Minute), "${ ($context.start | fromdateiso8601 + -60 | todateiso8601) }"},
+		{"add duration", start.AddDuration(ttl), "${ ($context.start | fromdateiso8601 + 5400 | todateiso8601) }"},
+		{"before", start.Before(end), "${ (($context.start | fromdateiso8601) < ($context.end | fromdateiso8601)) }"},
+		{"after", start.Add(time.Hour).After(end), "${ ((($context.start | fromdateiso8601 + 3600 | todateiso8601) | fromdateiso8601) > ($context.end | fromdateiso8601)) }"},
+		{"unix", end.Unix(), "${ ($context.end | fromdateiso8601) }"},
+		{"format", start.Format("%Y-%m-%d"), `${ ($context.start | fromdateiso8601 | strftime("%Y-%m-%d")) }`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ref.Expression(); got != tt.expected {
+				t.Errorf("Expression() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+
+	if got := ctx.GetTime("start"); got != start {
+		t.Errorf("GetTime() = %v, want start", got)
+	}
+	// Stored in UTC with second precision, the format fromdateiso8601 parses
+	if got := start.ToValue(); got != "2026-03-01T09:00:00Z" {
+		t.Errorf("ToValue() = %v, want 2026-03-01T09:00:00Z", got)
+	}
+}
+
+func TestDurationRef_Add(t *testing.T) {
+	ctx := NewContext()
+	base := ctx.SetDuration("base", 30*time.Second)
+	grace := ctx.SetDuration("grace", 15*time.Second)
+
+	sum := base.Add(grace)
+	if got := sum.Value(); got != 45*time.Second {
+		t.Errorf("Value() = %v, want 45s", got)
+	}
+	if got := sum.Expression(); got != `${ "45s" }` {
+		t.Errorf("Expression() = %q, want %q", got, `${ "45s" }`)
+	}
+	if !base.wasUsed() || !grace.wasUsed() {
+		t.Error("Add() did not mark its operands as used")
+	}
+}
+
+func TestStringRef_Transformations(t *testing.T) {
+	ctx := NewContext()
+	tags := ctx.SetString("tags", "api,backend,go")
+	branch := ctx.SetString("branch", "feature/login")
+	token := ctx.SetSecret("token", "s3cr3t")
+
+	tests := []struct {
+		name     string
+		ref      Ref
+		expected string
+	}{
+		{"split", tags.Split(","), `${ ($context.tags | split(",")) }`},
+		{"replace", branch.Replace("/", "-"), `${ ($context.branch | split("/") | join("-")) }`},
+		{"trim space", branch.TrimSpace(), `${ ($context.branch | sub("^\\s+"; "") | sub("\\s+$"; "")) }`},
+		{"slice", branch.Slice(0, 7), "${ ($context.branch[0:7]) }"},
+		{"slice from end", branch.Slice(-5, -1), "${ ($context.branch[-5:-1]) }"},
+		{"chained", branch.TrimSpace().Replace("/", "-").Lower(), `${ ((($context.branch | sub("^\\s+"; "") | sub("\\s+$"; "")) | split("/") | join("-")) | ascii_downcase) }`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ref.Expression(); got != tt.expected {
+				t.Errorf("Expression() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+
+	if !token.Slice(0, 4).IsSecret() {
+		t.Error("Slice() of a secret is not secret")
+	}
+	if !tags.wasUsed() {
+		t.Error("Split() did not mark the variable as used once referenced")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	ctx := NewContext()
+	apiBase := ctx.SetString("apiBase", "https://api.example.com")
+	page := ctx.SetInt("page", 2)
+	token := ctx.SetSecret("token", "s3cr3t")
+	fetch := workflow.HttpCallTask("fetch", workflow.WithHTTPGet(), workflow.WithURI("https://api.example.com"))
+
+	tests := []struct {
+		name     string
+		ref      *StringRef
+		expected string
+	}{
+		{"string ref", Format("%s/users", apiBase), `${ ($context.apiBase + "/users") }`},
+		{"int ref", Format("page %d of 10", page), `${ ("page " + ($context.page | tostring) + " of 10") }`},
+		{"task field", Format("%s/users/%v", apiBase, fetch.Field("id")), `${ ($context.apiBase + "/users/" + ($context.fetch.id | tostring)) }`},
+		{"plain values", Format("%d%% done by %s", 50, "ci"), `${ ("50" + "% done by " + "ci") }`},
+		{"missing argument", Format("%s and %s", apiBase), `${ ($context.apiBase + " and %!s(MISSING)") }`},
+		{"no verbs", Format("static"), `${ ("static") }`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ref.Expression(); got != tt.expected {
+				t.Errorf("Expression() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+
+	if !page.wasUsed() {
+		t.Error("Format() did not mark its arguments as used")
+	}
+	if Format("%s", apiBase).IsSecret() || !Format("Bearer %s", token).IsSecret() {
+		t.Error("Format() secrecy does not follow its arguments")
+	}
+}
+
+func TestIntRef_Comparisons(t *testing.T) {
+	ctx := NewContext()
+	attempts := ctx.SetInt("attempts", 0)
+	maxAttempts := ctx.SetInt("maxAttempts", 3)
+
+	tests := []struct {
+		name     string
+		ref      *BoolRef
+		expected string
+	}{
+		{"greater than ref", attempts.GreaterThan(maxAttempts), "${ ($context.attempts > $context.maxAttempts) }"},
+		{"greater than int", attempts.GreaterThan(3), "${ ($context.attempts > 3) }"},
+		{"less than", attempts.LessThan(maxAttempts), "${ ($context.attempts < $context.maxAttempts) }"},
+		{"equals", attempts.Equals(0), "${ ($context.attempts == 0) }"},
+		{"between", attempts.Between(1, maxAttempts), "${ (($context.attempts >= 1) and ($context.attempts <= $context.maxAttempts)) }"},
+		{"computed operand", attempts.Add(maxAttempts).LessThan(10), "${ (($context.attempts + $context.maxAttempts) < 10) }"},
+		{"combined", attempts.GreaterThan(0).And(attempts.LessThan(maxAttempts).Not()), "${ (($context.attempts > 0) and (($context.attempts < $context.maxAttempts) | not)) }"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ref.Expression(); got != tt.expected {
+				t.Errorf("Expression() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+
+	// The condition can drive a SWITCH task
+	task := workflow.SwitchTask("route",
+		workflow.WithCase(attempts.GreaterThan(maxAttempts).Expression(), "giveUp"),
+		workflow.WithDefault("retry"),
+	)
+	cfg := task.Config.(*workflow.SwitchTaskConfig)
+	if got := cfg.Cases[0].Condition; got != "${ ($context.attempts > $context.maxAttempts) }" {
+		t.Errorf("case condition = %q", got)
+	}
+}
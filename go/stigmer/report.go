@@ -0,0 +1,156 @@
+package stigmer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+// dryRunReportEnv selects where the dry-run report is written when no
+// WithDryRunReport option is given: "-" for stdout, or a file path.
+const dryRunReportEnv = "STIGMER_DRY_RUN_REPORT"
+
+// Report summarizes synthesized manifests, so CI pipelines can gate on what a
+// program would deploy.
+type Report struct {
+	Agents    []AgentReport    `json:"agents"`
+	Workflows []WorkflowReport `json:"workflows"`
+	Tasks     int              `json:"tasks"` // Top-level tasks across all workflows
+
+	// EnvVars and Secrets are the environment variables and secrets the
+	// resources reference: those declared by agents, and the ${.env_vars.X}
+	// and ${.secrets.X} placeholders of workflows. Sorted and deduplicated.
+	EnvVars []string `json:"envVars"`
+	Secrets []string `json:"secrets"`
+
+	// Warnings lists the data conversion dropped or replaced with a default
+	Warnings []ConversionWarning `json:"warnings"`
+}
+
+// AgentReport summarizes one agent.
+type AgentReport struct {
+	Name       string `json:"name"`
+	Skills     int    `json:"skills"`
+	MCPServers int    `json:"mcpServers"`
+	SubAgents  int    `json:"subAgents"`
+}
+
+// WorkflowReport summarizes one workflow.
+type WorkflowReport struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Tasks     int    `json:"tasks"`
+}
+
+// Report summarizes the manifests.
+func (m *Manifests) Report() *Report {
+	r := &Report{Agents: []AgentReport{}, Workflows: []WorkflowReport{}, Warnings: []ConversionWarning{}}
+	r.Warnings = append(r.Warnings, m.Warnings...)
+	envVars, secrets := make(map[string]bool), make(map[string]bool)
+
+	for _, ag := range m.AgentManifest.GetAgents() {
+		r.Agents = append(r.Agents, AgentReport{
+			Name:       ag.GetName(),
+			Skills:     len(ag.GetSkills()),
+			MCPServers: len(ag.GetMcpServers()),
+			SubAgents:  len(ag.GetSubAgents()),
+		})
+		for _, env := range ag.GetEnvironmentVariables() {
+			if env.GetIsSecret() {
+				secrets[env.GetName()] = true
+			} else {
+				envVars[env.GetName()] = true
+			}
+		}
+	}
+
+	for _, wf := range m.WorkflowManifest.GetWorkflows() {
+		spec := wf.GetSpec()
+		tasks := len(spec.GetTasks())
+		r.Workflows = append(r.Workflows, WorkflowReport{
+			Namespace: spec.GetDocument().GetNamespace(),
+			Name:      spec.GetDocument().GetName(),
+			Tasks:     tasks,
+		})
+		r.Tasks += tasks
+
+		// Placeholders survive JSON encoding unchanged, wherever they are nested
+		data, err := protojson.Marshal(wf)
+		if err != nil {
+			continue
+		}
+		for _, ref := range workflow.ExtractRuntimeRefs(string(data)) {
+			name := strings.TrimSuffix(ref[strings.LastIndex(ref, ".")+1:], "}")
+			if strings.HasPrefix(ref, "${.secrets.") {
+				secrets[name] = true
+			} else {
+				envVars[name] = true
+			}
+		}
+	}
+
+	r.EnvVars, r.Secrets = sortedKeys(envVars), sortedKeys(secrets)
+	return r
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// WithDryRunReport writes a JSON Report to w when synthesis runs in dry-run
+// mode (without STIGMER_OUT_DIR, WithSink or WithWriter), so CI pipelines can
+// check what a change would deploy.
+//
+// The report can also be requested with STIGMER_DRY_RUN_REPORT, set to "-"
+// for stdout or to a file path.
+//
+// Example:
+//
+//	stigmer.Run(func(ctx *stigmer.Context) error {
+//	    // ... define agents and workflows
+//	    return nil
+//	}, stigmer.WithDryRunReport(os.Stdout))
+func WithDryRunReport(w io.Writer) ContextOption {
+	return func(c *Context) {
+		c.dryRunReport = w
+	}
+}
+
+// writeDryRunReport writes the report of m to the configured destination, if any.
+func (c *Context) writeDryRunReport(m *Manifests) error {
+	w, path := c.dryRunReport, os.Getenv(dryRunReportEnv)
+	if w == nil && path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(m.Report(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode dry-run report: %w", err)
+	}
+	data = append(data, '\n')
+
+	switch {
+	case w != nil:
+		_, err = w.Write(data)
+	case path == "-":
+		_, err = os.Stdout.Write(data)
+	default:
+		err = os.WriteFile(path, data, 0644)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write dry-run report: %w", err)
+	}
+	return nil
+}
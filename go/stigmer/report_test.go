@@ -0,0 +1,80 @@
+package stigmer
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+func defineReportResources(ctx *Context) error {
+	if err := defineBundleResources(ctx); err != nil {
+		return err
+	}
+	wf, err := workflow.New(ctx, workflow.WithNamespace("test"), workflow.WithName("report-workflow"))
+	if err != nil {
+		return err
+	}
+	wf.HttpGet("fetch", "https://api-"+workflow.RuntimeEnv("REGION")+".example.com",
+		workflow.Header("Authorization", workflow.RuntimeSecret("API_TOKEN")),
+	).ExportFields("id")
+	return nil
+}
+
+func TestSynthesize_DryRunReport(t *testing.T) {
+	t.Setenv("STIGMER_OUT_DIR", "")
+	t.Setenv(dryRunReportEnv, "")
+
+	var buf bytes.Buffer
+	if err := Run(defineReportResources, WithDryRunReport(&buf)); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	var report Report
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("report is not JSON: %v\n%s", err, buf.String())
+	}
+	if len(report.Agents) != 1 || report.Agents[0].Name != "bundle-agent" {
+		t.Errorf("Agents = %+v, want [bundle-agent]", report.Agents)
+	}
+	if len(report.Workflows) != 2 || report.Workflows[1].Name != "report-workflow" || report.Workflows[1].Tasks != 1 {
+		t.Errorf("Workflows = %+v, want bundle-workflow and report-workflow with 1 task", report.Workflows)
+	}
+	if report.Tasks != 2 {
+		t.Errorf("Tasks = %d, want 2", report.Tasks)
+	}
+	if !reflect.DeepEqual(report.EnvVars, []string{"REGION"}) {
+		t.Errorf("EnvVars = %v, want [REGION]", report.EnvVars)
+	}
+	if !reflect.DeepEqual(report.Secrets, []string{"API_TOKEN"}) {
+		t.Errorf("Secrets = %v, want [API_TOKEN]", report.Secrets)
+	}
+	if len(report.Warnings) != 1 || report.Warnings[0].Task != "fetch" || report.Warnings[0].Field != "export" {
+		t.Errorf("Warnings = %+v, want the dropped ExportFields selection of fetch", report.Warnings)
+	}
+}
+
+func TestSynthesize_DryRunReportEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	t.Setenv("STIGMER_OUT_DIR", "")
+	t.Setenv(dryRunReportEnv, path)
+
+	if err := Run(defineBundleResources); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("report not written: %v", err)
+	}
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("report is not JSON: %v", err)
+	}
+	if len(report.Agents) != 1 || len(report.Workflows) != 1 {
+		t.Errorf("report = %+v, want one agent and one workflow", report)
+	}
+}
@@ -0,0 +1,80 @@
+package stigmer
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ManifestSink receives every file written by synthesis: the manifests (in the
+// configured layout), manifest.sha256 and its signature, and the YAML exports.
+// path is relative to the output root and uses forward slashes, e.g.
+// "workflows/default.deploy.pb".
+type ManifestSink func(path string, data []byte) error
+
+// WithSink sends the synthesized files to sink instead of writing them to
+// STIGMER_OUT_DIR, e.g. to upload them directly to an API. Manifests.OutputDir
+// is empty for hooks, since nothing is written to disk.
+//
+// Example:
+//
+//	stigmer.Run(func(ctx *stigmer.Context) error {
+//	    // ... define agents and workflows
+//	    return nil
+//	}, stigmer.WithSink(func(path string, data []byte) error {
+//	    return bucket.Upload(ctx, "manifests/"+path, data)
+//	}))
+func WithSink(sink ManifestSink) ContextOption {
+	return func(c *Context) {
+		c.sink = sink
+	}
+}
+
+// WithWriter writes the synthesized files to w as a tar stream instead of
+// writing them to STIGMER_OUT_DIR, e.g. to os.Stdout for piping:
+//
+//	go run . | tar -x -C out
+//
+// The archive holds the same files STIGMER_OUT_DIR would, and identical
+// programs produce identical archives. WithWriter takes precedence over WithSink.
+func WithWriter(w io.Writer) ContextOption {
+	return func(c *Context) {
+		c.writer = w
+	}
+}
+
+// resolveSink returns where synthesized files go: the writer, the sink, or
+// STIGMER_OUT_DIR, in that order. outputDir is only set for STIGMER_OUT_DIR.
+// finish must be called once every file is written. A nil sink means dry-run.
+func (c *Context) resolveSink() (sink ManifestSink, outputDir string, finish func() error) {
+	noop := func() error { return nil }
+	switch {
+	case c.writer != nil:
+		tw := tar.NewWriter(c.writer)
+		sink = func(path string, data []byte) error {
+			return writeTarEntry(tw, path, data)
+		}
+		return sink, "", tw.Close
+	case c.sink != nil:
+		return c.sink, "", noop
+	}
+
+	outputDir = os.Getenv("STIGMER_OUT_DIR")
+	if outputDir == "" {
+		return nil, "", noop
+	}
+	return dirSink(outputDir), outputDir, noop
+}
+
+// dirSink writes files below outputDir, creating directories as needed.
+func dirSink(outputDir string) ManifestSink {
+	return func(path string, data []byte) error {
+		full := filepath.Join(outputDir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+		return os.WriteFile(full, data, 0644)
+	}
+}
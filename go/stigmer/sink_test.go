@@ -0,0 +1,91 @@
+package stigmer
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"testing"
+)
+
+func TestSynthesize_WithSink(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("STIGMER_OUT_DIR", dir)
+	t.Setenv(manifestLayoutEnv, "")
+
+	written := make(map[string][]byte)
+	sink := func(path string, data []byte) error {
+		written[path] = data
+		return nil
+	}
+	if err := Run(defineBundleResources, WithSink(sink), WithManifestLayout(ManifestLayoutPerResource)); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	var paths []string
+	for path := range written {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	want := []string{"agents/bundle-agent.pb", ChecksumFileName, "workflows/test.bundle-workflow.pb"}
+	if len(paths) != len(want) {
+		t.Fatalf("sink received %v, want %v", paths, want)
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Errorf("sink received %v, want %v", paths, want)
+			break
+		}
+	}
+
+	// The sink replaces STIGMER_OUT_DIR
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("STIGMER_OUT_DIR has %d entries, want none", len(entries))
+	}
+}
+
+func TestSynthesize_WithSinkError(t *testing.T) {
+	t.Setenv("STIGMER_OUT_DIR", "")
+	errUpload := errors.New("upload failed")
+
+	err := Run(defineBundleResources, WithSink(func(string, []byte) error { return errUpload }))
+	if !errors.Is(err, errUpload) {
+		t.Errorf("Run() error = %v, want %v", err, errUpload)
+	}
+}
+
+func TestSynthesize_WithWriter(t *testing.T) {
+	t.Setenv("STIGMER_OUT_DIR", "")
+	t.Setenv(manifestLayoutEnv, "")
+	t.Setenv(legacyManifestEnv, "")
+
+	var first, second bytes.Buffer
+	for _, buf := range []*bytes.Buffer{&first, &second} {
+		if err := Run(defineBundleResources, WithWriter(buf)); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+	}
+	if !bytes.Equal(first.Bytes(), second.Bytes()) {
+		t.Error("identical programs produced different archives")
+	}
+
+	files := make(map[string]bool)
+	tr := tar.NewReader(&first)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("reading archive: %v", err)
+		}
+		files[hdr.Name] = true
+	}
+	for _, name := range []string{AgentManifestFileName, WorkflowManifestFileName, LegacyManifestFileName, ChecksumFileName} {
+		if !files[name] {
+			t.Errorf("archive is missing %s", name)
+		}
+	}
+}
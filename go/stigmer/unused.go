@@ -0,0 +1,123 @@
+package stigmer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrUnusedVariables is returned by Synthesize in strict mode when context
+// variables were set but never referenced.
+var ErrUnusedVariables = errors.New("unused context variables")
+
+// strictVariablesEnv enables strict mode when no WithStrictVariables option is given.
+const strictVariablesEnv = "STIGMER_STRICT_VARIABLES"
+
+// WithStrictVariables makes synthesis fail when a context variable is set but
+// never referenced by a workflow, agent or expression. Without it, unused
+// variables are reported as warnings on stderr.
+//
+// Strict mode can also be enabled with STIGMER_STRICT_VARIABLES=true.
+//
+// Example:
+//
+//	stigmer.Run(func(ctx *stigmer.Context) error {
+//	    ctx.SetString("apiVersion", "v1") // Never used: synthesis fails
+//	    return nil
+//	}, stigmer.WithStrictVariables())
+func WithStrictVariables() ContextOption {
+	return func(c *Context) {
+		c.strictVariables = true
+	}
+}
+
+// UnusedVariables returns the names of context variables that have not been
+// referenced by any workflow, agent or expression, sorted by name.
+//
+// A variable counts as referenced once its value or expression is consumed
+// (directly or through a derived reference such as Concat or Field), or when
+// a ${name} placeholder or $context.name expression for it appears in a
+// registered workflow or agent.
+func (c *Context) UnusedVariables() []string {
+	if c.parent != nil {
+		return c.root().UnusedVariables()
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.unusedVariables()
+}
+
+// unusedVariables implements UnusedVariables; callers must hold c.mu.
+func (c *Context) unusedVariables() []string {
+	var unused []string
+	var text string
+	for name, ref := range c.variables {
+		if r, ok := ref.(interface{ wasUsed() bool }); ok && r.wasUsed() {
+			continue
+		}
+		// Serialize resources lazily: most contexts use every variable
+		if text == "" {
+			text = c.resourceText()
+		}
+		if referencesVariable(text, name) {
+			continue
+		}
+		unused = append(unused, name)
+	}
+	sort.Strings(unused)
+	return unused
+}
+
+// resourceText returns the JSON form of every registered workflow and agent,
+// used to find variables referenced through literal strings.
+func (c *Context) resourceText() string {
+	var sb strings.Builder
+	for _, wf := range c.workflows {
+		if data, err := json.Marshal(wf); err == nil {
+			sb.Write(data)
+		}
+	}
+	for _, ag := range c.agents {
+		if data, err := json.Marshal(ag); err == nil {
+			sb.Write(data)
+		}
+	}
+	return sb.String()
+}
+
+// referencesVariable reports whether text contains a ${name} placeholder or a
+// $context.name expression.
+func referencesVariable(text, name string) bool {
+	if strings.Contains(text, "${"+name+"}") {
+		return true
+	}
+	return regexp.MustCompile(`\$context\.` + regexp.QuoteMeta(name) + `\b`).MatchString(text)
+}
+
+// checkUnusedVariables reports unused variables on stderr, or returns
+// ErrUnusedVariables in strict mode.
+func (c *Context) checkUnusedVariables() error {
+	unused := c.unusedVariables()
+	if len(unused) == 0 {
+		return nil
+	}
+
+	strict := c.strictVariables
+	if !strict {
+		strict, _ = strconv.ParseBool(os.Getenv(strictVariablesEnv))
+	}
+	if strict {
+		return fmt.Errorf("%w: %s", ErrUnusedVariables, strings.Join(unused, ", "))
+	}
+	for _, name := range unused {
+		msg := fmt.Sprintf("context variable %q is set but never used", name)
+		c.warn(msg, Diagnostic{Code: "unused_variable", Field: name, Message: msg})
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package stigmer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+// defineVariableUsage sets variables referenced in different ways plus one that is never used.
+func defineVariableUsage(ctx *Context) error {
+	baseURL := ctx.SetString("baseURL", "https://api.example.com")
+	retries := ctx.SetInt("retries", 3)
+	extra := ctx.SetInt("extra", 2)
+	ctx.SetString("region", "eu-west-1")
+	ctx.SetString("apiVersion", "v1")
+	ctx.SetString("tenant", "acme")
+
+	wf, err := workflow.New(ctx,
+		workflow.WithNamespace("test"),
+		workflow.WithName("usage-workflow"),
+		workflow.WithDefaults(workflow.DefaultHeaders(map[string]string{
+			"X-Tenant": "${ $context.tenant }", // Workflow-level expression
+		})),
+	)
+	if err != nil {
+		return err
+	}
+	wf.HttpGet("fetch", baseURL.Concat("/users")) // Resolved at synthesis
+	wf.SetVars("init",
+		"attempts", retries.Add(extra), // Derived runtime expression
+		"region", "${region}", // Placeholder
+	)
+	return nil
+}
+
+func TestContext_UnusedVariables(t *testing.T) {
+	ctx := NewContext()
+	if err := defineVariableUsage(ctx); err != nil {
+		t.Fatalf("defineVariableUsage() error = %v", err)
+	}
+
+	if got, want := ctx.UnusedVariables(), []string{"apiVersion"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("UnusedVariables() = %v, want %v", got, want)
+	}
+}
+
+func TestSynthesize_StrictVariables(t *testing.T) {
+	t.Setenv("STIGMER_OUT_DIR", t.TempDir())
+	t.Setenv(strictVariablesEnv, "")
+
+	if err := Run(defineVariableUsage); err != nil {
+		t.Fatalf("Run() without strict mode error = %v", err)
+	}
+
+	err := Run(defineVariableUsage, WithStrictVariables())
+	if !errors.Is(err, ErrUnusedVariables) {
+		t.Fatalf("Run() error = %v, want ErrUnusedVariables", err)
+	}
+
+	t.Setenv(strictVariablesEnv, "true")
+	if err := Run(defineVariableUsage); !errors.Is(err, ErrUnusedVariables) {
+		t.Errorf("Run() with %s error = %v, want ErrUnusedVariables", strictVariablesEnv, err)
+	}
+}
@@ -0,0 +1,103 @@
+package stigmer
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+)
+
+// VariableOption configures a context variable created with SetString, SetSecret,
+// SetInt, SetFloat, SetBool, SetTime, SetObject or SetList.
+type VariableOption func(*baseRef)
+
+// WithDoc attaches a human-readable description to a context variable.
+// Descriptions are listed by VariableDocs and WriteVariableDocs.
+//
+// Example:
+//
+//	billingURL := ctx.SetString("billingURL", "https://billing.example.com",
+//	    stigmer.WithDoc("Base URL of billing API"))
+func WithDoc(doc string) VariableOption {
+	return func(r *baseRef) {
+		r.doc = doc
+	}
+}
+
+// applyVariableOptions applies opts to a variable's base reference.
+func applyVariableOptions(r *baseRef, opts []VariableOption) {
+	for _, opt := range opts {
+		opt(r)
+	}
+}
+
+// VariableDoc describes one context variable for generated docs and dry-run output.
+type VariableDoc struct {
+	Name   string
+	Type   string // "string", "int", "bool" or "object"
+	Secret bool
+	Value  interface{} // nil for secrets
+	Doc    string
+}
+
+// VariableDocs returns a description of every context variable, sorted by name.
+// Secret values are omitted.
+func (c *Context) VariableDocs() []VariableDoc {
+	if c.parent != nil {
+		return c.root().VariableDocs()
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	docs := make([]VariableDoc, 0, len(c.variables))
+	for name, ref := range c.variables {
+		d := VariableDoc{Name: name, Secret: ref.IsSecret()}
+		if !d.Secret {
+			d.Value = ref.ToValue()
+		}
+		switch r := ref.(type) {
+		case *StringRef:
+			d.Type, d.Doc = "string", r.Doc()
+		case *IntRef:
+			d.Type, d.Doc = "int", r.Doc()
+		case *FloatRef:
+			d.Type, d.Doc = "float", r.Doc()
+		case *BoolRef:
+			d.Type, d.Doc = "bool", r.Doc()
+		case *DurationRef:
+			d.Type, d.Doc = "duration", r.Doc()
+		case *TimeRef:
+			d.Type, d.Doc = "time", r.Doc()
+		case *ObjectRef:
+			d.Type, d.Doc = "object", r.Doc()
+		case *ListRef:
+			d.Type, d.Doc = "list", r.Doc()
+		}
+		docs = append(docs, d)
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].Name < docs[j].Name })
+	return docs
+}
+
+// WriteVariableDocs writes a table of the context variables to w, one per line:
+// name, type, value (or "<secret>") and description.
+//
+// Example:
+//
+//	stigmer.Run(func(ctx *stigmer.Context) error {
+//	    // ...
+//	    return ctx.WriteVariableDocs(os.Stdout)
+//	})
+func (c *Context) WriteVariableDocs(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tTYPE\tVALUE\tDESCRIPTION")
+	for _, d := range c.VariableDocs() {
+		value := fmt.Sprintf("%v", d.Value)
+		if d.Secret {
+			value = "<secret>"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", d.Name, d.Type, value, d.Doc)
+	}
+	return tw.Flush()
+}
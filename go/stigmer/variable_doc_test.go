@@ -0,0 +1,45 @@
+package stigmer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWithDoc(t *testing.T) {
+	ctx := newContext()
+	billingURL := ctx.SetString("billingURL", "https://billing.example.com", WithDoc("Base URL of billing API"))
+	ctx.SetSecret("apiKey", "secret-key", WithDoc("Billing API key"))
+	ctx.SetInt("retries", 3)
+
+	if got := billingURL.Doc(); got != "Base URL of billing API" {
+		t.Errorf("Doc() = %q", got)
+	}
+
+	docs := ctx.VariableDocs()
+	if len(docs) != 3 {
+		t.Fatalf("VariableDocs() returned %d entries, want 3", len(docs))
+	}
+	want := []VariableDoc{
+		{Name: "apiKey", Type: "string", Secret: true, Doc: "Billing API key"},
+		{Name: "billingURL", Type: "string", Value: "https://billing.example.com", Doc: "Base URL of billing API"},
+		{Name: "retries", Type: "int", Value: 3},
+	}
+	for i, w := range want {
+		if docs[i] != w {
+			t.Errorf("VariableDocs()[%d] = %+v, want %+v", i, docs[i], w)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := ctx.WriteVariableDocs(&buf); err != nil {
+		t.Fatalf("WriteVariableDocs() error = %v", err)
+	}
+	out := buf.String()
+	if strings.Contains(out, "secret-key") {
+		t.Error("WriteVariableDocs() leaked a secret value")
+	}
+	if !strings.Contains(out, "Base URL of billing API") || !strings.Contains(out, "<secret>") {
+		t.Errorf("WriteVariableDocs() output missing entries:\n%s", out)
+	}
+}
@@ -0,0 +1,65 @@
+package stigmer
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+// WorkflowYAMLSuffix is appended to the workflow name for YAML exports
+// (e.g., "orders.order-pipeline.sw.yaml").
+const WorkflowYAMLSuffix = ".sw.yaml"
+
+// yamlExportEnv enables YAML export when no WithYAMLExport option is given.
+const yamlExportEnv = "STIGMER_YAML_EXPORT"
+
+// WithYAMLExport writes each workflow as Serverless Workflow DSL YAML next to
+// the synthesized manifests, for human review and other tooling. The proto
+// manifests are still what gets deployed.
+//
+// Files are named <namespace>.<name>.sw.yaml (or <name>.sw.yaml without a
+// namespace). Export can also be enabled with STIGMER_YAML_EXPORT=true.
+//
+// Example:
+//
+//	stigmer.Run(func(ctx *stigmer.Context) error {
+//	    // ... define workflows
+//	    return nil
+//	}, stigmer.WithYAMLExport())
+func WithYAMLExport() ContextOption {
+	return func(c *Context) {
+		c.yamlExport = true
+	}
+}
+
+// writeWorkflowYAML writes the YAML export of every selected workflow when enabled.
+func (c *Context) writeWorkflowYAML(sink ManifestSink) error {
+	enabled := c.yamlExport
+	if !enabled {
+		enabled, _ = strconv.ParseBool(os.Getenv(yamlExportEnv))
+	}
+	if !enabled {
+		return nil
+	}
+
+	_, workflows, err := c.selectResources()
+	if err != nil {
+		return err
+	}
+	for _, wf := range workflows {
+		data, err := workflow.MarshalYAML(wf)
+		if err != nil {
+			return fmt.Errorf("failed to export workflow %s as YAML: %w", wf.Document.Name, err)
+		}
+		name := wf.Document.Name
+		if wf.Document.Namespace != "" {
+			name = wf.Document.Namespace + "." + name
+		}
+		if err := sink(name+WorkflowYAMLSuffix, data); err != nil {
+			return fmt.Errorf("failed to write workflow YAML: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package stigmer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSynthesize_YAMLExport(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("STIGMER_OUT_DIR", dir)
+
+	if err := Run(defineBundleResources, WithYAMLExport()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "test.bundle-workflow"+WorkflowYAMLSuffix))
+	if err != nil {
+		t.Fatalf("expected YAML export: %v", err)
+	}
+	for _, want := range []string{"dsl: 1.0.0", "name: bundle-workflow", "- init:", "status: ready"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("YAML export missing %q:\n%s", want, data)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, WorkflowManifestFileName)); err != nil {
+		t.Errorf("expected %s alongside the YAML: %v", WorkflowManifestFileName, err)
+	}
+}
+
+func TestSynthesize_YAMLExportDisabledByDefault(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("STIGMER_OUT_DIR", dir)
+	t.Setenv(yamlExportEnv, "")
+
+	if err := Run(defineBundleResources); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(dir, "*"+WorkflowYAMLSuffix))
+	if len(matches) != 0 {
+		t.Errorf("unexpected YAML exports: %v", matches)
+	}
+}
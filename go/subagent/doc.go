@@ -23,6 +23,26 @@
 //
 //	sub := subagent.Reference("security-checker", "sec-checker-prod")
 //
+// Agents owned by other teams are pinned by organization, namespace and
+// version instead of hand-written reference strings:
+//
+//	sub := subagent.Reference("security-checker", "sec-checker",
+//	    subagent.InOrg("security"),
+//	    subagent.InNamespace("prod"),
+//	    subagent.AtVersion("1.2.0"),
+//	)
+//
+// The reference is synthesized as "security/prod/sec-checker@1.2.0" and each
+// segment is validated during synthesis.
+//
+// # Delegation Between Agents
+//
+// A referenced sub-agent whose instance reference is the name of another agent
+// defined in the same program (and not pinned to another organization) is a
+// delegation to that agent. Synthesis follows these delegations and fails
+// when they form a cycle or nest deeper than agent.MaxSubAgentDepth, naming
+// the path, e.g. "triage -> reviewer -> triage".
+//
 // # Integration with Agent
 //
 // Sub-agents are added to agents using the WithSubAgent option:
@@ -0,0 +1,117 @@
+package subagent
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ReferenceOption pins a referenced sub-agent to an organization, namespace or version.
+type ReferenceOption func(*SubAgent)
+
+// InOrg references an AgentInstance owned by another organization.
+//
+// Example:
+//
+//	subagent.Reference("security-checker", "sec-checker", subagent.InOrg("security"))
+func InOrg(org string) ReferenceOption {
+	return func(s *SubAgent) {
+		s.refOrg = org
+	}
+}
+
+// InNamespace references an AgentInstance in a specific namespace.
+// A namespace requires the owning organization to be set with InOrg.
+//
+// Example:
+//
+//	subagent.Reference("security-checker", "sec-checker",
+//	    subagent.InOrg("security"),
+//	    subagent.InNamespace("prod"),
+//	)
+func InNamespace(namespace string) ReferenceOption {
+	return func(s *SubAgent) {
+		s.refNamespace = namespace
+	}
+}
+
+// AtVersion pins the referenced AgentInstance to a semantic version.
+//
+// Example:
+//
+//	subagent.Reference("security-checker", "sec-checker", subagent.AtVersion("1.2.0"))
+func AtVersion(version string) ReferenceOption {
+	return func(s *SubAgent) {
+		s.refVersion = version
+	}
+}
+
+// Namespace returns the namespace for referenced sub-agents.
+func (s SubAgent) Namespace() string {
+	return s.refNamespace
+}
+
+// Version returns the pinned version for referenced sub-agents.
+func (s SubAgent) Version() string {
+	return s.refVersion
+}
+
+// QualifiedRef returns the fully qualified agent instance reference in the
+// form "[org/[namespace/]]instance[@version]".
+//
+// Without reference options this is the same as AgentInstanceID().
+func (s SubAgent) QualifiedRef() string {
+	var b strings.Builder
+	if s.refOrg != "" {
+		b.WriteString(s.refOrg + "/")
+		if s.refNamespace != "" {
+			b.WriteString(s.refNamespace + "/")
+		}
+	}
+	b.WriteString(s.agentInstanceRef)
+	if s.refVersion != "" {
+		b.WriteString("@" + s.refVersion)
+	}
+	return b.String()
+}
+
+var (
+	// refSegmentRegex matches org and namespace slugs (lowercase alphanumeric with hyphens).
+	refSegmentRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+	// refInstanceRegex matches AgentInstance IDs and names. Path and version
+	// separators are rejected so qualified references are built with options.
+	refInstanceRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+	// refVersionRegex matches semantic versions.
+	refVersionRegex = regexp.MustCompile(`^\d+\.\d+\.\d+(-[a-zA-Z0-9.-]+)?(\+[a-zA-Z0-9.-]+)?$`)
+)
+
+// validateReferenceFormat checks each segment of a referenced sub-agent.
+func (s SubAgent) validateReferenceFormat() error {
+	if !refInstanceRegex.MatchString(s.agentInstanceRef) {
+		if strings.ContainsAny(s.agentInstanceRef, "/@") {
+			return fmt.Errorf("agent_instance_ref %q must not contain '/' or '@'; use InOrg, InNamespace and AtVersion", s.agentInstanceRef)
+		}
+		return fmt.Errorf("agent_instance_ref %q must be alphanumeric with '-', '_' or '.'", s.agentInstanceRef)
+	}
+
+	if s.refOrg != "" && !refSegmentRegex.MatchString(s.refOrg) {
+		return fmt.Errorf("org %q must be lowercase alphanumeric with hyphens", s.refOrg)
+	}
+
+	if s.refNamespace != "" {
+		if s.refOrg == "" {
+			return fmt.Errorf("namespace %q requires an org (use InOrg)", s.refNamespace)
+		}
+		if !refSegmentRegex.MatchString(s.refNamespace) {
+			return fmt.Errorf("namespace %q must be lowercase alphanumeric with hyphens", s.refNamespace)
+		}
+	}
+
+	if s.refVersion != "" && !refVersionRegex.MatchString(s.refVersion) {
+		return fmt.Errorf("version %q must be semantic version (e.g., 1.2.0)", s.refVersion)
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package subagent
+
+import "testing"
+
+func TestReference_Qualified(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []ReferenceOption
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "unqualified",
+			want: "sec-checker",
+		},
+		{
+			name: "org",
+			opts: []ReferenceOption{InOrg("security")},
+			want: "security/sec-checker",
+		},
+		{
+			name: "org, namespace and version",
+			opts: []ReferenceOption{InOrg("security"), InNamespace("prod"), AtVersion("1.2.0")},
+			want: "security/prod/sec-checker@1.2.0",
+		},
+		{
+			name: "version only",
+			opts: []ReferenceOption{AtVersion("2.0.0-beta.1")},
+			want: "sec-checker@2.0.0-beta.1",
+		},
+		{
+			name:    "namespace without org",
+			opts:    []ReferenceOption{InNamespace("prod")},
+			wantErr: true,
+		},
+		{
+			name:    "invalid org",
+			opts:    []ReferenceOption{InOrg("Security Team")},
+			wantErr: true,
+		},
+		{
+			name:    "invalid version",
+			opts:    []ReferenceOption{AtVersion("latest")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := Reference("security", "sec-checker", tt.opts...)
+
+			err := sub.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := sub.QualifiedRef(); got != tt.want {
+				t.Errorf("QualifiedRef() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReference_RawQualifiedString(t *testing.T) {
+	sub := Reference("security", "security/sec-checker@1.2.0")
+	if err := sub.Validate(); err == nil {
+		t.Error("Validate() expected error for hand-written qualified reference")
+	}
+}
+
+func TestReference_Organization(t *testing.T) {
+	sub := Reference("security", "sec-checker", InOrg("security"), InNamespace("prod"))
+	if sub.Organization() != "security" {
+		t.Errorf("Organization() = %q, want %q", sub.Organization(), "security")
+	}
+	if sub.Namespace() != "prod" {
+		t.Errorf("Namespace() = %q, want %q", sub.Namespace(), "prod")
+	}
+	if got, want := sub.String(), "SubAgent(security -> security/prod/sec-checker)"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
@@ -23,6 +23,9 @@ type SubAgent struct {
 
 	// For referenced sub-agents
 	agentInstanceRef    string
+	refOrg              string
+	refNamespace        string
+	refVersion          string
 }
 
 type subAgentType int
@@ -158,12 +161,24 @@ func Inline(opts ...InlineOption) (SubAgent, error) {
 //
 // The name is the local name for this sub-agent reference.
 // The agentInstanceRef is the ID or name of the AgentInstance resource.
-func Reference(name, agentInstanceRef string) SubAgent {
-	return SubAgent{
+//
+// Agents owned by other teams can be pinned with reference options:
+//
+//	sub := subagent.Reference("security-checker", "sec-checker",
+//	    subagent.InOrg("security"),
+//	    subagent.InNamespace("prod"),
+//	    subagent.AtVersion("1.2.0"),
+//	)
+func Reference(name, agentInstanceRef string, opts ...ReferenceOption) SubAgent {
+	s := SubAgent{
 		subAgentType:     subAgentTypeReference,
 		name:             name,
 		agentInstanceRef: agentInstanceRef,
 	}
+	for _, opt := range opts {
+		opt(&s)
+	}
+	return s
 }
 
 // IsInline returns true if this is an inline sub-agent definition.
@@ -210,9 +225,7 @@ func (s SubAgent) Skills() []skill.Skill {
 // For inline sub-agents, returns empty string.
 func (s SubAgent) Organization() string {
 	if s.IsReference() {
-		// For references, we need to parse from agentInstanceRef
-		// For now, return empty - this will be handled by CLI
-		return ""
+		return s.refOrg
 	}
 	return ""
 }
@@ -261,6 +274,10 @@ func (s SubAgent) validateReference() error {
 	if s.agentInstanceRef == "" {
 		return fmt.Errorf("referenced sub-agent %q: agent_instance_ref is required", s.name)
 	}
+
+	if err := s.validateReferenceFormat(); err != nil {
+		return fmt.Errorf("referenced sub-agent %q: %w", s.name, err)
+	}
 	
 	return nil
 }
@@ -268,7 +285,7 @@ func (s SubAgent) validateReference() error {
 // String returns a string representation of the sub-agent.
 func (s SubAgent) String() string {
 	if s.IsReference() {
-		return fmt.Sprintf("SubAgent(%s -> %s)", s.name, s.agentInstanceRef)
+		return fmt.Sprintf("SubAgent(%s -> %s)", s.name, s.QualifiedRef())
 	}
 	return fmt.Sprintf("SubAgent(%s inline)", s.name)
 }
@@ -0,0 +1,280 @@
+package codegen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	agentv1 "buf.build/gen/go/leftbin/stigmer/protocolbuffers/go/ai/stigmer/agentic/agent/v1"
+)
+
+// AgentManifest generates a Go program that declares the agents of an agent
+// manifest with agent.New.
+//
+// Skills, MCP servers, sub-agents and environment variables are built first
+// and passed to the agent with the matching With* options. Skill IDs and SDK
+// metadata are assigned again at synthesis and are not generated.
+func AgentManifest(manifest *agentv1.AgentManifest) ([]byte, error) {
+	if manifest == nil || len(manifest.GetAgents()) == 0 {
+		return nil, fmt.Errorf("manifest has no agents")
+	}
+
+	g := newGenerator()
+	g.use("agent")
+	for i, blueprint := range manifest.GetAgents() {
+		if err := g.agent(blueprint); err != nil {
+			return nil, fmt.Errorf("agent[%d] %s: %w", i, blueprint.GetName(), err)
+		}
+	}
+	return g.program("agent-manifest.pb")
+}
+
+// agent appends the statements that build one agent blueprint.
+func (g *generator) agent(b *agentv1.AgentBlueprint) error {
+	if b.GetName() == "" {
+		return fmt.Errorf("agent has no name")
+	}
+
+	opts := []string{
+		"agent.WithName(" + strconv.Quote(b.GetName()) + ")",
+	}
+	if b.GetInstructions() != "" {
+		opts = append(opts, "agent.WithInstructions("+quote(b.GetInstructions())+")")
+	}
+	if b.GetDescription() != "" {
+		opts = append(opts, "agent.WithDescription("+quote(b.GetDescription())+")")
+	}
+	if b.GetIconUrl() != "" {
+		opts = append(opts, "agent.WithIconURL("+strconv.Quote(b.GetIconUrl())+")")
+	}
+
+	for i, s := range b.GetSkills() {
+		expr, err := g.skill(s)
+		if err != nil {
+			return fmt.Errorf("skill[%d]: %w", i, err)
+		}
+		opts = append(opts, "agent.WithSkill("+expr+")")
+	}
+	for i, server := range b.GetMcpServers() {
+		name, err := g.mcpServer(server)
+		if err != nil {
+			return fmt.Errorf("mcp_server[%d]: %w", i, err)
+		}
+		opts = append(opts, "agent.WithMCPServer("+name+")")
+	}
+	for i, sub := range b.GetSubAgents() {
+		expr, err := g.subAgent(sub)
+		if err != nil {
+			return fmt.Errorf("sub_agent[%d]: %w", i, err)
+		}
+		opts = append(opts, "agent.WithSubAgent("+expr+")")
+	}
+	for _, env := range b.GetEnvironmentVariables() {
+		opts = append(opts, "agent.WithEnvironmentVariable("+g.environmentVariable(env)+")")
+	}
+
+	g.printf("if _, err := %s; err != nil {", call("agent.New", []string{"ctx"}, opts))
+	g.printf("return fmt.Errorf(%s, err)", strconv.Quote("failed to create agent "+b.GetName()+": %w"))
+	g.printf("}")
+	g.printf("")
+	return nil
+}
+
+// skill returns an expression for a skill, declaring a variable for inline
+// skills since skill.New can fail.
+func (g *generator) skill(s *agentv1.ManifestSkill) (string, error) {
+	g.use("skill")
+	switch {
+	case s.GetPlatform() != nil:
+		return "skill.Platform(" + strconv.Quote(s.GetPlatform().GetName()) + ")", nil
+	case s.GetOrg() != nil:
+		return "skill.Organization(" + quoteAll([]string{s.GetOrg().GetOrg(), s.GetOrg().GetName()}) + ")", nil
+	case s.GetInline() != nil:
+		inline := s.GetInline()
+		opts := []string{"skill.WithName(" + strconv.Quote(inline.GetName()) + ")"}
+		if inline.GetDescription() != "" {
+			opts = append(opts, "skill.WithDescription("+quote(inline.GetDescription())+")")
+		}
+		opts = append(opts, "skill.WithMarkdown("+quote(inline.GetMarkdownContent())+")")
+
+		name := g.ident(inline.GetName(), "Skill")
+		g.printf("%s, err := %s", name, call("skill.New", nil, opts))
+		g.checkErr("skill " + inline.GetName())
+		return "*" + name, nil
+	default:
+		return "", fmt.Errorf("skill has unknown source type")
+	}
+}
+
+// mcpServer declares a variable for an MCP server and returns its name.
+func (g *generator) mcpServer(s *agentv1.ManifestMcpServer) (string, error) {
+	g.use("mcpserver")
+	opts := []string{"mcpserver.WithName(" + strconv.Quote(s.GetName()) + ")"}
+
+	var ctor string
+	switch {
+	case s.GetStdio() != nil:
+		stdio := s.GetStdio()
+		ctor = "mcpserver.Stdio"
+		opts = append(opts, "mcpserver.WithCommand("+strconv.Quote(stdio.GetCommand())+")")
+		if len(stdio.GetArgs()) > 0 {
+			opts = append(opts, "mcpserver.WithArgs("+quoteAll(stdio.GetArgs())+")")
+		}
+		opts = append(opts, envPlaceholderOptions(stdio.GetEnvPlaceholders())...)
+		if stdio.GetWorkingDir() != "" {
+			opts = append(opts, "mcpserver.WithWorkingDir("+strconv.Quote(stdio.GetWorkingDir())+")")
+		}
+
+	case s.GetHttp() != nil:
+		http := s.GetHttp()
+		ctor = "mcpserver.HTTP"
+		opts = append(opts, "mcpserver.WithURL("+strconv.Quote(http.GetUrl())+")")
+		for _, k := range sortedKeys(http.GetHeaders()) {
+			opts = append(opts, "mcpserver.WithHeader("+quoteAll([]string{k, http.GetHeaders()[k]})+")")
+		}
+		for _, k := range sortedKeys(http.GetQueryParams()) {
+			opts = append(opts, "mcpserver.WithQueryParam("+quoteAll([]string{k, http.GetQueryParams()[k]})+")")
+		}
+		if http.GetTimeoutSeconds() != 0 {
+			opts = append(opts, fmt.Sprintf("mcpserver.WithTimeout(%d)", http.GetTimeoutSeconds()))
+		}
+
+	case s.GetDocker() != nil:
+		docker := s.GetDocker()
+		ctor = "mcpserver.Docker"
+		opts = append(opts, "mcpserver.WithImage("+strconv.Quote(docker.GetImage())+")")
+		if len(docker.GetArgs()) > 0 {
+			opts = append(opts, "mcpserver.WithArgs("+quoteAll(docker.GetArgs())+")")
+		}
+		opts = append(opts, envPlaceholderOptions(docker.GetEnvPlaceholders())...)
+		for _, v := range docker.GetVolumes() {
+			opts = append(opts, fmt.Sprintf("mcpserver.WithVolumeMount(%s, %s, %t)",
+				strconv.Quote(v.GetHostPath()), strconv.Quote(v.GetContainerPath()), v.GetReadOnly()))
+		}
+		for _, p := range docker.GetPorts() {
+			opts = append(opts, fmt.Sprintf("mcpserver.WithPortMapping(%d, %d, %s)",
+				p.GetHostPort(), p.GetContainerPort(), strconv.Quote(p.GetProtocol())))
+		}
+		if docker.GetNetwork() != "" {
+			opts = append(opts, "mcpserver.WithNetwork("+strconv.Quote(docker.GetNetwork())+")")
+		}
+		if docker.GetContainerName() != "" {
+			opts = append(opts, "mcpserver.WithContainerName("+strconv.Quote(docker.GetContainerName())+")")
+		}
+
+	default:
+		return "", fmt.Errorf("MCP server %s has unknown server type", s.GetName())
+	}
+
+	if len(s.GetEnabledTools()) > 0 {
+		opts = append(opts, "mcpserver.WithEnabledTools("+quoteAll(s.GetEnabledTools())+")")
+	}
+
+	name := g.ident(s.GetName(), "")
+	g.printf("%s, err := %s", name, call(ctor, nil, opts))
+	g.checkErr(s.GetName() + " MCP server")
+	return name, nil
+}
+
+// envPlaceholderOptions renders WithEnvPlaceholder options in key order.
+func envPlaceholderOptions(placeholders map[string]string) []string {
+	var opts []string
+	for _, k := range sortedKeys(placeholders) {
+		opts = append(opts, "mcpserver.WithEnvPlaceholder("+quoteAll([]string{k, placeholders[k]})+")")
+	}
+	return opts
+}
+
+// subAgent returns an expression for a sub-agent, declaring a variable for
+// inline sub-agents since subagent.Inline can fail.
+func (g *generator) subAgent(s *agentv1.ManifestSubAgent) (string, error) {
+	g.use("subagent")
+	switch {
+	case s.GetReference() != nil:
+		return subAgentReference(s.GetReference().GetAgentInstanceId()), nil
+
+	case s.GetInline() != nil:
+		inline := s.GetInline()
+		opts := []string{"subagent.WithName(" + strconv.Quote(inline.GetName()) + ")"}
+		if inline.GetDescription() != "" {
+			opts = append(opts, "subagent.WithDescription("+quote(inline.GetDescription())+")")
+		}
+		if inline.GetInstructions() != "" {
+			opts = append(opts, "subagent.WithInstructions("+quote(inline.GetInstructions())+")")
+		}
+		if len(inline.GetMcpServerNames()) > 0 {
+			opts = append(opts, "subagent.WithMCPServers("+quoteAll(inline.GetMcpServerNames())+")")
+		}
+		for _, sel := range inline.GetToolSelections() {
+			args := append([]string{sel.GetMcpServerName()}, sel.GetTools()...)
+			opts = append(opts, "subagent.WithToolSelection("+quoteAll(args)+")")
+		}
+		for i, s := range inline.GetSkills() {
+			expr, err := g.skill(s)
+			if err != nil {
+				return "", fmt.Errorf("skill[%d]: %w", i, err)
+			}
+			opts = append(opts, "subagent.WithSkill("+expr+")")
+		}
+
+		name := g.ident(inline.GetName(), "")
+		g.printf("%s, err := %s", name, call("subagent.Inline", nil, opts))
+		g.checkErr("sub-agent " + inline.GetName())
+		return name, nil
+
+	default:
+		return "", fmt.Errorf("sub-agent has unknown source type")
+	}
+}
+
+// subAgentReference splits a qualified reference of the form
+// "[org/[namespace/]]instance[@version]" back into subagent.Reference options.
+// The instance name doubles as the local sub-agent name.
+func subAgentReference(ref string) string {
+	instance, version, _ := strings.Cut(ref, "@")
+	var org, namespace string
+	parts := strings.Split(instance, "/")
+	switch len(parts) {
+	case 2:
+		org, instance = parts[0], parts[1]
+	case 3:
+		org, namespace, instance = parts[0], parts[1], parts[2]
+	}
+
+	args := []string{strconv.Quote(instance), strconv.Quote(instance)}
+	if org != "" {
+		args = append(args, "subagent.InOrg("+strconv.Quote(org)+")")
+	}
+	if namespace != "" {
+		args = append(args, "subagent.InNamespace("+strconv.Quote(namespace)+")")
+	}
+	if version != "" {
+		args = append(args, "subagent.AtVersion("+strconv.Quote(version)+")")
+	}
+	return "subagent.Reference(" + strings.Join(args, ", ") + ")"
+}
+
+// environmentVariable declares a variable for an environment variable and
+// returns its name.
+func (g *generator) environmentVariable(env *agentv1.ManifestEnvironmentVariable) string {
+	g.use("environment")
+	opts := []string{"environment.WithName(" + strconv.Quote(env.GetName()) + ")"}
+	if env.GetIsSecret() {
+		opts = append(opts, "environment.WithSecret(true)")
+	}
+	if env.GetDescription() != "" {
+		opts = append(opts, "environment.WithDescription("+quote(env.GetDescription())+")")
+	}
+	if env.GetDefaultValue() != "" {
+		opts = append(opts, "environment.WithDefaultValue("+strconv.Quote(env.GetDefaultValue())+")")
+	}
+	// Variables are required unless stated otherwise
+	if !env.GetRequired() {
+		opts = append(opts, "environment.WithRequired(false)")
+	}
+
+	name := g.ident(strings.ToLower(env.GetName()), "")
+	g.printf("%s, err := %s", name, call("environment.New", nil, opts))
+	g.checkErr("environment variable " + env.GetName())
+	return name
+}
@@ -0,0 +1,236 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// sdkImportPrefix is the import path prefix of the SDK packages.
+const sdkImportPrefix = "github.com/leftbin/stigmer-sdk/go/"
+
+// generator accumulates the body of the generated stigmer.Run function.
+type generator struct {
+	body    bytes.Buffer
+	imports map[string]bool
+	idents  map[string]bool
+}
+
+func newGenerator() *generator {
+	g := &generator{
+		imports: map[string]bool{"fmt": true, "log": true, sdkImportPrefix + "stigmer": true},
+		// Names already taken in the generated function
+		idents: map[string]bool{"ctx": true, "err": true},
+	}
+	return g
+}
+
+// use records an SDK package import.
+func (g *generator) use(pkg string) {
+	g.imports[sdkImportPrefix+pkg] = true
+}
+
+// ident returns a unique local variable name derived from name.
+func (g *generator) ident(name, suffix string) string {
+	id := lowerCamel(name) + suffix
+	if id == "" || !unicode.IsLetter([]rune(id)[0]) {
+		id = "v" + id
+	}
+	base := id
+	for i := 2; g.idents[id] || token.IsKeyword(id) || isImportName(id); i++ {
+		id = fmt.Sprintf("%s%d", base, i)
+	}
+	g.idents[id] = true
+	return id
+}
+
+// printf appends a line to the function body.
+func (g *generator) printf(format string, args ...any) {
+	fmt.Fprintf(&g.body, format, args...)
+	g.body.WriteByte('\n')
+}
+
+// checkErr appends the error check after a builder call.
+func (g *generator) checkErr(what string) {
+	g.printf("if err != nil {")
+	g.printf("return fmt.Errorf(%s, err)", strconv.Quote("failed to create "+what+": %w"))
+	g.printf("}")
+}
+
+// program wraps the function body in a main package and formats it.
+func (g *generator) program(source string) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by codegen from %s. Review and edit freely.\n\n", source)
+	buf.WriteString("package main\n\n")
+
+	var std, sdk []string
+	for path := range g.imports {
+		if strings.HasPrefix(path, sdkImportPrefix) {
+			sdk = append(sdk, path)
+		} else {
+			std = append(std, path)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(sdk)
+	buf.WriteString("import (\n")
+	for _, path := range std {
+		fmt.Fprintf(&buf, "%q\n", path)
+	}
+	buf.WriteString("\n")
+	for _, path := range sdk {
+		fmt.Fprintf(&buf, "%q\n", path)
+	}
+	buf.WriteString(")\n\n")
+
+	buf.WriteString("func main() {\n")
+	buf.WriteString("err := stigmer.Run(func(ctx *stigmer.Context) error {\n")
+	buf.Write(g.body.Bytes())
+	buf.WriteString("return nil\n")
+	buf.WriteString("})\n")
+	buf.WriteString("if err != nil {\nlog.Fatal(err)\n}\n")
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return src, nil
+}
+
+// isImportName reports whether id would shadow a package used by generated code.
+func isImportName(id string) bool {
+	switch id {
+	case "fmt", "log", "stigmer", "agent", "workflow", "skill", "mcpserver", "subagent", "environment":
+		return true
+	}
+	return false
+}
+
+// lowerCamel converts a name like "code-reviewer" to "codeReviewer".
+func lowerCamel(s string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = b.Len() > 0
+			continue
+		}
+		switch {
+		case b.Len() == 0:
+			b.WriteRune(unicode.ToLower(r))
+		case upper:
+			b.WriteRune(unicode.ToUpper(r))
+		default:
+			b.WriteRune(r)
+		}
+		upper = false
+	}
+	return b.String()
+}
+
+// quote renders a string literal, using a raw string for multi-line text.
+func quote(s string) string {
+	if strings.Contains(s, "\n") && !strings.ContainsAny(s, "`\r") && strconv.CanBackquote(strings.ReplaceAll(s, "\n", "")) {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
+
+// quoteAll renders a list of string literals as call arguments.
+func quoteAll(ss []string) string {
+	quoted := make([]string, len(ss))
+	for i, s := range ss {
+		quoted[i] = strconv.Quote(s)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// sortedKeys returns the keys of m in lexical order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// stringMapLiteral renders a map[string]string literal.
+func stringMapLiteral(m map[string]string) string {
+	var b strings.Builder
+	b.WriteString("map[string]string{\n")
+	for _, k := range sortedKeys(m) {
+		fmt.Fprintf(&b, "%s: %s,\n", strconv.Quote(k), quote(m[k]))
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// mapLiteral renders a map[string]any literal.
+func mapLiteral(m map[string]any) string {
+	var b strings.Builder
+	b.WriteString("map[string]any{\n")
+	for _, k := range sortedKeys(m) {
+		fmt.Fprintf(&b, "%s: %s,\n", strconv.Quote(k), literal(m[k]))
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// literal renders a JSON-like value (as decoded from a protobuf Struct).
+func literal(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "nil"
+	case string:
+		return quote(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return strconv.FormatInt(int64(v), 10)
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case map[string]any:
+		return mapLiteral(v)
+	case []any:
+		var b strings.Builder
+		b.WriteString("[]any{")
+		for i, item := range v {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(literal(item))
+		}
+		b.WriteString("}")
+		return b.String()
+	default:
+		return fmt.Sprintf("%#v", v)
+	}
+}
+
+// call renders a function call: leading arguments stay on the first line and
+// options go one per line, as in the SDK examples.
+func call(fn string, head []string, opts []string) string {
+	first := fn + "(" + strings.Join(head, ", ")
+	if len(opts) == 0 {
+		return first + ")"
+	}
+	if len(head) > 0 {
+		first += ","
+	}
+	return first + "\n" + strings.Join(opts, ",\n") + ",\n)"
+}
@@ -0,0 +1,221 @@
+package codegen
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/agent"
+	"github.com/leftbin/stigmer-sdk/go/environment"
+	"github.com/leftbin/stigmer-sdk/go/internal/synth"
+	"github.com/leftbin/stigmer-sdk/go/mcpserver"
+	"github.com/leftbin/stigmer-sdk/go/skill"
+	"github.com/leftbin/stigmer-sdk/go/subagent"
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+// assertProgram checks that src parses as Go and contains every wanted snippet.
+func assertProgram(t *testing.T, src []byte, want ...string) {
+	t.Helper()
+	if _, err := parser.ParseFile(token.NewFileSet(), "main.go", src, parser.AllErrors); err != nil {
+		t.Fatalf("generated code does not parse: %v\n%s", err, src)
+	}
+	out := string(src)
+	for _, w := range want {
+		if !strings.Contains(out, w) {
+			t.Errorf("generated code missing %q\n%s", w, out)
+		}
+	}
+}
+
+// TestWorkflowManifest verifies workflows are generated with the SDK builders.
+func TestWorkflowManifest(t *testing.T) {
+	wf, err := workflow.Build(
+		workflow.WithNamespace("orders"),
+		workflow.WithName("fulfil-order"),
+		workflow.WithVersion("1.2.0"),
+		workflow.WithSchedule(workflow.Cron("0 2 * * *")),
+		workflow.WithRollout(workflow.Percent(10)),
+	)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	fetch := wf.HttpGet("fetch", "https://api.example.com/orders",
+		workflow.Header("Accept", "application/json"),
+		workflow.Timeout(30),
+	).ExportAll()
+	wf.AddTask(workflow.SwitchTask("route",
+		workflow.WithCase("${.status == \"paid\"}", "ship"),
+		workflow.WithDefault("cancel"),
+	))
+	wf.AddTask(workflow.ForTask("ship",
+		workflow.WithIn("${.items}"),
+		workflow.WithDo(workflow.SetTask("mark", workflow.SetVar("shipped", "true"))),
+	).End())
+	wf.AddTask(workflow.RaiseTask("cancel", workflow.WithError("OrderCancelled")))
+	fetch.WithDeadline("1m")
+
+	manifest, err := synth.ToWorkflowManifest(wf)
+	if err != nil {
+		t.Fatalf("ToWorkflowManifest() error = %v", err)
+	}
+	src, err := WorkflowManifest(manifest)
+	if err != nil {
+		t.Fatalf("WorkflowManifest() error = %v", err)
+	}
+
+	assertProgram(t, src,
+		"// Code generated by codegen from workflow-manifest.pb.",
+		"fulfilOrder, err := workflow.New(ctx,",
+		`workflow.WithNamespace("orders"),`,
+		`workflow.WithSchedule(workflow.Cron("0 2 * * *")),`,
+		"workflow.WithRollout(workflow.Percent(10)),",
+		`fulfilOrder.HttpGet("fetch", "https://api.example.com/orders",`,
+		`workflow.Header("Accept", "application/json"),`,
+		`workflow.Timeout(30),`,
+		`).ExportAll().WithDeadline("1m")`,
+		`fulfilOrder.AddTask(workflow.SwitchTask("route",`,
+		`workflow.WithDefault("cancel"),`,
+		`workflow.SetTask("mark",`,
+		`workflow.SetVar("shipped", "true"),`,
+		`).End())`,
+		`workflow.WithError("OrderCancelled"),`,
+	)
+}
+
+// TestWorkflowManifest_TODO verifies settings without builders are flagged.
+func TestWorkflowManifest_TODO(t *testing.T) {
+	wf, err := workflow.Build(workflow.WithNamespace("events"), workflow.WithName("await"))
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	expire := workflow.SetTask("expire", workflow.SetVar("expired", "true"))
+	wf.AddTask(workflow.ListenTask("wait",
+		workflow.WithEvent("order.paid"),
+		workflow.WithListenTimeout("1h", expire),
+	))
+	wf.AddTask(expire)
+
+	manifest, err := synth.ToWorkflowManifest(wf)
+	if err != nil {
+		t.Fatalf("ToWorkflowManifest() error = %v", err)
+	}
+	src, err := WorkflowManifest(manifest)
+	if err != nil {
+		t.Fatalf("WorkflowManifest() error = %v", err)
+	}
+	assertProgram(t, src, "// TODO: port wait timeout 1h (then expire) by hand")
+}
+
+// TestAgentManifest verifies agents are generated with their dependencies.
+func TestAgentManifest(t *testing.T) {
+	github, err := mcpserver.Stdio(
+		mcpserver.WithName("github"),
+		mcpserver.WithCommand("npx"),
+		mcpserver.WithArgs("-y", "@modelcontextprotocol/server-github"),
+		mcpserver.WithEnvPlaceholder("GITHUB_TOKEN", "${GITHUB_TOKEN}"),
+		mcpserver.WithEnabledTools("create_issue"),
+	)
+	if err != nil {
+		t.Fatalf("Stdio() error = %v", err)
+	}
+	token, err := environment.New(
+		environment.WithName("GITHUB_TOKEN"),
+		environment.WithSecret(true),
+	)
+	if err != nil {
+		t.Fatalf("environment.New() error = %v", err)
+	}
+	guide, err := skill.New(
+		skill.WithName("style-guide"),
+		skill.WithMarkdown("# Style\n\nPrefer small functions."),
+	)
+	if err != nil {
+		t.Fatalf("skill.New() error = %v", err)
+	}
+	a, err := agent.Build(
+		agent.WithName("code-reviewer"),
+		agent.WithInstructions("Review pull requests.\nBe concise."),
+		agent.WithSkill(skill.Platform("coding-best-practices")),
+		agent.WithSkill(*guide),
+		agent.WithMCPServer(github),
+		agent.WithSubAgent(subagent.Reference("security", "sec-checker",
+			subagent.InOrg("security"),
+			subagent.AtVersion("1.2.0"),
+		)),
+		agent.WithEnvironmentVariable(token),
+	)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	manifest, err := synth.ToManifest(a)
+	if err != nil {
+		t.Fatalf("ToManifest() error = %v", err)
+	}
+	src, err := AgentManifest(manifest)
+	if err != nil {
+		t.Fatalf("AgentManifest() error = %v", err)
+	}
+
+	assertProgram(t, src,
+		"// Code generated by codegen from agent-manifest.pb.",
+		"styleGuideSkill, err := skill.New(",
+		"skill.WithMarkdown(`# Style\n\nPrefer small functions.`),",
+		"github, err := mcpserver.Stdio(",
+		`mcpserver.WithArgs("-y", "@modelcontextprotocol/server-github"),`,
+		`mcpserver.WithEnvPlaceholder("GITHUB_TOKEN", "${GITHUB_TOKEN}"),`,
+		`mcpserver.WithEnabledTools("create_issue"),`,
+		"githubToken, err := environment.New(",
+		"environment.WithSecret(true),",
+		"if _, err := agent.New(ctx,",
+		`agent.WithName("code-reviewer"),`,
+		"agent.WithInstructions(`Review pull requests.\nBe concise.`),",
+		`agent.WithSkill(skill.Platform("coding-best-practices")),`,
+		"agent.WithSkill(*styleGuideSkill),",
+		"agent.WithMCPServer(github),",
+		`agent.WithSubAgent(subagent.Reference("sec-checker", "sec-checker", subagent.InOrg("security"), subagent.AtVersion("1.2.0"))),`,
+		"agent.WithEnvironmentVariable(githubToken),",
+	)
+}
+
+// TestManifest_Empty verifies empty manifests are rejected.
+func TestManifest_Empty(t *testing.T) {
+	if _, err := WorkflowManifest(nil); err == nil {
+		t.Error("WorkflowManifest(nil) expected error")
+	}
+	if _, err := AgentManifest(nil); err == nil {
+		t.Error("AgentManifest(nil) expected error")
+	}
+}
+
+// TestLowerCamel verifies resource names are converted to Go variable names.
+func TestLowerCamel(t *testing.T) {
+	tests := map[string]string{
+		"code-reviewer": "codeReviewer",
+		"github_token":  "githubToken",
+		"nightly sync":  "nightlySync",
+	}
+	for in, want := range tests {
+		if got := lowerCamel(in); got != want {
+			t.Errorf("lowerCamel(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// TestIdent verifies generated variable names are unique and valid.
+func TestIdent(t *testing.T) {
+	g := newGenerator()
+	for _, tc := range []struct{ name, want string }{
+		{"github", "github"},
+		{"github", "github2"},
+		{"2fa", "v2fa"},
+		{"workflow", "workflow2"},
+		{"type", "type2"},
+	} {
+		if got := g.ident(tc.name, ""); got != tc.want {
+			t.Errorf("ident(%q) = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
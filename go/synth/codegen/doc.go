@@ -0,0 +1,29 @@
+// Package codegen turns synthesized manifests back into Go programs that use
+// the SDK builders.
+//
+// Teams migrating agents or workflows authored as YAML or in the console can
+// bootstrap their repository from the manifest the platform already has:
+//
+//	data, _ := os.ReadFile("workflow-manifest.pb")
+//	manifest := &workflowv1.WorkflowManifest{}
+//	if err := proto.Unmarshal(data, manifest); err != nil {
+//	    return err
+//	}
+//	src, err := codegen.WorkflowManifest(manifest)
+//	if err != nil {
+//	    return err
+//	}
+//	os.WriteFile("main.go", src, 0o644)
+//
+// The generated file is a complete main package that calls stigmer.Run and
+// builds each resource with agent.New, workflow.New, wf.HttpGet and friends.
+// Running it synthesizes an equivalent manifest.
+//
+// # Limitations
+//
+// Generation starts from the synthesized form, so anything synthesis does not
+// record is lost (see workflow.FromProto): context variables appear as their
+// resolved values and task dependencies are implicit in task order. Settings
+// without a direct builder (OAuth2, TLS, proxies, listen timeouts) are listed
+// in a TODO comment above the task so they can be ported by hand.
+package codegen
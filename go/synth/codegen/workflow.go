@@ -0,0 +1,467 @@
+package codegen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	workflowv1 "buf.build/gen/go/leftbin/stigmer/protocolbuffers/go/ai/stigmer/agentic/workflow/v1"
+
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+// WorkflowManifest generates a Go program that declares the workflows of a
+// workflow manifest with workflow.New.
+//
+// Tasks are added in synthesized order with the workflow helpers (wf.HttpGet,
+// wf.SetVars, wf.CallAgent) where one exists, and with wf.AddTask and the
+// task constructors otherwise.
+func WorkflowManifest(manifest *workflowv1.WorkflowManifest) ([]byte, error) {
+	if manifest == nil || len(manifest.GetWorkflows()) == 0 {
+		return nil, fmt.Errorf("manifest has no workflows")
+	}
+
+	g := newGenerator()
+	g.use("workflow")
+	for i, pw := range manifest.GetWorkflows() {
+		wf, err := workflow.FromProto(pw)
+		if err != nil {
+			return nil, fmt.Errorf("workflow[%d]: %w", i, err)
+		}
+		if err := g.workflow(wf); err != nil {
+			return nil, fmt.Errorf("workflow[%d] %s: %w", i, wf.Document.Name, err)
+		}
+	}
+	return g.program("workflow-manifest.pb")
+}
+
+// workflow appends the statements that build one workflow.
+func (g *generator) workflow(wf *workflow.Workflow) error {
+	doc := wf.Document
+	opts := []string{
+		"workflow.WithNamespace(" + strconv.Quote(doc.Namespace) + ")",
+		"workflow.WithName(" + strconv.Quote(doc.Name) + ")",
+	}
+	if doc.Version != "" {
+		opts = append(opts, "workflow.WithVersion("+strconv.Quote(doc.Version)+")")
+	}
+	if doc.DSL != "" && doc.DSL != workflow.DefaultDSLVersion {
+		opts = append(opts, "workflow.WithDSLVersion("+strconv.Quote(doc.DSL)+")")
+	}
+	if wf.Description != "" {
+		opts = append(opts, "workflow.WithDescription("+quote(wf.Description)+")")
+	}
+	if wf.Org != "" {
+		opts = append(opts, "workflow.WithOrg("+strconv.Quote(wf.Org)+")")
+	}
+	for _, t := range wf.Triggers {
+		switch t.Kind {
+		case workflow.TriggerKindCron:
+			opts = append(opts, "workflow.WithSchedule(workflow.Cron("+strconv.Quote(t.Cron)+"))")
+		case workflow.TriggerKindInterval:
+			opts = append(opts, "workflow.WithSchedule(workflow.Interval("+strconv.Quote(t.Interval)+"))")
+		case workflow.TriggerKindEvent:
+			opts = append(opts, "workflow.WithEventTrigger("+strconv.Quote(t.Event)+")")
+		default:
+			return fmt.Errorf("unknown trigger kind %q", t.Kind)
+		}
+	}
+	if wf.Timeout != "" {
+		opts = append(opts, "workflow.WithWorkflowTimeout("+strconv.Quote(wf.Timeout)+")")
+	}
+	if p := wf.ErrorPolicy; p != nil {
+		policy := []string{fmt.Sprintf("workflow.MaxTotalRetries(%d)", p.MaxTotalRetries)}
+		if p.ExhaustedError != "" {
+			policy = append(policy, "workflow.OnExhausted("+quoteAll([]string{p.ExhaustedError, p.ExhaustedMessage})+")")
+		}
+		opts = append(opts, "workflow.WithErrorPolicy("+strings.Join(policy, ", ")+")")
+	}
+	if wf.Rollout != nil {
+		opts = append(opts, fmt.Sprintf("workflow.WithRollout(workflow.Percent(%d))", wf.Rollout.Percent))
+	}
+	if r := wf.Resources; !r.IsZero() {
+		opts = append(opts, "workflow.WithResources("+quoteAll([]string{r.CPU, r.Memory})+")")
+	}
+	if wf.Disabled {
+		opts = append(opts, "workflow.WithDisabled("+strconv.Quote(wf.DisabledReason)+")")
+	}
+
+	name := g.ident(doc.Name, "")
+	g.printf("%s, err := %s", name, call("workflow.New", []string{"ctx"}, opts))
+	g.checkErr("workflow " + doc.Name)
+	for _, task := range wf.Tasks {
+		if todo := taskTODO(task); todo != "" {
+			g.printf("// TODO: %s", todo)
+		}
+		expr, err := taskExpr(task, name)
+		if err != nil {
+			return fmt.Errorf("task %s: %w", task.Name, err)
+		}
+		g.printf("%s", expr)
+	}
+	g.printf("")
+	return nil
+}
+
+// httpHelpers maps HTTP methods to the Workflow helper that adds the task.
+var httpHelpers = map[string]string{
+	"GET":    "HttpGet",
+	"POST":   "HttpPost",
+	"PUT":    "HttpPut",
+	"PATCH":  "HttpPatch",
+	"DELETE": "HttpDelete",
+}
+
+// taskExpr renders a task builder call followed by its task-level modifiers.
+// Top-level tasks (wf set) are added to the workflow; nested tasks are
+// returned as *Task arguments for WithDo, WithBranch, WithTry and WithCatch.
+func taskExpr(task *workflow.Task, wf string) (string, error) {
+	name := strconv.Quote(task.Name)
+
+	var expr string
+	switch cfg := task.Config.(type) {
+	case *workflow.HttpCallTaskConfig:
+		opts := httpOptions(cfg)
+		method := strings.ToUpper(cfg.Method)
+		if method == "" {
+			method = "GET"
+		}
+		helper, ok := httpHelpers[method]
+		switch {
+		case wf != "" && ok:
+			expr = call(wf+"."+helper, []string{name, strconv.Quote(cfg.URI)}, opts)
+		default:
+			withMethod := "workflow.WithMethod(" + strconv.Quote(method) + ")"
+			expr = call("workflow.HttpCallTask", []string{name}, append([]string{withMethod, "workflow.WithURI(" + strconv.Quote(cfg.URI) + ")"}, opts...))
+		}
+
+	case *workflow.SetTaskConfig:
+		if wf != "" {
+			var pairs []string
+			for _, k := range sortedKeys(cfg.Variables) {
+				pairs = append(pairs, strconv.Quote(k)+", "+quote(cfg.Variables[k]))
+			}
+			expr = call(wf+".SetVars", []string{name}, pairs)
+		} else {
+			var opts []string
+			for _, k := range sortedKeys(cfg.Variables) {
+				opts = append(opts, "workflow.SetVar("+strconv.Quote(k)+", "+quote(cfg.Variables[k])+")")
+			}
+			expr = call("workflow.SetTask", []string{name}, opts)
+		}
+
+	case *workflow.AgentCallTaskConfig:
+		opts := agentCallOptions(cfg)
+		if wf != "" {
+			expr = call(wf+".CallAgent", []string{name}, opts)
+		} else {
+			expr = call("workflow.AgentCallTask", []string{name}, opts)
+		}
+
+	default:
+		ctor, opts, err := taskOptions(task)
+		if err != nil {
+			return "", err
+		}
+		expr = call(ctor, []string{name}, opts)
+	}
+
+	if task.ExportAs == "${.}" {
+		expr += ".ExportAll()"
+	} else if task.ExportAs != "" {
+		expr += ".Export(" + strconv.Quote(task.ExportAs) + ")"
+	}
+	if task.ThenTask == workflow.EndFlow {
+		expr += ".End()"
+	} else if task.ThenTask != "" {
+		expr += ".Then(" + strconv.Quote(task.ThenTask) + ")"
+	}
+	if task.Deadline != "" {
+		expr += ".WithDeadline(" + strconv.Quote(task.Deadline) + ")"
+	}
+	if task.IdempotencyKey != "" {
+		expr += ".WithIdempotencyKey(" + strconv.Quote(task.IdempotencyKey) + ")"
+	}
+
+	// Helpers on the workflow add the task themselves
+	if wf != "" && !strings.HasPrefix(expr, wf+".") {
+		expr = wf + ".AddTask(" + expr + ")"
+	}
+	return expr, nil
+}
+
+// taskOptions returns the constructor and options for task kinds without a
+// Workflow helper.
+func taskOptions(task *workflow.Task) (string, []string, error) {
+	var opts []string
+	switch cfg := task.Config.(type) {
+	case *workflow.GrpcCallTaskConfig:
+		opts = append(opts,
+			"workflow.WithService("+strconv.Quote(cfg.Service)+")",
+			"workflow.WithGrpcMethod("+strconv.Quote(cfg.Method)+")",
+		)
+		if cfg.Endpoint != "" {
+			opts = append(opts, "workflow.WithGrpcEndpoint("+strconv.Quote(cfg.Endpoint)+")")
+		}
+		if len(cfg.Metadata) > 0 {
+			opts = append(opts, "workflow.WithGrpcMetadata("+stringMapLiteral(cfg.Metadata)+")")
+		}
+		if len(cfg.Body) > 0 {
+			opts = append(opts, "workflow.WithGrpcBody("+mapLiteral(cfg.Body)+")")
+		}
+		if cfg.Deadline != "" {
+			opts = append(opts, "workflow.WithGrpcDeadline("+strconv.Quote(cfg.Deadline)+")")
+		}
+		if cfg.RetryPolicy != nil {
+			opts = append(opts, "workflow.WithGrpcRetry("+retryPolicyLiteral(cfg.RetryPolicy)+")")
+		}
+		return "workflow.GrpcCallTask", opts, nil
+
+	case *workflow.SwitchTaskConfig:
+		for _, c := range cfg.Cases {
+			opts = append(opts, "workflow.WithCase("+quoteAll([]string{c.Condition, c.Then})+")")
+		}
+		if cfg.DefaultTask != "" {
+			opts = append(opts, "workflow.WithDefault("+strconv.Quote(cfg.DefaultTask)+")")
+		}
+		return "workflow.SwitchTask", opts, nil
+
+	case *workflow.ForTaskConfig:
+		do, err := nestedTasks(cfg.Do)
+		if err != nil {
+			return "", nil, err
+		}
+		opts = append(opts,
+			"workflow.WithIn("+strconv.Quote(cfg.In)+")",
+			call("workflow.WithDo", nil, do),
+		)
+		return "workflow.ForTask", opts, nil
+
+	case *workflow.ForkTaskConfig:
+		for _, branch := range cfg.Branches {
+			tasks, err := nestedTasks(branch.Tasks)
+			if err != nil {
+				return "", nil, err
+			}
+			opts = append(opts, call("workflow.WithBranch", []string{strconv.Quote(branch.Name)}, tasks))
+		}
+		if cfg.Compete {
+			opts = append(opts, "workflow.WithCompete()")
+		}
+		return "workflow.ForkTask", opts, nil
+
+	case *workflow.TryTaskConfig:
+		tasks, err := nestedTasks(cfg.Tasks)
+		if err != nil {
+			return "", nil, err
+		}
+		opts = append(opts, call("workflow.WithTry", nil, tasks))
+		for _, catch := range cfg.Catch {
+			handlers, err := nestedTasks(catch.Tasks)
+			if err != nil {
+				return "", nil, err
+			}
+			errs := "nil"
+			if len(catch.Errors) > 0 {
+				errs = "[]string{" + quoteAll(catch.Errors) + "}"
+			}
+			opts = append(opts, call("workflow.WithCatch", []string{errs, strconv.Quote(catch.As)}, handlers))
+		}
+		return "workflow.TryTask", opts, nil
+
+	case *workflow.ListenTaskConfig:
+		opts = append(opts, "workflow.WithEvent("+strconv.Quote(cfg.Event)+")")
+		if cfg.Filter != "" {
+			opts = append(opts, "workflow.WithEventFilter("+strconv.Quote(cfg.Filter)+")")
+		}
+		for _, k := range sortedKeys(cfg.Correlation) {
+			opts = append(opts, "workflow.WithCorrelation("+quoteAll([]string{k, cfg.Correlation[k]})+")")
+		}
+		return "workflow.ListenTask", opts, nil
+
+	case *workflow.WaitTaskConfig:
+		if cfg.Duration != "" {
+			opts = append(opts, "workflow.WithDuration("+strconv.Quote(cfg.Duration)+")")
+		}
+		if cfg.Until != "" {
+			opts = append(opts, "workflow.WithUntilExpression("+strconv.Quote(cfg.Until)+")")
+		}
+		return "workflow.WaitTask", opts, nil
+
+	case *workflow.CallActivityTaskConfig:
+		opts = append(opts, "workflow.WithActivity("+strconv.Quote(cfg.Activity)+")")
+		if len(cfg.Input) > 0 {
+			opts = append(opts, "workflow.WithActivityInput("+mapLiteral(cfg.Input)+")")
+		}
+		if cfg.TaskQueue != "" {
+			opts = append(opts, "workflow.WithTaskQueue("+strconv.Quote(cfg.TaskQueue)+")")
+		}
+		if cfg.StartToCloseTimeout != "" {
+			opts = append(opts, "workflow.WithStartToCloseTimeout("+strconv.Quote(cfg.StartToCloseTimeout)+")")
+		}
+		if cfg.ScheduleToCloseTimeout != "" {
+			opts = append(opts, "workflow.WithScheduleToCloseTimeout("+strconv.Quote(cfg.ScheduleToCloseTimeout)+")")
+		}
+		if cfg.HeartbeatTimeout != "" {
+			opts = append(opts, "workflow.WithHeartbeatTimeout("+strconv.Quote(cfg.HeartbeatTimeout)+")")
+		}
+		if cfg.RetryPolicy != nil {
+			opts = append(opts, "workflow.WithActivityRetry("+retryPolicyLiteral(cfg.RetryPolicy)+")")
+		}
+		return "workflow.CallActivityTask", opts, nil
+
+	case *workflow.RaiseTaskConfig:
+		opts = append(opts, "workflow.WithError("+strconv.Quote(cfg.Error)+")")
+		if cfg.Message != "" {
+			opts = append(opts, "workflow.WithErrorMessage("+quote(cfg.Message)+")")
+		}
+		if len(cfg.Data) > 0 {
+			opts = append(opts, "workflow.WithErrorData("+mapLiteral(cfg.Data)+")")
+		}
+		return "workflow.RaiseTask", opts, nil
+
+	case *workflow.RunTaskConfig:
+		opts = append(opts, "workflow.WithWorkflow("+strconv.Quote(cfg.WorkflowName)+")")
+		if len(cfg.Input) > 0 {
+			opts = append(opts, "workflow.WithWorkflowInput("+mapLiteral(cfg.Input)+")")
+		}
+		return "workflow.RunTask", opts, nil
+
+	default:
+		return "", nil, fmt.Errorf("task kind %s is not supported", task.Kind)
+	}
+}
+
+// nestedTasks renders the tasks of a nested list as builder arguments.
+func nestedTasks(tasks []workflow.Task) ([]string, error) {
+	exprs := make([]string, 0, len(tasks))
+	for i := range tasks {
+		expr, err := taskExpr(&tasks[i], "")
+		if err != nil {
+			return nil, fmt.Errorf("task %s: %w", tasks[i].Name, err)
+		}
+		exprs = append(exprs, expr)
+	}
+	return exprs, nil
+}
+
+// httpOptions renders the options of an HTTP call other than method and URI.
+func httpOptions(cfg *workflow.HttpCallTaskConfig) []string {
+	var opts []string
+	for _, k := range sortedKeys(cfg.Headers) {
+		opts = append(opts, "workflow.Header("+quoteAll([]string{k, cfg.Headers[k]})+")")
+	}
+	if len(cfg.Body) > 0 && (cfg.BodyEncoding == "" || cfg.BodyEncoding == workflow.BodyEncodingJSON) {
+		opts = append(opts, "workflow.WithBody("+mapLiteral(cfg.Body)+")")
+	}
+	if cfg.TimeoutSeconds != 0 {
+		opts = append(opts, fmt.Sprintf("workflow.Timeout(%d)", cfg.TimeoutSeconds))
+	}
+	if cfg.Proxy != "" {
+		opts = append(opts, "workflow.WithProxy("+strconv.Quote(cfg.Proxy)+")")
+	}
+	if cfg.FollowRedirects != nil {
+		opts = append(opts, fmt.Sprintf("workflow.WithFollowRedirects(%t)", *cfg.FollowRedirects))
+	}
+	if cfg.RetryPolicy != nil {
+		opts = append(opts, "workflow.WithHttpRetry("+retryPolicyLiteral(cfg.RetryPolicy)+")")
+	}
+	return opts
+}
+
+// agentCallOptions renders the options of an agent call.
+func agentCallOptions(cfg *workflow.AgentCallTaskConfig) []string {
+	ref := []string{cfg.Agent.Slug()}
+	if cfg.Agent.Scope() != "" {
+		ref = append(ref, cfg.Agent.Scope())
+	}
+	opts := []string{"workflow.AgentOption(workflow.AgentBySlug(" + quoteAll(ref) + "))"}
+	if cfg.Message != "" {
+		opts = append(opts, "workflow.WithPrompt("+quote(cfg.Message)+")")
+	}
+	if len(cfg.Input) > 0 {
+		opts = append(opts, "workflow.WithAgentInput("+mapLiteral(cfg.Input)+")")
+	}
+	if len(cfg.Env) > 0 {
+		opts = append(opts, "workflow.WithEnv("+stringMapLiteral(cfg.Env)+")")
+	}
+	if c := cfg.Config; c != nil {
+		if c.Model != "" {
+			opts = append(opts, "workflow.AgentModel("+strconv.Quote(c.Model)+")")
+		}
+		if c.Timeout != 0 {
+			opts = append(opts, fmt.Sprintf("workflow.AgentTimeout(%d)", c.Timeout))
+		}
+		if c.Temperature != 0 {
+			opts = append(opts, "workflow.AgentTemperature("+strconv.FormatFloat(float64(c.Temperature), 'g', -1, 32)+")")
+		}
+	}
+	return opts
+}
+
+// retryPolicyLiteral renders a workflow.RetryPolicy literal.
+func retryPolicyLiteral(p *workflow.RetryPolicy) string {
+	var fields []string
+	if p.InitialInterval != "" {
+		fields = append(fields, "InitialInterval: "+strconv.Quote(p.InitialInterval))
+	}
+	if p.BackoffCoefficient != 0 {
+		fields = append(fields, "BackoffCoefficient: "+strconv.FormatFloat(p.BackoffCoefficient, 'g', -1, 64))
+	}
+	if p.MaximumInterval != "" {
+		fields = append(fields, "MaximumInterval: "+strconv.Quote(p.MaximumInterval))
+	}
+	if p.MaximumAttempts != 0 {
+		fields = append(fields, fmt.Sprintf("MaximumAttempts: %d", p.MaximumAttempts))
+	}
+	if len(p.NonRetryableErrorTypes) > 0 {
+		fields = append(fields, "NonRetryableErrorTypes: []string{"+quoteAll(p.NonRetryableErrorTypes)+"}")
+	}
+	return "workflow.RetryPolicy{" + strings.Join(fields, ", ") + "}"
+}
+
+// taskTODO lists settings of a top-level task, or of any task nested in it,
+// that have no builder in the generated code.
+func taskTODO(task *workflow.Task) string {
+	var missing []string
+	seen := map[string]bool{}
+	add := func(t *workflow.Task, setting string) {
+		item := fmt.Sprintf("%s %s", t.Name, setting)
+		if !seen[item] {
+			seen[item] = true
+			missing = append(missing, item)
+		}
+	}
+
+	scope := &workflow.Workflow{Tasks: []*workflow.Task{task}}
+	for t := range scope.AllTasks() {
+		switch cfg := t.Config.(type) {
+		case *workflow.HttpCallTaskConfig:
+			if cfg.OAuth2 != nil {
+				add(t, "OAuth2 authentication")
+			}
+			if cfg.TLS != nil {
+				add(t, "TLS settings")
+			}
+			if cfg.BodyEncoding != "" && cfg.BodyEncoding != workflow.BodyEncodingJSON {
+				add(t, string(cfg.BodyEncoding)+" body")
+			}
+		case *workflow.GrpcCallTaskConfig:
+			if cfg.TLS != nil {
+				add(t, "TLS settings")
+			}
+		case *workflow.RunTaskConfig:
+			if cfg.WorkflowNamespace != "" {
+				add(t, fmt.Sprintf("sub-workflow %s/%s (use WithWorkflowRef)", cfg.WorkflowNamespace, cfg.WorkflowName))
+			}
+		case *workflow.ListenTaskConfig:
+			if cfg.Timeout != "" {
+				add(t, fmt.Sprintf("timeout %s (then %s)", cfg.Timeout, cfg.TimeoutThen))
+			}
+		}
+	}
+	if len(missing) == 0 {
+		return ""
+	}
+	return "port " + strings.Join(missing, ", ") + " by hand"
+}
@@ -0,0 +1,320 @@
+package synth
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	agentv1 "buf.build/gen/go/leftbin/stigmer/protocolbuffers/go/ai/stigmer/agentic/agent/v1"
+	workflowv1 "buf.build/gen/go/leftbin/stigmer/protocolbuffers/go/ai/stigmer/agentic/workflow/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ChangeKind describes how a resource changed between two manifests.
+type ChangeKind string
+
+const (
+	// ChangeAdded marks a resource that only exists in the new manifest.
+	ChangeAdded ChangeKind = "added"
+
+	// ChangeRemoved marks a resource that only exists in the old manifest.
+	ChangeRemoved ChangeKind = "removed"
+
+	// ChangeModified marks a resource that exists in both but differs.
+	ChangeModified ChangeKind = "modified"
+)
+
+// symbol returns the preview prefix for a change kind.
+func (k ChangeKind) symbol() string {
+	switch k {
+	case ChangeAdded:
+		return "+"
+	case ChangeRemoved:
+		return "-"
+	default:
+		return "~"
+	}
+}
+
+// Change describes one added, removed or modified resource.
+type Change struct {
+	Kind ChangeKind
+
+	// Resource is the resource type: "agent", "workflow", "task", "skill",
+	// "mcp_server", "sub_agent" or "environment_variable"
+	Resource string
+
+	// Name identifies the resource, e.g. "code-reviewer" or "orders/fulfil-order"
+	Name string
+
+	// Fields lists the manifest fields that differ (modified resources only),
+	// e.g. "instructions" or "task_config.endpoint"
+	Fields []string
+
+	// Children lists changes to resources nested in a modified agent or
+	// workflow, such as its tasks or MCP servers
+	Children []Change
+}
+
+// Plan is the set of changes between two manifests.
+type Plan struct {
+	// Changes lists agent and workflow changes in manifest order; removed
+	// resources come last
+	Changes []Change
+}
+
+// Empty reports whether the manifests are equivalent.
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// Count returns the number of top-level resources of each change kind.
+func (p *Plan) Count(kind ChangeKind) int {
+	n := 0
+	for _, c := range p.Changes {
+		if c.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// String renders the plan as a preview, one change per line.
+func (p *Plan) String() string {
+	if p.Empty() {
+		return "No changes.\n"
+	}
+	var b strings.Builder
+	for _, c := range p.Changes {
+		writeChange(&b, c, "")
+	}
+	fmt.Fprintf(&b, "\nResources: %d to add, %d to change, %d to remove\n",
+		p.Count(ChangeAdded), p.Count(ChangeModified), p.Count(ChangeRemoved))
+	return b.String()
+}
+
+func writeChange(b *strings.Builder, c Change, indent string) {
+	fmt.Fprintf(b, "%s%s %s %s", indent, c.Kind.symbol(), c.Resource, c.Name)
+	if len(c.Fields) > 0 {
+		fmt.Fprintf(b, " (%s)", strings.Join(c.Fields, ", "))
+	}
+	b.WriteString("\n")
+	for _, child := range c.Children {
+		writeChange(b, child, indent+"    ")
+	}
+}
+
+// Diff compares a newly synthesized manifest with a previous one.
+//
+// Both manifests must be *agentv1.AgentManifest or both
+// *workflowv1.WorkflowManifest. oldManifest may be nil (e.g., on first
+// deployment), in which case every resource is reported as added.
+//
+// Agents are matched by name and workflows by namespace and name. SDK metadata
+// such as the generation time and generated skill IDs is ignored.
+func Diff(oldManifest, newManifest proto.Message) (*Plan, error) {
+	switch n := newManifest.(type) {
+	case *agentv1.AgentManifest:
+		var o *agentv1.AgentManifest
+		if oldManifest != nil {
+			var ok bool
+			if o, ok = oldManifest.(*agentv1.AgentManifest); !ok {
+				return nil, fmt.Errorf("cannot compare %T with %T", oldManifest, newManifest)
+			}
+		}
+		return &Plan{Changes: diffAgents(o.GetAgents(), n.GetAgents())}, nil
+
+	case *workflowv1.WorkflowManifest:
+		var o *workflowv1.WorkflowManifest
+		if oldManifest != nil {
+			var ok bool
+			if o, ok = oldManifest.(*workflowv1.WorkflowManifest); !ok {
+				return nil, fmt.Errorf("cannot compare %T with %T", oldManifest, newManifest)
+			}
+		}
+		return &Plan{Changes: diffWorkflows(o.GetWorkflows(), n.GetWorkflows())}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported manifest type %T", newManifest)
+	}
+}
+
+// diffAgents compares agent blueprints by name.
+func diffAgents(oldAgents, newAgents []*agentv1.AgentBlueprint) []Change {
+	return diffByKey(oldAgents, newAgents, "agent",
+		func(a *agentv1.AgentBlueprint) string { return a.GetName() },
+		func(o, n *agentv1.AgentBlueprint) ([]string, []Change) {
+			fields := changedFields(o, n, "skills", "mcp_servers", "sub_agents", "environment_variables")
+			var children []Change
+			children = append(children, diffByKey(o.GetSkills(), n.GetSkills(), "skill", skillKey, diffSkill)...)
+			children = append(children, diffByKey(o.GetMcpServers(), n.GetMcpServers(), "mcp_server",
+				func(m *agentv1.ManifestMcpServer) string { return m.GetName() },
+				leafDiff[*agentv1.ManifestMcpServer])...)
+			children = append(children, diffByKey(o.GetSubAgents(), n.GetSubAgents(), "sub_agent", subAgentKey, diffSubAgent)...)
+			children = append(children, diffByKey(o.GetEnvironmentVariables(), n.GetEnvironmentVariables(), "environment_variable",
+				func(e *agentv1.ManifestEnvironmentVariable) string { return e.GetName() },
+				leafDiff[*agentv1.ManifestEnvironmentVariable])...)
+			return fields, children
+		})
+}
+
+// diffWorkflows compares workflows by namespace and name.
+func diffWorkflows(oldWorkflows, newWorkflows []*workflowv1.Workflow) []Change {
+	return diffByKey(oldWorkflows, newWorkflows, "workflow",
+		func(w *workflowv1.Workflow) string {
+			doc := w.GetSpec().GetDocument()
+			return doc.GetNamespace() + "/" + doc.GetName()
+		},
+		func(o, n *workflowv1.Workflow) ([]string, []Change) {
+			fields := changedFields(o, n, "spec.tasks")
+			children := diffByKey(o.GetSpec().GetTasks(), n.GetSpec().GetTasks(), "task",
+				func(t *workflowv1.WorkflowTask) string { return t.GetName() },
+				leafDiff[*workflowv1.WorkflowTask])
+			return fields, children
+		})
+}
+
+// diffByKey matches old and new resources by key. compare returns the changed
+// fields and nested changes of a resource present in both.
+func diffByKey[T proto.Message](oldItems, newItems []T, resource string, key func(T) string, compare func(o, n T) ([]string, []Change)) []Change {
+	oldByKey := make(map[string]T, len(oldItems))
+	for _, item := range oldItems {
+		oldByKey[key(item)] = item
+	}
+	newKeys := make(map[string]bool, len(newItems))
+
+	var changes []Change
+	for _, item := range newItems {
+		k := key(item)
+		newKeys[k] = true
+		previous, ok := oldByKey[k]
+		if !ok {
+			changes = append(changes, Change{Kind: ChangeAdded, Resource: resource, Name: k})
+			continue
+		}
+		fields, children := compare(previous, item)
+		if len(fields) > 0 || len(children) > 0 {
+			changes = append(changes, Change{Kind: ChangeModified, Resource: resource, Name: k, Fields: fields, Children: children})
+		}
+	}
+	for _, item := range oldItems {
+		if k := key(item); !newKeys[k] {
+			newKeys[k] = true // report duplicates once
+			changes = append(changes, Change{Kind: ChangeRemoved, Resource: resource, Name: k})
+		}
+	}
+	return changes
+}
+
+// leafDiff compares resources without nested resources.
+func leafDiff[T proto.Message](o, n T) ([]string, []Change) {
+	return changedFields(o, n), nil
+}
+
+// skillKey names a skill by its source.
+func skillKey(s *agentv1.ManifestSkill) string {
+	switch {
+	case s.GetInline() != nil:
+		return s.GetInline().GetName()
+	case s.GetOrg() != nil:
+		return s.GetOrg().GetOrg() + "/" + s.GetOrg().GetName()
+	default:
+		return s.GetPlatform().GetName()
+	}
+}
+
+// diffSkill compares skills, ignoring the ID generated during synthesis.
+func diffSkill(o, n *agentv1.ManifestSkill) ([]string, []Change) {
+	return changedFields(o, n, "id"), nil
+}
+
+// subAgentKey names a sub-agent by its inline name or referenced instance.
+func subAgentKey(s *agentv1.ManifestSubAgent) string {
+	if s.GetInline() != nil {
+		return s.GetInline().GetName()
+	}
+	return s.GetReference().GetAgentInstanceId()
+}
+
+// diffSubAgent compares sub-agents, ignoring generated skill IDs and the order
+// of tool selections.
+func diffSubAgent(o, n *agentv1.ManifestSubAgent) ([]string, []Change) {
+	return changedFields(normalizeSubAgent(o), normalizeSubAgent(n)), nil
+}
+
+func normalizeSubAgent(s *agentv1.ManifestSubAgent) *agentv1.ManifestSubAgent {
+	if s.GetInline() == nil {
+		return s
+	}
+	s = proto.Clone(s).(*agentv1.ManifestSubAgent)
+	inline := s.GetInline()
+	for _, skill := range inline.GetSkills() {
+		skill.Id = ""
+	}
+	sort.Slice(inline.ToolSelections, func(i, j int) bool {
+		return inline.ToolSelections[i].GetMcpServerName() < inline.ToolSelections[j].GetMcpServerName()
+	})
+	return s
+}
+
+// changedFields returns the paths of the fields that differ between two
+// messages of the same type, skipping the given paths. Nested messages are
+// compared field by field, maps and google.protobuf.Struct values key by key.
+func changedFields(o, n proto.Message, skip ...string) []string {
+	skipped := make(map[string]bool, len(skip))
+	for _, path := range skip {
+		skipped[path] = true
+	}
+	var fields []string
+	compareMessages(o.ProtoReflect(), n.ProtoReflect(), "", skipped, &fields)
+	return fields
+}
+
+func compareMessages(o, n protoreflect.Message, prefix string, skip map[string]bool, out *[]string) {
+	fds := n.Descriptor().Fields()
+	for i := 0; i < fds.Len(); i++ {
+		fd := fds.Get(i)
+		path := prefix + string(fd.Name())
+		if skip[path] {
+			continue
+		}
+		ov, nv := o.Get(fd), n.Get(fd)
+		if ov.Equal(nv) {
+			continue
+		}
+		switch {
+		case fd.IsMap() && fd.MapKey().Kind() == protoreflect.StringKind:
+			compareMaps(ov.Map(), nv.Map(), path+"[%s]", out)
+		case fd.IsMap() || fd.IsList() || fd.Message() == nil || !o.Has(fd) || !n.Has(fd):
+			*out = append(*out, path)
+		case fd.Message().FullName() == "google.protobuf.Struct":
+			structFields := fd.Message().Fields().ByName("fields")
+			compareMaps(ov.Message().Get(structFields).Map(), nv.Message().Get(structFields).Map(), path+".%s", out)
+		default:
+			compareMessages(ov.Message(), nv.Message(), path+".", skip, out)
+		}
+	}
+}
+
+// compareMaps appends format applied to each key whose value differs. Both
+// maps must have string keys.
+func compareMaps(o, n protoreflect.Map, format string, out *[]string) {
+	keys := map[string]bool{}
+	collect := func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+		keys[k.String()] = true
+		return true
+	}
+	o.Range(collect)
+	n.Range(collect)
+
+	var changed []string
+	for k := range keys {
+		mk := protoreflect.ValueOfString(k).MapKey()
+		if !o.Has(mk) || !n.Has(mk) || !o.Get(mk).Equal(n.Get(mk)) {
+			changed = append(changed, fmt.Sprintf(format, k))
+		}
+	}
+	sort.Strings(changed)
+	*out = append(*out, changed...)
+}
@@ -0,0 +1,152 @@
+package synth
+
+import (
+	"strings"
+	"testing"
+
+	agentv1 "buf.build/gen/go/leftbin/stigmer/protocolbuffers/go/ai/stigmer/agentic/agent/v1"
+	workflowv1 "buf.build/gen/go/leftbin/stigmer/protocolbuffers/go/ai/stigmer/agentic/workflow/v1"
+
+	"github.com/leftbin/stigmer-sdk/go/agent"
+	"github.com/leftbin/stigmer-sdk/go/environment"
+	"github.com/leftbin/stigmer-sdk/go/internal/synth"
+	"github.com/leftbin/stigmer-sdk/go/mcpserver"
+	"github.com/leftbin/stigmer-sdk/go/skill"
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+func agentManifest(t *testing.T, instructions string, opts ...agent.Option) *agentv1.AgentManifest {
+	t.Helper()
+	guide, err := skill.New(skill.WithName("style-guide"), skill.WithMarkdown("# Style"))
+	if err != nil {
+		t.Fatalf("skill.New() error = %v", err)
+	}
+	opts = append([]agent.Option{
+		agent.WithName("code-reviewer"),
+		agent.WithInstructions(instructions),
+		agent.WithSkill(*guide),
+	}, opts...)
+	a, err := agent.Build(opts...)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	manifest, err := synth.ToManifest(a)
+	if err != nil {
+		t.Fatalf("ToManifest() error = %v", err)
+	}
+	return manifest
+}
+
+func workflowManifest(t *testing.T, name string, build func(wf *workflow.Workflow)) *workflowv1.WorkflowManifest {
+	t.Helper()
+	wf, err := workflow.Build(workflow.WithNamespace("orders"), workflow.WithName(name))
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	build(wf)
+	manifest, err := synth.ToWorkflowManifest(wf)
+	if err != nil {
+		t.Fatalf("ToWorkflowManifest() error = %v", err)
+	}
+	return manifest
+}
+
+// TestDiff_Agents verifies agent fields and nested resources are compared.
+func TestDiff_Agents(t *testing.T) {
+	token, err := environment.New(environment.WithName("LEGACY_TOKEN"), environment.WithSecret(true))
+	if err != nil {
+		t.Fatalf("environment.New() error = %v", err)
+	}
+	github, err := mcpserver.Stdio(mcpserver.WithName("github"), mcpserver.WithCommand("npx"))
+	if err != nil {
+		t.Fatalf("Stdio() error = %v", err)
+	}
+
+	previous := agentManifest(t, "Review pull requests.", agent.WithEnvironmentVariable(token))
+	current := agentManifest(t, "Review pull requests carefully.", agent.WithMCPServer(github))
+
+	plan, err := Diff(previous, current)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	want := "~ agent code-reviewer (instructions)\n" +
+		"    + mcp_server github\n" +
+		"    - environment_variable LEGACY_TOKEN\n" +
+		"\nResources: 0 to add, 1 to change, 0 to remove\n"
+	if got := plan.String(); got != want {
+		t.Errorf("String() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+// TestDiff_Unchanged verifies generated metadata does not count as a change.
+func TestDiff_Unchanged(t *testing.T) {
+	plan, err := Diff(agentManifest(t, "Review pull requests."), agentManifest(t, "Review pull requests."))
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if !plan.Empty() {
+		t.Errorf("Diff() = %s, want no changes", plan)
+	}
+	if got := plan.String(); got != "No changes.\n" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+// TestDiff_Workflows verifies workflows are matched by name and tasks compared.
+func TestDiff_Workflows(t *testing.T) {
+	previous := workflowManifest(t, "fulfil-order", func(wf *workflow.Workflow) {
+		wf.HttpGet("fetch", "https://api.example.com/orders")
+		wf.SetVars("mark", "status", "done")
+	})
+	previous.Workflows = append(previous.Workflows,
+		workflowManifest(t, "cleanup", func(wf *workflow.Workflow) { wf.SetVars("noop", "x", "1") }).Workflows...)
+
+	current := workflowManifest(t, "fulfil-order", func(wf *workflow.Workflow) {
+		wf.HttpGet("fetch", "https://api.example.com/v2/orders")
+		wf.SetVars("notify", "sent", "true")
+	})
+	current.Workflows = append(current.Workflows,
+		workflowManifest(t, "refund", func(wf *workflow.Workflow) { wf.SetVars("noop", "x", "1") }).Workflows...)
+
+	plan, err := Diff(previous, current)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if len(plan.Changes) != 3 {
+		t.Fatalf("Changes = %+v, want 3", plan.Changes)
+	}
+	if c := plan.Changes[0]; c.Kind != ChangeModified || c.Name != "orders/fulfil-order" || len(c.Children) != 3 {
+		t.Errorf("Changes[0] = %+v, want modified fulfil-order with 3 task changes", c)
+	}
+	fetch := plan.Changes[0].Children[0]
+	if fetch.Name != "fetch" || strings.Join(fetch.Fields, ",") != "task_config.endpoint" {
+		t.Errorf("fetch change = %+v, want task_config.endpoint", fetch)
+	}
+	if c := plan.Changes[1]; c.Kind != ChangeAdded || c.Name != "orders/refund" {
+		t.Errorf("Changes[1] = %+v, want added refund", c)
+	}
+	if c := plan.Changes[2]; c.Kind != ChangeRemoved || c.Name != "orders/cleanup" {
+		t.Errorf("Changes[2] = %+v, want removed cleanup", c)
+	}
+}
+
+// TestDiff_FirstDeployment verifies a nil previous manifest adds everything.
+func TestDiff_FirstDeployment(t *testing.T) {
+	plan, err := Diff(nil, agentManifest(t, "Review pull requests."))
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if plan.Count(ChangeAdded) != 1 {
+		t.Errorf("Diff() = %s, want one added agent", plan)
+	}
+}
+
+// TestDiff_MismatchedTypes verifies agent and workflow manifests cannot be compared.
+func TestDiff_MismatchedTypes(t *testing.T) {
+	if _, err := Diff(&workflowv1.WorkflowManifest{}, &agentv1.AgentManifest{}); err == nil {
+		t.Error("Diff() expected error for mismatched manifest types")
+	}
+	if _, err := Diff(nil, nil); err == nil {
+		t.Error("Diff() expected error for nil manifests")
+	}
+}
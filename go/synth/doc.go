@@ -0,0 +1,32 @@
+// Package synth works with manifests produced by synthesis.
+//
+// Diff compares a newly synthesized manifest with the one that was last
+// deployed, so tooling can show a preview of what an apply will change:
+//
+//	previous := &agentv1.AgentManifest{}
+//	if err := proto.Unmarshal(oldData, previous); err != nil {
+//	    log.Fatal(err)
+//	}
+//	plan, err := synth.Diff(previous, current)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Print(plan)
+//
+// The plan lists agents and workflows that are added, removed or changed, and
+// within them the tasks, skills, MCP servers, sub-agents and environment
+// variables that changed:
+//
+//	~ agent code-reviewer (instructions)
+//	    + mcp_server github
+//	    - environment_variable LEGACY_TOKEN
+//	+ workflow orders/refund
+//
+//	Resources: 1 to add, 1 to change, 0 to remove
+//
+// ExportRedacted writes a manifest as JSON with instructions, URLs, headers,
+// bodies and other proprietary content replaced, so it can be attached to a
+// bug report while keeping the structure needed to reproduce the issue.
+//
+// Code generation from manifests lives in the codegen subpackage.
+package synth
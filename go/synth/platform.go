@@ -0,0 +1,285 @@
+package synth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	workflowv1 "buf.build/gen/go/leftbin/stigmer/protocolbuffers/go/ai/stigmer/agentic/workflow/v1"
+	"buf.build/gen/go/leftbin/stigmer/protocolbuffers/go/ai/stigmer/commons/apiresource"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+// ErrUnsupportedFeature is returned by CheckCompatibility when a manifest uses
+// a task kind or option the target platform does not support.
+var ErrUnsupportedFeature = errors.New("not supported by the target platform")
+
+// Capabilities describes what a Stigmer platform release can run. It can be
+// looked up for a known release with PlatformCapabilities or fetched from a
+// running platform with FetchCapabilities.
+type Capabilities struct {
+	// Version is the platform release, e.g. "2025.1"
+	Version string `json:"version"`
+
+	// TaskKinds lists the supported workflow task kinds, e.g. "WORKFLOW_TASK_KIND_SET"
+	TaskKinds []string `json:"taskKinds"`
+
+	// WorkflowAnnotations lists the supported workflow option annotations,
+	// e.g. "workflow.stigmer.ai/triggers"
+	WorkflowAnnotations []string `json:"workflowAnnotations"`
+}
+
+// platformRelease lists what a platform release added.
+type platformRelease struct {
+	version     string
+	taskKinds   []apiresource.WorkflowTaskKind
+	annotations []string
+}
+
+// platformReleases lists known platform releases, oldest first, and is used
+// when no capability document is available (see FetchCapabilities). It is
+// maintained by hand and not read from a platform: the task kinds are the
+// apiresource.WorkflowTaskKind values and the annotations the workflow.Annotation*
+// keys, each listed under the release that started accepting it. A capability
+// document fetched from the platform itself takes precedence.
+var platformReleases = []platformRelease{
+	{
+		version: "2024.4",
+		taskKinds: []apiresource.WorkflowTaskKind{
+			apiresource.WorkflowTaskKind_WORKFLOW_TASK_KIND_SET,
+			apiresource.WorkflowTaskKind_WORKFLOW_TASK_KIND_HTTP_CALL,
+			apiresource.WorkflowTaskKind_WORKFLOW_TASK_KIND_GRPC_CALL,
+			apiresource.WorkflowTaskKind_WORKFLOW_TASK_KIND_CALL_ACTIVITY,
+			apiresource.WorkflowTaskKind_WORKFLOW_TASK_KIND_SWITCH,
+			apiresource.WorkflowTaskKind_WORKFLOW_TASK_KIND_FOR,
+			apiresource.WorkflowTaskKind_WORKFLOW_TASK_KIND_FORK,
+			apiresource.WorkflowTaskKind_WORKFLOW_TASK_KIND_TRY,
+			apiresource.WorkflowTaskKind_WORKFLOW_TASK_KIND_LISTEN,
+			apiresource.WorkflowTaskKind_WORKFLOW_TASK_KIND_WAIT,
+			apiresource.WorkflowTaskKind_WORKFLOW_TASK_KIND_RAISE,
+			apiresource.WorkflowTaskKind_WORKFLOW_TASK_KIND_RUN,
+		},
+		annotations: []string{
+			workflow.AnnotationTriggers,
+			workflow.AnnotationTimeout,
+		},
+	},
+	{
+		version: "2025.1",
+		taskKinds: []apiresource.WorkflowTaskKind{
+			apiresource.WorkflowTaskKind_WORKFLOW_TASK_KIND_AGENT_CALL,
+		},
+		annotations: []string{
+			workflow.AnnotationTaskDeadlines,
+			workflow.AnnotationErrorPolicy,
+			workflow.AnnotationIdempotencyKeys,
+			workflow.AnnotationDisabled,
+			workflow.AnnotationDisabledReason,
+		},
+	},
+	{
+		version: "2025.2",
+		annotations: []string{
+			workflow.AnnotationRollout,
+			workflow.AnnotationResources,
+		},
+	},
+}
+
+// PlatformVersions returns the platform releases known to this SDK, oldest first.
+func PlatformVersions() []string {
+	versions := make([]string, len(platformReleases))
+	for i, r := range platformReleases {
+		versions[i] = r.version
+	}
+	return versions
+}
+
+// PlatformCapabilities returns the capabilities of a platform release, such as
+// "2025.1". Versions newer than the latest known release get its capabilities.
+func PlatformCapabilities(version string) (*Capabilities, error) {
+	target, err := parsePlatformVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	caps := &Capabilities{Version: version}
+	for _, r := range platformReleases {
+		v, _ := parsePlatformVersion(r.version)
+		if comparePlatformVersions(v, target) > 0 {
+			break
+		}
+		for _, kind := range r.taskKinds {
+			caps.TaskKinds = append(caps.TaskKinds, kind.String())
+		}
+		caps.WorkflowAnnotations = append(caps.WorkflowAnnotations, r.annotations...)
+	}
+	if len(caps.TaskKinds) == 0 {
+		return nil, fmt.Errorf("platform version %q predates the oldest supported release %s", version, platformReleases[0].version)
+	}
+	return caps, nil
+}
+
+// FetchCapabilities downloads the capability document a platform serves at url
+// and decodes it. A nil client uses http.DefaultClient.
+//
+// Example:
+//
+//	caps, err := synth.FetchCapabilities(ctx, nil, capabilitiesURL)
+//	if err != nil {
+//	    return err
+//	}
+//	stigmer.Run(define, stigmer.WithPlatformCapabilities(caps))
+func FetchCapabilities(ctx context.Context, client *http.Client, url string) (*Capabilities, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching platform capabilities: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching platform capabilities: %s returned %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("fetching platform capabilities: %w", err)
+	}
+
+	var caps Capabilities
+	if err := json.Unmarshal(data, &caps); err != nil {
+		return nil, fmt.Errorf("invalid platform capability document: %w", err)
+	}
+	if caps.Version == "" || len(caps.TaskKinds) == 0 {
+		return nil, errors.New("invalid platform capability document: version and taskKinds are required")
+	}
+	return &caps, nil
+}
+
+// CheckCompatibility reports every task kind (including nested tasks) and
+// workflow option in the manifest that the platform does not support. The
+// returned error joins one error per problem, each wrapping
+// ErrUnsupportedFeature.
+func CheckCompatibility(manifest *workflowv1.WorkflowManifest, caps *Capabilities) error {
+	kinds := make(map[string]bool, len(caps.TaskKinds))
+	for _, k := range caps.TaskKinds {
+		kinds[k] = true
+	}
+	annotations := make(map[string]bool, len(caps.WorkflowAnnotations))
+	for _, a := range caps.WorkflowAnnotations {
+		annotations[a] = true
+	}
+
+	var errs []error
+	for _, wf := range manifest.GetWorkflows() {
+		doc := wf.GetSpec().GetDocument()
+		name := doc.GetNamespace() + "/" + doc.GetName()
+
+		keys := make([]string, 0, len(wf.GetMetadata().GetAnnotations()))
+		for key := range wf.GetMetadata().GetAnnotations() {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if strings.HasPrefix(key, workflow.AnnotationPrefix) && !annotations[key] {
+				errs = append(errs, fmt.Errorf("workflow %s: option %s%s: %w",
+					name, key, introducedIn(func(r platformRelease) bool { return slices.Contains(r.annotations, key) }), ErrUnsupportedFeature))
+			}
+		}
+
+		for _, task := range wf.GetSpec().GetTasks() {
+			checkTaskKind(name, task.GetName(), task.GetKind().String(), kinds, &errs)
+			checkNestedTasks(name, task.GetTaskConfig(), kinds, &errs)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("platform %s: %w", caps.Version, errors.Join(errs...))
+	}
+	return nil
+}
+
+func checkTaskKind(workflow, task, kind string, kinds map[string]bool, errs *[]error) {
+	if kinds[kind] {
+		return
+	}
+	*errs = append(*errs, fmt.Errorf("workflow %s: task %s: kind %s%s: %w",
+		workflow, task, strings.TrimPrefix(kind, "WORKFLOW_TASK_KIND_"),
+		introducedIn(func(r platformRelease) bool {
+			return slices.Contains(r.taskKinds, apiresource.WorkflowTaskKind(apiresource.WorkflowTaskKind_value[kind]))
+		}), ErrUnsupportedFeature))
+}
+
+// checkNestedTasks checks the kinds of tasks nested in a task config (FOR,
+// FORK, TRY and CATCH bodies), which are objects with a name and kind.
+func checkNestedTasks(workflow string, config *structpb.Struct, kinds map[string]bool, errs *[]error) {
+	var visit func(v *structpb.Value)
+	visit = func(v *structpb.Value) {
+		switch {
+		case v.GetStructValue() != nil:
+			fields := v.GetStructValue().GetFields()
+			name, hasName := fields["name"]
+			kind, hasKind := fields["kind"]
+			if hasName && hasKind {
+				if _, known := apiresource.WorkflowTaskKind_value[kind.GetStringValue()]; known {
+					checkTaskKind(workflow, name.GetStringValue(), kind.GetStringValue(), kinds, errs)
+				}
+			}
+			keys := make([]string, 0, len(fields))
+			for key := range fields {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				visit(fields[key])
+			}
+		case v.GetListValue() != nil:
+			for _, item := range v.GetListValue().GetValues() {
+				visit(item)
+			}
+		}
+	}
+	visit(structpb.NewStructValue(config))
+}
+
+// introducedIn names the known release that added a feature, for messages.
+func introducedIn(added func(platformRelease) bool) string {
+	for _, r := range platformReleases {
+		if added(r) {
+			return " (requires platform " + r.version + ")"
+		}
+	}
+	return ""
+}
+
+// parsePlatformVersion parses a "YEAR.RELEASE" platform version.
+func parsePlatformVersion(version string) ([2]int, error) {
+	year, release, ok := strings.Cut(version, ".")
+	y, errYear := strconv.Atoi(year)
+	r, errRelease := strconv.Atoi(release)
+	if !ok || errYear != nil || errRelease != nil || y < 0 || r < 0 {
+		return [2]int{}, fmt.Errorf("invalid platform version %q (want YEAR.RELEASE, e.g. 2025.1)", version)
+	}
+	return [2]int{y, r}, nil
+}
+
+func comparePlatformVersions(a, b [2]int) int {
+	if a[0] != b[0] {
+		return a[0] - b[0]
+	}
+	return a[1] - b[1]
+}
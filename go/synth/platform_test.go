@@ -0,0 +1,106 @@
+package synth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+// TestPlatformCapabilities verifies releases accumulate the features of older ones.
+func TestPlatformCapabilities(t *testing.T) {
+	old, err := PlatformCapabilities("2024.4")
+	if err != nil {
+		t.Fatalf("PlatformCapabilities() error = %v", err)
+	}
+	latest, err := PlatformCapabilities("2026.3")
+	if err != nil {
+		t.Fatalf("PlatformCapabilities() error = %v", err)
+	}
+	if len(latest.TaskKinds) <= len(old.TaskKinds) || len(latest.WorkflowAnnotations) <= len(old.WorkflowAnnotations) {
+		t.Errorf("newer release should support more: %+v vs %+v", latest, old)
+	}
+
+	for _, version := range []string{"2023.1", "2025", "next"} {
+		if _, err := PlatformCapabilities(version); err == nil {
+			t.Errorf("PlatformCapabilities(%q) expected error", version)
+		}
+	}
+}
+
+// TestCheckCompatibility verifies unsupported task kinds (including nested
+// tasks) and workflow options are all reported.
+func TestCheckCompatibility(t *testing.T) {
+	manifest := workflowManifest(t, "triage", func(wf *workflow.Workflow) {
+		if err := workflow.WithResources("1", "1Gi")(wf); err != nil {
+			t.Fatal(err)
+		}
+		wf.AddTasks(
+			workflow.AgentCallTask("classify", workflow.AgentOption(workflow.AgentBySlug("classifier")), workflow.Message("Classify")),
+			workflow.ForTask("each", workflow.WithIn("${ .items }"), workflow.WithDo(
+				workflow.AgentCallTask("summarize", workflow.AgentOption(workflow.AgentBySlug("summarizer")), workflow.Message("Summarize")),
+			)),
+		)
+	})
+
+	caps, err := PlatformCapabilities("2024.4")
+	if err != nil {
+		t.Fatalf("PlatformCapabilities() error = %v", err)
+	}
+	err = CheckCompatibility(manifest, caps)
+	if !errors.Is(err, ErrUnsupportedFeature) {
+		t.Fatalf("CheckCompatibility() error = %v, want ErrUnsupportedFeature", err)
+	}
+	for _, want := range []string{
+		"workflow orders/triage: option workflow.stigmer.ai/resources (requires platform 2025.2)",
+		"workflow orders/triage: task classify: kind AGENT_CALL (requires platform 2025.1)",
+		"workflow orders/triage: task summarize: kind AGENT_CALL (requires platform 2025.1)",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error missing %q:\n%v", want, err)
+		}
+	}
+
+	caps, _ = PlatformCapabilities("2025.2")
+	if err := CheckCompatibility(manifest, caps); err != nil {
+		t.Errorf("CheckCompatibility(2025.2) error = %v", err)
+	}
+
+	custom := &Capabilities{Version: "edge", TaskKinds: caps.TaskKinds}
+	if err := CheckCompatibility(manifest, custom); err == nil || !strings.Contains(err.Error(), "platform edge: ") {
+		t.Errorf("CheckCompatibility(custom) error = %v, want unsupported resources option", err)
+	}
+}
+
+// TestFetchCapabilities verifies the capability document served by a platform is decoded and checked.
+func TestFetchCapabilities(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/capabilities":
+			w.Write([]byte(`{"version":"2025.3","taskKinds":["WORKFLOW_TASK_KIND_SET"],"workflowAnnotations":["workflow.stigmer.ai/timeout"]}`))
+		case "/empty":
+			w.Write([]byte(`{}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	caps, err := FetchCapabilities(context.Background(), nil, srv.URL+"/capabilities")
+	if err != nil {
+		t.Fatalf("FetchCapabilities() error = %v", err)
+	}
+	if caps.Version != "2025.3" || len(caps.TaskKinds) != 1 || caps.WorkflowAnnotations[0] != workflow.AnnotationTimeout {
+		t.Errorf("FetchCapabilities() = %+v", caps)
+	}
+
+	for _, path := range []string{"/empty", "/missing"} {
+		if _, err := FetchCapabilities(context.Background(), srv.Client(), srv.URL+path); err == nil {
+			t.Errorf("FetchCapabilities(%s) expected error", path)
+		}
+	}
+}
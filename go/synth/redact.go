@@ -0,0 +1,150 @@
+package synth
+
+import (
+	"fmt"
+	"strings"
+
+	agentv1 "buf.build/gen/go/leftbin/stigmer/protocolbuffers/go/ai/stigmer/agentic/agent/v1"
+	workflowv1 "buf.build/gen/go/leftbin/stigmer/protocolbuffers/go/ai/stigmer/agentic/workflow/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Redacted replaces the values ExportRedacted strips.
+const Redacted = "<redacted>"
+
+// redactedFields are the manifest fields whose strings are always redacted:
+// free text, default values and connection details.
+var redactedFields = map[protoreflect.Name]bool{
+	"instructions":     true,
+	"description":      true,
+	"markdown_content": true,
+	"default_value":    true,
+	"value":            true,
+	"url":              true,
+	"icon_url":         true,
+	"args":             true,
+	"headers":          true,
+	"query_params":     true,
+}
+
+// redactedConfigKeys are the task_config keys whose values are redacted
+// entirely: request targets, bodies, prompts, data and credentials. Keys
+// describing control flow (in, when, until, then, export) are kept, so the
+// redacted manifest still reproduces the workflow structure.
+var redactedConfigKeys = map[string]bool{
+	"uri":            true,
+	"url":            true,
+	"endpoint":       true,
+	"token_url":      true,
+	"body":           true,
+	"headers":        true,
+	"query":          true,
+	"message":        true,
+	"prompt":         true,
+	"input":          true,
+	"data":           true,
+	"variables":      true,
+	"env":            true,
+	"authentication": true,
+	"client_id":      true,
+	"client_secret":  true,
+	"ca_cert":        true,
+	"proxy":          true,
+}
+
+// ExportRedacted returns the manifest as indented JSON with proprietary
+// content stripped, so it can be attached to a bug report. manifest must be
+// an *agentv1.AgentManifest or a *workflowv1.WorkflowManifest; it is not
+// modified.
+//
+// Instructions, descriptions, skill content, default values, URLs, headers,
+// request bodies, prompts and task inputs are replaced with Redacted. Names,
+// task kinds, control flow, numbers and booleans are kept, as are lists and
+// objects (with their keys), so the structure of the manifest is preserved.
+//
+// Example:
+//
+//	data, err := synth.ExportRedacted(manifests.WorkflowManifest)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	os.WriteFile("workflow-redacted.json", data, 0644)
+func ExportRedacted(manifest proto.Message) ([]byte, error) {
+	switch manifest.(type) {
+	case *agentv1.AgentManifest, *workflowv1.WorkflowManifest:
+	default:
+		return nil, fmt.Errorf("unsupported manifest type %T", manifest)
+	}
+
+	redacted := proto.Clone(manifest)
+	redactMessage(redacted.ProtoReflect())
+	return protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(redacted)
+}
+
+// redactMessage redacts the fields of m in place.
+func redactMessage(m protoreflect.Message) {
+	if s, ok := m.Interface().(*structpb.Struct); ok {
+		for key, v := range s.GetFields() {
+			redactValue(v, redactedConfigKeys[key])
+		}
+		return
+	}
+
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		redact := redactedFields[fd.Name()]
+		switch {
+		case fd.IsMap():
+			v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+				switch {
+				case fd.MapValue().Message() != nil:
+					redactMessage(mv.Message())
+				case fd.MapValue().Kind() == protoreflect.StringKind && (redact || isURL(mv.String())):
+					v.Map().Set(k, protoreflect.ValueOfString(Redacted))
+				}
+				return true
+			})
+		case fd.IsList():
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				switch {
+				case fd.Message() != nil:
+					redactMessage(list.Get(i).Message())
+				case fd.Kind() == protoreflect.StringKind && (redact || isURL(list.Get(i).String())):
+					list.Set(i, protoreflect.ValueOfString(Redacted))
+				}
+			}
+		case fd.Message() != nil:
+			redactMessage(v.Message())
+		case fd.Kind() == protoreflect.StringKind && (redact || isURL(v.String())):
+			m.Set(fd, protoreflect.ValueOfString(Redacted))
+		}
+		return true
+	})
+}
+
+// redactValue redacts the strings in a task_config value: all of them when
+// redact is set, otherwise only URLs and the values of redacted keys.
+func redactValue(v *structpb.Value, redact bool) {
+	switch kind := v.GetKind().(type) {
+	case *structpb.Value_StringValue:
+		if redact || isURL(kind.StringValue) {
+			kind.StringValue = Redacted
+		}
+	case *structpb.Value_StructValue:
+		for key, field := range kind.StructValue.GetFields() {
+			redactValue(field, redact || redactedConfigKeys[key])
+		}
+	case *structpb.Value_ListValue:
+		for _, item := range kind.ListValue.GetValues() {
+			redactValue(item, redact)
+		}
+	}
+}
+
+// isURL reports whether s contains an http(s) URL.
+func isURL(s string) bool {
+	return strings.Contains(s, "http://") || strings.Contains(s, "https://")
+}
@@ -0,0 +1,78 @@
+package synth
+
+import (
+	"strings"
+	"testing"
+
+	workflowv1 "buf.build/gen/go/leftbin/stigmer/protocolbuffers/go/ai/stigmer/agentic/workflow/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+// TestExportRedacted_Agent verifies agent content is stripped and names kept.
+func TestExportRedacted_Agent(t *testing.T) {
+	manifest := agentManifest(t, "Proprietary review checklist")
+
+	data, err := ExportRedacted(manifest)
+	if err != nil {
+		t.Fatalf("ExportRedacted() error = %v", err)
+	}
+	out := string(data)
+	for _, leaked := range []string{"Proprietary review checklist", "# Style"} {
+		if strings.Contains(out, leaked) {
+			t.Errorf("redacted manifest contains %q:\n%s", leaked, out)
+		}
+	}
+	for _, kept := range []string{"code-reviewer", "style-guide", Redacted} {
+		if !strings.Contains(out, kept) {
+			t.Errorf("redacted manifest lacks %q:\n%s", kept, out)
+		}
+	}
+	if manifest.GetAgents()[0].GetInstructions() != "Proprietary review checklist" {
+		t.Error("ExportRedacted() modified the manifest")
+	}
+}
+
+// TestExportRedacted_Workflow verifies request details are stripped while the
+// task structure and control flow are preserved.
+func TestExportRedacted_Workflow(t *testing.T) {
+	manifest := workflowManifest(t, "refund", func(wf *workflow.Workflow) {
+		fetch := wf.HttpPost("charge", "https://payments.internal.example.com/charge",
+			workflow.Header("Authorization", "Bearer sk_live_123"),
+			workflow.WithBody(map[string]any{"amount": 42, "card": "4242"}),
+		)
+		wf.SetVars("done", "status", fetch.Field("status"))
+	})
+
+	data, err := ExportRedacted(manifest)
+	if err != nil {
+		t.Fatalf("ExportRedacted() error = %v", err)
+	}
+	out := string(data)
+	for _, leaked := range []string{"payments.internal", "sk_live_123", "4242"} {
+		if strings.Contains(out, leaked) {
+			t.Errorf("redacted manifest contains %q:\n%s", leaked, out)
+		}
+	}
+
+	var redacted workflowv1.WorkflowManifest
+	if err := protojson.Unmarshal(data, &redacted); err != nil {
+		t.Fatalf("redacted manifest is not a WorkflowManifest: %v", err)
+	}
+	tasks := redacted.GetWorkflows()[0].GetSpec().GetTasks()
+	if len(tasks) != 2 || tasks[0].GetName() != "charge" || tasks[1].GetName() != "done" {
+		t.Fatalf("tasks = %v, want charge and done", tasks)
+	}
+	body := tasks[0].GetTaskConfig().GetFields()["body"].GetStructValue().GetFields()
+	if body["amount"].GetNumberValue() != 42 || body["card"].GetStringValue() != Redacted {
+		t.Errorf("body = %v, want numbers kept and strings redacted", body)
+	}
+}
+
+// TestExportRedacted_UnsupportedType verifies only manifests are accepted.
+func TestExportRedacted_UnsupportedType(t *testing.T) {
+	if _, err := ExportRedacted(&workflowv1.Workflow{}); err == nil {
+		t.Error("ExportRedacted() error = nil, want unsupported type error")
+	}
+}
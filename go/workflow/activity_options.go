@@ -0,0 +1,131 @@
+package workflow
+
+import (
+	"fmt"
+)
+
+// RetryPolicy controls how a failed activity is retried by Temporal.
+//
+// Zero values use the platform defaults (1s initial interval, 2.0 backoff,
+// 100x initial interval maximum, unlimited attempts).
+type RetryPolicy struct {
+	// InitialInterval is the delay before the first retry (e.g., "1s")
+	InitialInterval string `json:"initial_interval,omitempty"`
+
+	// BackoffCoefficient multiplies the interval after each retry (must be >= 1)
+	BackoffCoefficient float64 `json:"backoff_coefficient,omitempty"`
+
+	// MaximumInterval caps the delay between retries (e.g., "1m")
+	MaximumInterval string `json:"maximum_interval,omitempty"`
+
+	// MaximumAttempts limits the total number of attempts (0 = unlimited)
+	MaximumAttempts int32 `json:"maximum_attempts,omitempty"`
+
+	// NonRetryableErrorTypes lists error types that fail the activity immediately
+	NonRetryableErrorTypes []string `json:"non_retryable_error_types,omitempty"`
+}
+
+// WithTaskQueue sets the Temporal task queue the activity is dispatched to.
+// Accepts either a string or a StringRef from context.
+//
+// Example:
+//
+//	WithTaskQueue("data-processing")
+func WithTaskQueue(queue interface{}) CallActivityTaskOption {
+	return func(cfg *CallActivityTaskConfig) {
+		cfg.TaskQueue = toExpression(queue)
+	}
+}
+
+// WithStartToCloseTimeout sets the maximum duration of a single activity attempt.
+// Accepts seconds (int or IntRef), a time.Duration or DurationRef, duration helpers, or Ref types.
+//
+// Example:
+//
+//	WithStartToCloseTimeout(workflow.Minutes(5))
+func WithStartToCloseTimeout(duration interface{}) CallActivityTaskOption {
+	return func(cfg *CallActivityTaskConfig) {
+		cfg.StartToCloseTimeout = toDuration(duration)
+	}
+}
+
+// WithScheduleToCloseTimeout sets the maximum duration of the activity,
+// including all retries and time spent waiting in the task queue.
+// Accepts seconds (int or IntRef), a time.Duration or DurationRef, duration helpers, or Ref types.
+//
+// Example:
+//
+//	WithScheduleToCloseTimeout(workflow.Hours(1))
+func WithScheduleToCloseTimeout(duration interface{}) CallActivityTaskOption {
+	return func(cfg *CallActivityTaskConfig) {
+		cfg.ScheduleToCloseTimeout = toDuration(duration)
+	}
+}
+
+// WithHeartbeatTimeout sets the maximum time between activity heartbeats.
+// Long-running activities that stop heartbeating are considered failed.
+// Accepts seconds (int or IntRef), a time.Duration or DurationRef, duration helpers, or Ref types.
+//
+// Example:
+//
+//	WithHeartbeatTimeout(workflow.Seconds(30))
+func WithHeartbeatTimeout(duration interface{}) CallActivityTaskOption {
+	return func(cfg *CallActivityTaskConfig) {
+		cfg.HeartbeatTimeout = toDuration(duration)
+	}
+}
+
+// WithActivityRetry sets the retry policy for the activity.
+//
+// Example:
+//
+//	WithActivityRetry(workflow.RetryPolicy{
+//	    InitialInterval:        workflow.Seconds(1),
+//	    BackoffCoefficient:     2.0,
+//	    MaximumAttempts:        5,
+//	    NonRetryableErrorTypes: []string{"ValidationError"},
+//	})
+func WithActivityRetry(policy RetryPolicy) CallActivityTaskOption {
+	return func(cfg *CallActivityTaskConfig) {
+		cfg.RetryPolicy = &policy
+	}
+}
+
+// WithHttpRetry sets the retry policy for failed HTTP requests.
+// Overrides the workflow's DefaultRetry.
+func WithHttpRetry(policy RetryPolicy) HttpCallTaskOption {
+	return func(cfg *HttpCallTaskConfig) {
+		cfg.RetryPolicy = &policy
+	}
+}
+
+// WithGrpcRetry sets the retry policy for failed gRPC calls.
+// Overrides the workflow's DefaultRetry.
+func WithGrpcRetry(policy RetryPolicy) GrpcCallTaskOption {
+	return func(cfg *GrpcCallTaskConfig) {
+		cfg.RetryPolicy = &policy
+	}
+}
+
+// validateRetryPolicy validates retry policy bounds.
+func validateRetryPolicy(policy *RetryPolicy) error {
+	if policy.BackoffCoefficient != 0 && policy.BackoffCoefficient < 1 {
+		return NewValidationErrorWithCause(
+			"config.retry_policy.backoff_coefficient",
+			fmt.Sprintf("%g", policy.BackoffCoefficient),
+			"min",
+			"backoff coefficient must be >= 1",
+			ErrInvalidTaskConfig,
+		)
+	}
+	if policy.MaximumAttempts < 0 {
+		return NewValidationErrorWithCause(
+			"config.retry_policy.maximum_attempts",
+			fmt.Sprintf("%d", policy.MaximumAttempts),
+			"min",
+			"maximum attempts must be >= 0",
+			ErrInvalidTaskConfig,
+		)
+	}
+	return nil
+}
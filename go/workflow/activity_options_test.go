@@ -0,0 +1,60 @@
+package workflow
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestCallActivityOptions verifies task queue, timeout and retry options.
+func TestCallActivityOptions(t *testing.T) {
+	task := CallActivityTask("processData",
+		WithActivity("DataProcessor"),
+		WithTaskQueue("data-processing"),
+		WithStartToCloseTimeout(Minutes(5)),
+		WithScheduleToCloseTimeout(Hours(1)),
+		WithHeartbeatTimeout(Seconds(30)),
+		WithActivityRetry(RetryPolicy{
+			InitialInterval:        Seconds(1),
+			BackoffCoefficient:     2.0,
+			MaximumAttempts:        5,
+			NonRetryableErrorTypes: []string{"ValidationError"},
+		}),
+	)
+	cfg := task.Config.(*CallActivityTaskConfig)
+
+	if cfg.TaskQueue != "data-processing" {
+		t.Errorf("TaskQueue = %q", cfg.TaskQueue)
+	}
+	if cfg.StartToCloseTimeout != "5m" || cfg.ScheduleToCloseTimeout != "1h" || cfg.HeartbeatTimeout != "30s" {
+		t.Errorf("timeouts = %q, %q, %q", cfg.StartToCloseTimeout, cfg.ScheduleToCloseTimeout, cfg.HeartbeatTimeout)
+	}
+	if cfg.RetryPolicy == nil || cfg.RetryPolicy.MaximumAttempts != 5 {
+		t.Errorf("RetryPolicy = %+v", cfg.RetryPolicy)
+	}
+	if err := validateTaskConfig(task); err != nil {
+		t.Errorf("validateTaskConfig() error = %v", err)
+	}
+}
+
+// TestWithActivityRetry_Invalid verifies out-of-range retry settings are rejected.
+func TestWithActivityRetry_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy RetryPolicy
+	}{
+		{"backoff below 1", RetryPolicy{BackoffCoefficient: 0.5}},
+		{"negative attempts", RetryPolicy{MaximumAttempts: -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := CallActivityTask("processData",
+				WithActivity("DataProcessor"),
+				WithActivityRetry(tt.policy),
+			)
+			if err := validateTaskConfig(task); !errors.Is(err, ErrInvalidTaskConfig) {
+				t.Errorf("validateTaskConfig() error = %v, want ErrInvalidTaskConfig", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package workflow
+
+// Annotation keys synthesis uses to carry workflow settings that have no
+// dedicated field in the WorkflowSpec proto yet. Synthesis writes them,
+// FromProto reads them back and synth.CheckCompatibility checks them against
+// the target platform.
+const (
+	// AnnotationPrefix prefixes every workflow settings annotation; other
+	// annotations are left to the user
+	AnnotationPrefix = "workflow.stigmer.ai/"
+
+	// AnnotationTriggers holds the JSON-encoded trigger definitions (cron, interval, event)
+	AnnotationTriggers = AnnotationPrefix + "triggers"
+
+	// AnnotationTimeout holds the maximum execution time for the workflow
+	AnnotationTimeout = AnnotationPrefix + "timeout"
+
+	// AnnotationTaskDeadlines holds a JSON object mapping task names to their deadlines
+	AnnotationTaskDeadlines = AnnotationPrefix + "task-deadlines"
+
+	// AnnotationErrorPolicy holds the JSON-encoded workflow retry budget
+	AnnotationErrorPolicy = AnnotationPrefix + "error-policy"
+
+	// AnnotationIdempotencyKeys holds a JSON object mapping task names to their idempotency keys
+	AnnotationIdempotencyKeys = AnnotationPrefix + "idempotency-keys"
+
+	// AnnotationDisabled is set to "true" when the workflow must not be scheduled or triggered
+	AnnotationDisabled = AnnotationPrefix + "disabled"
+
+	// AnnotationDisabledReason holds the human-readable reason the workflow is disabled
+	AnnotationDisabledReason = AnnotationPrefix + "disabled-reason"
+
+	// AnnotationRollout holds the JSON-encoded canary rollout for this workflow version
+	AnnotationRollout = AnnotationPrefix + "rollout"
+
+	// AnnotationResources holds the JSON-encoded CPU and memory requested per execution
+	AnnotationResources = AnnotationPrefix + "resources"
+)
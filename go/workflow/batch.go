@@ -0,0 +1,98 @@
+package workflow
+
+import "fmt"
+
+// chunkRef is the Ref returned by Chunk.
+type chunkRef struct {
+	source string // jq expression for the collection (without ${} wrapper)
+	size   int
+}
+
+// Expression returns a JQ expression that splits the collection into batches.
+// Implements the Ref interface.
+func (r chunkRef) Expression() string {
+	return fmt.Sprintf("${ [range(0; (%s) | length; %d) as $i | (%s)[$i:$i + %d]] }",
+		r.source, r.size, r.source, r.size)
+}
+
+// Name returns a human-readable name for this reference.
+// Implements the Ref interface.
+func (r chunkRef) Name() string {
+	return fmt.Sprintf("chunk(%s, %d)", r.source, r.size)
+}
+
+// Chunk returns a reference to the collection split into batches of at most size
+// items, for use with ForTask. The last batch holds the remainder.
+// Sizes below 1 are treated as 1.
+//
+// Accepts a Ref (e.g., a TaskFieldRef or context variable) or an expression.
+//
+// Example:
+//
+//	workflow.ForTask("importUsers",
+//	    workflow.WithIn(workflow.Chunk(fetchTask.Field("users"), 100)),
+//	    workflow.WithDo(
+//	        workflow.SetTask("batch", workflow.SetVar("users", workflow.BatchItems())),
+//	    ),
+//	)
+func Chunk(collection interface{}, size int) Ref {
+	source := toExpression(collection)
+	if isExpression(source) {
+		source = expressionBody(source)
+	}
+	if size < 1 {
+		size = 1
+	}
+	return chunkRef{source: source, size: size}
+}
+
+// BatchItems returns the expression for the current batch inside a FOR task
+// iterating over Chunk.
+func BatchItems() string {
+	return "${ . }"
+}
+
+// BatchedHttpPostTask creates a FOR task that POSTs the collection in batches of
+// at most batchSize items, so bulk calls respect API payload limits.
+//
+// Each request is made by a nested HTTP_CALL task named "<name>-batch" with the
+// body {"items": [...batch]}. Pass WithBody with BatchItems() to use a different
+// body shape.
+//
+// Example:
+//
+//	task := workflow.BatchedHttpPostTask("importUsers",
+//	    "https://api.example.com/users/bulk",
+//	    fetchTask.Field("users"),
+//	    100,
+//	    workflow.Header("Authorization", token),
+//	)
+func BatchedHttpPostTask(name string, uri interface{}, collection interface{}, batchSize int, opts ...HttpCallTaskOption) *Task {
+	allOpts := []HttpCallTaskOption{
+		WithHTTPPost(),
+		WithURI(uri),
+		WithBody(map[string]any{"items": BatchItems()}),
+	}
+	allOpts = append(allOpts, opts...)
+
+	task := ForTask(name,
+		WithIn(Chunk(collection, batchSize)),
+		WithDo(HttpCallTask(name+"-batch", allOpts...)),
+	)
+	if ref, ok := collection.(taskOutputRef); ok {
+		task.dependsOnName(ref.TaskName())
+	}
+	return task
+}
+
+// BatchedHttpPost creates a batched HTTP POST task and adds it to the workflow.
+// See BatchedHttpPostTask.
+//
+// Example:
+//
+//	wf.BatchedHttpPost("importUsers", apiURL.Concat("/users/bulk"), fetchTask.Field("users"), 100)
+func (w *Workflow) BatchedHttpPost(name string, uri interface{}, collection interface{}, batchSize int, opts ...HttpCallTaskOption) *Task {
+	task := BatchedHttpPostTask(name, uri, collection, batchSize, opts...)
+	w.AddTask(task)
+	return task
+}
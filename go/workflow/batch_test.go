@@ -0,0 +1,66 @@
+package workflow
+
+import "testing"
+
+// TestChunk verifies the batching expression for refs and plain expressions.
+func TestChunk(t *testing.T) {
+	fetch := HttpCallTask("fetch", WithURI("https://api.example.com/users"))
+
+	tests := []struct {
+		name       string
+		collection interface{}
+		size       int
+		expected   string
+	}{
+		{
+			name:       "task field ref",
+			collection: fetch.Field("users"),
+			size:       100,
+			expected:   "${ [range(0; ($context.fetch.users) | length; 100) as $i | ($context.fetch.users)[$i:$i + 100]] }",
+		},
+		{
+			name:       "expression",
+			collection: "${ .items }",
+			size:       2,
+			expected:   "${ [range(0; (.items) | length; 2) as $i | (.items)[$i:$i + 2]] }",
+		},
+		{
+			name:       "size below 1",
+			collection: ".items",
+			size:       0,
+			expected:   "${ [range(0; (.items) | length; 1) as $i | (.items)[$i:$i + 1]] }",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Chunk(tt.collection, tt.size).Expression(); got != tt.expected {
+				t.Errorf("Chunk() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestBatchedHttpPostTask verifies the FOR task wraps a batched POST request.
+func TestBatchedHttpPostTask(t *testing.T) {
+	fetch := HttpCallTask("fetch", WithURI("https://api.example.com/users"))
+	task := BatchedHttpPostTask("importUsers", "https://api.example.com/users/bulk", fetch.Field("users"), 50)
+
+	cfg := task.Config.(*ForTaskConfig)
+	if cfg.In != Chunk(fetch.Field("users"), 50).Expression() {
+		t.Errorf("In = %q", cfg.In)
+	}
+	if len(cfg.Do) != 1 || cfg.Do[0].Name != "importUsers-batch" {
+		t.Fatalf("Do = %v, want [importUsers-batch]", cfg.Do)
+	}
+	post := cfg.Do[0].Config.(*HttpCallTaskConfig)
+	if post.Method != "POST" || post.Body["items"] != BatchItems() {
+		t.Errorf("batch request = %s %v", post.Method, post.Body)
+	}
+	if len(task.Dependencies) != 1 || task.Dependencies[0] != "fetch" {
+		t.Errorf("Dependencies = %v, want [fetch]", task.Dependencies)
+	}
+	if err := validateTaskConfig(task); err != nil {
+		t.Errorf("validateTaskConfig() error = %v", err)
+	}
+}
@@ -0,0 +1,296 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// WithBodyFromStruct sets the request body from a Go struct (JSON).
+//
+// The struct is converted to a map at synthesis time using its json tags
+// (name, omitempty, "-"). Fields holding Ref values (e.g., stigmer.StringRef,
+// TaskFieldRef) are preserved: known values are resolved, runtime values become
+// expressions.
+//
+// Example:
+//
+//	type CreateIssue struct {
+//	    Title  string            `json:"title"`
+//	    Body   workflow.Ref      `json:"body"`
+//	    Labels []string          `json:"labels,omitempty"`
+//	}
+//
+//	wf.HttpPost("createIssue", issuesURL,
+//	    workflow.WithBodyFromStruct(CreateIssue{
+//	        Title: "Nightly build failed",
+//	        Body:  buildTask.Field("log"),
+//	    }),
+//	)
+func WithBodyFromStruct(v any) HttpCallTaskOption {
+	return func(cfg *HttpCallTaskConfig) {
+		cfg.Body = nil
+		cfg.BodyStruct = v
+		cfg.BodyEncoding = BodyEncodingJSON
+		trackStructDependencies(v, cfg.ImplicitDependencies)
+	}
+}
+
+// WithGrpcBodyFromStruct sets the request body from a Go struct.
+// See WithBodyFromStruct for how the struct is converted.
+func WithGrpcBodyFromStruct(v any) GrpcCallTaskOption {
+	return func(cfg *GrpcCallTaskConfig) {
+		cfg.Body = nil
+		cfg.BodyStruct = v
+	}
+}
+
+// ResolvedBody returns the request body, converting the struct set by
+// WithBodyFromStruct if present.
+func (cfg *HttpCallTaskConfig) ResolvedBody() (map[string]any, error) {
+	if cfg.BodyStruct == nil {
+		return cfg.Body, nil
+	}
+	return structToBody(cfg.BodyStruct)
+}
+
+// ResolvedBody returns the request body, converting the struct set by
+// WithGrpcBodyFromStruct if present.
+func (cfg *GrpcCallTaskConfig) ResolvedBody() (map[string]any, error) {
+	if cfg.BodyStruct == nil {
+		return cfg.Body, nil
+	}
+	return structToBody(cfg.BodyStruct)
+}
+
+// structToBody converts a struct (or pointer to struct) to a body map.
+func structToBody(v any) (map[string]any, error) {
+	converted, err := structValueToAny(reflect.ValueOf(v))
+	if err != nil {
+		return nil, NewConversionErrorWithCause("body", "", err.Error(), ErrConversion)
+	}
+	body, ok := converted.(map[string]any)
+	if !ok {
+		return nil, NewConversionErrorWithCause(
+			"body",
+			"",
+			fmt.Sprintf("body must be a struct or map, got %T", v),
+			ErrConversion,
+		)
+	}
+	return body, nil
+}
+
+var (
+	refType           = reflect.TypeOf((*Ref)(nil)).Elem()
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// structValueToAny converts a reflected value to a protobuf-compatible value,
+// following encoding/json conventions for struct fields.
+func structValueToAny(v reflect.Value) (any, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+
+	// Refs are checked before dereferencing, since they are usually pointer types
+	if v.Type().Implements(refType) {
+		if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil() {
+			return nil, nil
+		}
+		return refToBodyValue(v.Interface().(Ref)), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return structValueToAny(v.Elem())
+
+	case reflect.Struct:
+		if v.Type().Implements(jsonMarshalerType) {
+			return marshalerToAny(v.Interface().(json.Marshaler))
+		}
+		result := make(map[string]any)
+		if err := structFieldsToMap(v, result); err != nil {
+			return nil, err
+		}
+		return result, nil
+
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("unsupported map key type %s", v.Type().Key())
+		}
+		result := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			val, err := structValueToAny(iter.Value())
+			if err != nil {
+				return nil, err
+			}
+			result[iter.Key().String()] = val
+		}
+		return result, nil
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil, nil
+		}
+		result := make([]any, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			val, err := structValueToAny(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			result[i] = val
+		}
+		return result, nil
+
+	case reflect.Func, reflect.Chan, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
+		return nil, fmt.Errorf("unsupported type %s", v.Type())
+
+	default:
+		return v.Interface(), nil
+	}
+}
+
+// structFieldsToMap adds the exported fields of a struct to result, using json
+// tag names and flattening embedded structs.
+func structFieldsToMap(v reflect.Value, result map[string]any) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+
+		name, omitEmpty, skip := parseJSONTag(field)
+		if skip {
+			continue
+		}
+
+		fv := v.Field(i)
+
+		// Embedded structs without a tag are flattened, like encoding/json
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			embedded := fv
+			if embedded.Kind() == reflect.Pointer {
+				if embedded.IsNil() {
+					continue
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err := structFieldsToMap(embedded, result); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if omitEmpty && fv.IsZero() {
+			continue
+		}
+		val, err := structValueToAny(fv)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		result[name] = val
+	}
+	return nil
+}
+
+// parseJSONTag returns the JSON name of a struct field, whether it has
+// omitempty, and whether it should be skipped.
+func parseJSONTag(field reflect.StructField) (name string, omitEmpty bool, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return name, omitEmpty, false
+}
+
+// refToBodyValue resolves a Ref to its known value when possible, or to its expression.
+func refToBodyValue(ref Ref) any {
+	switch r := ref.(type) {
+	case StringValue:
+		return r.Value()
+	case IntValue:
+		return r.Value()
+	case FloatValue:
+		return r.Value()
+	case BoolValue:
+		return r.Value()
+	default:
+		return ref.Expression()
+	}
+}
+
+// marshalerToAny converts a json.Marshaler to a generic JSON value.
+func marshalerToAny(m json.Marshaler) (any, error) {
+	data, err := m.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var result any
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// trackStructDependencies records tasks referenced by TaskFieldRef values in a struct.
+func trackStructDependencies(v any, deps map[string]bool) {
+	if deps == nil {
+		return
+	}
+	var walk func(reflect.Value)
+	walk = func(rv reflect.Value) {
+		if !rv.IsValid() {
+			return
+		}
+		if rv.CanInterface() {
+			if ref, ok := rv.Interface().(taskOutputRef); ok {
+				deps[ref.TaskName()] = true
+				return
+			}
+		}
+		switch rv.Kind() {
+		case reflect.Pointer, reflect.Interface:
+			if !rv.IsNil() {
+				walk(rv.Elem())
+			}
+		case reflect.Struct:
+			for i := 0; i < rv.NumField(); i++ {
+				if rv.Type().Field(i).IsExported() {
+					walk(rv.Field(i))
+				}
+			}
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < rv.Len(); i++ {
+				walk(rv.Index(i))
+			}
+		case reflect.Map:
+			iter := rv.MapRange()
+			for iter.Next() {
+				walk(iter.Value())
+			}
+		}
+	}
+	walk(reflect.ValueOf(v))
+}
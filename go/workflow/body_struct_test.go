@@ -0,0 +1,98 @@
+package workflow
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// knownStringRef is a Ref with a value known at synthesis time (like stigmer.StringRef).
+type knownStringRef struct {
+	name  string
+	value string
+}
+
+func (r *knownStringRef) Expression() string { return "${ $context." + r.name + " }" }
+func (r *knownStringRef) Name() string       { return r.name }
+func (r *knownStringRef) Value() string      { return r.value }
+
+// knownFloatRef is a float Ref with a value known at synthesis time (like stigmer.FloatRef).
+type knownFloatRef struct {
+	name  string
+	value float64
+}
+
+func (r *knownFloatRef) Expression() string { return "${ $context." + r.name + " }" }
+func (r *knownFloatRef) Name() string       { return r.name }
+func (r *knownFloatRef) Value() float64     { return r.value }
+
+type issueLabel struct {
+	Name string `json:"name"`
+}
+
+type issueMeta struct {
+	Source string `json:"source"`
+}
+
+type createIssue struct {
+	issueMeta
+	Title    string       `json:"title"`
+	Body     Ref          `json:"body"`
+	Assignee Ref          `json:"assignee,omitempty"`
+	Weight   Ref          `json:"weight,omitempty"`
+	Labels   []issueLabel `json:"labels,omitempty"`
+	Priority int          `json:"priority,omitempty"`
+	Internal string       `json:"-"`
+}
+
+// TestWithBodyFromStruct verifies structs are converted using json tags with refs preserved.
+func TestWithBodyFromStruct(t *testing.T) {
+	build := HttpCallTask("build", WithHTTPGet(), WithURI("https://ci.example.com/build"))
+
+	task := HttpCallTask("createIssue",
+		WithHTTPPost(),
+		WithURI("https://api.example.com/issues"),
+		WithBodyFromStruct(&createIssue{
+			issueMeta: issueMeta{Source: "ci"},
+			Title:     "Nightly build failed",
+			Body:      build.Field("log"),
+			Assignee:  &knownStringRef{name: "owner", value: "alice"},
+			Weight:    &knownFloatRef{name: "weight", value: 0.75},
+			Labels:    []issueLabel{{Name: "ci"}},
+			Internal:  "ignored",
+		}),
+	)
+	cfg := task.Config.(*HttpCallTaskConfig)
+
+	body, err := cfg.ResolvedBody()
+	if err != nil {
+		t.Fatalf("ResolvedBody() error = %v", err)
+	}
+
+	want := map[string]any{
+		"source":   "ci",
+		"title":    "Nightly build failed",
+		"body":     "${ $context.build.log }",
+		"assignee": "alice",
+		"weight":   0.75,
+		"labels":   []any{map[string]any{"name": "ci"}},
+	}
+	if !reflect.DeepEqual(body, want) {
+		t.Errorf("ResolvedBody() = %#v, want %#v", body, want)
+	}
+
+	if len(task.Dependencies) != 1 || task.Dependencies[0] != "build" {
+		t.Errorf("Dependencies = %v, want [build]", task.Dependencies)
+	}
+}
+
+// TestWithGrpcBodyFromStruct_Invalid verifies non-struct bodies return a conversion error.
+func TestWithGrpcBodyFromStruct_Invalid(t *testing.T) {
+	task := GrpcCallTask("call", WithService("UserService"), WithGrpcBodyFromStruct([]string{"a"}))
+	cfg := task.Config.(*GrpcCallTaskConfig)
+
+	_, err := cfg.ResolvedBody()
+	if !errors.Is(err, ErrConversion) {
+		t.Errorf("ResolvedBody() error = %v, want ErrConversion", err)
+	}
+}
@@ -0,0 +1,183 @@
+package workflow
+
+import (
+	"strings"
+)
+
+// Condition composition is built on a small AST so And(), Or() and Not() can
+// be nested in any order and given wrapped ("${ ... }") or bare conditions.
+// Each input is parsed into the tree below and rendered back with only the
+// parentheses required by operator precedence.
+
+// conditionPrecedence orders operators from loosest to tightest binding.
+type conditionPrecedence int
+
+const (
+	precPipe conditionPrecedence = iota // a | f
+	precOr                              // a || b
+	precAnd                             // a && b
+	precAtom                            // comparisons, paths, literals, !(...)
+)
+
+type conditionNode interface {
+	precedence() conditionPrecedence
+	render() string
+}
+
+type condAtom struct {
+	body string
+	prec conditionPrecedence
+}
+
+func (a condAtom) precedence() conditionPrecedence { return a.prec }
+func (a condAtom) render() string                  { return a.body }
+
+type condAnd struct{ terms []conditionNode }
+
+func (condAnd) precedence() conditionPrecedence { return precAnd }
+func (c condAnd) render() string                { return renderTerms(c.terms, precAnd, " && ") }
+
+type condOr struct{ terms []conditionNode }
+
+func (condOr) precedence() conditionPrecedence { return precOr }
+func (c condOr) render() string                { return renderTerms(c.terms, precOr, " || ") }
+
+type condNot struct{ inner conditionNode }
+
+func (condNot) precedence() conditionPrecedence { return precAtom }
+func (c condNot) render() string                { return "!(" + c.inner.render() + ")" }
+
+// renderTerms joins terms, parenthesizing any that bind looser than the operator.
+func renderTerms(terms []conditionNode, prec conditionPrecedence, sep string) string {
+	parts := make([]string, len(terms))
+	for i, term := range terms {
+		parts[i] = term.render()
+		if term.precedence() < prec {
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+	return strings.Join(parts, sep)
+}
+
+// parseCondition parses a condition (wrapped or bare) into a condition tree.
+func parseCondition(condition string) conditionNode {
+	return parseConditionBody(conditionBody(condition))
+}
+
+// parseConditionBody parses an expression body, splitting on top-level operators.
+func parseConditionBody(body string) conditionNode {
+	body = strings.TrimSpace(body)
+
+	if parts := splitTopLevel(body, "|"); len(parts) > 1 {
+		return condAtom{body: body, prec: precPipe}
+	}
+	if parts := splitTopLevel(body, "||", "or"); len(parts) > 1 {
+		or := condOr{}
+		for _, p := range parts {
+			or.terms = append(or.terms, parseConditionBody(p))
+		}
+		return or
+	}
+	if parts := splitTopLevel(body, "&&", "and"); len(parts) > 1 {
+		and := condAnd{}
+		for _, p := range parts {
+			and.terms = append(and.terms, parseConditionBody(p))
+		}
+		return and
+	}
+	if strings.HasPrefix(body, "!") {
+		if inner, ok := unwrapParens(strings.TrimSpace(body[1:])); ok {
+			return condNot{inner: parseConditionBody(inner)}
+		}
+	}
+	if inner, ok := unwrapParens(body); ok {
+		return parseConditionBody(inner)
+	}
+	return condAtom{body: body, prec: precAtom}
+}
+
+// unwrapParens returns the inside of s if one pair of parentheses encloses all of it.
+func unwrapParens(s string) (string, bool) {
+	if !strings.HasPrefix(s, "(") || !strings.HasSuffix(s, ")") {
+		return "", false
+	}
+	inner := s[1 : len(s)-1]
+	if !isBalanced(inner) {
+		return "", false
+	}
+	return inner, true
+}
+
+// splitTopLevel splits body on operators that appear outside strings and brackets.
+// Symbolic operators match exactly ("|" does not match "||"); word operators
+// ("and", "or") must be surrounded by non-identifier characters.
+func splitTopLevel(body string, ops ...string) []string {
+	var parts []string
+	depth := 0
+	var quote byte
+	start := 0
+
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		if quote != 0 {
+			switch c {
+			case '\\':
+				i++
+			case quote:
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '"', '\'':
+			quote = c
+			continue
+		case '(', '[', '{':
+			depth++
+			continue
+		case ')', ']', '}':
+			depth--
+			continue
+		}
+		if depth != 0 {
+			continue
+		}
+		for _, op := range ops {
+			if matchOperator(body, i, op) {
+				parts = append(parts, strings.TrimSpace(body[start:i]))
+				i += len(op) - 1
+				start = i + 1
+				break
+			}
+		}
+	}
+	return append(parts, strings.TrimSpace(body[start:]))
+}
+
+// matchOperator reports whether op occurs in body at position i as a standalone operator.
+func matchOperator(body string, i int, op string) bool {
+	if !strings.HasPrefix(body[i:], op) {
+		return false
+	}
+	before := byte(' ')
+	if i > 0 {
+		before = body[i-1]
+	}
+	after := byte(' ')
+	if i+len(op) < len(body) {
+		after = body[i+len(op)]
+	}
+
+	if isIdentByte(op[0]) {
+		return !isIdentByte(before) && before != '.' && before != '$' && !isIdentByte(after)
+	}
+	// "|" must not be part of "||"
+	if op == "|" {
+		return before != '|' && after != '|'
+	}
+	return true
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
@@ -0,0 +1,90 @@
+package workflow
+
+import "testing"
+
+// TestConditionComposition verifies And/Or/Not nest in any order with correct precedence.
+func TestConditionComposition(t *testing.T) {
+	status200 := Equals(Field("status"), Number(200))
+	status201 := Equals(Field("status"), Number(201))
+	ok := Equals(Field("type"), Literal("success"))
+
+	tests := []struct {
+		name     string
+		got      string
+		expected string
+	}{
+		{
+			name:     "or inside and is parenthesized",
+			got:      And(Or(status200, status201), ok),
+			expected: `${ (.status == 200 || .status == 201) && .type == "success" }`,
+		},
+		{
+			name:     "and inside or needs no parentheses",
+			got:      Or(And(status200, ok), status201),
+			expected: `${ .status == 200 && .type == "success" || .status == 201 }`,
+		},
+		{
+			name:     "nested and is flattened",
+			got:      And(And(status200, ok), status201),
+			expected: `${ .status == 200 && .type == "success" && .status == 201 }`,
+		},
+		{
+			name:     "not of or",
+			got:      Not(Or(status200, status201)),
+			expected: `${ !(.status == 200 || .status == 201) }`,
+		},
+		{
+			name:     "and of nots",
+			got:      And(Not(status200), Not(status201)),
+			expected: `${ !(.status == 200) && !(.status == 201) }`,
+		},
+		{
+			name:     "double negation",
+			got:      Not(Not(status200)),
+			expected: `${ !(!(.status == 200)) }`,
+		},
+		{
+			name:     "bare conditions",
+			got:      And(".status == 200", ".retries < 3 || .force"),
+			expected: `${ .status == 200 && (.retries < 3 || .force) }`,
+		},
+		{
+			name:     "redundant parentheses are dropped",
+			got:      Or("(.a)", "${ ((.b)) }"),
+			expected: `${ .a || .b }`,
+		},
+		{
+			name:     "pipe is parenthesized",
+			got:      And(".items | length > 0", ".ready"),
+			expected: `${ (.items | length > 0) && .ready }`,
+		},
+		{
+			name:     "operators inside strings are ignored",
+			got:      And(`.msg == "a || b"`, ".ok"),
+			expected: `${ .msg == "a || b" && .ok }`,
+		},
+		{
+			name:     "jq keywords",
+			got:      And(".a or .b", ".c"),
+			expected: `${ (.a || .b) && .c }`,
+		},
+		{
+			name:     "keyword prefix of field is not an operator",
+			got:      And(".order", ".android"),
+			expected: `${ .order && .android }`,
+		},
+		{
+			name:     "grouped terms stay grouped",
+			got:      And("(.a || .b) && (.c || .d)"),
+			expected: `${ (.a || .b) && (.c || .d) }`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.expected {
+				t.Errorf("got %q, want %q", tt.got, tt.expected)
+			}
+		})
+	}
+}
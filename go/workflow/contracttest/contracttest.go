@@ -0,0 +1,178 @@
+// Package contracttest verifies the contract between a parent workflow and the
+// child workflows it calls with RUN tasks.
+//
+// Teams that own child workflows declare their inputs and outputs with
+// workflow.WithInputs() and workflow.WithOutputs(). Teams that call them add a
+// unit test so that a breaking change on either side fails CI instead of
+// failing at runtime:
+//
+//	func TestOrderPipeline_CallsDataProcessor(t *testing.T) {
+//	    parent := buildOrderPipeline(stigmer.NewContext())
+//	    child := dataprocessor.Build(stigmer.NewContext())
+//
+//	    if err := contracttest.Verify(parent, child); err != nil {
+//	        t.Fatal(err)
+//	    }
+//	}
+package contracttest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+// ErrContractViolation is returned (wrapped) for every contract violation found.
+var ErrContractViolation = errors.New("workflow contract violation")
+
+// Verify checks every RUN task in parent that calls child:
+//
+//   - all required child inputs are provided
+//   - no inputs are passed that the child does not declare
+//   - static input values match the declared input type
+//   - every child output the parent reads (runTask.Field("x")) is declared
+//
+// Runtime expression values are not type-checked. All violations are returned
+// together, each wrapping ErrContractViolation.
+func Verify(parent, child *workflow.Workflow) error {
+	var runTasks []*workflow.Task
+	for task := range parent.AllTasks() {
+		if callsWorkflow(task, child) {
+			runTasks = append(runTasks, task)
+		}
+	}
+	if len(runTasks) == 0 {
+		return fmt.Errorf("%w: workflow %q has no RUN task calling %q",
+			ErrContractViolation, parent.Document.Name, child.Document.Name)
+	}
+
+	parentJSON, err := json.Marshal(parent)
+	if err != nil {
+		return fmt.Errorf("failed to inspect workflow %q: %w", parent.Document.Name, err)
+	}
+
+	var errs []error
+	for _, task := range runTasks {
+		cfg := task.Config.(*workflow.RunTaskConfig)
+		errs = append(errs, verifyInputs(task.Name, cfg.Input, child)...)
+		errs = append(errs, verifyOutputs(task.Name, string(parentJSON), child)...)
+	}
+	return errors.Join(errs...)
+}
+
+// callsWorkflow reports whether task is a RUN task targeting wf.
+func callsWorkflow(task *workflow.Task, wf *workflow.Workflow) bool {
+	cfg, ok := task.Config.(*workflow.RunTaskConfig)
+	if !ok || cfg.WorkflowName != wf.Document.Name {
+		return false
+	}
+	return cfg.WorkflowNamespace == "" || cfg.WorkflowNamespace == wf.Document.Namespace
+}
+
+// verifyInputs checks the RUN task input map against the child's declared inputs.
+func verifyInputs(taskName string, input map[string]any, child *workflow.Workflow) []error {
+	var errs []error
+
+	for _, p := range child.Inputs {
+		if _, ok := input[p.Name]; !ok && p.Required {
+			errs = append(errs, fmt.Errorf("%w: task %q: missing required input %q",
+				ErrContractViolation, taskName, p.Name))
+		}
+	}
+
+	keys := make([]string, 0, len(input))
+	for k := range input {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		p, ok := child.Input(key)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%w: task %q: input %q is not declared by workflow %q",
+				ErrContractViolation, taskName, key, child.Document.Name))
+			continue
+		}
+		if !matchesType(input[key], p.Type) {
+			errs = append(errs, fmt.Errorf("%w: task %q: input %q is %T, want %s",
+				ErrContractViolation, taskName, key, input[key], p.Type))
+		}
+	}
+
+	return errs
+}
+
+// verifyOutputs checks that every child output the parent reads is declared.
+func verifyOutputs(taskName, parentJSON string, child *workflow.Workflow) []error {
+	pattern := regexp.MustCompile(`\$context\.` + regexp.QuoteMeta(taskName) + `\.([A-Za-z_][A-Za-z0-9_]*)`)
+
+	var errs []error
+	seen := make(map[string]bool)
+	for _, m := range pattern.FindAllStringSubmatch(parentJSON, -1) {
+		output := m[1]
+		if seen[output] {
+			continue
+		}
+		seen[output] = true
+		if !child.HasOutput(output) {
+			errs = append(errs, fmt.Errorf("%w: task %q: output %q is not declared by workflow %q",
+				ErrContractViolation, taskName, output, child.Document.Name))
+		}
+	}
+	return errs
+}
+
+// matchesType reports whether a static value matches the declared type.
+// Runtime expressions match any type.
+func matchesType(value any, paramType workflow.ParamType) bool {
+	if s, ok := value.(string); ok && strings.HasPrefix(s, "${") && strings.HasSuffix(s, "}") {
+		return true
+	}
+	if ref, ok := value.(workflow.Ref); ok {
+		return matchesType(ref.Expression(), paramType)
+	}
+
+	if paramType == workflow.ParamTypeAny {
+		return true
+	}
+	if value == nil {
+		return false
+	}
+
+	v := reflect.ValueOf(value)
+	switch paramType {
+	case workflow.ParamTypeString:
+		return v.Kind() == reflect.String
+	case workflow.ParamTypeBoolean:
+		return v.Kind() == reflect.Bool
+	case workflow.ParamTypeInteger:
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return true
+		case reflect.Float32, reflect.Float64:
+			return v.Float() == math.Trunc(v.Float())
+		}
+		return false
+	case workflow.ParamTypeNumber:
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			return true
+		}
+		return false
+	case workflow.ParamTypeObject:
+		return v.Kind() == reflect.Map || v.Kind() == reflect.Struct
+	case workflow.ParamTypeArray:
+		return v.Kind() == reflect.Slice || v.Kind() == reflect.Array
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package contracttest
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/stigmer"
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+func newChild(t *testing.T) *workflow.Workflow {
+	t.Helper()
+	child, err := workflow.New(stigmer.NewContext(),
+		workflow.WithNamespace("data"),
+		workflow.WithName("data-processor"),
+		workflow.WithInputs(
+			workflow.RequiredInput("datasetId", workflow.ParamTypeString),
+			workflow.OptionalInput("limit", workflow.ParamTypeInteger),
+		),
+		workflow.WithOutputs("rowCount"),
+	)
+	if err != nil {
+		t.Fatalf("workflow.New() error = %v", err)
+	}
+	return child
+}
+
+func newParent(t *testing.T, child *workflow.Workflow, input map[string]any, consumed string) *workflow.Workflow {
+	t.Helper()
+	parent, err := workflow.New(stigmer.NewContext(),
+		workflow.WithNamespace("orders"),
+		workflow.WithName("order-pipeline"),
+	)
+	if err != nil {
+		t.Fatalf("workflow.New() error = %v", err)
+	}
+
+	run := workflow.RunTask("process",
+		workflow.WithWorkflowRef(child),
+		workflow.WithWorkflowInput(input),
+	)
+	parent.AddTask(run)
+	parent.AddTask(workflow.SetTask("summarize", workflow.SetVar("rows", run.Field(consumed))))
+	return parent
+}
+
+// TestVerify_Satisfied verifies a parent that honours the contract passes.
+func TestVerify_Satisfied(t *testing.T) {
+	child := newChild(t)
+	parent := newParent(t, child, map[string]any{
+		"datasetId": "${ $context.datasetId }",
+		"limit":     100,
+	}, "rowCount")
+
+	if err := Verify(parent, child); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+}
+
+// TestVerify_Violations verifies each kind of contract violation is reported.
+func TestVerify_Violations(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    map[string]any
+		consumed string
+		wantMsg  string
+	}{
+		{
+			name:     "missing required input",
+			input:    map[string]any{"limit": 10},
+			consumed: "rowCount",
+			wantMsg:  `missing required input "datasetId"`,
+		},
+		{
+			name:     "undeclared input",
+			input:    map[string]any{"datasetId": "ds-1", "dataset": "ds-1"},
+			consumed: "rowCount",
+			wantMsg:  `input "dataset" is not declared`,
+		},
+		{
+			name:     "wrong static type",
+			input:    map[string]any{"datasetId": "ds-1", "limit": "ten"},
+			consumed: "rowCount",
+			wantMsg:  `input "limit" is string, want integer`,
+		},
+		{
+			name:     "undeclared output",
+			input:    map[string]any{"datasetId": "ds-1"},
+			consumed: "rows",
+			wantMsg:  `output "rows" is not declared`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			child := newChild(t)
+			parent := newParent(t, child, tt.input, tt.consumed)
+
+			err := Verify(parent, child)
+			if !errors.Is(err, ErrContractViolation) {
+				t.Fatalf("Verify() error = %v, want ErrContractViolation", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("Verify() error = %q, want it to contain %q", err, tt.wantMsg)
+			}
+		})
+	}
+}
+
+// TestVerify_NoRunTask verifies a parent that never calls the child is reported.
+func TestVerify_NoRunTask(t *testing.T) {
+	child := newChild(t)
+	parent, _ := workflow.New(stigmer.NewContext(),
+		workflow.WithNamespace("orders"),
+		workflow.WithName("order-pipeline"),
+	)
+
+	if err := Verify(parent, child); !errors.Is(err, ErrContractViolation) {
+		t.Errorf("Verify() error = %v, want ErrContractViolation", err)
+	}
+}
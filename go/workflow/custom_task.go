@@ -0,0 +1,170 @@
+package workflow
+
+import (
+	"fmt"
+	"sync"
+)
+
+// CustomConfig is embedded in organization-specific config structs so they
+// implement TaskConfig.
+//
+// Example:
+//
+//	type JiraTicketConfig struct {
+//	    workflow.CustomConfig
+//	    Project string
+//	    Summary string
+//	}
+type CustomConfig struct{}
+
+func (CustomConfig) isTaskConfig() {}
+
+// TaskKindConverter lowers a task of a custom kind to a task of a built-in kind
+// that the workflow engine can execute (e.g., HTTP_CALL or CALL_ACTIVITY).
+//
+// The returned task's name, export, flow and dependencies are taken from the
+// original task.
+type TaskKindConverter func(task *Task) (*Task, error)
+
+var (
+	customTaskKindsMu sync.RWMutex
+	customTaskKinds   = make(map[TaskKind]TaskKindConverter)
+)
+
+// RegisterTaskKind registers an organization-specific task kind.
+//
+// Tasks of the registered kind are validated and synthesized by lowering them
+// to a built-in kind with the converter. Registering a built-in kind or a kind
+// that is already registered returns an error.
+//
+// Typically called from an init() function in the package that defines the kind.
+//
+// Example:
+//
+//	const TaskKindJiraTicket workflow.TaskKind = "ACME_JIRA_TICKET"
+//
+//	func init() {
+//	    workflow.RegisterTaskKind(TaskKindJiraTicket, func(task *workflow.Task) (*workflow.Task, error) {
+//	        cfg := task.Config.(*JiraTicketConfig)
+//	        return workflow.HttpCallTask(task.Name,
+//	            workflow.WithHTTPPost(),
+//	            workflow.WithURI("https://jira.acme.com/rest/api/2/issue"),
+//	            workflow.WithBody(map[string]any{
+//	                "fields": map[string]any{"project": cfg.Project, "summary": cfg.Summary},
+//	            }),
+//	        ), nil
+//	    })
+//	}
+func RegisterTaskKind(kind TaskKind, converter TaskKindConverter) error {
+	if kind == "" {
+		return NewValidationErrorWithCause("kind", "", "required", "task kind is required", ErrInvalidTaskKind)
+	}
+	if converter == nil {
+		return NewValidationErrorWithCause("converter", string(kind), "required", "task kind converter is required", ErrInvalidTaskKind)
+	}
+	if isBuiltinTaskKind(kind) {
+		return NewValidationErrorWithCause(
+			"kind",
+			string(kind),
+			"unique",
+			fmt.Sprintf("cannot register built-in task kind: %q", kind),
+			ErrInvalidTaskKind,
+		)
+	}
+
+	customTaskKindsMu.Lock()
+	defer customTaskKindsMu.Unlock()
+
+	if _, exists := customTaskKinds[kind]; exists {
+		return NewValidationErrorWithCause(
+			"kind",
+			string(kind),
+			"unique",
+			fmt.Sprintf("task kind already registered: %q", kind),
+			ErrInvalidTaskKind,
+		)
+	}
+	customTaskKinds[kind] = converter
+	return nil
+}
+
+// CustomTask creates a task of a registered custom kind.
+//
+// Example:
+//
+//	task := workflow.CustomTask("openTicket", TaskKindJiraTicket, &JiraTicketConfig{
+//	    Project: "OPS",
+//	    Summary: "Nightly build failed",
+//	})
+func CustomTask(name string, kind TaskKind, config TaskConfig) *Task {
+	return &Task{
+		Name:         name,
+		Kind:         kind,
+		Config:       config,
+		Dependencies: []string{},
+	}
+}
+
+// LowerTask converts a task of a custom kind to its built-in equivalent using the
+// registered converter. Tasks of built-in kinds are returned unchanged.
+//
+// This is used during validation and synthesis.
+func LowerTask(task *Task) (*Task, error) {
+	customTaskKindsMu.RLock()
+	converter, ok := customTaskKinds[task.Kind]
+	customTaskKindsMu.RUnlock()
+	if !ok {
+		return task, nil
+	}
+
+	lowered, err := converter(task)
+	if err != nil {
+		return nil, NewConversionErrorWithCause(string(task.Kind), task.Name, err.Error(), err)
+	}
+	if lowered == nil || !isBuiltinTaskKind(lowered.Kind) {
+		return nil, NewConversionErrorWithCause(
+			string(task.Kind),
+			task.Name,
+			"converter must return a task of a built-in kind",
+			ErrConversion,
+		)
+	}
+
+	result := *lowered
+	result.Name = task.Name
+	result.ExportAs = task.ExportAs
+	result.ContextKey = task.ContextKey
+	result.ExportedFields = task.ExportedFields
+	result.ThenTask = task.ThenTask
+	result.Dependencies = append(append([]string{}, task.Dependencies...), lowered.Dependencies...)
+	return &result, nil
+}
+
+// isCustomTaskKind reports whether kind was registered with RegisterTaskKind.
+func isCustomTaskKind(kind TaskKind) bool {
+	customTaskKindsMu.RLock()
+	defer customTaskKindsMu.RUnlock()
+	_, ok := customTaskKinds[kind]
+	return ok
+}
+
+// isBuiltinTaskKind reports whether kind is one of the SDK's built-in task kinds.
+func isBuiltinTaskKind(kind TaskKind) bool {
+	switch kind {
+	case TaskKindSet,
+		TaskKindHttpCall,
+		TaskKindGrpcCall,
+		TaskKindSwitch,
+		TaskKindFor,
+		TaskKindFork,
+		TaskKindTry,
+		TaskKindListen,
+		TaskKindWait,
+		TaskKindCallActivity,
+		TaskKindRaise,
+		TaskKindRun,
+		TaskKindAgentCall:
+		return true
+	}
+	return false
+}
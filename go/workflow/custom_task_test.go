@@ -0,0 +1,80 @@
+package workflow
+
+import (
+	"errors"
+	"testing"
+)
+
+type ticketConfig struct {
+	CustomConfig
+	Project string
+	Summary string
+}
+
+const testTaskKindTicket TaskKind = "TEST_TICKET"
+
+func init() {
+	if err := RegisterTaskKind(testTaskKindTicket, func(task *Task) (*Task, error) {
+		cfg := task.Config.(*ticketConfig)
+		if cfg.Project == "" {
+			return nil, errors.New("project is required")
+		}
+		return HttpCallTask("ignored",
+			WithHTTPPost(),
+			WithURI("https://tickets.example.com/issues"),
+			WithBody(map[string]any{"project": cfg.Project, "summary": cfg.Summary}),
+		), nil
+	}); err != nil {
+		panic(err)
+	}
+}
+
+// TestRegisterTaskKind_Errors verifies built-in and duplicate kinds cannot be registered.
+func TestRegisterTaskKind_Errors(t *testing.T) {
+	noop := func(task *Task) (*Task, error) { return task, nil }
+
+	if err := RegisterTaskKind(TaskKindHttpCall, noop); !errors.Is(err, ErrInvalidTaskKind) {
+		t.Errorf("RegisterTaskKind(built-in) error = %v, want ErrInvalidTaskKind", err)
+	}
+	if err := RegisterTaskKind(testTaskKindTicket, noop); !errors.Is(err, ErrInvalidTaskKind) {
+		t.Errorf("RegisterTaskKind(duplicate) error = %v, want ErrInvalidTaskKind", err)
+	}
+}
+
+// TestLowerTask verifies custom tasks are lowered while keeping identity fields.
+func TestLowerTask(t *testing.T) {
+	task := CustomTask("openTicket", testTaskKindTicket, &ticketConfig{Project: "OPS", Summary: "Build failed"})
+	task.ExportAs = "${.}"
+
+	lowered, err := LowerTask(task)
+	if err != nil {
+		t.Fatalf("LowerTask() error = %v", err)
+	}
+	if lowered.Kind != TaskKindHttpCall {
+		t.Errorf("Kind = %q, want %q", lowered.Kind, TaskKindHttpCall)
+	}
+	if lowered.Name != "openTicket" || lowered.ExportAs != "${.}" {
+		t.Errorf("lowered task = (%q, %q), want original name and export", lowered.Name, lowered.ExportAs)
+	}
+
+	builtin := SetTask("init", SetVar("x", "1"))
+	if got, _ := LowerTask(builtin); got != builtin {
+		t.Error("LowerTask() should return built-in tasks unchanged")
+	}
+}
+
+// TestValidateCustomTask verifies custom tasks are validated through their converter.
+func TestValidateCustomTask(t *testing.T) {
+	valid := CustomTask("openTicket", testTaskKindTicket, &ticketConfig{Project: "OPS"})
+	if err := validateTaskKind(valid.Kind); err != nil {
+		t.Errorf("validateTaskKind() error = %v", err)
+	}
+	if err := validateTaskConfig(valid); err != nil {
+		t.Errorf("validateTaskConfig() error = %v", err)
+	}
+
+	invalid := CustomTask("openTicket", testTaskKindTicket, &ticketConfig{})
+	if err := validateTaskConfig(invalid); err == nil {
+		t.Error("validateTaskConfig() expected error from converter")
+	}
+}
@@ -0,0 +1,59 @@
+package workflow
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// deadlineRegex matches the duration strings produced by Seconds(), Minutes(),
+// Hours() and Days(), including compound forms like "1h30m".
+var deadlineRegex = regexp.MustCompile(`^(\d+(ms|s|m|h|d))+$`)
+
+// WithWorkflowTimeout sets the maximum execution time for the whole workflow.
+// Accepts seconds (int or IntRef), a time.Duration or DurationRef, duration helpers, or Ref types.
+//
+// This bounds the workflow run; use WithTimeout for HTTP request timeouts and
+// Task.WithDeadline for individual tasks.
+//
+// Example:
+//
+//	workflow.New(ctx,
+//	    workflow.WithNamespace("billing"),
+//	    workflow.WithName("monthly-invoices"),
+//	    workflow.WithWorkflowTimeout(workflow.Hours(2)),
+//	)
+func WithWorkflowTimeout(duration interface{}) Option {
+	return func(w *Workflow) error {
+		w.Timeout = toDuration(duration)
+		return nil
+	}
+}
+
+// WithDeadline sets the maximum execution time for this task.
+// Works with every task kind. Accepts seconds (int or IntRef), a time.Duration or DurationRef, duration helpers, or Ref types.
+//
+// Example:
+//
+//	wf.HttpGet("fetchReport", reportURL).WithDeadline(workflow.Minutes(5))
+func (t *Task) WithDeadline(duration interface{}) *Task {
+	t.Deadline = toDuration(duration)
+	return t
+}
+
+// validateDeadline checks a workflow timeout or task deadline.
+// Expressions are resolved at runtime and are not checked.
+func validateDeadline(field, value string) error {
+	if value == "" || isExpression(value) {
+		return nil
+	}
+	if !deadlineRegex.MatchString(value) {
+		return NewValidationErrorWithCause(
+			field,
+			value,
+			"format",
+			fmt.Sprintf("%s must be a duration like \"30s\", \"5m\" or \"1h30m\", got %q", field, value),
+			ErrInvalidDeadline,
+		)
+	}
+	return nil
+}
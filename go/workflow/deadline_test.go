@@ -0,0 +1,50 @@
+package workflow_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/stigmer"
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+func TestWithWorkflowTimeout(t *testing.T) {
+	wf, err := workflow.New(stigmer.NewContext(),
+		workflow.WithNamespace("billing"),
+		workflow.WithName("monthly-invoices"),
+		workflow.WithWorkflowTimeout(workflow.Hours(2)),
+		workflow.WithTask(workflow.SetTask("init", workflow.SetVar("x", "1")).WithDeadline("1m30s")),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if wf.Timeout != "2h" {
+		t.Errorf("Timeout = %q, want %q", wf.Timeout, "2h")
+	}
+	if got := wf.Tasks[0].Deadline; got != "1m30s" {
+		t.Errorf("Deadline = %q, want %q", got, "1m30s")
+	}
+}
+
+func TestWithWorkflowTimeout_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  workflow.Option
+	}{
+		{"workflow timeout", workflow.WithWorkflowTimeout("two hours")},
+		{"task deadline", workflow.WithTask(workflow.SetTask("init", workflow.SetVar("x", "1")).WithDeadline("5"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := workflow.New(stigmer.NewContext(),
+				workflow.WithNamespace("billing"),
+				workflow.WithName("monthly-invoices"),
+				tt.opt,
+			)
+			if !errors.Is(err, workflow.ErrInvalidDeadline) {
+				t.Errorf("New() error = %v, want ErrInvalidDeadline", err)
+			}
+		})
+	}
+}
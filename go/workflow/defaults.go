@@ -0,0 +1,174 @@
+package workflow
+
+import "fmt"
+
+// TaskDefaults holds settings that HTTP_CALL and GRPC_CALL tasks inherit
+// unless the task sets them itself.
+type TaskDefaults struct {
+	// TimeoutSeconds is the request timeout (HTTP timeout, gRPC deadline)
+	TimeoutSeconds int32 `json:"timeout_seconds,omitempty"`
+
+	// RetryPolicy retries failed requests
+	RetryPolicy *RetryPolicy `json:"retry_policy,omitempty"`
+
+	// Headers are added to HTTP headers and gRPC metadata
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// timeoutErr records a DefaultTimeout value that could not be converted, reported by validation
+	timeoutErr error
+}
+
+// DefaultOption is a functional option for configuring TaskDefaults.
+type DefaultOption func(*TaskDefaults)
+
+// WithDefaults sets workflow-level defaults for HTTP and gRPC tasks.
+//
+// Defaults are applied during synthesis and never override a value set on the
+// task itself: WithTimeout/WithGrpcDeadline, WithHttpRetry/WithGrpcRetry, and
+// headers or metadata with the same key all take precedence.
+//
+// Example:
+//
+//	workflow.New(ctx,
+//	    workflow.WithNamespace("billing"),
+//	    workflow.WithName("sync-invoices"),
+//	    workflow.WithDefaults(
+//	        workflow.DefaultTimeout(30),
+//	        workflow.DefaultRetry(workflow.RetryPolicy{MaximumAttempts: 3}),
+//	        workflow.DefaultHeaders(map[string]string{"Authorization": "Bearer ${ .secrets.API_TOKEN }"}),
+//	    ),
+//	)
+func WithDefaults(opts ...DefaultOption) Option {
+	return func(w *Workflow) error {
+		if w.Defaults == nil {
+			w.Defaults = &TaskDefaults{Headers: make(map[string]string)}
+		}
+		for _, opt := range opts {
+			opt(w.Defaults)
+		}
+		return nil
+	}
+}
+
+// DefaultTimeout sets the default request timeout.
+// Accepts the same values as WithTimeout.
+func DefaultTimeout(seconds interface{}) DefaultOption {
+	return func(d *TaskDefaults) {
+		d.TimeoutSeconds, d.timeoutErr = toSeconds(seconds)
+	}
+}
+
+// DefaultRetry sets the default retry policy.
+func DefaultRetry(policy RetryPolicy) DefaultOption {
+	return func(d *TaskDefaults) {
+		d.RetryPolicy = &policy
+	}
+}
+
+// DefaultHeaders adds default HTTP headers (sent as metadata on gRPC calls).
+func DefaultHeaders(headers map[string]string) DefaultOption {
+	return func(d *TaskDefaults) {
+		for k, v := range headers {
+			d.Headers[k] = v
+		}
+	}
+}
+
+// ApplyDefaults returns a copy of the workflow whose HTTP_CALL and GRPC_CALL
+// tasks, including nested tasks, have the workflow's defaults filled in.
+// Values set on a task are kept.
+//
+// This is used during synthesis. The given workflow is not modified, so it can
+// be synthesized again (or concurrently, from contexts restored from the same
+// Snapshot).
+func ApplyDefaults(wf *Workflow) (*Workflow, error) {
+	d := wf.Defaults
+	if d == nil {
+		return wf, nil
+	}
+
+	copied := *wf
+	copied.Tasks = copyTasks(wf.Tasks)
+	copied.Finalizers = copyTasks(wf.Finalizers)
+
+	err := Walk(&copied, func(task *Task) error {
+		switch cfg := task.Config.(type) {
+		case *HttpCallTaskConfig:
+			if d.TimeoutSeconds > 0 && !cfg.timeoutSet {
+				cfg.TimeoutSeconds = d.TimeoutSeconds
+			}
+			if cfg.RetryPolicy == nil && d.RetryPolicy != nil {
+				policy := *d.RetryPolicy
+				cfg.RetryPolicy = &policy
+			}
+			if len(d.Headers) > 0 && cfg.Headers == nil {
+				cfg.Headers = make(map[string]string)
+			}
+			mergeDefaults(cfg.Headers, d.Headers)
+		case *GrpcCallTaskConfig:
+			if d.TimeoutSeconds > 0 && cfg.Deadline == "" {
+				cfg.Deadline = Seconds(int(d.TimeoutSeconds))
+			}
+			if cfg.RetryPolicy == nil && d.RetryPolicy != nil {
+				policy := *d.RetryPolicy
+				cfg.RetryPolicy = &policy
+			}
+			if len(d.Headers) > 0 && cfg.Metadata == nil {
+				cfg.Metadata = make(map[string]string)
+			}
+			mergeDefaults(cfg.Metadata, d.Headers)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &copied, nil
+}
+
+// copyTasks deep-copies tasks so their configs can be changed without
+// affecting the originals.
+func copyTasks(tasks []*Task) []*Task {
+	if tasks == nil {
+		return nil
+	}
+	result := make([]*Task, len(tasks))
+	for i, task := range tasks {
+		result[i] = copyTask(task, &taskCopier{replace: func(s string) string { return s }})
+	}
+	return result
+}
+
+// mergeDefaults copies entries from defaults into dst for keys dst doesn't have.
+func mergeDefaults(dst, defaults map[string]string) {
+	for k, v := range defaults {
+		if _, ok := dst[k]; !ok {
+			dst[k] = v
+		}
+	}
+}
+
+// validateTaskDefaults validates workflow-level task defaults.
+func validateTaskDefaults(d *TaskDefaults) error {
+	if d == nil {
+		return nil
+	}
+	if d.timeoutErr != nil {
+		return NewValidationErrorWithCause("defaults.timeout_seconds", "", "format", d.timeoutErr.Error(), ErrInvalidTaskConfig)
+	}
+	if d.TimeoutSeconds < 0 || d.TimeoutSeconds > 300 {
+		return NewValidationErrorWithCause(
+			"defaults.timeout_seconds",
+			fmt.Sprintf("%d", d.TimeoutSeconds),
+			"range",
+			"default timeout must be between 0 and 300 seconds",
+			ErrInvalidTaskConfig,
+		)
+	}
+	if d.RetryPolicy != nil {
+		if err := validateRetryPolicy(d.RetryPolicy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
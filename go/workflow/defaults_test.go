@@ -0,0 +1,76 @@
+package workflow
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestApplyDefaults verifies defaults fill unset HTTP and gRPC settings without overriding tasks.
+func TestApplyDefaults(t *testing.T) {
+	wf := &Workflow{}
+	if err := WithDefaults(
+		DefaultTimeout(10),
+		DefaultRetry(RetryPolicy{MaximumAttempts: 3}),
+		DefaultHeaders(map[string]string{"Authorization": "Bearer default", "X-Team": "billing"}),
+	)(wf); err != nil {
+		t.Fatalf("WithDefaults() error = %v", err)
+	}
+
+	inherit := HttpCallTask("inherit", WithHTTPGet(), WithURI("https://api.example.com/a"))
+	override := HttpCallTask("override",
+		WithHTTPGet(),
+		WithURI("https://api.example.com/b"),
+		WithTimeout(30),
+		WithHttpRetry(RetryPolicy{MaximumAttempts: 1}),
+		WithHeader("Authorization", "Bearer task"),
+	)
+	grpc := GrpcCallTask("lookup", WithService("billing.Invoices"), WithGrpcMethod("Get"))
+	nested := TryTask("guard", WithTry(HttpCallTask("nested", WithHTTPGet(), WithURI("https://api.example.com/c"))))
+	wf.Tasks = []*Task{inherit, override, grpc, nested}
+
+	got, err := ApplyDefaults(wf)
+	if err != nil {
+		t.Fatalf("ApplyDefaults() error = %v", err)
+	}
+
+	// The original tasks are left untouched
+	if cfg := inherit.Config.(*HttpCallTaskConfig); cfg.RetryPolicy != nil || len(cfg.Headers) != 0 {
+		t.Errorf("ApplyDefaults() modified the original task: %+v", cfg)
+	}
+	inherit, override, grpc, nested = got.Tasks[0], got.Tasks[1], got.Tasks[2], got.Tasks[3]
+
+	cfg := inherit.Config.(*HttpCallTaskConfig)
+	if cfg.TimeoutSeconds != 10 || cfg.RetryPolicy == nil || cfg.RetryPolicy.MaximumAttempts != 3 {
+		t.Errorf("inherit timeout = %d, retry = %+v", cfg.TimeoutSeconds, cfg.RetryPolicy)
+	}
+	if cfg.Headers["Authorization"] != "Bearer default" || cfg.Headers["X-Team"] != "billing" {
+		t.Errorf("inherit headers = %v", cfg.Headers)
+	}
+
+	cfg = override.Config.(*HttpCallTaskConfig)
+	if cfg.TimeoutSeconds != 30 || cfg.RetryPolicy.MaximumAttempts != 1 {
+		t.Errorf("override timeout = %d, retry = %+v", cfg.TimeoutSeconds, cfg.RetryPolicy)
+	}
+	if cfg.Headers["Authorization"] != "Bearer task" || cfg.Headers["X-Team"] != "billing" {
+		t.Errorf("override headers = %v", cfg.Headers)
+	}
+
+	gcfg := grpc.Config.(*GrpcCallTaskConfig)
+	if gcfg.Deadline != "10s" || gcfg.Metadata["X-Team"] != "billing" || gcfg.RetryPolicy == nil {
+		t.Errorf("grpc deadline = %q, metadata = %v, retry = %+v", gcfg.Deadline, gcfg.Metadata, gcfg.RetryPolicy)
+	}
+
+	inner := nested.Config.(*TryTaskConfig).Tasks[0].Config.(*HttpCallTaskConfig)
+	if inner.TimeoutSeconds != 10 {
+		t.Errorf("nested timeout = %d, want 10", inner.TimeoutSeconds)
+	}
+}
+
+// TestWithDefaults_Invalid verifies out-of-range defaults are rejected.
+func TestWithDefaults_Invalid(t *testing.T) {
+	wf := &Workflow{}
+	_ = WithDefaults(DefaultTimeout(600))(wf)
+	if err := validateTaskDefaults(wf.Defaults); !errors.Is(err, ErrInvalidTaskConfig) {
+		t.Errorf("validateTaskDefaults() error = %v, want ErrInvalidTaskConfig", err)
+	}
+}
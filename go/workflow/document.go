@@ -1,34 +1,37 @@
 package workflow
 
-import "regexp"
+import (
+	"regexp"
+	"slices"
+)
 
 // Document represents workflow metadata.
 // Maps to the `document:` block in Zigflow DSL YAML.
 type Document struct {
-	// DSL version (semver). Must be "1.0.0" for current Zigflow.
-	DSL string
+	// DSL version (semver), "1.0.0" unless set with WithDSLVersion.
+	DSL string `json:"dsl,omitempty"`
 
 	// Workflow namespace (organization/categorization).
-	Namespace string
+	Namespace string `json:"namespace,omitempty"`
 
 	// Workflow name (unique identifier within namespace).
-	Name string
+	Name string `json:"name,omitempty"`
 
 	// Workflow version (semver).
-	Version string
+	Version string `json:"version,omitempty"`
 
 	// Human-readable description.
-	Description string
+	Description string `json:"description,omitempty"`
 }
 
 // Validation constants for Document.
 const (
-	dslVersion          = "1.0.0" // Current supported DSL version
-	namespaceMinLength  = 1
-	namespaceMaxLength  = 100
-	nameMinLength       = 1
-	nameMaxLength       = 100
-	versionMinLength    = 1
+	dslVersion           = "1.0.0" // Default DSL version
+	namespaceMinLength   = 1
+	namespaceMaxLength   = 100
+	nameMinLength        = 1
+	nameMaxLength        = 100
+	versionMinLength     = 1
 	descriptionMaxLength = 500
 )
 
@@ -38,14 +41,8 @@ var semverRegex = regexp.MustCompile(`^\d+\.\d+\.\d+(-[a-zA-Z0-9.-]+)?(\+[a-zA-Z
 // validateDocument validates a workflow document.
 func validateDocument(d *Document) error {
 	// Validate DSL version
-	if d.DSL != dslVersion {
-		return NewValidationErrorWithCause(
-			"document.dsl",
-			d.DSL,
-			"const",
-			`DSL version must be "1.0.0"`,
-			ErrInvalidVersion,
-		)
+	if !slices.Contains(dslVersions, d.DSL) {
+		return unsupportedDSLVersion(d.DSL)
 	}
 
 	// Validate namespace (required)
@@ -0,0 +1,132 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DefaultDSLVersion is the DSL version of workflows without WithDSLVersion.
+const DefaultDSLVersion = dslVersion
+
+// dslVersions lists the supported DSL versions, oldest first.
+var dslVersions = []string{"1.0.0-alpha5", "1.0.0"}
+
+// dslFeature is a workflow or task feature that needs a minimum DSL version.
+type dslFeature struct {
+	name  string // e.g. "LISTEN tasks"
+	since string
+
+	// usedBy reports whether a workflow (task == nil) or task uses the feature
+	usedBy func(w *Workflow, task *Task) bool
+}
+
+// dslFeatures is the compatibility table checked by ValidateDSLVersion.
+var dslFeatures = []dslFeature{
+	{name: "workflow timeouts", since: "1.0.0", usedBy: func(w *Workflow, task *Task) bool {
+		return task == nil && w.Timeout != ""
+	}},
+	{name: "schedules", since: "1.0.0", usedBy: func(w *Workflow, task *Task) bool {
+		return task == nil && len(w.Triggers) > 0
+	}},
+	{name: "LISTEN tasks", since: "1.0.0", usedBy: func(_ *Workflow, task *Task) bool {
+		return task != nil && task.Kind == TaskKindListen
+	}},
+	{name: "EMIT tasks", since: "1.0.0", usedBy: func(_ *Workflow, task *Task) bool {
+		return task != nil && task.Kind == TaskKindEmit
+	}},
+	{name: "task deadlines", since: "1.0.0", usedBy: func(_ *Workflow, task *Task) bool {
+		return task != nil && task.Deadline != ""
+	}},
+	{name: "competing FORK branches", since: "1.0.0", usedBy: func(_ *Workflow, task *Task) bool {
+		cfg, ok := taskConfig[*ForkTaskConfig](task)
+		return ok && cfg.Compete
+	}},
+	{name: "LISTEN timeouts", since: "1.0.0", usedBy: func(_ *Workflow, task *Task) bool {
+		cfg, ok := taskConfig[*ListenTaskConfig](task)
+		return ok && cfg.Timeout != ""
+	}},
+}
+
+func taskConfig[T TaskConfig](task *Task) (T, bool) {
+	if task == nil {
+		var zero T
+		return zero, false
+	}
+	cfg, ok := task.Config.(T)
+	return cfg, ok
+}
+
+// WithDSLVersion sets the Serverless Workflow DSL version of the workflow.
+// Supported versions are 1.0.0 (the default) and 1.0.0-alpha5.
+//
+// Targeting the older version lets the workflow run on engines that have not
+// upgraded yet; synthesis then rejects features it lacks, such as LISTEN and
+// EMIT tasks (see ValidateDSLVersion).
+//
+// Example:
+//
+//	workflow.New(ctx,
+//	    workflow.WithName("legacy-sync"),
+//	    workflow.WithDSLVersion("1.0.0-alpha5"),
+//	)
+func WithDSLVersion(version string) Option {
+	return func(w *Workflow) error {
+		w.Document.DSL = version
+		return nil
+	}
+}
+
+// ValidateDSLVersion checks every workflow option and task (including nested
+// tasks) against the workflow's DSL version. Each feature the version does not
+// support is reported, naming the version that added it; the errors are
+// joined and wrap ErrDSLIncompatible.
+//
+// This runs during synthesis.
+func ValidateDSLVersion(w *Workflow) error {
+	version := w.Document.DSL
+	if version == "" {
+		version = DefaultDSLVersion
+	}
+	if !slices.Contains(dslVersions, version) {
+		return unsupportedDSLVersion(version)
+	}
+
+	var errs []error
+	check := func(field string, task *Task) {
+		for _, f := range dslFeatures {
+			if dslVersionBefore(version, f.since) && f.usedBy(w, task) {
+				errs = append(errs, NewValidationErrorWithCause(
+					field,
+					version,
+					"dsl_version",
+					fmt.Sprintf("%s require DSL %s or later (workflow uses %s); use WithDSLVersion(%q) or remove them",
+						f.name, f.since, version, f.since),
+					ErrDSLIncompatible,
+				))
+			}
+		}
+	}
+
+	check("document.dsl", nil)
+	for task := range w.AllTasks() {
+		check("tasks."+task.Name, task)
+	}
+	return errors.Join(errs...)
+}
+
+// dslVersionBefore reports whether DSL version a is older than b.
+func dslVersionBefore(a, b string) bool {
+	return slices.Index(dslVersions, a) < slices.Index(dslVersions, b)
+}
+
+func unsupportedDSLVersion(version string) error {
+	return NewValidationErrorWithCause(
+		"document.dsl",
+		version,
+		"enum",
+		fmt.Sprintf("DSL version must be one of %s", strings.Join(dslVersions, ", ")),
+		ErrInvalidVersion,
+	)
+}
@@ -0,0 +1,51 @@
+package workflow
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestValidateDSLVersion verifies features newer than the DSL version are
+// reported, including in nested tasks, with the version that adds them.
+func TestValidateDSLVersion(t *testing.T) {
+	w, err := Build(WithNamespace("ops"), WithName("approvals"), WithDSLVersion("1.0.0-alpha5"), WithWorkflowTimeout("1h"))
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	w.AddTasks(
+		SetTask("init", SetVar("x", "1")),
+		ForTask("each", WithIn("${ .items }"), WithDo(
+			ListenTask("approval", WithEvent("approval.granted")),
+		)),
+	)
+
+	err = ValidateDSLVersion(w)
+	if !errors.Is(err, ErrDSLIncompatible) {
+		t.Fatalf("ValidateDSLVersion() error = %v, want ErrDSLIncompatible", err)
+	}
+	for _, want := range []string{
+		`"document.dsl": workflow timeouts require DSL 1.0.0 or later (workflow uses 1.0.0-alpha5); use WithDSLVersion("1.0.0")`,
+		`"tasks.approval": LISTEN tasks require DSL 1.0.0 or later`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error missing %q:\n%v", want, err)
+		}
+	}
+	if strings.Contains(err.Error(), "tasks.init") {
+		t.Errorf("SET task reported as incompatible: %v", err)
+	}
+
+	w.Document.DSL = DefaultDSLVersion
+	if err := ValidateDSLVersion(w); err != nil {
+		t.Errorf("ValidateDSLVersion(%s) error = %v", DefaultDSLVersion, err)
+	}
+}
+
+// TestWithDSLVersion_Unsupported verifies unknown DSL versions are rejected.
+func TestWithDSLVersion_Unsupported(t *testing.T) {
+	_, err := Build(WithNamespace("ops"), WithName("approvals"), WithDSLVersion("2.0.0"))
+	if !errors.Is(err, ErrInvalidVersion) {
+		t.Errorf("Build() error = %v, want ErrInvalidVersion", err)
+	}
+}
@@ -0,0 +1,153 @@
+package workflow
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// This file is the single conversion layer for duration and timeout options.
+// Every option that takes a duration accepts the same values:
+//
+//   - int, int32, int64: a number of seconds
+//   - time.Duration
+//   - a duration string like "30s" or "1h30m" (see Seconds, Minutes, Hours, Days)
+//   - an IntRef (seconds) or DurationRef from context
+//   - a Ref or expression resolved at runtime (duration strings only)
+//
+// Options stored as duration strings (WAIT durations, deadlines, activity and
+// listen timeouts, intervals) use toDuration; options stored as whole seconds
+// (HTTP, default and agent timeouts) use toSeconds.
+
+// DurationValue represents a duration-valued reference that can provide its
+// value, such as stigmer.DurationRef.
+type DurationValue = Valuer[time.Duration]
+
+// Duration creates a duration string from a time.Duration, in the form the
+// other duration helpers produce (e.g., 90*time.Minute becomes "1h30m").
+// Sub-millisecond precision is rounded up to the next millisecond.
+//
+// Example:
+//
+//	workflow.WaitTask("backoff",
+//	    workflow.WithDuration(workflow.Duration(1500*time.Millisecond)),  // "1s500ms"
+//	)
+func Duration(d time.Duration) string {
+	if d < 0 {
+		// Kept recognizably negative so validation rejects it
+		return "-" + Duration(-d)
+	}
+	millis := int64(math.Ceil(float64(d) / float64(time.Millisecond)))
+	if millis == 0 {
+		return "0s"
+	}
+
+	var b strings.Builder
+	for _, unit := range []struct {
+		suffix string
+		millis int64
+	}{
+		{"h", int64(time.Hour / time.Millisecond)},
+		{"m", int64(time.Minute / time.Millisecond)},
+		{"s", int64(time.Second / time.Millisecond)},
+		{"ms", 1},
+	} {
+		if n := millis / unit.millis; n > 0 {
+			fmt.Fprintf(&b, "%d%s", n, unit.suffix)
+			millis -= n * unit.millis
+		}
+	}
+	return b.String()
+}
+
+// toDuration converts a duration option value to a duration string.
+// Strings and runtime Refs are kept as given and checked during validation.
+func toDuration(value interface{}) string {
+	switch v := value.(type) {
+	case time.Duration:
+		return Duration(v)
+	case int:
+		return Seconds(v)
+	case int32:
+		return Seconds(int(v))
+	case int64:
+		return Seconds(int(v))
+	case DurationValue:
+		return Duration(v.Value())
+	case IntValue:
+		return Seconds(v.Value())
+	default:
+		return toExpression(value)
+	}
+}
+
+// toSeconds converts a timeout option value to whole seconds, rounding up.
+//
+// Runtime expressions cannot be used where the engine expects a number of
+// seconds, so strings must be duration strings.
+func toSeconds(value interface{}) (int32, error) {
+	switch v := value.(type) {
+	case int:
+		return int32(v), nil
+	case int32:
+		return v, nil
+	case int64:
+		return int32(v), nil
+	case time.Duration:
+		return int32(math.Ceil(v.Seconds())), nil
+	case DurationValue:
+		return int32(math.Ceil(v.Value().Seconds())), nil
+	case IntValue:
+		return int32(v.Value()), nil
+	case string:
+		d, err := parseDuration(v)
+		if err != nil {
+			return 0, err
+		}
+		return int32(math.Ceil(d.Seconds())), nil
+	default:
+		return 0, fmt.Errorf("unsupported timeout type %T: use seconds, a time.Duration or a duration like \"30s\"", value)
+	}
+}
+
+// parseDuration parses the duration strings produced by Seconds(), Minutes(),
+// Hours(), Days() and Duration(), including compound forms like "1h30m".
+func parseDuration(s string) (time.Duration, error) {
+	if !deadlineRegex.MatchString(s) {
+		return 0, fmt.Errorf("invalid duration %q: want a duration like \"30s\", \"5m\" or \"1h30m\"", s)
+	}
+	var d time.Duration
+	for _, part := range durationPartRegex.FindAllStringSubmatch(s, -1) {
+		n, _ := strconv.Atoi(part[1])
+		switch part[2] {
+		case "d":
+			d += time.Duration(n) * 24 * time.Hour
+		case "h":
+			d += time.Duration(n) * time.Hour
+		case "m":
+			d += time.Duration(n) * time.Minute
+		case "s":
+			d += time.Duration(n) * time.Second
+		case "ms":
+			d += time.Duration(n) * time.Millisecond
+		}
+	}
+	return d, nil
+}
+
+// validateDuration checks a task's duration setting. Expressions are resolved
+// at runtime and are not checked.
+func validateDuration(field, value string) error {
+	if value == "" || isExpression(value) || deadlineRegex.MatchString(value) {
+		return nil
+	}
+	return NewValidationErrorWithCause(
+		field,
+		value,
+		"format",
+		fmt.Sprintf("%s must be a duration like \"30s\", \"5m\" or \"1h30m\", got %q", field, value),
+		ErrInvalidTaskConfig,
+	)
+}
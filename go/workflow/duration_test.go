@@ -0,0 +1,89 @@
+package workflow
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type testDuration time.Duration
+
+func (d testDuration) Value() time.Duration { return time.Duration(d) }
+
+type testInt int
+
+func (i testInt) Value() int { return int(i) }
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{30 * time.Second, "30s"},
+		{90 * time.Minute, "1h30m"},
+		{1500 * time.Millisecond, "1s500ms"},
+		{time.Microsecond, "1ms"},
+		{-5 * time.Second, "-5s"},
+	}
+	for _, tt := range tests {
+		if got := Duration(tt.in); got != tt.want {
+			t.Errorf("Duration(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// TestDurationOptions verifies every way of giving a duration converts to the
+// same setting, for both duration string and seconds options.
+func TestDurationOptions(t *testing.T) {
+	inputs := []interface{}{90, int32(90), int64(90), 90 * time.Second, testInt(90), testDuration(90 * time.Second), "1m30s"}
+	for _, in := range inputs {
+		wait := WaitTask("wait", WithDuration(in)).Config.(*WaitTaskConfig)
+		if got, err := parseDuration(wait.Duration); err != nil || got != 90*time.Second {
+			t.Errorf("WithDuration(%#v) = %q", in, wait.Duration)
+		}
+
+		http := HttpCallTask("fetch", WithHTTPGet(), WithURI("https://api.example.com"), WithTimeout(in)).Config.(*HttpCallTaskConfig)
+		if http.TimeoutSeconds != 90 {
+			t.Errorf("WithTimeout(%#v) = %d seconds, want 90", in, http.TimeoutSeconds)
+		}
+	}
+
+	http := HttpCallTask("fetch", WithHTTPGet(), WithURI("https://api.example.com"), WithTimeout(1500*time.Millisecond)).Config.(*HttpCallTaskConfig)
+	if http.TimeoutSeconds != 2 {
+		t.Errorf("partial seconds = %d, want rounded up to 2", http.TimeoutSeconds)
+	}
+
+	agent := AgentCallTask("review", AgentOption(AgentBySlug("reviewer")), Message("review"), AgentTimeout(10*time.Minute))
+	if got := agent.Config.(*AgentCallTaskConfig).Config.Timeout; got != 600 {
+		t.Errorf("AgentTimeout(10m) = %d, want 600", got)
+	}
+}
+
+func TestDurationValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		task *Task
+	}{
+		{"wait string", WaitTask("wait", WithDuration("soon"))},
+		{"negative wait", WaitTask("wait", WithDuration(-time.Second))},
+		{"grpc deadline", GrpcCallTask("call", WithService("Users"), WithGrpcMethod("Get"), WithGrpcDeadline("30 seconds"))},
+		{"activity timeout", CallActivityTask("run", WithActivity("Process"), WithHeartbeatTimeout("1 minute"))},
+		{"http timeout string", HttpCallTask("fetch", WithHTTPGet(), WithURI("https://api.example.com"), WithTimeout("half a minute"))},
+		{"http timeout type", HttpCallTask("fetch", WithHTTPGet(), WithURI("https://api.example.com"), WithTimeout(1.5))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateTaskConfig(tt.task); !errors.Is(err, ErrInvalidTaskConfig) {
+				t.Errorf("validateTaskConfig() error = %v, want ErrInvalidTaskConfig", err)
+			}
+		})
+	}
+
+	if err := validateTaskConfig(WaitTask("wait", WithDuration("${ .delay }"))); err != nil {
+		t.Errorf("expressions are checked at runtime: error = %v", err)
+	}
+	if err := validateTrigger(Interval("every hour")); !errors.Is(err, ErrInvalidTrigger) {
+		t.Errorf("validateTrigger() error = %v, want ErrInvalidTrigger", err)
+	}
+}
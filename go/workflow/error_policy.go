@@ -0,0 +1,91 @@
+package workflow
+
+import "fmt"
+
+// ErrorPolicy bounds retries across the whole workflow.
+//
+// Retries configured on individual tasks (e.g., WithActivityRetry) draw from a
+// single budget, so nested retries cannot multiply without limit.
+type ErrorPolicy struct {
+	// MaxTotalRetries is the number of retries allowed across all tasks in a run
+	MaxTotalRetries int `json:"max_total_retries"`
+
+	// ExhaustedError is the error type raised when the budget is used up
+	ExhaustedError string `json:"exhausted_error,omitempty"`
+
+	// ExhaustedMessage is the error message raised when the budget is used up
+	ExhaustedMessage string `json:"exhausted_message,omitempty"`
+}
+
+// ErrorPolicyOption is a functional option for configuring an ErrorPolicy.
+type ErrorPolicyOption func(*ErrorPolicy)
+
+// WithErrorPolicy sets a workflow-wide retry budget.
+//
+// Example:
+//
+//	workflow.New(ctx,
+//	    workflow.WithNamespace("orders"),
+//	    workflow.WithName("fulfil-order"),
+//	    workflow.WithErrorPolicy(
+//	        workflow.MaxTotalRetries(10),
+//	        workflow.OnExhausted("RetryBudgetExceeded", "Budget exceeded"),
+//	    ),
+//	)
+func WithErrorPolicy(opts ...ErrorPolicyOption) Option {
+	return func(w *Workflow) error {
+		policy := &ErrorPolicy{}
+		for _, opt := range opts {
+			opt(policy)
+		}
+		w.ErrorPolicy = policy
+		return nil
+	}
+}
+
+// MaxTotalRetries sets the number of retries allowed across all tasks in a run.
+func MaxTotalRetries(count int) ErrorPolicyOption {
+	return func(p *ErrorPolicy) {
+		p.MaxTotalRetries = count
+	}
+}
+
+// OnExhausted sets the error raised when the retry budget is used up.
+// Accepts either a string or a StringRef from context for the message.
+// Without it, the workflow fails with the error of the task that ran out of retries.
+//
+// Example:
+//
+//	workflow.OnExhausted("RetryBudgetExceeded", "Budget exceeded")
+func OnExhausted(errorType string, message interface{}) ErrorPolicyOption {
+	return func(p *ErrorPolicy) {
+		p.ExhaustedError = errorType
+		p.ExhaustedMessage = toExpression(message)
+	}
+}
+
+// validateErrorPolicy validates a workflow error policy.
+func validateErrorPolicy(p *ErrorPolicy) error {
+	if p == nil {
+		return nil
+	}
+	if p.MaxTotalRetries <= 0 {
+		return NewValidationErrorWithCause(
+			"error_policy.max_total_retries",
+			fmt.Sprintf("%d", p.MaxTotalRetries),
+			"min",
+			"error policy must allow at least one retry (use MaxTotalRetries)",
+			ErrInvalidErrorPolicy,
+		)
+	}
+	if p.ExhaustedMessage != "" && p.ExhaustedError == "" {
+		return NewValidationErrorWithCause(
+			"error_policy.exhausted_error",
+			"",
+			"required",
+			"error policy exhausted message requires an error type",
+			ErrInvalidErrorPolicy,
+		)
+	}
+	return nil
+}
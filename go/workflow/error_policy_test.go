@@ -0,0 +1,56 @@
+package workflow_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/stigmer"
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+func TestWithErrorPolicy(t *testing.T) {
+	wf, err := workflow.New(stigmer.NewContext(),
+		workflow.WithNamespace("orders"),
+		workflow.WithName("fulfil-order"),
+		workflow.WithErrorPolicy(
+			workflow.MaxTotalRetries(10),
+			workflow.OnExhausted("RetryBudgetExceeded", "Budget exceeded"),
+		),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	want := workflow.ErrorPolicy{
+		MaxTotalRetries:  10,
+		ExhaustedError:   "RetryBudgetExceeded",
+		ExhaustedMessage: "Budget exceeded",
+	}
+	if wf.ErrorPolicy == nil || *wf.ErrorPolicy != want {
+		t.Errorf("ErrorPolicy = %+v, want %+v", wf.ErrorPolicy, want)
+	}
+}
+
+func TestWithErrorPolicy_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []workflow.ErrorPolicyOption
+	}{
+		{"missing budget", []workflow.ErrorPolicyOption{workflow.OnExhausted("RetryBudgetExceeded", "")}},
+		{"negative budget", []workflow.ErrorPolicyOption{workflow.MaxTotalRetries(-1)}},
+		{"message without error type", []workflow.ErrorPolicyOption{workflow.MaxTotalRetries(3), workflow.OnExhausted("", "Budget exceeded")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := workflow.New(stigmer.NewContext(),
+				workflow.WithNamespace("orders"),
+				workflow.WithName("fulfil-order"),
+				workflow.WithErrorPolicy(tt.opts...),
+			)
+			if !errors.Is(err, workflow.ErrInvalidErrorPolicy) {
+				t.Errorf("New() error = %v, want ErrInvalidErrorPolicy", err)
+			}
+		})
+	}
+}
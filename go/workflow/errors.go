@@ -37,6 +37,40 @@ var (
 	// ErrMissingRequiredField is returned when a required field is missing.
 	ErrMissingRequiredField = errors.New("missing required field")
 
+	// ErrInvalidTrigger is returned when a workflow trigger is invalid.
+	ErrInvalidTrigger = errors.New("invalid workflow trigger")
+
+	// ErrInvalidSchema is returned when a workflow input or output declaration is invalid.
+	ErrInvalidSchema = errors.New("invalid workflow input/output schema")
+
+	// ErrInvalidDeadline is returned when a workflow timeout or task deadline is invalid.
+	ErrInvalidDeadline = errors.New("invalid workflow timeout or task deadline")
+
+	// ErrInvalidErrorPolicy is returned when a workflow error policy is invalid.
+	ErrInvalidErrorPolicy = errors.New("invalid workflow error policy")
+
+	// ErrInvalidRollout is returned when a workflow rollout is invalid.
+	ErrInvalidRollout = errors.New("invalid workflow rollout")
+
+	// ErrInvalidResources is returned when the requested CPU or memory is malformed.
+	ErrInvalidResources = errors.New("invalid workflow resources")
+
+	// ErrInvalidTemplate is returned when a task template is instantiated with missing or unknown parameters.
+	ErrInvalidTemplate = errors.New("invalid task template parameters")
+
+	// ErrInvalidExpression is returned when a shared expression is invalid, redefined or not defined.
+	ErrInvalidExpression = errors.New("invalid shared expression")
+
+	// ErrDSLIncompatible is returned when a workflow uses a feature its DSL version does not support.
+	ErrDSLIncompatible = errors.New("feature not supported by DSL version")
+
+	// ErrDependencyCycle is returned when task dependencies form a cycle.
+	ErrDependencyCycle = errors.New("task dependency cycle")
+
+	// ErrInvalidFlow is returned when a workflow's control flow has dangling
+	// targets, unreachable tasks or loops without an exit.
+	ErrInvalidFlow = errors.New("invalid workflow flow")
+
 	// ErrConversion is returned when proto conversion fails.
 	ErrConversion = errors.New("proto conversion failed")
 )
@@ -0,0 +1,159 @@
+package workflow
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// expressionNameRegex matches valid shared expression names.
+var expressionNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// expressionUseRegex matches the placeholders returned by UseExpression.
+var expressionUseRegex = regexp.MustCompile(`@expression\(([A-Za-z_][A-Za-z0-9_]*)\)`)
+
+// maxExpressionDepth bounds how deeply shared expressions may use each other.
+const maxExpressionDepth = 10
+
+var (
+	sharedExpressionsMu sync.RWMutex
+	sharedExpressions   = make(map[string]string)
+)
+
+// DefineExpression registers a named expression that tasks and workflows can
+// share with UseExpression, so a long jq snippet is written (and fixed) once.
+//
+// The expression may be given with or without the ${ } wrapper, and may itself
+// use other shared expressions. Defining a name again with a different
+// expression returns an error.
+//
+// Typically called from an init() function or a package-level var block:
+//
+//	func init() {
+//	    workflow.DefineExpression("isBusinessHours",
+//	        `(now | strftime("%H") | tonumber) as $h | $h >= 9 and $h < 17`)
+//	}
+func DefineExpression(name, expression string) error {
+	if !expressionNameRegex.MatchString(name) {
+		return NewValidationErrorWithCause(
+			"name",
+			name,
+			"format",
+			"expression name must be an identifier (letters, digits and underscores)",
+			ErrInvalidExpression,
+		)
+	}
+	body := strings.TrimSpace(expression)
+	if strings.HasPrefix(body, "${") && strings.HasSuffix(body, "}") {
+		body = strings.TrimSpace(body[2 : len(body)-1])
+	}
+	if body == "" {
+		return NewValidationErrorWithCause("expression", expression, "required", "expression is required", ErrInvalidExpression)
+	}
+
+	sharedExpressionsMu.Lock()
+	defer sharedExpressionsMu.Unlock()
+
+	if existing, ok := sharedExpressions[name]; ok && existing != body {
+		return NewValidationErrorWithCause(
+			"name",
+			name,
+			"unique",
+			fmt.Sprintf("expression already defined: %q", name),
+			ErrInvalidExpression,
+		)
+	}
+	sharedExpressions[name] = body
+	return nil
+}
+
+// UseExpression references an expression registered with DefineExpression.
+//
+// It returns a ${ } expression that can be passed anywhere an expression is
+// accepted, including condition builders such as And and Not. The definition
+// is substituted during synthesis, so a fix to the definition applies to every
+// use; referencing a name that was never defined fails synthesis.
+//
+// Example:
+//
+//	wf.AddTask(workflow.SwitchTask("route",
+//	    workflow.WithCase(workflow.UseExpression("isBusinessHours"), "page-oncall"),
+//	    workflow.WithDefault("queue"),
+//	))
+func UseExpression(name string) string {
+	return "${ @expression(" + name + ") }"
+}
+
+// ResolveExpressions returns the tasks with every UseExpression placeholder
+// replaced by its definition, including in nested tasks. Tasks without
+// placeholders are returned unchanged; others are copied.
+//
+// This is used during synthesis.
+func ResolveExpressions(tasks []*Task) ([]*Task, error) {
+	result := make([]*Task, len(tasks))
+	for i, task := range tasks {
+		var err error
+		used := false
+		copyTask(task, &taskCopier{replace: func(s string) string {
+			used = used || expressionUseRegex.MatchString(s)
+			return s
+		}})
+		if !used {
+			result[i] = task
+			continue
+		}
+		result[i] = copyTask(task, &taskCopier{replace: func(s string) string {
+			resolved, resolveErr := resolveExpressionString(s)
+			if resolveErr != nil && err == nil {
+				err = resolveErr
+			}
+			return resolved
+		}})
+		if err != nil {
+			return nil, fmt.Errorf("task %s: %w", task.Name, err)
+		}
+	}
+	return result, nil
+}
+
+// resolveExpressionString substitutes shared expressions in s, parenthesized
+// so they keep their meaning inside larger expressions.
+func resolveExpressionString(s string) (string, error) {
+	sharedExpressionsMu.RLock()
+	defer sharedExpressionsMu.RUnlock()
+
+	for depth := 0; expressionUseRegex.MatchString(s); depth++ {
+		if depth == maxExpressionDepth {
+			return s, NewValidationErrorWithCause(
+				"expression",
+				s,
+				"depth",
+				fmt.Sprintf("shared expressions nested more than %d levels (do they use each other?)", maxExpressionDepth),
+				ErrInvalidExpression,
+			)
+		}
+		var missing string
+		s = expressionUseRegex.ReplaceAllStringFunc(s, func(m string) string {
+			name := expressionUseRegex.FindStringSubmatch(m)[1]
+			body, ok := sharedExpressions[name]
+			if !ok {
+				if missing == "" {
+					missing = name
+				}
+				return m
+			}
+			return "(" + body + ")"
+		})
+		if missing != "" {
+			return s, NewValidationErrorWithCause(
+				"expression",
+				missing,
+				"defined",
+				fmt.Sprintf("expression not defined: %q (use DefineExpression)", missing),
+				ErrInvalidExpression,
+			)
+		}
+	}
+	return s, nil
+}
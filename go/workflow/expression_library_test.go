@@ -0,0 +1,112 @@
+package workflow
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestResolveExpressions verifies shared expressions are substituted in switch
+// cases, composed conditions and nested tasks, leaving the originals untouched.
+func TestResolveExpressions(t *testing.T) {
+	if err := DefineExpression("libIsBusinessHours", `${ (now | strftime("%H") | tonumber) as $h | $h >= 9 and $h < 17 }`); err != nil {
+		t.Fatalf("DefineExpression() error = %v", err)
+	}
+	if err := DefineExpression("libIsUrgent", `.priority == "high"`); err != nil {
+		t.Fatalf("DefineExpression() error = %v", err)
+	}
+
+	route := SwitchTask("route",
+		WithCase(UseExpression("libIsBusinessHours"), "page"),
+		WithCase(And(UseExpression("libIsUrgent"), Not(UseExpression("libIsBusinessHours"))), "wake"),
+		WithDefault("queue"),
+	)
+	loop := ForTask("each", WithIn("${ .items }"), WithDo(
+		SetTask("flag", SetVar("urgent", UseExpression("libIsUrgent"))),
+	))
+	plain := SetTask("plain", SetVar("x", "1"))
+
+	resolved, err := ResolveExpressions([]*Task{route, loop, plain})
+	if err != nil {
+		t.Fatalf("ResolveExpressions() error = %v", err)
+	}
+
+	cases := resolved[0].Config.(*SwitchTaskConfig).Cases
+	if want := `${ ((now | strftime("%H") | tonumber) as $h | $h >= 9 and $h < 17) }`; cases[0].Condition != want {
+		t.Errorf("case 1 = %s, want %s", cases[0].Condition, want)
+	}
+	if want := `${ (.priority == "high") && !(((now | strftime("%H") | tonumber) as $h | $h >= 9 and $h < 17)) }`; cases[1].Condition != want {
+		t.Errorf("case 2 = %s, want %s", cases[1].Condition, want)
+	}
+	nested := resolved[1].Config.(*ForTaskConfig).Do[0].Config.(*SetTaskConfig)
+	if got, want := nested.Variables["urgent"], `${ (.priority == "high") }`; got != want {
+		t.Errorf("nested variable = %v, want %s", got, want)
+	}
+	if resolved[2] != plain {
+		t.Error("task without shared expressions should not be copied")
+	}
+	if got := route.Config.(*SwitchTaskConfig).Cases[0].Condition; got != UseExpression("libIsBusinessHours") {
+		t.Errorf("original task modified: %s", got)
+	}
+}
+
+// TestResolveExpressions_Nested verifies shared expressions can use each other.
+func TestResolveExpressions_Nested(t *testing.T) {
+	if err := DefineExpression("libIsWeekday", `(now | strftime("%u") | tonumber) < 6`); err != nil {
+		t.Fatal(err)
+	}
+	if err := DefineExpression("libIsWorkingDay", And(UseExpression("libIsWeekday"), `${ .holiday | not }`)); err != nil {
+		t.Fatal(err)
+	}
+
+	resolved, err := ResolveExpressions([]*Task{
+		SwitchTask("route", WithCase(UseExpression("libIsWorkingDay"), "work"), WithDefault("rest")),
+	})
+	if err != nil {
+		t.Fatalf("ResolveExpressions() error = %v", err)
+	}
+	want := `${ (((now | strftime("%u") | tonumber) < 6) && (.holiday | not)) }`
+	if got := resolved[0].Config.(*SwitchTaskConfig).Cases[0].Condition; got != want {
+		t.Errorf("condition = %s, want %s", got, want)
+	}
+}
+
+// TestExpressionLibrary_Errors verifies invalid names, conflicting definitions
+// and undefined or cyclic references are rejected.
+func TestExpressionLibrary_Errors(t *testing.T) {
+	if err := DefineExpression("is-open", ".open"); !errors.Is(err, ErrInvalidExpression) {
+		t.Errorf("invalid name: error = %v, want ErrInvalidExpression", err)
+	}
+	if err := DefineExpression("libEmpty", "${ }"); !errors.Is(err, ErrInvalidExpression) {
+		t.Errorf("empty expression: error = %v, want ErrInvalidExpression", err)
+	}
+
+	if err := DefineExpression("libIsOpen", ".open"); err != nil {
+		t.Fatal(err)
+	}
+	if err := DefineExpression("libIsOpen", "${ .open }"); err != nil {
+		t.Errorf("identical redefinition: error = %v", err)
+	}
+	if err := DefineExpression("libIsOpen", ".closed | not"); !errors.Is(err, ErrInvalidExpression) {
+		t.Errorf("conflicting redefinition: error = %v, want ErrInvalidExpression", err)
+	}
+
+	_, err := ResolveExpressions([]*Task{
+		SwitchTask("route", WithCase(UseExpression("libUndefined"), "a"), WithDefault("b")),
+	})
+	if !errors.Is(err, ErrInvalidExpression) {
+		t.Errorf("undefined expression: error = %v, want ErrInvalidExpression", err)
+	}
+
+	if err := DefineExpression("libPing", UseExpression("libPong")); err != nil {
+		t.Fatal(err)
+	}
+	if err := DefineExpression("libPong", UseExpression("libPing")); err != nil {
+		t.Fatal(err)
+	}
+	_, err = ResolveExpressions([]*Task{
+		SwitchTask("route", WithCase(UseExpression("libPing"), "a"), WithDefault("b")),
+	})
+	if !errors.Is(err, ErrInvalidExpression) {
+		t.Errorf("cyclic expressions: error = %v, want ErrInvalidExpression", err)
+	}
+}
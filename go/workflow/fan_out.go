@@ -0,0 +1,142 @@
+package workflow
+
+import "fmt"
+
+// fanOutConfig holds the settings of a FanOutAgentCalls task.
+type fanOutConfig struct {
+	items       interface{}
+	concurrency int
+	collect     string
+	callOpts    []AgentCallOption
+}
+
+// FanOutOption is a functional option for FanOutAgentCalls.
+type FanOutOption func(*fanOutConfig)
+
+// WithItems sets the collection the agent is called for, one call per item.
+// Accepts a ListRef, another Ref, or an expression. A task output reference
+// makes the fan-out depend on its task.
+func WithItems(items interface{}) FanOutOption {
+	return func(cfg *fanOutConfig) {
+		cfg.items = items
+	}
+}
+
+// WithConcurrency sets how many agent calls run at the same time.
+// Defaults to 1 (one item after the other).
+func WithConcurrency(n int) FanOutOption {
+	return func(cfg *fanOutConfig) {
+		cfg.concurrency = n
+	}
+}
+
+// WithCollect appends the result of every agent call to the list under key in
+// the workflow context, so later tasks can read all results.
+func WithCollect(key string) FanOutOption {
+	return func(cfg *fanOutConfig) {
+		cfg.collect = key
+	}
+}
+
+// WithAgentCall configures each agent call, e.g. its prompt or timeout.
+// Use FanOutItem to refer to the current item.
+//
+// Example:
+//
+//	workflow.WithAgentCall(
+//	    workflow.WithPrompt("Review this pull request"),
+//	    workflow.AgentTimeout(10*time.Minute),
+//	)
+func WithAgentCall(opts ...AgentCallOption) FanOutOption {
+	return func(cfg *fanOutConfig) {
+		cfg.callOpts = append(cfg.callOpts, opts...)
+	}
+}
+
+// FanOutItem returns the expression for the current item inside an agent call
+// made by FanOutAgentCalls.
+func FanOutItem() string {
+	return "${ . }"
+}
+
+// FanOutAgentCalls creates a task that calls the agent once per item, the
+// standard pattern for running an agent over many items. Each call receives
+// the item as its "item" input and, unless WithAgentCall sets a prompt, as
+// JSON in its prompt.
+//
+// With the default concurrency of 1 the task is a FOR task around the agent
+// call "<name>-call". With WithConcurrency(n) it is a FORK task with n
+// branches "<name>-<i>", each a FOR task over every n-th item, so at most n
+// calls run at the same time.
+//
+// Example:
+//
+//	reviewer := workflow.Agent(reviewAgent)
+//	task := workflow.FanOutAgentCalls("reviewAll", reviewer,
+//	    workflow.WithItems(fetchTask.FieldAsList("pullRequests")),
+//	    workflow.WithConcurrency(5),
+//	    workflow.WithCollect("reviews"),
+//	)
+//	// Later tasks read all results from ${ $context.reviews }
+func FanOutAgentCalls(name string, agent AgentRef, opts ...FanOutOption) *Task {
+	cfg := &fanOutConfig{concurrency: 1}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	items := toExpression(cfg.items)
+	if isExpression(items) {
+		items = expressionBody(items)
+	}
+
+	var task *Task
+	if cfg.concurrency <= 1 {
+		task = ForTask(name,
+			WithIn(fmt.Sprintf("${ %s }", items)),
+			WithDo(cfg.agentCall(name+"-call", agent)),
+		)
+	} else {
+		var branches []ForkTaskOption
+		for i := 0; i < cfg.concurrency; i++ {
+			branch := fmt.Sprintf("%s-%d", name, i)
+			// Branch i handles the items whose index is i modulo the concurrency
+			stride := fmt.Sprintf("${ [(%s) | to_entries[] | select(.key %% %d == %d) | .value] }", items, cfg.concurrency, i)
+			branches = append(branches, WithBranch(branch,
+				ForTask(branch, WithIn(stride), WithDo(cfg.agentCall(branch+"-call", agent))),
+			))
+		}
+		task = ForkTask(name, branches...)
+	}
+
+	if ref, ok := cfg.items.(taskOutputRef); ok {
+		task.dependsOnName(ref.TaskName())
+	}
+	return task
+}
+
+// FanOutAgentCalls creates a fan-out agent task and adds it to the workflow.
+// See the FanOutAgentCalls function.
+//
+// Example:
+//
+//	wf.FanOutAgentCalls("reviewAll", workflow.Agent(reviewAgent),
+//	    workflow.WithItems(prs), workflow.WithCollect("reviews"))
+func (w *Workflow) FanOutAgentCalls(name string, agent AgentRef, opts ...FanOutOption) *Task {
+	task := FanOutAgentCalls(name, agent, opts...)
+	w.AddTask(task)
+	return task
+}
+
+// agentCall returns the agent call made for each item.
+func (cfg *fanOutConfig) agentCall(name string, agent AgentRef) *Task {
+	callOpts := []AgentCallOption{
+		AgentOption(agent),
+		WithPrompt("${ . | tojson }"),
+		WithAgentInput(map[string]any{"item": FanOutItem()}),
+	}
+	call := AgentCallTask(name, append(callOpts, cfg.callOpts...)...)
+	if cfg.collect != "" {
+		call.Export(fmt.Sprintf("${ $context + { %s: (($context.%s // []) + [.]) } }", cfg.collect, cfg.collect))
+	}
+	return call
+}
@@ -0,0 +1,71 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestFanOutAgentCalls_Sequential verifies the FOR task wrapping one agent call.
+func TestFanOutAgentCalls_Sequential(t *testing.T) {
+	fetch := HttpCallTask("fetch", WithURI("https://api.example.com/prs"))
+	task := FanOutAgentCalls("reviewAll", AgentBySlug("reviewer"),
+		WithItems(fetch.FieldAsList("prs")),
+		WithCollect("reviews"),
+	)
+
+	if task.Kind != TaskKindFor {
+		t.Fatalf("Kind = %s, want FOR", task.Kind)
+	}
+	cfg := task.Config.(*ForTaskConfig)
+	if cfg.In != "${ $context.fetch.prs }" {
+		t.Errorf("In = %q", cfg.In)
+	}
+	if len(cfg.Do) != 1 || cfg.Do[0].Name != "reviewAll-call" {
+		t.Fatalf("Do = %v, want [reviewAll-call]", cfg.Do)
+	}
+	call := cfg.Do[0]
+	if agentCfg := call.Config.(*AgentCallTaskConfig); agentCfg.Agent.Slug() != "reviewer" || agentCfg.Input["item"] != FanOutItem() {
+		t.Errorf("agent call = %+v", agentCfg)
+	}
+	if want := "${ $context + { reviews: (($context.reviews // []) + [.]) } }"; call.ExportAs != want {
+		t.Errorf("ExportAs = %q, want %q", call.ExportAs, want)
+	}
+	if len(task.Dependencies) != 1 || task.Dependencies[0] != "fetch" {
+		t.Errorf("Dependencies = %v, want [fetch]", task.Dependencies)
+	}
+	if err := validateTaskConfig(task); err != nil {
+		t.Errorf("validateTaskConfig() error = %v", err)
+	}
+}
+
+// TestFanOutAgentCalls_Concurrency verifies that concurrency splits the items
+// across FORK branches.
+func TestFanOutAgentCalls_Concurrency(t *testing.T) {
+	task := FanOutAgentCalls("reviewAll", AgentBySlug("reviewer"),
+		WithItems("${ .input.prs }"),
+		WithConcurrency(3),
+		WithAgentCall(WithPrompt("Review this pull request")),
+	)
+
+	if task.Kind != TaskKindFork {
+		t.Fatalf("Kind = %s, want FORK", task.Kind)
+	}
+	branches := task.Config.(*ForkTaskConfig).Branches
+	if len(branches) != 3 {
+		t.Fatalf("got %d branches, want 3", len(branches))
+	}
+	loop := branches[2].Tasks[0]
+	if loop.Name != "reviewAll-2" {
+		t.Errorf("branch task = %q, want reviewAll-2", loop.Name)
+	}
+	if want := "${ [(.input.prs) | to_entries[] | select(.key % 3 == 2) | .value] }"; loop.Config.(*ForTaskConfig).In != want {
+		t.Errorf("In = %q, want %q", loop.Config.(*ForTaskConfig).In, want)
+	}
+	call := loop.Config.(*ForTaskConfig).Do[0]
+	if msg := call.Config.(*AgentCallTaskConfig).Message; msg != "Review this pull request" {
+		t.Errorf("Message = %q", msg)
+	}
+	if strings.Contains(call.ExportAs, "$context +") {
+		t.Errorf("ExportAs = %q, want no collection without WithCollect", call.ExportAs)
+	}
+}
@@ -0,0 +1,127 @@
+package workflow
+
+import "fmt"
+
+const (
+	// finalizerTryName names the TRY task that wraps the workflow's tasks.
+	finalizerTryName = "finally"
+
+	// finalizerErrorVar is the catch variable bound to the failure that
+	// triggered the finalizers.
+	finalizerErrorVar = "finallyError"
+
+	// finalizerErrorSuffix is appended to the names of the finalizer copies
+	// that run when the workflow fails.
+	finalizerErrorSuffix = "-on-error"
+)
+
+// WithFinalizer registers tasks that run at the end of the workflow whether it
+// succeeds or fails, such as releasing a lock or sending an audit event.
+// Finalizers run in the order given; when the workflow failed, the original
+// error is raised again after them.
+//
+// The DSL has no finally block, so finalizers are synthesized as a top-level
+// TRY task (see LowerFinalizers). As a consequence, tasks may not jump to
+// EndFlow with Then() or a switch case, which would skip the finalizers, and finalizers may not
+// use Then() themselves. Finalizers that read task outputs must allow for
+// fields missing when the task that exports them did not run.
+//
+// Example:
+//
+//	wf, err := workflow.New(ctx,
+//	    workflow.WithNamespace("billing"),
+//	    workflow.WithName("monthly-invoices"),
+//	    workflow.WithFinalizer(
+//	        workflow.HttpCallTask("releaseLock", workflow.WithHTTPDelete(), workflow.WithURI(lockURL)),
+//	        workflow.HttpCallTask("audit", workflow.WithHTTPPost(), workflow.WithURI(auditURL)),
+//	    ),
+//	)
+func WithFinalizer(tasks ...*Task) Option {
+	return func(w *Workflow) error {
+		w.Finalizers = append(w.Finalizers, tasks...)
+		return nil
+	}
+}
+
+// LowerFinalizers rewrites a task list so that the finalizers run after it on
+// success and on failure.
+//
+// The tasks are wrapped in a TRY task named "finally" whose catch block runs a
+// copy of each finalizer (named "<task>-on-error") and re-raises the error; the
+// finalizers follow the TRY task for the success path. The task list is
+// returned unchanged when there are no finalizers.
+//
+// This is used during synthesis, after LowerCompensations.
+func LowerFinalizers(tasks, finalizers []*Task) ([]*Task, error) {
+	if len(finalizers) == 0 {
+		return tasks, nil
+	}
+	if err := validateFinalizers(tasks, finalizers); err != nil {
+		return nil, err
+	}
+
+	catch := make([]*Task, 0, len(finalizers)+1)
+	for _, f := range finalizers {
+		onError := *f
+		onError.Name = f.Name + finalizerErrorSuffix
+		catch = append(catch, &onError)
+	}
+	catch = append(catch, RaiseTask(finalizerTryName+"-rethrow",
+		WithError(ErrorCode(finalizerErrorVar)),
+		WithErrorMessage(ErrorMessage(finalizerErrorVar)),
+	))
+
+	result := make([]*Task, 0, len(finalizers)+1)
+	result = append(result, TryTask(finalizerTryName,
+		WithTry(tasks...),
+		WithCatchTyped(CatchAny(), finalizerErrorVar, catch...),
+	))
+	return append(result, finalizers...), nil
+}
+
+// validateFinalizers rejects finalizer names that clash with the workflow's
+// tasks and jumps (Then(), switch cases and defaults) that would leave the TRY
+// task or skip the finalizers.
+func validateFinalizers(tasks, finalizers []*Task) error {
+	names := map[string]bool{finalizerTryName: true}
+	for _, t := range tasks {
+		names[t.Name] = true
+	}
+	for i, f := range finalizers {
+		field := fmt.Sprintf("finalizers[%d]", i)
+		for _, name := range []string{f.Name, f.Name + finalizerErrorSuffix} {
+			if names[name] {
+				return NewValidationErrorWithCause(field+".name", name, "unique",
+					fmt.Sprintf("duplicate task name: %q", name), ErrDuplicateTaskName)
+			}
+			names[name] = true
+		}
+		if f.ThenTask != "" {
+			return NewValidationErrorWithCause(field+".then", f.ThenTask, "finalizer",
+				fmt.Sprintf("finalizer %q cannot use Then()", f.Name), ErrInvalidTaskConfig)
+		}
+		if len(f.Compensations) > 0 {
+			return NewValidationErrorWithCause(field+".compensations", f.Name, "finalizer",
+				fmt.Sprintf("finalizer %q cannot use Compensate()", f.Name), ErrInvalidTaskConfig)
+		}
+		if err := validateNestedCompensations(f); err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+	}
+
+	finalizerNames := make(map[string]bool, len(finalizers))
+	for _, f := range finalizers {
+		finalizerNames[f.Name] = true
+	}
+	for _, t := range tasks {
+		targets, _ := flowTargets(t)
+		for _, target := range targets {
+			if target.name == EndFlow || finalizerNames[target.name] {
+				return NewValidationErrorWithCause(target.field, target.name, "finalizer",
+					fmt.Sprintf("task %q jumps to %q, which would skip the workflow finalizers", t.Name, target.name),
+					ErrInvalidTaskConfig)
+			}
+		}
+	}
+	return nil
+}
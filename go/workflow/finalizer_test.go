@@ -0,0 +1,90 @@
+package workflow
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestLowerFinalizers verifies finalizers run after the TRY task and in its catch block.
+func TestLowerFinalizers(t *testing.T) {
+	work := SetTask("work", SetVar("done", "true"))
+	release := SetTask("releaseLock", SetVar("locked", "false"))
+
+	tasks, err := LowerFinalizers([]*Task{work}, []*Task{release})
+	if err != nil {
+		t.Fatalf("LowerFinalizers() error = %v", err)
+	}
+	if len(tasks) != 2 || tasks[0].Name != "finally" || tasks[1] != release {
+		t.Fatalf("top level = %v, want [finally releaseLock]", taskNames(tasks))
+	}
+
+	try := tasks[0].Config.(*TryTaskConfig)
+	if got := try.Tasks; len(got) != 1 || got[0].Name != "work" {
+		t.Errorf("finally try tasks = %v, want [work]", got)
+	}
+	if got := try.Catch[0].Tasks; len(got) != 2 || got[0].Name != "releaseLock-on-error" || got[1].Kind != TaskKindRaise {
+		t.Errorf("finally catch tasks = %v, want [releaseLock-on-error finally-rethrow]", got)
+	}
+	if release.Name != "releaseLock" {
+		t.Errorf("finalizer renamed in place to %q", release.Name)
+	}
+
+	unchanged, err := LowerFinalizers([]*Task{work}, nil)
+	if err != nil || len(unchanged) != 1 || unchanged[0] != work {
+		t.Errorf("LowerFinalizers() without finalizers = %v, %v; want unchanged", taskNames(unchanged), err)
+	}
+}
+
+// TestLowerFinalizers_Errors verifies name clashes and jumps that skip the finalizers are rejected.
+func TestLowerFinalizers_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		tasks      []*Task
+		finalizers []*Task
+		want       error
+	}{
+		{
+			name:       "duplicate name",
+			tasks:      []*Task{SetTask("audit", SetVar("x", "1"))},
+			finalizers: []*Task{SetTask("audit", SetVar("x", "2"))},
+			want:       ErrDuplicateTaskName,
+		},
+		{
+			name:       "end flow",
+			tasks:      []*Task{SetTask("work", SetVar("x", "1")).Then(EndFlow)},
+			finalizers: []*Task{SetTask("audit", SetVar("x", "2"))},
+			want:       ErrInvalidTaskConfig,
+		},
+		{
+			name:       "switch case to end",
+			tasks:      []*Task{SwitchTask("route", WithCase("${ .done }", EndFlow))},
+			finalizers: []*Task{SetTask("audit", SetVar("x", "2"))},
+			want:       ErrInvalidTaskConfig,
+		},
+		{
+			name:       "switch default to end",
+			tasks:      []*Task{SwitchTask("route", WithCase("${ .retry }", "route"), WithDefault(EndFlow))},
+			finalizers: []*Task{SetTask("audit", SetVar("x", "2"))},
+			want:       ErrInvalidTaskConfig,
+		},
+		{
+			name:       "jump to finalizer",
+			tasks:      []*Task{SetTask("work", SetVar("x", "1")).Then("audit")},
+			finalizers: []*Task{SetTask("audit", SetVar("x", "2"))},
+			want:       ErrInvalidTaskConfig,
+		},
+		{
+			name:       "finalizer then",
+			tasks:      []*Task{SetTask("work", SetVar("x", "1"))},
+			finalizers: []*Task{SetTask("audit", SetVar("x", "2")).Then("work")},
+			want:       ErrInvalidTaskConfig,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LowerFinalizers(tt.tasks, tt.finalizers); !errors.Is(err, tt.want) {
+				t.Errorf("LowerFinalizers() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
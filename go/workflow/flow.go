@@ -0,0 +1,267 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Flow directives accepted as Then and switch targets in addition to task names.
+const (
+	flowContinue = "continue"
+	flowExit     = "exit"
+)
+
+// ValidateFlow checks the control flow of a task list, as it will run:
+//
+//   - every Then, switch case, switch default and LISTEN timeout target names an
+//     existing task in the same list (or is "end", "exit" or "continue"); errors list the
+//     valid targets and suggest the closest name
+//   - every task can be reached from the first task
+//   - every reachable task can still reach the end of the list, so the flow
+//     has no loop without an exit
+//
+// Nested task lists (FOR, FORK, TRY) are checked as separate scopes.
+// Problems are reported as ErrInvalidFlow.
+//
+// This runs during synthesis, after tasks are ordered by their dependencies.
+func ValidateFlow(tasks []*Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	index := make(map[string]int, len(tasks))
+	for i, task := range tasks {
+		index[task.Name] = i
+	}
+
+	// next[i] holds the tasks that can run after tasks[i]; ends[i] is true if
+	// the flow can finish after tasks[i]
+	next := make([][]int, len(tasks))
+	ends := make([]bool, len(tasks))
+	for i, task := range tasks {
+		if task.Kind == TaskKindRaise {
+			// A raise leaves the list with an error, which also ends it
+			ends[i] = true
+		}
+		targets, fallThrough := flowTargets(task)
+		if fallThrough {
+			targets = append(targets, flowTarget{field: "then", name: flowContinue})
+		}
+		for _, target := range targets {
+			switch target.name {
+			case EndFlow, flowExit:
+				ends[i] = true
+			case flowContinue:
+				if i == len(tasks)-1 {
+					ends[i] = true
+				} else {
+					next[i] = append(next[i], i+1)
+				}
+			default:
+				j, ok := index[target.name]
+				if !ok {
+					return danglingTargetError(task, target, tasks)
+				}
+				next[i] = append(next[i], j)
+			}
+		}
+	}
+
+	// Forward reachability from the first task
+	reachable := make([]bool, len(tasks))
+	stack := []int{0}
+	reachable[0] = true
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, j := range next[i] {
+			if !reachable[j] {
+				reachable[j] = true
+				stack = append(stack, j)
+			}
+		}
+	}
+	for i, task := range tasks {
+		if !reachable[i] {
+			return NewValidationErrorWithCause(
+				"tasks",
+				task.Name,
+				"reachable",
+				fmt.Sprintf("task %q can never run: no task flows into it", task.Name),
+				ErrInvalidFlow,
+			)
+		}
+	}
+
+	// Tasks that can finish: iterate to a fixed point over the successors
+	finishes := append([]bool(nil), ends...)
+	for changed := true; changed; {
+		changed = false
+		for i := range tasks {
+			if finishes[i] {
+				continue
+			}
+			for _, j := range next[i] {
+				if finishes[j] {
+					finishes[i] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	var looping []string
+	for i, task := range tasks {
+		if !finishes[i] {
+			looping = append(looping, task.Name)
+		}
+	}
+	if len(looping) > 0 {
+		return NewValidationErrorWithCause(
+			"tasks",
+			strings.Join(looping, ","),
+			"terminates",
+			fmt.Sprintf("tasks %s loop forever: no path leads to the end of the workflow", strings.Join(looping, ", ")),
+			ErrInvalidFlow,
+		)
+	}
+
+	for _, task := range tasks {
+		for _, child := range nestedTaskLists(task) {
+			if err := ValidateFlow(child); err != nil {
+				return fmt.Errorf("task %s: %w", task.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// flowTarget is a jump target together with the field that declares it.
+type flowTarget struct {
+	field string // "then", "cases[i].then", "default" or "timeout_then"
+	name  string
+}
+
+// flowTargets returns the explicit targets a task can jump to, and whether it
+// can also fall through to the next task.
+func flowTargets(task *Task) ([]flowTarget, bool) {
+	if task.Kind == TaskKindRaise {
+		// A raise never continues; the error is handled by an enclosing TRY
+		return nil, false
+	}
+
+	var targets []flowTarget
+	fallThrough := task.ThenTask == ""
+	if !fallThrough {
+		targets = append(targets, flowTarget{field: "then", name: task.ThenTask})
+	}
+
+	if cfg, ok := task.Config.(*SwitchTaskConfig); ok {
+		for i, c := range cfg.Cases {
+			if c.Then != "" {
+				targets = append(targets, flowTarget{field: fmt.Sprintf("cases[%d].then", i), name: c.Then})
+			}
+			if c.Condition == "" {
+				// A case without a condition always matches
+				fallThrough = false
+			}
+		}
+		if cfg.DefaultTask != "" {
+			// With a default, the switch always jumps
+			targets = append(targets, flowTarget{field: "default", name: cfg.DefaultTask})
+			fallThrough = false
+		}
+	}
+
+	if cfg, ok := task.Config.(*ListenTaskConfig); ok && cfg.TimeoutThen != "" {
+		// The listen continues as usual when the event arrives
+		targets = append(targets, flowTarget{field: "timeout_then", name: cfg.TimeoutThen})
+	}
+	return targets, fallThrough
+}
+
+// danglingTargetError reports a jump to a task that is not in scope, listing
+// the valid targets and the closest match.
+func danglingTargetError(task *Task, target flowTarget, scope []*Task) error {
+	valid := make([]string, 0, len(scope)+3)
+	for _, t := range scope {
+		valid = append(valid, t.Name)
+	}
+
+	msg := fmt.Sprintf("task %q %s target %q does not exist", task.Name, target.field, target.name)
+	if suggestion := closestName(target.name, valid); suggestion != "" {
+		msg += fmt.Sprintf(" (did you mean %q?)", suggestion)
+	}
+	valid = append(valid, EndFlow, flowExit, flowContinue)
+	msg += "; valid targets: " + strings.Join(valid, ", ")
+
+	return NewValidationErrorWithCause(
+		target.field,
+		target.name,
+		"exists",
+		msg,
+		ErrInvalidFlow,
+	)
+}
+
+// closestName returns the name within edit distance 2 of target, or "".
+func closestName(target string, names []string) string {
+	best, bestDist := "", 3
+	for _, name := range names {
+		if d := editDistance(target, name); d < bestDist {
+			best, bestDist = name, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// nestedTaskLists returns the task lists nested directly in a task, as pointers.
+func nestedTaskLists(task *Task) [][]*Task {
+	var lists [][]*Task
+	add := func(tasks []Task) {
+		if len(tasks) == 0 {
+			return
+		}
+		list := make([]*Task, len(tasks))
+		for i := range tasks {
+			list[i] = &tasks[i]
+		}
+		lists = append(lists, list)
+	}
+
+	switch cfg := task.Config.(type) {
+	case *ForTaskConfig:
+		add(cfg.Do)
+	case *ForkTaskConfig:
+		for _, branch := range cfg.Branches {
+			add(branch.Tasks)
+		}
+	case *TryTaskConfig:
+		add(cfg.Tasks)
+		for _, catch := range cfg.Catch {
+			add(catch.Tasks)
+		}
+	}
+	return lists
+}
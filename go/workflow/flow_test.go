@@ -0,0 +1,162 @@
+package workflow
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestValidateFlow verifies valid flows, including polling loops with an exit, are accepted.
+func TestValidateFlow(t *testing.T) {
+	poll := HttpCallTask("poll", WithURI("https://api.example.com/status"))
+	check := SwitchTask("check",
+		WithCase("${ .status == \"done\" }", "finish"),
+		WithDefault("wait"),
+	)
+	wait := WaitTask("wait", WithDuration("5s")).Then("poll")
+	finish := SetTask("finish", SetVar("done", "true"))
+
+	if err := ValidateFlow([]*Task{poll, check, wait, finish}); err != nil {
+		t.Errorf("ValidateFlow() error = %v", err)
+	}
+}
+
+// TestValidateFlow_Raise verifies a list that ends in a raise is not reported as a loop.
+func TestValidateFlow_Raise(t *testing.T) {
+	try := TryTask("guard",
+		WithTry(RaiseTask("fail", WithError("Failed"))),
+		WithCatch(nil, "err", SetTask("recover", SetVar("ok", "false"))),
+	)
+	if err := ValidateFlow([]*Task{try}); err != nil {
+		t.Errorf("ValidateFlow() error = %v", err)
+	}
+}
+
+// TestValidateFlow_Invalid verifies dangling targets, unreachable tasks and endless loops are rejected.
+func TestValidateFlow_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		tasks func() []*Task
+	}{
+		{
+			name: "dangling then",
+			tasks: func() []*Task {
+				return []*Task{
+					HttpCallTask("fetchData", WithURI("https://api.example.com")).Then("procesData"),
+					SetTask("processData", SetVar("x", "1")),
+				}
+			},
+		},
+		{
+			name: "dangling switch case",
+			tasks: func() []*Task {
+				return []*Task{SwitchTask("route", WithCase("${ .ok }", "missing"))}
+			},
+		},
+		{
+			name: "unreachable task",
+			tasks: func() []*Task {
+				return []*Task{
+					SetTask("a", SetVar("x", "1")).End(),
+					SetTask("orphan", SetVar("x", "2")),
+				}
+			},
+		},
+		{
+			name: "loop without exit",
+			tasks: func() []*Task {
+				return []*Task{
+					SetTask("a", SetVar("x", "1")),
+					SetTask("b", SetVar("x", "2")).Then("a"),
+				}
+			},
+		},
+		{
+			name: "nested scope",
+			tasks: func() []*Task {
+				return []*Task{ForTask("each",
+					WithIn("${ .items }"),
+					WithDo(SetTask("step", SetVar("x", "1")).Then("nowhere")),
+				)}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateFlow(tt.tasks()); !errors.Is(err, ErrInvalidFlow) {
+				t.Errorf("ValidateFlow() error = %v, want ErrInvalidFlow", err)
+			}
+		})
+	}
+}
+
+// TestValidateFlow_SwitchTargetMessage verifies dangling switch targets list valid names and suggest a fix.
+func TestValidateFlow_SwitchTargetMessage(t *testing.T) {
+	route := SwitchTask("route",
+		WithCase("${ .ok }", "handleSucess"),
+		WithDefault("handleError"),
+	)
+	tasks := []*Task{
+		route,
+		SetTask("handleSuccess", SetVar("x", "1")).End(),
+		SetTask("handleError", SetVar("x", "2")),
+	}
+
+	err := ValidateFlow(tasks)
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("ValidateFlow() error = %v, want ValidationError", err)
+	}
+	want := `task "route" cases[0].then target "handleSucess" does not exist (did you mean "handleSuccess"?); ` +
+		`valid targets: route, handleSuccess, handleError, end, exit, continue`
+	if verr.Message != want {
+		t.Errorf("Message = %q, want %q", verr.Message, want)
+	}
+	if verr.Field != "cases[0].then" {
+		t.Errorf("Field = %q, want cases[0].then", verr.Field)
+	}
+}
+
+// TestValidateFlow_ConditionlessCase verifies a case without a condition acts as a default.
+func TestValidateFlow_ConditionlessCase(t *testing.T) {
+	tasks := []*Task{
+		SwitchTask("route", WithCase("${ .ok }", "done"), WithCase("", "retry")),
+		SetTask("skipped", SetVar("x", "1")),
+		SetTask("retry", SetVar("x", "2")).End(),
+		SetTask("done", SetVar("x", "3")),
+	}
+	if err := ValidateFlow(tasks); !errors.Is(err, ErrInvalidFlow) {
+		t.Errorf("ValidateFlow() error = %v, want unreachable task error", err)
+	}
+}
+
+// TestValidateFlow_ListenTimeout verifies a LISTEN timeout target counts as a
+// flow edge and must name an existing task.
+func TestValidateFlow_ListenTimeout(t *testing.T) {
+	escalate := SetTask("escalate", SetVar("escalated", "true"))
+	wait := ListenTask("waitForApproval",
+		WithEvent("approval.granted"),
+		WithListenTimeout(Hours(24), escalate),
+	)
+	approve := SetTask("approve", SetVar("approved", "true")).End()
+
+	// escalate is only reachable through the timeout
+	if err := ValidateFlow([]*Task{wait, approve, escalate}); err != nil {
+		t.Errorf("ValidateFlow() error = %v", err)
+	}
+
+	wait.Config.(*ListenTaskConfig).TimeoutThen = "escalat"
+	err := ValidateFlow([]*Task{wait, approve, escalate})
+	var verr *ValidationError
+	if !errors.As(err, &verr) || !errors.Is(err, ErrInvalidFlow) {
+		t.Fatalf("ValidateFlow() error = %v, want ErrInvalidFlow", err)
+	}
+	want := `task "waitForApproval" timeout_then target "escalat" does not exist (did you mean "escalate"?); ` +
+		`valid targets: waitForApproval, approve, escalate, end, exit, continue`
+	if verr.Message != want {
+		t.Errorf("Message = %q, want %q", verr.Message, want)
+	}
+	if verr.Field != "timeout_then" {
+		t.Errorf("Field = %q, want timeout_then", verr.Field)
+	}
+}
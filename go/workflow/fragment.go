@@ -0,0 +1,138 @@
+package workflow
+
+import "regexp"
+
+// contextTaskRefRegex matches the task name in "$context.<task>" references.
+var contextTaskRefRegex = regexp.MustCompile(`\$context\.([a-zA-Z0-9_-]+)`)
+
+// Fragment is a reusable, ordered set of tasks with their own internal flow.
+//
+// Define a fragment once (for example in a shared package) and embed it into
+// any number of workflows with Workflow.Include. Tasks inside a fragment can
+// reference each other with Field(), Then() and DependsOn() as usual.
+//
+// Example:
+//
+//	func Billing() *workflow.Fragment {
+//	    invoice := workflow.HttpCallTask("invoice",
+//	        workflow.WithHTTPPost(),
+//	        workflow.WithURI("https://billing.example.com/invoices"),
+//	    )
+//	    charge := workflow.HttpCallTask("charge",
+//	        workflow.WithHTTPPost(),
+//	        workflow.WithURI("https://billing.example.com/charges"),
+//	        workflow.WithBody(map[string]any{"invoice": invoice.Field("id")}),
+//	    )
+//	    return workflow.NewFragment("billing", invoice, charge)
+//	}
+type Fragment struct {
+	// Name identifies the fragment in error messages and docs
+	Name string
+
+	// Tasks are the fragment's top-level tasks, in execution order
+	Tasks []*Task
+}
+
+// NewFragment creates a fragment from tasks, in execution order.
+func NewFragment(name string, tasks ...*Task) *Fragment {
+	return &Fragment{Name: name, Tasks: tasks}
+}
+
+// AddTask appends a task to the fragment.
+func (f *Fragment) AddTask(task *Task) *Fragment {
+	f.Tasks = append(f.Tasks, task)
+	return f
+}
+
+// IncludeOption configures how Workflow.Include embeds a fragment.
+type IncludeOption func(*includeConfig)
+
+// includeConfig holds the options for a single Include call.
+type includeConfig struct {
+	prefix string
+}
+
+// WithPrefix prepends prefix to the name of every task in the fragment, so the
+// same fragment can be included more than once in a workflow.
+//
+// Example:
+//
+//	wf.Include(Billing(), workflow.WithPrefix("billing-"))  // Tasks "billing-invoice", "billing-charge"
+func WithPrefix(prefix string) IncludeOption {
+	return func(c *includeConfig) {
+		c.prefix = prefix
+	}
+}
+
+// Include appends a copy of the fragment's tasks to the workflow and returns
+// the copies, in order.
+//
+// With WithPrefix, every task in the fragment (including nested tasks) is
+// renamed, and references between fragment tasks are rewired to the new names:
+// Then() targets, dependencies, compensations and Field() references in task
+// configurations. References to tasks outside the fragment are left unchanged.
+//
+// The fragment itself is not modified, so it can be included again.
+//
+// Example:
+//
+//	wf.HttpGet("fetchOrder", orderURL)
+//	tasks := wf.Include(Billing(), workflow.WithPrefix("billing-"))
+//	wf.SetVars("done", "chargeId", tasks[1].Field("id"))
+func (w *Workflow) Include(f *Fragment, opts ...IncludeOption) []*Task {
+	cfg := &includeConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	// Collect every task name defined by the fragment so only internal
+	// references are renamed
+	internal := make(map[string]bool)
+	for _, task := range f.Tasks {
+		forFragmentTask(task, func(t *Task) { internal[t.Name] = true })
+	}
+	rename := func(name string) string {
+		if internal[name] {
+			return cfg.prefix + name
+		}
+		return name
+	}
+
+	copier := &taskCopier{
+		replace: func(s string) string {
+			if cfg.prefix == "" {
+				return s
+			}
+			return contextTaskRefRegex.ReplaceAllStringFunc(s, func(m string) string {
+				return "$context." + rename(m[len("$context."):])
+			})
+		},
+		fieldRef: func(ref TaskFieldRef) TaskFieldRef {
+			return TaskFieldRef{taskName: rename(ref.taskName), fieldName: ref.fieldName}
+		},
+	}
+
+	included := make([]*Task, 0, len(f.Tasks))
+	for _, task := range f.Tasks {
+		// The copier only rewrites expressions; names and flow targets are renamed here
+		cp := copyTask(task, copier)
+		forFragmentTask(cp, func(t *Task) {
+			t.Name = rename(t.Name)
+			t.ThenTask = rename(t.ThenTask)
+			for i, dep := range t.Dependencies {
+				t.Dependencies[i] = rename(dep)
+			}
+		})
+		w.AddTask(cp)
+		included = append(included, cp)
+	}
+	return included
+}
+
+// forFragmentTask calls fn for task and its nested tasks, including compensations.
+func forFragmentTask(task *Task, fn func(*Task)) {
+	fn(task)
+	for _, child := range nestedTasks(task) {
+		forFragmentTask(child, fn)
+	}
+}
@@ -0,0 +1,78 @@
+package workflow
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newBillingFragment(external *Task) *Fragment {
+	invoice := HttpCallTask("invoice",
+		WithHTTPPost(),
+		WithURI("https://billing.example.com/invoices"),
+		WithBody(map[string]any{"order": external.Field("id")}),
+	)
+	charge := HttpCallTask("charge",
+		WithHTTPPost(),
+		WithURI("${ $context.invoice.chargeUrl }"),
+		WithBody(map[string]any{"invoice": invoice.Field("id")}),
+	)
+	skip := SetTask("skip", SetVar("charged", "false"))
+	invoice.DependsOn(external).ThenRef(charge)
+	charge.DependsOn(invoice)
+	return NewFragment("billing", invoice, skip, charge)
+}
+
+// TestWorkflow_Include verifies fragment tasks are prefixed and internal references rewired.
+func TestWorkflow_Include(t *testing.T) {
+	order := HttpCallTask("order", WithURI("https://orders.example.com"))
+	fragment := newBillingFragment(order)
+
+	wf := &Workflow{}
+	wf.AddTask(order)
+	tasks := wf.Include(fragment, WithPrefix("billing-"))
+
+	if got, want := taskNames(wf.Tasks), []string{"order", "billing-invoice", "billing-skip", "billing-charge"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("task names = %v, want %v", got, want)
+	}
+	if len(tasks) != 3 || tasks[0] != wf.Tasks[1] {
+		t.Fatalf("Include() returned %v, want the included tasks", taskNames(tasks))
+	}
+
+	invoice, charge := tasks[0], tasks[2]
+	if invoice.ThenTask != "billing-charge" {
+		t.Errorf("invoice.ThenTask = %q, want billing-charge", invoice.ThenTask)
+	}
+	if !reflect.DeepEqual(invoice.Dependencies, []string{"order"}) {
+		t.Errorf("invoice.Dependencies = %v, want external dependency kept", invoice.Dependencies)
+	}
+	if !reflect.DeepEqual(charge.Dependencies, []string{"billing-invoice"}) {
+		t.Errorf("charge.Dependencies = %v, want [billing-invoice]", charge.Dependencies)
+	}
+
+	cfg := charge.Config.(*HttpCallTaskConfig)
+	if cfg.URI != "${ $context.billing-invoice.chargeUrl }" {
+		t.Errorf("charge URI = %q", cfg.URI)
+	}
+	if ref := cfg.Body["invoice"].(TaskFieldRef); ref.TaskName() != "billing-invoice" {
+		t.Errorf("charge body ref task = %q, want billing-invoice", ref.TaskName())
+	}
+	if ref := invoice.Config.(*HttpCallTaskConfig).Body["order"].(TaskFieldRef); ref.TaskName() != "order" {
+		t.Errorf("invoice body ref task = %q, want external order task", ref.TaskName())
+	}
+}
+
+// TestWorkflow_IncludeTwice verifies a fragment can be included with different prefixes.
+func TestWorkflow_IncludeTwice(t *testing.T) {
+	fragment := newBillingFragment(HttpCallTask("order", WithURI("https://orders.example.com")))
+
+	wf := &Workflow{}
+	wf.Include(fragment, WithPrefix("eu-"))
+	wf.Include(fragment, WithPrefix("us-"))
+
+	if got, want := taskNames(wf.Tasks), []string{"eu-invoice", "eu-skip", "eu-charge", "us-invoice", "us-skip", "us-charge"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("task names = %v, want %v", got, want)
+	}
+	if fragment.Tasks[0].Name != "invoice" || fragment.Tasks[0].ThenTask != "charge" {
+		t.Errorf("fragment was modified: %q then %q", fragment.Tasks[0].Name, fragment.Tasks[0].ThenTask)
+	}
+}
@@ -0,0 +1,399 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	workflowv1 "buf.build/gen/go/leftbin/stigmer/protocolbuffers/go/ai/stigmer/agentic/workflow/v1"
+)
+
+// protoTaskKindPrefix prefixes task kinds in the WorkflowTaskKind enum
+// (e.g., WORKFLOW_TASK_KIND_HTTP_CALL for HTTP_CALL).
+const protoTaskKindPrefix = "WORKFLOW_TASK_KIND_"
+
+// FromProto reconstructs a Workflow from its synthesized proto, for example a
+// workflow read back from workflow-manifest.pb.
+//
+// Tasks get typed configs (*HttpCallTaskConfig, *SwitchTaskConfig, ...) and
+// workflow settings carried as annotations (triggers, timeout, error policy,
+// task deadlines, idempotency keys, disabled state, rollout) are restored.
+//
+// Synthesis is not fully reversible:
+//   - context variables are already resolved to their values
+//   - HTTP query parameters are part of the URI
+//   - only the first catch block of a TRY task is kept, without error filters
+//   - dependencies are not recorded; tasks are returned in synthesized order
+//
+// The workflow is not registered with a context; use Context.Adopt for that.
+//
+// Example:
+//
+//	manifest := &workflowv1.WorkflowManifest{}
+//	if err := proto.Unmarshal(data, manifest); err != nil {
+//	    return err
+//	}
+//	for _, pw := range manifest.Workflows {
+//	    wf, err := workflow.FromProto(pw)
+//	    ...
+//	}
+func FromProto(pw *workflowv1.Workflow) (*Workflow, error) {
+	if pw == nil || pw.GetSpec() == nil {
+		return nil, fmt.Errorf("%w: workflow has no spec", ErrConversion)
+	}
+	spec := pw.GetSpec()
+
+	w := &Workflow{
+		Description: spec.GetDescription(),
+		Org:         pw.GetMetadata().GetOrg(),
+	}
+	if doc := spec.GetDocument(); doc != nil {
+		w.Document = Document{
+			DSL:         doc.GetDsl(),
+			Namespace:   doc.GetNamespace(),
+			Name:        doc.GetName(),
+			Version:     doc.GetVersion(),
+			Description: doc.GetDescription(),
+		}
+	}
+
+	for i, pt := range spec.GetTasks() {
+		task, err := taskFromMap(map[string]any{
+			"name":        pt.GetName(),
+			"kind":        pt.GetKind().String(),
+			"task_config": pt.GetTaskConfig().AsMap(),
+			"export":      map[string]any{"as": pt.GetExport().GetAs()},
+			"flow":        map[string]any{"then": pt.GetFlow().GetThen()},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("task[%d] %s: %w", i, pt.GetName(), err)
+		}
+		w.Tasks = append(w.Tasks, task)
+	}
+
+	if err := annotationsFromProto(w, pw.GetMetadata().GetAnnotations()); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// annotationsFromProto restores workflow settings carried as annotations.
+func annotationsFromProto(w *Workflow, annotations map[string]string) error {
+	if v := annotations[AnnotationTriggers]; v != "" {
+		if err := json.Unmarshal([]byte(v), &w.Triggers); err != nil {
+			return fmt.Errorf("%w: decoding triggers: %v", ErrConversion, err)
+		}
+	}
+	w.Timeout = annotations[AnnotationTimeout]
+
+	if v := annotations[AnnotationErrorPolicy]; v != "" {
+		w.ErrorPolicy = &ErrorPolicy{}
+		if err := json.Unmarshal([]byte(v), w.ErrorPolicy); err != nil {
+			return fmt.Errorf("%w: decoding error policy: %v", ErrConversion, err)
+		}
+	}
+
+	w.Disabled, _ = strconv.ParseBool(annotations[AnnotationDisabled])
+	w.DisabledReason = annotations[AnnotationDisabledReason]
+
+	if v := annotations[AnnotationRollout]; v != "" {
+		w.Rollout = &Rollout{}
+		if err := json.Unmarshal([]byte(v), w.Rollout); err != nil {
+			return fmt.Errorf("%w: decoding rollout: %v", ErrConversion, err)
+		}
+	}
+
+	if v := annotations[AnnotationResources]; v != "" {
+		if err := json.Unmarshal([]byte(v), &w.Resources); err != nil {
+			return fmt.Errorf("%w: decoding resources: %v", ErrConversion, err)
+		}
+	}
+
+	var deadlines, idempotencyKeys map[string]string
+	if v := annotations[AnnotationTaskDeadlines]; v != "" {
+		if err := json.Unmarshal([]byte(v), &deadlines); err != nil {
+			return fmt.Errorf("%w: decoding task deadlines: %v", ErrConversion, err)
+		}
+	}
+	if v := annotations[AnnotationIdempotencyKeys]; v != "" {
+		if err := json.Unmarshal([]byte(v), &idempotencyKeys); err != nil {
+			return fmt.Errorf("%w: decoding idempotency keys: %v", ErrConversion, err)
+		}
+	}
+	for task := range w.AllTasks() {
+		task.Deadline = deadlines[task.Name]
+		task.IdempotencyKey = idempotencyKeys[task.Name]
+	}
+	return nil
+}
+
+// taskFromMap builds a task from its proto form: top-level tasks are passed
+// as the same map shape the synthesizer uses for nested tasks.
+func taskFromMap(m map[string]any) (*Task, error) {
+	kind := TaskKind(strings.TrimPrefix(mapString(m, "kind"), protoTaskKindPrefix))
+	task := &Task{
+		Name:     mapString(m, "name"),
+		Kind:     kind,
+		ExportAs: mapString(mapMap(m, "export"), "as"),
+		ThenTask: mapString(mapMap(m, "flow"), "then"),
+	}
+
+	cfg, err := taskConfigFromMap(kind, mapMap(m, "task_config"))
+	if err != nil {
+		return nil, err
+	}
+	task.Config = cfg
+	return task, nil
+}
+
+// taskConfigFromMap decodes the task_config struct of a task.
+func taskConfigFromMap(kind TaskKind, m map[string]any) (TaskConfig, error) {
+	switch kind {
+	case TaskKindSet:
+		return &SetTaskConfig{Variables: mapStrings(m, "variables")}, nil
+
+	case TaskKindHttpCall:
+		endpoint := mapMap(m, "endpoint")
+		cfg := &HttpCallTaskConfig{
+			Method:         mapString(m, "method"),
+			URI:            mapString(endpoint, "uri"),
+			Headers:        mapStrings(m, "headers"),
+			Body:           mapMap(m, "body"),
+			BodyEncoding:   BodyEncoding(mapString(m, "body_encoding")),
+			TimeoutSeconds: int32(mapNumber(m, "timeout_seconds")),
+			Proxy:          mapString(m, "proxy"),
+		}
+		if auth := mapMap(mapMap(endpoint, "authentication"), "oauth2"); auth != nil {
+			cfg.OAuth2 = &OAuth2Config{}
+			if err := decodeMap(auth, cfg.OAuth2); err != nil {
+				return nil, err
+			}
+		}
+		if follow, ok := m["follow_redirects"].(bool); ok {
+			cfg.FollowRedirects = &follow
+		}
+		if err := decodeOptional(m, "tls", &cfg.TLS); err != nil {
+			return nil, err
+		}
+		if err := decodeOptional(m, "retry_policy", &cfg.RetryPolicy); err != nil {
+			return nil, err
+		}
+		return cfg, nil
+
+	case TaskKindGrpcCall:
+		cfg := &GrpcCallTaskConfig{
+			Service:  mapString(m, "service"),
+			Method:   mapString(m, "method"),
+			Body:     mapMap(m, "body"),
+			Endpoint: mapString(m, "endpoint"),
+			Metadata: mapStrings(m, "metadata"),
+			Deadline: mapString(m, "deadline"),
+		}
+		if err := decodeOptional(m, "tls", &cfg.TLS); err != nil {
+			return nil, err
+		}
+		if err := decodeOptional(m, "retry_policy", &cfg.RetryPolicy); err != nil {
+			return nil, err
+		}
+		return cfg, nil
+
+	case TaskKindSwitch:
+		cfg := &SwitchTaskConfig{Cases: []SwitchCase{}}
+		for _, c := range mapList(m, "cases") {
+			cm, _ := c.(map[string]any)
+			if mapString(cm, "name") == "default" && mapString(cm, "when") == "" {
+				cfg.DefaultTask = mapString(cm, "then")
+				continue
+			}
+			cfg.Cases = append(cfg.Cases, SwitchCase{
+				Condition: mapString(cm, "when"),
+				Then:      mapString(cm, "then"),
+			})
+		}
+		return cfg, nil
+
+	case TaskKindFor:
+		do, err := tasksFromList(mapList(m, "do"))
+		if err != nil {
+			return nil, err
+		}
+		return &ForTaskConfig{In: mapString(m, "in"), Do: do}, nil
+
+	case TaskKindFork:
+		cfg := &ForkTaskConfig{}
+		cfg.Compete, _ = m["compete"].(bool)
+		for _, b := range mapList(m, "branches") {
+			bm, _ := b.(map[string]any)
+			tasks, err := tasksFromList(mapList(bm, "do"))
+			if err != nil {
+				return nil, err
+			}
+			cfg.Branches = append(cfg.Branches, ForkBranch{Name: mapString(bm, "name"), Tasks: tasks})
+		}
+		return cfg, nil
+
+	case TaskKindTry:
+		tasks, err := tasksFromList(mapList(m, "try"))
+		if err != nil {
+			return nil, err
+		}
+		cfg := &TryTaskConfig{Tasks: tasks}
+		if catch := mapMap(m, "catch"); catch != nil {
+			catchTasks, err := tasksFromList(mapList(catch, "do"))
+			if err != nil {
+				return nil, err
+			}
+			cfg.Catch = []CatchBlock{{As: mapString(catch, "as"), Tasks: catchTasks}}
+		}
+		return cfg, nil
+
+	case TaskKindListen:
+		return &ListenTaskConfig{
+			Event:                mapString(m, "event"),
+			Filter:               mapString(m, "filter"),
+			Correlation:          mapStrings(m, "correlation"),
+			Timeout:              mapString(m, "timeout"),
+			TimeoutThen:          mapString(m, "timeout_then"),
+			ImplicitDependencies: make(map[string]bool),
+		}, nil
+
+	case TaskKindWait:
+		return &WaitTaskConfig{
+			Duration:             mapString(m, "duration"),
+			Until:                mapString(m, "until"),
+			ImplicitDependencies: make(map[string]bool),
+		}, nil
+
+	case TaskKindCallActivity:
+		cfg := &CallActivityTaskConfig{
+			Activity:               mapString(m, "activity"),
+			Input:                  mapMap(m, "input"),
+			TaskQueue:              mapString(m, "task_queue"),
+			StartToCloseTimeout:    mapString(m, "start_to_close_timeout"),
+			ScheduleToCloseTimeout: mapString(m, "schedule_to_close_timeout"),
+			HeartbeatTimeout:       mapString(m, "heartbeat_timeout"),
+		}
+		if err := decodeOptional(m, "retry_policy", &cfg.RetryPolicy); err != nil {
+			return nil, err
+		}
+		return cfg, nil
+
+	case TaskKindRaise:
+		return &RaiseTaskConfig{
+			Error:   mapString(m, "error"),
+			Message: mapString(m, "message"),
+			Data:    mapMap(m, "data"),
+		}, nil
+
+	case TaskKindRun:
+		return &RunTaskConfig{
+			WorkflowName:      mapString(m, "workflow"),
+			WorkflowNamespace: mapString(m, "namespace"),
+			WorkflowVersion:   mapString(m, "version"),
+			Input:             mapMap(m, "input"),
+		}, nil
+
+	case TaskKindAgentCall:
+		cfg := &AgentCallTaskConfig{
+			Message:              mapString(m, "message"),
+			Env:                  mapStrings(m, "env"),
+			Input:                mapMap(m, "input"),
+			ImplicitDependencies: make(map[string]bool),
+		}
+		if scope := mapString(m, "scope"); scope != "" {
+			cfg.Agent = AgentBySlug(mapString(m, "agent"), scope)
+		} else {
+			cfg.Agent = AgentBySlug(mapString(m, "agent"))
+		}
+		if err := decodeOptional(m, "config", &cfg.Config); err != nil {
+			return nil, err
+		}
+		return cfg, nil
+
+	default:
+		return nil, fmt.Errorf("%w: task kind %q is not supported", ErrConversion, kind)
+	}
+}
+
+// tasksFromList decodes a nested task list.
+func tasksFromList(list []any) ([]Task, error) {
+	tasks := make([]Task, 0, len(list))
+	for i, item := range list {
+		m, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%w: nested task[%d] is not an object", ErrConversion, i)
+		}
+		task, err := taskFromMap(m)
+		if err != nil {
+			return nil, fmt.Errorf("nested task[%d] %s: %w", i, mapString(m, "name"), err)
+		}
+		tasks = append(tasks, *task)
+	}
+	return tasks, nil
+}
+
+// mapString returns m[key] as a string, or "" if absent.
+func mapString(m map[string]any, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+// mapNumber returns m[key] as a number, or 0 if absent.
+func mapNumber(m map[string]any, key string) float64 {
+	n, _ := m[key].(float64)
+	return n
+}
+
+// mapMap returns m[key] as an object, or nil if absent.
+func mapMap(m map[string]any, key string) map[string]any {
+	v, _ := m[key].(map[string]any)
+	return v
+}
+
+// mapList returns m[key] as a list, or nil if absent.
+func mapList(m map[string]any, key string) []any {
+	v, _ := m[key].([]any)
+	return v
+}
+
+// mapStrings returns m[key] as a string map. Values resolved from context
+// variables may be numbers or booleans; they are kept in their JSON form.
+func mapStrings(m map[string]any, key string) map[string]string {
+	obj := mapMap(m, key)
+	if obj == nil {
+		return nil
+	}
+	result := make(map[string]string, len(obj))
+	for k, v := range obj {
+		if s, ok := v.(string); ok {
+			result[k] = s
+			continue
+		}
+		data, _ := json.Marshal(v)
+		result[k] = string(data)
+	}
+	return result
+}
+
+// decodeOptional decodes m[key] into *target when present.
+func decodeOptional[T any](m map[string]any, key string, target **T) error {
+	obj := mapMap(m, key)
+	if obj == nil {
+		return nil
+	}
+	*target = new(T)
+	return decodeMap(obj, *target)
+}
+
+// decodeMap decodes an object into a struct through its JSON tags.
+func decodeMap(m map[string]any, target any) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrConversion, err)
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("%w: %v", ErrConversion, err)
+	}
+	return nil
+}
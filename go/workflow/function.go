@@ -0,0 +1,217 @@
+package workflow
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// TaskKindFunctionCall calls a function registered with DefineFunction.
+//
+// FUNCTION_CALL tasks have no dedicated engine task kind: they are lowered to
+// a copy of the function's HTTP_CALL or GRPC_CALL task. In Serverless Workflow
+// YAML the function is written once under use.functions and called by name.
+const TaskKindFunctionCall TaskKind = "FUNCTION_CALL"
+
+func init() {
+	if err := RegisterTaskKind(TaskKindFunctionCall, lowerFunctionCallTask); err != nil {
+		panic(err)
+	}
+}
+
+var (
+	functionsMu sync.RWMutex
+	functions   = make(map[string]TaskConfig)
+)
+
+// DefineFunction registers a reusable call, so an endpoint used by several
+// tasks (and workflows) is defined once. Tasks call it with FunctionCallTask
+// or Workflow.CallFunction.
+//
+// The config must be an *HttpCallTaskConfig or a *GrpcCallTaskConfig, and is
+// validated like the corresponding task. Defining a name again with a
+// different config returns an error. Workflow defaults (WithDefaults) don't
+// apply to functions: calls use the definition as given.
+//
+// Typically called from an init() function:
+//
+//	func init() {
+//	    billing := workflow.HttpCallTask("callBilling",
+//	        workflow.WithHTTPPost(),
+//	        workflow.WithURI("https://billing.example.com/v1/charges"),
+//	        workflow.WithHeader("Authorization", workflow.RuntimeSecret("BILLING_TOKEN")),
+//	    )
+//	    if err := workflow.DefineFunction("callBilling", billing.Config); err != nil {
+//	        panic(err)
+//	    }
+//	}
+func DefineFunction(name string, config TaskConfig) error {
+	if !expressionNameRegex.MatchString(name) {
+		return NewValidationErrorWithCause(
+			"name",
+			name,
+			"format",
+			"function name must be an identifier (letters, digits and underscores)",
+			ErrInvalidTaskConfig,
+		)
+	}
+
+	var kind TaskKind
+	switch config.(type) {
+	case *HttpCallTaskConfig:
+		kind = TaskKindHttpCall
+	case *GrpcCallTaskConfig:
+		kind = TaskKindGrpcCall
+	default:
+		return NewValidationErrorWithCause(
+			"config",
+			name,
+			"type",
+			fmt.Sprintf("function %q must be an HTTP or gRPC call, got %T", name, config),
+			ErrInvalidTaskConfig,
+		)
+	}
+	if err := validateTaskConfig(&Task{Name: name, Kind: kind, Config: config}); err != nil {
+		return fmt.Errorf("function %s: %w", name, err)
+	}
+
+	functionsMu.Lock()
+	defer functionsMu.Unlock()
+
+	if existing, ok := functions[name]; ok && !reflect.DeepEqual(existing, config) {
+		return NewValidationErrorWithCause(
+			"name",
+			name,
+			"unique",
+			fmt.Sprintf("function already defined: %q", name),
+			ErrInvalidTaskConfig,
+		)
+	}
+	functions[name] = config
+	return nil
+}
+
+// FunctionCallTaskConfig defines the configuration for FUNCTION_CALL tasks.
+type FunctionCallTaskConfig struct {
+	Function string `json:"function,omitempty"` // Name given to DefineFunction
+}
+
+func (*FunctionCallTaskConfig) isTaskConfig() {}
+
+// FunctionCallTask creates a task that calls a function registered with
+// DefineFunction. Calling a function that was never defined fails validation.
+//
+// Example:
+//
+//	charge := workflow.FunctionCallTask("chargeCustomer", "callBilling")
+//	refund := workflow.FunctionCallTask("refundCustomer", "callBilling")
+func FunctionCallTask(name, function string) *Task {
+	return &Task{
+		Name:         name,
+		Kind:         TaskKindFunctionCall,
+		Config:       &FunctionCallTaskConfig{Function: function},
+		Dependencies: []string{},
+	}
+}
+
+// CallFunction creates a task that calls a function registered with
+// DefineFunction and adds it to the workflow.
+//
+// Example:
+//
+//	charge := wf.CallFunction("chargeCustomer", "callBilling")
+//	wf.SetVars("record", "chargeId", charge.Field("id"))
+func (w *Workflow) CallFunction(name, function string) *Task {
+	task := FunctionCallTask(name, function)
+	w.AddTask(task)
+	return task
+}
+
+// lookupFunction returns the config registered under name.
+func lookupFunction(name string) (TaskConfig, error) {
+	functionsMu.RLock()
+	config, ok := functions[name]
+	functionsMu.RUnlock()
+	if !ok {
+		return nil, NewValidationErrorWithCause(
+			"config.function",
+			name,
+			"defined",
+			fmt.Sprintf("function not defined: %q (see DefineFunction)", name),
+			ErrInvalidTaskConfig,
+		)
+	}
+	return config, nil
+}
+
+// lowerFunctionCallTask converts a FUNCTION_CALL task to a copy of the
+// function's call task.
+func lowerFunctionCallTask(task *Task) (*Task, error) {
+	cfg, ok := task.Config.(*FunctionCallTaskConfig)
+	if !ok {
+		return nil, NewValidationErrorWithCause(
+			"config",
+			"",
+			"type",
+			"invalid config type for FUNCTION_CALL task",
+			ErrInvalidTaskConfig,
+		)
+	}
+	if cfg.Function == "" {
+		return nil, NewValidationErrorWithCause(
+			"config.function",
+			"",
+			"required",
+			"FUNCTION_CALL task must name a function",
+			ErrInvalidTaskConfig,
+		)
+	}
+	config, err := lookupFunction(cfg.Function)
+	if err != nil {
+		return nil, err
+	}
+
+	// Copy the config so synthesis never modifies the shared definition
+	switch def := config.(type) {
+	case *HttpCallTaskConfig:
+		c := *def
+		c.Headers = maps.Clone(def.Headers)
+		c.QueryParams = maps.Clone(def.QueryParams)
+		c.Body = maps.Clone(def.Body)
+		return &Task{Name: task.Name, Kind: TaskKindHttpCall, Config: &c, Dependencies: []string{}}, nil
+	case *GrpcCallTaskConfig:
+		c := *def
+		c.Body = maps.Clone(def.Body)
+		c.Metadata = maps.Clone(def.Metadata)
+		return &Task{Name: task.Name, Kind: TaskKindGrpcCall, Config: &c, Dependencies: []string{}}, nil
+	default:
+		return nil, NewValidationErrorWithCause("config.function", cfg.Function, "type", "function is not a call", ErrInvalidTaskConfig)
+	}
+}
+
+// usedFunctions returns the names of the functions the tasks (including
+// nested tasks) call, sorted.
+func usedFunctions(tasks []*Task) []string {
+	seen := make(map[string]bool)
+	var visit func(task *Task)
+	visit = func(task *Task) {
+		if cfg, ok := task.Config.(*FunctionCallTaskConfig); ok && cfg.Function != "" {
+			seen[cfg.Function] = true
+		}
+		for _, child := range nestedTasks(task) {
+			visit(child)
+		}
+	}
+	for _, task := range tasks {
+		visit(task)
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
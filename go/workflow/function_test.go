@@ -0,0 +1,106 @@
+package workflow
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestFunctionCallTask verifies calls to a shared function are lowered to
+// independent copies of its HTTP call.
+func TestFunctionCallTask(t *testing.T) {
+	billing := HttpCallTask("billing",
+		WithHTTPPost(),
+		WithURI("https://billing.example.com/v1/charges"),
+		WithHeader("Authorization", "Bearer ${ $env.BILLING_TOKEN }"),
+	)
+	if err := DefineFunction("fnCallBilling", billing.Config); err != nil {
+		t.Fatalf("DefineFunction() error = %v", err)
+	}
+	if err := DefineFunction("fnCallBilling", billing.Config); err != nil {
+		t.Errorf("redefining with the same config: error = %v", err)
+	}
+
+	charge := FunctionCallTask("charge", "fnCallBilling")
+	charge.ExportAs = "${ . }"
+	if err := validateTaskConfig(charge); err != nil {
+		t.Fatalf("validateTaskConfig() error = %v", err)
+	}
+
+	lowered, err := LowerTask(charge)
+	if err != nil {
+		t.Fatalf("LowerTask() error = %v", err)
+	}
+	if lowered.Kind != TaskKindHttpCall || lowered.Name != "charge" || lowered.ExportAs != "${ . }" {
+		t.Errorf("lowered = %s %s export %q, want HTTP_CALL charge export ${ . }", lowered.Kind, lowered.Name, lowered.ExportAs)
+	}
+	cfg := lowered.Config.(*HttpCallTaskConfig)
+	if cfg.Method != "POST" || cfg.URI != "https://billing.example.com/v1/charges" {
+		t.Errorf("lowered config = %s %s", cfg.Method, cfg.URI)
+	}
+	cfg.Headers["X-Trace"] = "1"
+	if _, ok := billing.Config.(*HttpCallTaskConfig).Headers["X-Trace"]; ok {
+		t.Error("lowered task shares headers with the function definition")
+	}
+}
+
+func TestDefineFunction_Errors(t *testing.T) {
+	if err := DefineFunction("fnConflict", HttpCallTask("a", WithHTTPGet(), WithURI("https://a.example.com")).Config); err != nil {
+		t.Fatalf("DefineFunction() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		fnName string
+		config TaskConfig
+	}{
+		{"invalid name", "call-billing", HttpCallTask("a", WithHTTPGet(), WithURI("https://a.example.com")).Config},
+		{"not a call", "fnSet", SetTask("a", SetVar("x", "1")).Config},
+		{"invalid config", "fnNoURI", HttpCallTask("a", WithHTTPGet()).Config},
+		{"conflicting definition", "fnConflict", HttpCallTask("a", WithHTTPGet(), WithURI("https://b.example.com")).Config},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := DefineFunction(tt.fnName, tt.config)
+			if !errors.Is(err, ErrInvalidTaskConfig) {
+				t.Errorf("DefineFunction() error = %v, want ErrInvalidTaskConfig", err)
+			}
+		})
+	}
+
+	if err := validateTaskConfig(FunctionCallTask("charge", "fnUndefined")); err == nil || !strings.Contains(err.Error(), "function not defined") {
+		t.Errorf("calling an undefined function: error = %v", err)
+	}
+}
+
+// TestMarshalYAML_Functions verifies a function called by several tasks is
+// written once under use.functions.
+func TestMarshalYAML_Functions(t *testing.T) {
+	if err := DefineFunction("fnNotify", HttpCallTask("notify", WithHTTPPost(), WithURI("https://hooks.example.com/notify")).Config); err != nil {
+		t.Fatalf("DefineFunction() error = %v", err)
+	}
+
+	wf := &Workflow{
+		Document: Document{DSL: "1.0.0", Namespace: "ops", Name: "alerts", Version: "1.0.0"},
+	}
+	wf.CallFunction("first", "fnNotify")
+	wf.AddTask(ForTask("each", WithIn("${ .items }"), WithDo(FunctionCallTask("again", "fnNotify"))))
+
+	data, err := MarshalYAML(wf)
+	if err != nil {
+		t.Fatalf("MarshalYAML() error = %v", err)
+	}
+	got := string(data)
+	for _, want := range []string{
+		"use:\n  functions:\n    fnNotify:\n      call: http\n",
+		"endpoint: https://hooks.example.com/notify",
+		"- first:\n      call: fnNotify\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("YAML missing %q:\n%s", want, got)
+		}
+	}
+	if n := strings.Count(got, "hooks.example.com"); n != 1 {
+		t.Errorf("endpoint written %d times, want 1:\n%s", n, got)
+	}
+}
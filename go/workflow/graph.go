@@ -0,0 +1,305 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ToMermaid renders the workflow's task graph as a Mermaid flowchart.
+//
+// Tasks are shown in the order they will run (see OrderTasks). Switch cases are
+// labelled with their conditions, and FOR, FORK and TRY bodies are drawn as
+// nested subgraphs; catch handlers are linked with dashed edges. The output can
+// be pasted into Markdown docs or pull requests that render Mermaid.
+//
+// Example:
+//
+//	fmt.Println("```mermaid\n" + wf.ToMermaid() + "```")
+func (w *Workflow) ToMermaid() string {
+	g := buildTaskGraph(w)
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+	g.writeMermaidCluster(&b, g.root, "    ")
+	for _, e := range g.edges {
+		arrow := "-->"
+		if e.dashed {
+			arrow = "-.->"
+		}
+		if e.label != "" {
+			fmt.Fprintf(&b, "    %s %s|%s| %s\n", e.from, arrow, mermaidQuote(e.label), e.to)
+		} else {
+			fmt.Fprintf(&b, "    %s %s %s\n", e.from, arrow, e.to)
+		}
+	}
+	return b.String()
+}
+
+// ToDOT renders the workflow's task graph in Graphviz DOT format.
+//
+// The graph has the same nodes, edges and clusters as ToMermaid.
+//
+// Example:
+//
+//	os.WriteFile("workflow.dot", []byte(wf.ToDOT()), 0o644)
+func (w *Workflow) ToDOT() string {
+	g := buildTaskGraph(w)
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %s {\n", dotQuote(w.Document.Name))
+	b.WriteString("    node [shape=box];\n")
+	g.writeDOTCluster(&b, g.root, "    ")
+	for _, e := range g.edges {
+		var attrs []string
+		if e.label != "" {
+			attrs = append(attrs, "label="+dotQuote(e.label))
+		}
+		if e.dashed {
+			attrs = append(attrs, "style=dashed")
+		}
+		if len(attrs) > 0 {
+			fmt.Fprintf(&b, "    %s -> %s [%s];\n", e.from, e.to, strings.Join(attrs, ", "))
+		} else {
+			fmt.Fprintf(&b, "    %s -> %s;\n", e.from, e.to)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Node shapes used by the graph renderers.
+const (
+	graphShapeTask      = "task"      // plain task
+	graphShapeSwitch    = "switch"    // SWITCH decision
+	graphShapeContainer = "container" // FOR, FORK and TRY
+	graphShapeTerminal  = "terminal"  // start and end markers
+)
+
+// Ids of the start and end markers. Task nodes are numbered (n1, n2, ...), so
+// task names never clash with these or with renderer keywords.
+const (
+	graphStartID = "start"
+	graphEndID   = "done"
+)
+
+// taskGraph is the renderer-independent form of a workflow's task graph.
+type taskGraph struct {
+	root     *graphCluster
+	edges    []graphEdge
+	nodes    int
+	clusters int
+}
+
+// graphCluster groups the nodes of one task list.
+type graphCluster struct {
+	id       string
+	label    string
+	nodes    []graphNode
+	clusters []*graphCluster
+}
+
+type graphNode struct {
+	id    string
+	label string
+	shape string
+}
+
+type graphEdge struct {
+	from   string
+	to     string
+	label  string
+	dashed bool
+}
+
+// buildTaskGraph lays out the workflow's tasks, nested lists and jumps.
+func buildTaskGraph(w *Workflow) *taskGraph {
+	g := &taskGraph{root: &graphCluster{}}
+	g.root.nodes = append(g.root.nodes,
+		graphNode{id: graphStartID, label: "start", shape: graphShapeTerminal},
+		graphNode{id: graphEndID, label: "end", shape: graphShapeTerminal},
+	)
+
+	tasks, err := OrderTasks(w.Tasks)
+	if err != nil {
+		// Cycles are reported by validation; draw the tasks as declared
+		tasks = w.Tasks
+	}
+	entry := graphEdge{from: graphStartID, to: graphEndID}
+	if first := g.addScope(g.root, tasks, true); first != "" {
+		entry.to = first
+	}
+	g.edges = append([]graphEdge{entry}, g.edges...)
+	return g
+}
+
+// addScope adds a task list to cluster and returns the id of its first task.
+// Falling off the end of the top-level list leads to the end marker; nested
+// lists return to their enclosing task, which is not drawn.
+func (g *taskGraph) addScope(cluster *graphCluster, tasks []*Task, top bool) string {
+	if len(tasks) == 0 {
+		return ""
+	}
+
+	ids := make(map[string]string, len(tasks))
+	order := make([]string, len(tasks))
+	for i, task := range tasks {
+		g.nodes++
+		id := fmt.Sprintf("n%d", g.nodes)
+		ids[task.Name] = id
+		order[i] = id
+		cluster.nodes = append(cluster.nodes, graphNode{id: id, label: task.Name, shape: graphShape(task)})
+	}
+
+	for i, task := range tasks {
+		from := order[i]
+		targets, fallThrough := flowTargets(task)
+		if fallThrough {
+			targets = append(targets, flowTarget{field: "then", name: flowContinue})
+		}
+		cases := map[string]string{}
+		if cfg, ok := task.Config.(*SwitchTaskConfig); ok {
+			for j, c := range cfg.Cases {
+				cases[fmt.Sprintf("cases[%d].then", j)] = c.Condition
+			}
+		}
+		for _, target := range targets {
+			label := ""
+			switch {
+			case target.field == "default":
+				label = "default"
+			case strings.HasPrefix(target.field, "cases["):
+				label = cases[target.field]
+			}
+
+			to := ""
+			switch target.name {
+			case flowExit:
+				to = graphEndID
+			case EndFlow:
+				if top {
+					to = graphEndID
+				}
+			case flowContinue:
+				if i+1 < len(tasks) {
+					to = order[i+1]
+				} else if top {
+					to = graphEndID
+				}
+			default:
+				// Dangling targets are reported by ValidateFlow
+				to = ids[target.name]
+			}
+			if to != "" {
+				g.edges = append(g.edges, graphEdge{from: from, to: to, label: label})
+			}
+		}
+
+		g.addNested(cluster, task, from)
+	}
+	return order[0]
+}
+
+// addNested draws the task lists nested in a FOR, FORK or TRY task as
+// sub-clusters linked from the task's node.
+func (g *taskGraph) addNested(cluster *graphCluster, task *Task, from string) {
+	nest := func(label, edgeLabel string, tasks []Task, dashed bool) {
+		if len(tasks) == 0 {
+			return
+		}
+		g.clusters++
+		child := &graphCluster{id: fmt.Sprintf("c%d", g.clusters), label: label}
+		cluster.clusters = append(cluster.clusters, child)
+
+		list := make([]*Task, len(tasks))
+		for i := range tasks {
+			list[i] = &tasks[i]
+		}
+		first := g.addScope(child, list, false)
+		g.edges = append(g.edges, graphEdge{from: from, to: first, label: edgeLabel, dashed: dashed})
+	}
+
+	switch cfg := task.Config.(type) {
+	case *ForTaskConfig:
+		nest(task.Name+": for each", "do", cfg.Do, false)
+	case *ForkTaskConfig:
+		for i, branch := range cfg.Branches {
+			name := branch.Name
+			if name == "" {
+				name = fmt.Sprintf("branch %d", i+1)
+			}
+			nest(task.Name+": "+name, name, branch.Tasks, false)
+		}
+	case *TryTaskConfig:
+		nest(task.Name+": try", "try", cfg.Tasks, false)
+		for _, catch := range cfg.Catch {
+			label := "catch"
+			if len(catch.Errors) > 0 {
+				label += " " + strings.Join(catch.Errors, ", ")
+			}
+			nest(task.Name+": "+label, label, catch.Tasks, true)
+		}
+	}
+}
+
+// graphShape picks the node shape for a task.
+func graphShape(task *Task) string {
+	switch task.Kind {
+	case TaskKindSwitch:
+		return graphShapeSwitch
+	case TaskKindFor, TaskKindFork, TaskKindTry:
+		return graphShapeContainer
+	default:
+		return graphShapeTask
+	}
+}
+
+func (g *taskGraph) writeMermaidCluster(b *strings.Builder, c *graphCluster, indent string) {
+	for _, n := range c.nodes {
+		label := mermaidQuote(n.label)
+		switch n.shape {
+		case graphShapeSwitch:
+			fmt.Fprintf(b, "%s%s{%s}\n", indent, n.id, label)
+		case graphShapeContainer:
+			fmt.Fprintf(b, "%s%s[[%s]]\n", indent, n.id, label)
+		case graphShapeTerminal:
+			fmt.Fprintf(b, "%s%s((%s))\n", indent, n.id, label)
+		default:
+			fmt.Fprintf(b, "%s%s[%s]\n", indent, n.id, label)
+		}
+	}
+	for _, child := range c.clusters {
+		fmt.Fprintf(b, "%ssubgraph %s [%s]\n", indent, child.id, mermaidQuote(child.label))
+		g.writeMermaidCluster(b, child, indent+"    ")
+		fmt.Fprintf(b, "%send\n", indent)
+	}
+}
+
+func (g *taskGraph) writeDOTCluster(b *strings.Builder, c *graphCluster, indent string) {
+	for _, n := range c.nodes {
+		attrs := "label=" + dotQuote(n.label)
+		switch n.shape {
+		case graphShapeSwitch:
+			attrs += ", shape=diamond"
+		case graphShapeContainer:
+			attrs += ", peripheries=2"
+		case graphShapeTerminal:
+			attrs += ", shape=circle"
+		}
+		fmt.Fprintf(b, "%s%s [%s];\n", indent, n.id, attrs)
+	}
+	for _, child := range c.clusters {
+		fmt.Fprintf(b, "%ssubgraph cluster_%s {\n", indent, child.id)
+		fmt.Fprintf(b, "%s    label=%s;\n", indent, dotQuote(child.label))
+		g.writeDOTCluster(b, child, indent+"    ")
+		fmt.Fprintf(b, "%s}\n", indent)
+	}
+}
+
+// mermaidQuote quotes a label for Mermaid, escaping embedded double quotes.
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
+
+// dotQuote quotes a label or id for DOT.
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
@@ -0,0 +1,91 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+)
+
+// graphTestWorkflow builds a workflow with a switch, a fork and a try/catch.
+func graphTestWorkflow() *Workflow {
+	w := &Workflow{Document: Document{Name: "orders"}}
+	w.AddTasks(
+		HttpCallTask("fetch", WithURI("https://api.example.com/orders")),
+		SwitchTask("route",
+			WithCase(`${ .status == "ok" }`, "process"),
+			WithDefault("fail"),
+		),
+		ForkTask("process",
+			WithBranch("billing", SetTask("bill", SetVar("billed", "true"))),
+			WithBranch("shipping", SetTask("ship", SetVar("shipped", "true"))),
+		).End(),
+		TryTask("fail",
+			WithTry(HttpCallTask("notify", WithURI("https://hooks.example.com"))),
+			WithCatch([]string{"NetworkError"}, "err", SetTask("log", SetVar("logged", "true"))),
+		),
+	)
+	return w
+}
+
+// TestToMermaid verifies the Mermaid rendering of tasks, switch cases, fork branches and catch handlers.
+func TestToMermaid(t *testing.T) {
+	want := `flowchart TD
+    start(("start"))
+    done(("end"))
+    n1["fetch"]
+    n2{"route"}
+    n3[["process"]]
+    n4[["fail"]]
+    subgraph c1 ["process: billing"]
+        n5["bill"]
+    end
+    subgraph c2 ["process: shipping"]
+        n6["ship"]
+    end
+    subgraph c3 ["fail: try"]
+        n7["notify"]
+    end
+    subgraph c4 ["fail: catch NetworkError"]
+        n8["log"]
+    end
+    start --> n1
+    n1 --> n2
+    n2 -->|"${ .status == #quot;ok#quot; }"| n3
+    n2 -->|"default"| n4
+    n3 --> done
+    n3 -->|"billing"| n5
+    n3 -->|"shipping"| n6
+    n4 --> done
+    n4 -->|"try"| n7
+    n4 -.->|"catch NetworkError"| n8
+`
+	if got := graphTestWorkflow().ToMermaid(); got != want {
+		t.Errorf("ToMermaid() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// TestToDOT verifies the DOT rendering uses clusters, shapes and escaped labels.
+func TestToDOT(t *testing.T) {
+	got := graphTestWorkflow().ToDOT()
+
+	for _, want := range []string{
+		"digraph \"orders\" {\n",
+		`n2 [label="route", shape=diamond];`,
+		`n3 [label="process", peripheries=2];`,
+		"subgraph cluster_c1 {\n        label=\"process: billing\";\n        n5 [label=\"bill\"];\n    }",
+		`n2 -> n3 [label="${ .status == \"ok\" }"];`,
+		`n4 -> n8 [label="catch NetworkError", style=dashed];`,
+		"start -> n1;",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ToDOT() missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+// TestToMermaid_Empty verifies an empty workflow renders as start to end.
+func TestToMermaid_Empty(t *testing.T) {
+	got := (&Workflow{}).ToMermaid()
+	if !strings.HasSuffix(got, "    start --> done\n") {
+		t.Errorf("ToMermaid() = %q, want start linked to end", got)
+	}
+}
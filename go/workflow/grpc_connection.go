@@ -0,0 +1,86 @@
+package workflow
+
+import (
+	"net"
+)
+
+// WithGrpcEndpoint sets the gRPC server address in host:port form.
+// Accepts either a string or a StringRef from context.
+//
+// Examples:
+//
+//	WithGrpcEndpoint("users.internal:443")
+//	WithGrpcEndpoint(ctx.SetString("usersEndpoint", "users.internal:443"))
+func WithGrpcEndpoint(endpoint interface{}) GrpcCallTaskOption {
+	return func(cfg *GrpcCallTaskConfig) {
+		cfg.Endpoint = toExpression(endpoint)
+	}
+}
+
+// WithGrpcMetadata adds gRPC metadata (the gRPC equivalent of HTTP headers).
+//
+// Example:
+//
+//	WithGrpcMetadata(map[string]string{
+//	    "authorization": workflow.Interpolate("Bearer ", workflow.RuntimeSecret("USERS_TOKEN")),
+//	})
+func WithGrpcMetadata(metadata map[string]string) GrpcCallTaskOption {
+	return func(cfg *GrpcCallTaskConfig) {
+		if cfg.Metadata == nil {
+			cfg.Metadata = make(map[string]string)
+		}
+		for k, v := range metadata {
+			cfg.Metadata[k] = v
+		}
+	}
+}
+
+// WithGrpcTLS enables TLS for the call.
+// Without options, the server certificate is verified against system roots.
+//
+// Examples:
+//
+//	WithGrpcTLS()                                                   // System roots
+//	WithGrpcTLS(workflow.TLSCACert(workflow.RuntimeSecret("CA")))   // Custom CA
+//	WithGrpcTLS(workflow.TLSServerName("users.internal"))
+func WithGrpcTLS(opts ...TLSOption) GrpcCallTaskOption {
+	return func(cfg *GrpcCallTaskConfig) {
+		if cfg.TLS == nil {
+			cfg.TLS = &TLSConfig{}
+		}
+		for _, opt := range opts {
+			opt(cfg.TLS)
+		}
+	}
+}
+
+// WithGrpcDeadline sets the call deadline.
+// Accepts seconds (int or IntRef), a time.Duration or DurationRef, duration helpers, or Ref types.
+//
+// Examples:
+//
+//	WithGrpcDeadline("30s")
+//	WithGrpcDeadline(workflow.Seconds(30))
+func WithGrpcDeadline(duration interface{}) GrpcCallTaskOption {
+	return func(cfg *GrpcCallTaskConfig) {
+		cfg.Deadline = toDuration(duration)
+	}
+}
+
+// validateGrpcEndpoint validates a static host:port endpoint. Expressions are not checked.
+func validateGrpcEndpoint(endpoint string) error {
+	if endpoint == "" || isExpression(endpoint) {
+		return nil
+	}
+	host, port, err := net.SplitHostPort(endpoint)
+	if err != nil || host == "" || port == "" {
+		return NewValidationErrorWithCause(
+			"config.endpoint",
+			endpoint,
+			"format",
+			"gRPC endpoint must be in host:port form",
+			ErrInvalidTaskConfig,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package workflow
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestGrpcConnectionOptions verifies endpoint, metadata, TLS and deadline options.
+func TestGrpcConnectionOptions(t *testing.T) {
+	task := GrpcCallTask("getUser",
+		WithGrpcEndpoint("users.internal:443"),
+		WithService("UserService"),
+		WithGrpcMethod("GetUser"),
+		WithGrpcMetadata(map[string]string{"x-tenant": "acme"}),
+		WithGrpcTLS(TLSCACert(RuntimeSecret("USERS_CA")), TLSServerName("users.internal")),
+		WithGrpcDeadline(Seconds(10)),
+	)
+	cfg := task.Config.(*GrpcCallTaskConfig)
+
+	if cfg.Endpoint != "users.internal:443" {
+		t.Errorf("Endpoint = %q", cfg.Endpoint)
+	}
+	if cfg.Metadata["x-tenant"] != "acme" {
+		t.Errorf("Metadata = %v", cfg.Metadata)
+	}
+	if cfg.TLS == nil || cfg.TLS.CACert != "${.secrets.USERS_CA}" || cfg.TLS.ServerName != "users.internal" {
+		t.Errorf("TLS = %+v", cfg.TLS)
+	}
+	if cfg.Deadline != "10s" {
+		t.Errorf("Deadline = %q, want %q", cfg.Deadline, "10s")
+	}
+	if err := validateTaskConfig(task); err != nil {
+		t.Errorf("validateTaskConfig() error = %v", err)
+	}
+}
+
+// TestWithGrpcEndpoint_Invalid verifies endpoints without a port are rejected.
+func TestWithGrpcEndpoint_Invalid(t *testing.T) {
+	task := GrpcCallTask("getUser",
+		WithGrpcEndpoint("users.internal"),
+		WithService("UserService"),
+		WithGrpcMethod("GetUser"),
+	)
+	if err := validateTaskConfig(task); !errors.Is(err, ErrInvalidTaskConfig) {
+		t.Errorf("validateTaskConfig() error = %v, want ErrInvalidTaskConfig", err)
+	}
+}
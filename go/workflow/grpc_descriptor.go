@@ -0,0 +1,87 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// GrpcCallFromMethod creates a GRPC_CALL task from a protobuf method descriptor.
+//
+// The service and method names are taken from the descriptor, and the request
+// body is validated against the request message fields at synthesis time, so
+// typos in field names are caught before deployment.
+//
+// Descriptors are available from generated Go code:
+//
+//	method := userv1.File_user_v1_service_proto.
+//	    Services().ByName("UserService").
+//	    Methods().ByName("GetUser")
+//
+//	task := workflow.GrpcCallFromMethod("getUser", method,
+//	    workflow.WithGrpcEndpoint("users.internal:443"),
+//	    workflow.WithGrpcBody(map[string]any{"user_id": "${.userId}"}),
+//	)
+func GrpcCallFromMethod(name string, method protoreflect.MethodDescriptor, opts ...GrpcCallTaskOption) *Task {
+	allOpts := append([]GrpcCallTaskOption{
+		WithService(string(method.Parent().FullName())),
+		WithGrpcMethod(string(method.Name())),
+		func(cfg *GrpcCallTaskConfig) {
+			cfg.RequestDescriptor = method.Input()
+		},
+	}, opts...)
+	return GrpcCallTask(name, allOpts...)
+}
+
+// ValidateBody checks the request body against the request message descriptor.
+// Does nothing if the task was not created with GrpcCallFromMethod.
+//
+// Field names may use either the proto name (user_id) or the JSON name (userId).
+// Values that are runtime expressions are not type-checked.
+func (cfg *GrpcCallTaskConfig) ValidateBody() error {
+	if cfg.RequestDescriptor == nil {
+		return nil
+	}
+	body, err := cfg.ResolvedBody()
+	if err != nil {
+		return err
+	}
+	return validateMessageFields("body", body, cfg.RequestDescriptor)
+}
+
+// validateMessageFields validates a map against a message descriptor, recursing
+// into nested messages.
+func validateMessageFields(path string, values map[string]any, desc protoreflect.MessageDescriptor) error {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		field := desc.Fields().ByJSONName(key)
+		if field == nil {
+			field = desc.Fields().ByName(protoreflect.Name(key))
+		}
+		if field == nil {
+			return NewValidationErrorWithCause(
+				path+"."+key,
+				key,
+				"field",
+				fmt.Sprintf("unknown field %q in message %s", key, desc.FullName()),
+				ErrInvalidTaskConfig,
+			)
+		}
+
+		// Recurse into nested messages (not maps or repeated fields)
+		if field.Kind() == protoreflect.MessageKind && !field.IsList() && !field.IsMap() {
+			if nested, ok := values[key].(map[string]any); ok {
+				if err := validateMessageFields(path+"."+key, nested, field.Message()); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package workflow
+
+import (
+	"errors"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// testUserServiceMethod builds a descriptor for:
+//
+//	message Address { string city = 1; }
+//	message GetUserRequest { string user_id = 1; Address address = 2; }
+//	service UserService { rpc GetUser(GetUserRequest) returns (GetUserRequest); }
+func testUserServiceMethod(t *testing.T) protoreflect.MethodDescriptor {
+	t.Helper()
+
+	stringField := func(name string, number int32) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(number),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+		}
+	}
+	userID := stringField("user_id", 1)
+	userID.JsonName = proto.String("userId")
+
+	fd := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("test/user.proto"),
+		Package: proto.String("test.user.v1"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name:  proto.String("Address"),
+				Field: []*descriptorpb.FieldDescriptorProto{stringField("city", 1)},
+			},
+			{
+				Name: proto.String("GetUserRequest"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					userID,
+					{
+						Name:     proto.String("address"),
+						JsonName: proto.String("address"),
+						Number:   proto.Int32(2),
+						Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+						Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+						TypeName: proto.String(".test.user.v1.Address"),
+					},
+				},
+			},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{
+			{
+				Name: proto.String("UserService"),
+				Method: []*descriptorpb.MethodDescriptorProto{
+					{
+						Name:       proto.String("GetUser"),
+						InputType:  proto.String(".test.user.v1.GetUserRequest"),
+						OutputType: proto.String(".test.user.v1.GetUserRequest"),
+					},
+				},
+			},
+		},
+	}
+
+	file, err := protodesc.NewFile(fd, nil)
+	if err != nil {
+		t.Fatalf("protodesc.NewFile() error = %v", err)
+	}
+	return file.Services().ByName("UserService").Methods().ByName("GetUser")
+}
+
+// TestGrpcCallFromMethod verifies service and method names come from the descriptor.
+func TestGrpcCallFromMethod(t *testing.T) {
+	task := GrpcCallFromMethod("getUser", testUserServiceMethod(t),
+		WithGrpcEndpoint("users.internal:443"),
+	)
+
+	cfg := task.Config.(*GrpcCallTaskConfig)
+	if cfg.Service != "test.user.v1.UserService" {
+		t.Errorf("Service = %q, want %q", cfg.Service, "test.user.v1.UserService")
+	}
+	if cfg.Method != "GetUser" {
+		t.Errorf("Method = %q, want %q", cfg.Method, "GetUser")
+	}
+	if cfg.Endpoint != "users.internal:443" {
+		t.Errorf("Endpoint = %q, want %q", cfg.Endpoint, "users.internal:443")
+	}
+}
+
+// TestGrpcCallTaskConfig_ValidateBody verifies body keys are checked against the request message.
+func TestGrpcCallTaskConfig_ValidateBody(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    map[string]any
+		wantErr bool
+	}{
+		{
+			name: "proto field names",
+			body: map[string]any{"user_id": "u-1"},
+		},
+		{
+			name: "json field names",
+			body: map[string]any{"userId": "${ $context.id }"},
+		},
+		{
+			name: "nested message",
+			body: map[string]any{"address": map[string]any{"city": "Berlin"}},
+		},
+		{
+			name:    "unknown field",
+			body:    map[string]any{"user_name": "alice"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown nested field",
+			body:    map[string]any{"address": map[string]any{"zip": "10115"}},
+			wantErr: true,
+		},
+	}
+
+	method := testUserServiceMethod(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := GrpcCallFromMethod("getUser", method, WithGrpcBody(tt.body))
+			err := task.Config.(*GrpcCallTaskConfig).ValidateBody()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateBody() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidTaskConfig) {
+				t.Errorf("ValidateBody() error = %v, want ErrInvalidTaskConfig", err)
+			}
+		})
+	}
+}
+
+// TestGrpcCallTaskConfig_ValidateBody_NoDescriptor verifies tasks without a descriptor are not checked.
+func TestGrpcCallTaskConfig_ValidateBody_NoDescriptor(t *testing.T) {
+	task := GrpcCallTask("call",
+		WithService("test.user.v1.UserService"),
+		WithGrpcMethod("GetUser"),
+		WithGrpcBody(map[string]any{"anything": true}),
+	)
+	if err := task.Config.(*GrpcCallTaskConfig).ValidateBody(); err != nil {
+		t.Errorf("ValidateBody() error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,121 @@
+package workflow
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// OAuth2Config defines OAuth2 client credentials authentication for HTTP_CALL tasks.
+//
+// The access token is requested from TokenURL at runtime and sent as a bearer
+// token, so credentials never need to appear in request headers.
+type OAuth2Config struct {
+	ClientID     string   `json:"client_id,omitempty"`     // OAuth2 client ID
+	ClientSecret string   `json:"client_secret,omitempty"` // OAuth2 client secret (prefer RuntimeSecret)
+	TokenURL     string   `json:"token_url,omitempty"`     // Token endpoint URL
+	Scopes       []string `json:"scopes,omitempty"`        // Optional scopes to request
+}
+
+// WithBearerToken sets the Authorization header to "Bearer <token>".
+// Accepts either a string or a Ref type.
+//
+// Runtime secrets stay as runtime expressions and are never baked into the manifest.
+//
+// Examples:
+//
+//	WithBearerToken(workflow.RuntimeSecret("API_TOKEN"))   // Resolved at execution time
+//	WithBearerToken(ctx.SetSecret("token", "..."))         // Resolved at synthesis time
+func WithBearerToken(token interface{}) HttpCallTaskOption {
+	return func(cfg *HttpCallTaskConfig) {
+		cfg.Headers["Authorization"] = Interpolate("Bearer ", toExpression(token))
+	}
+}
+
+// WithBasicAuth sets the Authorization header to "Basic <base64(username:password)>".
+// Accepts either strings or Ref types for both username and password.
+//
+// If both values are known at synthesis time the header is encoded immediately.
+// Otherwise a JQ expression is generated that encodes the credentials at runtime
+// with @base64.
+//
+// Examples:
+//
+//	WithBasicAuth("admin", workflow.RuntimeSecret("ADMIN_PASSWORD"))
+//	WithBasicAuth(ctx.SetString("user", "admin"), ctx.SetSecret("pass", "..."))
+func WithBasicAuth(username, password interface{}) HttpCallTaskOption {
+	return func(cfg *HttpCallTaskConfig) {
+		user := toExpression(username)
+		pass := toExpression(password)
+
+		if !isExpression(user) && !isExpression(pass) {
+			encoded := base64.StdEncoding.EncodeToString([]byte(user + ":" + pass))
+			cfg.Headers["Authorization"] = "Basic " + encoded
+			return
+		}
+
+		cfg.Headers["Authorization"] = fmt.Sprintf(
+			`${ "Basic " + ((%s + ":" + %s) | @base64) }`,
+			jqOperand(user),
+			jqOperand(pass),
+		)
+	}
+}
+
+// WithOAuth2 configures OAuth2 client credentials authentication.
+// Accepts either strings or Ref types for the client ID, secret and token URL.
+//
+// Example:
+//
+//	workflow.HttpCallTask("fetchOrders",
+//	    workflow.WithHTTPGet(),
+//	    workflow.WithURI("https://api.example.com/orders"),
+//	    workflow.WithOAuth2(
+//	        "my-client",
+//	        workflow.RuntimeSecret("ORDERS_CLIENT_SECRET"),
+//	        "https://auth.example.com/oauth/token",
+//	        "orders:read",
+//	    ),
+//	)
+func WithOAuth2(clientID, clientSecret, tokenURL interface{}, scopes ...string) HttpCallTaskOption {
+	return func(cfg *HttpCallTaskConfig) {
+		cfg.OAuth2 = &OAuth2Config{
+			ClientID:     toExpression(clientID),
+			ClientSecret: toExpression(clientSecret),
+			TokenURL:     toExpression(tokenURL),
+			Scopes:       scopes,
+		}
+	}
+}
+
+// validateOAuth2Config validates OAuth2 authentication settings.
+func validateOAuth2Config(auth *OAuth2Config) error {
+	required := []struct {
+		field string
+		value string
+	}{
+		{"config.oauth2.client_id", auth.ClientID},
+		{"config.oauth2.client_secret", auth.ClientSecret},
+		{"config.oauth2.token_url", auth.TokenURL},
+	}
+	for _, r := range required {
+		if r.value == "" {
+			return NewValidationErrorWithCause(
+				r.field,
+				"",
+				"required",
+				"OAuth2 authentication must have a client ID, client secret and token URL",
+				ErrInvalidTaskConfig,
+			)
+		}
+	}
+	return nil
+}
+
+// jqOperand converts a value to a JQ operand: expressions are unwrapped,
+// static strings are quoted.
+func jqOperand(value string) string {
+	if isExpression(value) {
+		return "(" + expressionBody(value) + ")"
+	}
+	return fmt.Sprintf("%q", value)
+}
@@ -0,0 +1,86 @@
+package workflow
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestWithBearerToken verifies the Authorization header for static and runtime tokens.
+func TestWithBearerToken(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    interface{}
+		expected string
+	}{
+		{
+			name:     "static token",
+			token:    "abc123",
+			expected: "Bearer abc123",
+		},
+		{
+			name:     "runtime secret",
+			token:    RuntimeSecret("API_TOKEN"),
+			expected: `${ "Bearer " + .secrets.API_TOKEN }`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := HttpCallTask("call", WithBearerToken(tt.token))
+			cfg := task.Config.(*HttpCallTaskConfig)
+			if got := cfg.Headers["Authorization"]; got != tt.expected {
+				t.Errorf("Authorization = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestWithBasicAuth verifies static credentials are encoded and runtime secrets are encoded at runtime.
+func TestWithBasicAuth(t *testing.T) {
+	tests := []struct {
+		name     string
+		username interface{}
+		password interface{}
+		expected string
+	}{
+		{
+			name:     "static credentials",
+			username: "admin",
+			password: "secret",
+			expected: "Basic YWRtaW46c2VjcmV0",
+		},
+		{
+			name:     "runtime secret password",
+			username: "admin",
+			password: RuntimeSecret("ADMIN_PASSWORD"),
+			expected: `${ "Basic " + (("admin" + ":" + (.secrets.ADMIN_PASSWORD)) | @base64) }`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := HttpCallTask("call", WithBasicAuth(tt.username, tt.password))
+			cfg := task.Config.(*HttpCallTaskConfig)
+			if got := cfg.Headers["Authorization"]; got != tt.expected {
+				t.Errorf("Authorization = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestWithOAuth2_Validation verifies incomplete OAuth2 settings are rejected.
+func TestWithOAuth2_Validation(t *testing.T) {
+	task := HttpCallTask("call",
+		WithHTTPGet(),
+		WithURI("https://api.example.com"),
+		WithOAuth2("my-client", RuntimeSecret("CLIENT_SECRET"), ""),
+	)
+
+	err := validateTaskConfig(task)
+	if err == nil {
+		t.Fatal("expected validation error for missing token URL")
+	}
+	if !errors.Is(err, ErrInvalidTaskConfig) {
+		t.Errorf("error = %v, want ErrInvalidTaskConfig", err)
+	}
+}
@@ -0,0 +1,111 @@
+package workflow
+
+import "strings"
+
+// BodyEncoding determines how an HTTP_CALL request body is encoded.
+type BodyEncoding string
+
+// Supported HTTP body encodings.
+const (
+	BodyEncodingJSON      BodyEncoding = "json"
+	BodyEncodingForm      BodyEncoding = "form"
+	BodyEncodingMultipart BodyEncoding = "multipart"
+)
+
+// MultipartFile is a file part in a multipart/form-data request body.
+type MultipartFile struct {
+	Field       string `json:"field,omitempty"`        // Form field name
+	Filename    string `json:"filename,omitempty"`     // File name sent to the server
+	Content     string `json:"content,omitempty"`      // File content or expression (e.g., "${ .report.content }")
+	ContentType string `json:"content_type,omitempty"` // Optional MIME type (e.g., "application/pdf")
+}
+
+// WithFormBody sets an application/x-www-form-urlencoded request body.
+//
+// The Content-Type header is set automatically unless it was already set.
+//
+// Example:
+//
+//	workflow.HttpCallTask("login",
+//	    workflow.WithHTTPPost(),
+//	    workflow.WithURI("https://legacy.example.com/login"),
+//	    workflow.WithFormBody(map[string]string{
+//	        "username": "admin",
+//	        "password": workflow.RuntimeSecret("LEGACY_PASSWORD"),
+//	    }),
+//	)
+func WithFormBody(fields map[string]string) HttpCallTaskOption {
+	return func(cfg *HttpCallTaskConfig) {
+		body := make(map[string]any, len(fields))
+		for k, v := range fields {
+			body[k] = v
+		}
+		cfg.Body = body
+		cfg.BodyEncoding = BodyEncodingForm
+		setDefaultContentType(cfg, "application/x-www-form-urlencoded")
+	}
+}
+
+// WithMultipart sets a multipart/form-data request body with file parts and
+// plain form fields.
+//
+// The Content-Type header (including the boundary) is generated at runtime,
+// so any Content-Type header set on the task is removed.
+//
+// Example:
+//
+//	workflow.HttpCallTask("upload",
+//	    workflow.WithHTTPPost(),
+//	    workflow.WithURI("https://api.example.com/upload"),
+//	    workflow.WithMultipart(
+//	        []workflow.MultipartFile{{
+//	            Field:       "file",
+//	            Filename:    "report.pdf",
+//	            Content:     reportTask.Field("content").Expression(),
+//	            ContentType: "application/pdf",
+//	        }},
+//	        map[string]string{"description": "Monthly report"},
+//	    ),
+//	)
+func WithMultipart(files []MultipartFile, fields map[string]string) HttpCallTaskOption {
+	return func(cfg *HttpCallTaskConfig) {
+		fileParts := make([]any, len(files))
+		for i, f := range files {
+			part := map[string]any{
+				"field":    f.Field,
+				"filename": f.Filename,
+				"content":  f.Content,
+			}
+			if f.ContentType != "" {
+				part["content_type"] = f.ContentType
+			}
+			fileParts[i] = part
+		}
+
+		fieldParts := make(map[string]any, len(fields))
+		for k, v := range fields {
+			fieldParts[k] = v
+		}
+
+		cfg.Body = map[string]any{
+			"files":  fileParts,
+			"fields": fieldParts,
+		}
+		cfg.BodyEncoding = BodyEncodingMultipart
+		for k := range cfg.Headers {
+			if strings.EqualFold(k, "Content-Type") {
+				delete(cfg.Headers, k)
+			}
+		}
+	}
+}
+
+// setDefaultContentType sets the Content-Type header unless one is already present.
+func setDefaultContentType(cfg *HttpCallTaskConfig, contentType string) {
+	for k := range cfg.Headers {
+		if strings.EqualFold(k, "Content-Type") {
+			return
+		}
+	}
+	cfg.Headers["Content-Type"] = contentType
+}
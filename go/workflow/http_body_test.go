@@ -0,0 +1,67 @@
+package workflow
+
+import (
+	"testing"
+)
+
+// TestWithFormBody verifies form bodies set the encoding and a default Content-Type.
+func TestWithFormBody(t *testing.T) {
+	task := HttpCallTask("login",
+		WithHTTPPost(),
+		WithURI("https://legacy.example.com/login"),
+		WithFormBody(map[string]string{"username": "admin"}),
+	)
+	cfg := task.Config.(*HttpCallTaskConfig)
+
+	if cfg.BodyEncoding != BodyEncodingForm {
+		t.Errorf("BodyEncoding = %q, want %q", cfg.BodyEncoding, BodyEncodingForm)
+	}
+	if cfg.Body["username"] != "admin" {
+		t.Errorf("Body[username] = %v, want %q", cfg.Body["username"], "admin")
+	}
+	if got := cfg.Headers["Content-Type"]; got != "application/x-www-form-urlencoded" {
+		t.Errorf("Content-Type = %q, want %q", got, "application/x-www-form-urlencoded")
+	}
+}
+
+// TestWithFormBody_KeepsExplicitContentType verifies an explicit Content-Type is not overridden.
+func TestWithFormBody_KeepsExplicitContentType(t *testing.T) {
+	task := HttpCallTask("login",
+		WithHeader("content-type", "application/x-www-form-urlencoded; charset=utf-8"),
+		WithFormBody(map[string]string{"username": "admin"}),
+	)
+	cfg := task.Config.(*HttpCallTaskConfig)
+
+	if len(cfg.Headers) != 1 {
+		t.Errorf("Headers = %v, want only the explicit content-type", cfg.Headers)
+	}
+}
+
+// TestWithMultipart verifies multipart bodies carry files and fields without a static Content-Type.
+func TestWithMultipart(t *testing.T) {
+	task := HttpCallTask("upload",
+		WithHeader("Content-Type", "application/json"),
+		WithMultipart(
+			[]MultipartFile{{Field: "file", Filename: "report.pdf", Content: "${ .report }", ContentType: "application/pdf"}},
+			map[string]string{"description": "Monthly report"},
+		),
+	)
+	cfg := task.Config.(*HttpCallTaskConfig)
+
+	if cfg.BodyEncoding != BodyEncodingMultipart {
+		t.Errorf("BodyEncoding = %q, want %q", cfg.BodyEncoding, BodyEncodingMultipart)
+	}
+	if _, ok := cfg.Headers["Content-Type"]; ok {
+		t.Error("multipart body should remove static Content-Type header")
+	}
+
+	files := cfg.Body["files"].([]any)
+	file := files[0].(map[string]any)
+	if file["filename"] != "report.pdf" || file["content_type"] != "application/pdf" {
+		t.Errorf("file part = %v", file)
+	}
+	fields := cfg.Body["fields"].(map[string]any)
+	if fields["description"] != "Monthly report" {
+		t.Errorf("fields = %v", fields)
+	}
+}
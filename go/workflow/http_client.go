@@ -0,0 +1,118 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HttpClientScope creates HTTP_CALL tasks that share a base URL and default options
+// (headers, auth, timeouts). Create one with HttpClient or Workflow.HttpClient.
+type HttpClientScope struct {
+	baseURL interface{}
+	opts    []HttpCallTaskOption
+
+	// wf is set when the scope was created with Workflow.HttpClient; tasks are added to it
+	wf *Workflow
+}
+
+// HttpClient creates a scope for a group of tasks that call the same API.
+//
+// The base URL accepts a string or a Ref. The options (typically Header or
+// WithOAuth2) are applied to every task before the task's own options, so a
+// task can override them.
+//
+// Example:
+//
+//	billing := workflow.HttpClient("https://billing.example.com/v1",
+//	    workflow.Header("Authorization", token.Prepend("Bearer ")),
+//	)
+//	invoices := billing.Get("listInvoices", "/invoices")
+//	charge := billing.Post("charge", "/charges", workflow.WithBody(map[string]any{"amount": 100}))
+func HttpClient(baseURL interface{}, opts ...HttpCallTaskOption) *HttpClientScope {
+	return &HttpClientScope{baseURL: baseURL, opts: opts}
+}
+
+// HttpClient creates an HTTP client scope whose tasks are added to the workflow.
+// See the package-level HttpClient.
+//
+// Example:
+//
+//	billing := wf.HttpClient(billingURL, workflow.Header("Authorization", token))
+//	billing.Get("listInvoices", "/invoices")  // Added to wf
+func (w *Workflow) HttpClient(baseURL interface{}, opts ...HttpCallTaskOption) *HttpClientScope {
+	scope := HttpClient(baseURL, opts...)
+	scope.wf = w
+	return scope
+}
+
+// Get creates an HTTP GET task for path relative to the base URL.
+func (c *HttpClientScope) Get(name string, path interface{}, opts ...HttpCallTaskOption) *Task {
+	return c.request(name, WithHTTPGet(), path, opts)
+}
+
+// Post creates an HTTP POST task for path relative to the base URL.
+func (c *HttpClientScope) Post(name string, path interface{}, opts ...HttpCallTaskOption) *Task {
+	return c.request(name, WithHTTPPost(), path, opts)
+}
+
+// Put creates an HTTP PUT task for path relative to the base URL.
+func (c *HttpClientScope) Put(name string, path interface{}, opts ...HttpCallTaskOption) *Task {
+	return c.request(name, WithHTTPPut(), path, opts)
+}
+
+// Patch creates an HTTP PATCH task for path relative to the base URL.
+func (c *HttpClientScope) Patch(name string, path interface{}, opts ...HttpCallTaskOption) *Task {
+	return c.request(name, WithHTTPPatch(), path, opts)
+}
+
+// Delete creates an HTTP DELETE task for path relative to the base URL.
+func (c *HttpClientScope) Delete(name string, path interface{}, opts ...HttpCallTaskOption) *Task {
+	return c.request(name, WithHTTPDelete(), path, opts)
+}
+
+// request builds a task with the scope's options followed by the task's own options.
+func (c *HttpClientScope) request(name string, method HttpCallTaskOption, path interface{}, opts []HttpCallTaskOption) *Task {
+	allOpts := []HttpCallTaskOption{method, withScopedURI(c.baseURL, path)}
+	allOpts = append(allOpts, c.opts...)
+	allOpts = append(allOpts, opts...)
+
+	task := HttpCallTask(name, allOpts...)
+	if c.wf != nil {
+		c.wf.AddTask(task)
+	}
+	return task
+}
+
+// withScopedURI sets the URI to path joined onto baseURL, tracking task field
+// references in either part as dependencies.
+func withScopedURI(baseURL, path interface{}) HttpCallTaskOption {
+	return func(cfg *HttpCallTaskConfig) {
+		cfg.URI = joinURL(toExpression(baseURL), toExpression(path))
+		for _, part := range []interface{}{baseURL, path} {
+			if fieldRef, ok := part.(taskOutputRef); ok {
+				cfg.ImplicitDependencies[fieldRef.TaskName()] = true
+			}
+		}
+	}
+}
+
+// joinURL joins a base URL and a path with exactly one slash between them.
+// If either part is a runtime expression, a JQ string concatenation is returned.
+func joinURL(base, path string) string {
+	if path == "" {
+		return base
+	}
+	if !isExpression(base) && !isExpression(path) {
+		return strings.TrimRight(base, "/") + "/" + strings.TrimLeft(path, "/")
+	}
+
+	left := quoteLiteral(strings.TrimRight(base, "/"))
+	if isExpression(base) {
+		left = "(" + expressionBody(base) + ")"
+	}
+	right := quoteLiteral("/" + strings.TrimLeft(path, "/"))
+	if isExpression(path) {
+		right = `"/" + (` + expressionBody(path) + ")"
+	}
+	return fmt.Sprintf("${ %s + %s }", left, right)
+}
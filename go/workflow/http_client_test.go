@@ -0,0 +1,62 @@
+package workflow
+
+import "testing"
+
+// TestHttpClient_InheritsBaseURLAndHeaders verifies scoped tasks share the base URL and headers.
+func TestHttpClient_InheritsBaseURLAndHeaders(t *testing.T) {
+	api := HttpClient("https://api.example.com/v1/",
+		Header("Authorization", "Bearer token"),
+		Header("Accept", "application/json"),
+	)
+
+	get := api.Get("listUsers", "/users")
+	post := api.Post("createUser", "users", Header("Accept", "text/plain"))
+
+	getCfg := get.Config.(*HttpCallTaskConfig)
+	if getCfg.Method != "GET" || getCfg.URI != "https://api.example.com/v1/users" {
+		t.Errorf("Get = %s %s", getCfg.Method, getCfg.URI)
+	}
+	if getCfg.Headers["Authorization"] != "Bearer token" {
+		t.Errorf("Authorization header = %q", getCfg.Headers["Authorization"])
+	}
+
+	postCfg := post.Config.(*HttpCallTaskConfig)
+	if postCfg.Method != "POST" || postCfg.URI != "https://api.example.com/v1/users" {
+		t.Errorf("Post = %s %s", postCfg.Method, postCfg.URI)
+	}
+	if postCfg.Headers["Accept"] != "text/plain" {
+		t.Errorf("Accept header = %q, want task option to override client default", postCfg.Headers["Accept"])
+	}
+}
+
+// TestHttpClient_RuntimeURL verifies runtime base URLs and paths are concatenated as expressions.
+func TestHttpClient_RuntimeURL(t *testing.T) {
+	discover := HttpCallTask("discover", WithURI("https://registry.example.com"))
+	api := HttpClient(discover.Field("baseUrl"))
+
+	task := api.Delete("deleteUser", "${ $context.userId }")
+	cfg := task.Config.(*HttpCallTaskConfig)
+
+	want := `${ ($context.discover.baseUrl) + "/" + ($context.userId) }`
+	if cfg.URI != want {
+		t.Errorf("URI = %q, want %q", cfg.URI, want)
+	}
+	if len(task.Dependencies) != 1 || task.Dependencies[0] != "discover" {
+		t.Errorf("Dependencies = %v, want [discover]", task.Dependencies)
+	}
+}
+
+// TestWorkflow_HttpClient verifies tasks from a workflow-scoped client are added to the workflow.
+func TestWorkflow_HttpClient(t *testing.T) {
+	wf := &Workflow{}
+	api := wf.HttpClient("https://api.example.com")
+	api.Get("a", "/a")
+	api.Put("b", "/b")
+
+	if len(wf.Tasks) != 2 {
+		t.Fatalf("len(Tasks) = %d, want 2", len(wf.Tasks))
+	}
+	if got := wf.Tasks[1].Config.(*HttpCallTaskConfig).Method; got != "PUT" {
+		t.Errorf("Method = %q, want PUT", got)
+	}
+}
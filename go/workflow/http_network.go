@@ -0,0 +1,128 @@
+package workflow
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// TLSConfig defines TLS settings for calls to services with custom trust.
+type TLSConfig struct {
+	// InsecureSkipVerify disables server certificate verification.
+	// Only use this for internal services in development.
+	InsecureSkipVerify bool `json:"insecure_skip_verify,omitempty"`
+
+	// CACert is a PEM-encoded CA certificate (or expression) used to verify the server.
+	CACert string `json:"ca_cert,omitempty"`
+
+	// ServerName overrides the server name used for certificate verification.
+	ServerName string `json:"server_name,omitempty"`
+}
+
+// TLSOption is a functional option for configuring TLS settings.
+type TLSOption func(*TLSConfig)
+
+// TLSCACert sets a PEM-encoded CA certificate used to verify the server.
+// Accepts either a string or a Ref type.
+func TLSCACert(pem interface{}) TLSOption {
+	return func(cfg *TLSConfig) {
+		cfg.CACert = toExpression(pem)
+	}
+}
+
+// TLSInsecureSkipVerify disables server certificate verification.
+func TLSInsecureSkipVerify() TLSOption {
+	return func(cfg *TLSConfig) {
+		cfg.InsecureSkipVerify = true
+	}
+}
+
+// TLSServerName overrides the server name used for certificate verification.
+func TLSServerName(name string) TLSOption {
+	return func(cfg *TLSConfig) {
+		cfg.ServerName = name
+	}
+}
+
+// WithInsecureSkipVerify disables TLS certificate verification for the request.
+//
+// Example:
+//
+//	wf.HttpGet("health", "https://internal.dev.local/health",
+//	    workflow.WithInsecureSkipVerify(),
+//	)
+func WithInsecureSkipVerify() HttpCallTaskOption {
+	return func(cfg *HttpCallTaskConfig) {
+		if cfg.TLS == nil {
+			cfg.TLS = &TLSConfig{}
+		}
+		cfg.TLS.InsecureSkipVerify = true
+	}
+}
+
+// WithCACert sets a PEM-encoded CA certificate used to verify the server.
+// Accepts either a string or a Ref type.
+//
+// Examples:
+//
+//	WithCACert(caPEM)                                  // PEM string
+//	WithCACert(workflow.RuntimeSecret("INTERNAL_CA"))  // Resolved at execution time
+func WithCACert(pem interface{}) HttpCallTaskOption {
+	return func(cfg *HttpCallTaskConfig) {
+		if cfg.TLS == nil {
+			cfg.TLS = &TLSConfig{}
+		}
+		cfg.TLS.CACert = toExpression(pem)
+	}
+}
+
+// WithProxy routes the request through a proxy.
+// Accepts either a string or a Ref type.
+//
+// Example:
+//
+//	WithProxy("http://proxy.corp.example.com:3128")
+func WithProxy(proxyURL interface{}) HttpCallTaskOption {
+	return func(cfg *HttpCallTaskConfig) {
+		cfg.Proxy = toExpression(proxyURL)
+	}
+}
+
+// WithFollowRedirects controls whether HTTP redirects are followed.
+//
+// Example:
+//
+//	WithFollowRedirects(false)  // Return 3xx responses as-is
+func WithFollowRedirects(follow bool) HttpCallTaskOption {
+	return func(cfg *HttpCallTaskConfig) {
+		cfg.FollowRedirects = &follow
+	}
+}
+
+// validateProxyURL validates a static proxy URL. Expressions are not checked.
+func validateProxyURL(proxy string) error {
+	if proxy == "" || isExpression(proxy) {
+		return nil
+	}
+	u, err := url.Parse(proxy)
+	if err != nil || u.Host == "" {
+		return NewValidationErrorWithCause(
+			"config.proxy",
+			proxy,
+			"format",
+			"proxy must be an absolute URL (e.g., http://proxy:3128)",
+			ErrInvalidTaskConfig,
+		)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+		return nil
+	default:
+		return NewValidationErrorWithCause(
+			"config.proxy",
+			proxy,
+			"enum",
+			fmt.Sprintf("unsupported proxy scheme %q (must be http, https or socks5)", u.Scheme),
+			ErrInvalidTaskConfig,
+		)
+	}
+}
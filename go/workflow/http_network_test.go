@@ -0,0 +1,52 @@
+package workflow
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestHttpNetworkOptions verifies TLS, proxy and redirect options are set on the config.
+func TestHttpNetworkOptions(t *testing.T) {
+	task := HttpCallTask("internal",
+		WithHTTPGet(),
+		WithURI("https://internal.example.com"),
+		WithInsecureSkipVerify(),
+		WithCACert(RuntimeSecret("INTERNAL_CA")),
+		WithProxy("http://proxy.corp.example.com:3128"),
+		WithFollowRedirects(false),
+	)
+	cfg := task.Config.(*HttpCallTaskConfig)
+
+	if cfg.TLS == nil || !cfg.TLS.InsecureSkipVerify {
+		t.Error("TLS.InsecureSkipVerify should be true")
+	}
+	if cfg.TLS.CACert != "${.secrets.INTERNAL_CA}" {
+		t.Errorf("TLS.CACert = %q, want runtime secret reference", cfg.TLS.CACert)
+	}
+	if cfg.Proxy != "http://proxy.corp.example.com:3128" {
+		t.Errorf("Proxy = %q", cfg.Proxy)
+	}
+	if cfg.FollowRedirects == nil || *cfg.FollowRedirects {
+		t.Error("FollowRedirects should be false")
+	}
+	if err := validateTaskConfig(task); err != nil {
+		t.Errorf("validateTaskConfig() error = %v", err)
+	}
+}
+
+// TestWithProxy_Invalid verifies unsupported proxy URLs are rejected.
+func TestWithProxy_Invalid(t *testing.T) {
+	tests := []string{"proxy:3128", "ftp://proxy:21"}
+	for _, proxy := range tests {
+		t.Run(proxy, func(t *testing.T) {
+			task := HttpCallTask("call",
+				WithHTTPGet(),
+				WithURI("https://api.example.com"),
+				WithProxy(proxy),
+			)
+			if err := validateTaskConfig(task); !errors.Is(err, ErrInvalidTaskConfig) {
+				t.Errorf("validateTaskConfig() error = %v, want ErrInvalidTaskConfig", err)
+			}
+		})
+	}
+}
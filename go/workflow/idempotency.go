@@ -0,0 +1,23 @@
+package workflow
+
+// WithIdempotencyKey sets a key the engine uses to dedupe this task when it is
+// retried or replayed, so side effects (payments, emails) happen at most once
+// per key. Works with every task kind.
+//
+// Accepts a string, an expression, a StringRef from context, or a TaskFieldRef.
+// Task field references also add an implicit dependency on the referenced task.
+//
+// Example:
+//
+//	wf.HttpPost("chargeCard", paymentsURL, ...).
+//	    WithIdempotencyKey("${ \"charge-\" + .input.orderId }")
+//
+//	wf.HttpPost("sendReceipt", mailURL, ...).
+//	    WithIdempotencyKey(chargeTask.Field("chargeId"))
+func (t *Task) WithIdempotencyKey(key interface{}) *Task {
+	if ref, ok := key.(taskOutputRef); ok {
+		t.dependsOnName(ref.TaskName())
+	}
+	t.IdempotencyKey = toExpression(key)
+	return t
+}
@@ -0,0 +1,21 @@
+package workflow
+
+import "testing"
+
+// TestWithIdempotencyKey verifies static and referenced idempotency keys.
+func TestWithIdempotencyKey(t *testing.T) {
+	charge := SetTask("charge", SetVar("charged", "true")).
+		WithIdempotencyKey(`${ "charge-" + .input.orderId }`)
+	if got := charge.IdempotencyKey; got != `${ "charge-" + .input.orderId }` {
+		t.Errorf("IdempotencyKey = %q", got)
+	}
+
+	receipt := SetTask("receipt", SetVar("sent", "true")).
+		WithIdempotencyKey(charge.Field("chargeId"))
+	if got := receipt.IdempotencyKey; got != "${ $context.charge.chargeId }" {
+		t.Errorf("IdempotencyKey = %q, want task field expression", got)
+	}
+	if len(receipt.Dependencies) != 1 || receipt.Dependencies[0] != "charge" {
+		t.Errorf("Dependencies = %v, want [charge]", receipt.Dependencies)
+	}
+}
@@ -0,0 +1,73 @@
+package workflow
+
+import "iter"
+
+// AllTasks returns an iterator over every task in the workflow, including tasks
+// nested inside FOR, FORK, and TRY (and CATCH) bodies and compensations (see
+// Compensate), followed by the workflow finalizers (see WithFinalizer).
+//
+// Tasks are visited depth-first in definition order: each task is yielded before
+// its nested tasks. The yielded pointers refer to the tasks stored in the workflow,
+// so changes made through them are reflected in the workflow.
+//
+// Example:
+//
+//	for task := range wf.AllTasks() {
+//	    if task.Kind == workflow.TaskKindHttpCall {
+//	        fmt.Println("HTTP task:", task.Name)
+//	    }
+//	}
+func (w *Workflow) AllTasks() iter.Seq[*Task] {
+	return func(yield func(*Task) bool) {
+		for _, task := range w.Tasks {
+			if !walkTask(task, yield) {
+				return
+			}
+		}
+		for _, task := range w.Finalizers {
+			if !walkTask(task, yield) {
+				return
+			}
+		}
+	}
+}
+
+// walkTask yields task and then its nested tasks depth-first.
+// Returns false if iteration was stopped by the caller.
+func walkTask(task *Task, yield func(*Task) bool) bool {
+	if !yield(task) {
+		return false
+	}
+	for _, child := range nestedTasks(task) {
+		if !walkTask(child, yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// nestedTasks returns pointers to the tasks directly nested in a task's config,
+// followed by its compensations. Returns nil for tasks that contain no other tasks.
+func nestedTasks(task *Task) []*Task {
+	var children []*Task
+	appendAll := func(tasks []Task) {
+		for i := range tasks {
+			children = append(children, &tasks[i])
+		}
+	}
+
+	switch cfg := task.Config.(type) {
+	case *ForTaskConfig:
+		appendAll(cfg.Do)
+	case *ForkTaskConfig:
+		for _, branch := range cfg.Branches {
+			appendAll(branch.Tasks)
+		}
+	case *TryTaskConfig:
+		appendAll(cfg.Tasks)
+		for _, catch := range cfg.Catch {
+			appendAll(catch.Tasks)
+		}
+	}
+	return append(children, task.Compensations...)
+}
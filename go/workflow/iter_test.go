@@ -0,0 +1,87 @@
+package workflow
+
+import (
+	"testing"
+)
+
+// TestAllTasks_DepthFirst verifies nested tasks are visited depth-first in definition order.
+func TestAllTasks_DepthFirst(t *testing.T) {
+	wf := &Workflow{
+		Tasks: []*Task{
+			SetTask("init", SetVar("x", "1")),
+			ForTask("loop",
+				WithIn("${.items}"),
+				WithDo(SetTask("loopBody", SetVar("y", "2"))),
+			),
+			ForkTask("parallel",
+				WithBranch("a", SetTask("branchA", SetVar("a", "1"))),
+				WithBranch("b", SetTask("branchB", SetVar("b", "1"))),
+			),
+			TryTask("attempt",
+				WithTry(HttpCallTask("call", WithHTTPGet(), WithURI("https://api.example.com"))),
+				WithCatch([]string{"NetworkError"}, "err",
+					SetTask("recover", SetVar("failed", "true")),
+				),
+			),
+		},
+	}
+
+	var got []string
+	for task := range wf.AllTasks() {
+		got = append(got, task.Name)
+	}
+
+	want := []string{"init", "loop", "loopBody", "parallel", "branchA", "branchB", "attempt", "call", "recover"}
+	if len(got) != len(want) {
+		t.Fatalf("AllTasks() visited %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("AllTasks()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+// TestAllTasks_EarlyBreak verifies iteration stops when the loop breaks.
+func TestAllTasks_EarlyBreak(t *testing.T) {
+	wf := &Workflow{
+		Tasks: []*Task{
+			ForTask("loop", WithDo(
+				SetTask("first", SetVar("a", "1")),
+				SetTask("second", SetVar("b", "2")),
+			)),
+			SetTask("after", SetVar("c", "3")),
+		},
+	}
+
+	count := 0
+	for task := range wf.AllTasks() {
+		count++
+		if task.Name == "first" {
+			break
+		}
+	}
+	if count != 2 {
+		t.Errorf("visited %d tasks before break, want 2", count)
+	}
+}
+
+// TestAllTasks_MutatesNested verifies yielded nested tasks point into the workflow.
+func TestAllTasks_MutatesNested(t *testing.T) {
+	wf := &Workflow{
+		Tasks: []*Task{
+			ForTask("loop", WithDo(SetTask("body", SetVar("a", "1")))),
+		},
+	}
+
+	for task := range wf.AllTasks() {
+		if task.Name == "body" {
+			task.ExportAs = "${.}"
+		}
+	}
+
+	cfg := wf.Tasks[0].Config.(*ForTaskConfig)
+	if cfg.Do[0].ExportAs != "${.}" {
+		t.Errorf("nested task ExportAs = %q, want %q", cfg.Do[0].ExportAs, "${.}")
+	}
+}
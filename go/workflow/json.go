@@ -0,0 +1,141 @@
+package workflow
+
+import (
+	"encoding/json"
+
+	"github.com/leftbin/stigmer-sdk/go/resources"
+)
+
+// This file provides a stable JSON view of workflows for logging and snapshot
+// tests. It is not the manifest format; use the synthesizer for that.
+//
+// Keys are snake_case, empty fields are omitted, and map keys are sorted by
+// encoding/json, so the output is deterministic for a given workflow.
+
+// taskJSON is the JSON view of a Task. The config is discriminated by kind.
+type taskJSON struct {
+	Name           string     `json:"name"`
+	Kind           TaskKind   `json:"kind"`
+	Config         TaskConfig `json:"config,omitempty"`
+	ExportAs       string     `json:"export_as,omitempty"`
+	ContextKey     string     `json:"context_key,omitempty"`
+	ExportedFields []string   `json:"exported_fields,omitempty"`
+	ThenTask       string     `json:"then,omitempty"`
+	Dependencies   []string   `json:"dependencies,omitempty"`
+	Compensations  []*Task    `json:"compensations,omitempty"`
+	Deadline       string     `json:"deadline,omitempty"`
+	IdempotencyKey string     `json:"idempotency_key,omitempty"`
+}
+
+// MarshalJSON returns a stable JSON view of the task.
+//
+// Example output:
+//
+//	{"name":"fetch","kind":"HTTP_CALL","config":{"method":"GET","uri":"https://api.example.com","timeout_seconds":30}}
+func (t Task) MarshalJSON() ([]byte, error) {
+	return json.Marshal(taskJSON{
+		Name:           t.Name,
+		Kind:           t.Kind,
+		Config:         t.Config,
+		ExportAs:       t.ExportAs,
+		ContextKey:     t.ContextKey,
+		ExportedFields: t.ExportedFields,
+		ThenTask:       t.ThenTask,
+		Dependencies:   t.Dependencies,
+		Compensations:  t.Compensations,
+		Deadline:       t.Deadline,
+		IdempotencyKey: t.IdempotencyKey,
+	})
+}
+
+// environmentVariableJSON is the JSON view of an environment variable.
+type environmentVariableJSON struct {
+	Name         string `json:"name"`
+	IsSecret     bool   `json:"is_secret,omitempty"`
+	Description  string `json:"description,omitempty"`
+	DefaultValue string `json:"default_value,omitempty"`
+	Required     bool   `json:"required,omitempty"`
+}
+
+// workflowJSON is the JSON view of a Workflow.
+type workflowJSON struct {
+	Document             Document                  `json:"document"`
+	Description          string                    `json:"description,omitempty"`
+	Org                  string                    `json:"org,omitempty"`
+	Triggers             []Trigger                 `json:"triggers,omitempty"`
+	Disabled             bool                      `json:"disabled,omitempty"`
+	DisabledReason       string                    `json:"disabled_reason,omitempty"`
+	Inputs               []InputParam              `json:"inputs,omitempty"`
+	Outputs              []string                  `json:"outputs,omitempty"`
+	Timeout              string                    `json:"timeout,omitempty"`
+	ErrorPolicy          *ErrorPolicy              `json:"error_policy,omitempty"`
+	Rollout              *Rollout                  `json:"rollout,omitempty"`
+	Resources            *resources.Requirements   `json:"resources,omitempty"`
+	Defaults             *TaskDefaults             `json:"defaults,omitempty"`
+	EnvironmentVariables []environmentVariableJSON `json:"environment_variables,omitempty"`
+	Tasks                []*Task                   `json:"tasks"`
+	Finalizers           []*Task                   `json:"finalizers,omitempty"`
+}
+
+// MarshalJSON returns a stable JSON view of the workflow, including nested tasks.
+// Secret environment variable default values are redacted.
+func (w *Workflow) MarshalJSON() ([]byte, error) {
+	view := workflowJSON{
+		Document:       w.Document,
+		Description:    w.Description,
+		Org:            w.Org,
+		Triggers:       w.Triggers,
+		Disabled:       w.Disabled,
+		DisabledReason: w.DisabledReason,
+		Inputs:         w.Inputs,
+		Outputs:        w.Outputs,
+		Timeout:        w.Timeout,
+		ErrorPolicy:    w.ErrorPolicy,
+		Rollout:        w.Rollout,
+		Defaults:       w.Defaults,
+		Tasks:          w.Tasks,
+		Finalizers:     w.Finalizers,
+	}
+	if !w.Resources.IsZero() {
+		view.Resources = &w.Resources
+	}
+	if view.Tasks == nil {
+		view.Tasks = []*Task{}
+	}
+	// Variables attached more than once (e.g., a context-level declaration) are listed once
+	seenEnv := make(map[string]bool, len(w.EnvironmentVariables))
+	for _, v := range w.EnvironmentVariables {
+		if seenEnv[v.Name] {
+			continue
+		}
+		seenEnv[v.Name] = true
+		envJSON := environmentVariableJSON{
+			Name:         v.Name,
+			IsSecret:     v.IsSecret,
+			Description:  v.Description,
+			DefaultValue: v.DefaultValue,
+			Required:     v.Required,
+		}
+		if v.IsSecret && envJSON.DefaultValue != "" {
+			envJSON.DefaultValue = "<redacted>"
+		}
+		view.EnvironmentVariables = append(view.EnvironmentVariables, envJSON)
+	}
+	return json.Marshal(view)
+}
+
+// MarshalJSON encodes the agent reference as {"slug": ..., "scope": ...}.
+func (r AgentRef) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Slug  string `json:"slug"`
+		Scope string `json:"scope,omitempty"`
+	}{
+		Slug:  r.slug,
+		Scope: r.scope,
+	})
+}
+
+// MarshalJSON encodes the field reference as its expression string.
+func (r TaskFieldRef) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.Expression())
+}
@@ -0,0 +1,165 @@
+package workflow
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/resources"
+)
+
+// TestTaskMarshalJSON verifies tasks encode with snake_case keys and a kind-discriminated config.
+func TestTaskMarshalJSON(t *testing.T) {
+	fetch := HttpCallTask("fetch",
+		WithHTTPGet(),
+		WithURI("https://api.example.com/items"),
+	)
+	process := SetTask("process", SetVar("title", fetch.Field("title")))
+
+	data, err := json.Marshal(process)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	want := `{"name":"process","kind":"SET","config":{"variables":{"title":"${ $context.fetch.title }"}},"dependencies":["fetch"]}`
+	if string(data) != want {
+		t.Errorf("json.Marshal() =\n%s\nwant\n%s", data, want)
+	}
+}
+
+// TestTaskMarshalJSON_Options verifies task and workflow options set outside the config are encoded.
+func TestTaskMarshalJSON_Options(t *testing.T) {
+	charge := HttpCallTask("charge", WithHTTPPost(), WithURI("https://api.example.com/charge")).
+		ExportTo("payment").
+		WithDeadline("30s").
+		WithIdempotencyKey("${ .orderId }").
+		Compensate(SetTask("refund", SetVar("refunded", "true")))
+
+	data, err := json.Marshal(charge)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var task map[string]interface{}
+	if err := json.Unmarshal(data, &task); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if task["context_key"] != "payment" || task["deadline"] != "30s" || task["idempotency_key"] != "${ .orderId }" {
+		t.Errorf("json.Marshal() = %s, want context_key, deadline and idempotency_key", data)
+	}
+	if comps, _ := task["compensations"].([]interface{}); len(comps) != 1 {
+		t.Errorf("compensations = %v, want [refund]", task["compensations"])
+	}
+
+	data, err = json.Marshal(HttpCallTask("fetch", WithHTTPGet(), WithURI("https://api.example.com")).ExportFields("count", "status"))
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var fetch struct {
+		ExportedFields []string `json:"exported_fields"`
+	}
+	if err := json.Unmarshal(data, &fetch); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if len(fetch.ExportedFields) != 2 || fetch.ExportedFields[0] != "count" || fetch.ExportedFields[1] != "status" {
+		t.Errorf("exported_fields = %v, want [count status]", fetch.ExportedFields)
+	}
+
+	wf := &Workflow{
+		Document:       Document{DSL: "1.0.0", Namespace: "test", Name: "options", Version: "1.0.0"},
+		Disabled:       true,
+		DisabledReason: "migration",
+		Timeout:        "1h",
+		ErrorPolicy:    &ErrorPolicy{MaxTotalRetries: 5},
+		Rollout:        &Rollout{Percent: 10},
+		Resources:      resources.Requirements{CPU: "500m"},
+		Defaults:       &TaskDefaults{TimeoutSeconds: 15, Headers: map[string]string{"X-Env": "${ $context.env }"}},
+		Tasks:          []*Task{charge},
+	}
+	data, err = json.Marshal(wf)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var view map[string]interface{}
+	if err := json.Unmarshal(data, &view); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if view["disabled"] != true || view["disabled_reason"] != "migration" || view["timeout"] != "1h" {
+		t.Errorf("json.Marshal() = %s, want disabled, disabled_reason and timeout", data)
+	}
+	if policy, _ := view["error_policy"].(map[string]interface{}); policy["max_total_retries"] != float64(5) {
+		t.Errorf("error_policy = %v, want max_total_retries 5", view["error_policy"])
+	}
+	if rollout, _ := view["rollout"].(map[string]interface{}); rollout["percent"] != float64(10) {
+		t.Errorf("rollout = %v, want percent 10", view["rollout"])
+	}
+	if res, _ := view["resources"].(map[string]interface{}); res["cpu"] != "500m" {
+		t.Errorf("resources = %v, want cpu 500m", view["resources"])
+	}
+	defaults, _ := view["defaults"].(map[string]interface{})
+	if headers, _ := defaults["headers"].(map[string]interface{}); defaults["timeout_seconds"] != float64(15) || headers["X-Env"] != "${ $context.env }" {
+		t.Errorf("defaults = %v, want timeout_seconds 15 and the X-Env header", view["defaults"])
+	}
+
+	// Unset options are omitted
+	data, err = json.Marshal(&Workflow{Document: wf.Document})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	view = nil
+	if err := json.Unmarshal(data, &view); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	for _, key := range []string{"rollout", "resources", "defaults"} {
+		if _, ok := view[key]; ok {
+			t.Errorf("json.Marshal() = %s, want no %s", data, key)
+		}
+	}
+}
+
+// TestWorkflowMarshalJSON_Stable verifies nested tasks are encoded and output is deterministic.
+func TestWorkflowMarshalJSON_Stable(t *testing.T) {
+	wf := &Workflow{
+		Document: Document{DSL: "1.0.0", Namespace: "test", Name: "nested", Version: "1.0.0"},
+		Tasks: []*Task{
+			ForTask("loop",
+				WithIn("${.items}"),
+				WithDo(HttpCallTask("post",
+					WithHTTPPost(),
+					WithURI("https://api.example.com"),
+					WithHeader("B", "2"),
+					WithHeader("A", "1"),
+				)),
+			),
+			AgentCallTask("review", AgentOption(AgentBySlug("code-reviewer")), Message("Review")),
+		},
+	}
+
+	first, err := json.Marshal(wf)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		again, _ := json.Marshal(wf)
+		if string(again) != string(first) {
+			t.Fatalf("json.Marshal() output is not stable:\n%s\n%s", first, again)
+		}
+	}
+
+	var decoded struct {
+		Tasks []struct {
+			Kind   string                 `json:"kind"`
+			Config map[string]interface{} `json:"config"`
+		} `json:"tasks"`
+	}
+	if err := json.Unmarshal(first, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	nested := decoded.Tasks[0].Config["do"].([]interface{})[0].(map[string]interface{})
+	if nested["kind"] != "HTTP_CALL" {
+		t.Errorf("nested task kind = %v, want HTTP_CALL", nested["kind"])
+	}
+	agent := decoded.Tasks[1].Config["agent"].(map[string]interface{})
+	if agent["slug"] != "code-reviewer" {
+		t.Errorf("agent slug = %v, want code-reviewer", agent["slug"])
+	}
+}
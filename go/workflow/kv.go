@@ -0,0 +1,219 @@
+package workflow
+
+import "encoding/json"
+
+// TaskKindKV reads or writes an entry in the platform key-value store.
+//
+// KV tasks have no dedicated engine task kind: they are lowered to
+// CALL_ACTIVITY tasks running the platform's key-value activities. Entries are
+// scoped to the workflow's organization, so every execution of every workflow
+// in the organization sees the same keys.
+const TaskKindKV TaskKind = "KV"
+
+// Platform activities that back KV tasks.
+const (
+	kvGetActivity = "stigmer.kv.Get"
+	kvSetActivity = "stigmer.kv.Set"
+)
+
+// KVAction is the operation a KV task performs.
+type KVAction string
+
+const (
+	KVActionGet KVAction = "get"
+	KVActionSet KVAction = "set"
+)
+
+func init() {
+	if err := RegisterTaskKind(TaskKindKV, lowerKVTask); err != nil {
+		panic(err)
+	}
+}
+
+// KVTaskConfig defines the configuration for KV tasks.
+type KVTaskConfig struct {
+	Action  KVAction `json:"action,omitempty"`  // Get or set
+	Key     string   `json:"key,omitempty"`     // Entry key (string or expression)
+	Value   any      `json:"value,omitempty"`   // Value to store (set only)
+	Default any      `json:"default,omitempty"` // Value returned when the key is missing (get only)
+	TTL     string   `json:"ttl,omitempty"`     // How long a stored entry is kept (set only)
+}
+
+func (*KVTaskConfig) isTaskConfig() {}
+
+// KVTaskOption is a functional option for configuring KV tasks.
+type KVTaskOption func(*KVTaskConfig)
+
+// KVGet creates a KV task that reads key from the platform key-value store,
+// so long-running workflows can share small state across executions without
+// a database. The task output is {"value": ..., "found": bool}; read it with
+// task.Field("value").
+//
+// key accepts a string, an expression, a StringRef from context, or a
+// TaskFieldRef (which also adds an implicit dependency).
+//
+// Example:
+//
+//	cursor := workflow.KVGet("loadCursor", "sync/last-cursor", workflow.KVDefault("0"))
+//	wf.AddTask(cursor)
+//	wf.HttpGet("fetchChanges", changesURL, workflow.WithQueryParam("since", cursor.Field("value")))
+func KVGet(name string, key interface{}, opts ...KVTaskOption) *Task {
+	return newKVTask(name, &KVTaskConfig{Action: KVActionGet, Key: toExpression(key)}, opts, key)
+}
+
+// KVSet creates a KV task that stores value under key in the platform
+// key-value store. Entries are kept until overwritten unless KVTTL is given.
+//
+// key accepts the same types as KVGet; value accepts any JSON value, a Ref
+// type, or a TaskFieldRef (which also adds an implicit dependency).
+//
+// Example:
+//
+//	wf.AddTask(workflow.KVSet("saveCursor", "sync/last-cursor", fetch.Field("nextCursor")))
+func KVSet(name string, key, value interface{}, opts ...KVTaskOption) *Task {
+	cfg := &KVTaskConfig{Action: KVActionSet, Key: toExpression(key), Value: kvValue(value)}
+	return newKVTask(name, cfg, opts, key, value)
+}
+
+// KVGet adds a task that reads key from the platform key-value store (see
+// the KVGet function).
+func (w *Workflow) KVGet(name string, key interface{}, opts ...KVTaskOption) *Task {
+	task := KVGet(name, key, opts...)
+	w.AddTask(task)
+	return task
+}
+
+// KVSet adds a task that stores value under key in the platform key-value
+// store (see the KVSet function).
+func (w *Workflow) KVSet(name string, key, value interface{}, opts ...KVTaskOption) *Task {
+	task := KVSet(name, key, value, opts...)
+	w.AddTask(task)
+	return task
+}
+
+// KVDefault sets the value a KVGet task returns when the key is missing.
+func KVDefault(value interface{}) KVTaskOption {
+	return func(cfg *KVTaskConfig) {
+		cfg.Default = kvValue(value)
+	}
+}
+
+// KVTTL sets how long a KVSet task keeps the entry before it expires.
+// Accepts seconds (int or IntRef), a time.Duration or DurationRef, duration helpers, or Ref types.
+func KVTTL(ttl interface{}) KVTaskOption {
+	return func(cfg *KVTaskConfig) {
+		cfg.TTL = toDuration(ttl)
+	}
+}
+
+// newKVTask builds a KV task, adding implicit dependencies for task field
+// references among refs.
+func newKVTask(name string, cfg *KVTaskConfig, opts []KVTaskOption, refs ...interface{}) *Task {
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	task := &Task{
+		Name:   name,
+		Kind:   TaskKindKV,
+		Config: cfg,
+	}
+	for _, ref := range refs {
+		if fieldRef, ok := ref.(taskOutputRef); ok {
+			task.dependsOnName(fieldRef.TaskName())
+		}
+	}
+	return task
+}
+
+// kvValue converts Ref types to their value or expression and keeps other
+// values as they are, so structured values are stored as JSON.
+func kvValue(value interface{}) any {
+	if _, ok := value.(Ref); ok {
+		return toExpression(value)
+	}
+	return value
+}
+
+// lowerKVTask converts a KV task to the CALL_ACTIVITY task the engine executes.
+func lowerKVTask(task *Task) (*Task, error) {
+	cfg, ok := task.Config.(*KVTaskConfig)
+	if !ok {
+		return nil, NewValidationErrorWithCause(
+			"config",
+			"",
+			"type",
+			"invalid config type for KV task",
+			ErrInvalidTaskConfig,
+		)
+	}
+	if cfg.Key == "" {
+		return nil, NewValidationErrorWithCause(
+			"config.key",
+			"",
+			"required",
+			"KV task must have a key",
+			ErrInvalidTaskConfig,
+		)
+	}
+
+	input := map[string]any{"key": cfg.Key}
+	var activity string
+	switch cfg.Action {
+	case KVActionGet:
+		activity = kvGetActivity
+		if cfg.Default != nil {
+			value, err := jsonValue("config.default", cfg.Default)
+			if err != nil {
+				return nil, err
+			}
+			input["default"] = value
+		}
+	case KVActionSet:
+		activity = kvSetActivity
+		if cfg.Value == nil {
+			return nil, NewValidationErrorWithCause(
+				"config.value",
+				"",
+				"required",
+				"KV set task must have a value",
+				ErrInvalidTaskConfig,
+			)
+		}
+		if err := validateDuration("config.ttl", cfg.TTL); err != nil {
+			return nil, err
+		}
+		value, err := jsonValue("config.value", cfg.Value)
+		if err != nil {
+			return nil, err
+		}
+		input["value"] = value
+		if cfg.TTL != "" {
+			input["ttl"] = cfg.TTL
+		}
+	default:
+		return nil, NewValidationErrorWithCause(
+			"config.action",
+			string(cfg.Action),
+			"enum",
+			"KV action must be get or set",
+			ErrInvalidTaskConfig,
+		)
+	}
+
+	return CallActivityTask(task.Name, WithActivity(activity), WithActivityInput(input)), nil
+}
+
+// jsonValue converts value to its JSON form (maps, slices and primitives), so
+// structs and typed slices can be carried in the activity input.
+func jsonValue(field string, value any) (any, error) {
+	var result any
+	data, err := json.Marshal(value)
+	if err == nil {
+		err = json.Unmarshal(data, &result)
+	}
+	if err != nil {
+		return nil, NewValidationErrorWithCause(field, "", "json",
+			"value must be JSON-encodable: "+err.Error(), ErrInvalidTaskConfig)
+	}
+	return result, nil
+}
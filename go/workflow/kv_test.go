@@ -0,0 +1,65 @@
+package workflow
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestKVTasks(t *testing.T) {
+	wf := &Workflow{Document: Document{Name: "sync"}}
+	fetch := wf.HttpGet("fetch", "https://api.example.com/changes")
+	wf.KVGet("loadCursor", "sync/last-cursor", KVDefault("0"))
+	set := wf.KVSet("saveCursor", "sync/last-cursor", fetch.Field("nextCursor"), KVTTL(24*time.Hour))
+	tags := KVSet("saveTags", "sync/tags", []string{"a", "b"})
+
+	if !reflect.DeepEqual(set.Dependencies, []string{"fetch"}) {
+		t.Errorf("saveCursor dependencies = %v, want [fetch]", set.Dependencies)
+	}
+
+	get, err := LowerTask(wf.Tasks[1])
+	if err != nil {
+		t.Fatalf("LowerTask(loadCursor) error = %v", err)
+	}
+	want := map[string]any{"key": "sync/last-cursor", "default": "0"}
+	if cfg := get.Config.(*CallActivityTaskConfig); cfg.Activity != kvGetActivity || !reflect.DeepEqual(cfg.Input, want) {
+		t.Errorf("lowered get = %s %v", cfg.Activity, cfg.Input)
+	}
+
+	lowered, err := LowerTask(set)
+	if err != nil {
+		t.Fatalf("LowerTask(saveCursor) error = %v", err)
+	}
+	want = map[string]any{"key": "sync/last-cursor", "value": "${ $context.fetch.nextCursor }", "ttl": "24h"}
+	if cfg := lowered.Config.(*CallActivityTaskConfig); cfg.Activity != kvSetActivity || !reflect.DeepEqual(cfg.Input, want) {
+		t.Errorf("lowered set = %s %v", cfg.Activity, cfg.Input)
+	}
+
+	lowered, err = LowerTask(tags)
+	if err != nil {
+		t.Fatalf("LowerTask(saveTags) error = %v", err)
+	}
+	if got := lowered.Config.(*CallActivityTaskConfig).Input["value"]; !reflect.DeepEqual(got, []any{"a", "b"}) {
+		t.Errorf("typed slice value = %#v, want JSON list", got)
+	}
+}
+
+func TestKVValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		task *Task
+	}{
+		{"missing key", KVGet("load", "")},
+		{"missing value", KVSet("save", "k", nil)},
+		{"bad ttl", KVSet("save", "k", 1, KVTTL("a day"))},
+		{"not json", KVSet("save", "k", make(chan int))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateTaskConfig(tt.task); !errors.Is(err, ErrInvalidTaskConfig) {
+				t.Errorf("validateTaskConfig() error = %v, want ErrInvalidTaskConfig", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// taskOutputRef is a reference to the output of a task, such as a TaskFieldRef
+// or a ListRef. Tasks that use one depend on the task it comes from.
+type taskOutputRef interface {
+	Ref
+	TaskName() string
+}
+
+// ListRef is a reference to an array field of a task's output, created with
+// Task.FieldAsList. Its methods generate the JQ expressions for reading and
+// transforming the array, so FOR loops and array handling don't need
+// hand-written expressions.
+//
+// Like a TaskFieldRef, a ListRef (and every reference derived from it) makes
+// the tasks that use it depend on its source task.
+//
+// Example:
+//
+//	fetchTask := wf.HttpGet("fetch", endpoint)
+//	issues := fetchTask.FieldAsList("issues")
+//	wf.AddTask(workflow.ForTask("triage", workflow.WithIn(issues.Filter(`.state == "open"`)), ...))
+//	wf.SetVars("summary", "count", issues.Len())
+type ListRef struct {
+	taskName string
+	expr     string // JQ expression for the list, without "${ }"
+}
+
+// FieldAsList creates a ListRef to an array field of this task's output.
+// Like Field, it automatically exports the task.
+//
+// Example:
+//
+//	users := fetchTask.FieldAsList("users")
+//	first := users.First()
+//	// Result: "${ ($context.fetch.users[0]) }"
+func (t *Task) FieldAsList(fieldName string) ListRef {
+	ref := t.Field(fieldName)
+	return ListRef{taskName: ref.taskName, expr: expressionBody(ref.Expression())}
+}
+
+// Expression returns the JQ expression for the list.
+// Implements the Ref interface.
+func (l ListRef) Expression() string {
+	return fmt.Sprintf("${ %s }", l.expr)
+}
+
+// Name returns a human-readable name for this reference.
+// Implements the Ref interface.
+func (l ListRef) Name() string {
+	return l.expr
+}
+
+// TaskName returns the name of the source task.
+// This is used for dependency tracking.
+func (l ListRef) TaskName() string {
+	return l.taskName
+}
+
+// MarshalJSON encodes the list reference as its expression string.
+func (l ListRef) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.Expression())
+}
+
+// Len returns a reference to the number of items in the list.
+func (l ListRef) Len() Ref {
+	return l.derive(fmt.Sprintf("(%s | length)", l.expr))
+}
+
+// Index returns a reference to the item at position i. Negative positions
+// count from the end.
+func (l ListRef) Index(i int) Ref {
+	return l.derive(fmt.Sprintf("(%s[%d])", l.expr, i))
+}
+
+// First returns a reference to the first item of the list, like Index(0).
+func (l ListRef) First() Ref {
+	return l.Index(0)
+}
+
+// Filter returns a ListRef with the items for which cond is true. cond is a
+// JQ expression evaluated against each item, with or without "${ }".
+//
+// Example:
+//
+//	open := issues.Filter(`.state == "open"`)
+//	// Result: "${ ($context.fetch.issues | map(select(.state == "open"))) }"
+func (l ListRef) Filter(cond string) ListRef {
+	return l.derive(fmt.Sprintf("(%s | map(select(%s)))", l.expr, itemExpression(cond)))
+}
+
+// Map returns a ListRef with expr applied to each item. expr is a JQ
+// expression evaluated against each item, with or without "${ }".
+//
+// Example:
+//
+//	titles := issues.Map(".title")
+//	// Result: "${ ($context.fetch.issues | map(.title)) }"
+func (l ListRef) Map(expr string) ListRef {
+	return l.derive(fmt.Sprintf("(%s | map(%s))", l.expr, itemExpression(expr)))
+}
+
+// derive returns a reference to expr that keeps the source task of l.
+func (l ListRef) derive(expr string) ListRef {
+	return ListRef{taskName: l.taskName, expr: expr}
+}
+
+// itemExpression returns the body of an expression applied to list items.
+func itemExpression(expr string) string {
+	if isExpression(expr) {
+		return expressionBody(expr)
+	}
+	return expr
+}
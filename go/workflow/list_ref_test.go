@@ -0,0 +1,52 @@
+package workflow
+
+import "testing"
+
+// TestListRef_Expressions verifies the JQ expressions generated by ListRef.
+func TestListRef_Expressions(t *testing.T) {
+	fetch := HttpCallTask("fetch", WithURI("https://api.example.com/issues"))
+	issues := fetch.FieldAsList("issues")
+
+	tests := []struct {
+		name     string
+		ref      Ref
+		expected string
+	}{
+		{"list", issues, "${ $context.fetch.issues }"},
+		{"len", issues.Len(), "${ ($context.fetch.issues | length) }"},
+		{"index", issues.Index(-1), "${ ($context.fetch.issues[-1]) }"},
+		{"first", issues.First(), "${ ($context.fetch.issues[0]) }"},
+		{"filter", issues.Filter(`${ .state == "open" }`), `${ ($context.fetch.issues | map(select(.state == "open"))) }`},
+		{"map", issues.Map(".title"), "${ ($context.fetch.issues | map(.title)) }"},
+		{"chained", issues.Filter(".open").Map(".id").Len(), "${ ((($context.fetch.issues | map(select(.open))) | map(.id)) | length) }"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ref.Expression(); got != tt.expected {
+				t.Errorf("Expression() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+
+	if fetch.ExportAs == "" {
+		t.Error("FieldAsList() did not export the task")
+	}
+}
+
+// TestListRef_Dependencies verifies that refs derived from a ListRef make the
+// tasks using them depend on the source task.
+func TestListRef_Dependencies(t *testing.T) {
+	fetch := HttpCallTask("fetch", WithURI("https://api.example.com/issues"))
+	issues := fetch.FieldAsList("issues")
+
+	set := SetTask("summary", SetVar("count", issues.Filter(".open").Len()))
+	if len(set.Dependencies) != 1 || set.Dependencies[0] != "fetch" {
+		t.Errorf("Dependencies = %v, want [fetch]", set.Dependencies)
+	}
+
+	batch := BatchedHttpPostTask("import", "https://api.example.com/bulk", issues, 10)
+	if len(batch.Dependencies) != 1 || batch.Dependencies[0] != "fetch" {
+		t.Errorf("Dependencies = %v, want [fetch]", batch.Dependencies)
+	}
+}
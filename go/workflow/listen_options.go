@@ -0,0 +1,65 @@
+package workflow
+
+// WithEventFilter only resumes the workflow on events matching the condition.
+// The event is available as "." in the condition.
+//
+// Accepts condition helpers or bare conditions:
+//
+//	WithEventFilter(workflow.Equals(workflow.Field("data.status"), workflow.Literal("approved")))
+//	WithEventFilter(`.data.amount > 100`)
+func WithEventFilter(condition string) ListenTaskOption {
+	return func(cfg *ListenTaskConfig) {
+		cfg.Filter = "${ " + conditionBody(condition) + " }"
+	}
+}
+
+// WithCorrelation only resumes the workflow on events whose attribute matches
+// the given value. Use this when several workflow instances wait for the same
+// event name, so each instance only receives its own events.
+// Accepts strings, context Refs, or TaskFieldRefs.
+//
+// Can be called multiple times; all correlations must match.
+//
+// Example:
+//
+//	createOrder := wf.HttpPost("createOrder", ...)
+//	workflow.ListenTask("waitForPayment",
+//	    workflow.WithEvent("payment.completed"),
+//	    workflow.WithCorrelation("orderId", createOrder.Field("id")),
+//	)
+func WithCorrelation(attribute string, value interface{}) ListenTaskOption {
+	return func(cfg *ListenTaskConfig) {
+		if cfg.Correlation == nil {
+			cfg.Correlation = make(map[string]string)
+		}
+		cfg.Correlation[attribute] = toExpression(value)
+
+		// Track implicit dependency if this is a TaskFieldRef
+		if fieldRef, ok := value.(taskOutputRef); ok {
+			if cfg.ImplicitDependencies == nil {
+				cfg.ImplicitDependencies = make(map[string]bool)
+			}
+			cfg.ImplicitDependencies[fieldRef.TaskName()] = true
+		}
+	}
+}
+
+// WithListenTimeout gives up waiting for the event after the duration and
+// continues at thenTask instead. Without a timeout a LISTEN task waits forever.
+// Accepts seconds (int or IntRef), a time.Duration or DurationRef, duration helpers, or Ref types for the duration.
+//
+// Example:
+//
+//	escalate := wf.HttpPost("escalate", ...)
+//	workflow.ListenTask("waitForApproval",
+//	    workflow.WithEvent("approval.granted"),
+//	    workflow.WithListenTimeout(workflow.Hours(24), escalate),
+//	)
+func WithListenTimeout(duration interface{}, thenTask *Task) ListenTaskOption {
+	return func(cfg *ListenTaskConfig) {
+		cfg.Timeout = toDuration(duration)
+		if thenTask != nil {
+			cfg.TimeoutThen = thenTask.Name
+		}
+	}
+}
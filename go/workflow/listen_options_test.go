@@ -0,0 +1,82 @@
+package workflow
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestListenTaskFilterAndCorrelation verifies filter normalization and correlation dependencies.
+func TestListenTaskFilterAndCorrelation(t *testing.T) {
+	createOrder := HttpCallTask("createOrder", WithHTTPPost(), WithURI("https://api.example.com/orders"))
+
+	task := ListenTask("waitForPayment",
+		WithEvent("payment.completed"),
+		WithEventFilter(`.data.amount > 100`),
+		WithCorrelation("orderId", createOrder.Field("id")),
+		WithCorrelation("tenant", "acme"),
+	)
+	cfg := task.Config.(*ListenTaskConfig)
+
+	if cfg.Filter != "${ .data.amount > 100 }" {
+		t.Errorf("Filter = %q, want bare condition wrapped", cfg.Filter)
+	}
+	if cfg.Correlation["orderId"] != "${ $context.createOrder.id }" {
+		t.Errorf("Correlation[orderId] = %q", cfg.Correlation["orderId"])
+	}
+	if cfg.Correlation["tenant"] != "acme" {
+		t.Errorf("Correlation[tenant] = %q", cfg.Correlation["tenant"])
+	}
+	if len(task.Dependencies) != 1 || task.Dependencies[0] != "createOrder" {
+		t.Errorf("Dependencies = %v, want [createOrder]", task.Dependencies)
+	}
+	if err := validateTaskConfig(task); err != nil {
+		t.Errorf("validateTaskConfig() error = %v", err)
+	}
+}
+
+// TestWithEventFilter_Wrapped verifies condition helpers are not double-wrapped.
+func TestWithEventFilter_Wrapped(t *testing.T) {
+	task := ListenTask("waitForApproval",
+		WithEvent("approval"),
+		WithEventFilter(Equals(Field("data.status"), Literal("approved"))),
+	)
+	if got := task.Config.(*ListenTaskConfig).Filter; got != `${ .data.status == "approved" }` {
+		t.Errorf("Filter = %q", got)
+	}
+}
+
+// TestWithCorrelation_EmptyKey verifies empty correlation attributes are rejected.
+func TestWithCorrelation_EmptyKey(t *testing.T) {
+	task := ListenTask("wait", WithEvent("approval"), WithCorrelation("", "x"))
+	if err := validateTaskConfig(task); !errors.Is(err, ErrInvalidTaskConfig) {
+		t.Errorf("validateTaskConfig() error = %v, want ErrInvalidTaskConfig", err)
+	}
+}
+
+// TestWithListenTimeout verifies the timeout and fallback task are recorded and validated.
+func TestWithListenTimeout(t *testing.T) {
+	escalate := SetTask("escalate", SetVar("escalated", "true"))
+	task := ListenTask("waitForApproval",
+		WithEvent("approval.granted"),
+		WithListenTimeout(Hours(24), escalate),
+	)
+	cfg := task.Config.(*ListenTaskConfig)
+
+	if cfg.Timeout != "24h" {
+		t.Errorf("Timeout = %q, want %q", cfg.Timeout, "24h")
+	}
+	if cfg.TimeoutThen != "escalate" {
+		t.Errorf("TimeoutThen = %q, want %q", cfg.TimeoutThen, "escalate")
+	}
+	if err := validateTaskConfig(task); err != nil {
+		t.Errorf("validateTaskConfig() error = %v", err)
+	}
+}
+
+// TestWithListenTimeout_MissingTask verifies a timeout without a fallback task is rejected.
+func TestWithListenTimeout_MissingTask(t *testing.T) {
+	task := ListenTask("wait", WithEvent("approval"), WithListenTimeout(Minutes(5), nil))
+	if err := validateTaskConfig(task); !errors.Is(err, ErrInvalidTaskConfig) {
+		t.Errorf("validateTaskConfig() error = %v, want ErrInvalidTaskConfig", err)
+	}
+}
@@ -0,0 +1,169 @@
+package workflow
+
+// TaskKindLock acquires or releases a named distributed lock.
+//
+// LOCK tasks have no dedicated engine task kind: they are lowered to
+// CALL_ACTIVITY tasks running the platform's lock activities, which hold the
+// lock on behalf of the workflow instance.
+const TaskKindLock TaskKind = "LOCK"
+
+// Platform activities that back LOCK tasks.
+const (
+	lockAcquireActivity = "stigmer.locks.Acquire"
+	lockReleaseActivity = "stigmer.locks.Release"
+)
+
+// lockOwner identifies the workflow instance holding a lock.
+const lockOwner = "${ $workflow.id }"
+
+// LockAction is the operation a LOCK task performs.
+type LockAction string
+
+const (
+	LockAcquire LockAction = "acquire"
+	LockRelease LockAction = "release"
+)
+
+func init() {
+	if err := RegisterTaskKind(TaskKindLock, lowerLockTask); err != nil {
+		panic(err)
+	}
+}
+
+// LockTaskConfig defines the configuration for LOCK tasks.
+type LockTaskConfig struct {
+	Lock   string     `json:"lock,omitempty"`   // Lock name
+	Action LockAction `json:"action,omitempty"` // Acquire or release
+	TTL    string     `json:"ttl,omitempty"`    // How long an acquired lock is held before it expires
+}
+
+func (*LockTaskConfig) isTaskConfig() {}
+
+// AcquireLock creates a LOCK task named "acquire-lock-<lock>" that waits until
+// the named lock is free and takes it for at most ttl, so workflows sharing an
+// external resource run one at a time. The TTL bounds how long a crashed
+// workflow can hold the lock.
+//
+// Lock names use the characters allowed in task names. ttl accepts seconds
+// (int or IntRef), a time.Duration or DurationRef, duration helpers, or Ref types.
+//
+// Prefer Workflow.AcquireLock, which also releases the lock when the workflow ends.
+//
+// Example:
+//
+//	wf.AddTask(workflow.AcquireLock("billing-ledger", workflow.Minutes(10)))
+func AcquireLock(lock string, ttl interface{}) *Task {
+	return &Task{
+		Name:   "acquire-lock-" + lock,
+		Kind:   TaskKindLock,
+		Config: &LockTaskConfig{Lock: lock, Action: LockAcquire, TTL: toDuration(ttl)},
+	}
+}
+
+// ReleaseLock creates a LOCK task named "release-lock-<lock>" that releases
+// the named lock. Releasing a lock the workflow does not hold does nothing.
+//
+// Example:
+//
+//	wf.AddTask(workflow.ReleaseLock("billing-ledger"))
+func ReleaseLock(lock string) *Task {
+	return &Task{
+		Name:   "release-lock-" + lock,
+		Kind:   TaskKindLock,
+		Config: &LockTaskConfig{Lock: lock, Action: LockRelease},
+	}
+}
+
+// AcquireLock adds a task that acquires the named lock (see the AcquireLock
+// function) and registers a finalizer named "finally-release-lock-<lock>" that
+// releases it when the workflow ends, whether it succeeds or fails.
+//
+// Example:
+//
+//	wf.AcquireLock("billing-ledger", workflow.Minutes(10))
+//	wf.HttpPost("postInvoices", ledgerURL, ...)
+//	// The lock is released after postInvoices, or when any task fails.
+func (w *Workflow) AcquireLock(lock string, ttl interface{}) *Task {
+	task := AcquireLock(lock, ttl)
+	w.AddTask(task)
+
+	release := ReleaseLock(lock)
+	release.Name = "finally-" + release.Name
+	for _, f := range w.Finalizers {
+		if f.Name == release.Name {
+			return task
+		}
+	}
+	w.Finalizers = append(w.Finalizers, release)
+	return task
+}
+
+// ReleaseLock adds a task that releases the named lock before the workflow
+// ends, so later tasks do not hold it needlessly. The finalizer registered by
+// AcquireLock still runs and does nothing once the lock is released.
+//
+// Example:
+//
+//	wf.AcquireLock("billing-ledger", workflow.Minutes(10))
+//	wf.HttpPost("postInvoices", ledgerURL, ...)
+//	wf.ReleaseLock("billing-ledger")
+//	wf.HttpPost("notify", notifyURL, ...)
+func (w *Workflow) ReleaseLock(lock string) *Task {
+	task := ReleaseLock(lock)
+	w.AddTask(task)
+	return task
+}
+
+// lowerLockTask converts a LOCK task to the CALL_ACTIVITY task the engine executes.
+func lowerLockTask(task *Task) (*Task, error) {
+	cfg, ok := task.Config.(*LockTaskConfig)
+	if !ok {
+		return nil, NewValidationErrorWithCause(
+			"config",
+			"",
+			"type",
+			"invalid config type for LOCK task",
+			ErrInvalidTaskConfig,
+		)
+	}
+	if cfg.Lock == "" || !taskNameRegex.MatchString(cfg.Lock) {
+		return nil, NewValidationErrorWithCause(
+			"config.lock",
+			cfg.Lock,
+			"format",
+			"lock name is required and must contain only letters, digits, hyphens and underscores",
+			ErrInvalidTaskConfig,
+		)
+	}
+
+	input := map[string]any{"lock": cfg.Lock, "owner": lockOwner}
+	activity := lockReleaseActivity
+	switch cfg.Action {
+	case LockAcquire:
+		if cfg.TTL == "" {
+			return nil, NewValidationErrorWithCause(
+				"config.ttl",
+				"",
+				"required",
+				"acquiring a lock requires a TTL",
+				ErrInvalidTaskConfig,
+			)
+		}
+		if err := validateDuration("config.ttl", cfg.TTL); err != nil {
+			return nil, err
+		}
+		input["ttl"] = cfg.TTL
+		activity = lockAcquireActivity
+	case LockRelease:
+	default:
+		return nil, NewValidationErrorWithCause(
+			"config.action",
+			string(cfg.Action),
+			"enum",
+			"lock action must be acquire or release",
+			ErrInvalidTaskConfig,
+		)
+	}
+
+	return CallActivityTask(task.Name, WithActivity(activity), WithActivityInput(input)), nil
+}
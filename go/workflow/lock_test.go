@@ -0,0 +1,57 @@
+package workflow
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestWorkflowAcquireLock verifies the lock is acquired in place and released by a finalizer.
+func TestWorkflowAcquireLock(t *testing.T) {
+	wf := &Workflow{Document: Document{Name: "ledger"}}
+	wf.AcquireLock("billing-ledger", 10*time.Minute)
+	wf.AcquireLock("billing-ledger", 10*time.Minute)
+	wf.ReleaseLock("billing-ledger")
+
+	if got := taskNames(wf.Tasks); len(got) != 3 || got[0] != "acquire-lock-billing-ledger" || got[2] != "release-lock-billing-ledger" {
+		t.Errorf("tasks = %v", got)
+	}
+	if len(wf.Finalizers) != 1 || wf.Finalizers[0].Name != "finally-release-lock-billing-ledger" {
+		t.Fatalf("finalizers = %v, want one release per lock", taskNames(wf.Finalizers))
+	}
+
+	acquire, err := LowerTask(wf.Tasks[0])
+	if err != nil {
+		t.Fatalf("LowerTask() error = %v", err)
+	}
+	cfg := acquire.Config.(*CallActivityTaskConfig)
+	if acquire.Name != "acquire-lock-billing-ledger" || cfg.Activity != lockAcquireActivity || cfg.Input["ttl"] != "10m" || cfg.Input["lock"] != "billing-ledger" {
+		t.Errorf("lowered acquire = %s %+v", acquire.Name, cfg)
+	}
+
+	release, err := LowerTask(wf.Finalizers[0])
+	if err != nil {
+		t.Fatalf("LowerTask() error = %v", err)
+	}
+	if cfg := release.Config.(*CallActivityTaskConfig); cfg.Activity != lockReleaseActivity || cfg.Input["ttl"] != nil {
+		t.Errorf("lowered release = %+v", cfg)
+	}
+}
+
+func TestLockValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		task *Task
+	}{
+		{"lock name", AcquireLock("billing/ledger", 60)},
+		{"missing ttl", AcquireLock("ledger", "")},
+		{"bad ttl", AcquireLock("ledger", "ten minutes")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateTaskConfig(tt.task); !errors.Is(err, ErrInvalidTaskConfig) {
+				t.Errorf("validateTaskConfig() error = %v, want ErrInvalidTaskConfig", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package workflow
+
+import (
+	"reflect"
+	"regexp"
+)
+
+// Task returns the task with the given name, searching nested tasks as well.
+// Returns false if no task has that name.
+//
+// Example:
+//
+//	if task, ok := wf.Task("fetchData"); ok {
+//	    fmt.Println(task.Kind)
+//	}
+func (w *Workflow) Task(name string) (*Task, bool) {
+	for task := range w.AllTasks() {
+		if task.Name == name {
+			return task, true
+		}
+	}
+	return nil, false
+}
+
+// TasksOfKind returns all tasks of the given kind, including nested tasks,
+// in depth-first order.
+//
+// Example:
+//
+//	for _, task := range wf.TasksOfKind(workflow.TaskKindHttpCall) {
+//	    fmt.Println(task.Name)
+//	}
+func (w *Workflow) TasksOfKind(kind TaskKind) []*Task {
+	var tasks []*Task
+	for task := range w.AllTasks() {
+		if task.Kind == kind {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+// References returns all tasks whose configuration references the named context
+// variable, including nested tasks, in depth-first order.
+//
+// Both runtime references ("${ $context.apiURL }") and synthesis-time
+// placeholders ("${apiURL}") are detected. Tasks that only contain the variable
+// through a nested task are not included; the nested task is returned instead.
+//
+// Example:
+//
+//	for _, task := range wf.References("apiURL") {
+//	    fmt.Println(task.Name, "uses apiURL")
+//	}
+func (w *Workflow) References(varName string) []*Task {
+	pattern := regexp.MustCompile(
+		`\$context\.` + regexp.QuoteMeta(varName) + `\b|\$\{\s*` + regexp.QuoteMeta(varName) + `\s*\}`,
+	)
+
+	var tasks []*Task
+	for task := range w.AllTasks() {
+		found := false
+		collectConfigStrings(reflect.ValueOf(task.Config), func(s string) bool {
+			found = pattern.MatchString(s)
+			return !found
+		})
+		if found {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+var taskType = reflect.TypeOf(Task{})
+
+// collectConfigStrings calls fn for every string value reachable from v, skipping
+// nested tasks. Iteration stops when fn returns false.
+func collectConfigStrings(v reflect.Value, fn func(string) bool) bool {
+	switch v.Kind() {
+	case reflect.String:
+		return fn(v.String())
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return true
+		}
+		return collectConfigStrings(v.Elem(), fn)
+	case reflect.Struct:
+		if v.Type() == taskType {
+			return true
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Type().Field(i).IsExported() {
+				continue
+			}
+			if !collectConfigStrings(v.Field(i), fn) {
+				return false
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if !collectConfigStrings(v.Index(i), fn) {
+				return false
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			if !collectConfigStrings(iter.Value(), fn) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,66 @@
+package workflow
+
+import (
+	"testing"
+)
+
+func newLookupTestWorkflow() *Workflow {
+	return &Workflow{
+		Tasks: []*Task{
+			HttpCallTask("fetch", WithHTTPGet(), WithURI("${ $context.apiURL + \"/items\" }")),
+			ForTask("loop",
+				WithIn("${.items}"),
+				WithDo(
+					HttpCallTask("process", WithHTTPPost(), WithURI("${apiURL}/process")),
+					SetTask("count", SetVar("total", "${ $context.apiURLs }")),
+				),
+			),
+		},
+	}
+}
+
+// TestWorkflowTask verifies lookup by name includes nested tasks.
+func TestWorkflowTask(t *testing.T) {
+	wf := newLookupTestWorkflow()
+
+	task, ok := wf.Task("process")
+	if !ok {
+		t.Fatal("Task(\"process\") not found")
+	}
+	if task.Kind != TaskKindHttpCall {
+		t.Errorf("Kind = %q, want %q", task.Kind, TaskKindHttpCall)
+	}
+
+	if _, ok := wf.Task("missing"); ok {
+		t.Error("Task(\"missing\") should not be found")
+	}
+}
+
+// TestWorkflowTasksOfKind verifies kind filtering includes nested tasks.
+func TestWorkflowTasksOfKind(t *testing.T) {
+	wf := newLookupTestWorkflow()
+
+	tasks := wf.TasksOfKind(TaskKindHttpCall)
+	if len(tasks) != 2 || tasks[0].Name != "fetch" || tasks[1].Name != "process" {
+		t.Errorf("TasksOfKind(HTTP_CALL) = %v, want [fetch process]", taskNames(tasks))
+	}
+}
+
+// TestWorkflowReferences verifies runtime and synthesis-time variable references are found.
+func TestWorkflowReferences(t *testing.T) {
+	wf := newLookupTestWorkflow()
+
+	tasks := wf.References("apiURL")
+	names := taskNames(tasks)
+	if len(names) != 2 || names[0] != "fetch" || names[1] != "process" {
+		t.Errorf("References(\"apiURL\") = %v, want [fetch process]", names)
+	}
+}
+
+func taskNames(tasks []*Task) []string {
+	names := make([]string, len(tasks))
+	for i, task := range tasks {
+		names[i] = task.Name
+	}
+	return names
+}
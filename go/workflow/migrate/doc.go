@@ -0,0 +1,23 @@
+// Package migrate helps move workflow code from raw "${ ... }" expression
+// strings to the typed helpers and references of the workflow package.
+//
+// Scan inspects a built workflow and reports every expression that has a
+// typed equivalent, including references to other tasks' outputs:
+//
+//	for _, s := range migrate.Scan(wf) {
+//	    fmt.Println(s)
+//	}
+//	// task notify: Headers[Authorization]: replace "${ \"Bearer \" + $context.token }" with workflow.Interpolate("Bearer ", workflow.VarRef("token"))
+//	// task notify: Body[title]: replace "${ $context.fetch.title }" with fetch.Field("title") (review: ...)
+//
+// RewriteSource applies the safe suggestions to Go source directly, replacing
+// string literals with helper calls:
+//
+//	out, suggestions, err := migrate.RewriteSource("pipeline.go", src)
+//
+// A suggestion is safe when the helper produces the same expression as the
+// string it replaces (ignoring whitespace), so rewriting never changes the
+// synthesized manifest. Suggestions that need a human decision, such as using
+// a task variable or treating a field as a caught error, are reported but not
+// applied.
+package migrate
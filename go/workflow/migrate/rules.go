@@ -0,0 +1,283 @@
+package migrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+const (
+	identPattern = `[A-Za-z_][A-Za-z0-9_]*`
+	pathPattern  = identPattern + `(?:\.` + identPattern + `)*`
+)
+
+var (
+	// contextRefRegex matches a context variable or exported task field.
+	contextRefRegex = regexp.MustCompile(`^\$context\.(` + pathPattern + `)$`)
+
+	// fieldRegex matches a field of the current data.
+	fieldRegex = regexp.MustCompile(`^\.(` + pathPattern + `)$`)
+
+	// counterRegex matches a context variable incremented or decremented by one.
+	counterRegex = regexp.MustCompile(`^\$context\.(` + identPattern + `)\s*([+-])\s*1$`)
+
+	// errorFieldRegex matches a field of a caught error.
+	errorFieldRegex = regexp.MustCompile(`^\.(` + identPattern + `)\.(message|code|stackTrace)$`)
+
+	// comparisonRegex matches a binary comparison.
+	comparisonRegex = regexp.MustCompile(`^(\S+)\s*(==|!=|>=|<=|>|<)\s*(.+)$`)
+
+	numberRegex = regexp.MustCompile(`^-?\d+(?:\.\d+)?$`)
+)
+
+// comparisons maps comparison operators to their helper and implementation.
+var comparisons = map[string]struct {
+	helper string
+	build  func(left, right string) string
+}{
+	"==": {"Equals", workflow.Equals},
+	"!=": {"NotEquals", workflow.NotEquals},
+	">":  {"GreaterThan", workflow.GreaterThan},
+	">=": {"GreaterThanOrEqual", workflow.GreaterThanOrEqual},
+	"<":  {"LessThan", workflow.LessThan},
+	"<=": {"LessThanOrEqual", workflow.LessThanOrEqual},
+}
+
+// errorFields maps caught error fields to their helper and implementation.
+var errorFields = map[string]struct {
+	helper string
+	build  func(string) string
+}{
+	"message":    {"ErrorMessage", workflow.ErrorMessage},
+	"code":       {"ErrorCode", workflow.ErrorCode},
+	"stackTrace": {"ErrorStackTrace", workflow.ErrorStackTrace},
+}
+
+// fix is a typed replacement for an expression string.
+type fix struct {
+	code  string // Go code, qualified with the workflow package name
+	value string // what the code evaluates to
+	safe  bool
+	note  string
+}
+
+// rules derives typed replacements for expression strings.
+type rules struct {
+	pkg string // local name of the workflow package
+
+	// tasks maps task names to true when task field references can be
+	// suggested (only known when scanning a workflow)
+	tasks map[string]bool
+}
+
+func (r rules) call(helper string, args ...string) string {
+	return r.pkg + "." + helper + "(" + strings.Join(args, ", ") + ")"
+}
+
+// suggest returns the typed replacement for s, if there is one. Replacements
+// whose value differs from s are discarded, so a fix never changes behavior.
+func (r rules) suggest(s string) (fix, bool) {
+	body, ok := expressionBody(s)
+	if !ok {
+		return fix{}, false
+	}
+	f, ok := r.match(body)
+	if !ok || canonical(f.value) != canonical(s) {
+		return fix{}, false
+	}
+	return f, true
+}
+
+func (r rules) match(body string) (fix, bool) {
+	if m := contextRefRegex.FindStringSubmatch(body); m != nil {
+		if task, field, ok := strings.Cut(m[1], "."); ok && r.tasks[task] {
+			return fix{
+				code:  goIdent(task) + ".Field(" + strconv.Quote(field) + ")",
+				value: workflow.VarRef(m[1]), // same as TaskFieldRef.Expression()
+				note:  fmt.Sprintf("use the variable holding task %q; this also records the dependency", task),
+			}, true
+		}
+		return fix{code: r.call("VarRef", strconv.Quote(m[1])), value: workflow.VarRef(m[1]), safe: true}, true
+	}
+
+	if m := counterRegex.FindStringSubmatch(body); m != nil {
+		if m[2] == "+" {
+			return fix{code: r.call("Increment", strconv.Quote(m[1])), value: workflow.Increment(m[1]), safe: true}, true
+		}
+		return fix{code: r.call("Decrement", strconv.Quote(m[1])), value: workflow.Decrement(m[1]), safe: true}, true
+	}
+
+	if m := errorFieldRegex.FindStringSubmatch(body); m != nil {
+		e := errorFields[m[2]]
+		return fix{
+			code:  r.call(e.helper, strconv.Quote(m[1])),
+			value: e.build(m[1]),
+			note:  fmt.Sprintf("only if %q is a caught error (WithCatch)", m[1]),
+		}, true
+	}
+
+	if f, ok := r.comparison(body); ok {
+		return f, true
+	}
+	return r.interpolation(body)
+}
+
+// comparison converts a comparison of a field or variable with a field,
+// variable or literal to a condition builder.
+func (r rules) comparison(body string) (fix, bool) {
+	m := comparisonRegex.FindStringSubmatch(body)
+	if m == nil {
+		return fix{}, false
+	}
+	left, leftValue, ok := r.operand(m[1], false)
+	if !ok {
+		return fix{}, false
+	}
+	right, rightValue, ok := r.operand(strings.TrimSpace(m[3]), true)
+	if !ok {
+		return fix{}, false
+	}
+	c := comparisons[m[2]]
+	return fix{code: r.call(c.helper, left, right), value: c.build(leftValue, rightValue), safe: true}, true
+}
+
+// operand converts a comparison operand to a condition builder call and its
+// value. Literals are only accepted on the right-hand side.
+func (r rules) operand(s string, allowLiteral bool) (code, value string, ok bool) {
+	if m := fieldRegex.FindStringSubmatch(s); m != nil {
+		return r.call("Field", strconv.Quote(m[1])), workflow.Field(m[1]), true
+	}
+	if m := contextRefRegex.FindStringSubmatch(s); m != nil {
+		return r.call("Var", strconv.Quote(m[1])), workflow.Var(m[1]), true
+	}
+	if !allowLiteral {
+		return "", "", false
+	}
+	if numberRegex.MatchString(s) {
+		return r.call("Number", s), workflow.Number(s), true
+	}
+	if lit, ok := unquote(s); ok {
+		return r.call("Literal", strconv.Quote(lit)), workflow.Literal(lit), true
+	}
+	return "", "", false
+}
+
+// interpolation converts string concatenation of literals and context
+// variables to Interpolate.
+func (r rules) interpolation(body string) (fix, bool) {
+	parts := splitConcat(body)
+	if len(parts) < 2 {
+		return fix{}, false
+	}
+	args := make([]string, len(parts))
+	values := make([]interface{}, len(parts))
+	for i, part := range parts {
+		if lit, ok := unquote(part); ok {
+			args[i], values[i] = strconv.Quote(lit), lit
+			continue
+		}
+		m := contextRefRegex.FindStringSubmatch(part)
+		if m == nil {
+			return fix{}, false
+		}
+		args[i], values[i] = r.call("VarRef", strconv.Quote(m[1])), workflow.VarRef(m[1])
+	}
+	return fix{code: r.call("Interpolate", args...), value: workflow.Interpolate(values...), safe: true}, true
+}
+
+// expressionBody returns the trimmed body of a "${ ... }" string.
+func expressionBody(s string) (string, bool) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "${") || !strings.HasSuffix(s, "}") {
+		return "", false
+	}
+	body := strings.TrimSpace(s[2 : len(s)-1])
+	return body, body != ""
+}
+
+// unquote decodes a JQ (JSON) string literal.
+func unquote(s string) (string, bool) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return "", false
+	}
+	var lit string
+	if err := json.Unmarshal([]byte(s), &lit); err != nil {
+		return "", false
+	}
+	return lit, true
+}
+
+// splitConcat splits an expression body on top-level + operators.
+func splitConcat(body string) []string {
+	var parts []string
+	var quoted, escaped bool
+	depth, start := 0, 0
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case escaped:
+			escaped = false
+		case quoted:
+			escaped = c == '\\'
+			quoted = c != '"'
+		case c == '"':
+			quoted = true
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case c == ')' || c == ']' || c == '}':
+			depth--
+		case c == '+' && depth == 0:
+			parts = append(parts, strings.TrimSpace(body[start:i]))
+			start = i + 1
+		}
+	}
+	return append(parts, strings.TrimSpace(body[start:]))
+}
+
+// canonical removes whitespace outside string literals, so expressions that
+// differ only in spacing compare equal.
+func canonical(s string) string {
+	var b strings.Builder
+	var quoted, escaped bool
+	for _, c := range strings.TrimSpace(s) {
+		switch {
+		case escaped:
+			escaped = false
+		case quoted:
+			escaped = c == '\\'
+			quoted = c != '"'
+		case c == '"':
+			quoted = true
+		case c == ' ' || c == '\t' || c == '\n':
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// goIdent turns a task name into a plausible Go variable name.
+func goIdent(name string) string {
+	var b strings.Builder
+	upper := false
+	for _, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_', c >= '0' && c <= '9' && b.Len() > 0:
+			if upper {
+				c = []rune(strings.ToUpper(string(c)))[0]
+			}
+			b.WriteRune(c)
+			upper = false
+		default:
+			upper = b.Len() > 0
+		}
+	}
+	if b.Len() == 0 {
+		return "task"
+	}
+	return b.String()
+}
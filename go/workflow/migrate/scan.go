@@ -0,0 +1,120 @@
+package migrate
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+// Suggestion is a raw expression string with a typed replacement.
+type Suggestion struct {
+	// Location identifies the string: "task <name>: <config path>" for Scan,
+	// or "file:line:column" for RewriteSource
+	Location string
+
+	// Expression is the raw expression string
+	Expression string
+
+	// Replacement is the Go code to use instead, e.g. workflow.VarRef("token")
+	Replacement string
+
+	// Safe reports whether the replacement produces the same expression and
+	// can be applied without review. RewriteSource only applies safe suggestions.
+	Safe bool
+
+	// Note explains what to check before applying an unsafe suggestion
+	Note string
+}
+
+// String formats the suggestion for display.
+func (s Suggestion) String() string {
+	msg := fmt.Sprintf("%s: replace %q with %s", s.Location, s.Expression, s.Replacement)
+	if s.Note != "" {
+		msg += " (review: " + s.Note + ")"
+	}
+	return msg
+}
+
+// taskType and taskSliceType identify nested tasks, which Walk visits itself.
+var (
+	taskType      = reflect.TypeOf(workflow.Task{})
+	taskSliceType = reflect.TypeOf([]workflow.Task{})
+)
+
+// Scan returns a suggestion for every expression string in the workflow's
+// task configurations that has a typed equivalent, including nested tasks.
+//
+// References to other tasks' output ($context.<task>.<field>) are suggested as
+// <task>.Field(...) so the dependency is tracked; these need the variable that
+// holds the task and are never marked safe.
+func Scan(wf *workflow.Workflow) []Suggestion {
+	r := rules{pkg: "workflow", tasks: make(map[string]bool)}
+	_ = workflow.Walk(wf, func(task *workflow.Task) error {
+		r.tasks[task.Name] = true
+		return nil
+	})
+
+	var suggestions []Suggestion
+	_ = workflow.Walk(wf, func(task *workflow.Task) error {
+		visitStrings(reflect.ValueOf(task.Config), "", func(path, s string) {
+			f, ok := r.suggest(s)
+			if !ok {
+				return
+			}
+			suggestions = append(suggestions, Suggestion{
+				Location:    fmt.Sprintf("task %s: %s", task.Name, path),
+				Expression:  s,
+				Replacement: f.code,
+				Safe:        f.safe,
+				Note:        f.note,
+			})
+		})
+		return nil
+	})
+	return suggestions
+}
+
+// visitStrings calls fn for every string reachable from v through exported
+// fields, slices and maps (in key order), skipping nested tasks.
+func visitStrings(v reflect.Value, path string, fn func(path, s string)) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			visitStrings(v.Elem(), path, fn)
+		}
+	case reflect.String:
+		fn(path, v.String())
+	case reflect.Struct:
+		if v.Type() == taskType {
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name := field.Name
+			if path != "" {
+				name = path + "." + name
+			}
+			visitStrings(v.Field(i), name, fn)
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Type() == taskSliceType {
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			visitStrings(v.Index(i), fmt.Sprintf("%s[%d]", path, i), fn)
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for _, k := range keys {
+			visitStrings(v.MapIndex(k), fmt.Sprintf("%s[%v]", path, k.Interface()), fn)
+		}
+	}
+}
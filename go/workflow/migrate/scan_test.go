@@ -0,0 +1,85 @@
+package migrate
+
+import (
+	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+// TestSuggest verifies each rule produces an equivalent typed replacement.
+func TestSuggest(t *testing.T) {
+	r := rules{pkg: "workflow", tasks: map[string]bool{"fetch": true}}
+
+	tests := []struct {
+		expr string
+		code string
+		safe bool
+	}{
+		{`${ $context.apiURL }`, `workflow.VarRef("apiURL")`, true},
+		{`${$context.user.name}`, `workflow.VarRef("user.name")`, true},
+		{`${ $context.fetch.title }`, `fetch.Field("title")`, false},
+		{`${ $context.retries + 1 }`, `workflow.Increment("retries")`, true},
+		{`${ $context.remaining - 1 }`, `workflow.Decrement("remaining")`, true},
+		{`${ .httpErr.message }`, `workflow.ErrorMessage("httpErr")`, false},
+		{`${ .status == 200 }`, `workflow.Equals(workflow.Field("status"), workflow.Number(200))`, true},
+		{`${ .type != "error" }`, `workflow.NotEquals(workflow.Field("type"), workflow.Literal("error"))`, true},
+		{`${ $context.count >= $context.limit }`, `workflow.GreaterThanOrEqual(workflow.Var("count"), workflow.Var("limit"))`, true},
+		{`${ "Bearer " + $context.token }`, `workflow.Interpolate("Bearer ", workflow.VarRef("token"))`, true},
+		{`${ "<b>" + $context.name + "</b>" }`, `workflow.Interpolate("<b>", workflow.VarRef("name"), "</b>")`, true},
+	}
+	for _, tt := range tests {
+		f, ok := r.suggest(tt.expr)
+		if !ok {
+			t.Errorf("suggest(%q) found no replacement", tt.expr)
+			continue
+		}
+		if f.code != tt.code || f.safe != tt.safe {
+			t.Errorf("suggest(%q) = %s (safe %v), want %s (safe %v)", tt.expr, f.code, f.safe, tt.code, tt.safe)
+		}
+	}
+
+	for _, expr := range []string{
+		"https://api.example.com",
+		"${ .items | length }",
+		"${ 200 == .status }",
+		"${ $context.a * 2 }",
+	} {
+		if f, ok := r.suggest(expr); ok {
+			t.Errorf("suggest(%q) = %s, want no replacement", expr, f.code)
+		}
+	}
+}
+
+// TestScan verifies expressions are found in task configs, including nested tasks.
+func TestScan(t *testing.T) {
+	fetch := workflow.HttpCallTask("fetch", workflow.WithURI("${ $context.apiURL }"))
+	notify := workflow.HttpCallTask("notify",
+		workflow.WithHTTPPost(),
+		workflow.WithURI("https://hooks.example.com"),
+		workflow.Header("Authorization", `${ "Bearer " + $context.token }`),
+		workflow.WithBody(map[string]any{"title": "${ $context.fetch.title }"}),
+	)
+	loop := workflow.ForTask("each", workflow.WithIn("${ .items }"), workflow.WithDo(
+		workflow.SetTask("count", workflow.SetVar("n", "${ $context.n + 1 }")),
+	))
+	wf := &workflow.Workflow{Tasks: []*workflow.Task{fetch, notify, loop}}
+
+	var got []string
+	for _, s := range Scan(wf) {
+		got = append(got, s.String())
+	}
+	want := []string{
+		`task fetch: URI: replace "${ $context.apiURL }" with workflow.VarRef("apiURL")`,
+		`task notify: Headers[Authorization]: replace "${ \"Bearer \" + $context.token }" with workflow.Interpolate("Bearer ", workflow.VarRef("token"))`,
+		`task notify: Body[title]: replace "${ $context.fetch.title }" with fetch.Field("title") (review: use the variable holding task "fetch"; this also records the dependency)`,
+		`task count: Variables[n]: replace "${ $context.n + 1 }" with workflow.Increment("n")`,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Scan() = %d suggestions, want %d:\n%v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("suggestion %d = %s\nwant %s", i, got[i], want[i])
+		}
+	}
+}
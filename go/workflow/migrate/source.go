@@ -0,0 +1,155 @@
+package migrate
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+)
+
+// workflowImportPath is the import path of the workflow package.
+const workflowImportPath = "github.com/leftbin/stigmer-sdk/go/workflow"
+
+// edit replaces src[start:end] with text.
+type edit struct {
+	start, end int
+	text       string
+}
+
+// RewriteSource replaces expression string literals in a Go source file with
+// the equivalent typed helper calls and returns the formatted result along
+// with every suggestion found.
+//
+// Only safe suggestions are applied; the others are returned for review.
+// Literals in constant declarations are reported but left alone, since a
+// helper call is not a constant. Files that do not import the workflow package
+// are returned unchanged.
+//
+// Example (migrate a package in place):
+//
+//	paths, _ := filepath.Glob("pipelines/*.go")
+//	for _, path := range paths {
+//	    src, err := os.ReadFile(path)
+//	    if err != nil {
+//	        return err
+//	    }
+//	    out, suggestions, err := migrate.RewriteSource(path, src)
+//	    if err != nil {
+//	        return err
+//	    }
+//	    for _, s := range suggestions {
+//	        fmt.Println(s)
+//	    }
+//	    if err := os.WriteFile(path, out, 0o644); err != nil {
+//	        return err
+//	    }
+//	}
+func RewriteSource(filename string, src []byte) ([]byte, []Suggestion, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing %s: %w", filename, err)
+	}
+	pkg, ok := workflowImportName(file)
+	if !ok {
+		return src, nil, nil
+	}
+	r := rules{pkg: pkg}
+
+	// Literals that cannot become function calls
+	fixed := make(map[*ast.BasicLit]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ImportSpec:
+			return false
+		case *ast.Field:
+			if n.Tag != nil {
+				fixed[n.Tag] = true
+			}
+		case *ast.GenDecl:
+			if n.Tok == token.CONST {
+				ast.Inspect(n, func(c ast.Node) bool {
+					if lit, ok := c.(*ast.BasicLit); ok {
+						fixed[lit] = true
+					}
+					return true
+				})
+			}
+		}
+		return true
+	})
+
+	var suggestions []Suggestion
+	var edits []edit
+	ast.Inspect(file, func(n ast.Node) bool {
+		if _, ok := n.(*ast.ImportSpec); ok {
+			return false
+		}
+		lit, ok := n.(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		value, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return true
+		}
+		f, ok := r.suggest(value)
+		if !ok {
+			return true
+		}
+		s := Suggestion{
+			Location:    fset.Position(lit.Pos()).String(),
+			Expression:  value,
+			Replacement: f.code,
+			Safe:        f.safe,
+			Note:        f.note,
+		}
+		if fixed[lit] {
+			s.Safe = false
+			s.Note = "declared as a constant; use a variable or inline the helper call"
+		}
+		suggestions = append(suggestions, s)
+		if s.Safe {
+			edits = append(edits, edit{
+				start: fset.Position(lit.Pos()).Offset,
+				end:   fset.Position(lit.End()).Offset,
+				text:  f.code,
+			})
+		}
+		return true
+	})
+	if len(edits) == 0 {
+		return src, suggestions, nil
+	}
+
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	out := append([]byte(nil), src...)
+	for _, e := range edits {
+		out = append(out[:e.start], append([]byte(e.text), out[e.end:]...)...)
+	}
+	formatted, err := format.Source(out)
+	if err != nil {
+		return nil, nil, fmt.Errorf("formatting %s: %w", filename, err)
+	}
+	return formatted, suggestions, nil
+}
+
+// workflowImportName returns the name the file uses for the workflow package.
+func workflowImportName(file *ast.File) (string, bool) {
+	for _, spec := range file.Imports {
+		if path, _ := strconv.Unquote(spec.Path.Value); path != workflowImportPath {
+			continue
+		}
+		if spec.Name == nil {
+			return "workflow", true
+		}
+		if spec.Name.Name == "_" || spec.Name.Name == "." {
+			return "", false
+		}
+		return spec.Name.Name, true
+	}
+	return "", false
+}
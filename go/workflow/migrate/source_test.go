@@ -0,0 +1,63 @@
+package migrate
+
+import (
+	"strings"
+	"testing"
+)
+
+const legacySource = `package pipeline
+
+import wf "github.com/leftbin/stigmer-sdk/go/workflow"
+
+const endpoint = "${ $context.endpoint }"
+
+func tasks() []*wf.Task {
+	return []*wf.Task{
+		wf.HttpCallTask("fetch", wf.WithURI(endpoint), wf.Header("Authorization", "${ \"Bearer \" + $context.token }")),
+		wf.SwitchTask("route", wf.WithCase("${ .status == 200 }", "ok"), wf.WithDefault("fail")),
+		wf.SetTask("fail", wf.SetVar("reason", "${ .err.message }")),
+	}
+}
+`
+
+// TestRewriteSource verifies safe suggestions are applied and the rest reported.
+func TestRewriteSource(t *testing.T) {
+	out, suggestions, err := RewriteSource("pipeline.go", []byte(legacySource))
+	if err != nil {
+		t.Fatalf("RewriteSource() error = %v", err)
+	}
+	src := string(out)
+
+	for _, want := range []string{
+		`wf.Header("Authorization", wf.Interpolate("Bearer ", wf.VarRef("token")))`,
+		`wf.WithCase(wf.Equals(wf.Field("status"), wf.Number(200)), "ok")`,
+		`const endpoint = "${ $context.endpoint }"`,
+		`wf.SetVar("reason", "${ .err.message }")`,
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("rewritten source missing %s:\n%s", want, src)
+		}
+	}
+
+	if len(suggestions) != 4 {
+		t.Fatalf("got %d suggestions, want 4: %v", len(suggestions), suggestions)
+	}
+	if s := suggestions[0]; s.Location != "pipeline.go:5:18" || s.Safe {
+		t.Errorf("constant suggestion = %+v, want unsafe at pipeline.go:5:18", s)
+	}
+	if s := suggestions[3]; s.Replacement != `wf.ErrorMessage("err")` || s.Safe {
+		t.Errorf("error field suggestion = %+v, want unsafe wf.ErrorMessage", s)
+	}
+}
+
+// TestRewriteSource_NoWorkflowImport verifies unrelated files are left alone.
+func TestRewriteSource_NoWorkflowImport(t *testing.T) {
+	src := []byte("package main\n\nvar tmpl = \"${ $context.name }\"\n")
+	out, suggestions, err := RewriteSource("main.go", src)
+	if err != nil {
+		t.Fatalf("RewriteSource() error = %v", err)
+	}
+	if string(out) != string(src) || len(suggestions) != 0 {
+		t.Errorf("RewriteSource() changed a file without the workflow import: %s %v", out, suggestions)
+	}
+}
@@ -0,0 +1,124 @@
+package workflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// Normalize returns a canonical, well-formed form of an expression string.
+//
+// Rules:
+//   - A well-formed "${ ... }" expression is returned as "${ <body> }" with
+//     surrounding whitespace trimmed, so "${.a}" and "${  .a }" both become "${ .a }".
+//   - A string that looks like an expression but is malformed (unbalanced
+//     quotes, brackets, or nested "${") is turned into a quoted literal
+//     expression, e.g. `${ "a }` becomes `${ "${ \"a }" }`.
+//   - Any other string is a literal and is returned unchanged.
+//
+// Normalize never produces a corrupt expression, and Normalize(Normalize(s)) == Normalize(s).
+func Normalize(expr string) string {
+	trimmed := strings.TrimSpace(expr)
+	if !strings.HasPrefix(trimmed, "${") || !strings.HasSuffix(trimmed, "}") {
+		return expr
+	}
+	if body, ok := wellFormedBody(trimmed); ok {
+		return "${ " + body + " }"
+	}
+	return "${ " + quoteLiteral(expr) + " }"
+}
+
+// wellFormedBody returns the trimmed body of a "${ ... }" expression and whether
+// it is well formed (non-empty, balanced, no nested "${").
+func wellFormedBody(expr string) (string, bool) {
+	if len(expr) < 3 || !strings.HasPrefix(expr, "${") || !strings.HasSuffix(expr, "}") {
+		return "", false
+	}
+	body := strings.TrimSpace(expr[2 : len(expr)-1])
+	if body == "" || !isBalanced(body) {
+		return "", false
+	}
+	return body, true
+}
+
+// isBalanced reports whether quotes, parentheses, brackets, and braces in an
+// expression body are balanced, and that it contains no "${" outside strings.
+func isBalanced(body string) bool {
+	var stack []byte
+	var quote byte
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		if quote != 0 {
+			switch c {
+			case '\\':
+				i++
+			case quote:
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '"', '\'':
+			quote = c
+		case '$':
+			if i+1 < len(body) && body[i+1] == '{' {
+				return false
+			}
+		case '(', '[', '{':
+			stack = append(stack, c)
+		case ')', ']', '}':
+			if len(stack) == 0 || stack[len(stack)-1] != map[byte]byte{')': '(', ']': '[', '}': '{'}[c] {
+				return false
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	return quote == 0 && len(stack) == 0
+}
+
+// quoteLiteral quotes a string as a JQ (JSON) string literal.
+func quoteLiteral(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s) // encoding a string cannot fail
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// simpleOperandRegex matches operands that never need parentheses when combined
+// with other operators: paths, variables, numbers, and string literals.
+var simpleOperandRegex = regexp.MustCompile(`^(?:` +
+	`(?:\$[A-Za-z_]\w*|\.[A-Za-z_]\w*|\.)(?:\.[A-Za-z_]\w*|\[\d+\]|\["(?:[^"\\]|\\.)*"\])*` +
+	`|-?\d+(?:\.\d+)?` +
+	`|"(?:[^"\\]|\\.)*"` +
+	`)$`)
+
+// operand returns an expression body suitable for use as an operand of + or a
+// comparison, wrapping it in parentheses unless it is a simple operand.
+func operand(body string) string {
+	if simpleOperandRegex.MatchString(body) {
+		return body
+	}
+	if strings.HasPrefix(body, "(") && strings.HasSuffix(body, ")") && isBalanced(body[1:len(body)-1]) {
+		return body
+	}
+	return "(" + body + ")"
+}
+
+// conditionBody extracts the body of a condition for composition.
+// Accepts "${ ... }" expressions and bare conditions (".status == 200").
+// Malformed input becomes a quoted literal, and empty input becomes null.
+func conditionBody(condition string) string {
+	trimmed := strings.TrimSpace(condition)
+	if trimmed == "" {
+		return "null"
+	}
+	if body, ok := wellFormedBody(Normalize(trimmed)); ok {
+		return body
+	}
+	if !strings.Contains(trimmed, "${") && isBalanced(trimmed) {
+		return trimmed
+	}
+	return quoteLiteral(condition)
+}
@@ -0,0 +1,117 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestNormalize verifies canonical formatting and handling of malformed input.
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"literal", "https://api.example.com", "https://api.example.com"},
+		{"compact expression", "${.secrets.TOKEN}", "${ .secrets.TOKEN }"},
+		{"extra whitespace", "  ${   $context.apiURL  }  ", "${ $context.apiURL }"},
+		{"braces inside string", `${ "}" + .a }`, `${ "}" + .a }`},
+		{"unbalanced quote", `${ "a }`, `${ "${ \"a }" }`},
+		{"unbalanced paren", "${ (.a }", `${ "${ (.a }" }`},
+		{"nested delimiters", "${ ${ .a } }", `${ "${ ${ .a } }" }`},
+		{"two expressions", "${ .a } + ${ .b }", `${ "${ .a } + ${ .b }" }`},
+		{"empty expression", "${ }", `${ "${ }" }`},
+		{"unterminated", "${ .a", "${ .a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Normalize(tt.input); got != tt.expected {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestInterpolate_Malformed verifies literal parts with quotes and malformed
+// expressions never corrupt the generated expression.
+func TestInterpolate_Malformed(t *testing.T) {
+	tests := []struct {
+		name     string
+		parts    []interface{}
+		expected string
+	}{
+		{
+			name:     "embedded quotes",
+			parts:    []interface{}{`say "hi" to `, VarRef("name")},
+			expected: `${ "say \"hi\" to " + $context.name }`,
+		},
+		{
+			name:     "pipe operand is parenthesized",
+			parts:    []interface{}{"user-", "${ $context.name | ascii_downcase }"},
+			expected: `${ "user-" + ($context.name | ascii_downcase) }`,
+		},
+		{
+			name:     "malformed expression part",
+			parts:    []interface{}{"${ \"oops }", VarRef("name")},
+			expected: `${ "${ \"oops }" + $context.name }`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Interpolate(tt.parts...); got != tt.expected {
+				t.Errorf("Interpolate() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+// assertWellFormed fails if s looks like an expression but is not well formed.
+func assertWellFormed(t *testing.T, s string) {
+	t.Helper()
+	trimmed := strings.TrimSpace(s)
+	if !strings.HasPrefix(trimmed, "${") || !strings.HasSuffix(trimmed, "}") {
+		return
+	}
+	if _, ok := wellFormedBody(trimmed); !ok {
+		t.Fatalf("corrupt expression: %q", s)
+	}
+}
+
+func FuzzNormalize(f *testing.F) {
+	for _, seed := range []string{"", "plain", "${ .a }", `${ "a }`, "${ (.a }", "${ ${ .a } }", `${ "\\" }`, "${}"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		out := Normalize(s)
+		assertWellFormed(t, out)
+		if again := Normalize(out); again != out {
+			t.Fatalf("Normalize not idempotent: %q -> %q -> %q", s, out, again)
+		}
+	})
+}
+
+func FuzzInterpolate(f *testing.F) {
+	f.Add("Bearer ", "${ $context.token }", "")
+	f.Add(`"quoted"`, "${ .a | tostring }", "/tail")
+	f.Add("${ \"", "}", "${")
+	f.Fuzz(func(t *testing.T, a, b, c string) {
+		out := Interpolate(a, b, c)
+		assertWellFormed(t, out)
+		if !strings.Contains(a+b+c, "${") && out != a+b+c {
+			t.Fatalf("Interpolate(%q, %q, %q) = %q, want plain concatenation", a, b, c, out)
+		}
+	})
+}
+
+func FuzzConditions(f *testing.F) {
+	f.Add("${ .status == 200 }", ".type == \"ok\"")
+	f.Add("${ \"a }", "")
+	f.Add("(", "${ ${ }")
+	f.Fuzz(func(t *testing.T, a, b string) {
+		assertWellFormed(t, And(a, b))
+		assertWellFormed(t, Or(a, b))
+		assertWellFormed(t, Not(a))
+	})
+}
@@ -0,0 +1,28 @@
+// Package openapi generates HTTP_CALL task constructors from OpenAPI 3 specs.
+//
+// Instead of hand-writing URIs, load a spec and build tasks by operation ID.
+// Methods, path templating, and parameter validation come from the spec:
+//
+//	spec, err := openapi.Load("petstore.yaml")
+//	if err != nil {
+//	    return err
+//	}
+//
+//	op, _ := spec.Operation("getPetById")
+//	task, err := op.Task("fetchPet", map[string]interface{}{
+//	    "petId": petID, // string, context Ref, or TaskFieldRef
+//	})
+//
+// # Generated Constructors
+//
+// Generate() produces Go source with one strongly-typed constructor per
+// operation. Required parameters become function arguments, so a missing
+// parameter is a compile error rather than a runtime failure:
+//
+//	//go:generate go run ./gen -spec petstore.yaml -package petstore
+//
+//	task, err := petstore.GetPetByID("fetchPet", petID)
+//
+// Optional query and header parameters are set with the regular
+// workflow.WithQueryParam() and workflow.WithHeader() options.
+package openapi
@@ -0,0 +1,188 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// GenerateOptions configures code generation.
+type GenerateOptions struct {
+	// Package is the Go package name of the generated file (required)
+	Package string
+
+	// BaseURL overrides the spec's server URL in generated operations
+	BaseURL string
+}
+
+// Generate produces Go source with one typed constructor per operation.
+//
+// Each constructor takes the task name, then one argument per required path,
+// query, or header parameter (in spec order), then optional HttpCallTaskOptions:
+//
+//	// GetPetByID builds a GET /pets/{petId} task.
+//	func GetPetByID(name string, petID interface{}, opts ...workflow.HttpCallTaskOption) (*workflow.Task, error)
+//
+// The output is gofmt-formatted.
+func Generate(spec *Spec, opts GenerateOptions) ([]byte, error) {
+	if !token.IsIdentifier(opts.Package) {
+		return nil, fmt.Errorf("invalid package name %q", opts.Package)
+	}
+
+	baseURL := spec.BaseURL
+	if opts.BaseURL != "" {
+		baseURL = strings.TrimSuffix(opts.BaseURL, "/")
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by openapi.Generate; DO NOT EDIT.\n\n")
+	if spec.Title != "" {
+		fmt.Fprintf(&buf, "// Package %s provides HTTP_CALL task constructors for %s.\n", opts.Package, spec.Title)
+	}
+	fmt.Fprintf(&buf, "package %s\n\n", opts.Package)
+	buf.WriteString("import (\n")
+	buf.WriteString("\t\"github.com/leftbin/stigmer-sdk/go/workflow\"\n")
+	buf.WriteString("\t\"github.com/leftbin/stigmer-sdk/go/workflow/openapi\"\n")
+	buf.WriteString(")\n\n")
+
+	fmt.Fprintf(&buf, "// BaseURL is the server URL used by all constructors in this package.\n")
+	fmt.Fprintf(&buf, "var BaseURL = %s\n", strconv.Quote(baseURL))
+
+	funcNames := make(map[string]string)
+	for _, op := range spec.Operations() {
+		funcName := exportedIdentifier(op.ID)
+		if other, ok := funcNames[funcName]; ok {
+			return nil, fmt.Errorf("operations %q and %q both generate %s", other, op.ID, funcName)
+		}
+		funcNames[funcName] = op.ID
+
+		if err := writeOperation(&buf, funcName, op); err != nil {
+			return nil, err
+		}
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return src, nil
+}
+
+// writeOperation writes the operation variable and its typed constructor.
+func writeOperation(buf *bytes.Buffer, funcName string, op *Operation) error {
+	varName := unexportedIdentifier(funcName) + "Operation"
+
+	fmt.Fprintf(buf, "\nvar %s = openapi.Operation{\n", varName)
+	fmt.Fprintf(buf, "ID: %s,\n", strconv.Quote(op.ID))
+	fmt.Fprintf(buf, "Method: %s,\n", strconv.Quote(op.Method))
+	fmt.Fprintf(buf, "Path: %s,\n", strconv.Quote(op.Path))
+	fmt.Fprintf(buf, "Summary: %s,\n", strconv.Quote(op.Summary))
+	if len(op.Parameters) > 0 {
+		buf.WriteString("Parameters: []openapi.Parameter{\n")
+		for _, p := range op.Parameters {
+			fmt.Fprintf(buf, "{Name: %s, In: %s, Required: %t},\n", strconv.Quote(p.Name), strconv.Quote(string(p.In)), p.Required)
+		}
+		buf.WriteString("},\n")
+	}
+	if op.BodyRequired {
+		buf.WriteString("BodyRequired: true,\n")
+	}
+	buf.WriteString("}\n")
+
+	// Required parameters become positional arguments
+	used := map[string]bool{"name": true, "opts": true, "op": true, "workflow": true, "openapi": true}
+	var params, args []string
+	for _, p := range op.Parameters {
+		if !p.Required || p.In == InCookie {
+			continue
+		}
+		argName := unexportedIdentifier(p.Name)
+		for used[argName] || token.IsKeyword(argName) {
+			argName += "Param"
+		}
+		used[argName] = true
+		params = append(params, argName+" interface{}")
+		args = append(args, fmt.Sprintf("%s: %s", strconv.Quote(p.Name), argName))
+	}
+
+	fmt.Fprintf(buf, "\n// %s builds a %s %s task.\n", funcName, op.Method, op.Path)
+	if op.Summary != "" {
+		fmt.Fprintf(buf, "//\n// %s\n", strings.Join(strings.Fields(op.Summary), " "))
+	}
+	signature := append([]string{"name string"}, params...)
+	signature = append(signature, "opts ...workflow.HttpCallTaskOption")
+	fmt.Fprintf(buf, "func %s(%s) (*workflow.Task, error) {\n", funcName, strings.Join(signature, ", "))
+	fmt.Fprintf(buf, "op := %s\n", varName)
+	buf.WriteString("op.BaseURL = BaseURL\n")
+	fmt.Fprintf(buf, "return op.Task(name, map[string]interface{}{%s}, opts...)\n", strings.Join(args, ", "))
+	buf.WriteString("}\n")
+	return nil
+}
+
+// commonInitialisms are words rendered in upper case in Go identifiers.
+var commonInitialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true,
+	"JSON": true, "SQL": true, "UID": true, "URI": true, "URL": true,
+	"UUID": true, "XML": true,
+}
+
+// identifierWords splits an operation or parameter name into words on
+// non-alphanumeric characters and lower-to-upper case transitions.
+func identifierWords(s string) []string {
+	var words []string
+	var current []rune
+	runes := []rune(s)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(current) > 0 {
+				words = append(words, string(current))
+				current = nil
+			}
+			continue
+		}
+		if len(current) > 0 && unicode.IsUpper(r) && i > 0 && unicode.IsLower(runes[i-1]) {
+			words = append(words, string(current))
+			current = nil
+		}
+		current = append(current, r)
+	}
+	if len(current) > 0 {
+		words = append(words, string(current))
+	}
+	return words
+}
+
+// exportedIdentifier converts a name like "getPetById" to "GetPetByID".
+func exportedIdentifier(s string) string {
+	var b strings.Builder
+	for _, w := range identifierWords(s) {
+		upper := strings.ToUpper(w)
+		if commonInitialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		runes := []rune(strings.ToLower(w))
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	id := b.String()
+	if id == "" || !unicode.IsLetter([]rune(id)[0]) {
+		id = "Op" + id
+	}
+	return id
+}
+
+// unexportedIdentifier converts a name like "petId" to "petID".
+func unexportedIdentifier(s string) string {
+	words := identifierWords(s)
+	if len(words) == 0 {
+		return "param"
+	}
+	id := exportedIdentifier(s)
+	first := exportedIdentifier(words[0])
+	return strings.ToLower(first) + id[len(first):]
+}
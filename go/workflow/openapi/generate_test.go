@@ -0,0 +1,56 @@
+package openapi
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestGenerate verifies typed constructors are generated for each operation.
+func TestGenerate(t *testing.T) {
+	spec := loadPetstore(t)
+
+	src, err := Generate(spec, GenerateOptions{Package: "petstore"})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	out := string(src)
+
+	for _, want := range []string{
+		"// Code generated by openapi.Generate; DO NOT EDIT.",
+		"package petstore",
+		`var BaseURL = "https://petstore.example.com/v1"`,
+		"func GetPetByID(name string, petID interface{}, opts ...workflow.HttpCallTaskOption) (*workflow.Task, error) {",
+		"func ListPets(name string, xRequestID interface{}, opts ...workflow.HttpCallTaskOption) (*workflow.Task, error) {",
+		"func CreatePet(name string, opts ...workflow.HttpCallTaskOption) (*workflow.Task, error) {",
+		"func DeletePetsPetID(name string, petID interface{}, opts ...workflow.HttpCallTaskOption) (*workflow.Task, error) {",
+		`return op.Task(name, map[string]interface{}{"petId": petID}, opts...)`,
+		"BodyRequired: true,",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("generated code missing %q\n%s", want, out)
+		}
+	}
+}
+
+// TestGenerate_InvalidPackage verifies the package name is validated.
+func TestGenerate_InvalidPackage(t *testing.T) {
+	if _, err := Generate(loadPetstore(t), GenerateOptions{Package: "pet-store"}); err == nil {
+		t.Error("Generate() expected error for invalid package name")
+	}
+}
+
+// TestExportedIdentifier verifies operation IDs are converted to Go names.
+func TestExportedIdentifier(t *testing.T) {
+	tests := map[string]string{
+		"getPetById":         "GetPetByID",
+		"list_user_api_keys": "ListUserAPIKeys",
+		"get /users/{id}":    "GetUsersID",
+		"X-Request-Id":       "XRequestID",
+		"2fa":                "Op2fa",
+	}
+	for in, want := range tests {
+		if got := exportedIdentifier(in); got != want {
+			t.Errorf("exportedIdentifier(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package openapi
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+// Task builds an HTTP_CALL task for the operation.
+//
+// args maps parameter names (as declared in the spec) to values. Values can be
+// strings, numbers, booleans, context Refs, or TaskFieldRefs. Path parameters
+// are escaped and substituted into the path template, query parameters are
+// added with workflow.WithQueryParam(), and header parameters with
+// workflow.WithHeader().
+//
+// Additional options (body, auth, timeout) are applied after the generated
+// ones, so they can override anything derived from the spec.
+//
+// Returns an error if a required parameter is missing, an argument is not a
+// declared parameter, or the operation requires a body and none was set.
+func (op *Operation) Task(name string, args map[string]interface{}, opts ...workflow.HttpCallTaskOption) (*workflow.Task, error) {
+	declared := make(map[string]Parameter, len(op.Parameters))
+	for _, p := range op.Parameters {
+		declared[p.Name] = p
+	}
+
+	// Reject unknown arguments (sorted for deterministic errors)
+	names := make([]string, 0, len(args))
+	for argName := range args {
+		names = append(names, argName)
+	}
+	sort.Strings(names)
+	for _, argName := range names {
+		p, ok := declared[argName]
+		if !ok {
+			return nil, fmt.Errorf("%w: operation %s has no parameter %q", ErrUnknownParameter, op.ID, argName)
+		}
+		if p.In == InCookie {
+			return nil, fmt.Errorf("%w: operation %s: cookie parameter %q is not supported", ErrUnknownParameter, op.ID, argName)
+		}
+	}
+
+	taskOpts := []workflow.HttpCallTaskOption{
+		workflow.WithMethod(op.Method),
+	}
+	for _, p := range op.Parameters {
+		value, ok := args[p.Name]
+		if !ok || value == nil {
+			if p.Required {
+				return nil, fmt.Errorf("%w: operation %s requires %s parameter %q", ErrMissingParameter, op.ID, p.In, p.Name)
+			}
+			continue
+		}
+		switch p.In {
+		case InQuery:
+			taskOpts = append(taskOpts, workflow.WithQueryParam(p.Name, value))
+		case InHeader:
+			taskOpts = append(taskOpts, workflow.WithHeader(p.Name, value))
+		}
+	}
+	taskOpts = append(taskOpts, withPathURI(op.BaseURL, op.Path, args))
+	taskOpts = append(taskOpts, opts...)
+
+	task := workflow.HttpCallTask(name, taskOpts...)
+
+	if op.BodyRequired {
+		cfg := task.Config.(*workflow.HttpCallTaskConfig)
+		if len(cfg.Body) == 0 && cfg.BodyStruct == nil {
+			return nil, fmt.Errorf("%w: operation %s", ErrMissingBody, op.ID)
+		}
+	}
+
+	return task, nil
+}
+
+// withPathURI sets the task URI from the base URL and path template.
+//
+// Static values are path-escaped at synthesis time. If any value is a runtime
+// expression, the URI becomes a JQ expression that escapes it with @uri.
+func withPathURI(baseURL, path string, args map[string]interface{}) workflow.HttpCallTaskOption {
+	return func(cfg *workflow.HttpCallTaskConfig) {
+		var parts []string // JQ operands
+		var static strings.Builder
+		literal := baseURL
+		hasExpression := false
+
+		rest := path
+		for {
+			start := strings.IndexByte(rest, '{')
+			end := strings.IndexByte(rest, '}')
+			if start < 0 || end < start {
+				literal += rest
+				break
+			}
+			literal += rest[:start]
+			value := args[rest[start+1:end]]
+			rest = rest[end+1:]
+
+			if fieldRef, ok := value.(workflow.TaskFieldRef); ok {
+				cfg.ImplicitDependencies[fieldRef.TaskName()] = true
+			}
+
+			resolved := resolveValue(value)
+			if isExpression(resolved) {
+				hasExpression = true
+				static.WriteString(literal)
+				parts = append(parts, strconv.Quote(static.String()))
+				static.Reset()
+				parts = append(parts, "(("+expressionBody(resolved)+") | tostring | @uri)")
+				literal = ""
+				continue
+			}
+			literal += url.PathEscape(resolved)
+		}
+
+		if !hasExpression {
+			cfg.URI = literal
+			return
+		}
+		if literal != "" {
+			parts = append(parts, strconv.Quote(literal))
+		}
+		cfg.URI = "${ " + strings.Join(parts, " + ") + " }"
+	}
+}
+
+// resolveValue converts an argument to a string, resolving known context values
+// at synthesis time and falling back to the runtime expression.
+func resolveValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case workflow.StringValue:
+		return v.Value()
+	case workflow.IntValue:
+		return strconv.Itoa(v.Value())
+	case workflow.BoolValue:
+		return strconv.FormatBool(v.Value())
+	case workflow.Ref:
+		return v.Expression()
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// isExpression reports whether s is a ${...} runtime expression.
+func isExpression(s string) bool {
+	return strings.HasPrefix(s, "${") && strings.HasSuffix(s, "}")
+}
+
+// expressionBody strips the ${ } delimiters from an expression.
+func expressionBody(s string) string {
+	return strings.TrimSpace(s[2 : len(s)-1])
+}
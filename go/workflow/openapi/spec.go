@@ -0,0 +1,315 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Sentinel errors for OpenAPI loading and task building.
+var (
+	// ErrInvalidSpec indicates the OpenAPI document could not be used
+	ErrInvalidSpec = errors.New("invalid OpenAPI spec")
+
+	// ErrMissingParameter indicates a required operation parameter was not provided
+	ErrMissingParameter = errors.New("missing required parameter")
+
+	// ErrUnknownParameter indicates a parameter not declared by the operation
+	ErrUnknownParameter = errors.New("unknown parameter")
+
+	// ErrMissingBody indicates the operation requires a request body
+	ErrMissingBody = errors.New("missing required request body")
+)
+
+// ParameterLocation is where a parameter is sent in the HTTP request.
+type ParameterLocation string
+
+// Parameter locations supported by OpenAPI 3.
+const (
+	InPath   ParameterLocation = "path"
+	InQuery  ParameterLocation = "query"
+	InHeader ParameterLocation = "header"
+	InCookie ParameterLocation = "cookie"
+)
+
+// Parameter describes a single operation parameter.
+type Parameter struct {
+	// Name is the parameter name as declared in the spec (e.g., "petId")
+	Name string
+
+	// In is where the parameter is sent
+	In ParameterLocation
+
+	// Required reports whether the parameter must be provided (always true for path parameters)
+	Required bool
+}
+
+// Operation describes a single API operation (one method on one path).
+type Operation struct {
+	// ID is the operationId, or a name derived from method and path if the spec omits it
+	ID string
+
+	// Method is the upper-case HTTP method (e.g., "GET")
+	Method string
+
+	// Path is the path template (e.g., "/pets/{petId}")
+	Path string
+
+	// BaseURL is prepended to Path (from the first server entry)
+	BaseURL string
+
+	// Summary is the operation summary, used for generated doc comments
+	Summary string
+
+	// Parameters are the path, query, header, and cookie parameters
+	Parameters []Parameter
+
+	// BodyRequired reports whether the operation requires a request body
+	BodyRequired bool
+}
+
+// Spec is a loaded OpenAPI 3 document.
+type Spec struct {
+	// Title is the API title from the info section
+	Title string
+
+	// BaseURL is the URL of the first server entry.
+	// Override it before building tasks to target a different environment.
+	BaseURL string
+
+	operations []*Operation
+}
+
+// Load reads and parses an OpenAPI 3 spec from a YAML or JSON file.
+func Load(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OpenAPI spec %s: %w", path, err)
+	}
+	return Parse(data)
+}
+
+// Parse parses an OpenAPI 3 spec from YAML or JSON bytes.
+func Parse(data []byte) (*Spec, error) {
+	var doc document
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSpec, err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, fmt.Errorf("%w: unsupported version %q, expected 3.x", ErrInvalidSpec, doc.OpenAPI)
+	}
+
+	spec := &Spec{Title: doc.Info.Title}
+	if len(doc.Servers) > 0 {
+		spec.BaseURL = strings.TrimSuffix(doc.Servers[0].URL, "/")
+	}
+
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		item := doc.Paths[path]
+		for _, m := range item.operations() {
+			op, err := newOperation(&doc, path, m.method, item.Parameters, m.op)
+			if err != nil {
+				return nil, err
+			}
+			if seen[op.ID] {
+				return nil, fmt.Errorf("%w: duplicate operationId %q", ErrInvalidSpec, op.ID)
+			}
+			seen[op.ID] = true
+			spec.operations = append(spec.operations, op)
+		}
+	}
+
+	return spec, nil
+}
+
+// Operations returns all operations, sorted by path and then method.
+func (s *Spec) Operations() []*Operation {
+	ops := make([]*Operation, len(s.operations))
+	for i, op := range s.operations {
+		cp := *op
+		cp.BaseURL = s.BaseURL
+		ops[i] = &cp
+	}
+	return ops
+}
+
+// Operation returns the operation with the given operationId.
+func (s *Spec) Operation(id string) (*Operation, bool) {
+	for _, op := range s.Operations() {
+		if op.ID == id {
+			return op, true
+		}
+	}
+	return nil, false
+}
+
+// newOperation builds an Operation, merging path-level and operation-level parameters.
+func newOperation(doc *document, path, method string, pathParams []parameter, raw *operation) (*Operation, error) {
+	op := &Operation{
+		ID:      raw.OperationID,
+		Method:  method,
+		Path:    path,
+		Summary: raw.Summary,
+	}
+	if op.ID == "" {
+		op.ID = strings.ToLower(method) + " " + path
+	}
+	if raw.RequestBody != nil {
+		op.BodyRequired = raw.RequestBody.Required
+	}
+
+	// Operation-level parameters override path-level ones with the same name and location
+	byKey := make(map[string]int)
+	for _, list := range [][]parameter{pathParams, raw.Parameters} {
+		for _, p := range list {
+			resolved, err := doc.resolveParameter(p)
+			if err != nil {
+				return nil, fmt.Errorf("operation %s: %w", op.ID, err)
+			}
+			key := string(resolved.In) + ":" + resolved.Name
+			if i, ok := byKey[key]; ok {
+				op.Parameters[i] = resolved
+				continue
+			}
+			byKey[key] = len(op.Parameters)
+			op.Parameters = append(op.Parameters, resolved)
+		}
+	}
+
+	// Every {name} in the path must be declared as a path parameter
+	for _, name := range pathTemplateNames(path) {
+		if _, ok := byKey[string(InPath)+":"+name]; !ok {
+			return nil, fmt.Errorf("%w: operation %s: path parameter %q is not declared", ErrInvalidSpec, op.ID, name)
+		}
+	}
+
+	return op, nil
+}
+
+// pathTemplateNames returns the {name} placeholders in a path template, in order.
+func pathTemplateNames(path string) []string {
+	var names []string
+	for {
+		start := strings.IndexByte(path, '{')
+		if start < 0 {
+			return names
+		}
+		end := strings.IndexByte(path[start:], '}')
+		if end < 0 {
+			return names
+		}
+		names = append(names, path[start+1:start+end])
+		path = path[start+end+1:]
+	}
+}
+
+// =============================================================================
+// Raw OpenAPI document (only the fields used for task generation)
+// =============================================================================
+
+type document struct {
+	OpenAPI string `yaml:"openapi"`
+	Info    struct {
+		Title string `yaml:"title"`
+	} `yaml:"info"`
+	Servers []struct {
+		URL string `yaml:"url"`
+	} `yaml:"servers"`
+	Paths      map[string]pathItem `yaml:"paths"`
+	Components struct {
+		Parameters map[string]parameter `yaml:"parameters"`
+	} `yaml:"components"`
+}
+
+type pathItem struct {
+	Parameters []parameter `yaml:"parameters"`
+	Get        *operation  `yaml:"get"`
+	Put        *operation  `yaml:"put"`
+	Post       *operation  `yaml:"post"`
+	Delete     *operation  `yaml:"delete"`
+	Options    *operation  `yaml:"options"`
+	Head       *operation  `yaml:"head"`
+	Patch      *operation  `yaml:"patch"`
+}
+
+type methodOperation struct {
+	method string
+	op     *operation
+}
+
+// operations returns the operations defined on the path item in a fixed method order.
+func (p pathItem) operations() []methodOperation {
+	var ops []methodOperation
+	for _, m := range []methodOperation{
+		{"GET", p.Get},
+		{"PUT", p.Put},
+		{"POST", p.Post},
+		{"DELETE", p.Delete},
+		{"OPTIONS", p.Options},
+		{"HEAD", p.Head},
+		{"PATCH", p.Patch},
+	} {
+		if m.op != nil {
+			ops = append(ops, m)
+		}
+	}
+	return ops
+}
+
+type operation struct {
+	OperationID string      `yaml:"operationId"`
+	Summary     string      `yaml:"summary"`
+	Parameters  []parameter `yaml:"parameters"`
+	RequestBody *struct {
+		Required bool `yaml:"required"`
+	} `yaml:"requestBody"`
+}
+
+type parameter struct {
+	Ref      string `yaml:"$ref"`
+	Name     string `yaml:"name"`
+	In       string `yaml:"in"`
+	Required bool   `yaml:"required"`
+}
+
+// resolveParameter resolves a parameter reference and validates its location.
+func (d *document) resolveParameter(p parameter) (Parameter, error) {
+	if p.Ref != "" {
+		const prefix = "#/components/parameters/"
+		if !strings.HasPrefix(p.Ref, prefix) {
+			return Parameter{}, fmt.Errorf("%w: unsupported parameter $ref %q", ErrInvalidSpec, p.Ref)
+		}
+		target, ok := d.Components.Parameters[strings.TrimPrefix(p.Ref, prefix)]
+		if !ok || target.Ref != "" {
+			return Parameter{}, fmt.Errorf("%w: unresolved parameter $ref %q", ErrInvalidSpec, p.Ref)
+		}
+		p = target
+	}
+
+	in := ParameterLocation(p.In)
+	switch in {
+	case InPath, InQuery, InHeader, InCookie:
+	default:
+		return Parameter{}, fmt.Errorf("%w: parameter %q has invalid location %q", ErrInvalidSpec, p.Name, p.In)
+	}
+	if p.Name == "" {
+		return Parameter{}, fmt.Errorf("%w: parameter without a name", ErrInvalidSpec)
+	}
+
+	return Parameter{
+		Name:     p.Name,
+		In:       in,
+		Required: p.Required || in == InPath,
+	}, nil
+}
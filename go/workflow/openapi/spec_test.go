@@ -0,0 +1,197 @@
+package openapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+func loadPetstore(t *testing.T) *Spec {
+	t.Helper()
+	spec, err := Load("testdata/petstore.yaml")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	return spec
+}
+
+// TestLoad verifies operations, parameters, and the base URL are read from the spec.
+func TestLoad(t *testing.T) {
+	spec := loadPetstore(t)
+
+	if spec.BaseURL != "https://petstore.example.com/v1" {
+		t.Errorf("BaseURL = %q, want trailing slash trimmed", spec.BaseURL)
+	}
+
+	var ids []string
+	for _, op := range spec.Operations() {
+		ids = append(ids, op.ID)
+	}
+	want := []string{"listPets", "createPet", "getPetById", "delete /pets/{petId}"}
+	if len(ids) != len(want) {
+		t.Fatalf("Operations() = %v, want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Errorf("Operations()[%d] = %q, want %q", i, ids[i], want[i])
+		}
+	}
+
+	list, ok := spec.Operation("listPets")
+	if !ok {
+		t.Fatal("Operation(listPets) not found")
+	}
+	if len(list.Parameters) != 2 {
+		t.Fatalf("listPets parameters = %v, want 2", list.Parameters)
+	}
+	if list.Parameters[0] != (Parameter{Name: "limit", In: InQuery}) {
+		t.Errorf("listPets parameter[0] = %+v, want resolved $ref", list.Parameters[0])
+	}
+
+	get, _ := spec.Operation("getPetById")
+	if len(get.Parameters) != 1 || !get.Parameters[0].Required || get.Parameters[0].In != InPath {
+		t.Errorf("getPetById parameters = %+v, want inherited path parameter", get.Parameters)
+	}
+}
+
+// TestParse_Invalid verifies malformed specs are rejected with ErrInvalidSpec.
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+	}{
+		{
+			name: "swagger 2",
+			spec: "swagger: '2.0'\n",
+		},
+		{
+			name: "undeclared path parameter",
+			spec: "openapi: 3.0.0\npaths:\n  /pets/{petId}:\n    get:\n      operationId: getPet\n",
+		},
+		{
+			name: "unresolved $ref",
+			spec: "openapi: 3.0.0\npaths:\n  /pets:\n    get:\n      parameters:\n        - $ref: '#/components/parameters/missing'\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.spec))
+			if !errors.Is(err, ErrInvalidSpec) {
+				t.Errorf("Parse() error = %v, want ErrInvalidSpec", err)
+			}
+		})
+	}
+}
+
+// TestOperation_Task verifies method, URI templating, and parameter placement.
+func TestOperation_Task(t *testing.T) {
+	spec := loadPetstore(t)
+	fetch := workflow.HttpCallTask("fetch", workflow.WithURI("https://api.example.com/owner"))
+
+	tests := []struct {
+		name       string
+		operation  string
+		args       map[string]interface{}
+		opts       []workflow.HttpCallTaskOption
+		wantMethod string
+		wantURI    string
+		wantDeps   []string
+	}{
+		{
+			name:       "static path parameter is escaped",
+			operation:  "getPetById",
+			args:       map[string]interface{}{"petId": "a/b c"},
+			wantMethod: "GET",
+			wantURI:    "https://petstore.example.com/v1/pets/a%2Fb%20c",
+		},
+		{
+			name:       "runtime path parameter",
+			operation:  "getPetById",
+			args:       map[string]interface{}{"petId": fetch.Field("petId")},
+			wantMethod: "GET",
+			wantURI:    `${ "https://petstore.example.com/v1/pets/" + (($context.fetch.petId) | tostring | @uri) }`,
+			wantDeps:   []string{"fetch"},
+		},
+		{
+			name:       "query and header parameters",
+			operation:  "listPets",
+			args:       map[string]interface{}{"limit": 10, "X-Request-Id": "req-1"},
+			wantMethod: "GET",
+			wantURI:    "https://petstore.example.com/v1/pets?limit=10",
+		},
+		{
+			name:       "required body provided",
+			operation:  "createPet",
+			opts:       []workflow.HttpCallTaskOption{workflow.WithBody(map[string]any{"name": "Rex"})},
+			wantMethod: "POST",
+			wantURI:    "https://petstore.example.com/v1/pets",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op, _ := spec.Operation(tt.operation)
+			task, err := op.Task("call", tt.args, tt.opts...)
+			if err != nil {
+				t.Fatalf("Task() error = %v", err)
+			}
+			cfg := task.Config.(*workflow.HttpCallTaskConfig)
+			if cfg.Method != tt.wantMethod {
+				t.Errorf("Method = %q, want %q", cfg.Method, tt.wantMethod)
+			}
+			if got := cfg.RequestURI(); got != tt.wantURI {
+				t.Errorf("RequestURI() = %q, want %q", got, tt.wantURI)
+			}
+			if len(task.Dependencies) != len(tt.wantDeps) {
+				t.Errorf("Dependencies = %v, want %v", task.Dependencies, tt.wantDeps)
+			}
+		})
+	}
+}
+
+// TestOperation_Task_ParameterValidation verifies missing, unknown, and body checks.
+func TestOperation_Task_ParameterValidation(t *testing.T) {
+	spec := loadPetstore(t)
+
+	tests := []struct {
+		name      string
+		operation string
+		args      map[string]interface{}
+		wantErr   error
+	}{
+		{
+			name:      "missing path parameter",
+			operation: "getPetById",
+			wantErr:   ErrMissingParameter,
+		},
+		{
+			name:      "missing required header",
+			operation: "listPets",
+			args:      map[string]interface{}{"limit": 10},
+			wantErr:   ErrMissingParameter,
+		},
+		{
+			name:      "unknown parameter",
+			operation: "getPetById",
+			args:      map[string]interface{}{"petId": "1", "petID": "1"},
+			wantErr:   ErrUnknownParameter,
+		},
+		{
+			name:      "missing body",
+			operation: "createPet",
+			wantErr:   ErrMissingBody,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op, _ := spec.Operation(tt.operation)
+			_, err := op.Task("call", tt.args)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Task() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OrderTasks returns tasks sorted so that every task comes after the tasks it
+// depends on (see Task.DependsOn). Dependencies come from DependsOn() and from
+// Field() references in task configurations.
+//
+// The sort is stable: tasks keep their declaration order unless a dependency
+// requires otherwise, so a list that is already valid is returned unchanged.
+// Dependencies on tasks outside the list are ignored. A dependency cycle is
+// reported as ErrDependencyCycle.
+//
+// This is used during synthesis, where list order is the execution order.
+func OrderTasks(tasks []*Task) ([]*Task, error) {
+	index := make(map[string]int, len(tasks))
+	for i, task := range tasks {
+		index[task.Name] = i
+	}
+
+	// pending[i] counts the unsatisfied in-list dependencies of tasks[i]
+	pending := make([]int, len(tasks))
+	dependents := make([][]int, len(tasks))
+	for i, task := range tasks {
+		for _, dep := range task.Dependencies {
+			if j, ok := index[dep]; ok {
+				pending[i]++
+				dependents[j] = append(dependents[j], i)
+			}
+		}
+	}
+
+	ordered := make([]*Task, 0, len(tasks))
+	done := make([]bool, len(tasks))
+	for len(ordered) < len(tasks) {
+		// Pick the earliest declared task that is ready
+		next := -1
+		for i := range tasks {
+			if !done[i] && pending[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			cycle := findCycle(tasks, index, done)
+			return nil, NewValidationErrorWithCause(
+				"dependencies",
+				strings.Join(cycle, ","),
+				"acyclic",
+				fmt.Sprintf("dependency cycle: %s", strings.Join(cycle, " -> ")),
+				ErrDependencyCycle,
+			)
+		}
+		done[next] = true
+		ordered = append(ordered, tasks[next])
+		for _, d := range dependents[next] {
+			pending[d]--
+		}
+	}
+	return ordered, nil
+}
+
+// findCycle returns the names along one dependency cycle among the tasks not yet
+// ordered, starting and ending with the same task. Every such task has at least
+// one unordered dependency, so following them must revisit a task.
+func findCycle(tasks []*Task, index map[string]int, done []bool) []string {
+	start := 0
+	for done[start] {
+		start++
+	}
+
+	seen := make(map[int]int) // task index -> position in path
+	var path []int
+	for i := start; ; {
+		if pos, ok := seen[i]; ok {
+			names := make([]string, 0, len(path)-pos+1)
+			for _, p := range path[pos:] {
+				names = append(names, tasks[p].Name)
+			}
+			return append(names, tasks[i].Name)
+		}
+		seen[i] = len(path)
+		path = append(path, i)
+		for _, dep := range tasks[i].Dependencies {
+			if j, ok := index[dep]; ok && !done[j] {
+				i = j
+				break
+			}
+		}
+	}
+}
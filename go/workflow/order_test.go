@@ -0,0 +1,65 @@
+package workflow
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestOrderTasks verifies tasks are moved after their dependencies and otherwise keep their order.
+func TestOrderTasks(t *testing.T) {
+	notify := SetTask("notify", SetVar("sent", "true"))
+	fetch := HttpCallTask("fetch", WithURI("https://api.example.com"))
+	process := SetTask("process", SetVar("title", fetch.Field("title")))
+	audit := SetTask("audit", SetVar("ok", "true"))
+	notify.DependsOn(process)
+
+	ordered, err := OrderTasks([]*Task{notify, fetch, audit, process})
+	if err != nil {
+		t.Fatalf("OrderTasks() error = %v", err)
+	}
+	if got, want := taskNames(ordered), []string{"fetch", "audit", "process", "notify"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("OrderTasks() = %v, want %v", got, want)
+	}
+
+	// Already valid order is unchanged; outside dependencies are ignored
+	process.DependsOn(&Task{Name: "elsewhere"})
+	ordered, _ = OrderTasks([]*Task{fetch, process, notify, audit})
+	if got, want := taskNames(ordered), []string{"fetch", "process", "notify", "audit"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("OrderTasks() = %v, want %v", got, want)
+	}
+}
+
+// TestOrderTasks_Cycle verifies dependency cycles are reported.
+func TestOrderTasks_Cycle(t *testing.T) {
+	a := SetTask("a", SetVar("x", "1"))
+	b := SetTask("b", SetVar("x", "2"))
+	c := SetTask("c", SetVar("x", "3"))
+	a.DependsOn(c)
+	b.DependsOn(a)
+	c.DependsOn(b)
+
+	_, err := OrderTasks([]*Task{a, b, c})
+	if !errors.Is(err, ErrDependencyCycle) {
+		t.Fatalf("OrderTasks() error = %v, want ErrDependencyCycle", err)
+	}
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.Message != "dependency cycle: a -> c -> b -> a" {
+		t.Errorf("error message = %v", err)
+	}
+}
+
+// TestDependsOn_UnifiedWithImplicit verifies explicit and implicit dependencies are deduplicated together.
+func TestDependsOn_UnifiedWithImplicit(t *testing.T) {
+	fetch := HttpCallTask("fetch", WithURI("https://api.example.com"))
+	other := HttpCallTask("other", WithURI("https://api.example.com"))
+	process := SetTask("process",
+		SetVar("b", other.Field("b")),
+		SetVar("a", fetch.Field("a")),
+	)
+	process.DependsOn(fetch, other)
+
+	if want := []string{"fetch", "other"}; !reflect.DeepEqual(process.Dependencies, want) {
+		t.Errorf("Dependencies = %v, want %v", process.Dependencies, want)
+	}
+}
@@ -15,23 +15,34 @@ type Ref interface {
 	Name() string
 }
 
+// Valuer is a reference that can provide its value of type T at synthesis time.
+type Valuer[T any] interface {
+	Value() T
+}
+
+// TypedRef is a reference whose value has type T, such as stigmer.IntRef for
+// TypedRef[int]. Helpers that only make sense for one type accept a TypedRef
+// so a mismatched reference is a compile error rather than a synthesis error.
+type TypedRef[T any] interface {
+	Ref
+	Valuer[T]
+}
+
 // IntValue represents an int-valued reference that can provide its value.
 // This is used for numeric parameters like timeouts.
-type IntValue interface {
-	Value() int
-}
+type IntValue = Valuer[int]
+
+// FloatValue represents a float-valued reference that can provide its value.
+// This is used for numeric parameters like thresholds.
+type FloatValue = Valuer[float64]
 
 // BoolValue represents a bool-valued reference that can provide its value.
 // This is used for boolean parameters.
-type BoolValue interface {
-	Value() bool
-}
+type BoolValue = Valuer[bool]
 
 // StringValue represents a string-valued reference that can provide its value.
 // This is used for string parameters like org, name, etc.
-type StringValue interface {
-	Value() string
-}
+type StringValue = Valuer[string]
 
 // toExpression converts various input types to expression strings.
 // 
@@ -49,6 +60,7 @@ type StringValue interface {
 //   - float32, float64: converted to string representation
 //   - StringValue: returns the known value (synthesis-time resolution)
 //   - IntValue: returns the known value as string
+//   - FloatValue: returns the known value as string
 //   - BoolValue: returns the known value as string
 //   - Ref: calls Expression() to get JQ expression (runtime resolution)
 //
@@ -87,6 +99,9 @@ func toExpression(value interface{}) string {
 	case IntValue:
 		// This is a known int value - convert to string
 		return fmt.Sprintf("%d", v.Value())
+	case FloatValue:
+		// This is a known float value - convert to string
+		return fmt.Sprintf("%f", v.Value())
 	case BoolValue:
 		// This is a known bool value - convert to string
 		return fmt.Sprintf("%t", v.Value())
@@ -101,33 +116,6 @@ func toExpression(value interface{}) string {
 	}
 }
 
-// toInt32 converts various input types to int32.
-// This helper enables timeout and numeric parameters to accept both
-// legacy int values and new typed IntRef values.
-//
-// Supported types:
-//   - int, int32, int64: converted to int32
-//   - IntValue: returns the initial value (used during synthesis)
-//
-// Examples:
-//
-//	toInt32(30)                       // 30
-//	toInt32(ctx.SetInt("timeout", 60)) // 60
-func toInt32(value interface{}) int32 {
-	switch v := value.(type) {
-	case int:
-		return int32(v)
-	case int32:
-		return v
-	case int64:
-		return int32(v)
-	case IntValue:
-		return int32(v.Value())
-	default:
-		return 0
-	}
-}
-
 // toBool converts various input types to bool.
 // This helper enables boolean parameters to accept both
 // legacy bool values and new typed BoolRef values.
@@ -0,0 +1,62 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationWarning represents a non-fatal validation finding.
+//
+// Warnings do not prevent a workflow from being created or synthesized,
+// but usually point at something the author should fix.
+type ValidationWarning struct {
+	Field   string // The field the warning applies to
+	Message string // Human-readable warning message
+}
+
+// String returns a string representation of the warning.
+func (w ValidationWarning) String() string {
+	if w.Field != "" {
+		return fmt.Sprintf("%s: %s", w.Field, w.Message)
+	}
+	return w.Message
+}
+
+// ValidationReport collects non-fatal warnings produced while building a workflow.
+//
+// Returned by NewWithReport().
+type ValidationReport struct {
+	Warnings []ValidationWarning
+}
+
+// HasWarnings returns true if the report contains at least one warning.
+func (r *ValidationReport) HasWarnings() bool {
+	return r != nil && len(r.Warnings) > 0
+}
+
+// String returns all warnings, one per line.
+func (r *ValidationReport) String() string {
+	if !r.HasWarnings() {
+		return ""
+	}
+	lines := make([]string, len(r.Warnings))
+	for i, w := range r.Warnings {
+		lines[i] = w.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// addWarning appends a warning to the report.
+func (r *ValidationReport) addWarning(field, message string) {
+	r.Warnings = append(r.Warnings, ValidationWarning{
+		Field:   field,
+		Message: message,
+	})
+}
+
+// collectWarnings inspects a validated workflow for non-fatal issues.
+func collectWarnings(w *Workflow, report *ValidationReport) {
+	if w.Document.Description == "" {
+		report.addWarning("document.description", "description is empty; it is shown in UI and marketplace")
+	}
+}
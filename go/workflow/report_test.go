@@ -0,0 +1,66 @@
+package workflow_test
+
+import (
+	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/stigmer"
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+func TestNewWithReport_Warnings(t *testing.T) {
+	ctx := stigmer.NewContext()
+	wf, report, err := workflow.NewWithReport(ctx,
+		workflow.WithNamespace("data-processing"),
+		workflow.WithName("daily-sync"),
+	)
+	if err != nil {
+		t.Fatalf("NewWithReport() error = %v", err)
+	}
+	if wf == nil {
+		t.Fatal("NewWithReport() returned nil workflow")
+	}
+	if !report.HasWarnings() {
+		t.Fatal("expected warnings, got none")
+	}
+
+	fields := make(map[string]bool)
+	for _, w := range report.Warnings {
+		fields[w.Field] = true
+	}
+	for _, field := range []string{"document.version", "document.description"} {
+		if !fields[field] {
+			t.Errorf("expected warning for %q, got %v", field, report.Warnings)
+		}
+	}
+
+	if len(ctx.Workflows()) != 1 {
+		t.Errorf("Workflows() count = %d, want 1", len(ctx.Workflows()))
+	}
+}
+
+func TestNewWithReport_NoWarnings(t *testing.T) {
+	_, report, err := workflow.NewWithReport(stigmer.NewContext(),
+		workflow.WithNamespace("data-processing"),
+		workflow.WithName("daily-sync"),
+		workflow.WithVersion("1.0.0"),
+		workflow.WithDescription("Sync data daily"),
+	)
+	if err != nil {
+		t.Fatalf("NewWithReport() error = %v", err)
+	}
+	if report.HasWarnings() {
+		t.Errorf("expected no warnings, got:\n%s", report)
+	}
+}
+
+func TestNewWithReport_Error(t *testing.T) {
+	wf, report, err := workflow.NewWithReport(stigmer.NewContext(),
+		workflow.WithName("daily-sync"),
+	)
+	if err == nil {
+		t.Fatal("NewWithReport() expected error for missing namespace")
+	}
+	if wf != nil || report != nil {
+		t.Errorf("NewWithReport() = (%v, %v), want nil results on error", wf, report)
+	}
+}
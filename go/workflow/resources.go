@@ -0,0 +1,29 @@
+package workflow
+
+import "github.com/leftbin/stigmer-sdk/go/resources"
+
+// WithResources declares the CPU and memory requested for each execution of
+// the workflow. Quantities use the Kubernetes notation (e.g., "500m" CPU,
+// "1Gi" memory); pass an empty string to keep the platform default.
+//
+// Example:
+//
+//	workflow.New(ctx,
+//	    workflow.WithNamespace("data"),
+//	    workflow.WithName("nightly-export"),
+//	    workflow.WithResources("2", "4Gi"),
+//	)
+func WithResources(cpu, memory string) Option {
+	return func(w *Workflow) error {
+		w.Resources = resources.Requirements{CPU: cpu, Memory: memory}
+		return nil
+	}
+}
+
+// validateResources validates the workflow resource requests.
+func validateResources(r resources.Requirements) error {
+	if err := r.Validate(); err != nil {
+		return NewValidationErrorWithCause("resources", r.String(), "quantity", err.Error(), ErrInvalidResources)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package workflow_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/resources"
+	"github.com/leftbin/stigmer-sdk/go/stigmer"
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+func TestWithResources(t *testing.T) {
+	wf, err := workflow.New(stigmer.NewContext(),
+		workflow.WithNamespace("data"),
+		workflow.WithName("nightly-export"),
+		workflow.WithResources("2", "4Gi"),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if want := (resources.Requirements{CPU: "2", Memory: "4Gi"}); wf.Resources != want {
+		t.Errorf("Resources = %+v, want %+v", wf.Resources, want)
+	}
+}
+
+func TestWithResources_Invalid(t *testing.T) {
+	_, err := workflow.New(stigmer.NewContext(),
+		workflow.WithNamespace("data"),
+		workflow.WithName("nightly-export"),
+		workflow.WithResources("two", ""),
+	)
+	if !errors.Is(err, workflow.ErrInvalidResources) {
+		t.Errorf("New() error = %v, want ErrInvalidResources", err)
+	}
+}
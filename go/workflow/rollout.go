@@ -0,0 +1,61 @@
+package workflow
+
+import "fmt"
+
+// Rollout routes a fraction of triggers to this workflow version.
+//
+// While a rollout is in progress the previously deployed version stays active
+// and receives the remaining triggers.
+type Rollout struct {
+	// Percent is the share of triggers (1-100) routed to this version
+	Percent int `json:"percent"`
+}
+
+// RolloutOption is a functional option for configuring a Rollout.
+type RolloutOption func(*Rollout)
+
+// WithRollout declares a canary rollout for this workflow version.
+// Declare it alongside the version bump it applies to.
+//
+// Example:
+//
+//	workflow.New(ctx,
+//	    workflow.WithNamespace("orders"),
+//	    workflow.WithName("fulfil-order"),
+//	    workflow.WithVersion("1.4.0"),
+//	    workflow.WithRollout(workflow.Percent(10)),
+//	)
+func WithRollout(opts ...RolloutOption) Option {
+	return func(w *Workflow) error {
+		rollout := &Rollout{}
+		for _, opt := range opts {
+			opt(rollout)
+		}
+		w.Rollout = rollout
+		return nil
+	}
+}
+
+// Percent sets the share of triggers (1-100) routed to the new version.
+func Percent(percent int) RolloutOption {
+	return func(r *Rollout) {
+		r.Percent = percent
+	}
+}
+
+// validateRollout validates a workflow rollout.
+func validateRollout(r *Rollout) error {
+	if r == nil {
+		return nil
+	}
+	if r.Percent < 1 || r.Percent > 100 {
+		return NewValidationErrorWithCause(
+			"rollout.percent",
+			fmt.Sprintf("%d", r.Percent),
+			"range",
+			"rollout percent must be between 1 and 100 (use Percent)",
+			ErrInvalidRollout,
+		)
+	}
+	return nil
+}
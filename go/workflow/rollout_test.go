@@ -0,0 +1,49 @@
+package workflow_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/stigmer"
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+func TestWithRollout(t *testing.T) {
+	wf, err := workflow.New(stigmer.NewContext(),
+		workflow.WithNamespace("orders"),
+		workflow.WithName("fulfil-order"),
+		workflow.WithVersion("1.4.0"),
+		workflow.WithRollout(workflow.Percent(10)),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if wf.Rollout == nil || wf.Rollout.Percent != 10 {
+		t.Errorf("Rollout = %+v, want Percent 10", wf.Rollout)
+	}
+}
+
+func TestWithRollout_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []workflow.RolloutOption
+	}{
+		{"missing percent", nil},
+		{"negative percent", []workflow.RolloutOption{workflow.Percent(-5)}},
+		{"over 100", []workflow.RolloutOption{workflow.Percent(150)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := workflow.New(stigmer.NewContext(),
+				workflow.WithNamespace("orders"),
+				workflow.WithName("fulfil-order"),
+				workflow.WithRollout(tt.opts...),
+			)
+			if !errors.Is(err, workflow.ErrInvalidRollout) {
+				t.Errorf("New() error = %v, want ErrInvalidRollout", err)
+			}
+		})
+	}
+}
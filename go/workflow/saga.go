@@ -0,0 +1,124 @@
+package workflow
+
+import "fmt"
+
+// Compensate registers tasks that undo this task's effects.
+//
+// If any task that runs after this one in the workflow fails, the compensation
+// tasks of every previously completed task run in reverse order (most recent
+// first), and the original error is raised again. Tasks without compensations
+// are skipped.
+//
+// Compensation is synthesized as nested TRY tasks (see LowerCompensations), so
+// Then() jumps from tasks that follow a compensated task must target tasks that
+// also follow it. Only top-level workflow tasks can be compensated: tasks inside
+// FOR, FORK and TRY bodies, compensations and finalizers are rejected by validation.
+//
+// Example:
+//
+//	charge := wf.HttpPost("chargeCard", paymentsURL, ...).
+//	    Compensate(workflow.HttpCallTask("refundCard", ...))
+//	reserve := wf.HttpPost("reserveStock", inventoryURL, ...).
+//	    Compensate(workflow.HttpCallTask("releaseStock", ...))
+//	wf.HttpPost("ship", shippingURL, ...)
+//	// If "ship" fails: releaseStock, then refundCard, then the error is re-raised.
+func (t *Task) Compensate(tasks ...*Task) *Task {
+	t.Compensations = append(t.Compensations, tasks...)
+	return t
+}
+
+// compensationErrorVar is the catch variable bound to the failure that
+// triggered compensation.
+const compensationErrorVar = "sagaError"
+
+// LowerCompensations rewrites a task list so that compensations registered with
+// Compensate run when a later task fails.
+//
+// The tasks after each compensated task are wrapped in a TRY task named
+// "<task>-saga" whose catch block runs the compensations and re-raises the
+// error. Because the TRY tasks nest, compensations run in reverse order.
+// Task lists without compensations are returned unchanged.
+//
+// This is used during synthesis.
+func LowerCompensations(tasks []*Task) ([]*Task, error) {
+	for _, task := range tasks {
+		if err := validateNestedCompensations(task); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, task := range tasks {
+		if len(task.Compensations) == 0 || i == len(tasks)-1 {
+			// Nothing after the last task can fail, so it never needs compensating
+			continue
+		}
+
+		rest := tasks[i+1:]
+		if err := validateSagaFlow(task, rest); err != nil {
+			return nil, err
+		}
+		lowered, err := LowerCompensations(rest)
+		if err != nil {
+			return nil, err
+		}
+
+		catch := make([]*Task, 0, len(task.Compensations)+1)
+		catch = append(catch, task.Compensations...)
+		catch = append(catch, RaiseTask(task.Name+"-rethrow",
+			WithError(ErrorCode(compensationErrorVar)),
+			WithErrorMessage(ErrorMessage(compensationErrorVar)),
+		))
+
+		saga := TryTask(task.Name+"-saga",
+			WithTry(lowered...),
+			WithCatchTyped(CatchAny(), compensationErrorVar, catch...),
+		)
+
+		result := make([]*Task, 0, i+2)
+		result = append(result, tasks[:i+1]...)
+		return append(result, saga), nil
+	}
+	return tasks, nil
+}
+
+// validateNestedCompensations rejects Compensate() on the tasks nested in task
+// and on its compensations, which LowerCompensations does not lower.
+func validateNestedCompensations(task *Task) error {
+	for _, child := range nestedTasks(task) {
+		if len(child.Compensations) > 0 {
+			return NewValidationErrorWithCause(
+				"compensations",
+				child.Name,
+				"top_level",
+				fmt.Sprintf("task %q inside %q cannot use Compensate(); only top-level workflow tasks can be compensated", child.Name, task.Name),
+				ErrInvalidTaskConfig,
+			)
+		}
+		if err := validateNestedCompensations(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateSagaFlow rejects Then() jumps that would leave the TRY task wrapping
+// the tasks after a compensated task.
+func validateSagaFlow(compensated *Task, rest []*Task) error {
+	inside := make(map[string]bool, len(rest))
+	for _, t := range rest {
+		inside[t.Name] = true
+	}
+	for _, t := range rest {
+		if t.ThenTask == "" || t.ThenTask == EndFlow || inside[t.ThenTask] {
+			continue
+		}
+		return NewValidationErrorWithCause(
+			"then",
+			t.ThenTask,
+			"saga",
+			fmt.Sprintf("task %q jumps to %q outside the tasks compensated by %q", t.Name, t.ThenTask, compensated.Name),
+			ErrInvalidTaskConfig,
+		)
+	}
+	return nil
+}
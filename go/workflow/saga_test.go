@@ -0,0 +1,123 @@
+package workflow
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestLowerCompensations verifies compensations nest so they run in reverse order.
+func TestLowerCompensations(t *testing.T) {
+	charge := SetTask("charge", SetVar("charged", "true")).
+		Compensate(SetTask("refund", SetVar("charged", "false")))
+	reserve := SetTask("reserve", SetVar("reserved", "true")).
+		Compensate(SetTask("release", SetVar("reserved", "false")))
+	ship := SetTask("ship", SetVar("shipped", "true"))
+
+	tasks, err := LowerCompensations([]*Task{charge, reserve, ship})
+	if err != nil {
+		t.Fatalf("LowerCompensations() error = %v", err)
+	}
+	if len(tasks) != 2 || tasks[0] != charge || tasks[1].Name != "charge-saga" {
+		t.Fatalf("top level = %v, want [charge charge-saga]", taskNames(tasks))
+	}
+
+	outer := tasks[1].Config.(*TryTaskConfig)
+	if got := outer.Tasks; len(got) != 2 || got[0].Name != "reserve" || got[1].Name != "reserve-saga" {
+		t.Fatalf("charge-saga try tasks = %v", got)
+	}
+	if got := outer.Catch[0].Tasks; len(got) != 2 || got[0].Name != "refund" || got[1].Kind != TaskKindRaise {
+		t.Errorf("charge-saga catch tasks = %v, want [refund charge-rethrow]", got)
+	}
+
+	inner := outer.Tasks[1].Config.(*TryTaskConfig)
+	if got := inner.Tasks; len(got) != 1 || got[0].Name != "ship" {
+		t.Errorf("reserve-saga try tasks = %v, want [ship]", got)
+	}
+	if got := inner.Catch[0].Tasks[0].Name; got != "release" {
+		t.Errorf("reserve-saga first catch task = %q, want release", got)
+	}
+}
+
+// TestLowerCompensations_Unchanged verifies task lists that need no wrapping are returned as-is.
+func TestLowerCompensations_Unchanged(t *testing.T) {
+	first := SetTask("first", SetVar("x", "1"))
+	last := SetTask("last", SetVar("y", "2")).Compensate(SetTask("undo", SetVar("y", "0")))
+
+	tasks, err := LowerCompensations([]*Task{first, last})
+	if err != nil {
+		t.Fatalf("LowerCompensations() error = %v", err)
+	}
+	if len(tasks) != 2 || tasks[0] != first || tasks[1] != last {
+		t.Errorf("tasks = %v, want unchanged", taskNames(tasks))
+	}
+}
+
+// TestLowerCompensations_JumpOutside verifies jumps out of the compensated range are rejected.
+func TestLowerCompensations_JumpOutside(t *testing.T) {
+	start := SetTask("start", SetVar("x", "1"))
+	charge := SetTask("charge", SetVar("charged", "true")).
+		Compensate(SetTask("refund", SetVar("charged", "false")))
+	retry := SetTask("retry", SetVar("x", "2")).Then("start")
+
+	if _, err := LowerCompensations([]*Task{start, charge, retry}); !errors.Is(err, ErrInvalidTaskConfig) {
+		t.Errorf("LowerCompensations() error = %v, want ErrInvalidTaskConfig", err)
+	}
+}
+
+// TestLowerCompensations_Nested verifies compensations that would not be lowered are rejected.
+func TestLowerCompensations_Nested(t *testing.T) {
+	compensated := func(name string) *Task {
+		return SetTask(name, SetVar("done", "true")).Compensate(SetTask(name+"-undo", SetVar("done", "false")))
+	}
+
+	tests := []struct {
+		name string
+		task *Task
+	}{
+		{
+			name: "for body",
+			task: ForTask("each", WithIn("${ .items }"), WithDo(compensated("charge"))),
+		},
+		{
+			name: "fork branch",
+			task: ForkTask("parallel", WithBranch("payments", compensated("charge"))),
+		},
+		{
+			name: "try body",
+			task: TryTask("guard", WithTry(compensated("charge"))),
+		},
+		{
+			name: "catch body",
+			task: TryTask("guard",
+				WithTry(SetTask("work", SetVar("x", "1"))),
+				WithCatch(nil, "err", compensated("recover")),
+			),
+		},
+		{
+			name: "compensation",
+			task: SetTask("charge", SetVar("charged", "true")).Compensate(compensated("refund")),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks := []*Task{tt.task, SetTask("ship", SetVar("shipped", "true"))}
+			if _, err := LowerCompensations(tasks); !errors.Is(err, ErrInvalidTaskConfig) {
+				t.Errorf("LowerCompensations() error = %v, want ErrInvalidTaskConfig", err)
+			}
+		})
+	}
+}
+
+// TestLowerFinalizers_Compensations verifies finalizers cannot be compensated.
+func TestLowerFinalizers_Compensations(t *testing.T) {
+	work := SetTask("work", SetVar("done", "true"))
+	audit := SetTask("audit", SetVar("audited", "true")).Compensate(SetTask("unaudit", SetVar("audited", "false")))
+	if _, err := LowerFinalizers([]*Task{work}, []*Task{audit}); !errors.Is(err, ErrInvalidTaskConfig) {
+		t.Errorf("LowerFinalizers() error = %v, want ErrInvalidTaskConfig", err)
+	}
+
+	nested := TryTask("guardedAudit", WithTry(audit))
+	if _, err := LowerFinalizers([]*Task{work}, []*Task{nested}); !errors.Is(err, ErrInvalidTaskConfig) {
+		t.Errorf("LowerFinalizers() nested error = %v, want ErrInvalidTaskConfig", err)
+	}
+}
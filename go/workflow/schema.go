@@ -0,0 +1,142 @@
+package workflow
+
+import (
+	"fmt"
+)
+
+// ParamType is the type of a declared workflow input.
+type ParamType string
+
+// Input parameter types.
+const (
+	ParamTypeString  ParamType = "string"
+	ParamTypeNumber  ParamType = "number"
+	ParamTypeInteger ParamType = "integer"
+	ParamTypeBoolean ParamType = "boolean"
+	ParamTypeObject  ParamType = "object"
+	ParamTypeArray   ParamType = "array"
+	ParamTypeAny     ParamType = "any"
+)
+
+// InputParam declares a single workflow input.
+//
+// Declared inputs are the contract between a workflow and the RUN tasks that
+// call it. Use RequiredInput() and OptionalInput() to build them.
+type InputParam struct {
+	// Name is the input key passed by callers
+	Name string `json:"name"`
+
+	// Type is the expected value type
+	Type ParamType `json:"type"`
+
+	// Required reports whether callers must provide the input
+	Required bool `json:"required,omitempty"`
+
+	// Description explains the input for callers
+	Description string `json:"description,omitempty"`
+}
+
+// RequiredInput declares an input callers must provide.
+func RequiredInput(name string, paramType ParamType) InputParam {
+	return InputParam{Name: name, Type: paramType, Required: true}
+}
+
+// OptionalInput declares an input callers may omit.
+func OptionalInput(name string, paramType ParamType) InputParam {
+	return InputParam{Name: name, Type: paramType}
+}
+
+// Describe returns a copy of the input with a description.
+func (p InputParam) Describe(description string) InputParam {
+	p.Description = description
+	return p
+}
+
+// WithInputs declares the inputs the workflow accepts.
+// Can be called multiple times to add more inputs.
+//
+// Example:
+//
+//	workflow.New(ctx,
+//	    workflow.WithNamespace("data"),
+//	    workflow.WithName("data-processor"),
+//	    workflow.WithInputs(
+//	        workflow.RequiredInput("datasetId", workflow.ParamTypeString),
+//	        workflow.OptionalInput("limit", workflow.ParamTypeInteger),
+//	    ),
+//	)
+func WithInputs(params ...InputParam) Option {
+	return func(w *Workflow) error {
+		w.Inputs = append(w.Inputs, params...)
+		return nil
+	}
+}
+
+// WithOutputs declares the fields the workflow exposes to callers.
+// Callers read them through the RUN task, e.g. runTask.Field("rowCount").
+//
+// Example:
+//
+//	workflow.WithOutputs("rowCount", "reportURL")
+func WithOutputs(names ...string) Option {
+	return func(w *Workflow) error {
+		w.Outputs = append(w.Outputs, names...)
+		return nil
+	}
+}
+
+// Input returns the declared input with the given name.
+func (w *Workflow) Input(name string) (InputParam, bool) {
+	for _, p := range w.Inputs {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return InputParam{}, false
+}
+
+// HasOutput reports whether the workflow declares the named output.
+func (w *Workflow) HasOutput(name string) bool {
+	for _, o := range w.Outputs {
+		if o == name {
+			return true
+		}
+	}
+	return false
+}
+
+// validateSchema validates input and output declarations.
+func validateSchema(w *Workflow) error {
+	inputs := make(map[string]bool, len(w.Inputs))
+	for i, p := range w.Inputs {
+		field := fmt.Sprintf("inputs[%d]", i)
+		if p.Name == "" {
+			return NewValidationErrorWithCause(field+".name", "", "required", "input name is required", ErrInvalidSchema)
+		}
+		if inputs[p.Name] {
+			return NewValidationErrorWithCause(field+".name", p.Name, "unique", fmt.Sprintf("duplicate input: %q", p.Name), ErrInvalidSchema)
+		}
+		inputs[p.Name] = true
+
+		switch p.Type {
+		case ParamTypeString, ParamTypeNumber, ParamTypeInteger, ParamTypeBoolean,
+			ParamTypeObject, ParamTypeArray, ParamTypeAny:
+		default:
+			return NewValidationErrorWithCause(field+".type", string(p.Type), "enum", fmt.Sprintf("invalid input type: %q", p.Type), ErrInvalidSchema)
+		}
+	}
+
+	outputs := make(map[string]bool, len(w.Outputs))
+	for i, name := range w.Outputs {
+		field := fmt.Sprintf("outputs[%d]", i)
+		if name == "" {
+			return NewValidationErrorWithCause(field, "", "required", "output name is required", ErrInvalidSchema)
+		}
+		if outputs[name] {
+			return NewValidationErrorWithCause(field, name, "unique", fmt.Sprintf("duplicate output: %q", name), ErrInvalidSchema)
+		}
+		outputs[name] = true
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package workflow_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/stigmer"
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+func TestWithInputsOutputs(t *testing.T) {
+	wf, err := workflow.New(stigmer.NewContext(),
+		workflow.WithNamespace("data"),
+		workflow.WithName("data-processor"),
+		workflow.WithInputs(
+			workflow.RequiredInput("datasetId", workflow.ParamTypeString).Describe("Dataset to process"),
+			workflow.OptionalInput("limit", workflow.ParamTypeInteger),
+		),
+		workflow.WithOutputs("rowCount"),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	p, ok := wf.Input("datasetId")
+	if !ok || !p.Required || p.Description != "Dataset to process" {
+		t.Errorf("Input(datasetId) = %+v, %v", p, ok)
+	}
+	if _, ok := wf.Input("missing"); ok {
+		t.Error("Input(missing) should not be found")
+	}
+	if !wf.HasOutput("rowCount") || wf.HasOutput("rows") {
+		t.Errorf("HasOutput() mismatch for outputs %v", wf.Outputs)
+	}
+}
+
+func TestWithInputsOutputs_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  workflow.Option
+	}{
+		{"duplicate input", workflow.WithInputs(
+			workflow.RequiredInput("id", workflow.ParamTypeString),
+			workflow.OptionalInput("id", workflow.ParamTypeString),
+		)},
+		{"invalid input type", workflow.WithInputs(workflow.RequiredInput("id", "uuid"))},
+		{"empty output", workflow.WithOutputs("")},
+		{"duplicate output", workflow.WithOutputs("count", "count")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := workflow.New(stigmer.NewContext(),
+				workflow.WithNamespace("data"),
+				workflow.WithName("data-processor"),
+				tt.opt,
+			)
+			if !errors.Is(err, workflow.ErrInvalidSchema) {
+				t.Errorf("New() error = %v, want ErrInvalidSchema", err)
+			}
+		})
+	}
+}
@@ -2,7 +2,13 @@ package workflow
 
 import (
 	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 // TaskKind represents the type of workflow task.
@@ -46,12 +52,27 @@ type Task struct {
 	// Export configuration (how to save task output to context)
 	ExportAs string
 
+	// ContextKey is the context key the whole output is exported under (set by ExportTo)
+	ContextKey string
+
+	// ExportedFields are the fields named by ExportFields; the whole output is exported
+	ExportedFields []string
+
 	// Flow control (which task executes next)
 	ThenTask string
 
 	// Explicit dependencies (optional, for cases where field references don't capture it)
 	// This is tracked automatically when using TaskFieldRef but can be set explicitly
 	Dependencies []string
+
+	// Compensations undo this task if a later task fails (set by Compensate)
+	Compensations []*Task
+
+	// Deadline is the maximum execution time for this task (set by WithDeadline)
+	Deadline string
+
+	// IdempotencyKey lets the engine dedupe this task on retry (set by WithIdempotencyKey)
+	IdempotencyKey string
 }
 
 // TaskConfig is a marker interface for task configurations.
@@ -77,8 +98,9 @@ type TaskConfig interface {
 //	workflow.FieldRef("title")  // ❌ Where does "title" come from? Unclear!
 //	fetchTask.Field("title")    // ✅ Clear origin - from fetchTask
 type TaskFieldRef struct {
-	taskName  string // Name of the task this field comes from
-	fieldName string // Name of the field in the task output
+	taskName   string // Name of the task this field comes from
+	fieldName  string // Name of the field in the task output
+	contextKey string // Context key the task exports under, if not its name (see ExportTo)
 }
 
 // Expression returns the JQ expression for this field reference.
@@ -86,6 +108,9 @@ type TaskFieldRef struct {
 func (r TaskFieldRef) Expression() string {
 	// Reference format: ${ $context.taskName.fieldName }
 	// This assumes the task has exported its output to context
+	if r.contextKey != "" {
+		return fmt.Sprintf("${ $context.%s.%s }", r.contextKey, r.fieldName)
+	}
 	return fmt.Sprintf("${ $context.%s.%s }", r.taskName, r.fieldName)
 }
 
@@ -141,8 +166,9 @@ func (t *Task) Field(fieldName string) TaskFieldRef {
 	}
 	
 	return TaskFieldRef{
-		taskName:  t.Name,
-		fieldName: fieldName,
+		taskName:   t.Name,
+		fieldName:  fieldName,
+		contextKey: t.ContextKey,
 	}
 }
 
@@ -155,6 +181,10 @@ func (t *Task) Field(fieldName string) TaskFieldRef {
 //  - Side effects matter (task A must run before task B, but B doesn't use A's output)
 //  - Ordering is important for reasons not captured by data flow
 //
+// Explicit and implicit dependencies are recorded the same way. During synthesis
+// tasks are reordered so each runs after its dependencies (see OrderTasks), and
+// dependency cycles are reported as errors.
+//
 // Example:
 //
 //	// Implicit dependency (preferred):
@@ -167,21 +197,35 @@ func (t *Task) Field(fieldName string) TaskFieldRef {
 //	cleanupTask.DependsOn(processTask)  // Cleanup must run after process
 func (t *Task) DependsOn(tasks ...*Task) *Task {
 	for _, task := range tasks {
-		// Check if already in dependencies
-		found := false
-		for _, dep := range t.Dependencies {
-			if dep == task.Name {
-				found = true
-				break
-			}
-		}
-		if !found {
-			t.Dependencies = append(t.Dependencies, task.Name)
-		}
+		t.dependsOnName(task.Name)
 	}
 	return t
 }
 
+// dependsOnName records a dependency on the named task, skipping duplicates.
+// Both DependsOn() and implicit TaskFieldRef dependencies go through here.
+func (t *Task) dependsOnName(name string) {
+	for _, dep := range t.Dependencies {
+		if dep == name {
+			return
+		}
+	}
+	t.Dependencies = append(t.Dependencies, name)
+}
+
+// addImplicitDependencies records the dependencies a task config discovered
+// through TaskFieldRef usage, in name order so synthesis is deterministic.
+func (t *Task) addImplicitDependencies(deps map[string]bool) {
+	names := make([]string, 0, len(deps))
+	for name := range deps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		t.dependsOnName(name)
+	}
+}
+
 // Export sets the export directive for this task using a low-level expression.
 // For most use cases, prefer ExportAll() or ExportField() for better UX.
 // Example: task.Export("${.}") exports entire output.
@@ -214,6 +258,25 @@ func (t *Task) ExportFields(fieldNames ...string) *Task {
 	// access specific fields. This is more efficient than creating separate exports.
 	// In the future, we could support selective field export if the proto supports it.
 	t.ExportAs = "${.}"
+	t.ExportedFields = fieldNames
+	return t
+}
+
+// ExportTo exports the entire task output under the given context key instead
+// of the task name, so context names stay meaningful when task names are long
+// or generated. Field() references read from the new key.
+//
+// Call ExportTo before Field(): references created earlier keep reading from
+// the task name. The key must be an identifier and must not clash with another
+// task's name or key.
+//
+// Example:
+//
+//	fetch := wf.HttpGet("fetch-user-profile-v2-7f3a", profileURL).ExportTo("userData")
+//	wf.SetVars("greet", "name", fetch.Field("name"))  // ${ $context.userData.name }
+func (t *Task) ExportTo(key string) *Task {
+	t.ContextKey = key
+	t.ExportAs = fmt.Sprintf("${ $context + { %s: . } }", key)
 	return t
 }
 
@@ -253,12 +316,12 @@ func (t *Task) End() *Task {
 type SetTaskConfig struct {
 	// Variables to set in workflow state.
 	// Keys are variable names, values can be literals or expressions.
-	Variables map[string]string
-	
+	Variables map[string]string `json:"variables,omitempty"`
+
 	// ImplicitDependencies tracks task dependencies discovered through TaskFieldRef usage.
 	// This is used during task creation to populate the task's Dependencies field.
 	// Map key is the task name, value is always true (set semantics).
-	ImplicitDependencies map[string]bool
+	ImplicitDependencies map[string]bool `json:"-"`
 }
 
 func (*SetTaskConfig) isTaskConfig() {}
@@ -299,9 +362,7 @@ func SetTask(name string, opts ...SetTaskOption) *Task {
 	}
 
 	// Propagate implicit dependencies to task
-	for taskName := range cfg.ImplicitDependencies {
-		task.Dependencies = append(task.Dependencies, taskName)
-	}
+	task.addImplicitDependencies(cfg.ImplicitDependencies)
 
 	return task
 }
@@ -326,7 +387,7 @@ func SetVar(key string, value interface{}) SetTaskOption {
 		cfg.Variables[key] = toExpression(value)
 		
 		// Track implicit dependency if this is a TaskFieldRef
-		if fieldRef, ok := value.(TaskFieldRef); ok {
+		if fieldRef, ok := value.(taskOutputRef); ok {
 			// Store dependency info in config for later tracking
 			if cfg.ImplicitDependencies == nil {
 				cfg.ImplicitDependencies = make(map[string]bool)
@@ -406,14 +467,38 @@ func SetFloat(key string, value interface{}) SetTaskOption {
 
 // HttpCallTaskConfig defines the configuration for HTTP_CALL tasks.
 type HttpCallTaskConfig struct {
-	Method         string            // HTTP method (GET, POST, PUT, DELETE, PATCH)
-	URI            string            // HTTP endpoint URI
-	Headers        map[string]string // HTTP headers
-	Body           map[string]any    // Request body (JSON)
-	TimeoutSeconds int32             // Request timeout in seconds
-	
+	Method         string            `json:"method,omitempty"`          // HTTP method (GET, POST, PUT, DELETE, PATCH)
+	URI            string            `json:"uri,omitempty"`             // HTTP endpoint URI
+	Headers        map[string]string `json:"headers,omitempty"`         // HTTP headers
+	QueryParams    map[string]string `json:"query_params,omitempty"`    // URL query parameters (escaped when the URI is built)
+	Body           map[string]any    `json:"body,omitempty"`            // Request body (JSON)
+	BodyStruct     any               `json:"-"`                         // Request body struct, converted at synthesis (set by WithBodyFromStruct)
+	BodyEncoding   BodyEncoding      `json:"body_encoding,omitempty"`   // Request body encoding (empty means JSON)
+	TimeoutSeconds int32             `json:"timeout_seconds,omitempty"` // Request timeout in seconds
+
+	// OAuth2 configures OAuth2 client credentials authentication (set by WithOAuth2)
+	OAuth2 *OAuth2Config `json:"oauth2,omitempty"`
+
+	// TLS configures certificate verification (set by WithInsecureSkipVerify, WithCACert)
+	TLS *TLSConfig `json:"tls,omitempty"`
+
+	// Proxy is the proxy URL to route the request through (set by WithProxy)
+	Proxy string `json:"proxy,omitempty"`
+
+	// FollowRedirects controls redirect handling; nil uses the engine default
+	FollowRedirects *bool `json:"follow_redirects,omitempty"`
+
+	// RetryPolicy retries failed requests (set by WithHttpRetry or workflow defaults)
+	RetryPolicy *RetryPolicy `json:"retry_policy,omitempty"`
+
+	// timeoutSet records an explicit WithTimeout so workflow defaults don't override it
+	timeoutSet bool
+
+	// timeoutErr records a WithTimeout value that could not be converted, reported by validation
+	timeoutErr error
+
 	// ImplicitDependencies tracks task dependencies discovered through TaskFieldRef usage.
-	ImplicitDependencies map[string]bool
+	ImplicitDependencies map[string]bool `json:"-"`
 }
 
 func (*HttpCallTaskConfig) isTaskConfig() {}
@@ -432,6 +517,7 @@ func (*HttpCallTaskConfig) isTaskConfig() {}
 func HttpCallTask(name string, opts ...HttpCallTaskOption) *Task {
 	cfg := &HttpCallTaskConfig{
 		Headers:              make(map[string]string),
+		QueryParams:          make(map[string]string),
 		Body:                 make(map[string]any),
 		TimeoutSeconds:       30, // default timeout
 		ImplicitDependencies: make(map[string]bool),
@@ -450,9 +536,7 @@ func HttpCallTask(name string, opts ...HttpCallTaskOption) *Task {
 	}
 
 	// Propagate implicit dependencies to task
-	for taskName := range cfg.ImplicitDependencies {
-		task.Dependencies = append(task.Dependencies, taskName)
-	}
+	task.addImplicitDependencies(cfg.ImplicitDependencies)
 
 	return task
 }
@@ -542,7 +626,7 @@ func WithURI(uri interface{}) HttpCallTaskOption {
 		cfg.URI = toExpression(uri)
 		
 		// Track implicit dependency if this is a TaskFieldRef
-		if fieldRef, ok := uri.(TaskFieldRef); ok {
+		if fieldRef, ok := uri.(taskOutputRef); ok {
 			if cfg.ImplicitDependencies == nil {
 				cfg.ImplicitDependencies = make(map[string]bool)
 			}
@@ -587,23 +671,156 @@ func WithHeaders(headers map[string]string) HttpCallTaskOption {
 	}
 }
 
-// WithBody sets the request body.
+// WithQueryParam adds a URL query parameter.
+// Accepts either a string or a Ref type for the value.
+//
+// Parameters are escaped and appended to the URI during synthesis, so values
+// containing runtime expressions are URL-encoded at runtime.
+//
+// Examples:
+//
+//	WithQueryParam("page", "1")                          // Static value
+//	WithQueryParam("q", ctx.SetString("query", "a b"))   // Context ref
+//	WithQueryParam("cursor", fetchTask.Field("next"))    // Implicit dependency on fetchTask!
+func WithQueryParam(key string, value interface{}) HttpCallTaskOption {
+	return func(cfg *HttpCallTaskConfig) {
+		if cfg.QueryParams == nil {
+			cfg.QueryParams = make(map[string]string)
+		}
+		cfg.QueryParams[key] = toExpression(value)
+
+		// Track implicit dependency if this is a TaskFieldRef
+		if fieldRef, ok := value.(taskOutputRef); ok {
+			if cfg.ImplicitDependencies == nil {
+				cfg.ImplicitDependencies = make(map[string]bool)
+			}
+			cfg.ImplicitDependencies[fieldRef.TaskName()] = true
+		}
+	}
+}
+
+// WithQueryParams adds multiple URL query parameters.
+func WithQueryParams(params map[string]string) HttpCallTaskOption {
+	return func(cfg *HttpCallTaskConfig) {
+		if cfg.QueryParams == nil {
+			cfg.QueryParams = make(map[string]string)
+		}
+		for k, v := range params {
+			cfg.QueryParams[k] = v
+		}
+	}
+}
+
+// RequestURI returns the URI with query parameters appended.
+//
+// Parameters are sorted by key for deterministic output. Static values are
+// escaped immediately; if the URI or any value is a runtime expression, a JQ
+// expression is returned that escapes values with @uri at runtime.
+//
+// Examples:
+//
+//	URI: "https://api.example.com/search", QueryParams: {"q": "a b"}
+//	→ "https://api.example.com/search?q=a+b"
+//
+//	URI: "https://api.example.com/search", QueryParams: {"q": "${ $context.query }"}
+//	→ "${ \"https://api.example.com/search?q=\" + (($context.query) | @uri) }"
+func (cfg *HttpCallTaskConfig) RequestURI() string {
+	if len(cfg.QueryParams) == 0 {
+		return cfg.URI
+	}
+
+	keys := make([]string, 0, len(cfg.QueryParams))
+	for k := range cfg.QueryParams {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	hasExpression := isExpression(cfg.URI)
+	for _, k := range keys {
+		if isExpression(cfg.QueryParams[k]) {
+			hasExpression = true
+			break
+		}
+	}
+
+	// Static URI and values - escape now
+	if !hasExpression {
+		values := url.Values{}
+		for _, k := range keys {
+			values.Set(k, cfg.QueryParams[k])
+		}
+		return cfg.URI + querySeparator(cfg.URI) + values.Encode()
+	}
+
+	// Build a JQ expression, escaping runtime values with @uri
+	var exprParts []string
+	if isExpression(cfg.URI) {
+		uriExpr := "(" + expressionBody(cfg.URI) + ")"
+		exprParts = append(exprParts,
+			uriExpr,
+			fmt.Sprintf(`(if (%s | contains("?")) then "&" else "?" end)`, uriExpr),
+		)
+	} else {
+		exprParts = append(exprParts, strconv.Quote(cfg.URI+querySeparator(cfg.URI)))
+	}
+
+	for i, k := range keys {
+		prefix := url.QueryEscape(k) + "="
+		if i > 0 {
+			prefix = "&" + prefix
+		}
+		v := cfg.QueryParams[k]
+		if isExpression(v) {
+			exprParts = append(exprParts, strconv.Quote(prefix), fmt.Sprintf("((%s) | @uri)", expressionBody(v)))
+		} else {
+			exprParts = append(exprParts, strconv.Quote(prefix+url.QueryEscape(v)))
+		}
+	}
+
+	return fmt.Sprintf("${ %s }", strings.Join(exprParts, " + "))
+}
+
+// querySeparator returns the character used to append a query string to uri.
+func querySeparator(uri string) string {
+	if strings.Contains(uri, "?") {
+		return "&"
+	}
+	return "?"
+}
+
+// isExpression reports whether s is a runtime expression ("${ ... }").
+func isExpression(s string) bool {
+	return strings.HasPrefix(s, "${") && strings.HasSuffix(s, "}")
+}
+
+// expressionBody strips the "${" and "}" delimiters from an expression.
+func expressionBody(s string) string {
+	return strings.TrimSpace(s[2 : len(s)-1])
+}
+
+// WithBody sets the request body (JSON).
+// For form-encoded or multipart bodies, use WithFormBody or WithMultipart.
 func WithBody(body map[string]any) HttpCallTaskOption {
 	return func(cfg *HttpCallTaskConfig) {
 		cfg.Body = body
+		cfg.BodyStruct = nil
+		cfg.BodyEncoding = BodyEncodingJSON
 	}
 }
 
-// WithTimeout sets the request timeout in seconds.
-// Accepts either an int or an IntRef from context.
+// WithTimeout sets the request timeout.
+// Accepts seconds (int or IntRef), a time.Duration or DurationRef, or a
+// duration string like "30s"; partial seconds are rounded up.
 //
 // Examples:
 //
-//	WithTimeout(30)                                // Legacy int
+//	WithTimeout(30)                                // Seconds
+//	WithTimeout(45 * time.Second)                  // time.Duration
 //	WithTimeout(ctx.SetInt("timeout", 30))         // Typed context
 func WithTimeout(seconds interface{}) HttpCallTaskOption {
 	return func(cfg *HttpCallTaskConfig) {
-		cfg.TimeoutSeconds = toInt32(seconds)
+		cfg.TimeoutSeconds, cfg.timeoutErr = toSeconds(seconds)
+		cfg.timeoutSet = true
 	}
 }
 
@@ -619,15 +836,48 @@ func Timeout(seconds interface{}) HttpCallTaskOption {
 	return WithTimeout(seconds)
 }
 
+// TimeoutRef sets the request timeout from an int reference holding seconds.
+// Unlike Timeout, it only accepts int references, so passing a StringRef or
+// BoolRef fails to compile.
+//
+// Example:
+//
+//	timeout := ctx.SetInt("timeout", 30)
+//	wf.HttpGet("fetch", endpoint, workflow.TimeoutRef(timeout))
+func TimeoutRef(seconds TypedRef[int]) HttpCallTaskOption {
+	return WithTimeout(seconds)
+}
+
 // ============================================================================
 // GRPC_CALL Task
 // ============================================================================
 
 // GrpcCallTaskConfig defines the configuration for GRPC_CALL tasks.
 type GrpcCallTaskConfig struct {
-	Service string         // gRPC service name
-	Method  string         // gRPC method name
-	Body    map[string]any // Request body (proto message as JSON)
+	Service string         `json:"service,omitempty"` // gRPC service name
+	Method  string         `json:"method,omitempty"`  // gRPC method name
+	Body    map[string]any `json:"body,omitempty"`    // Request body (proto message as JSON)
+
+	// BodyStruct is a request body struct, converted at synthesis (set by WithGrpcBodyFromStruct)
+	BodyStruct any `json:"-"`
+
+	// Endpoint is the server address in host:port form (set by WithGrpcEndpoint)
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Metadata is sent with the call as gRPC metadata (set by WithGrpcMetadata)
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// TLS configures transport security; nil uses the engine default (set by WithGrpcTLS)
+	TLS *TLSConfig `json:"tls,omitempty"`
+
+	// Deadline is the call deadline (e.g., "30s") (set by WithGrpcDeadline)
+	Deadline string `json:"deadline,omitempty"`
+
+	// RetryPolicy retries failed calls (set by WithGrpcRetry or workflow defaults)
+	RetryPolicy *RetryPolicy `json:"retry_policy,omitempty"`
+
+	// RequestDescriptor describes the request message, used to validate Body (set by GrpcCallFromMethod)
+	RequestDescriptor protoreflect.MessageDescriptor `json:"-"`
 }
 
 func (*GrpcCallTaskConfig) isTaskConfig() {}
@@ -637,13 +887,15 @@ func (*GrpcCallTaskConfig) isTaskConfig() {}
 // Example:
 //
 //	task := workflow.GrpcCallTask("callService",
+//	    workflow.WithGrpcEndpoint("users.internal:443"),
 //	    workflow.WithService("UserService"),
 //	    workflow.WithGrpcMethod("GetUser"),
 //	    workflow.WithGrpcBody(map[string]any{"userId": "${.userId}"}),
 //	)
 func GrpcCallTask(name string, opts ...GrpcCallTaskOption) *Task {
 	cfg := &GrpcCallTaskConfig{
-		Body: make(map[string]any),
+		Body:     make(map[string]any),
+		Metadata: make(map[string]string),
 	}
 
 	for _, opt := range opts {
@@ -690,6 +942,7 @@ func WithGrpcMethod(method interface{}) GrpcCallTaskOption {
 func WithGrpcBody(body map[string]any) GrpcCallTaskOption {
 	return func(cfg *GrpcCallTaskConfig) {
 		cfg.Body = body
+		cfg.BodyStruct = nil
 	}
 }
 
@@ -699,14 +952,14 @@ func WithGrpcBody(body map[string]any) GrpcCallTaskOption {
 
 // SwitchTaskConfig defines the configuration for SWITCH tasks.
 type SwitchTaskConfig struct {
-	Cases       []SwitchCase // Conditional cases
-	DefaultTask string       // Default task if no cases match
+	Cases       []SwitchCase `json:"cases,omitempty"`        // Conditional cases
+	DefaultTask string       `json:"default_task,omitempty"` // Default task if no cases match
 }
 
 // SwitchCase represents a conditional case in a SWITCH task.
 type SwitchCase struct {
-	Condition string // Condition expression
-	Then      string // Task to execute if condition is true
+	Condition string `json:"condition,omitempty"` // Condition expression
+	Then      string `json:"then,omitempty"`      // Task to execute if condition is true
 }
 
 func (*SwitchTaskConfig) isTaskConfig() {}
@@ -787,8 +1040,8 @@ func WithDefaultRef(task *Task) SwitchTaskOption {
 
 // ForTaskConfig defines the configuration for FOR tasks.
 type ForTaskConfig struct {
-	In string  // Collection expression to iterate over
-	Do []Task  // Tasks to execute for each item
+	In string `json:"in,omitempty"` // Collection expression to iterate over
+	Do []Task `json:"do,omitempty"` // Tasks to execute for each item
 }
 
 func (*ForTaskConfig) isTaskConfig() {}
@@ -850,13 +1103,14 @@ func WithDo(tasks ...*Task) ForTaskOption {
 
 // ForkTaskConfig defines the configuration for FORK tasks.
 type ForkTaskConfig struct {
-	Branches []ForkBranch // Parallel branches to execute
+	Branches []ForkBranch `json:"branches,omitempty"` // Parallel branches to execute
+	Compete  bool         `json:"compete,omitempty"`  // First branch to complete wins (set by WithCompete)
 }
 
 // ForkBranch represents a parallel branch in a FORK task.
 type ForkBranch struct {
-	Name  string // Branch name
-	Tasks []Task // Tasks to execute in this branch
+	Name  string `json:"name,omitempty"`  // Branch name
+	Tasks []Task `json:"tasks,omitempty"` // Tasks to execute in this branch
 }
 
 func (*ForkTaskConfig) isTaskConfig() {}
@@ -906,21 +1160,29 @@ func WithBranch(name string, tasks ...*Task) ForkTaskOption {
 	}
 }
 
+// WithCompete makes the branches race: the first branch to complete wins and
+// the others are cancelled. By default all branches must complete.
+func WithCompete() ForkTaskOption {
+	return func(cfg *ForkTaskConfig) {
+		cfg.Compete = true
+	}
+}
+
 // ============================================================================
 // TRY Task
 // ============================================================================
 
 // TryTaskConfig defines the configuration for TRY tasks.
 type TryTaskConfig struct {
-	Tasks []Task       // Tasks to try
-	Catch []CatchBlock // Error handlers
+	Tasks []Task       `json:"tasks,omitempty"` // Tasks to try
+	Catch []CatchBlock `json:"catch,omitempty"` // Error handlers
 }
 
 // CatchBlock represents an error handler in a TRY task.
 type CatchBlock struct {
-	Errors []string // Error types to catch
-	As     string   // Variable name to bind error to
-	Tasks  []Task   // Tasks to execute on error
+	Errors []string `json:"errors,omitempty"` // Error types to catch
+	As     string   `json:"as,omitempty"`     // Variable name to bind error to
+	Tasks  []Task   `json:"tasks,omitempty"`  // Tasks to execute on error
 }
 
 func (*TryTaskConfig) isTaskConfig() {}
@@ -987,7 +1249,14 @@ func WithCatch(errors []string, as string, tasks ...*Task) TryTaskOption {
 
 // ListenTaskConfig defines the configuration for LISTEN tasks.
 type ListenTaskConfig struct {
-	Event string // Event name to listen for
+	Event       string            `json:"event,omitempty"`        // Event name to listen for
+	Filter      string            `json:"filter,omitempty"`       // Condition the event must satisfy (set by WithEventFilter)
+	Correlation map[string]string `json:"correlation,omitempty"`  // Event attribute -> expected value (set by WithCorrelation)
+	Timeout     string            `json:"timeout,omitempty"`      // How long to wait for the event (set by WithListenTimeout)
+	TimeoutThen string            `json:"timeout_then,omitempty"` // Task to jump to when the timeout elapses
+
+	// ImplicitDependencies tracks task dependencies from TaskFieldRef usage
+	ImplicitDependencies map[string]bool `json:"-"`
 }
 
 func (*ListenTaskConfig) isTaskConfig() {}
@@ -1000,17 +1269,24 @@ func (*ListenTaskConfig) isTaskConfig() {}
 //	    workflow.WithEvent("approval.granted"),
 //	)
 func ListenTask(name string, opts ...ListenTaskOption) *Task {
-	cfg := &ListenTaskConfig{}
+	cfg := &ListenTaskConfig{
+		ImplicitDependencies: make(map[string]bool),
+	}
 
 	for _, opt := range opts {
 		opt(cfg)
 	}
 
-	return &Task{
+	task := &Task{
 		Name:   name,
 		Kind:   TaskKindListen,
 		Config: cfg,
 	}
+
+	// Propagate implicit dependencies to task
+	task.addImplicitDependencies(cfg.ImplicitDependencies)
+
+	return task
 }
 
 // ListenTaskOption is a functional option for configuring LISTEN tasks.
@@ -1035,7 +1311,11 @@ func WithEvent(event interface{}) ListenTaskOption {
 
 // WaitTaskConfig defines the configuration for WAIT tasks.
 type WaitTaskConfig struct {
-	Duration string // Duration to wait (e.g., "5s", "1m", "1h")
+	Duration string `json:"duration,omitempty"` // Duration to wait (e.g., "5s", "1m", "1h")
+	Until    string `json:"until,omitempty"`    // RFC 3339 timestamp or expression to wait until (set by WithUntil/WithUntilExpression)
+
+	// ImplicitDependencies tracks task dependencies from TaskFieldRef usage
+	ImplicitDependencies map[string]bool `json:"-"`
 }
 
 func (*WaitTaskConfig) isTaskConfig() {}
@@ -1048,24 +1328,31 @@ func (*WaitTaskConfig) isTaskConfig() {}
 //	    workflow.WithDuration("5s"),
 //	)
 func WaitTask(name string, opts ...WaitTaskOption) *Task {
-	cfg := &WaitTaskConfig{}
+	cfg := &WaitTaskConfig{
+		ImplicitDependencies: make(map[string]bool),
+	}
 
 	for _, opt := range opts {
 		opt(cfg)
 	}
 
-	return &Task{
+	task := &Task{
 		Name:   name,
 		Kind:   TaskKindWait,
 		Config: cfg,
 	}
+
+	// Propagate implicit dependencies to task
+	task.addImplicitDependencies(cfg.ImplicitDependencies)
+
+	return task
 }
 
 // WaitTaskOption is a functional option for configuring WAIT tasks.
 type WaitTaskOption func(*WaitTaskConfig)
 
 // WithDuration sets the wait duration.
-// Accepts string format, duration helpers, or Ref types.
+// Accepts seconds (int or IntRef), a time.Duration or DurationRef, duration helpers, or Ref types.
 //
 // String format examples: "5s", "1m", "1h", "1d"
 //
@@ -1074,10 +1361,50 @@ type WaitTaskOption func(*WaitTaskConfig)
 //	workflow.WithDuration(workflow.Seconds(5))              // Type-safe helper
 //	workflow.WithDuration(workflow.Minutes(30))             // Discoverable
 //	workflow.WithDuration("5s")                             // Legacy string
+//	workflow.WithDuration(90 * time.Second)                 // time.Duration
 //	workflow.WithDuration(ctx.SetString("wait", "10s"))     // Typed context
 func WithDuration(duration interface{}) WaitTaskOption {
 	return func(cfg *WaitTaskConfig) {
-		cfg.Duration = toExpression(duration)
+		cfg.Duration = toDuration(duration)
+	}
+}
+
+// WithUntil waits until an absolute point in time instead of a relative duration.
+// The time is recorded in UTC as an RFC 3339 timestamp.
+//
+// Example:
+//
+//	workflow.WaitTask("waitForLaunch",
+//	    workflow.WithUntil(time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)),
+//	)
+func WithUntil(t time.Time) WaitTaskOption {
+	return func(cfg *WaitTaskConfig) {
+		cfg.Until = t.UTC().Format(time.RFC3339)
+	}
+}
+
+// WithUntilExpression waits until a timestamp computed at runtime, such as a
+// value returned by a previous task. The expression must evaluate to an
+// RFC 3339 timestamp.
+// Accepts expressions, context Refs (such as a stigmer.TimeRef), or TaskFieldRefs.
+//
+// Example:
+//
+//	schedule := wf.HttpGet("getSchedule", ...)
+//	workflow.WaitTask("waitForSlot",
+//	    workflow.WithUntilExpression(schedule.Field("startsAt")),
+//	)
+func WithUntilExpression(ref interface{}) WaitTaskOption {
+	return func(cfg *WaitTaskConfig) {
+		cfg.Until = toExpression(ref)
+
+		// Track implicit dependency if this is a TaskFieldRef
+		if fieldRef, ok := ref.(taskOutputRef); ok {
+			if cfg.ImplicitDependencies == nil {
+				cfg.ImplicitDependencies = make(map[string]bool)
+			}
+			cfg.ImplicitDependencies[fieldRef.TaskName()] = true
+		}
 	}
 }
 
@@ -1162,8 +1489,13 @@ func Days(count int) string {
 
 // CallActivityTaskConfig defines the configuration for CALL_ACTIVITY tasks.
 type CallActivityTaskConfig struct {
-	Activity string         // Activity name
-	Input    map[string]any // Activity input
+	Activity               string         `json:"activity,omitempty"`                  // Activity name
+	Input                  map[string]any `json:"input,omitempty"`                     // Activity input
+	TaskQueue              string         `json:"task_queue,omitempty"`                // Temporal task queue (set by WithTaskQueue)
+	StartToCloseTimeout    string         `json:"start_to_close_timeout,omitempty"`    // Max duration of a single attempt
+	ScheduleToCloseTimeout string         `json:"schedule_to_close_timeout,omitempty"` // Max duration including retries
+	HeartbeatTimeout       string         `json:"heartbeat_timeout,omitempty"`         // Max time between heartbeats
+	RetryPolicy            *RetryPolicy   `json:"retry_policy,omitempty"`              // Retry policy (set by WithActivityRetry)
 }
 
 func (*CallActivityTaskConfig) isTaskConfig() {}
@@ -1221,9 +1553,9 @@ func WithActivityInput(input map[string]any) CallActivityTaskOption {
 
 // RaiseTaskConfig defines the configuration for RAISE tasks.
 type RaiseTaskConfig struct {
-	Error   string         // Error type/name
-	Message string         // Error message
-	Data    map[string]any // Additional error data
+	Error   string         `json:"error,omitempty"`   // Error type/name
+	Message string         `json:"message,omitempty"` // Error message
+	Data    map[string]any `json:"data,omitempty"`    // Additional error data
 }
 
 func (*RaiseTaskConfig) isTaskConfig() {}
@@ -1294,8 +1626,13 @@ func WithErrorData(data map[string]any) RaiseTaskOption {
 
 // RunTaskConfig defines the configuration for RUN tasks.
 type RunTaskConfig struct {
-	WorkflowName string         // Sub-workflow name
-	Input        map[string]any // Sub-workflow input
+	WorkflowName      string         `json:"workflow_name,omitempty"`      // Sub-workflow name
+	WorkflowNamespace string         `json:"workflow_namespace,omitempty"` // Sub-workflow namespace (set by WithWorkflowRef)
+	WorkflowVersion   string         `json:"workflow_version,omitempty"`   // Sub-workflow version (set by WithWorkflowRef)
+	Input             map[string]any `json:"input,omitempty"`              // Sub-workflow input
+
+	// refErr records a WithWorkflowRef call that could not be applied, reported by validation
+	refErr error
 }
 
 func (*RunTaskConfig) isTaskConfig() {}
@@ -1340,6 +1677,34 @@ func WithWorkflow(workflow interface{}) RunTaskOption {
 	}
 }
 
+// WithWorkflowRef sets the sub-workflow from an in-process Workflow.
+// This is type-safe and prevents typos in workflow names.
+//
+// The namespace, name and version are captured from the referenced workflow.
+// At synthesis time, the referenced workflow must be registered in the same
+// context, otherwise synthesis fails.
+//
+// Example:
+//
+//	processor, _ := workflow.New(ctx,
+//	    workflow.WithNamespace("data"),
+//	    workflow.WithName("data-processor"),
+//	)
+//	task := workflow.RunTask("process",
+//	    workflow.WithWorkflowRef(processor),
+//	)
+func WithWorkflowRef(wf *Workflow) RunTaskOption {
+	return func(cfg *RunTaskConfig) {
+		if wf == nil {
+			cfg.refErr = fmt.Errorf("WithWorkflowRef requires a workflow, got nil")
+			return
+		}
+		cfg.WorkflowName = wf.Document.Name
+		cfg.WorkflowNamespace = wf.Document.Namespace
+		cfg.WorkflowVersion = wf.Document.Version
+	}
+}
+
 // WithWorkflowInput sets the sub-workflow input.
 func WithWorkflowInput(input map[string]any) RunTaskOption {
 	return func(cfg *RunTaskConfig) {
@@ -1412,17 +1777,20 @@ func Interpolate(parts ...interface{}) string {
 		}
 	}
 	
-	// Single part - return as-is
+	// Single part - return as-is unless it is a malformed expression
 	if len(stringParts) == 1 {
-		return stringParts[0]
+		if _, ok := wellFormedBody(strings.TrimSpace(stringParts[0])); ok {
+			return stringParts[0]
+		}
+		return Normalize(stringParts[0])
 	}
 	
-	// Check if any part contains an expression (starts with ${)
+	// Check if any part is an expression (malformed expressions become quoted literals)
 	hasExpression := false
-	for _, part := range stringParts {
-		if strings.HasPrefix(part, "${") {
+	for i, part := range stringParts {
+		stringParts[i] = Normalize(part)
+		if _, ok := wellFormedBody(stringParts[i]); ok {
 			hasExpression = true
-			break
 		}
 	}
 	
@@ -1434,14 +1802,12 @@ func Interpolate(parts ...interface{}) string {
 	// Build expression with proper concatenation
 	exprParts := make([]string, 0, len(stringParts))
 	for _, part := range stringParts {
-		if strings.HasPrefix(part, "${") && strings.HasSuffix(part, "}") {
-			// Extract expression content (remove ${ and })
-			// Handle both formats: "${...}" and "${ ... }" (with spaces)
-			expr := strings.TrimSpace(part[2 : len(part)-1])
-			exprParts = append(exprParts, expr)
+		if body, ok := wellFormedBody(part); ok {
+			// Parenthesize pipes and operators so + binds correctly
+			exprParts = append(exprParts, operand(body))
 		} else {
-			// Quote static strings
-			exprParts = append(exprParts, fmt.Sprintf("\"%s\"", part))
+			// Quote static strings (escaping embedded quotes)
+			exprParts = append(exprParts, quoteLiteral(part))
 		}
 	}
 	
@@ -1658,7 +2024,7 @@ func Var(varName string) string {
 // Literal returns a literal value wrapped in quotes for use in conditions.
 // Example: Literal("200") returns "\"200\""
 func Literal(value string) string {
-	return fmt.Sprintf("\"%s\"", value)
+	return quoteLiteral(value)
 }
 
 // Number returns a numeric literal for use in conditions (no quotes).
@@ -1706,29 +2072,39 @@ func LessThanOrEqual(left, right string) string {
 // And combines multiple conditions with logical AND.
 // Example: And(Equals(Field("status"), Number(200)), Equals(Field("type"), Literal("success")))
 func And(conditions ...string) string {
-	// Remove ${ and } wrappers from conditions for proper nesting
-	unwrapped := make([]string, len(conditions))
-	for i, cond := range conditions {
-		unwrapped[i] = strings.TrimSpace(strings.TrimPrefix(strings.TrimSuffix(strings.TrimSpace(cond), "}"), "${"))
+	// Parse each condition so nesting keeps the right precedence
+	node := condAnd{}
+	for _, cond := range conditions {
+		term := parseCondition(cond)
+		// Flatten nested And() calls
+		if nested, ok := term.(condAnd); ok {
+			node.terms = append(node.terms, nested.terms...)
+			continue
+		}
+		node.terms = append(node.terms, term)
 	}
-	return fmt.Sprintf("${ %s }", strings.Join(unwrapped, " && "))
+	return fmt.Sprintf("${ %s }", node.render())
 }
 
 // Or combines multiple conditions with logical OR.
 // Example: Or(Equals(Field("status"), Number(200)), Equals(Field("status"), Number(201)))
 func Or(conditions ...string) string {
-	// Remove ${ and } wrappers from conditions for proper nesting
-	unwrapped := make([]string, len(conditions))
-	for i, cond := range conditions {
-		unwrapped[i] = strings.TrimSpace(strings.TrimPrefix(strings.TrimSuffix(strings.TrimSpace(cond), "}"), "${"))
+	// Parse each condition so nesting keeps the right precedence
+	node := condOr{}
+	for _, cond := range conditions {
+		term := parseCondition(cond)
+		// Flatten nested Or() calls
+		if nested, ok := term.(condOr); ok {
+			node.terms = append(node.terms, nested.terms...)
+			continue
+		}
+		node.terms = append(node.terms, term)
 	}
-	return fmt.Sprintf("${ %s }", strings.Join(unwrapped, " || "))
+	return fmt.Sprintf("${ %s }", node.render())
 }
 
 // Not negates a condition.
 // Example: Not(Equals(Field("status"), Number(200))) generates "${ !(.status == 200) }"
 func Not(condition string) string {
-	// Remove ${ and } wrapper from condition for proper nesting
-	unwrapped := strings.TrimSpace(strings.TrimPrefix(strings.TrimSuffix(strings.TrimSpace(condition), "}"), "${"))
-	return fmt.Sprintf("${ !(%s) }", unwrapped)
+	return fmt.Sprintf("${ %s }", condNot{inner: parseCondition(condition)}.render())
 }
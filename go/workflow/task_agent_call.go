@@ -1,5 +1,7 @@
 package workflow
 
+import "github.com/leftbin/stigmer-sdk/go/agent"
+
 // AgentCallTaskConfig represents configuration for calling an agent.
 //
 // This config maps to the AgentCallTaskConfig proto message and defines
@@ -16,18 +18,25 @@ package workflow
 //	}
 type AgentCallTaskConfig struct {
 	// Agent reference (slug or AgentRef)
-	Agent AgentRef
+	Agent AgentRef `json:"agent,omitempty"`
 
 	// Message/instructions to the agent
 	// Supports workflow variable interpolation (e.g., "${.input.data}")
-	Message string
+	Message string `json:"message,omitempty"`
 
 	// Environment variables (supports expressions)
 	// Example: {"GITHUB_TOKEN": "${.secrets.GITHUB_TOKEN}"}
-	Env map[string]string
+	Env map[string]string `json:"env,omitempty"`
+
+	// Structured input passed to the agent alongside the message
+	// Values may be literals, expressions or task field references
+	Input map[string]any `json:"input,omitempty"`
 
 	// Optional execution configuration
-	Config *AgentExecutionConfig
+	Config *AgentExecutionConfig `json:"config,omitempty"`
+
+	// ImplicitDependencies tracks task dependencies from TaskFieldRef usage
+	ImplicitDependencies map[string]bool `json:"-"`
 }
 
 // AgentExecutionConfig controls agent execution parameters.
@@ -36,16 +45,19 @@ type AgentCallTaskConfig struct {
 // configuration will be used.
 type AgentExecutionConfig struct {
 	// Model is the LLM model override (e.g., "claude-3-5-sonnet")
-	Model string
+	Model string `json:"model,omitempty"`
 
 	// Timeout in seconds (1-3600)
 	// Default is typically 300 seconds (5 minutes)
-	Timeout int32
+	Timeout int32 `json:"timeout,omitempty"`
 
 	// Temperature controls randomness (0.0-1.0)
 	// Lower = more deterministic, Higher = more creative
 	// Default is typically 0.7
-	Temperature float32
+	Temperature float32 `json:"temperature,omitempty"`
+
+	// timeoutErr records an AgentTimeout value that could not be converted, reported by validation
+	timeoutErr error
 }
 
 // Implement TaskConfig interface
@@ -72,7 +84,8 @@ func (AgentCallTaskConfig) isTaskConfig() {}
 //	)
 func AgentCallTask(name string, opts ...AgentCallOption) *Task {
 	config := &AgentCallTaskConfig{
-		Env: make(map[string]string),
+		Env:                  make(map[string]string),
+		ImplicitDependencies: make(map[string]bool),
 	}
 
 	// Apply options
@@ -80,11 +93,16 @@ func AgentCallTask(name string, opts ...AgentCallOption) *Task {
 		opt(config)
 	}
 
-	return &Task{
+	task := &Task{
 		Name:   name,
 		Kind:   TaskKindAgentCall,
 		Config: config,
 	}
+
+	// Propagate implicit dependencies to task
+	task.addImplicitDependencies(config.ImplicitDependencies)
+
+	return task
 }
 
 // ============================================================================
@@ -109,6 +127,65 @@ func AgentOption(agent AgentRef) AgentCallOption {
 	}
 }
 
+// WithAgentRef calls an agent defined in the same program.
+// This is shorthand for AgentOption(Agent(a)).
+//
+// Example:
+//
+//	reviewer, _ := agent.New(ctx, agent.WithName("code-reviewer"), ...)
+//	workflow.AgentCallTask("review",
+//	    workflow.WithAgentRef(reviewer),
+//	    workflow.WithPrompt("Review this pull request"),
+//	)
+func WithAgentRef(a *agent.Agent) AgentCallOption {
+	return func(c *AgentCallTaskConfig) {
+		c.Agent = Agent(a)
+	}
+}
+
+// WithPrompt sets the message sent to the agent.
+// Accepts strings, context Refs, or TaskFieldRefs; a TaskFieldRef makes the
+// agent call depend on the referenced task.
+//
+// Examples:
+//
+//	workflow.WithPrompt("Summarize the incident")                    // Static prompt
+//	workflow.WithPrompt(ctx.SetString("prompt", "..."))              // Typed context
+//	workflow.WithPrompt(fetchTicket.Field("description"))            // Previous task output
+func WithPrompt(prompt interface{}) AgentCallOption {
+	return func(c *AgentCallTaskConfig) {
+		c.Message = toExpression(prompt)
+
+		// Track implicit dependency if this is a TaskFieldRef
+		if fieldRef, ok := prompt.(taskOutputRef); ok {
+			if c.ImplicitDependencies == nil {
+				c.ImplicitDependencies = make(map[string]bool)
+			}
+			c.ImplicitDependencies[fieldRef.TaskName()] = true
+		}
+	}
+}
+
+// WithAgentInput passes structured input to the agent.
+// Values may be literals, expressions, context Refs or TaskFieldRefs; tasks
+// referenced through TaskFieldRefs become dependencies of the agent call.
+//
+// Example:
+//
+//	workflow.WithAgentInput(map[string]any{
+//	    "ticketId": fetchTicket.Field("id"),
+//	    "severity": "high",
+//	})
+func WithAgentInput(input map[string]any) AgentCallOption {
+	return func(c *AgentCallTaskConfig) {
+		c.Input = input
+		if c.ImplicitDependencies == nil {
+			c.ImplicitDependencies = make(map[string]bool)
+		}
+		trackStructDependencies(input, c.ImplicitDependencies)
+	}
+}
+
 // Message sets the agent instructions/message.
 //
 // The message supports workflow variable interpolation:
@@ -160,19 +237,21 @@ func AgentModel(model string) AgentCallOption {
 	}
 }
 
-// AgentTimeout sets the agent execution timeout in seconds.
+// AgentTimeout sets the agent execution timeout.
+// Accepts the same values as WithTimeout.
 //
 // Valid range: 1-3600 seconds (1 second to 1 hour)
 //
 // Example:
 //
-//	workflow.AgentTimeout(600) // 10 minutes
-func AgentTimeout(seconds int32) AgentCallOption {
+//	workflow.AgentTimeout(600)              // 10 minutes
+//	workflow.AgentTimeout(10 * time.Minute) // Same, as a time.Duration
+func AgentTimeout(seconds interface{}) AgentCallOption {
 	return func(c *AgentCallTaskConfig) {
 		if c.Config == nil {
 			c.Config = &AgentExecutionConfig{}
 		}
-		c.Config.Timeout = seconds
+		c.Config.Timeout, c.Config.timeoutErr = toSeconds(seconds)
 	}
 }
 
@@ -1,8 +1,10 @@
 package workflow_test
 
 import (
+	"errors"
 	"testing"
 
+	"github.com/leftbin/stigmer-sdk/go/agent"
 	"github.com/leftbin/stigmer-sdk/go/workflow"
 )
 
@@ -244,3 +246,97 @@ func TestWorkflow_CallAgent(t *testing.T) {
 type mockWorkflowContext struct{}
 
 func (m *mockWorkflowContext) RegisterWorkflow(wf *workflow.Workflow) {}
+
+func TestAgentCallTask_PromptAndInput(t *testing.T) {
+	reviewer := &agent.Agent{Name: "code-reviewer"}
+	fetchPR := workflow.HttpCallTask("fetchPR", workflow.WithURI("https://api.github.com/pulls/1"))
+	fetchDiff := workflow.HttpCallTask("fetchDiff", workflow.WithURI("https://api.github.com/pulls/1.diff"))
+
+	task := workflow.AgentCallTask("review",
+		workflow.WithAgentRef(reviewer),
+		workflow.WithPrompt(fetchPR.Field("body")),
+		workflow.WithAgentInput(map[string]any{
+			"diff":     fetchDiff.Field("content"),
+			"severity": "high",
+		}),
+	)
+	cfg := task.Config.(*workflow.AgentCallTaskConfig)
+
+	if cfg.Agent.Slug() != "code-reviewer" {
+		t.Errorf("Agent.Slug() = %q, want %q", cfg.Agent.Slug(), "code-reviewer")
+	}
+	if cfg.Message != "${ $context.fetchPR.body }" {
+		t.Errorf("Message = %q", cfg.Message)
+	}
+	if cfg.Input["severity"] != "high" {
+		t.Errorf("Input[severity] = %v, want %q", cfg.Input["severity"], "high")
+	}
+
+	deps := map[string]bool{}
+	for _, d := range task.Dependencies {
+		deps[d] = true
+	}
+	if len(deps) != 2 || !deps["fetchPR"] || !deps["fetchDiff"] {
+		t.Errorf("Dependencies = %v, want [fetchPR fetchDiff]", task.Dependencies)
+	}
+
+	// The agent's response is consumed like any other task output
+	if got := task.Field("summary").Expression(); got != "${ $context.review.summary }" {
+		t.Errorf("Field() = %q", got)
+	}
+}
+
+func TestAgentCallTask_Validation(t *testing.T) {
+	mockCtx := &mockWorkflowContext{}
+
+	tests := []struct {
+		name    string
+		task    *workflow.Task
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			task: workflow.AgentCallTask("review",
+				workflow.AgentOption(workflow.AgentBySlug("code-reviewer")),
+				workflow.WithPrompt("Review PR"),
+			),
+		},
+		{
+			name:    "missing agent",
+			task:    workflow.AgentCallTask("review", workflow.WithPrompt("Review PR")),
+			wantErr: true,
+		},
+		{
+			name: "missing prompt",
+			task: workflow.AgentCallTask("review",
+				workflow.AgentOption(workflow.AgentBySlug("code-reviewer")),
+			),
+			wantErr: true,
+		},
+		{
+			name: "temperature out of range",
+			task: workflow.AgentCallTask("review",
+				workflow.AgentOption(workflow.AgentBySlug("code-reviewer")),
+				workflow.WithPrompt("Review PR"),
+				workflow.AgentTemperature(1.5),
+			),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := workflow.New(mockCtx,
+				workflow.WithNamespace("test"),
+				workflow.WithName("test-workflow"),
+				workflow.WithTasks(tt.task),
+			)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("workflow.New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, workflow.ErrInvalidTaskConfig) {
+				t.Errorf("workflow.New() error = %v, want ErrInvalidTaskConfig", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package workflow
+
+// TaskKindEmit publishes an event.
+//
+// EMIT tasks have no dedicated engine task kind: they are lowered to
+// CALL_ACTIVITY tasks running the platform's event activity, with the event
+// name and payload as input.
+const TaskKindEmit TaskKind = "EMIT"
+
+// emitActivity is the platform activity that backs EMIT tasks.
+const emitActivity = "stigmer.events.Emit"
+
+func init() {
+	if err := RegisterTaskKind(TaskKindEmit, lowerEmitTask); err != nil {
+		panic(err)
+	}
+}
+
+// EmitTaskConfig defines the configuration for EMIT tasks.
+//
+// An EMIT task publishes an event that other workflows can wait for with a
+// LISTEN task or start on with WithEventTrigger.
+type EmitTaskConfig struct {
+	Event string         `json:"event,omitempty"` // Event name to publish
+	Data  map[string]any `json:"data,omitempty"`  // Event payload
+
+	// ImplicitDependencies tracks task dependencies from TaskFieldRef usage
+	ImplicitDependencies map[string]bool `json:"-"`
+}
+
+func (*EmitTaskConfig) isTaskConfig() {}
+
+// EmitTask creates a new EMIT task.
+//
+// Example:
+//
+//	task := workflow.EmitTask("orderCreated",
+//	    workflow.WithEventName("order.created"),
+//	    workflow.WithEventData(map[string]any{
+//	        "orderId": createOrder.Field("id"),
+//	    }),
+//	)
+func EmitTask(name string, opts ...EmitTaskOption) *Task {
+	cfg := &EmitTaskConfig{
+		Data:                 make(map[string]any),
+		ImplicitDependencies: make(map[string]bool),
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	task := &Task{
+		Name:   name,
+		Kind:   TaskKindEmit,
+		Config: cfg,
+	}
+
+	// Propagate implicit dependencies to task
+	task.addImplicitDependencies(cfg.ImplicitDependencies)
+
+	return task
+}
+
+// EmitTaskOption is a functional option for configuring EMIT tasks.
+type EmitTaskOption func(*EmitTaskConfig)
+
+// WithEventName sets the name of the event to publish.
+// Accepts either a string or a StringRef from context.
+//
+// Examples:
+//
+//	WithEventName("order.created")                        // Legacy string
+//	WithEventName(ctx.SetString("eventName", "..."))      // Typed context
+func WithEventName(event interface{}) EmitTaskOption {
+	return func(cfg *EmitTaskConfig) {
+		cfg.Event = toExpression(event)
+	}
+}
+
+// WithEventData sets the event payload.
+// Values may be literals, expressions, context Refs or TaskFieldRefs; tasks
+// referenced through TaskFieldRefs become dependencies of the EMIT task.
+//
+// Example:
+//
+//	WithEventData(map[string]any{
+//	    "orderId": createOrder.Field("id"),
+//	    "status":  "created",
+//	})
+func WithEventData(data map[string]any) EmitTaskOption {
+	return func(cfg *EmitTaskConfig) {
+		cfg.Data = data
+		if cfg.ImplicitDependencies == nil {
+			cfg.ImplicitDependencies = make(map[string]bool)
+		}
+		trackStructDependencies(data, cfg.ImplicitDependencies)
+	}
+}
+
+// lowerEmitTask converts an EMIT task to the CALL_ACTIVITY task the engine executes.
+func lowerEmitTask(task *Task) (*Task, error) {
+	cfg, ok := task.Config.(*EmitTaskConfig)
+	if !ok {
+		return nil, NewValidationErrorWithCause(
+			"config",
+			"",
+			"type",
+			"invalid config type for EMIT task",
+			ErrInvalidTaskConfig,
+		)
+	}
+	if cfg.Event == "" {
+		return nil, NewValidationErrorWithCause(
+			"config.event",
+			"",
+			"required",
+			"EMIT task must have an event name",
+			ErrInvalidTaskConfig,
+		)
+	}
+
+	input := map[string]any{"event": cfg.Event}
+	if len(cfg.Data) > 0 {
+		input["data"] = cfg.Data
+	}
+	return CallActivityTask(task.Name, WithActivity(emitActivity), WithActivityInput(input)), nil
+}
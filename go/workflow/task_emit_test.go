@@ -0,0 +1,66 @@
+package workflow
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestEmitTask verifies event name, payload and implicit dependencies.
+func TestEmitTask(t *testing.T) {
+	createOrder := HttpCallTask("createOrder", WithHTTPPost(), WithURI("https://api.example.com/orders"))
+
+	task := EmitTask("orderCreated",
+		WithEventName("order.created"),
+		WithEventData(map[string]any{
+			"orderId": createOrder.Field("id"),
+			"status":  "created",
+		}),
+	)
+	cfg := task.Config.(*EmitTaskConfig)
+
+	if task.Kind != TaskKindEmit {
+		t.Errorf("Kind = %q, want %q", task.Kind, TaskKindEmit)
+	}
+	if cfg.Event != "order.created" {
+		t.Errorf("Event = %q, want %q", cfg.Event, "order.created")
+	}
+	if cfg.Data["status"] != "created" {
+		t.Errorf("Data[status] = %v, want %q", cfg.Data["status"], "created")
+	}
+	if len(task.Dependencies) != 1 || task.Dependencies[0] != "createOrder" {
+		t.Errorf("Dependencies = %v, want [createOrder]", task.Dependencies)
+	}
+	if err := validateTaskKind(task.Kind); err != nil {
+		t.Errorf("validateTaskKind() error = %v", err)
+	}
+	if err := validateTaskConfig(task); err != nil {
+		t.Errorf("validateTaskConfig() error = %v", err)
+	}
+}
+
+// TestEmitTask_MissingEvent verifies an EMIT task without an event name is rejected.
+func TestEmitTask_MissingEvent(t *testing.T) {
+	task := EmitTask("emit", WithEventData(map[string]any{"a": 1}))
+	if err := validateTaskConfig(task); !errors.Is(err, ErrInvalidTaskConfig) {
+		t.Errorf("validateTaskConfig() error = %v, want ErrInvalidTaskConfig", err)
+	}
+}
+
+// TestEmitTask_Lowered verifies EMIT tasks lower to the platform event activity.
+func TestEmitTask_Lowered(t *testing.T) {
+	task := EmitTask("orderCreated",
+		WithEventName("order.created"),
+		WithEventData(map[string]any{"status": "created"}),
+	)
+
+	lowered, err := LowerTask(task)
+	if err != nil {
+		t.Fatalf("LowerTask() error = %v", err)
+	}
+	cfg := lowered.Config.(*CallActivityTaskConfig)
+	want := map[string]any{"event": "order.created", "data": map[string]any{"status": "created"}}
+	if lowered.Kind != TaskKindCallActivity || cfg.Activity != emitActivity || !reflect.DeepEqual(cfg.Input, want) {
+		t.Errorf("lowered = %s %s %v", lowered.Kind, cfg.Activity, cfg.Input)
+	}
+}
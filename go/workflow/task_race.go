@@ -0,0 +1,98 @@
+package workflow
+
+// Race winners reported by RaceWinner.
+const (
+	// RaceWinnerEvent means the event arrived before the timeout.
+	RaceWinnerEvent = "event"
+
+	// RaceWinnerTimeout means the timeout elapsed before the event arrived.
+	RaceWinnerTimeout = "timeout"
+)
+
+// raceConfig collects the options passed to RaceTask.
+type raceConfig struct {
+	event   string
+	timeout string
+}
+
+// RaceOption is a functional option for configuring RaceTask.
+type RaceOption func(*raceConfig)
+
+// WithListen sets the event the race waits for.
+// Accepts either a string or a StringRef from context.
+func WithListen(event interface{}) RaceOption {
+	return func(cfg *raceConfig) {
+		cfg.event = toExpression(event)
+	}
+}
+
+// WithRaceTimeout sets how long the race waits for the event.
+// Accepts seconds (int or IntRef), a time.Duration or DurationRef, duration helpers, or Ref types.
+func WithRaceTimeout(duration interface{}) RaceOption {
+	return func(cfg *raceConfig) {
+		cfg.timeout = toDuration(duration)
+	}
+}
+
+// RaceTask creates a competing FORK that waits for an event or a timeout,
+// whichever comes first.
+//
+// The event branch runs a LISTEN task and the timeout branch runs a WAIT task.
+// Each branch finishes with a SET task recording which branch won, and the
+// FORK exports the winning branch's output. Use RaceWon to branch on the
+// result, RaceWinner to pass the winner on, and RaceEvent to read the received event.
+//
+// Example:
+//
+//	race := workflow.RaceTask("awaitApproval",
+//	    workflow.WithListen("approval.granted"),
+//	    workflow.WithRaceTimeout(workflow.Minutes(30)),
+//	)
+//	route := workflow.SwitchTask("route",
+//	    workflow.WithCase(workflow.RaceWon(race, workflow.RaceWinnerEvent), "approve"),
+//	    workflow.WithDefault("escalate"),
+//	)
+func RaceTask(name string, opts ...RaceOption) *Task {
+	cfg := &raceConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	task := ForkTask(name,
+		WithBranch(RaceWinnerEvent,
+			ListenTask(name+"-event", WithEvent(cfg.event)),
+			SetTask(name+"-event-won",
+				SetVar("winner", RaceWinnerEvent),
+				SetVar("event", "${ . }"),
+			),
+		),
+		WithBranch(RaceWinnerTimeout,
+			WaitTask(name+"-timeout", WithDuration(cfg.timeout)),
+			SetTask(name+"-timeout-won", SetVar("winner", RaceWinnerTimeout)),
+		),
+		WithCompete(),
+	)
+	task.ExportAll()
+	return task
+}
+
+// RaceWinner returns a reference to the winning branch of a RaceTask:
+// RaceWinnerEvent or RaceWinnerTimeout.
+func RaceWinner(race *Task) TaskFieldRef {
+	return race.Field("winner")
+}
+
+// RaceEvent returns a reference to the event received by a RaceTask.
+// It is only set when RaceWinner is RaceWinnerEvent.
+func RaceEvent(race *Task) TaskFieldRef {
+	return race.Field("event")
+}
+
+// RaceWon returns a condition that is true when the given branch won a RaceTask.
+//
+// Example:
+//
+//	workflow.WithCase(workflow.RaceWon(race, workflow.RaceWinnerTimeout), "escalate")
+func RaceWon(race *Task, winner string) string {
+	return Equals(Var(race.Name+".winner"), Literal(winner))
+}
@@ -0,0 +1,41 @@
+package workflow
+
+import "testing"
+
+// TestRaceTask verifies the generated competing FORK and its winner references.
+func TestRaceTask(t *testing.T) {
+	race := RaceTask("awaitApproval",
+		WithListen("approval.granted"),
+		WithRaceTimeout(Minutes(30)),
+	)
+
+	if race.Kind != TaskKindFork {
+		t.Fatalf("Kind = %s, want FORK", race.Kind)
+	}
+	cfg := race.Config.(*ForkTaskConfig)
+	if !cfg.Compete {
+		t.Error("Compete = false, want true")
+	}
+	if len(cfg.Branches) != 2 {
+		t.Fatalf("Branches = %d, want 2", len(cfg.Branches))
+	}
+
+	listen := cfg.Branches[0].Tasks[0].Config.(*ListenTaskConfig)
+	if listen.Event != "approval.granted" {
+		t.Errorf("listen event = %q", listen.Event)
+	}
+	wait := cfg.Branches[1].Tasks[0].Config.(*WaitTaskConfig)
+	if wait.Duration != "30m" {
+		t.Errorf("wait duration = %q, want 30m", wait.Duration)
+	}
+	if err := validateTaskConfig(race); err != nil {
+		t.Errorf("validateTaskConfig() error = %v", err)
+	}
+
+	if got := RaceWinner(race).Expression(); got != "${ $context.awaitApproval.winner }" {
+		t.Errorf("RaceWinner() = %q", got)
+	}
+	if got := RaceWon(race, RaceWinnerTimeout); got != `${ $context.awaitApproval.winner == "timeout" }` {
+		t.Errorf("RaceWon() = %q", got)
+	}
+}
@@ -0,0 +1,97 @@
+package workflow
+
+import "fmt"
+
+// TaskKindScript runs an inline jq transformation over the workflow context.
+//
+// SCRIPT tasks have no dedicated engine task kind: they are lowered to a SET
+// task that evaluates the expression and stores it under scriptResultVar.
+const TaskKindScript TaskKind = "SCRIPT"
+
+// scriptResultVar is the SET variable a lowered SCRIPT task stores its result in.
+const scriptResultVar = "result"
+
+func init() {
+	if err := RegisterTaskKind(TaskKindScript, lowerScriptTask); err != nil {
+		panic(err)
+	}
+}
+
+// ScriptTaskConfig defines the configuration for SCRIPT tasks.
+type ScriptTaskConfig struct {
+	Expression string `json:"expression,omitempty"` // jq expression evaluated over the context
+}
+
+func (*ScriptTaskConfig) isTaskConfig() {}
+
+// ScriptTask creates a new SCRIPT task that reshapes data with a jq expression
+// and exports the result, so no external activity is needed between calls.
+//
+// The result is exported under the task name, so later tasks read it with
+// task.Field() like any other task output.
+//
+// Example:
+//
+//	fetchUser := wf.HttpGet("fetchUser", "https://api.example.com/users/1")
+//	toPayload := workflow.ScriptTask("toPayload",
+//	    workflow.WithExpression(`$context.fetchUser | {id, email, fullName: (.first + " " + .last)}`),
+//	)
+//	wf.HttpPost("createContact", "https://crm.example.com/contacts",
+//	    workflow.WithBody(map[string]any{"email": toPayload.Field("email")}),
+//	)
+func ScriptTask(name string, opts ...ScriptTaskOption) *Task {
+	cfg := &ScriptTaskConfig{}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return &Task{
+		Name:     name,
+		Kind:     TaskKindScript,
+		Config:   cfg,
+		ExportAs: fmt.Sprintf("${ .%s }", scriptResultVar),
+	}
+}
+
+// ScriptTaskOption is a functional option for configuring SCRIPT tasks.
+type ScriptTaskOption func(*ScriptTaskConfig)
+
+// WithExpression sets the jq expression of a SCRIPT task.
+// Accepts a bare jq expression or one wrapped in "${ }".
+//
+// Example:
+//
+//	workflow.WithExpression(`$context.fetchOrders.items | map(select(.status == "open"))`)
+func WithExpression(expr string) ScriptTaskOption {
+	return func(cfg *ScriptTaskConfig) {
+		if expr != "" && !isExpression(expr) {
+			expr = "${ " + expr + " }"
+		}
+		cfg.Expression = expr
+	}
+}
+
+// lowerScriptTask converts a SCRIPT task to the SET task the engine executes.
+func lowerScriptTask(task *Task) (*Task, error) {
+	cfg, ok := task.Config.(*ScriptTaskConfig)
+	if !ok {
+		return nil, NewValidationErrorWithCause(
+			"config",
+			"",
+			"type",
+			"invalid config type for SCRIPT task",
+			ErrInvalidTaskConfig,
+		)
+	}
+	if cfg.Expression == "" {
+		return nil, NewValidationErrorWithCause(
+			"config.expression",
+			"",
+			"required",
+			"SCRIPT task must have an expression",
+			ErrInvalidTaskConfig,
+		)
+	}
+	return SetTask(task.Name, SetVar(scriptResultVar, cfg.Expression)), nil
+}
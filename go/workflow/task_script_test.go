@@ -0,0 +1,62 @@
+package workflow
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestScriptTask verifies SCRIPT tasks wrap the expression and lower to a SET task.
+func TestScriptTask(t *testing.T) {
+	task := ScriptTask("toPayload",
+		WithExpression(`$context.fetchUser | {id, email}`),
+	)
+	cfg := task.Config.(*ScriptTaskConfig)
+
+	if cfg.Expression != "${ $context.fetchUser | {id, email} }" {
+		t.Errorf("Expression = %q", cfg.Expression)
+	}
+	if task.ExportAs != "${ .result }" {
+		t.Errorf("ExportAs = %q, want %q", task.ExportAs, "${ .result }")
+	}
+	if err := validateTaskConfig(task); err != nil {
+		t.Errorf("validateTaskConfig() error = %v", err)
+	}
+
+	lowered, err := LowerTask(task)
+	if err != nil {
+		t.Fatalf("LowerTask() error = %v", err)
+	}
+	if lowered.Kind != TaskKindSet {
+		t.Errorf("lowered Kind = %q, want %q", lowered.Kind, TaskKindSet)
+	}
+	if got := lowered.Config.(*SetTaskConfig).Variables["result"]; got != cfg.Expression {
+		t.Errorf("lowered result = %q, want %q", got, cfg.Expression)
+	}
+	if lowered.ExportAs != task.ExportAs {
+		t.Errorf("lowered ExportAs = %q, want %q", lowered.ExportAs, task.ExportAs)
+	}
+
+	// Field references read the exported result under the task name
+	if got := task.Field("email").Expression(); got != "${ $context.toPayload.email }" {
+		t.Errorf("Field() = %q", got)
+	}
+	if task.ExportAs != "${ .result }" {
+		t.Errorf("Field() overrode ExportAs: %q", task.ExportAs)
+	}
+}
+
+// TestScriptTask_Wrapped verifies already-wrapped expressions are kept as-is.
+func TestScriptTask_Wrapped(t *testing.T) {
+	task := ScriptTask("count", WithExpression("${ $context.items | length }"))
+	if got := task.Config.(*ScriptTaskConfig).Expression; got != "${ $context.items | length }" {
+		t.Errorf("Expression = %q", got)
+	}
+}
+
+// TestScriptTask_MissingExpression verifies a SCRIPT task without an expression is rejected.
+func TestScriptTask_MissingExpression(t *testing.T) {
+	task := ScriptTask("empty")
+	if err := validateTaskConfig(task); !errors.Is(err, ErrInvalidTaskConfig) {
+		t.Errorf("validateTaskConfig() error = %v, want ErrInvalidTaskConfig", err)
+	}
+}
@@ -0,0 +1,243 @@
+package workflow
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+)
+
+// templateParamRegex matches {{param}} placeholders in task templates.
+var templateParamRegex = regexp.MustCompile(`\{\{([A-Za-z_][A-Za-z0-9_]*)\}\}`)
+
+// taskPkgPath limits deep copies to structs defined in this package.
+var taskPkgPath = reflect.TypeOf(Task{}).PkgPath()
+
+// TemplateParams holds the values substituted into a TaskTemplate.
+// Values can be strings, numbers, booleans, TaskFieldRefs or context Refs.
+type TemplateParams map[string]interface{}
+
+// TaskTemplate is a reusable, parameterized task definition.
+//
+// A template is an ordinary task whose strings contain {{param}} placeholders
+// (see Param). Placeholders may appear in the task name, URIs, headers, body
+// values and any other string field. Instantiate returns a copy of the task
+// with every placeholder replaced.
+//
+// Example:
+//
+//	notifySlack := workflow.NewTaskTemplate(workflow.HttpCallTask("notify-{{channel}}",
+//	    workflow.WithHTTPPost(),
+//	    workflow.WithURI("https://hooks.slack.com/services/{{webhook}}"),
+//	    workflow.WithBody(map[string]any{"text": workflow.Param("message")}),
+//	))
+//
+//	task, err := notifySlack.Instantiate(workflow.TemplateParams{
+//	    "channel": "deploys",
+//	    "webhook": "T000/B000/XXXX",
+//	    "message": deploy.Field("summary"),
+//	})
+type TaskTemplate struct {
+	task   *Task
+	params []string
+}
+
+// Param returns the placeholder for a template parameter, for use where
+// building a string by hand would be awkward.
+//
+// Example:
+//
+//	workflow.WithBody(map[string]any{"amount": workflow.Param("amount")})
+func Param(name string) string {
+	return "{{" + name + "}}"
+}
+
+// NewTaskTemplate creates a template from a task containing {{param}} placeholders.
+// The task itself is never added to a workflow; use Instantiate to create copies.
+func NewTaskTemplate(task *Task) *TaskTemplate {
+	seen := make(map[string]bool)
+	collect := func(s string) string {
+		for _, m := range templateParamRegex.FindAllStringSubmatch(s, -1) {
+			seen[m[1]] = true
+		}
+		return s
+	}
+	copyTask(task, &taskCopier{replace: collect})
+
+	params := make([]string, 0, len(seen))
+	for name := range seen {
+		params = append(params, name)
+	}
+	sort.Strings(params)
+	return &TaskTemplate{task: task, params: params}
+}
+
+// Params returns the names of the template's parameters, sorted.
+func (t *TaskTemplate) Params() []string {
+	return append([]string(nil), t.params...)
+}
+
+// Instantiate creates a new task from the template with every placeholder
+// replaced by its value in params.
+//
+// All template parameters must be provided, and params must not contain names
+// the template does not use. Task field references in params become
+// dependencies of the new task.
+func (t *TaskTemplate) Instantiate(params TemplateParams) (*Task, error) {
+	declared := make(map[string]bool, len(t.params))
+	for _, name := range t.params {
+		declared[name] = true
+		if _, ok := params[name]; !ok {
+			return nil, NewValidationErrorWithCause(
+				"params",
+				name,
+				"required",
+				fmt.Sprintf("missing value for template parameter %q", name),
+				ErrInvalidTemplate,
+			)
+		}
+	}
+	for name := range params {
+		if !declared[name] {
+			return nil, NewValidationErrorWithCause(
+				"params",
+				name,
+				"unknown",
+				fmt.Sprintf("template has no parameter %q", name),
+				ErrInvalidTemplate,
+			)
+		}
+	}
+
+	task := copyTask(t.task, &taskCopier{
+		replace: func(s string) string { return substituteParams(s, params) },
+		params:  params,
+	})
+
+	for _, value := range params {
+		if ref, ok := value.(taskOutputRef); ok {
+			task.dependsOnName(ref.TaskName())
+		}
+	}
+	return task, nil
+}
+
+// substituteParams replaces placeholders in s. If any value is a runtime
+// expression, the result is a JQ concatenation built with Interpolate.
+func substituteParams(s string, params TemplateParams) string {
+	matches := templateParamRegex.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s
+	}
+
+	var parts []interface{}
+	last := 0
+	for _, m := range matches {
+		if m[0] > last {
+			parts = append(parts, s[last:m[0]])
+		}
+		parts = append(parts, toExpression(params[s[m[2]:m[3]]]))
+		last = m[1]
+	}
+	if last < len(s) {
+		parts = append(parts, s[last:])
+	}
+	return Interpolate(parts...)
+}
+
+// copyTask deep-copies a task, including nested tasks, using c.
+func copyTask(task *Task, c *taskCopier) *Task {
+	return c.copy(reflect.ValueOf(task)).Interface().(*Task)
+}
+
+// taskCopier deep-copies tasks and their configs with reflection.
+type taskCopier struct {
+	// replace is applied to every string
+	replace func(string) string
+
+	// params, when set, replaces interface values that are exactly one
+	// placeholder (such as body values) with the raw parameter value, so
+	// numbers and references keep their type
+	params TemplateParams
+
+	// fieldRef, when set, rewrites TaskFieldRef values held in interfaces
+	fieldRef func(TaskFieldRef) TaskFieldRef
+}
+
+func (c *taskCopier) copy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.String:
+		out := reflect.New(v.Type()).Elem()
+		out.SetString(c.replace(v.String()))
+		return out
+
+	case reflect.Ptr:
+		// Only descend into this package's structs (tasks and task configs);
+		// other pointers, such as proto descriptors, are shared.
+		if v.IsNil() || v.Elem().Kind() != reflect.Struct || v.Type().Elem().PkgPath() != taskPkgPath {
+			return v
+		}
+		out := reflect.New(v.Type().Elem())
+		out.Elem().Set(c.copy(v.Elem()))
+		return out
+
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v) // Copies unexported fields as-is
+		for i := 0; i < v.NumField(); i++ {
+			if out.Field(i).CanSet() {
+				out.Field(i).Set(c.copy(v.Field(i)))
+			}
+		}
+		return out
+
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(c.copy(iter.Key()), c.copy(iter.Value()))
+		}
+		return out
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(c.copy(v.Index(i)))
+		}
+		return out
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		elem := v.Elem()
+		if ref, ok := elem.Interface().(TaskFieldRef); ok && c.fieldRef != nil {
+			out := reflect.New(v.Type()).Elem()
+			out.Set(reflect.ValueOf(c.fieldRef(ref)))
+			return out
+		}
+		if elem.Kind() == reflect.String && c.params != nil {
+			if m := templateParamRegex.FindStringSubmatch(elem.String()); m != nil && m[0] == elem.String() {
+				if value := c.params[m[1]]; value != nil {
+					out := reflect.New(v.Type()).Elem()
+					out.Set(reflect.ValueOf(value))
+					return out
+				}
+			}
+		}
+		switch elem.Kind() {
+		case reflect.String, reflect.Map, reflect.Slice, reflect.Ptr:
+			out := reflect.New(v.Type()).Elem()
+			out.Set(c.copy(elem))
+			return out
+		}
+		return v
+	}
+	return v
+}
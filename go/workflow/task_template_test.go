@@ -0,0 +1,104 @@
+package workflow
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func newNotifyTemplate() *TaskTemplate {
+	return NewTaskTemplate(HttpCallTask("notify-{{channel}}",
+		WithHTTPPost(),
+		WithURI("https://hooks.example.com/{{channel}}"),
+		Header("X-Team", Param("team")),
+		WithBody(map[string]any{
+			"text":     Param("message"),
+			"priority": Param("priority"),
+		}),
+	))
+}
+
+// TestTaskTemplate_Instantiate verifies placeholders are replaced in names, URIs, headers and body values.
+func TestTaskTemplate_Instantiate(t *testing.T) {
+	tmpl := newNotifyTemplate()
+	if got, want := tmpl.Params(), []string{"channel", "message", "priority", "team"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Params() = %v, want %v", got, want)
+	}
+
+	deploy := HttpCallTask("deploy", WithURI("https://deploy.example.com"))
+	task, err := tmpl.Instantiate(TemplateParams{
+		"channel":  "deploys",
+		"team":     "platform",
+		"message":  deploy.Field("summary"),
+		"priority": 2,
+	})
+	if err != nil {
+		t.Fatalf("Instantiate() error = %v", err)
+	}
+
+	if task.Name != "notify-deploys" {
+		t.Errorf("Name = %q, want notify-deploys", task.Name)
+	}
+	cfg := task.Config.(*HttpCallTaskConfig)
+	if cfg.URI != "https://hooks.example.com/deploys" {
+		t.Errorf("URI = %q", cfg.URI)
+	}
+	if cfg.Headers["X-Team"] != "platform" {
+		t.Errorf("X-Team header = %q, want platform", cfg.Headers["X-Team"])
+	}
+	if cfg.Body["priority"] != 2 {
+		t.Errorf("body priority = %#v, want 2", cfg.Body["priority"])
+	}
+	if ref, ok := cfg.Body["text"].(TaskFieldRef); !ok || ref.TaskName() != "deploy" {
+		t.Errorf("body text = %#v, want deploy.summary field reference", cfg.Body["text"])
+	}
+	if !reflect.DeepEqual(task.Dependencies, []string{"deploy"}) {
+		t.Errorf("Dependencies = %v, want [deploy]", task.Dependencies)
+	}
+}
+
+// TestTaskTemplate_InstancesAreIndependent verifies instances do not share state with each other or the template.
+func TestTaskTemplate_InstancesAreIndependent(t *testing.T) {
+	tmpl := newNotifyTemplate()
+	params := TemplateParams{"channel": "a", "team": "t", "message": "m", "priority": 1}
+
+	first, _ := tmpl.Instantiate(params)
+	params["channel"] = "b"
+	second, _ := tmpl.Instantiate(params)
+
+	first.Config.(*HttpCallTaskConfig).Headers["X-Extra"] = "1"
+	if _, ok := second.Config.(*HttpCallTaskConfig).Headers["X-Extra"]; ok {
+		t.Error("instances share the headers map")
+	}
+	if second.Name != "notify-b" {
+		t.Errorf("second.Name = %q, want notify-b", second.Name)
+	}
+	if got := tmpl.task.Config.(*HttpCallTaskConfig).URI; got != "https://hooks.example.com/{{channel}}" {
+		t.Errorf("template URI changed to %q", got)
+	}
+}
+
+// TestTaskTemplate_RuntimeValueInString verifies expressions embedded in a string become JQ concatenations.
+func TestTaskTemplate_RuntimeValueInString(t *testing.T) {
+	tmpl := NewTaskTemplate(HttpCallTask("audit", WithHTTPGet(), WithURI("https://audit.example.com/{{id}}/log")))
+	task, err := tmpl.Instantiate(TemplateParams{"id": "${ $context.orderId }"})
+	if err != nil {
+		t.Fatalf("Instantiate() error = %v", err)
+	}
+	want := `${ "https://audit.example.com/" + $context.orderId + "/log" }`
+	if got := task.Config.(*HttpCallTaskConfig).URI; got != want {
+		t.Errorf("URI = %q, want %q", got, want)
+	}
+}
+
+// TestTaskTemplate_InvalidParams verifies missing and unknown parameters are rejected.
+func TestTaskTemplate_InvalidParams(t *testing.T) {
+	tmpl := NewTaskTemplate(SetTask("init", SetVar("region", "{{region}}")))
+
+	if _, err := tmpl.Instantiate(TemplateParams{}); !errors.Is(err, ErrInvalidTemplate) {
+		t.Errorf("missing param: error = %v, want ErrInvalidTemplate", err)
+	}
+	if _, err := tmpl.Instantiate(TemplateParams{"region": "eu", "zone": "a"}); !errors.Is(err, ErrInvalidTemplate) {
+		t.Errorf("unknown param: error = %v, want ErrInvalidTemplate", err)
+	}
+}
@@ -1,7 +1,10 @@
 package workflow
 
 import (
+	"errors"
+	"strings"
 	"testing"
+	"time"
 )
 
 // TestField_AutoExport verifies that calling Field() automatically exports the task.
@@ -151,3 +154,208 @@ func TestExportField_NotOverriddenByField(t *testing.T) {
 		t.Errorf("Expected custom export %s to be preserved, got: %s", customExport, task.ExportAs)
 	}
 }
+
+// TestExportTo verifies the output is exported under the custom key and Field() reads from it.
+func TestExportTo(t *testing.T) {
+	task := &Task{
+		Name: "fetch-user-profile-v2-7f3a",
+		Kind: TaskKindHttpCall,
+	}
+
+	ref := task.ExportTo("userData").Field("name")
+
+	if task.ExportAs != "${ $context + { userData: . } }" {
+		t.Errorf("Expected export under userData, got: %s", task.ExportAs)
+	}
+	if ref.Expression() != "${ $context.userData.name }" {
+		t.Errorf("Expected expression to read from userData, got: %s", ref.Expression())
+	}
+	if ref.TaskName() != "fetch-user-profile-v2-7f3a" {
+		t.Errorf("Expected task name to be kept for dependencies, got: %s", ref.TaskName())
+	}
+}
+
+// TestExportTo_Validation verifies export keys must be identifiers and unique.
+func TestExportTo_Validation(t *testing.T) {
+	tests := []struct {
+		name  string
+		tasks []*Task
+	}{
+		{"not an identifier", []*Task{SetTask("fetch", SetVar("a", "1")).ExportTo("user-data")}},
+		{"duplicate key", []*Task{
+			SetTask("first", SetVar("a", "1")).ExportTo("userData"),
+			SetTask("second", SetVar("a", "1")).ExportTo("userData"),
+		}},
+		{"clashes with task name", []*Task{
+			SetTask("userData", SetVar("a", "1")),
+			SetTask("second", SetVar("a", "1")).ExportTo("userData"),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &Workflow{
+				Document: Document{DSL: "1.0.0", Namespace: "test", Name: "export", Version: "1.0.0"},
+				Tasks:    tt.tasks,
+			}
+			if err := validate(w); !errors.Is(err, ErrInvalidTaskConfig) {
+				t.Errorf("validate() error = %v, want ErrInvalidTaskConfig", err)
+			}
+		})
+	}
+}
+
+// TestWithWorkflowRef verifies that RUN tasks capture the referenced workflow's identity.
+func TestWithWorkflowRef(t *testing.T) {
+	sub := &Workflow{
+		Document: Document{
+			Namespace: "data",
+			Name:      "data-processor",
+			Version:   "1.2.0",
+		},
+	}
+
+	task := RunTask("process", WithWorkflowRef(sub))
+
+	cfg, ok := task.Config.(*RunTaskConfig)
+	if !ok {
+		t.Fatalf("Expected *RunTaskConfig, got %T", task.Config)
+	}
+	if cfg.WorkflowName != "data-processor" {
+		t.Errorf("WorkflowName = %q, want %q", cfg.WorkflowName, "data-processor")
+	}
+	if cfg.WorkflowNamespace != "data" {
+		t.Errorf("WorkflowNamespace = %q, want %q", cfg.WorkflowNamespace, "data")
+	}
+	if cfg.WorkflowVersion != "1.2.0" {
+		t.Errorf("WorkflowVersion = %q, want %q", cfg.WorkflowVersion, "1.2.0")
+	}
+}
+
+// TestWithWorkflowRef_Nil verifies a nil workflow reference is reported by validation instead of panicking.
+func TestWithWorkflowRef_Nil(t *testing.T) {
+	task := RunTask("process", WithWorkflowRef(nil))
+
+	err := validateTaskConfig(task)
+	if !errors.Is(err, ErrInvalidTaskConfig) {
+		t.Fatalf("validateTaskConfig() error = %v, want ErrInvalidTaskConfig", err)
+	}
+	if !strings.Contains(err.Error(), "WithWorkflowRef") {
+		t.Errorf("validateTaskConfig() error = %q, want it to mention WithWorkflowRef", err)
+	}
+}
+
+// TestRequestURI_QueryParams verifies query parameters are escaped and appended to the URI.
+func TestRequestURI_QueryParams(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []HttpCallTaskOption
+		expected string
+	}{
+		{
+			name:     "no query params",
+			opts:     []HttpCallTaskOption{WithURI("https://api.example.com/search")},
+			expected: "https://api.example.com/search",
+		},
+		{
+			name: "static values are escaped",
+			opts: []HttpCallTaskOption{
+				WithURI("https://api.example.com/search"),
+				WithQueryParam("q", "a b&c"),
+				WithQueryParam("page", 2),
+			},
+			expected: "https://api.example.com/search?page=2&q=a+b%26c",
+		},
+		{
+			name: "URI with existing query",
+			opts: []HttpCallTaskOption{
+				WithURI("https://api.example.com/search?lang=en"),
+				WithQueryParams(map[string]string{"q": "go"}),
+			},
+			expected: "https://api.example.com/search?lang=en&q=go",
+		},
+		{
+			name: "runtime value is escaped with @uri",
+			opts: []HttpCallTaskOption{
+				WithURI("https://api.example.com/search"),
+				WithQueryParam("limit", "10"),
+				WithQueryParam("q", "${ $context.query }"),
+			},
+			expected: `${ "https://api.example.com/search?" + "limit=10" + "&q=" + (($context.query) | @uri) }`,
+		},
+		{
+			name: "runtime URI",
+			opts: []HttpCallTaskOption{
+				WithURI("${ $context.apiURL }"),
+				WithQueryParam("q", "go"),
+			},
+			expected: `${ ($context.apiURL) + (if (($context.apiURL) | contains("?")) then "&" else "?" end) + "q=go" }`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := HttpCallTask("search", tt.opts...)
+			cfg := task.Config.(*HttpCallTaskConfig)
+			if got := cfg.RequestURI(); got != tt.expected {
+				t.Errorf("RequestURI() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestWithQueryParam_TaskFieldRef verifies query params from task fields add dependencies.
+func TestWithQueryParam_TaskFieldRef(t *testing.T) {
+	fetch := HttpCallTask("fetch", WithURI("https://api.example.com/items"))
+	next := HttpCallTask("next",
+		WithURI("https://api.example.com/items"),
+		WithQueryParam("cursor", fetch.Field("cursor")),
+	)
+
+	if len(next.Dependencies) != 1 || next.Dependencies[0] != "fetch" {
+		t.Errorf("Dependencies = %v, want [fetch]", next.Dependencies)
+	}
+}
+
+// TestWaitTask_Until verifies absolute and computed WAIT deadlines.
+func TestWaitTask_Until(t *testing.T) {
+	launch := time.Date(2026, 3, 1, 9, 0, 0, 0, time.FixedZone("CET", 3600))
+	task := WaitTask("waitForLaunch", WithUntil(launch))
+	if got := task.Config.(*WaitTaskConfig).Until; got != "2026-03-01T08:00:00Z" {
+		t.Errorf("Until = %q, want UTC RFC 3339 timestamp", got)
+	}
+	if err := validateTaskConfig(task); err != nil {
+		t.Errorf("validateTaskConfig() error = %v", err)
+	}
+
+	schedule := HttpCallTask("getSchedule", WithURI("https://api.example.com/schedule"))
+	task = WaitTask("waitForSlot", WithUntilExpression(schedule.Field("startsAt")))
+	if got := task.Config.(*WaitTaskConfig).Until; got != "${ $context.getSchedule.startsAt }" {
+		t.Errorf("Until = %q", got)
+	}
+	if len(task.Dependencies) != 1 || task.Dependencies[0] != "getSchedule" {
+		t.Errorf("Dependencies = %v, want [getSchedule]", task.Dependencies)
+	}
+	if err := validateTaskConfig(task); err != nil {
+		t.Errorf("validateTaskConfig() error = %v", err)
+	}
+}
+
+// TestWaitTask_UntilInvalid verifies conflicting or malformed WAIT deadlines are rejected.
+func TestWaitTask_UntilInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		task *Task
+	}{
+		{"duration and until", WaitTask("wait", WithDuration("5s"), WithUntil(time.Now()))},
+		{"malformed timestamp", WaitTask("wait", WithUntilExpression("tomorrow"))},
+		{"neither", WaitTask("wait")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateTaskConfig(tt.task); !errors.Is(err, ErrInvalidTaskConfig) {
+				t.Errorf("validateTaskConfig() error = %v, want ErrInvalidTaskConfig", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TriggerKind represents how a workflow is started.
+type TriggerKind string
+
+// Trigger kinds supported by the workflow manifest.
+const (
+	TriggerKindCron     TriggerKind = "CRON"
+	TriggerKindInterval TriggerKind = "INTERVAL"
+	TriggerKindEvent    TriggerKind = "EVENT"
+)
+
+// Trigger describes when a workflow runs.
+//
+// Use the Cron(), Interval() and WithEventTrigger() helpers instead of
+// building Trigger values directly.
+type Trigger struct {
+	// Kind determines which of the fields below is set
+	Kind TriggerKind `json:"kind,omitempty"`
+
+	// Cron expression (standard 5-field format or @-macro), for CRON triggers
+	Cron string `json:"cron,omitempty"`
+
+	// Interval duration (e.g., "5m", "1h"), for INTERVAL triggers
+	Interval string `json:"interval,omitempty"`
+
+	// Event name to start the workflow on, for EVENT triggers
+	Event string `json:"event,omitempty"`
+}
+
+// cronMacros are the @-shorthands accepted in place of a 5-field cron expression.
+var cronMacros = map[string]bool{
+	"@yearly":   true,
+	"@annually": true,
+	"@monthly":  true,
+	"@weekly":   true,
+	"@daily":    true,
+	"@midnight": true,
+	"@hourly":   true,
+}
+
+// Cron creates a schedule trigger from a cron expression.
+//
+// Accepts the standard 5-field format (minute hour day-of-month month day-of-week)
+// or one of the @-macros (@hourly, @daily, @weekly, @monthly, @yearly).
+//
+// Example:
+//
+//	workflow.WithSchedule(workflow.Cron("0 2 * * *"))  // Every day at 02:00
+//	workflow.WithSchedule(workflow.Cron("@hourly"))
+func Cron(expression string) Trigger {
+	return Trigger{
+		Kind: TriggerKindCron,
+		Cron: expression,
+	}
+}
+
+// Interval creates a schedule trigger that fires at a fixed interval.
+// Accepts seconds (int or IntRef), a time.Duration or DurationRef, duration helpers, or Ref types.
+//
+// Example:
+//
+//	workflow.WithSchedule(workflow.Interval(workflow.Minutes(15)))
+//	workflow.WithSchedule(workflow.Interval("1h"))
+func Interval(duration interface{}) Trigger {
+	return Trigger{
+		Kind:     TriggerKindInterval,
+		Interval: toDuration(duration),
+	}
+}
+
+// WithSchedule adds a time-based trigger (cron or interval) to the workflow.
+//
+// Multiple schedules can be added by calling this option multiple times.
+//
+// Example:
+//
+//	workflow.New(ctx,
+//	    workflow.WithNamespace("reports"),
+//	    workflow.WithName("nightly-report"),
+//	    workflow.WithSchedule(workflow.Cron("0 2 * * *")),
+//	)
+func WithSchedule(trigger Trigger) Option {
+	return func(w *Workflow) error {
+		w.Triggers = append(w.Triggers, trigger)
+		return nil
+	}
+}
+
+// WithEventTrigger adds an event trigger to the workflow.
+// The workflow is started whenever the named event is published.
+// Accepts either a string or a StringRef from context.
+//
+// Example:
+//
+//	workflow.WithEventTrigger("order.created")
+func WithEventTrigger(event interface{}) Option {
+	return func(w *Workflow) error {
+		w.Triggers = append(w.Triggers, Trigger{
+			Kind:  TriggerKindEvent,
+			Event: toExpression(event),
+		})
+		return nil
+	}
+}
+
+// WithDisabled ships the workflow without scheduling or triggering it.
+//
+// The workflow is still deployed and visible in the platform UI together with
+// the reason, but its schedules and event triggers do not fire. This is useful
+// during staged rollouts, or to pause a workflow without deleting it.
+//
+// Example:
+//
+//	workflow.New(ctx,
+//	    workflow.WithName("nightly-report"),
+//	    workflow.WithSchedule(workflow.Cron("0 2 * * *")),
+//	    workflow.WithDisabled("waiting for reporting DB migration"),
+//	)
+func WithDisabled(reason string) Option {
+	return func(w *Workflow) error {
+		w.Disabled = true
+		w.DisabledReason = reason
+		return nil
+	}
+}
+
+// validateTrigger validates a single trigger definition.
+func validateTrigger(t Trigger) error {
+	switch t.Kind {
+	case TriggerKindCron:
+		if err := validateCronExpression(t.Cron); err != nil {
+			return err
+		}
+	case TriggerKindInterval:
+		if t.Interval == "" {
+			return NewValidationErrorWithCause(
+				"interval",
+				"",
+				"required",
+				"interval trigger must have a duration",
+				ErrInvalidTrigger,
+			)
+		}
+		if !isExpression(t.Interval) && !deadlineRegex.MatchString(t.Interval) {
+			return NewValidationErrorWithCause(
+				"interval",
+				t.Interval,
+				"format",
+				fmt.Sprintf("interval must be a duration like \"5m\" or \"1h\", got %q", t.Interval),
+				ErrInvalidTrigger,
+			)
+		}
+	case TriggerKindEvent:
+		if t.Event == "" {
+			return NewValidationErrorWithCause(
+				"event",
+				"",
+				"required",
+				"event trigger must have an event name",
+				ErrInvalidTrigger,
+			)
+		}
+	default:
+		return NewValidationErrorWithCause(
+			"kind",
+			string(t.Kind),
+			"enum",
+			fmt.Sprintf("invalid trigger kind: %q", t.Kind),
+			ErrInvalidTrigger,
+		)
+	}
+	return nil
+}
+
+// validateCronExpression performs a structural check on a cron expression.
+// Field values are validated by the platform scheduler.
+func validateCronExpression(expr string) error {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return NewValidationErrorWithCause(
+			"cron",
+			expr,
+			"required",
+			"cron trigger must have an expression",
+			ErrInvalidTrigger,
+		)
+	}
+	if strings.HasPrefix(expr, "@") {
+		if !cronMacros[expr] {
+			return NewValidationErrorWithCause(
+				"cron",
+				expr,
+				"format",
+				fmt.Sprintf("unknown cron macro: %q", expr),
+				ErrInvalidTrigger,
+			)
+		}
+		return nil
+	}
+	if fields := strings.Fields(expr); len(fields) != 5 {
+		return NewValidationErrorWithCause(
+			"cron",
+			expr,
+			"format",
+			fmt.Sprintf("cron expression must have 5 fields, got %d", len(fields)),
+			ErrInvalidTrigger,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package workflow_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/stigmer"
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+func TestWithSchedule(t *testing.T) {
+	ctx := stigmer.NewContext()
+	wf, err := workflow.New(ctx,
+		workflow.WithNamespace("reports"),
+		workflow.WithName("nightly-report"),
+		workflow.WithSchedule(workflow.Cron("0 2 * * *")),
+		workflow.WithSchedule(workflow.Interval(workflow.Minutes(15))),
+		workflow.WithEventTrigger("order.created"),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if len(wf.Triggers) != 3 {
+		t.Fatalf("Triggers count = %d, want 3", len(wf.Triggers))
+	}
+
+	want := []workflow.Trigger{
+		{Kind: workflow.TriggerKindCron, Cron: "0 2 * * *"},
+		{Kind: workflow.TriggerKindInterval, Interval: "15m"},
+		{Kind: workflow.TriggerKindEvent, Event: "order.created"},
+	}
+	for i, w := range want {
+		if wf.Triggers[i] != w {
+			t.Errorf("Triggers[%d] = %+v, want %+v", i, wf.Triggers[i], w)
+		}
+	}
+}
+
+func TestWithSchedule_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		trigger workflow.Option
+	}{
+		{"empty cron", workflow.WithSchedule(workflow.Cron(""))},
+		{"cron with too few fields", workflow.WithSchedule(workflow.Cron("0 2 * *"))},
+		{"unknown cron macro", workflow.WithSchedule(workflow.Cron("@sometimes"))},
+		{"empty interval", workflow.WithSchedule(workflow.Interval(""))},
+		{"empty event", workflow.WithEventTrigger("")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := workflow.New(stigmer.NewContext(),
+				workflow.WithNamespace("reports"),
+				workflow.WithName("nightly-report"),
+				tt.trigger,
+			)
+			if err == nil {
+				t.Fatal("New() expected error, got nil")
+			}
+			if !errors.Is(err, workflow.ErrInvalidTrigger) {
+				t.Errorf("New() error = %v, want ErrInvalidTrigger", err)
+			}
+		})
+	}
+}
+
+func TestWithSchedule_CronMacro(t *testing.T) {
+	_, err := workflow.New(stigmer.NewContext(),
+		workflow.WithNamespace("reports"),
+		workflow.WithName("hourly-report"),
+		workflow.WithSchedule(workflow.Cron("@hourly")),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+}
+
+func TestWithDisabled(t *testing.T) {
+	wf, err := workflow.New(stigmer.NewContext(),
+		workflow.WithNamespace("reports"),
+		workflow.WithName("nightly-report"),
+		workflow.WithSchedule(workflow.Cron("0 2 * * *")),
+		workflow.WithDisabled("staged rollout"),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if !wf.Disabled {
+		t.Error("Disabled = false, want true")
+	}
+	if wf.DisabledReason != "staged rollout" {
+		t.Errorf("DisabledReason = %q, want %q", wf.DisabledReason, "staged rollout")
+	}
+	if len(wf.Triggers) != 1 {
+		t.Errorf("Triggers count = %d, want triggers kept on disabled workflow", len(wf.Triggers))
+	}
+}
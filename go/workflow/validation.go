@@ -3,6 +3,7 @@ package workflow
 import (
 	"fmt"
 	"regexp"
+	"time"
 )
 
 // Validation constants.
@@ -21,6 +22,56 @@ func validate(w *Workflow) error {
 		return err
 	}
 
+	// Validate triggers
+	for i, trigger := range w.Triggers {
+		if err := validateTrigger(trigger); err != nil {
+			return fmt.Errorf("trigger[%d]: %w", i, err)
+		}
+	}
+
+	// Validate input/output declarations
+	if err := validateSchema(w); err != nil {
+		return err
+	}
+
+	// Validate workflow timeout
+	if err := validateDeadline("timeout", w.Timeout); err != nil {
+		return err
+	}
+
+	// Validate retry budget
+	if err := validateErrorPolicy(w.ErrorPolicy); err != nil {
+		return err
+	}
+
+	// Validate canary rollout
+	if err := validateRollout(w.Rollout); err != nil {
+		return err
+	}
+
+	// Validate resource requests
+	if err := validateResources(w.Resources); err != nil {
+		return err
+	}
+
+	// Validate task defaults
+	if err := validateTaskDefaults(w.Defaults); err != nil {
+		return err
+	}
+
+	// Validate finalizer tasks
+	for i, f := range w.Finalizers {
+		if err := validateTaskName(f.Name); err != nil {
+			return fmt.Errorf("finalizers[%d]: %w", i, err)
+		}
+		if err := validateTaskKind(f.Kind); err != nil {
+			return fmt.Errorf("finalizers[%d]: %w", i, err)
+		}
+		if err := validateTaskConfig(f); err != nil {
+			return fmt.Errorf("finalizers[%d]: %w", i, err)
+		}
+	}
+
 	// Note: We no longer require tasks during workflow creation to support
 	// the Pulumi-style pattern where workflows are created first, then tasks
 	// are added via wf.HttpGet(), wf.SetVars(), etc.
@@ -58,6 +109,48 @@ func validate(w *Workflow) error {
 		if err := validateTaskConfig(task); err != nil {
 			return fmt.Errorf("task[%d]: %w", i, err)
 		}
+
+		// Validate task deadline
+		if err := validateDeadline("deadline", task.Deadline); err != nil {
+			return fmt.Errorf("task[%d]: %w", i, err)
+		}
+
+		// Validate compensation tasks
+		for j, comp := range task.Compensations {
+			if err := validateTaskName(comp.Name); err != nil {
+				return fmt.Errorf("task[%d].compensations[%d]: %w", i, j, err)
+			}
+			if err := validateTaskKind(comp.Kind); err != nil {
+				return fmt.Errorf("task[%d].compensations[%d]: %w", i, j, err)
+			}
+			if err := validateTaskConfig(comp); err != nil {
+				return fmt.Errorf("task[%d].compensations[%d]: %w", i, j, err)
+			}
+		}
+	}
+
+	// Validate custom export keys
+	if err := validateContextKeys(w.Tasks, taskNames); err != nil {
+		return err
+	}
+
+	// Validate compensation flow (tasks after a compensated task are wrapped in TRY)
+	if _, err := LowerCompensations(w.Tasks); err != nil {
+		return err
+	}
+
+	// Validate finalizer names and flow (tasks are wrapped in a top-level TRY)
+	if _, err := LowerFinalizers(w.Tasks, w.Finalizers); err != nil {
+		return err
+	}
+
+	// Validate control flow in execution order (Then/switch targets, reachability, loops)
+	ordered, err := OrderTasks(w.Tasks)
+	if err != nil {
+		return err
+	}
+	if err := ValidateFlow(ordered); err != nil {
+		return err
 	}
 
 	return nil
@@ -98,6 +191,33 @@ func validateTaskName(name string) error {
 	return nil
 }
 
+// validateContextKeys checks the keys set by ExportTo are identifiers, unique,
+// and distinct from task names.
+func validateContextKeys(tasks []*Task, taskNames map[string]bool) error {
+	keys := make(map[string]string)
+	for i, task := range tasks {
+		key := task.ContextKey
+		if key == "" {
+			continue
+		}
+		field := fmt.Sprintf("tasks[%d].context_key", i)
+		if !expressionNameRegex.MatchString(key) {
+			return NewValidationErrorWithCause(field, key, "format",
+				"export key must be an identifier (letters, digits and underscores)", ErrInvalidTaskConfig)
+		}
+		if owner, ok := keys[key]; ok {
+			return NewValidationErrorWithCause(field, key, "unique",
+				fmt.Sprintf("export key %q is already used by task %q", key, owner), ErrInvalidTaskConfig)
+		}
+		if key != task.Name && taskNames[key] {
+			return NewValidationErrorWithCause(field, key, "unique",
+				fmt.Sprintf("export key %q clashes with a task name", key), ErrInvalidTaskConfig)
+		}
+		keys[key] = task.Name
+	}
+	return nil
+}
+
 // validateTaskKind validates a task kind.
 func validateTaskKind(kind TaskKind) error {
 	switch kind {
@@ -112,9 +232,13 @@ func validateTaskKind(kind TaskKind) error {
 		TaskKindWait,
 		TaskKindCallActivity,
 		TaskKindRaise,
-		TaskKindRun:
+		TaskKindRun,
+		TaskKindAgentCall:
 		return nil
 	default:
+		if isCustomTaskKind(kind) {
+			return nil
+		}
 		return NewValidationErrorWithCause(
 			"kind",
 			string(kind),
@@ -127,6 +251,15 @@ func validateTaskKind(kind TaskKind) error {
 
 // validateTaskConfig validates task-specific configuration.
 func validateTaskConfig(task *Task) error {
+	// Custom task kinds are validated as the built-in task they lower to
+	if isCustomTaskKind(task.Kind) {
+		lowered, err := LowerTask(task)
+		if err != nil {
+			return err
+		}
+		return validateTaskConfig(lowered)
+	}
+
 	// Each task type has its own validation rules
 	switch task.Kind {
 	case TaskKindSet:
@@ -153,6 +286,8 @@ func validateTaskConfig(task *Task) error {
 		return validateRaiseTaskConfig(task)
 	case TaskKindRun:
 		return validateRunTaskConfig(task)
+	case TaskKindAgentCall:
+		return validateAgentCallTaskConfig(task)
 	default:
 		return NewValidationErrorWithCause(
 			"config",
@@ -229,6 +364,9 @@ func validateHttpCallTaskConfig(task *Task) error {
 			ErrInvalidTaskConfig,
 		)
 	}
+	if cfg.timeoutErr != nil {
+		return NewValidationErrorWithCause("config.timeout_seconds", "", "format", cfg.timeoutErr.Error(), ErrInvalidTaskConfig)
+	}
 	if cfg.TimeoutSeconds < 0 || cfg.TimeoutSeconds > 300 {
 		return NewValidationErrorWithCause(
 			"config.timeout_seconds",
@@ -238,6 +376,19 @@ func validateHttpCallTaskConfig(task *Task) error {
 			ErrInvalidTaskConfig,
 		)
 	}
+	if cfg.OAuth2 != nil {
+		if err := validateOAuth2Config(cfg.OAuth2); err != nil {
+			return err
+		}
+	}
+	if err := validateProxyURL(cfg.Proxy); err != nil {
+		return err
+	}
+	if cfg.RetryPolicy != nil {
+		if err := validateRetryPolicy(cfg.RetryPolicy); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -270,7 +421,15 @@ func validateGrpcCallTaskConfig(task *Task) error {
 			ErrInvalidTaskConfig,
 		)
 	}
-	return nil
+	if err := validateGrpcEndpoint(cfg.Endpoint); err != nil {
+		return err
+	}
+	if cfg.RetryPolicy != nil {
+		if err := validateRetryPolicy(cfg.RetryPolicy); err != nil {
+			return err
+		}
+	}
+	return validateDuration("config.deadline", cfg.Deadline)
 }
 
 func validateSwitchTaskConfig(task *Task) error {
@@ -394,7 +553,36 @@ func validateListenTaskConfig(task *Task) error {
 			ErrInvalidTaskConfig,
 		)
 	}
-	return nil
+	for key := range cfg.Correlation {
+		if key == "" {
+			return NewValidationErrorWithCause(
+				"config.correlation",
+				"",
+				"required",
+				"LISTEN task correlation key must not be empty",
+				ErrInvalidTaskConfig,
+			)
+		}
+	}
+	if cfg.Timeout != "" && cfg.TimeoutThen == "" {
+		return NewValidationErrorWithCause(
+			"config.timeout_then",
+			"",
+			"required",
+			"LISTEN task timeout must have a task to jump to",
+			ErrInvalidTaskConfig,
+		)
+	}
+	if cfg.TimeoutThen != "" && cfg.Timeout == "" {
+		return NewValidationErrorWithCause(
+			"config.timeout",
+			"",
+			"required",
+			"LISTEN task timeout handler requires a timeout duration",
+			ErrInvalidTaskConfig,
+		)
+	}
+	return validateDuration("config.timeout", cfg.Timeout)
 }
 
 func validateWaitTaskConfig(task *Task) error {
@@ -408,16 +596,36 @@ func validateWaitTaskConfig(task *Task) error {
 			ErrInvalidTaskConfig,
 		)
 	}
-	if cfg.Duration == "" {
+	if cfg.Duration == "" && cfg.Until == "" {
 		return NewValidationErrorWithCause(
 			"config.duration",
 			"",
 			"required",
-			"WAIT task must have a duration",
+			"WAIT task must have a duration or an until time",
 			ErrInvalidTaskConfig,
 		)
 	}
-	return nil
+	if cfg.Duration != "" && cfg.Until != "" {
+		return NewValidationErrorWithCause(
+			"config.until",
+			cfg.Until,
+			"exclusive",
+			"WAIT task cannot have both a duration and an until time",
+			ErrInvalidTaskConfig,
+		)
+	}
+	if cfg.Until != "" && !isExpression(cfg.Until) {
+		if _, err := time.Parse(time.RFC3339, cfg.Until); err != nil {
+			return NewValidationErrorWithCause(
+				"config.until",
+				cfg.Until,
+				"format",
+				"WAIT task until time must be an RFC 3339 timestamp or an expression",
+				ErrInvalidTaskConfig,
+			)
+		}
+	}
+	return validateDuration("config.duration", cfg.Duration)
 }
 
 func validateCallActivityTaskConfig(task *Task) error {
@@ -440,7 +648,18 @@ func validateCallActivityTaskConfig(task *Task) error {
 			ErrInvalidTaskConfig,
 		)
 	}
-	return nil
+	if cfg.RetryPolicy != nil {
+		if err := validateRetryPolicy(cfg.RetryPolicy); err != nil {
+			return err
+		}
+	}
+	if err := validateDuration("config.start_to_close_timeout", cfg.StartToCloseTimeout); err != nil {
+		return err
+	}
+	if err := validateDuration("config.schedule_to_close_timeout", cfg.ScheduleToCloseTimeout); err != nil {
+		return err
+	}
+	return validateDuration("config.heartbeat_timeout", cfg.HeartbeatTimeout)
 }
 
 func validateRaiseTaskConfig(task *Task) error {
@@ -466,6 +685,61 @@ func validateRaiseTaskConfig(task *Task) error {
 	return nil
 }
 
+func validateAgentCallTaskConfig(task *Task) error {
+	cfg, ok := task.Config.(*AgentCallTaskConfig)
+	if !ok {
+		return NewValidationErrorWithCause(
+			"config",
+			"",
+			"type",
+			"invalid config type for AGENT_CALL task",
+			ErrInvalidTaskConfig,
+		)
+	}
+	if cfg.Agent.Slug() == "" {
+		return NewValidationErrorWithCause(
+			"config.agent",
+			"",
+			"required",
+			"AGENT_CALL task must reference an agent",
+			ErrInvalidTaskConfig,
+		)
+	}
+	if cfg.Message == "" {
+		return NewValidationErrorWithCause(
+			"config.message",
+			"",
+			"required",
+			"AGENT_CALL task must have a prompt",
+			ErrInvalidTaskConfig,
+		)
+	}
+	if cfg.Config != nil {
+		if cfg.Config.timeoutErr != nil {
+			return NewValidationErrorWithCause("config.config.timeout", "", "format", cfg.Config.timeoutErr.Error(), ErrInvalidTaskConfig)
+		}
+		if cfg.Config.Timeout < 0 || cfg.Config.Timeout > 3600 {
+			return NewValidationErrorWithCause(
+				"config.config.timeout",
+				fmt.Sprintf("%d", cfg.Config.Timeout),
+				"range",
+				"AGENT_CALL timeout must be between 1 and 3600 seconds",
+				ErrInvalidTaskConfig,
+			)
+		}
+		if cfg.Config.Temperature < 0 || cfg.Config.Temperature > 1 {
+			return NewValidationErrorWithCause(
+				"config.config.temperature",
+				fmt.Sprintf("%g", cfg.Config.Temperature),
+				"range",
+				"AGENT_CALL temperature must be between 0.0 and 1.0",
+				ErrInvalidTaskConfig,
+			)
+		}
+	}
+	return nil
+}
+
 func validateRunTaskConfig(task *Task) error {
 	cfg, ok := task.Config.(*RunTaskConfig)
 	if !ok {
@@ -477,6 +751,9 @@ func validateRunTaskConfig(task *Task) error {
 			ErrInvalidTaskConfig,
 		)
 	}
+	if cfg.refErr != nil {
+		return NewValidationErrorWithCause("config.workflow", "", "required", cfg.refErr.Error(), ErrInvalidTaskConfig)
+	}
 	if cfg.WorkflowName == "" {
 		return NewValidationErrorWithCause(
 			"config.workflow",
@@ -0,0 +1,161 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+)
+
+// SkipChildren can be returned by a WalkFunc to skip the nested tasks of the
+// current task. It is not returned as an error by Walk.
+var SkipChildren = errors.New("skip children")
+
+// WalkFunc is called by Walk for every task in a workflow.
+//
+// The task may be modified in place. Return SkipChildren to skip the task's
+// nested tasks, or any other error to stop the walk.
+type WalkFunc func(task *Task) error
+
+// RewriteFunc is called by Rewrite for every task in a workflow.
+//
+// It returns the task to use in place of the given one: the same task (possibly
+// modified), a new task, or nil to remove the task from the workflow.
+type RewriteFunc func(task *Task) (*Task, error)
+
+// Walk visits every task in the workflow depth-first, including tasks nested
+// inside FOR, FORK, and TRY (and CATCH) bodies, compensations (see Compensate),
+// and the workflow finalizers (see WithFinalizer). Each task is visited before
+// its nested tasks.
+//
+// Example (inject a tracing header into every HTTP call):
+//
+//	err := workflow.Walk(wf, func(task *workflow.Task) error {
+//	    if cfg, ok := task.Config.(*workflow.HttpCallTaskConfig); ok {
+//	        cfg.Headers["X-Trace-Id"] = workflow.RuntimeEnv("TRACE_ID")
+//	    }
+//	    return nil
+//	})
+func Walk(wf *Workflow, fn WalkFunc) error {
+	for _, tasks := range [][]*Task{wf.Tasks, wf.Finalizers} {
+		for _, task := range tasks {
+			if err := walkWithFunc(task, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// walkWithFunc calls fn for task and then walks its nested tasks (including compensations).
+func walkWithFunc(task *Task, fn WalkFunc) error {
+	if err := fn(task); err != nil {
+		if errors.Is(err, SkipChildren) {
+			return nil
+		}
+		return fmt.Errorf("task %s: %w", task.Name, err)
+	}
+	for _, child := range nestedTasks(task) {
+		if err := walkWithFunc(child, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rewrite replaces every task in the workflow, including nested tasks,
+// compensations and finalizers, with the result of fn. Returning nil from fn
+// removes the task.
+//
+// Tasks are rewritten depth-first: fn is called for a task first, and then for
+// the nested tasks of the task it returned.
+//
+// Example (rewrite hostnames):
+//
+//	err := workflow.Rewrite(wf, func(task *workflow.Task) (*workflow.Task, error) {
+//	    if cfg, ok := task.Config.(*workflow.HttpCallTaskConfig); ok {
+//	        cfg.URI = strings.Replace(cfg.URI, "api.example.com", "api.staging.example.com", 1)
+//	    }
+//	    return task, nil
+//	})
+func Rewrite(wf *Workflow, fn RewriteFunc) error {
+	tasks, err := rewriteList(wf.Tasks, fn)
+	if err != nil {
+		return err
+	}
+	finalizers, err := rewriteList(wf.Finalizers, fn)
+	if err != nil {
+		return err
+	}
+	wf.Tasks, wf.Finalizers = tasks, finalizers
+	return nil
+}
+
+// rewriteList applies rewriteTask to a list of tasks, dropping removed tasks.
+func rewriteList(tasks []*Task, fn RewriteFunc) ([]*Task, error) {
+	if tasks == nil {
+		return nil, nil
+	}
+	result := make([]*Task, 0, len(tasks))
+	for _, task := range tasks {
+		rewritten, err := rewriteTask(task, fn)
+		if err != nil {
+			return nil, err
+		}
+		if rewritten != nil {
+			result = append(result, rewritten)
+		}
+	}
+	return result, nil
+}
+
+// rewriteTask applies fn to task and then rewrites the nested tasks and
+// compensations of the result.
+func rewriteTask(task *Task, fn RewriteFunc) (*Task, error) {
+	rewritten, err := fn(task)
+	if err != nil {
+		return nil, fmt.Errorf("task %s: %w", task.Name, err)
+	}
+	if rewritten == nil {
+		return nil, nil
+	}
+
+	switch cfg := rewritten.Config.(type) {
+	case *ForTaskConfig:
+		if cfg.Do, err = rewriteNested(cfg.Do, fn); err != nil {
+			return nil, err
+		}
+	case *ForkTaskConfig:
+		for i := range cfg.Branches {
+			if cfg.Branches[i].Tasks, err = rewriteNested(cfg.Branches[i].Tasks, fn); err != nil {
+				return nil, err
+			}
+		}
+	case *TryTaskConfig:
+		if cfg.Tasks, err = rewriteNested(cfg.Tasks, fn); err != nil {
+			return nil, err
+		}
+		for i := range cfg.Catch {
+			if cfg.Catch[i].Tasks, err = rewriteNested(cfg.Catch[i].Tasks, fn); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if rewritten.Compensations, err = rewriteList(rewritten.Compensations, fn); err != nil {
+		return nil, err
+	}
+	return rewritten, nil
+}
+
+// rewriteNested applies rewriteTask to a slice of nested tasks.
+func rewriteNested(tasks []Task, fn RewriteFunc) ([]Task, error) {
+	result := make([]Task, 0, len(tasks))
+	for i := range tasks {
+		rewritten, err := rewriteTask(&tasks[i], fn)
+		if err != nil {
+			return nil, err
+		}
+		if rewritten != nil {
+			result = append(result, *rewritten)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,174 @@
+package workflow
+
+import (
+	"errors"
+	"testing"
+)
+
+func newWalkTestWorkflow() *Workflow {
+	return &Workflow{
+		Tasks: []*Task{
+			HttpCallTask("fetch", WithHTTPGet(), WithURI("https://api.example.com/items")),
+			ForTask("loop",
+				WithIn("${.items}"),
+				WithDo(
+					HttpCallTask("process", WithHTTPPost(), WithURI("https://api.example.com/process")),
+					SetTask("debug", SetVar("x", "1")),
+				),
+			),
+			TryTask("attempt",
+				WithTry(HttpCallTask("notify", WithHTTPPost(), WithURI("https://api.example.com/notify"))),
+				WithCatch([]string{"NetworkError"}, "err", SetTask("recover", SetVar("failed", "true"))),
+			),
+		},
+	}
+}
+
+// TestWalk_MutatesAllHTTPTasks verifies Walk reaches nested tasks and mutations persist.
+func TestWalk_MutatesAllHTTPTasks(t *testing.T) {
+	wf := newWalkTestWorkflow()
+
+	err := Walk(wf, func(task *Task) error {
+		if cfg, ok := task.Config.(*HttpCallTaskConfig); ok {
+			cfg.Headers["X-Trace-Id"] = "trace"
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk() error = %v", err)
+	}
+
+	count := 0
+	for task := range wf.AllTasks() {
+		if cfg, ok := task.Config.(*HttpCallTaskConfig); ok {
+			count++
+			if cfg.Headers["X-Trace-Id"] != "trace" {
+				t.Errorf("task %s missing tracing header", task.Name)
+			}
+		}
+	}
+	if count != 3 {
+		t.Errorf("found %d HTTP tasks, want 3", count)
+	}
+}
+
+// TestWalk_SkipChildrenAndErrors verifies SkipChildren and error propagation.
+func TestWalk_SkipChildrenAndErrors(t *testing.T) {
+	wf := newWalkTestWorkflow()
+
+	var visited []string
+	err := Walk(wf, func(task *Task) error {
+		visited = append(visited, task.Name)
+		if task.Kind == TaskKindFor {
+			return SkipChildren
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk() error = %v", err)
+	}
+	for _, name := range visited {
+		if name == "process" || name == "debug" {
+			t.Errorf("visited %q inside skipped FOR task", name)
+		}
+	}
+
+	errStop := errors.New("stop")
+	err = Walk(wf, func(task *Task) error {
+		if task.Name == "notify" {
+			return errStop
+		}
+		return nil
+	})
+	if !errors.Is(err, errStop) {
+		t.Errorf("Walk() error = %v, want %v", err, errStop)
+	}
+}
+
+// TestRewrite_ReplaceAndRemove verifies Rewrite can replace and remove nested tasks.
+func TestRewrite_ReplaceAndRemove(t *testing.T) {
+	wf := newWalkTestWorkflow()
+
+	err := Rewrite(wf, func(task *Task) (*Task, error) {
+		switch task.Name {
+		case "debug":
+			return nil, nil
+		case "recover":
+			return SetTask("recovered", SetVar("failed", "true")), nil
+		}
+		return task, nil
+	})
+	if err != nil {
+		t.Fatalf("Rewrite() error = %v", err)
+	}
+
+	forCfg := wf.Tasks[1].Config.(*ForTaskConfig)
+	if len(forCfg.Do) != 1 || forCfg.Do[0].Name != "process" {
+		t.Errorf("FOR body = %v, want only \"process\"", forCfg.Do)
+	}
+
+	tryCfg := wf.Tasks[2].Config.(*TryTaskConfig)
+	if tryCfg.Catch[0].Tasks[0].Name != "recovered" {
+		t.Errorf("catch task = %q, want %q", tryCfg.Catch[0].Tasks[0].Name, "recovered")
+	}
+}
+
+// TestRewrite_RemoveTopLevel verifies top-level tasks can be removed.
+func TestRewrite_RemoveTopLevel(t *testing.T) {
+	wf := newWalkTestWorkflow()
+
+	err := Rewrite(wf, func(task *Task) (*Task, error) {
+		if task.Name == "fetch" {
+			return nil, nil
+		}
+		return task, nil
+	})
+	if err != nil {
+		t.Fatalf("Rewrite() error = %v", err)
+	}
+	if len(wf.Tasks) != 2 || wf.Tasks[0].Name != "loop" {
+		t.Errorf("Tasks after Rewrite() = %d (first %q), want 2 (first \"loop\")", len(wf.Tasks), wf.Tasks[0].Name)
+	}
+}
+
+// TestWalk_CompensationsAndFinalizers verifies Walk and Rewrite reach compensations and finalizers.
+func TestWalk_CompensationsAndFinalizers(t *testing.T) {
+	wf := newWalkTestWorkflow()
+	wf.Tasks[0].Compensate(HttpCallTask("undoFetch", WithHTTPDelete(), WithURI("https://api.example.com/items")))
+	wf.Finalizers = []*Task{
+		HttpCallTask("audit", WithHTTPPost(), WithURI("https://api.example.com/audit")),
+		SetTask("cleanup", SetVar("done", "true")),
+	}
+
+	visited := map[string]bool{}
+	if err := Walk(wf, func(task *Task) error {
+		visited[task.Name] = true
+		return nil
+	}); err != nil {
+		t.Fatalf("Walk() error = %v", err)
+	}
+	for _, name := range []string{"undoFetch", "audit", "cleanup"} {
+		if !visited[name] {
+			t.Errorf("Walk() did not visit %q", name)
+		}
+	}
+
+	err := Rewrite(wf, func(task *Task) (*Task, error) {
+		switch task.Name {
+		case "cleanup":
+			return nil, nil
+		case "undoFetch":
+			return SetTask("undone", SetVar("fetched", "false")), nil
+		}
+		return task, nil
+	})
+	if err != nil {
+		t.Fatalf("Rewrite() error = %v", err)
+	}
+	if len(wf.Finalizers) != 1 || wf.Finalizers[0].Name != "audit" {
+		t.Errorf("Finalizers after Rewrite() = %v, want [audit]", taskNames(wf.Finalizers))
+	}
+	if comps := wf.Tasks[0].Compensations; len(comps) != 1 || comps[0].Name != "undone" {
+		t.Errorf("Compensations after Rewrite() = %v, want [undone]", taskNames(comps))
+	}
+}
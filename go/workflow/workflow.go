@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/leftbin/stigmer-sdk/go/environment"
+	"github.com/leftbin/stigmer-sdk/go/resources"
 )
 
 // Context is a minimal interface that represents a stigmer context.
@@ -42,12 +43,45 @@ type Workflow struct {
 	// Ordered list of tasks that make up this workflow
 	Tasks []*Task
 
+	// Finalizers run at the end of the workflow on success and failure (set by WithFinalizer)
+	Finalizers []*Task
+
 	// Environment variables required by the workflow
 	EnvironmentVariables []environment.Variable
 
 	// Organization that owns this workflow (optional)
 	Org string
 
+	// Triggers define when the workflow runs (schedules, events)
+	Triggers []Trigger
+
+	// Disabled marks the workflow as deployed but not scheduled or triggered (set by WithDisabled)
+	Disabled bool
+
+	// DisabledReason explains why the workflow is disabled, shown in the platform UI
+	DisabledReason string
+
+	// Inputs declare the parameters callers pass to the workflow (used by RUN tasks)
+	Inputs []InputParam
+
+	// Outputs declare the fields the workflow exposes to callers
+	Outputs []string
+
+	// Timeout is the maximum execution time for the whole workflow (set by WithWorkflowTimeout)
+	Timeout string
+
+	// ErrorPolicy is the workflow-wide retry budget (set by WithErrorPolicy)
+	ErrorPolicy *ErrorPolicy
+
+	// Rollout routes a fraction of triggers to this version (set by WithRollout)
+	Rollout *Rollout
+
+	// Resources is the CPU and memory requested per execution (set by WithResources)
+	Resources resources.Requirements
+
+	// Defaults are settings inherited by HTTP and gRPC tasks (set by WithDefaults)
+	Defaults *TaskDefaults
+
 	// Context reference (optional, used for typed variable management)
 	ctx Context
 }
@@ -80,9 +114,80 @@ type Option func(*Workflow) error
 //	    return err
 //	})
 func New(ctx Context, opts ...Option) (*Workflow, error) {
+	w, _, err := NewWithReport(ctx, opts...)
+	return w, err
+}
+
+// NewWithReport creates a new Workflow like New, and also returns a report of
+// non-fatal validation warnings (e.g., missing description, default version applied).
+//
+// Use this when building tooling that surfaces warnings to users. The report is
+// nil only when an error is returned.
+//
+// Example:
+//
+//	wf, report, err := workflow.NewWithReport(ctx,
+//	    workflow.WithNamespace("data-processing"),
+//	    workflow.WithName("daily-sync"),
+//	)
+//	if err != nil {
+//	    return err
+//	}
+//	for _, w := range report.Warnings {
+//	    log.Printf("warning: %s", w)
+//	}
+func NewWithReport(ctx Context, opts ...Option) (*Workflow, *ValidationReport, error) {
+	w, report, err := build(ctx, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Register with context
+	ctx.RegisterWorkflow(w)
+
+	return w, report, nil
+}
+
+// Build creates and validates a Workflow like New, without registering it
+// with a context.
+//
+// Use Build in reusable library functions that construct candidate workflows;
+// the caller decides which ones to synthesize by adopting them with
+// stigmer.Context.Adopt.
+//
+// Example:
+//
+//	// In a library
+//	func NightlySync(source string) (*workflow.Workflow, error) {
+//	    wf, err := workflow.Build(
+//	        workflow.WithNamespace("sync"),
+//	        workflow.WithName("nightly-"+source),
+//	    )
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//	    wf.HttpGet("fetch", "https://"+source+".example.com/export")
+//	    return wf, nil
+//	}
+//
+//	// In the program
+//	wf, err := lib.NightlySync("crm")
+//	if err != nil {
+//	    return err
+//	}
+//	return ctx.Adopt(wf)
+func Build(opts ...Option) (*Workflow, error) {
+	w, _, err := build(nil, opts)
+	return w, err
+}
+
+// build applies options, fills in defaults and validates a workflow without registering it.
+func build(ctx Context, opts []Option) (*Workflow, *ValidationReport, error) {
+	report := &ValidationReport{}
+
 	w := &Workflow{
 		Document: Document{
-			DSL: "1.0.0", // Default DSL version
+			DSL: DefaultDSLVersion,
 		},
 		Tasks:                []*Task{},
 		EnvironmentVariables: []environment.Variable{},
@@ -92,24 +197,25 @@ func New(ctx Context, opts ...Option) (*Workflow, error) {
 	// Apply all options
 	for _, opt := range opts {
 		if err := opt(w); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
 	// Auto-generate version if not provided
 	if w.Document.Version == "" {
 		w.Document.Version = "0.1.0" // Default version for development
+		report.addWarning("document.version", "version not set, defaulting to 0.1.0")
 	}
 
 	// Validate the workflow
 	if err := validate(w); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	// Register with context
-	ctx.RegisterWorkflow(w)
+	// Collect non-fatal warnings
+	collectWarnings(w, report)
 
-	return w, nil
+	return w, report, nil
 }
 
 // WithNamespace sets the workflow namespace.
@@ -0,0 +1,1241 @@
+package workflowtest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// This file implements the subset of JQ used by workflow expressions:
+// paths ($context.a.b, .items[0], .items[], .sha[0:7]), literals, array construction,
+// arithmetic, comparisons,
+// and/or/not, if-then-else, pipes, and common filters (tostring, length,
+// ascii_downcase, @uri, @base64, contains, map, select, split, sub, ...).
+
+// ErrEvaluation is returned (wrapped) when an expression cannot be parsed or evaluated.
+var ErrEvaluation = errors.New("expression evaluation failed")
+
+// ErrUnsupported is returned (wrapped, together with ErrEvaluation) when an
+// expression uses a JQ filter this evaluator does not implement. It means the
+// expression could not be checked, not that it is wrong.
+var ErrUnsupported = errors.New("unsupported JQ filter")
+
+// evaluate evaluates a ${ ... } expression against the input and variables.
+func evaluate(expr string, input any, vars map[string]any) (any, error) {
+	body := strings.TrimSpace(expr)
+	body = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(body, "${"), "}"))
+
+	tokens, err := tokenize(body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrEvaluation, expr, err)
+	}
+	p := &parser{tokens: tokens}
+	n, err := p.parsePipe()
+	if err == nil && !p.done() {
+		err = fmt.Errorf("unexpected %q", p.peek().text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrEvaluation, expr, err)
+	}
+
+	value, err := n.eval(input, vars)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrEvaluation, expr, err)
+	}
+	if s, ok := value.(stream); ok {
+		if len(s) != 1 {
+			return nil, fmt.Errorf("%w: %s: produced %d values, want 1", ErrEvaluation, expr, len(s))
+		}
+		return s[0], nil
+	}
+	return value, nil
+}
+
+// stream holds the values of a filter that does not produce exactly one
+// value, such as select() when its condition is false.
+type stream []any
+
+// appendValues appends v, or every value of a stream, to values.
+func appendValues(values []any, v any) []any {
+	if s, ok := v.(stream); ok {
+		return append(values, s...)
+	}
+	return append(values, v)
+}
+
+// =============================================================================
+// Lexer
+// =============================================================================
+
+type tokenKind int
+
+const (
+	tokNumber tokenKind = iota
+	tokString
+	tokIdent    // identifiers and keywords (and, or, if, ...)
+	tokVariable // $name
+	tokFormat   // @uri, @base64, ...
+	tokOperator
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{tokNumber, string(runes[start:i])})
+		case r == '"' || r == '\'':
+			str, n, err := scanString(runes[i:])
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{tokString, str})
+			i += n
+		case r == '$' || r == '@' || isIdentStart(r):
+			start := i
+			i++
+			for i < len(runes) && isIdentPart(runes[i]) {
+				i++
+			}
+			kind := tokIdent
+			if r == '$' {
+				kind = tokVariable
+			} else if r == '@' {
+				kind = tokFormat
+			}
+			tokens = append(tokens, token{kind, string(runes[start:i])})
+		default:
+			if i+1 < len(runes) {
+				two := string(runes[i : i+2])
+				switch two {
+				case "==", "!=", "<=", ">=":
+					tokens = append(tokens, token{tokOperator, two})
+					i += 2
+					continue
+				}
+			}
+			if !strings.ContainsRune(".[]()|+-*/%<>!,;:", r) {
+				return nil, fmt.Errorf("unexpected character %q", r)
+			}
+			tokens = append(tokens, token{tokOperator, string(r)})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+// scanString scans a quoted string literal, returning its value and length in runes.
+func scanString(runes []rune) (string, int, error) {
+	quote := runes[0]
+	var b strings.Builder
+	for i := 1; i < len(runes); i++ {
+		switch runes[i] {
+		case quote:
+			return b.String(), i + 1, nil
+		case '\\':
+			i++
+			if i >= len(runes) {
+				return "", 0, errors.New("unterminated string")
+			}
+			switch runes[i] {
+			case 'n':
+				b.WriteRune('\n')
+			case 't':
+				b.WriteRune('\t')
+			case 'r':
+				b.WriteRune('\r')
+			default:
+				b.WriteRune(runes[i])
+			}
+		default:
+			b.WriteRune(runes[i])
+		}
+	}
+	return "", 0, errors.New("unterminated string")
+}
+
+func isIdentStart(r rune) bool { return r == '_' || unicode.IsLetter(r) }
+func isIdentPart(r rune) bool  { return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) }
+
+// =============================================================================
+// Parser
+// =============================================================================
+
+type node interface {
+	eval(input any, vars map[string]any) (any, error)
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) done() bool { return p.pos >= len(p.tokens) }
+
+func (p *parser) peek() token {
+	if p.done() {
+		return token{kind: -1}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+// accept consumes the next token if it is an operator or identifier with the given text.
+func (p *parser) accept(text string) bool {
+	t := p.peek()
+	if (t.kind == tokOperator || t.kind == tokIdent) && t.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// check reports whether the next token is the operator text, without consuming it.
+func (p *parser) check(text string) bool {
+	t := p.peek()
+	return t.kind == tokOperator && t.text == text
+}
+
+func (p *parser) expect(text string) error {
+	if !p.accept(text) {
+		if p.done() {
+			return fmt.Errorf("expected %q, got end of expression", text)
+		}
+		return fmt.Errorf("expected %q, got %q", text, p.peek().text)
+	}
+	return nil
+}
+
+func (p *parser) parsePipe() (node, error) {
+	left, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("|") {
+		right, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		left = pipeNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{"or", left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("and") {
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{"and", left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.accept(op) {
+			right, err := p.parseAdditive()
+			if err != nil {
+				return nil, err
+			}
+			return binaryNode{op, left, right}, nil
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAdditive() (node, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek().text
+		if p.peek().kind != tokOperator || (op != "+" && op != "-") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op, left, right}
+	}
+}
+
+func (p *parser) parseMultiplicative() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek().text
+		if p.peek().kind != tokOperator || (op != "*" && op != "/" && op != "%") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op, left, right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.accept("!") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return pipeNode{operand, funcNode{name: "not"}}, nil
+	}
+	if p.accept("-") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return binaryNode{"-", literalNode{0.0}, operand}, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *parser) parsePostfix() (node, error) {
+	n, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	return p.parsePathSegments(n)
+}
+
+// parsePathSegments parses the .field and [index] segments that follow n.
+func (p *parser) parsePathSegments(n node) (node, error) {
+	for {
+		switch {
+		case p.peek().kind == tokOperator && p.peek().text == "." && p.pos+1 < len(p.tokens) && p.tokens[p.pos+1].kind == tokIdent:
+			p.next()
+			n = fieldNode{n, p.next().text}
+		case p.accept("["):
+			if p.accept("]") {
+				// Later segments apply to each value: .items[].id is .items[] | .id
+				rest, err := p.parsePathSegments(identityNode{})
+				if err != nil {
+					return nil, err
+				}
+				return pipeNode{iterateNode{n}, rest}, nil
+			}
+			var index node
+			if !p.check(":") {
+				var err error
+				if index, err = p.parsePipe(); err != nil {
+					return nil, err
+				}
+			}
+			if p.accept(":") {
+				slice := sliceNode{target: n, from: index}
+				if !p.check("]") {
+					var err error
+					if slice.to, err = p.parsePipe(); err != nil {
+						return nil, err
+					}
+				}
+				if err := p.expect("]"); err != nil {
+					return nil, err
+				}
+				n = slice
+				continue
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			n = indexNode{n, index}
+		default:
+			return n, nil
+		}
+	}
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	if p.done() {
+		return nil, errors.New("unexpected end of expression")
+	}
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", t.text)
+		}
+		return literalNode{f}, nil
+	case tokString:
+		return literalNode{t.text}, nil
+	case tokVariable:
+		return variableNode{strings.TrimPrefix(t.text, "$")}, nil
+	case tokFormat:
+		return funcNode{name: t.text}, nil
+	case tokIdent:
+		switch t.text {
+		case "true":
+			return literalNode{true}, nil
+		case "false":
+			return literalNode{false}, nil
+		case "null":
+			return literalNode{nil}, nil
+		case "if":
+			return p.parseIf()
+		}
+		fn := funcNode{name: t.text}
+		if p.accept("(") {
+			// Arguments are separated by ";", as in sub("a"; "b")
+			for {
+				arg, err := p.parsePipe()
+				if err != nil {
+					return nil, err
+				}
+				fn.args = append(fn.args, arg)
+				if !p.accept(";") {
+					break
+				}
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+		}
+		return fn, nil
+	case tokOperator:
+		switch t.text {
+		case "(":
+			n, err := p.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+			return n, p.expect(")")
+		case "[":
+			// Array construction collects every value of the inner expression
+			if p.accept("]") {
+				return literalNode{[]any{}}, nil
+			}
+			n, err := p.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+			return collectNode{n}, p.expect("]")
+		case ".":
+			// .field directly after the dot, otherwise identity
+			if p.peek().kind == tokIdent {
+				return fieldNode{identityNode{}, p.next().text}, nil
+			}
+			return identityNode{}, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected %q", t.text)
+}
+
+func (p *parser) parseIf() (node, error) {
+	cond, err := p.parsePipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("then"); err != nil {
+		return nil, err
+	}
+	then, err := p.parsePipe()
+	if err != nil {
+		return nil, err
+	}
+	n := ifNode{cond: cond, then: then, otherwise: identityNode{}}
+	switch {
+	case p.accept("elif"):
+		otherwise, err := p.parseIf()
+		if err != nil {
+			return nil, err
+		}
+		n.otherwise = otherwise
+		return n, nil
+	case p.accept("else"):
+		otherwise, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		n.otherwise = otherwise
+	}
+	return n, p.expect("end")
+}
+
+// =============================================================================
+// Evaluation
+// =============================================================================
+
+type identityNode struct{}
+
+func (identityNode) eval(input any, _ map[string]any) (any, error) { return input, nil }
+
+type literalNode struct{ value any }
+
+func (n literalNode) eval(any, map[string]any) (any, error) { return n.value, nil }
+
+type variableNode struct{ name string }
+
+func (n variableNode) eval(_ any, vars map[string]any) (any, error) {
+	value, ok := vars[n.name]
+	if !ok {
+		return nil, fmt.Errorf("$%s is not defined", n.name)
+	}
+	return value, nil
+}
+
+type pipeNode struct{ left, right node }
+
+func (n pipeNode) eval(input any, vars map[string]any) (any, error) {
+	value, err := n.left.eval(input, vars)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := value.(stream)
+	if !ok {
+		return n.right.eval(value, vars)
+	}
+	// Apply the right side to each value of the stream
+	var result stream
+	for _, v := range s {
+		out, err := n.right.eval(v, vars)
+		if err != nil {
+			return nil, err
+		}
+		result = appendValues(result, out)
+	}
+	return result, nil
+}
+
+type fieldNode struct {
+	target node
+	name   string
+}
+
+func (n fieldNode) eval(input any, vars map[string]any) (any, error) {
+	target, err := n.target.eval(input, vars)
+	if err != nil {
+		return nil, err
+	}
+	switch t := target.(type) {
+	case nil:
+		return nil, nil
+	case map[string]any:
+		return t[n.name], nil
+	default:
+		return nil, fmt.Errorf("cannot index %s with %q", typeName(target), n.name)
+	}
+}
+
+type indexNode struct{ target, index node }
+
+func (n indexNode) eval(input any, vars map[string]any) (any, error) {
+	target, err := n.target.eval(input, vars)
+	if err != nil {
+		return nil, err
+	}
+	index, err := n.index.eval(input, vars)
+	if err != nil {
+		return nil, err
+	}
+	switch t := target.(type) {
+	case nil:
+		return nil, nil
+	case map[string]any:
+		key, ok := index.(string)
+		if !ok {
+			return nil, fmt.Errorf("cannot index object with %s", typeName(index))
+		}
+		return t[key], nil
+	case []any:
+		f, ok := index.(float64)
+		if !ok {
+			return nil, fmt.Errorf("cannot index array with %s", typeName(index))
+		}
+		i := int(f)
+		if i < 0 {
+			i += len(t)
+		}
+		if i < 0 || i >= len(t) {
+			return nil, nil
+		}
+		return t[i], nil
+	default:
+		return nil, fmt.Errorf("cannot index %s", typeName(target))
+	}
+}
+
+// sliceNode is .[from:to] on a string or array; either bound may be omitted.
+type sliceNode struct{ target, from, to node }
+
+func (n sliceNode) eval(input any, vars map[string]any) (any, error) {
+	target, err := n.target.eval(input, vars)
+	if err != nil {
+		return nil, err
+	}
+	var length int
+	switch t := target.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		length = len([]rune(t))
+	case []any:
+		length = len(t)
+	default:
+		return nil, fmt.Errorf("cannot slice %s", typeName(target))
+	}
+	from, err := sliceBound(n.from, 0, length, input, vars)
+	if err != nil {
+		return nil, err
+	}
+	to, err := sliceBound(n.to, length, length, input, vars)
+	if err != nil {
+		return nil, err
+	}
+	if to < from {
+		to = from
+	}
+	if s, ok := target.(string); ok {
+		return string([]rune(s)[from:to]), nil
+	}
+	return target.([]any)[from:to], nil
+}
+
+// sliceBound evaluates a slice bound (def when omitted), counting negative
+// values from the end and clamping the result to [0, length].
+func sliceBound(b node, def, length int, input any, vars map[string]any) (int, error) {
+	if b == nil {
+		return def, nil
+	}
+	v, err := b.eval(input, vars)
+	if err != nil {
+		return 0, err
+	}
+	f, ok := v.(float64)
+	if !ok {
+		return 0, fmt.Errorf("slice bound must be a number, got %s", typeName(v))
+	}
+	i := int(math.Floor(f))
+	if i < 0 {
+		i += length
+	}
+	return min(max(i, 0), length), nil
+}
+
+// iterateNode is .[]: the values of an array or object, as a stream.
+type iterateNode struct{ target node }
+
+func (n iterateNode) eval(input any, vars map[string]any) (any, error) {
+	target, err := n.target.eval(input, vars)
+	if err != nil {
+		return nil, err
+	}
+	switch t := target.(type) {
+	case []any:
+		return stream(t), nil
+	case map[string]any:
+		values := make(stream, 0, len(t))
+		for _, k := range sortedKeys(t) {
+			values = append(values, t[k])
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("cannot iterate over %s", typeName(target))
+	}
+}
+
+// collectNode is [expr]: an array of every value the expression produces.
+type collectNode struct{ inner node }
+
+func (n collectNode) eval(input any, vars map[string]any) (any, error) {
+	value, err := n.inner.eval(input, vars)
+	if err != nil {
+		return nil, err
+	}
+	return appendValues([]any{}, value), nil
+}
+
+type ifNode struct{ cond, then, otherwise node }
+
+func (n ifNode) eval(input any, vars map[string]any) (any, error) {
+	cond, err := n.cond.eval(input, vars)
+	if err != nil {
+		return nil, err
+	}
+	if truthy(cond) {
+		return n.then.eval(input, vars)
+	}
+	return n.otherwise.eval(input, vars)
+}
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+func (n binaryNode) eval(input any, vars map[string]any) (any, error) {
+	left, err := n.left.eval(input, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	// Short-circuit boolean operators
+	switch n.op {
+	case "and":
+		if !truthy(left) {
+			return false, nil
+		}
+	case "or":
+		if truthy(left) {
+			return true, nil
+		}
+	}
+
+	right, err := n.right.eval(input, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "and", "or":
+		return truthy(right), nil
+	case "==":
+		return reflect.DeepEqual(left, right), nil
+	case "!=":
+		return !reflect.DeepEqual(left, right), nil
+	case "<", "<=", ">", ">=":
+		return compare(n.op, left, right)
+	case "+":
+		return add(left, right)
+	}
+
+	l, lok := left.(float64)
+	r, rok := right.(float64)
+	if !lok || !rok {
+		return nil, fmt.Errorf("%s (%v) and %s (%v) cannot be combined with %q",
+			typeName(left), left, typeName(right), right, n.op)
+	}
+	switch n.op {
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/":
+		if r == 0 {
+			return nil, errors.New("division by zero")
+		}
+		return l / r, nil
+	default: // %
+		if int(r) == 0 {
+			return nil, errors.New("modulo by zero")
+		}
+		return float64(int(l) % int(r)), nil
+	}
+}
+
+func add(left, right any) (any, error) {
+	if left == nil {
+		return right, nil
+	}
+	if right == nil {
+		return left, nil
+	}
+	switch l := left.(type) {
+	case float64:
+		if r, ok := right.(float64); ok {
+			return l + r, nil
+		}
+	case string:
+		if r, ok := right.(string); ok {
+			return l + r, nil
+		}
+	case []any:
+		if r, ok := right.([]any); ok {
+			return append(append([]any{}, l...), r...), nil
+		}
+	case map[string]any:
+		if r, ok := right.(map[string]any); ok {
+			merged := make(map[string]any, len(l)+len(r))
+			for k, v := range l {
+				merged[k] = v
+			}
+			for k, v := range r {
+				merged[k] = v
+			}
+			return merged, nil
+		}
+	}
+	return nil, fmt.Errorf("%s (%v) and %s (%v) cannot be added", typeName(left), left, typeName(right), right)
+}
+
+func compare(op string, left, right any) (any, error) {
+	var c int
+	switch l := left.(type) {
+	case float64:
+		r, ok := right.(float64)
+		if !ok {
+			return nil, fmt.Errorf("cannot compare %s with %s", typeName(left), typeName(right))
+		}
+		c = cmpFloat(l, r)
+	case string:
+		r, ok := right.(string)
+		if !ok {
+			return nil, fmt.Errorf("cannot compare %s with %s", typeName(left), typeName(right))
+		}
+		c = strings.Compare(l, r)
+	default:
+		return nil, fmt.Errorf("cannot compare %s with %s", typeName(left), typeName(right))
+	}
+	switch op {
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	default:
+		return c >= 0, nil
+	}
+}
+
+func cmpFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+type funcNode struct {
+	name string
+	args []node
+}
+
+// funcArity is the number of arguments each supported function takes.
+var funcArity = map[string]int{
+	"not":             0,
+	"tostring":        0,
+	"@text":           0,
+	"tojson":          0,
+	"@json":           0,
+	"tonumber":        0,
+	"round":           0,
+	"fromdateiso8601": 0,
+	"todateiso8601":   0,
+	"strftime":        1,
+	"length":          0,
+	"type":            0,
+	"keys":            0,
+	"ascii_downcase":  0,
+	"ascii_upcase":    0,
+	"contains":        1,
+	"@uri":            0,
+	"@base64":         0,
+	"@base64d":        0,
+	"map":             1,
+	"select":          1,
+	"to_entries":      0,
+	"split":           1,
+	"join":            1,
+	"sub":             2,
+}
+
+func (n funcNode) eval(input any, vars map[string]any) (any, error) {
+	arity, ok := funcArity[n.name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupported, n.name)
+	}
+	if len(n.args) != arity {
+		return nil, fmt.Errorf("%s takes %d argument(s), got %d", n.name, arity, len(n.args))
+	}
+
+	// Filters whose argument is applied to each value rather than evaluated once
+	switch n.name {
+	case "map":
+		items, ok := input.([]any)
+		if !ok {
+			return nil, fmt.Errorf("map cannot be applied to %s", typeName(input))
+		}
+		result := make([]any, 0, len(items))
+		for _, item := range items {
+			v, err := n.args[0].eval(item, vars)
+			if err != nil {
+				return nil, err
+			}
+			result = appendValues(result, v)
+		}
+		return result, nil
+	case "select":
+		cond, err := n.args[0].eval(input, vars)
+		if err != nil {
+			return nil, err
+		}
+		if truthy(cond) {
+			return input, nil
+		}
+		return stream(nil), nil
+	}
+
+	args := make([]any, len(n.args))
+	for i, a := range n.args {
+		v, err := a.eval(input, vars)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	var arg any
+	if arity > 0 {
+		arg = args[0]
+	}
+
+	switch n.name {
+	case "not":
+		return !truthy(input), nil
+	case "tostring", "@text":
+		if s, ok := input.(string); ok {
+			return s, nil
+		}
+		return toJSON(input)
+	case "tojson", "@json":
+		return toJSON(input)
+	case "tonumber":
+		switch v := input.(type) {
+		case float64:
+			return v, nil
+		case string:
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse %q as number", v)
+			}
+			return f, nil
+		}
+	case "round":
+		if f, ok := input.(float64); ok {
+			return math.Round(f), nil
+		}
+	case "fromdateiso8601":
+		if s, ok := input.(string); ok {
+			t, err := time.Parse(iso8601Layout, s)
+			if err != nil {
+				return nil, fmt.Errorf("date %q does not match format %q", s, "%Y-%m-%dT%H:%M:%SZ")
+			}
+			return float64(t.Unix()), nil
+		}
+	case "todateiso8601":
+		if f, ok := input.(float64); ok {
+			return unixTime(f).Format(iso8601Layout), nil
+		}
+	case "strftime":
+		if f, ok := input.(float64); ok {
+			format, ok := arg.(string)
+			if !ok {
+				return nil, fmt.Errorf("strftime requires a string format, got %s", typeName(arg))
+			}
+			return strftime(unixTime(f), format)
+		}
+	case "length":
+		switch v := input.(type) {
+		case nil:
+			return 0.0, nil
+		case string:
+			return float64(len([]rune(v))), nil
+		case []any:
+			return float64(len(v)), nil
+		case map[string]any:
+			return float64(len(v)), nil
+		case float64:
+			return math.Abs(v), nil
+		}
+	case "type":
+		return typeName(input), nil
+	case "keys":
+		if m, ok := input.(map[string]any); ok {
+			keys := make([]string, 0, len(m))
+			for k := range m {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			result := make([]any, len(keys))
+			for i, k := range keys {
+				result[i] = k
+			}
+			return result, nil
+		}
+	case "to_entries":
+		switch v := input.(type) {
+		case map[string]any:
+			entries := make([]any, 0, len(v))
+			for _, k := range sortedKeys(v) {
+				entries = append(entries, map[string]any{"key": k, "value": v[k]})
+			}
+			return entries, nil
+		case []any:
+			entries := make([]any, len(v))
+			for i, item := range v {
+				entries[i] = map[string]any{"key": float64(i), "value": item}
+			}
+			return entries, nil
+		}
+	case "split":
+		if s, ok := input.(string); ok {
+			sep, ok := arg.(string)
+			if !ok {
+				return nil, fmt.Errorf("split requires a string separator, got %s", typeName(arg))
+			}
+			parts := strings.Split(s, sep)
+			result := make([]any, len(parts))
+			for i, part := range parts {
+				result[i] = part
+			}
+			return result, nil
+		}
+	case "join":
+		if items, ok := input.([]any); ok {
+			sep, ok := arg.(string)
+			if !ok {
+				return nil, fmt.Errorf("join requires a string separator, got %s", typeName(arg))
+			}
+			parts := make([]string, len(items))
+			for i, item := range items {
+				switch v := item.(type) {
+				case nil:
+				case string:
+					parts[i] = v
+				case float64, bool:
+					text, err := toJSON(v)
+					if err != nil {
+						return nil, err
+					}
+					parts[i] = text.(string)
+				default:
+					return nil, fmt.Errorf("cannot join %s", typeName(item))
+				}
+			}
+			return strings.Join(parts, sep), nil
+		}
+	case "sub":
+		if s, ok := input.(string); ok {
+			pattern, ok := args[0].(string)
+			if !ok {
+				return nil, fmt.Errorf("sub requires a string regex, got %s", typeName(args[0]))
+			}
+			replacement, ok := args[1].(string)
+			if !ok {
+				return nil, fmt.Errorf("sub requires a string replacement, got %s", typeName(args[1]))
+			}
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regex %q: %v", pattern, err)
+			}
+			// Only the first match is replaced, like JQ's sub (gsub replaces all)
+			if loc := re.FindStringIndex(s); loc != nil {
+				return s[:loc[0]] + replacement + s[loc[1]:], nil
+			}
+			return s, nil
+		}
+	case "ascii_downcase", "ascii_upcase":
+		if s, ok := input.(string); ok {
+			if n.name == "ascii_downcase" {
+				return strings.ToLower(s), nil
+			}
+			return strings.ToUpper(s), nil
+		}
+	case "contains":
+		if s, ok := input.(string); ok {
+			if sub, ok := arg.(string); ok {
+				return strings.Contains(s, sub), nil
+			}
+		}
+		return reflect.DeepEqual(input, arg), nil
+	case "@uri":
+		s, err := toText(input)
+		if err != nil {
+			return nil, err
+		}
+		return uriEscape(s), nil
+	case "@base64":
+		s, err := toText(input)
+		if err != nil {
+			return nil, err
+		}
+		return base64.StdEncoding.EncodeToString([]byte(s)), nil
+	case "@base64d":
+		if s, ok := input.(string); ok {
+			decoded, err := base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				return nil, fmt.Errorf("invalid base64: %v", err)
+			}
+			return string(decoded), nil
+		}
+	}
+	return nil, fmt.Errorf("%s cannot be applied to %s", n.name, typeName(input))
+}
+
+// iso8601Layout is the only date format JQ's fromdateiso8601 and todateiso8601 accept.
+const iso8601Layout = "2006-01-02T15:04:05Z"
+
+// unixTime converts seconds since the Unix epoch to a UTC time.
+func unixTime(seconds float64) time.Time {
+	whole, frac := math.Modf(seconds)
+	return time.Unix(int64(whole), int64(frac*1e9)).UTC()
+}
+
+// strftimeDirectives maps the strftime conversions JQ supports to Go layouts.
+var strftimeDirectives = map[byte]string{
+	'Y': "2006",
+	'y': "06",
+	'm': "01",
+	'd': "02",
+	'e': "_2",
+	'H': "15",
+	'I': "03",
+	'M': "04",
+	'S': "05",
+	'p': "PM",
+	'b': "Jan",
+	'h': "Jan",
+	'B': "January",
+	'a': "Mon",
+	'A': "Monday",
+	'j': "002",
+	'Z': "UTC",
+	'z': "-0700",
+}
+
+// strftime formats t like JQ's strftime. Literal text is copied as is, so it
+// cannot be mistaken for a Go layout element.
+func strftime(t time.Time, format string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			b.WriteByte(format[i])
+			continue
+		}
+		i++
+		if i == len(format) {
+			return "", fmt.Errorf("strftime format %q ends with %%", format)
+		}
+		switch c := format[i]; c {
+		case '%':
+			b.WriteByte('%')
+		case 's':
+			b.WriteString(strconv.FormatInt(t.Unix(), 10))
+		default:
+			layout, ok := strftimeDirectives[c]
+			if !ok {
+				return "", fmt.Errorf("%w: strftime directive %%%c", ErrUnsupported, c)
+			}
+			b.WriteString(t.Format(layout))
+		}
+	}
+	return b.String(), nil
+}
+
+// uriEscape percent-encodes everything except unreserved characters, like JQ's @uri.
+func uriEscape(s string) string {
+	var b strings.Builder
+	for _, c := range []byte(s) {
+		if c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func toText(v any) (string, error) {
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	s, err := toJSON(v)
+	if err != nil {
+		return "", err
+	}
+	return s.(string), nil
+}
+
+func toJSON(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func truthy(v any) bool {
+	return v != nil && v != false
+}
+
+func typeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
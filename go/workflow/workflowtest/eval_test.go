@@ -0,0 +1,138 @@
+package workflowtest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestEvaluate verifies the JQ subset used by workflow expressions.
+func TestEvaluate(t *testing.T) {
+	input := map[string]any{
+		"status":  200.0,
+		"secrets": map[string]any{"TOKEN": "abc"},
+	}
+	vars := map[string]any{
+		"context": map[string]any{
+			"apiURL":  "https://api.example.com",
+			"query":   "a b&c",
+			"retries": 2.0,
+			"price":   2.5,
+			"rate":    0.5,
+			"start":   "2026-03-01T09:30:00Z",
+			"end":     "2026-03-01T10:00:00Z",
+			"branch":  "  Feature/Login-Page ",
+			"sha":     "3f9a1c2e8b7d",
+			"tags":    "api,web",
+			"isProd":  true,
+			"isDebug": false,
+			"user":    map[string]any{"name": "Ada"},
+			"fetch":   map[string]any{"items": []any{map[string]any{"id": "i-1"}}},
+			"issues": []any{
+				map[string]any{"title": "crash", "state": "open"},
+				map[string]any{"title": "typo", "state": "closed"},
+			},
+		},
+	}
+
+	tests := []struct {
+		expr string
+		want any
+	}{
+		{`${ $context.apiURL }`, "https://api.example.com"},
+		{`${ $context.apiURL + "/users" }`, "https://api.example.com/users"},
+		{`${ $context.user.name | ascii_upcase }`, "ADA"},
+		{`${ $context.fetch.items[0].id }`, "i-1"},
+		{`${ $context.missing.field }`, nil},
+		{`${ $context.retries + 1 }`, 3.0},
+		{`${ .status == 200 }`, true},
+		{`${ !(.status == 404) }`, true},
+		{`${ $context.isProd and ($context.isDebug | not) }`, true},
+		{`${.secrets.TOKEN}`, "abc"},
+		{`${ "Bearer " + .secrets.TOKEN }`, "Bearer abc"},
+		{`${ (($context.query) | @uri) }`, "a%20b%26c"},
+		{`${ "Basic " + (("u" + ":" + "p") | @base64) }`, "Basic dTpw"},
+		{`${ if ($context.apiURL | contains("?")) then "&" else "?" end }`, "?"},
+		{`${ $context.retries | tostring }`, "2"},
+		{`${ 'single' + ' ' + 'quotes' }`, "single quotes"},
+		{`${ ($context.issues | map(select(.state == "open"))) }`, []any{map[string]any{"title": "crash", "state": "open"}}},
+		{`${ ($context.issues | map(.title)) }`, []any{"crash", "typo"}},
+		{`${ (($context.issues | map(select(.state == "open"))) | length) }`, 1.0},
+		{`${ ($context.issues[1]) | select(.state == "closed") | .title }`, "typo"},
+		{`${ [($context.issues) | to_entries[] | select(.key % 2 == 1) | .value.title] }`, []any{"typo"}},
+		{`${ [$context.issues[].state] }`, []any{"open", "closed"}},
+		{`${ $context.user | to_entries }`, []any{map[string]any{"key": "name", "value": "Ada"}}},
+		{`${ [] }`, []any{}},
+		{`${ ($context.price | round) }`, 3.0},
+		{`${ ((($context.price * $context.rate) * 100 | round) / 100) }`, 1.25},
+		{`${ ($context.start | fromdateiso8601 + 7200 | todateiso8601) }`, "2026-03-01T11:30:00Z"},
+		{`${ ($context.start | fromdateiso8601 + -60 | todateiso8601) }`, "2026-03-01T09:29:00Z"},
+		{`${ (($context.start | fromdateiso8601) < ($context.end | fromdateiso8601)) }`, true},
+		{`${ ($context.end | fromdateiso8601) }`, 1772359200.0},
+		{`${ ($context.start | fromdateiso8601 | strftime("%Y-%m-%d %H:%M")) }`, "2026-03-01 09:30"},
+		{`${ ($context.start | fromdateiso8601 | strftime("Mon %a, %d %b")) }`, "Mon Sun, 01 Mar"},
+		{`${ ($context.tags | split(",")) }`, []any{"api", "web"}},
+		{`${ ($context.sha[0:7]) }`, "3f9a1c2"},
+		{`${ ($context.sha[-5:-1]) }`, "e8b7"},
+		{`${ ($context.sha[8:]) }`, "8b7d"},
+		{`${ ($context.issues[:1] | map(.title)) }`, []any{"crash"}},
+		{`${ ($context.branch | sub("^\\s+"; "") | sub("\\s+$"; "")) }`, "Feature/Login-Page"},
+		{`${ ((($context.branch | sub("^\\s+"; "") | sub("\\s+$"; "")) | split("/") | join("-")) | ascii_downcase) }`, "feature-login-page"},
+		{`${ ($context.tags | split(",") | join(" ")) }`, "api web"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := evaluate(tt.expr, input, vars)
+			if err != nil {
+				t.Fatalf("evaluate() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("evaluate() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestEvaluate_Errors verifies malformed and ill-typed expressions are reported.
+func TestEvaluate_Errors(t *testing.T) {
+	vars := map[string]any{"context": map[string]any{"name": "Ada", "count": 1.0}}
+
+	for _, expr := range []string{
+		`${ $context.name + 1 }`,
+		`${ $context.count.value }`,
+		`${ $undefined }`,
+		`${ "unterminated }`,
+		`${ ($context.count }`,
+		`${ $context.name | frobnicate }`,
+	} {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := evaluate(expr, nil, vars); !errors.Is(err, ErrEvaluation) {
+				t.Errorf("evaluate() error = %v, want ErrEvaluation", err)
+			}
+		})
+	}
+}
+
+// TestEvaluate_Unsupported verifies unknown filters and wrong argument counts are told apart.
+func TestEvaluate_Unsupported(t *testing.T) {
+	vars := map[string]any{"context": map[string]any{"name": "Ada"}}
+
+	_, err := evaluate(`${ $context.name | frobnicate }`, nil, vars)
+	if !errors.Is(err, ErrEvaluation) || !errors.Is(err, ErrUnsupported) {
+		t.Errorf("evaluate() error = %v, want ErrEvaluation and ErrUnsupported", err)
+	}
+
+	for _, expr := range []string{
+		`${ $context.name | contains }`,
+		`${ $context.name | not("x") }`,
+		`${ $context.name | contains("A"; "B") }`,
+	} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := evaluate(expr, nil, vars)
+			if !errors.Is(err, ErrEvaluation) || errors.Is(err, ErrUnsupported) {
+				t.Errorf("evaluate() error = %v, want an argument count error", err)
+			}
+		})
+	}
+}
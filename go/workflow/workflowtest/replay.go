@@ -0,0 +1,299 @@
+// Package workflowtest provides helpers for testing workflows against data
+// recorded from real executions.
+//
+// A replay fixture captures what each task received and returned in
+// production. Replay() re-evaluates the workflow's expressions against that
+// data, so a change to an expression (or to the shape of an upstream response)
+// that would break a production run fails a unit test instead:
+//
+//	func TestOrderPipeline_Replay(t *testing.T) {
+//	    fixture, err := workflowtest.LoadFixture("testdata/order-1234.json")
+//	    if err != nil {
+//	        t.Fatal(err)
+//	    }
+//	    wf := buildOrderPipeline(stigmer.NewContext())
+//	    if _, err := workflowtest.Replay(wf, fixture); err != nil {
+//	        t.Fatal(err)
+//	    }
+//	}
+package workflowtest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+// ErrReplayMismatch is returned (wrapped) when an evaluated task input differs
+// from the input recorded in the fixture.
+var ErrReplayMismatch = errors.New("replay mismatch")
+
+// Fixture is a recording of a single workflow execution.
+//
+// Fixture JSON format:
+//
+//	{
+//	  "workflow": "order-pipeline",
+//	  "input":   {"orderId": "o-1234", "secrets": {"API_TOKEN": "redacted"}},
+//	  "context": {"apiURL": "https://api.example.com"},
+//	  "tasks": [
+//	    {
+//	      "name":   "fetchOrder",
+//	      "input":  {"uri": "https://api.example.com/orders/o-1234"},
+//	      "output": {"id": "o-1234", "total": 42.5}
+//	    }
+//	  ]
+//	}
+type Fixture struct {
+	// Workflow is the name of the recorded workflow (informational)
+	Workflow string `json:"workflow,omitempty"`
+
+	// Input is the workflow input, available to expressions as "."
+	Input map[string]any `json:"input,omitempty"`
+
+	// Context holds context variables, available to expressions as $context
+	Context map[string]any `json:"context,omitempty"`
+
+	// Tasks are the recorded task executions
+	Tasks []TaskRecord `json:"tasks"`
+}
+
+// TaskRecord is the recorded input and output of one task execution.
+type TaskRecord struct {
+	// Name is the task name
+	Name string `json:"name"`
+
+	// Input is the evaluated task configuration recorded at execution time,
+	// keyed like the task's JSON config (e.g., "uri", "headers", "body").
+	// Only the keys present are compared during replay.
+	Input map[string]any `json:"input,omitempty"`
+
+	// Output is the task result, exposed to later tasks as $context.<name>
+	Output any `json:"output,omitempty"`
+
+	// Vars are extra variables in scope for the task (e.g., loop variables)
+	Vars map[string]any `json:"vars,omitempty"`
+}
+
+// LoadFixture reads a replay fixture from a JSON file.
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture %s: %w", path, err)
+	}
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+	return &fixture, nil
+}
+
+// Result holds the evaluated configuration of every replayed task.
+type Result struct {
+	Tasks []TaskResult
+}
+
+// TaskResult is the task configuration with all expressions evaluated.
+type TaskResult struct {
+	Name   string
+	Config map[string]any
+}
+
+// Task returns the result for the named task.
+func (r *Result) Task(name string) (TaskResult, bool) {
+	for _, t := range r.Tasks {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return TaskResult{}, false
+}
+
+// Replay re-evaluates the workflow's expressions against a recorded execution.
+//
+// Tasks are replayed in workflow order (including nested tasks). Each task sees
+// the fixture context plus the outputs of the tasks recorded before it. For
+// tasks with a recorded input, the evaluated configuration is compared against
+// it. Tasks nested inside another task's config are evaluated on their own.
+//
+// All evaluation errors (wrapping ErrEvaluation) and mismatches (wrapping
+// ErrReplayMismatch) are returned together. Expressions that use JQ filters
+// the evaluator does not implement are reported with ErrUnsupported, so they
+// can be told apart from regressions.
+func Replay(wf *workflow.Workflow, fixture *Fixture) (*Result, error) {
+	records := make(map[string]TaskRecord, len(fixture.Tasks))
+	for _, rec := range fixture.Tasks {
+		records[rec.Name] = rec
+	}
+
+	contextVars, err := normalize(fixture.Context)
+	if err != nil {
+		return nil, fmt.Errorf("fixture context: %w", err)
+	}
+	contextMap, _ := contextVars.(map[string]any)
+	if contextMap == nil {
+		contextMap = make(map[string]any)
+	}
+	input, err := normalize(fixture.Input)
+	if err != nil {
+		return nil, fmt.Errorf("fixture input: %w", err)
+	}
+
+	result := &Result{}
+	var errs []error
+	for task := range wf.AllTasks() {
+		rec := records[task.Name]
+
+		vars := map[string]any{"context": contextMap}
+		for k, v := range rec.Vars {
+			vars[k], _ = normalize(v)
+		}
+
+		config, err := taskConfig(task)
+		if err != nil {
+			return nil, err
+		}
+		evaluated, taskErrs := evaluateValue(task.Name, "config", config, input, vars)
+		errs = append(errs, taskErrs...)
+
+		evaluatedConfig, _ := evaluated.(map[string]any)
+		result.Tasks = append(result.Tasks, TaskResult{Name: task.Name, Config: evaluatedConfig})
+
+		if rec.Input != nil {
+			errs = append(errs, compareInput(task.Name, rec.Input, evaluatedConfig)...)
+		}
+		if rec.Output != nil {
+			output, err := normalize(rec.Output)
+			if err != nil {
+				return nil, fmt.Errorf("fixture output for %q: %w", task.Name, err)
+			}
+			contextMap[task.Name] = output
+		}
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// taskConfig returns the task config in its JSON form.
+func taskConfig(task *workflow.Task) (map[string]any, error) {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode task %q: %w", task.Name, err)
+	}
+	var decoded struct {
+		Config map[string]any `json:"config"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode task %q: %w", task.Name, err)
+	}
+	return decoded.Config, nil
+}
+
+// evaluateValue evaluates every ${...} string in a config value.
+// Nested task definitions are left untouched; they are replayed separately.
+func evaluateValue(taskName, path string, value, input any, vars map[string]any) (any, []error) {
+	switch v := value.(type) {
+	case string:
+		if !isExpression(v) {
+			return v, nil
+		}
+		result, err := evaluate(v, input, vars)
+		if err != nil {
+			return nil, []error{fmt.Errorf("task %q: %s: %w", taskName, path, err)}
+		}
+		return result, nil
+	case map[string]any:
+		if isTaskJSON(v) {
+			return v, nil
+		}
+		var errs []error
+		out := make(map[string]any, len(v))
+		for _, k := range sortedKeys(v) {
+			var keyErrs []error
+			out[k], keyErrs = evaluateValue(taskName, path+"."+k, v[k], input, vars)
+			errs = append(errs, keyErrs...)
+		}
+		return out, errs
+	case []any:
+		var errs []error
+		out := make([]any, len(v))
+		for i, item := range v {
+			var itemErrs []error
+			out[i], itemErrs = evaluateValue(taskName, fmt.Sprintf("%s[%d]", path, i), item, input, vars)
+			errs = append(errs, itemErrs...)
+		}
+		return out, errs
+	default:
+		return v, nil
+	}
+}
+
+// compareInput compares the recorded input keys against the evaluated config.
+func compareInput(taskName string, recorded, evaluated map[string]any) []error {
+	var errs []error
+	for _, key := range sortedKeys(recorded) {
+		want, err := normalize(recorded[key])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("task %q: recorded input %q: %w", taskName, key, err))
+			continue
+		}
+		got := evaluated[key]
+		if !reflect.DeepEqual(got, want) {
+			errs = append(errs, fmt.Errorf("%w: task %q: %s = %s, recorded %s",
+				ErrReplayMismatch, taskName, key, jsonString(got), jsonString(want)))
+		}
+	}
+	return errs
+}
+
+// normalize round-trips a value through JSON so numbers are float64 and
+// objects are map[string]any, matching evaluated values.
+func normalize(v any) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// isTaskJSON reports whether an object is a serialized nested task.
+func isTaskJSON(m map[string]any) bool {
+	_, hasName := m["name"]
+	_, hasKind := m["kind"]
+	return hasName && hasKind
+}
+
+func isExpression(s string) bool {
+	s = strings.TrimSpace(s)
+	return strings.HasPrefix(s, "${") && strings.HasSuffix(s, "}")
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func jsonString(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
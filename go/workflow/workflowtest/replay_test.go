@@ -0,0 +1,129 @@
+package workflowtest
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/leftbin/stigmer-sdk/go/stigmer"
+	"github.com/leftbin/stigmer-sdk/go/workflow"
+)
+
+// newOrderPipeline builds the workflow recorded in testdata/order-pipeline.json.
+func newOrderPipeline(t *testing.T, emailField string) *workflow.Workflow {
+	t.Helper()
+	ctx := stigmer.NewContext()
+	apiURL := ctx.SetString("apiURL", "https://api.example.com")
+
+	wf, err := workflow.New(ctx,
+		workflow.WithNamespace("orders"),
+		workflow.WithName("order-pipeline"),
+	)
+	if err != nil {
+		t.Fatalf("workflow.New() error = %v", err)
+	}
+
+	fetch := workflow.HttpCallTask("fetchOrder",
+		workflow.WithHTTPGet(),
+		workflow.WithURI("${ $context.apiURL + \"/orders\" }"),
+		workflow.WithBearerToken(workflow.RuntimeSecret("API_TOKEN")),
+	)
+	notify := workflow.HttpCallTask("notify",
+		workflow.WithHTTPPost(),
+		workflow.WithURI(apiURL.Concat("/notify")),
+		workflow.WithBody(map[string]any{
+			"to":    "${ $context.fetchOrder.customer." + emailField + " }",
+			"order": fetch.Field("id"),
+		}),
+	)
+	wf.AddTasks(fetch, notify)
+	return wf
+}
+
+// TestReplay verifies a workflow whose expressions match the recording replays cleanly.
+func TestReplay(t *testing.T) {
+	fixture, err := LoadFixture("testdata/order-pipeline.json")
+	if err != nil {
+		t.Fatalf("LoadFixture() error = %v", err)
+	}
+
+	result, err := Replay(newOrderPipeline(t, "email"), fixture)
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+
+	notify, ok := result.Task("notify")
+	if !ok {
+		t.Fatal("Result.Task(notify) not found")
+	}
+	body := notify.Config["body"].(map[string]any)
+	if body["order"] != "o-1234" {
+		t.Errorf("notify body.order = %v, want o-1234", body["order"])
+	}
+}
+
+// TestReplay_Regression verifies an expression change is caught against recorded data.
+func TestReplay_Regression(t *testing.T) {
+	fixture, err := LoadFixture("testdata/order-pipeline.json")
+	if err != nil {
+		t.Fatalf("LoadFixture() error = %v", err)
+	}
+
+	// "mail" does not exist in the recorded fetchOrder output
+	_, err = Replay(newOrderPipeline(t, "mail"), fixture)
+	if !errors.Is(err, ErrReplayMismatch) {
+		t.Fatalf("Replay() error = %v, want ErrReplayMismatch", err)
+	}
+	if !strings.Contains(err.Error(), `task "notify": body`) {
+		t.Errorf("Replay() error = %v, want mismatch on notify body", err)
+	}
+}
+
+// TestReplay_EvaluationError verifies ill-typed expressions are reported per task.
+func TestReplay_EvaluationError(t *testing.T) {
+	wf, err := workflow.New(stigmer.NewContext(),
+		workflow.WithNamespace("orders"),
+		workflow.WithName("broken"),
+	)
+	if err != nil {
+		t.Fatalf("workflow.New() error = %v", err)
+	}
+	wf.AddTask(workflow.SetTask("total", workflow.SetVar("sum", "${ $context.order.total + \"USD\" }")))
+
+	fixture := &Fixture{Context: map[string]any{"order": map[string]any{"total": 42.5}}}
+	if _, err := Replay(wf, fixture); !errors.Is(err, ErrEvaluation) {
+		t.Errorf("Replay() error = %v, want ErrEvaluation", err)
+	}
+}
+
+// TestReplay_FanOut verifies the stride expressions generated by FanOutAgentCalls evaluate.
+func TestReplay_FanOut(t *testing.T) {
+	wf, err := workflow.New(stigmer.NewContext(),
+		workflow.WithNamespace("support"),
+		workflow.WithName("triage-all"),
+	)
+	if err != nil {
+		t.Fatalf("workflow.New() error = %v", err)
+	}
+	wf.FanOutAgentCalls("triage", workflow.AgentBySlug("triager"),
+		workflow.WithItems("${ $context.tickets }"),
+		workflow.WithConcurrency(2),
+		workflow.WithAgentCall(workflow.Message(workflow.FanOutItem())),
+	)
+
+	fixture := &Fixture{Context: map[string]any{"tickets": []any{"t-1", "t-2", "t-3"}}}
+	result, err := Replay(wf, fixture)
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	for name, want := range map[string][]any{"triage-0": {"t-1", "t-3"}, "triage-1": {"t-2"}} {
+		branch, ok := result.Task(name)
+		if !ok {
+			t.Fatalf("Result.Task(%s) not found", name)
+		}
+		if got := branch.Config["in"]; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s in = %v, want %v", name, got, want)
+		}
+	}
+}
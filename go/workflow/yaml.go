@@ -0,0 +1,503 @@
+package workflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// This file renders workflows as CNCF Serverless Workflow DSL 1.0 YAML, so
+// definitions can be reviewed by humans and consumed by other tooling. The
+// proto manifest remains the deployment format.
+
+// MarshalYAML renders the workflow as Serverless Workflow DSL 1.0 YAML.
+//
+// Tasks map to their DSL counterparts (call: http, call: grpc, set, switch,
+// for, fork, try/catch, listen, wait, raise, run, emit); other task kinds are
+// written as a call to the lower-cased kind with the task config under "with".
+// Functions registered with DefineFunction are written once under
+// use.functions and called by name. Durations are converted to ISO 8601 (e.g., "1h30m" becomes "PT1H30M").
+//
+// Settings the DSL cannot express (transport options such as TLS, proxies and
+// retries, listen timeout jumps, and all but the first trigger) are left out.
+//
+// Example:
+//
+//	data, err := workflow.MarshalYAML(wf)
+//	if err != nil {
+//	    return err
+//	}
+//	os.WriteFile("order-pipeline.sw.yaml", data, 0o644)
+func MarshalYAML(w *Workflow) ([]byte, error) {
+	doc := swWorkflow{
+		Document: swDocument{
+			DSL:       w.Document.DSL,
+			Namespace: w.Document.Namespace,
+			Name:      w.Document.Name,
+			Version:   w.Document.Version,
+			Summary:   w.Description,
+		},
+		Input:    swInputSchema(w.Inputs),
+		Schedule: swScheduleFor(w.Triggers),
+		Timeout:  swTimeoutFor(w.Timeout),
+	}
+	if doc.Document.DSL == "" {
+		doc.Document.DSL = dslVersion
+	}
+	if doc.Document.Summary == "" {
+		doc.Document.Summary = w.Document.Description
+	}
+
+	resolved, err := ResolveExpressions(w.Tasks)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := swTaskList(resolved)
+	if err != nil {
+		return nil, err
+	}
+	doc.Do = tasks
+
+	functions, err := swFunctions(usedFunctions(resolved))
+	if err != nil {
+		return nil, err
+	}
+	if len(functions) > 0 {
+		doc.Use = map[string]any{"functions": functions}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("%w: encoding workflow YAML: %v", ErrConversion, err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("%w: encoding workflow YAML: %v", ErrConversion, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// swWorkflow is the top-level Serverless Workflow document.
+type swWorkflow struct {
+	Document swDocument     `yaml:"document"`
+	Input    map[string]any `yaml:"input,omitempty"`
+	Schedule map[string]any `yaml:"schedule,omitempty"`
+	Timeout  map[string]any `yaml:"timeout,omitempty"`
+	Use      map[string]any `yaml:"use,omitempty"`
+	Do       []swNamedTask  `yaml:"do"`
+}
+
+type swDocument struct {
+	DSL       string `yaml:"dsl"`
+	Namespace string `yaml:"namespace"`
+	Name      string `yaml:"name"`
+	Version   string `yaml:"version"`
+	Summary   string `yaml:"summary,omitempty"`
+}
+
+// swNamedTask is a single-entry mapping from task name to task definition,
+// the shape of every item in a DSL task list.
+type swNamedTask map[string]*swTask
+
+// swTask holds every DSL task property; only those of the task's kind are set.
+// Field order matches the order properties are written.
+type swTask struct {
+	Call    string         `yaml:"call,omitempty"`
+	With    map[string]any `yaml:"with,omitempty"`
+	Set     map[string]any `yaml:"set,omitempty"`
+	Switch  []swNamedCase  `yaml:"switch,omitempty"`
+	For     map[string]any `yaml:"for,omitempty"`
+	Fork    map[string]any `yaml:"fork,omitempty"`
+	Try     []swNamedTask  `yaml:"try,omitempty"`
+	Catch   map[string]any `yaml:"catch,omitempty"`
+	Listen  map[string]any `yaml:"listen,omitempty"`
+	Wait    string         `yaml:"wait,omitempty"`
+	Raise   map[string]any `yaml:"raise,omitempty"`
+	Run     map[string]any `yaml:"run,omitempty"`
+	Emit    map[string]any `yaml:"emit,omitempty"`
+	Do      []swNamedTask  `yaml:"do,omitempty"`
+	Timeout map[string]any `yaml:"timeout,omitempty"`
+	Export  map[string]any `yaml:"export,omitempty"`
+	Then    string         `yaml:"then,omitempty"`
+}
+
+// swNamedCase is a single-entry mapping from case name to switch case.
+type swNamedCase map[string]swCase
+
+type swCase struct {
+	When string `yaml:"when,omitempty"`
+	Then string `yaml:"then"`
+}
+
+// swTaskList converts a task list, in declaration order.
+func swTaskList(tasks []*Task) ([]swNamedTask, error) {
+	list := make([]swNamedTask, 0, len(tasks))
+	for _, task := range tasks {
+		t, err := swTaskFor(task)
+		if err != nil {
+			return nil, fmt.Errorf("task %s: %w", task.Name, err)
+		}
+		list = append(list, swNamedTask{task.Name: t})
+	}
+	return list, nil
+}
+
+// swNestedList converts a nested task list stored by value.
+func swNestedList(tasks []Task) ([]swNamedTask, error) {
+	list := make([]*Task, len(tasks))
+	for i := range tasks {
+		list[i] = &tasks[i]
+	}
+	return swTaskList(list)
+}
+
+// swTaskFor converts a single task.
+func swTaskFor(task *Task) (*swTask, error) {
+	t := &swTask{
+		Then:    task.ThenTask,
+		Timeout: swTimeoutFor(task.Deadline),
+	}
+	if task.ExportAs != "" {
+		t.Export = map[string]any{"as": task.ExportAs}
+	}
+
+	switch cfg := task.Config.(type) {
+	case *SetTaskConfig:
+		t.Set = make(map[string]any, len(cfg.Variables))
+		for k, v := range cfg.Variables {
+			t.Set[k] = v
+		}
+
+	case *HttpCallTaskConfig:
+		method := strings.ToLower(cfg.Method)
+		if method == "" {
+			method = "get"
+		}
+		t.Call = "http"
+		t.With = map[string]any{
+			"method":   method,
+			"endpoint": cfg.URI,
+		}
+		if len(cfg.Headers) > 0 {
+			t.With["headers"] = cfg.Headers
+		}
+		if len(cfg.QueryParams) > 0 {
+			t.With["query"] = cfg.QueryParams
+		}
+		if len(cfg.Body) > 0 {
+			t.With["body"] = cfg.Body
+		}
+
+	case *GrpcCallTaskConfig:
+		t.Call = "grpc"
+		service := map[string]any{"name": cfg.Service}
+		if host, port, err := net.SplitHostPort(cfg.Endpoint); err == nil {
+			service["host"] = host
+			service["port"] = port
+		} else if cfg.Endpoint != "" {
+			service["host"] = cfg.Endpoint
+		}
+		t.With = map[string]any{
+			"proto":   map[string]any{"endpoint": cfg.Endpoint},
+			"service": service,
+			"method":  cfg.Method,
+		}
+		if len(cfg.Body) > 0 {
+			t.With["arguments"] = cfg.Body
+		}
+
+	case *SwitchTaskConfig:
+		for i, c := range cfg.Cases {
+			t.Switch = append(t.Switch, swNamedCase{
+				fmt.Sprintf("case%d", i+1): {When: c.Condition, Then: c.Then},
+			})
+		}
+		if cfg.DefaultTask != "" {
+			t.Switch = append(t.Switch, swNamedCase{"default": {Then: cfg.DefaultTask}})
+		}
+
+	case *ForTaskConfig:
+		do, err := swNestedList(cfg.Do)
+		if err != nil {
+			return nil, err
+		}
+		t.For = map[string]any{"each": "item", "in": cfg.In}
+		t.Do = do
+
+	case *ForkTaskConfig:
+		branches := make([]map[string]any, 0, len(cfg.Branches))
+		for i, branch := range cfg.Branches {
+			do, err := swNestedList(branch.Tasks)
+			if err != nil {
+				return nil, err
+			}
+			name := branch.Name
+			if name == "" {
+				name = fmt.Sprintf("branch%d", i+1)
+			}
+			branches = append(branches, map[string]any{name: map[string]any{"do": do}})
+		}
+		t.Fork = map[string]any{"branches": branches}
+		if cfg.Compete {
+			t.Fork["compete"] = true
+		}
+
+	case *TryTaskConfig:
+		return swTryFor(t, cfg)
+
+	case *ListenTaskConfig:
+		filter := map[string]any{"with": map[string]any{"type": cfg.Event}}
+		if len(cfg.Correlation) > 0 {
+			correlate := make(map[string]any, len(cfg.Correlation))
+			for attr, value := range cfg.Correlation {
+				correlate[attr] = map[string]any{"from": "${ ." + attr + " }", "expect": value}
+			}
+			filter["correlate"] = correlate
+		}
+		t.Listen = map[string]any{"to": map[string]any{"one": filter}}
+		if cfg.Timeout != "" {
+			t.Timeout = swTimeoutFor(cfg.Timeout)
+		}
+
+	case *WaitTaskConfig:
+		t.Wait = isoDuration(cfg.Duration)
+
+	case *RaiseTaskConfig:
+		raised := map[string]any{"type": cfg.Error}
+		if cfg.Message != "" {
+			raised["detail"] = cfg.Message
+		}
+		t.Raise = map[string]any{"error": raised}
+
+	case *RunTaskConfig:
+		sub := map[string]any{"name": cfg.WorkflowName}
+		if cfg.WorkflowNamespace != "" {
+			sub["namespace"] = cfg.WorkflowNamespace
+		}
+		if cfg.WorkflowVersion != "" {
+			sub["version"] = cfg.WorkflowVersion
+		}
+		if len(cfg.Input) > 0 {
+			sub["input"] = cfg.Input
+		}
+		t.Run = map[string]any{"workflow": sub}
+
+	case *FunctionCallTaskConfig:
+		t.Call = cfg.Function
+
+	case *EmitTaskConfig:
+		event := map[string]any{"type": cfg.Event}
+		if len(cfg.Data) > 0 {
+			event["data"] = cfg.Data
+		}
+		t.Emit = map[string]any{"event": map[string]any{"with": event}}
+
+	default:
+		// No DSL equivalent: call the kind as a custom function
+		with, err := configToMap(task.Config)
+		if err != nil {
+			return nil, err
+		}
+		t.Call = strings.ToLower(string(task.Kind))
+		t.With = with
+	}
+	return t, nil
+}
+
+// swFunctions converts the functions the workflow calls to the DSL
+// use.functions section, where each is written once.
+func swFunctions(names []string) (map[string]*swTask, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	functions := make(map[string]*swTask, len(names))
+	for _, name := range names {
+		lowered, err := lowerFunctionCallTask(FunctionCallTask(name, name))
+		if err != nil {
+			return nil, err
+		}
+		t, err := swTaskFor(lowered)
+		if err != nil {
+			return nil, err
+		}
+		functions[name] = &swTask{Call: t.Call, With: t.With}
+	}
+	return functions, nil
+}
+
+// swTryFor converts a TRY task. The DSL allows a single catch per try, so
+// additional catch blocks wrap the task in further try blocks, the first
+// catch being the innermost.
+func swTryFor(t *swTask, cfg *TryTaskConfig) (*swTask, error) {
+	tasks, err := swNestedList(cfg.Tasks)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.Catch) == 0 {
+		t.Try = tasks
+		return t, nil
+	}
+
+	for i, catch := range cfg.Catch {
+		do, err := swNestedList(catch.Tasks)
+		if err != nil {
+			return nil, err
+		}
+		c := map[string]any{}
+		switch len(catch.Errors) {
+		case 0:
+		case 1:
+			c["errors"] = map[string]any{"with": map[string]any{"type": catch.Errors[0]}}
+		default:
+			as := catch.As
+			if as == "" {
+				as = "error"
+			}
+			conditions := make([]string, len(catch.Errors))
+			for j, e := range catch.Errors {
+				conditions[j] = fmt.Sprintf("$%s.type == %q", as, e)
+			}
+			c["when"] = "${ " + strings.Join(conditions, " or ") + " }"
+		}
+		if catch.As != "" {
+			c["as"] = catch.As
+		}
+		if len(do) > 0 {
+			c["do"] = do
+		}
+
+		if i > 0 {
+			tasks = []swNamedTask{{fmt.Sprintf("try%d", i): {Try: tasks, Catch: t.Catch}}}
+		}
+		t.Catch = c
+	}
+	t.Try = tasks
+	return t, nil
+}
+
+// swInputSchema declares workflow inputs as a JSON schema.
+func swInputSchema(inputs []InputParam) map[string]any {
+	if len(inputs) == 0 {
+		return nil
+	}
+	properties := make(map[string]any, len(inputs))
+	var required []string
+	for _, in := range inputs {
+		prop := map[string]any{}
+		if in.Type != "" && in.Type != ParamTypeAny {
+			prop["type"] = string(in.Type)
+		}
+		if in.Description != "" {
+			prop["description"] = in.Description
+		}
+		properties[in.Name] = prop
+		if in.Required {
+			required = append(required, in.Name)
+		}
+	}
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return map[string]any{"schema": map[string]any{"format": "json", "document": schema}}
+}
+
+// swScheduleFor converts the first trigger; the DSL has a single schedule.
+func swScheduleFor(triggers []Trigger) map[string]any {
+	if len(triggers) == 0 {
+		return nil
+	}
+	switch t := triggers[0]; t.Kind {
+	case TriggerKindCron:
+		return map[string]any{"cron": t.Cron}
+	case TriggerKindInterval:
+		return map[string]any{"every": isoDuration(t.Interval)}
+	case TriggerKindEvent:
+		return map[string]any{"on": map[string]any{"one": map[string]any{"with": map[string]any{"type": t.Event}}}}
+	}
+	return nil
+}
+
+// swTimeoutFor converts a workflow timeout or task deadline.
+func swTimeoutFor(duration string) map[string]any {
+	if duration == "" {
+		return nil
+	}
+	return map[string]any{"after": isoDuration(duration)}
+}
+
+// durationPartRegex matches one component of a duration like "1h30m".
+var durationPartRegex = regexp.MustCompile(`(\d+)(ms|s|m|h|d)`)
+
+// isoDuration converts durations produced by Seconds(), Minutes(), Hours()
+// and Days() (including compound forms) to ISO 8601. Expressions and other
+// values are returned unchanged.
+func isoDuration(d string) string {
+	if !deadlineRegex.MatchString(d) {
+		return d
+	}
+	var days, hours, minutes, millis int
+	for _, part := range durationPartRegex.FindAllStringSubmatch(d, -1) {
+		value, _ := strconv.Atoi(part[1])
+		switch part[2] {
+		case "d":
+			days += value
+		case "h":
+			hours += value
+		case "m":
+			minutes += value
+		case "s":
+			millis += value * 1000
+		case "ms":
+			millis += value
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("P")
+	if days > 0 {
+		fmt.Fprintf(&b, "%dD", days)
+	}
+	if hours == 0 && minutes == 0 && millis == 0 {
+		if days == 0 {
+			return "PT0S"
+		}
+		return b.String()
+	}
+	b.WriteString("T")
+	if hours > 0 {
+		fmt.Fprintf(&b, "%dH", hours)
+	}
+	if minutes > 0 {
+		fmt.Fprintf(&b, "%dM", minutes)
+	}
+	if millis%1000 != 0 {
+		fmt.Fprintf(&b, "%.3fS", float64(millis)/1000)
+	} else if millis > 0 {
+		fmt.Fprintf(&b, "%dS", millis/1000)
+	}
+	return b.String()
+}
+
+// configToMap converts a task config to a generic map via its JSON form.
+func configToMap(cfg TaskConfig) (map[string]any, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%w: encoding task config: %v", ErrConversion, err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("%w: decoding task config: %v", ErrConversion, err)
+	}
+	return m, nil
+}
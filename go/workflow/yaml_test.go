@@ -0,0 +1,119 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestMarshalYAML verifies tasks, switch cases, fork branches and catch blocks are written in DSL form.
+func TestMarshalYAML(t *testing.T) {
+	w := graphTestWorkflow()
+	w.Document.Namespace = "shop"
+	w.Document.Version = "1.0.0"
+	w.Timeout = "1h30m"
+	w.Triggers = []Trigger{Cron("0 2 * * *")}
+
+	data, err := MarshalYAML(w)
+	if err != nil {
+		t.Fatalf("MarshalYAML() error = %v", err)
+	}
+
+	want := `document:
+  dsl: 1.0.0
+  namespace: shop
+  name: orders
+  version: 1.0.0
+schedule:
+  cron: 0 2 * * *
+timeout:
+  after: PT1H30M
+do:
+  - fetch:
+      call: http
+      with:
+        endpoint: https://api.example.com/orders
+        method: get
+  - route:
+      switch:
+        - case1:
+            when: ${ .status == "ok" }
+            then: process
+        - default:
+            then: fail
+  - process:
+      fork:
+        branches:
+          - billing:
+              do:
+                - bill:
+                    set:
+                      billed: "true"
+          - shipping:
+              do:
+                - ship:
+                    set:
+                      shipped: "true"
+      then: end
+  - fail:
+      try:
+        - notify:
+            call: http
+            with:
+              endpoint: https://hooks.example.com
+              method: get
+      catch:
+        as: err
+        do:
+          - log:
+              set:
+                logged: "true"
+        errors:
+          with:
+            type: NetworkError
+`
+	if string(data) != want {
+		t.Errorf("MarshalYAML() =\n%s\nwant:\n%s", data, want)
+	}
+}
+
+// TestMarshalYAML_MultipleCatch verifies extra catch blocks wrap the task in nested try blocks.
+func TestMarshalYAML_MultipleCatch(t *testing.T) {
+	w := &Workflow{Document: Document{Name: "orders"}}
+	w.AddTask(TryTask("charge",
+		WithTry(HttpCallTask("pay", WithHTTPPost(), WithURI("https://pay.example.com"))),
+		WithCatch([]string{"CardDeclined"}, "", RaiseTask("declined", WithError("PaymentFailed"))),
+		WithCatch([]string{"Timeout", "Unavailable"}, "e", SetTask("retryLater", SetVar("retry", "true"))),
+	))
+
+	data, err := MarshalYAML(w)
+	if err != nil {
+		t.Fatalf("MarshalYAML() error = %v", err)
+	}
+	got := string(data)
+	for _, want := range []string{
+		"      try:\n        - try1:\n            try:\n              - pay:",
+		"            catch:\n              do:\n                - declined:",
+		`when: ${ $e.type == "Timeout" or $e.type == "Unavailable" }`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("MarshalYAML() missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+// TestIsoDuration verifies SDK durations are converted to ISO 8601.
+func TestIsoDuration(t *testing.T) {
+	tests := map[string]string{
+		"30s":                   "PT30S",
+		"1h30m":                 "PT1H30M",
+		"2d":                    "P2D",
+		"1d12h":                 "P1DT12H",
+		"1s500ms":               "PT1.500S",
+		"${ $context.timeout }": "${ $context.timeout }",
+	}
+	for in, want := range tests {
+		if got := isoDuration(in); got != want {
+			t.Errorf("isoDuration(%q) = %q, want %q", in, got, want)
+		}
+	}
+}